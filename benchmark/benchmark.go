@@ -0,0 +1,189 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/preslavrachev/gomjml/mjml"
+)
+
+// DefaultThreshold is the relative slowdown tolerated before a case counts as
+// a regression.
+const DefaultThreshold = 0.10
+
+// Result is the measurement of one case.
+type Result struct {
+	Name        string  `json:"name"`
+	NsPerOp     float64 `json:"nsPerOp"`
+	BytesPerOp  int64   `json:"bytesPerOp"`
+	AllocsPerOp int64   `json:"allocsPerOp"`
+}
+
+// Baseline is a stored set of results, together with the environment that
+// produced them. Timings are only comparable on similar hardware.
+type Baseline struct {
+	GoVersion string    `json:"goVersion"`
+	GOOS      string    `json:"goos"`
+	GOARCH    string    `json:"goarch"`
+	CPUs      int       `json:"cpus"`
+	CreatedAt time.Time `json:"createdAt"`
+	Results   []Result  `json:"results"`
+}
+
+// Options configure Run.
+type Options struct {
+	// Filter selects the cases whose name matches; nil runs every case.
+	Filter *regexp.Regexp
+	// BenchTime is the minimum run time per case; 0 uses the testing default of 1s.
+	BenchTime time.Duration
+	// Render options applied to every render.
+	RenderOptions []mjml.RenderOption
+	// Progress, if set, is called after each case finishes.
+	Progress func(Result)
+}
+
+var (
+	initTesting sync.Once
+	benchTimeMu sync.Mutex
+)
+
+// Run benchmarks cases outside of go test. Each case is rendered once before
+// timing; a case that fails to render aborts the run.
+func Run(cases []Case, opts Options) ([]Result, error) {
+	initTesting.Do(testing.Init)
+
+	if opts.BenchTime > 0 {
+		benchTimeMu.Lock()
+		defer benchTimeMu.Unlock()
+		previous := flag.Lookup("test.benchtime").Value.String()
+		if err := flag.Set("test.benchtime", opts.BenchTime.String()); err != nil {
+			return nil, err
+		}
+		defer flag.Set("test.benchtime", previous)
+	}
+
+	var results []Result
+	for _, c := range cases {
+		if opts.Filter != nil && !opts.Filter.MatchString(c.Name) {
+			continue
+		}
+		if _, err := mjml.Render(c.MJML, opts.RenderOptions...); err != nil {
+			return results, fmt.Errorf("%s: %w", c.Name, err)
+		}
+
+		br := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				mjml.Render(c.MJML, opts.RenderOptions...)
+			}
+		})
+		result := Result{
+			Name:        c.Name,
+			NsPerOp:     float64(br.T.Nanoseconds()) / float64(br.N),
+			BytesPerOp:  br.AllocedBytesPerOp(),
+			AllocsPerOp: br.AllocsPerOp(),
+		}
+		results = append(results, result)
+		if opts.Progress != nil {
+			opts.Progress(result)
+		}
+	}
+	return results, nil
+}
+
+// NewBaseline wraps results with a description of the current environment.
+func NewBaseline(results []Result) *Baseline {
+	return &Baseline{
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		CPUs:      runtime.NumCPU(),
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Results:   results,
+	}
+}
+
+// LoadBaseline reads a baseline written by SaveBaseline.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("parse baseline %s: %w", path, err)
+	}
+	return &baseline, nil
+}
+
+// SaveBaseline writes baseline to path as indented JSON.
+func SaveBaseline(path string, baseline *Baseline) error {
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Comparison relates a current result to its baseline.
+type Comparison struct {
+	Name        string
+	Baseline    *Result // nil for cases missing from the baseline
+	Current     Result
+	TimeDelta   float64 // Relative change in ns/op; 0.25 means 25% slower
+	AllocsDelta float64 // Relative change in allocs/op
+	Regressed   bool
+}
+
+// Compare matches current results against the baseline by name. A case
+// regresses when its time or allocation count grows by more than threshold,
+// a fraction such as 0.10 for 10%. Cases missing from the baseline never
+// regress. The comparisons are sorted by name.
+func Compare(baseline *Baseline, current []Result, threshold float64) []Comparison {
+	byName := make(map[string]*Result, len(baseline.Results))
+	for i := range baseline.Results {
+		byName[baseline.Results[i].Name] = &baseline.Results[i]
+	}
+
+	comparisons := make([]Comparison, 0, len(current))
+	for _, cur := range current {
+		c := Comparison{Name: cur.Name, Current: cur, Baseline: byName[cur.Name]}
+		if c.Baseline != nil {
+			c.TimeDelta = relativeChange(c.Baseline.NsPerOp, cur.NsPerOp)
+			c.AllocsDelta = relativeChange(float64(c.Baseline.AllocsPerOp), float64(cur.AllocsPerOp))
+			c.Regressed = c.TimeDelta > threshold || c.AllocsDelta > threshold
+		}
+		comparisons = append(comparisons, c)
+	}
+	sort.Slice(comparisons, func(i, j int) bool { return comparisons[i].Name < comparisons[j].Name })
+	return comparisons
+}
+
+// Regressions returns the comparisons that regressed.
+func Regressions(comparisons []Comparison) []Comparison {
+	var regressed []Comparison
+	for _, c := range comparisons {
+		if c.Regressed {
+			regressed = append(regressed, c)
+		}
+	}
+	return regressed
+}
+
+func relativeChange(before, after float64) float64 {
+	if before == 0 {
+		if after == 0 {
+			return 0
+		}
+		return 1
+	}
+	return (after - before) / before
+}
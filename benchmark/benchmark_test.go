@@ -0,0 +1,123 @@
+package benchmark
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/preslavrachev/gomjml/mjml"
+)
+
+// BenchmarkComponents renders each component case in a minimal document.
+func BenchmarkComponents(b *testing.B) {
+	for _, c := range ComponentCases() {
+		b.Run(c.Name, func(b *testing.B) {
+			benchmarkCase(b, c)
+		})
+	}
+}
+
+// BenchmarkTemplates renders the small, medium and large templates.
+func BenchmarkTemplates(b *testing.B) {
+	for _, c := range TemplateCases() {
+		b.Run(c.Name, func(b *testing.B) {
+			benchmarkCase(b, c)
+		})
+	}
+}
+
+func benchmarkCase(b *testing.B, c Case) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(c.MJML)))
+	for i := 0; i < b.N; i++ {
+		if _, err := mjml.Render(c.MJML); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCasesRender(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, c := range Cases() {
+		if seen[c.Name] {
+			t.Errorf("duplicate case name %q", c.Name)
+		}
+		seen[c.Name] = true
+
+		if _, err := mjml.Render(c.MJML); err != nil {
+			t.Errorf("%s: %v", c.Name, err)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := &Baseline{Results: []Result{
+		{Name: "a", NsPerOp: 1000, AllocsPerOp: 100},
+		{Name: "b", NsPerOp: 1000, AllocsPerOp: 100},
+		{Name: "c", NsPerOp: 1000, AllocsPerOp: 100},
+	}}
+	current := []Result{
+		{Name: "c", NsPerOp: 1050, AllocsPerOp: 100}, // within threshold
+		{Name: "a", NsPerOp: 1200, AllocsPerOp: 90},  // slower
+		{Name: "b", NsPerOp: 900, AllocsPerOp: 150},  // more allocations
+		{Name: "d", NsPerOp: 5000, AllocsPerOp: 500}, // not in baseline
+	}
+
+	comparisons := Compare(baseline, current, DefaultThreshold)
+	if len(comparisons) != 4 {
+		t.Fatalf("got %d comparisons, want 4", len(comparisons))
+	}
+
+	want := map[string]bool{"a": true, "b": true, "c": false, "d": false}
+	for i, c := range comparisons {
+		if name := string(rune('a' + i)); c.Name != name {
+			t.Errorf("comparison %d is %q, want %q", i, c.Name, name)
+		}
+		if c.Regressed != want[c.Name] {
+			t.Errorf("%s: Regressed = %v, want %v", c.Name, c.Regressed, want[c.Name])
+		}
+	}
+	if comparisons[0].TimeDelta < 0.199 || comparisons[0].TimeDelta > 0.201 {
+		t.Errorf("a: TimeDelta = %v, want 0.2", comparisons[0].TimeDelta)
+	}
+	if comparisons[3].Baseline != nil {
+		t.Error("d: expected no baseline")
+	}
+	if got := len(Regressions(comparisons)); got != 2 {
+		t.Errorf("got %d regressions, want 2", got)
+	}
+}
+
+func TestRunAndBaselineRoundTrip(t *testing.T) {
+	results, err := Run(Cases(), Options{
+		Filter:    regexp.MustCompile(`^component/mj-(text|button)$`),
+		BenchTime: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	for _, r := range results {
+		if r.NsPerOp <= 0 || r.AllocsPerOp <= 0 {
+			t.Errorf("%s: implausible result %+v", r.Name, r)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := SaveBaseline(path, NewBaseline(results)); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	loaded, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(loaded.Results) != 2 || loaded.Results[0] != results[0] {
+		t.Errorf("round trip mismatch: %+v", loaded.Results)
+	}
+	if regressions := Regressions(Compare(loaded, results, DefaultThreshold)); len(regressions) != 0 {
+		t.Errorf("identical results should not regress: %+v", regressions)
+	}
+}
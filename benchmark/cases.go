@@ -0,0 +1,171 @@
+// Package benchmark measures gomjml rendering performance and compares it
+// against a stored baseline, so performance regressions can fail CI.
+//
+// The suite has two groups: per-component micro-benchmarks that render one
+// component in a minimal document, and end-to-end templates of small, medium
+// and large complexity. The same cases back the Go benchmarks in this package
+// and the gomjml bench command.
+package benchmark
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Case is a named MJML document to benchmark.
+type Case struct {
+	Name string // Unique name, e.g. "component/mj-button" or "template/small"
+	MJML string
+}
+
+// ComponentCases returns one case per body component, each rendering the
+// component inside a single section and column where the component allows it.
+func ComponentCases() []Case {
+	return []Case{
+		componentCase("mj-text", inColumn(`<mj-text font-size="16px" color="#333333">Hello <b>world</b>, this is a paragraph of text.</mj-text>`)),
+		componentCase("mj-button", inColumn(`<mj-button href="https://example.com" background-color="#e74c3c" border-radius="4px">Click me</mj-button>`)),
+		componentCase("mj-image", inColumn(`<mj-image src="https://example.com/image.png" alt="Image" width="300px" href="https://example.com" />`)),
+		componentCase("mj-divider", inColumn(`<mj-divider border-color="#e0e0e0" border-width="1px" />`)),
+		componentCase("mj-spacer", inColumn(`<mj-spacer height="40px" />`)),
+		componentCase("mj-table", inColumn(`<mj-table><tr><th>Item</th><th>Price</th></tr><tr><td>Shoes</td><td>$40</td></tr></mj-table>`)),
+		componentCase("mj-raw", inColumn(`<mj-raw><p>Raw HTML</p></mj-raw>`)),
+		componentCase("mj-social", inColumn(`<mj-social mode="horizontal">
+        <mj-social-element name="facebook" href="https://facebook.com">Facebook</mj-social-element>
+        <mj-social-element name="twitter" href="https://twitter.com">Twitter</mj-social-element>
+      </mj-social>`)),
+		componentCase("mj-navbar", inColumn(`<mj-navbar hamburger="hamburger">
+        <mj-navbar-link href="/about">About</mj-navbar-link>
+        <mj-navbar-link href="/blog">Blog</mj-navbar-link>
+      </mj-navbar>`)),
+		componentCase("mj-accordion", inColumn(`<mj-accordion>
+        <mj-accordion-element>
+          <mj-accordion-title>Question</mj-accordion-title>
+          <mj-accordion-text>Answer</mj-accordion-text>
+        </mj-accordion-element>
+      </mj-accordion>`)),
+		componentCase("mj-carousel", inColumn(`<mj-carousel>
+        <mj-carousel-image src="https://example.com/1.png" />
+        <mj-carousel-image src="https://example.com/2.png" />
+        <mj-carousel-image src="https://example.com/3.png" />
+      </mj-carousel>`)),
+		componentCase("mj-column", `<mj-section>
+      <mj-column width="33%"><mj-text>One</mj-text></mj-column>
+      <mj-column width="33%"><mj-text>Two</mj-text></mj-column>
+      <mj-column width="34%"><mj-text>Three</mj-text></mj-column>
+    </mj-section>`),
+		componentCase("mj-group", `<mj-section>
+      <mj-group>
+        <mj-column><mj-text>One</mj-text></mj-column>
+        <mj-column><mj-text>Two</mj-text></mj-column>
+      </mj-group>
+    </mj-section>`),
+		componentCase("mj-wrapper", `<mj-wrapper background-color="#f4f4f4" padding="20px">
+      <mj-section><mj-column><mj-text>Wrapped</mj-text></mj-column></mj-section>
+    </mj-wrapper>`),
+		componentCase("mj-hero", `<mj-hero mode="fixed-height" height="300px" background-url="https://example.com/hero.png" background-color="#2a2a2a">
+      <mj-text color="#ffffff">Hero</mj-text>
+    </mj-hero>`),
+	}
+}
+
+// TemplateCases returns end-to-end documents of increasing complexity.
+func TemplateCases() []Case {
+	return []Case{
+		{Name: "template/small", MJML: generateTemplate(3)},
+		{Name: "template/medium", MJML: generateTemplate(20)},
+		{Name: "template/large", MJML: generateTemplate(100)},
+	}
+}
+
+// Cases returns the full suite: component cases followed by template cases.
+func Cases() []Case {
+	return append(ComponentCases(), TemplateCases()...)
+}
+
+func componentCase(tag, body string) Case {
+	return Case{
+		Name: "component/" + tag,
+		MJML: "<mjml>\n  <mj-body>\n    " + body + "\n  </mj-body>\n</mjml>",
+	}
+}
+
+func inColumn(content string) string {
+	return "<mj-section>\n      <mj-column>\n      " + content + "\n      </mj-column>\n    </mj-section>"
+}
+
+// generateTemplate builds a newsletter-style document with the given number of
+// content blocks, mixing head styles, fonts, multi-column sections and the most
+// common content components.
+func generateTemplate(blocks int) string {
+	var sb strings.Builder
+
+	sb.WriteString(`<mjml>
+  <mj-head>
+    <mj-title>Benchmark newsletter</mj-title>
+    <mj-preview>Latest news</mj-preview>
+    <mj-font name="Roboto" href="https://fonts.googleapis.com/css?family=Roboto:300,400,500,700" />
+    <mj-attributes>
+      <mj-all font-family="Roboto, Arial, sans-serif" />
+      <mj-text font-size="14px" line-height="22px" />
+      <mj-class name="muted" color="#888888" />
+    </mj-attributes>
+    <mj-style>
+      .highlight { background-color: #f39c12; }
+    </mj-style>
+  </mj-head>
+  <mj-body background-color="#f4f4f4">
+    <mj-section background-color="#ffffff">
+      <mj-column>
+        <mj-image src="https://example.com/logo.png" alt="Logo" width="150px" />
+      </mj-column>
+    </mj-section>
+`)
+
+	for i := 1; i <= blocks; i++ {
+		switch i % 3 {
+		case 0:
+			fmt.Fprintf(&sb, `    <mj-wrapper background-color="#fafafa" padding="10px">
+      <mj-section>
+        <mj-group>
+          <mj-column><mj-image src="https://example.com/%[1]d-a.png" alt="Item %[1]d A" /></mj-column>
+          <mj-column><mj-image src="https://example.com/%[1]d-b.png" alt="Item %[1]d B" /></mj-column>
+        </mj-group>
+      </mj-section>
+    </mj-wrapper>
+`, i)
+		case 1:
+			fmt.Fprintf(&sb, `    <mj-section background-color="#ffffff" padding="20px">
+      <mj-column width="60%%">
+        <mj-text><h2>Story %[1]d</h2><p>Some <a href="https://example.com/%[1]d">linked</a> text for story %[1]d.</p></mj-text>
+        <mj-button href="https://example.com/%[1]d" background-color="#e74c3c">Read more</mj-button>
+      </mj-column>
+      <mj-column width="40%%">
+        <mj-image src="https://example.com/%[1]d.png" alt="Story %[1]d" />
+      </mj-column>
+    </mj-section>
+`, i)
+		default:
+			fmt.Fprintf(&sb, `    <mj-section>
+      <mj-column>
+        <mj-divider border-color="#e0e0e0" border-width="1px" />
+        <mj-text mj-class="muted" align="center">Block %d of %d</mj-text>
+      </mj-column>
+    </mj-section>
+`, i, blocks)
+		}
+	}
+
+	sb.WriteString(`    <mj-section background-color="#34495e">
+      <mj-column>
+        <mj-social>
+          <mj-social-element name="facebook" href="https://facebook.com" />
+          <mj-social-element name="twitter" href="https://twitter.com" />
+        </mj-social>
+        <mj-text color="#ffffff" align="center">You are receiving this email because you subscribed.</mj-text>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`)
+
+	return sb.String()
+}
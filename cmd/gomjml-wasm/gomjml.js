@@ -0,0 +1,58 @@
+// gomjml.js loads gomjml.wasm and exposes a promise-based MJML renderer.
+//
+// Go's wasm_exec.js must be loaded first; it defines the global Go class.
+// Copy it from "$(go env GOROOT)/lib/wasm/wasm_exec.js" so it matches the Go
+// version used to build gomjml.wasm.
+//
+//   <script src="wasm_exec.js"></script>
+//   <script type="module">
+//     import { load } from "./gomjml.js";
+//     const gomjml = await load("gomjml.wasm");
+//     const { html, error } = gomjml.render(source, { title: "Preview" });
+//   </script>
+//
+// render options: title, previewText, sanitizeHTML, accessibilityChecks, amp,
+// debug, and variables (an object used by mj-cond expressions).
+
+let instance = null;
+
+/**
+ * Loads the WebAssembly module once and resolves to the renderer.
+ * @param {string|URL} wasmURL location of gomjml.wasm
+ * @returns {Promise<{render: (mjml: string, options?: object) => {html: string, error?: object}}>}
+ */
+export function load(wasmURL = new URL("gomjml.wasm", import.meta.url)) {
+  if (instance) {
+    return instance;
+  }
+  if (typeof globalThis.Go !== "function") {
+    return Promise.reject(new Error("gomjml: load wasm_exec.js before gomjml.js"));
+  }
+
+  instance = (async () => {
+    const go = new globalThis.Go();
+    const response = fetch(wasmURL);
+    const { instance: wasm } = WebAssembly.instantiateStreaming
+      ? await WebAssembly.instantiateStreaming(response, go.importObject)
+      : await WebAssembly.instantiate(await (await response).arrayBuffer(), go.importObject);
+
+    // go.run resolves only when the Go program exits, which it never does.
+    go.run(wasm);
+    if (!globalThis.gomjml) {
+      throw new Error("gomjml: the WebAssembly module did not register its API");
+    }
+
+    const api = globalThis.gomjml;
+    return {
+      version: api.version,
+      render(mjml, options = {}) {
+        return api.render(String(mjml), options);
+      },
+    };
+  })();
+
+  instance.catch(() => {
+    instance = null;
+  });
+  return instance;
+}
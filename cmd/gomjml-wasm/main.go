@@ -0,0 +1,152 @@
+//go:build js && wasm
+
+// Command gomjml-wasm exposes the MJML renderer to JavaScript when compiled to
+// WebAssembly, so browser-based editors can preview emails without a server.
+//
+// Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o gomjml.wasm ./cmd/gomjml-wasm
+//
+// and load it with gomjml.js next to Go's wasm_exec.js. The program registers a
+// global gomjml object with a render function and then blocks, keeping the Go
+// runtime alive for later calls.
+package main
+
+import (
+	"errors"
+	"syscall/js"
+
+	"github.com/preslavrachev/gomjml/mjml"
+	"github.com/preslavrachev/gomjml/parser"
+)
+
+func main() {
+	js.Global().Set("gomjml", js.ValueOf(map[string]any{
+		"render":  js.FuncOf(render),
+		"version": "dev",
+	}))
+	select {}
+}
+
+// render implements gomjml.render(mjml, options). It returns an object with the
+// rendered html and, when rendering reported problems, an error object shaped
+// like {type, message, details: [{line, column, tagName, attribute, message}]}.
+// Validation problems still produce html.
+func render(_ js.Value, args []js.Value) any {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return js.ValueOf(map[string]any{
+			"html":  "",
+			"error": map[string]any{"type": "error", "message": "gomjml.render expects an MJML string"},
+		})
+	}
+
+	var opts []mjml.RenderOption
+	if len(args) > 1 {
+		opts = renderOptions(args[1])
+	}
+
+	html, err := mjml.Render(args[0].String(), opts...)
+	result := map[string]any{"html": html}
+	if err != nil {
+		result["error"] = errorValue(err)
+	}
+	return js.ValueOf(result)
+}
+
+// renderOptions reads the optional options object passed from JavaScript.
+func renderOptions(o js.Value) []mjml.RenderOption {
+	if o.Type() != js.TypeObject {
+		return nil
+	}
+
+	var opts []mjml.RenderOption
+	if v := o.Get("title"); v.Type() == js.TypeString {
+		opts = append(opts, mjml.WithTitle(v.String()))
+	}
+	if v := o.Get("previewText"); v.Type() == js.TypeString {
+		opts = append(opts, mjml.WithPreviewText(v.String()))
+	}
+	if o.Get("sanitizeHTML").Truthy() {
+		opts = append(opts, mjml.WithSanitizeHTML())
+	}
+	if o.Get("accessibilityChecks").Truthy() {
+		opts = append(opts, mjml.WithAccessibilityChecks())
+	}
+	if o.Get("amp").Truthy() {
+		opts = append(opts, mjml.WithOutputFormat(mjml.FormatAMP))
+	}
+	if o.Get("debug").Truthy() {
+		opts = append(opts, mjml.WithDebugTags(true))
+	}
+	if v := o.Get("variables"); v.Type() == js.TypeObject {
+		opts = append(opts, mjml.WithVariables(objectToMap(v)))
+	}
+	return opts
+}
+
+// objectToMap converts a plain JavaScript object to the nested map form used
+// by mj-cond expressions.
+func objectToMap(o js.Value) map[string]any {
+	keys := js.Global().Get("Object").Call("keys", o)
+	vars := make(map[string]any, keys.Length())
+	for i := 0; i < keys.Length(); i++ {
+		key := keys.Index(i).String()
+		vars[key] = goValue(o.Get(key))
+	}
+	return vars
+}
+
+func goValue(v js.Value) any {
+	switch v.Type() {
+	case js.TypeBoolean:
+		return v.Bool()
+	case js.TypeNumber:
+		return v.Float()
+	case js.TypeString:
+		return v.String()
+	case js.TypeObject:
+		if js.Global().Get("Array").Call("isArray", v).Bool() {
+			items := make([]any, v.Length())
+			for i := range items {
+				items[i] = goValue(v.Index(i))
+			}
+			return items
+		}
+		return objectToMap(v)
+	default:
+		return nil
+	}
+}
+
+// errorValue converts a render error to a JavaScript-friendly object.
+func errorValue(err error) map[string]any {
+	var parseErr *parser.ParseError
+	if errors.As(err, &parseErr) {
+		details := make([]any, 0, len(parseErr.Errors))
+		for _, e := range parseErr.Errors {
+			details = append(details, map[string]any{
+				"line":      e.Line,
+				"column":    e.Column,
+				"tagName":   e.Tag,
+				"attribute": e.Attribute,
+				"message":   e.Message,
+			})
+		}
+		return map[string]any{"type": "parse", "message": err.Error(), "details": details}
+	}
+
+	var mjmlErr mjml.Error
+	if errors.As(err, &mjmlErr) {
+		details := make([]any, 0, len(mjmlErr.Details))
+		for _, d := range mjmlErr.Details {
+			details = append(details, map[string]any{
+				"line":    d.Line,
+				"tagName": d.TagName,
+				"message": d.Message,
+			})
+		}
+		return map[string]any{"type": "validation", "message": mjmlErr.Message, "details": details}
+	}
+
+	return map[string]any{"type": "error", "message": err.Error()}
+}
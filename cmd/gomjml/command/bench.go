@@ -0,0 +1,142 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"text/tabwriter"
+	"time"
+
+	"github.com/preslavrachev/gomjml/benchmark"
+	"github.com/spf13/cobra"
+)
+
+// NewBenchCommand creates the bench command
+func NewBenchCommand() *cobra.Command {
+	var (
+		baselinePath string
+		save         bool
+		threshold    float64
+		pattern      string
+		benchTime    time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Run benchmarks and compare against a baseline",
+		Long: `Run the gomjml benchmark suite and compare the results against a stored baseline.
+
+The suite renders every body component in a minimal document and three
+end-to-end templates of small, medium and large complexity. A case regresses
+when its time per render or allocation count grows by more than --threshold
+percent. The command exits with status 1 when any case regresses.
+
+Baselines are only comparable on similar hardware; record one with --save on
+the machine that runs the comparison.
+
+Examples:
+  gomjml bench                               # Compare against benchmark/baseline.json
+  gomjml bench --save                        # Record a new baseline
+  gomjml bench --threshold 5 --run template  # Templates only, fail above 5%`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			opts := benchmark.Options{BenchTime: benchTime}
+			if pattern != "" {
+				filter, err := regexp.Compile(pattern)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: invalid --run pattern: %v\n", err)
+					os.Exit(1)
+				}
+				opts.Filter = filter
+			}
+
+			var baseline *benchmark.Baseline
+			if !save {
+				loaded, err := benchmark.LoadBaseline(baselinePath)
+				if err != nil && !errors.Is(err, fs.ErrNotExist) {
+					fmt.Fprintf(os.Stderr, "Error reading baseline: %v\n", err)
+					os.Exit(1)
+				}
+				baseline = loaded
+			}
+
+			fmt.Fprintf(os.Stderr, "Running gomjml benchmarks...\n")
+			opts.Progress = func(r benchmark.Result) {
+				fmt.Fprintf(os.Stderr, "  %s\n", r.Name)
+			}
+			results, err := benchmark.Run(benchmark.Cases(), opts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error running benchmarks: %v\n", err)
+				os.Exit(1)
+			}
+
+			if save {
+				if err := benchmark.SaveBaseline(baselinePath, benchmark.NewBaseline(results)); err != nil {
+					fmt.Fprintf(os.Stderr, "Error writing baseline: %v\n", err)
+					os.Exit(1)
+				}
+				printResults(os.Stdout, results)
+				fmt.Printf("\nBaseline written to %s\n", baselinePath)
+				return
+			}
+
+			if baseline == nil {
+				printResults(os.Stdout, results)
+				fmt.Printf("\nNo baseline at %s; run with --save to record one\n", baselinePath)
+				return
+			}
+
+			comparisons := benchmark.Compare(baseline, results, threshold/100)
+			printComparisons(os.Stdout, comparisons)
+			if regressions := benchmark.Regressions(comparisons); len(regressions) > 0 {
+				fmt.Fprintf(os.Stderr, "\n%d benchmark(s) regressed by more than %.1f%%\n", len(regressions), threshold)
+				os.Exit(1)
+			}
+			fmt.Println("\nNo regressions")
+		},
+	}
+
+	// Add flags
+	cmd.Flags().StringVar(&baselinePath, "baseline", "benchmark/baseline.json", "baseline file to compare against or write")
+	cmd.Flags().BoolVar(&save, "save", false, "write the results as the new baseline instead of comparing")
+	cmd.Flags().Float64Var(&threshold, "threshold", benchmark.DefaultThreshold*100, "allowed slowdown in percent before failing")
+	cmd.Flags().StringVar(&pattern, "run", "", "run only benchmarks matching the regular expression")
+	cmd.Flags().DurationVar(&benchTime, "benchtime", 0, "minimum run time per benchmark (default 1s)")
+
+	return cmd
+}
+
+func printResults(w io.Writer, results []benchmark.Result) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Benchmark\tTime/op\tBytes/op\tAllocs/op\t")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t\n", r.Name, formatNs(r.NsPerOp), r.BytesPerOp, r.AllocsPerOp)
+	}
+	tw.Flush()
+}
+
+func printComparisons(w io.Writer, comparisons []benchmark.Comparison) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Benchmark\tBaseline\tCurrent\tDelta\tAllocs delta\t\t")
+	for _, c := range comparisons {
+		if c.Baseline == nil {
+			fmt.Fprintf(tw, "%s\t-\t%s\t-\t-\tnew\t\n", c.Name, formatNs(c.Current.NsPerOp))
+			continue
+		}
+		status := ""
+		if c.Regressed {
+			status = "REGRESSED"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%+.1f%%\t%+.1f%%\t%s\t\n",
+			c.Name, formatNs(c.Baseline.NsPerOp), formatNs(c.Current.NsPerOp),
+			c.TimeDelta*100, c.AllocsDelta*100, status)
+	}
+	tw.Flush()
+}
+
+func formatNs(ns float64) string {
+	return time.Duration(ns).Round(time.Microsecond).String()
+}
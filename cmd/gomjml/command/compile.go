@@ -1,14 +1,49 @@
 package command
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/preslavrachev/gomjml/mjml"
+	"github.com/preslavrachev/gomjml/parser"
 	"github.com/spf13/cobra"
 )
 
+// Exit codes of the compile command, distinguishing malformed MJML from MJML
+// that parses but fails validation so scripts can react differently.
+const (
+	exitOK              = 0
+	exitFailure         = 1 // I/O errors, bad flags and other failures
+	exitParseError      = 2 // The input is not well-formed MJML
+	exitValidationError = 3 // The input parses but uses invalid attributes
+)
+
+// Error output formats selected with --format.
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// compileError is the JSON document written to stderr with --format json.
+type compileError struct {
+	Type    string               `json:"type"` // "parse", "validation" or "error"
+	Message string               `json:"message"`
+	Details []compileErrorDetail `json:"details,omitempty"`
+}
+
+type compileErrorDetail struct {
+	Line      int    `json:"line"`
+	Column    int    `json:"column,omitempty"`
+	TagName   string `json:"tagName,omitempty"`
+	Attribute string `json:"attribute,omitempty"`
+	Message   string `json:"message"`
+}
+
 // NewCompileCommand creates the compile command
 func NewCompileCommand() *cobra.Command {
 	var (
@@ -18,6 +53,14 @@ func NewCompileCommand() *cobra.Command {
 		cache         bool
 		cacheTTL      time.Duration
 		cacheInterval time.Duration
+		errorFormat   string
+		validation    string
+		compat        string
+		mjmlVersion   string
+		mergeStyles   bool
+		minifyStyles  bool
+		includeDir    string
+		isolate       bool
 	)
 
 	cmd := &cobra.Command{
@@ -25,19 +68,64 @@ func NewCompileCommand() *cobra.Command {
 		Short: "Compile MJML to HTML",
 		Long: `Compile MJML markup to responsive HTML.
 
+The input is read from standard input when no file is given or the file is "-".
+HTML is written to standard output unless --output is set.
+
+--validation selects how invalid attributes and unknown tags are handled:
+"soft" (default) writes the HTML and reports the problems, "skip" does not
+validate, and "strict" stops at the first problem without writing HTML.
+
+--compat selects the implementation the output matches byte for byte where
+they differ: "mrml" (default) or "mjml4".
+
+--mjml-version selects the MJML release whose output is matched, such as
+"4.13", replacing the version attribute of the mjml root.
+
+mj-include paths are read from --include-dir, which defaults to the directory
+of the input file, or the working directory for standard input; includes
+cannot reach outside it. --isolate-includes keeps the mj-attributes of
+included files and the including document apart.
+
+Exit codes:
+  0  success
+  1  I/O or other error
+  2  the input is not well-formed MJML
+  3  the input has validation errors (not with --validation skip)
+
 Examples:
   gomjml compile input.mjml -o output.html
   gomjml compile input.mjml -s
-  gomjml compile input.mjml --debug`,
-		Args: cobra.ExactArgs(1),
+  gomjml compile input.mjml --debug
+  gomjml compile input.mjml --validation strict
+  gomjml compile input.mjml --compat mjml4
+  gomjml compile input.mjml --mjml-version 4.13
+  cat input.mjml | gomjml compile --format json > output.html`,
+		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			inputFile := args[0]
+			if errorFormat != formatText && errorFormat != formatJSON {
+				fmt.Fprintf(os.Stderr, "Error: unknown --format %q (expected %q or %q)\n", errorFormat, formatText, formatJSON)
+				os.Exit(exitFailure)
+			}
+			validationLevel, err := mjml.ParseValidationLevel(validation)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(exitFailure)
+			}
+			compatibility, err := mjml.ParseCompatibility(compat)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(exitFailure)
+			}
+
+			inputFile := "-"
+			if len(args) > 0 {
+				inputFile = args[0]
+			}
 
 			// Read MJML file
-			mjmlContent, err := os.ReadFile(inputFile)
+			mjmlContent, err := readInput(inputFile, os.Stdin)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
-				os.Exit(1)
+				os.Exit(reportError(os.Stderr, errorFormat, "Error reading input", err))
 			}
 
 			if cacheTTL > 0 {
@@ -48,29 +136,48 @@ Examples:
 			}
 
 			// Render MJML to HTML using library
-			opts := []mjml.RenderOption{}
+			opts := []mjml.RenderOption{mjml.WithValidationLevel(validationLevel), mjml.WithCompatibility(compatibility)}
+			if includeDir == "" {
+				includeDir = "."
+				if inputFile != "-" {
+					includeDir = filepath.Dir(inputFile)
+				}
+			}
+			opts = append(opts, mjml.WithIncludes(os.DirFS(includeDir)))
+			if isolate {
+				opts = append(opts, mjml.WithIsolatedIncludes())
+			}
+			if mjmlVersion != "" {
+				opts = append(opts, mjml.WithMJMLVersion(mjmlVersion))
+			}
 			if debug {
 				opts = append(opts, mjml.WithDebugTags(true))
 			}
 			if cache {
 				opts = append(opts, mjml.WithCache())
 			}
-			html, err := mjml.Render(string(mjmlContent), opts...)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error rendering MJML: %v\n", err)
-				os.Exit(1)
+			if minifyStyles {
+				opts = append(opts, mjml.WithMinifiedHeadStyles())
+			} else if mergeStyles {
+				opts = append(opts, mjml.WithMergedHeadStyles())
+			}
+			html, renderErr := mjml.Render(string(mjmlContent), opts...)
+			if html == "" && renderErr != nil {
+				os.Exit(reportError(os.Stderr, errorFormat, "Error rendering MJML", renderErr))
 			}
 
-			// Output HTML
+			// Output HTML, even when soft validation reported problems
 			if outputFile != "" {
 				err := os.WriteFile(outputFile, []byte(html), 0o644)
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error writing output file: %v\n", err)
-					os.Exit(1)
+					os.Exit(reportError(os.Stderr, errorFormat, "Error writing output file", err))
 				}
 			} else {
 				fmt.Print(html)
 			}
+			if renderErr != nil {
+				os.Exit(reportError(os.Stderr, errorFormat, "Error rendering MJML", renderErr))
+			}
 		},
 	}
 
@@ -81,6 +188,83 @@ Examples:
 	cmd.Flags().BoolVar(&cache, "cache", false, "enable experimental AST caching")
 	cmd.Flags().DurationVar(&cacheTTL, "cache-ttl", 0, "AST cache TTL (e.g. 10m)")
 	cmd.Flags().DurationVar(&cacheInterval, "cache-cleanup-interval", 0, "AST cache cleanup interval")
+	cmd.Flags().StringVar(&errorFormat, "format", formatText, `error output format: "text" or "json"`)
+	cmd.Flags().StringVar(&validation, "validation", "soft", `validation level: "skip", "soft" or "strict"`)
+	cmd.Flags().StringVar(&compat, "compat", "mrml", `reference implementation to match: "mrml" or "mjml4"`)
+	cmd.Flags().StringVar(&mjmlVersion, "mjml-version", "", `MJML release to match, such as "4.13" (default latest)`)
+	cmd.Flags().BoolVar(&mergeStyles, "merge-styles", false, "deduplicate head CSS and merge it into one style tag")
+	cmd.Flags().BoolVar(&minifyStyles, "minify-styles", false, "merge and minify head CSS")
+	cmd.Flags().StringVar(&includeDir, "include-dir", "", "directory mj-include paths are read from (default: the input file's directory)")
+	cmd.Flags().BoolVar(&isolate, "isolate-includes", false, "keep mj-attributes of included files and the including document apart")
 
 	return cmd
 }
+
+// readInput reads the named file, or stdin when name is "-".
+func readInput(name string, stdin io.Reader) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(name)
+}
+
+// stdinIsPiped reports whether standard input is redirected from a file or pipe
+// rather than attached to a terminal.
+func stdinIsPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// reportError writes err to w in the requested format and returns the exit
+// code matching its kind.
+func reportError(w io.Writer, format, context string, err error) int {
+	report := classifyError(err)
+	code := exitFailure
+	switch report.Type {
+	case "parse":
+		code = exitParseError
+	case "validation":
+		code = exitValidationError
+	}
+
+	if format == formatJSON {
+		if encErr := json.NewEncoder(w).Encode(report); encErr == nil {
+			return code
+		}
+	}
+	fmt.Fprintf(w, "%s: %v\n", context, err)
+	return code
+}
+
+// classifyError converts err into the structured form used for JSON output.
+func classifyError(err error) compileError {
+	var parseErr *parser.ParseError
+	if errors.As(err, &parseErr) {
+		report := compileError{Type: "parse", Message: err.Error()}
+		for _, e := range parseErr.Errors {
+			report.Details = append(report.Details, compileErrorDetail{
+				Line:      e.Line,
+				Column:    e.Column,
+				TagName:   e.Tag,
+				Attribute: e.Attribute,
+				Message:   e.Message,
+			})
+		}
+		return report
+	}
+
+	var mjmlErr mjml.Error
+	if errors.As(err, &mjmlErr) {
+		report := compileError{Type: "validation", Message: mjmlErr.Message}
+		for _, d := range mjmlErr.Details {
+			report.Details = append(report.Details, compileErrorDetail{
+				Line:    d.Line,
+				TagName: d.TagName,
+				Message: d.Message,
+			})
+		}
+		return report
+	}
+
+	return compileError{Type: "error", Message: err.Error()}
+}
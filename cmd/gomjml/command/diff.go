@@ -0,0 +1,166 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml"
+	"github.com/preslavrachev/gomjml/mjml/htmldiff"
+	"github.com/spf13/cobra"
+)
+
+// exitDifferent is the exit code of the diff command when the outputs differ.
+const exitDifferent = 4
+
+// diffEntry is one difference in the JSON output of the diff command.
+type diffEntry struct {
+	Kind     string `json:"kind"`
+	Path     string `json:"path,omitempty"`
+	Message  string `json:"message"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+// NewDiffCommand creates the diff command
+func NewDiffCommand() *cobra.Command {
+	var (
+		referenceCmd  string
+		referenceFile string
+		compat        string
+		format        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff [input]",
+		Short: "Compare the output with the reference MJML implementation",
+		Long: `Render an MJML template with gomjml and with a reference implementation, and
+print the semantic differences between the two outputs: elements, attributes,
+inline styles, head CSS and Outlook conditional comments. Formatting and
+attribute order are ignored.
+
+--reference-cmd is run with the template on standard input and must write the
+HTML to standard output; it is split on spaces, without a shell. It defaults
+to the official MJML CLI through npx. --reference compares with an HTML file
+rendered beforehand instead, such as a fixture in mjml/testdata.
+
+--compat selects the implementation gomjml matches where they differ:
+"mjml4" (default) or "mrml".
+
+The input is read from standard input when no file is given or the file is "-".
+
+Exit codes:
+  0  the outputs are equivalent
+  1  I/O or other error, including a failing reference command
+  4  the outputs differ
+
+Examples:
+  gomjml diff welcome.mjml
+  gomjml diff welcome.mjml --reference-cmd "node_modules/.bin/mjml -i -s"
+  gomjml diff welcome.mjml --reference-cmd "mrml render" --compat mrml
+  gomjml diff mjml/testdata/basic.mjml --reference mjml/testdata/basic.html --compat mrml
+  gomjml diff welcome.mjml --format json`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if format != formatText && format != formatJSON {
+				fmt.Fprintf(os.Stderr, "Error: unknown --format %q (expected %q or %q)\n", format, formatText, formatJSON)
+				os.Exit(exitFailure)
+			}
+			compatibility, err := mjml.ParseCompatibility(compat)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(exitFailure)
+			}
+
+			inputFile := "-"
+			if len(args) > 0 {
+				inputFile = args[0]
+			}
+			mjmlContent, err := readInput(inputFile, os.Stdin)
+			if err != nil {
+				os.Exit(reportError(os.Stderr, formatText, "Error reading input", err))
+			}
+
+			var reference []byte
+			if referenceFile != "" {
+				reference, err = os.ReadFile(referenceFile)
+			} else {
+				reference, err = runReference(referenceCmd, mjmlContent)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error getting reference output: %v\n", err)
+				os.Exit(exitFailure)
+			}
+
+			// Soft validation problems are reported, but the outputs are still compared
+			actual, renderErr := mjml.Render(string(mjmlContent), mjml.WithCompatibility(compatibility))
+			if actual == "" && renderErr != nil {
+				os.Exit(reportError(os.Stderr, formatText, "Error rendering MJML", renderErr))
+			}
+			if renderErr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", renderErr)
+			}
+
+			report := htmldiff.Compare(string(reference), actual)
+			if format == formatJSON {
+				entries := make([]diffEntry, len(report.Differences))
+				for i, d := range report.Differences {
+					entries[i] = diffEntry{Kind: d.Kind.String(), Path: d.Path, Message: d.Message, Expected: d.Expected, Actual: d.Actual}
+				}
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				enc.SetEscapeHTML(false)
+				if err := enc.Encode(entries); err != nil {
+					fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+					os.Exit(exitFailure)
+				}
+			} else if report.Equal() {
+				fmt.Println("Outputs are equivalent")
+			} else {
+				fmt.Printf("%d differences (expected: reference, actual: gomjml)\n", len(report.Differences))
+				for _, d := range report.Differences {
+					fmt.Println("  " + strings.ReplaceAll(d.String(), "\n", "\n  "))
+				}
+			}
+			if !report.Equal() {
+				os.Exit(exitDifferent)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&referenceCmd, "reference-cmd", "npx mjml -i -s", "command rendering MJML from stdin to HTML on stdout")
+	cmd.Flags().StringVar(&referenceFile, "reference", "", "reference HTML file to compare with instead of running --reference-cmd")
+	cmd.Flags().StringVar(&compat, "compat", "mjml4", `reference implementation to match: "mjml4" or "mrml"`)
+	cmd.Flags().StringVar(&format, "format", formatText, `output format: "text" or "json"`)
+	cmd.MarkFlagsMutuallyExclusive("reference-cmd", "reference")
+
+	return cmd
+}
+
+// runReference runs command with input on stdin and returns its standard
+// output. The command's standard error is included in the error when it fails.
+func runReference(command string, input []byte) ([]byte, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty --reference-cmd")
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(fields[0], fields[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %w: %s", command, err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", command, err)
+	}
+	if stdout.Len() == 0 {
+		return nil, fmt.Errorf("%s wrote no output", command)
+	}
+	return stdout.Bytes(), nil
+}
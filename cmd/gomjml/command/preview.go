@@ -0,0 +1,106 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/preslavrachev/gomjml/preview"
+	"github.com/spf13/cobra"
+)
+
+// NewPreviewCommand creates the preview command
+func NewPreviewCommand() *cobra.Command {
+	var (
+		provider string
+		subject  string
+		clients  []string
+		timeout  time.Duration
+		format   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "preview [input]",
+		Short: "Capture screenshots of a template in email clients",
+		Long: `Render an MJML template and send it to a client preview service, printing
+the URL of the screenshot captured in each email client.
+
+--provider selects the service: "litmus" (Litmus Instant API, key in
+LITMUS_API_KEY) or "emailonacid" (Email on Acid, key and password in
+EOA_API_KEY and EOA_PASSWORD). Client codes are the provider's own; without
+--client, Litmus captures every client available to the key and Email on
+Acid the account's default clients.
+
+The input is read from standard input when no file is given or the file is "-".
+
+Examples:
+  gomjml preview --provider litmus basic.mjml
+  gomjml preview --provider emailonacid --client outlook16 --client iphone13 basic.mjml
+  gomjml preview --provider litmus --format json basic.mjml > screenshots.json`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if format != formatText && format != formatJSON {
+				fmt.Fprintf(os.Stderr, "Error: unknown --format %q (expected %q or %q)\n", format, formatText, formatJSON)
+				os.Exit(exitFailure)
+			}
+			previewer, err := newPreviewProvider(provider)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(exitFailure)
+			}
+
+			inputFile := "-"
+			if len(args) > 0 {
+				inputFile = args[0]
+			}
+			mjmlContent, err := readInput(inputFile, os.Stdin)
+			if err != nil {
+				os.Exit(reportError(os.Stderr, format, "Error reading input", err))
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			fmt.Fprintf(os.Stderr, "Requesting previews from %s...\n", previewer.Name())
+			result, err := preview.Render(ctx, previewer, string(mjmlContent), subject, clients)
+			if result == nil {
+				os.Exit(reportError(os.Stderr, format, "Error previewing MJML", err))
+			}
+
+			if format == formatJSON {
+				_ = json.NewEncoder(os.Stdout).Encode(result)
+			} else {
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				for _, shot := range result.Screenshots {
+					fmt.Fprintf(w, "%s\t%s\n", shot.Client, shot.URL)
+				}
+				w.Flush()
+			}
+			if err != nil {
+				os.Exit(reportError(os.Stderr, format, "Error rendering MJML", err))
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&provider, "provider", "litmus", `preview service: "litmus" or "emailonacid"`)
+	cmd.Flags().StringVar(&subject, "subject", "gomjml preview", "subject line of the test email")
+	cmd.Flags().StringArrayVar(&clients, "client", nil, "provider client code to capture (repeatable)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 10*time.Minute, "how long to wait for the screenshots")
+	cmd.Flags().StringVar(&format, "format", formatText, `output format: "text" or "json"`)
+
+	return cmd
+}
+
+// newPreviewProvider returns the named provider, configured from the
+// environment.
+func newPreviewProvider(name string) (preview.ClientPreviewProvider, error) {
+	switch name {
+	case "litmus":
+		return &preview.Litmus{APIKey: os.Getenv("LITMUS_API_KEY")}, nil
+	case "emailonacid":
+		return &preview.EmailOnAcid{APIKey: os.Getenv("EOA_API_KEY"), Password: os.Getenv("EOA_PASSWORD")}, nil
+	}
+	return nil, fmt.Errorf("unknown --provider %q (expected \"litmus\" or \"emailonacid\")", name)
+}
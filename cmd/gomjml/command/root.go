@@ -18,16 +18,24 @@ It compiles MJML markup into responsive HTML suitable for email clients.
 Available Commands:
   compile    Compile MJML to HTML (default)
   test       Run test suite against MRML
+  bench      Run benchmarks and compare against a baseline
+  preview    Capture screenshots of a template in email clients
+  send       Send a compiled template as a test email over SMTP
+  diff       Compare the output with the reference MJML implementation
   version    Show version information`,
 	}
 
 	// Add subcommands
 	rootCmd.AddCommand(NewCompileCommand())
 	rootCmd.AddCommand(NewTestCommand())
+	rootCmd.AddCommand(NewBenchCommand())
+	rootCmd.AddCommand(NewPreviewCommand())
+	rootCmd.AddCommand(NewSendCommand())
+	rootCmd.AddCommand(NewDiffCommand())
 
 	// If no command is specified, default to compile
 	rootCmd.Run = func(cmd *cobra.Command, args []string) {
-		if len(args) == 0 {
+		if len(args) == 0 && !stdinIsPiped() {
 			cmd.Help()
 			return
 		}
@@ -0,0 +1,198 @@
+package command
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/preslavrachev/gomjml/mjml"
+	"github.com/spf13/cobra"
+)
+
+var documentTitle = regexp.MustCompile(`(?s)<title>(.*?)</title>`)
+
+// NewSendCommand creates the send command
+func NewSendCommand() *cobra.Command {
+	var (
+		to       []string
+		from     string
+		server   string
+		subject  string
+		username string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "send [input]",
+		Short: "Send a compiled template as a test email over SMTP",
+		Long: `Compile MJML and send it over SMTP as a multipart message with the HTML and
+a plain-text alternative generated from the same template, to check the
+rendering in real inboxes.
+
+The subject defaults to the template's mj-title. When --username is set, or
+SMTP_USERNAME is, the command authenticates with the password in
+SMTP_PASSWORD; the connection is upgraded with STARTTLS when the server
+supports it. --from defaults to the username.
+
+The input is read from standard input when no file is given or the file is "-".
+
+Examples:
+  gomjml send --to me@example.com --smtp localhost:1025 basic.mjml
+  SMTP_USERNAME=me@gmail.com SMTP_PASSWORD=app-password \
+    gomjml send --to me@example.com --smtp smtp.gmail.com:587 basic.mjml`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(to) == 0 || server == "" {
+				fmt.Fprintf(os.Stderr, "Error: --to and --smtp are required\n")
+				os.Exit(exitFailure)
+			}
+			host, _, err := net.SplitHostPort(server)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid --smtp address %q: %v\n", server, err)
+				os.Exit(exitFailure)
+			}
+			recipients := make([]string, 0, len(to))
+			headerTo := make([]string, 0, len(to))
+			for _, value := range to {
+				addr, err := mail.ParseAddress(value)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: invalid --to address %q: %v\n", value, err)
+					os.Exit(exitFailure)
+				}
+				recipients = append(recipients, addr.Address)
+				headerTo = append(headerTo, addr.String())
+			}
+			if username == "" {
+				username = os.Getenv("SMTP_USERNAME")
+			}
+			if from == "" {
+				from = username
+			}
+			if from == "" {
+				fmt.Fprintf(os.Stderr, "Error: --from is required without an SMTP username\n")
+				os.Exit(exitFailure)
+			}
+
+			inputFile := "-"
+			if len(args) > 0 {
+				inputFile = args[0]
+			}
+			mjmlContent, err := readInput(inputFile, os.Stdin)
+			if err != nil {
+				os.Exit(reportError(os.Stderr, formatText, "Error reading input", err))
+			}
+
+			// Soft validation problems are reported, but the email is still sent
+			htmlBody, renderErr := mjml.Render(string(mjmlContent))
+			if htmlBody == "" && renderErr != nil {
+				os.Exit(reportError(os.Stderr, formatText, "Error rendering MJML", renderErr))
+			}
+			if renderErr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", renderErr)
+			}
+			textBody, err := mjml.RenderText(string(mjmlContent))
+			if err != nil {
+				os.Exit(reportError(os.Stderr, formatText, "Error rendering plain text", err))
+			}
+			if subject == "" {
+				if match := documentTitle.FindStringSubmatch(htmlBody); match != nil {
+					subject = html.UnescapeString(strings.TrimSpace(match[1]))
+				}
+			}
+			if subject == "" {
+				subject = "gomjml test email"
+			}
+
+			message, err := buildMessage(from, headerTo, subject, textBody, htmlBody, time.Now())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error building message: %v\n", err)
+				os.Exit(exitFailure)
+			}
+
+			var auth smtp.Auth
+			if username != "" {
+				auth = smtp.PlainAuth("", username, os.Getenv("SMTP_PASSWORD"), host)
+			}
+			envelopeFrom := from
+			if addr, err := mail.ParseAddress(from); err == nil {
+				envelopeFrom = addr.Address
+			}
+			if err := smtp.SendMail(server, auth, envelopeFrom, recipients, message); err != nil {
+				fmt.Fprintf(os.Stderr, "Error sending email: %v\n", err)
+				os.Exit(exitFailure)
+			}
+			fmt.Fprintf(os.Stderr, "Sent %q to %s\n", subject, strings.Join(recipients, ", "))
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&to, "to", nil, `recipient address, such as "Ada <ada@example.com>" (repeatable)`)
+	cmd.Flags().StringVar(&from, "from", "", "sender address (default: the SMTP username)")
+	cmd.Flags().StringVar(&server, "smtp", "", "SMTP server as host:port")
+	cmd.Flags().StringVar(&subject, "subject", "", "subject line (default: the template's mj-title)")
+	cmd.Flags().StringVar(&username, "username", "", "SMTP username (default: $SMTP_USERNAME)")
+
+	return cmd
+}
+
+// buildMessage assembles a multipart/alternative message with a plain-text
+// and an HTML part, both quoted-printable encoded.
+func buildMessage(from string, to []string, subject, text, htmlBody string, date time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", text},
+		{"text/html; charset=UTF-8", htmlBody},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	domain := "gomjml.localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(addr.Address, "@"); at >= 0 {
+			domain = addr.Address[at+1:]
+		}
+	}
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id), domain)
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", parts.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
@@ -27,7 +27,8 @@ with the reference MRML implementation to ensure compatibility.
 Examples:
   gomjml test                    # Run all tests
   gomjml test -v                 # Run with verbose output
-  gomjml test -pattern "basic"   # Run tests matching pattern`,
+  gomjml test -pattern "basic"   # Run tests matching pattern
+  gomjml test init ./emails      # Scaffold golden-file tests for your templates`,
 		Run: func(cmd *cobra.Command, args []string) {
 			// Change to the mjml package directory to run tests
 			mjmlDir := filepath.Join("mjml")
@@ -63,5 +64,7 @@ Examples:
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "verbose test output")
 	cmd.Flags().StringVarP(&pattern, "pattern", "p", "", "run only tests matching pattern")
 
+	cmd.AddCommand(NewTestInitCommand())
+
 	return cmd
 }
@@ -0,0 +1,259 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/preslavrachev/gomjml/mjml"
+	"github.com/spf13/cobra"
+)
+
+// NewTestInitCommand creates the test init command
+func NewTestInitCommand() *cobra.Command {
+	var (
+		mjmlBinary string
+		useGomjml  bool
+		pkgName    string
+		testFile   string
+		force      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "init <dir>",
+		Short: "Scaffold golden-file tests for a directory of templates",
+		Long: `Render every .mjml file in a directory to a golden .html file next to it and
+write a Go test that compares gomjml's output with those files using the
+mjml/htmldiff package. Run the test after upgrading gomjml to see whether the
+rendering of your templates changed.
+
+Golden files are rendered with the reference MJML CLI when it is available
+(the mjml binary on PATH, or --mjml), otherwise with gomjml itself. Existing
+golden files and test files are kept unless --force is given.
+
+Examples:
+  gomjml test init ./emails                  # Use mjml from PATH if installed
+  gomjml test init ./emails --mjml ./node_modules/.bin/mjml
+  gomjml test init ./emails --gomjml         # Lock in the current gomjml output
+  gomjml test init ./emails --force          # Re-render all golden files`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			dir := args[0]
+			templates, err := filepath.Glob(filepath.Join(dir, "*.mjml"))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if len(templates) == 0 {
+				fmt.Fprintf(os.Stderr, "Error: no .mjml files found in %s\n", dir)
+				os.Exit(1)
+			}
+
+			render := renderWithGomjml
+			renderer := "gomjml"
+			if !useGomjml {
+				if binary, ok := findReferenceBinary(mjmlBinary); ok {
+					render = func(path string) (string, error) { return renderWithReference(binary, path) }
+					renderer = binary
+				} else if mjmlBinary != "" {
+					fmt.Fprintf(os.Stderr, "Error: reference MJML binary %q not found\n", mjmlBinary)
+					os.Exit(1)
+				}
+			}
+			fmt.Fprintf(os.Stderr, "Rendering golden files with %s\n", renderer)
+
+			for _, path := range templates {
+				golden := strings.TrimSuffix(path, ".mjml") + ".html"
+				if _, err := os.Stat(golden); err == nil && !force {
+					fmt.Fprintf(os.Stderr, "  %s (kept)\n", golden)
+					continue
+				}
+				html, err := render(path)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error rendering %s: %v\n", path, err)
+					os.Exit(1)
+				}
+				if err := os.WriteFile(golden, []byte(html), 0o644); err != nil {
+					fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", golden, err)
+					os.Exit(1)
+				}
+				fmt.Fprintf(os.Stderr, "  %s\n", golden)
+			}
+
+			testPath := filepath.Join(dir, testFile)
+			if _, err := os.Stat(testPath); err == nil && !force {
+				fmt.Fprintf(os.Stderr, "Kept existing %s\n", testPath)
+				return
+			}
+			if pkgName == "" {
+				pkgName = detectPackageName(dir)
+			}
+			source, err := goldenTestSource(pkgName, dir)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error generating test file: %v\n", err)
+				os.Exit(1)
+			}
+			if err := os.WriteFile(testPath, source, 0o644); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", testPath, err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Wrote %s; run it with: go test %s\n", testPath, goPackagePath(dir))
+		},
+	}
+
+	cmd.Flags().StringVar(&mjmlBinary, "mjml", "", "reference MJML CLI used to render golden files (default: mjml on PATH)")
+	cmd.Flags().BoolVar(&useGomjml, "gomjml", false, "render golden files with gomjml even if the MJML CLI is available")
+	cmd.Flags().StringVar(&pkgName, "package", "", "package name of the generated test (default: derived from the directory)")
+	cmd.Flags().StringVar(&testFile, "test-file", "golden_test.go", "name of the generated test file")
+	cmd.Flags().BoolVar(&force, "force", false, "overwrite existing golden files and test file")
+
+	return cmd
+}
+
+// findReferenceBinary resolves the MJML CLI: the given path, or mjml on PATH.
+func findReferenceBinary(binary string) (string, bool) {
+	if binary == "" {
+		binary = "mjml"
+	}
+	path, err := exec.LookPath(binary)
+	return path, err == nil
+}
+
+// renderWithReference renders a template with the MJML CLI, which writes the
+// HTML to stdout with -s.
+func renderWithReference(binary, path string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	refCmd := exec.Command(binary, path, "-s")
+	refCmd.Stdout = &stdout
+	refCmd.Stderr = &stderr
+	if err := refCmd.Run(); err != nil {
+		return "", fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+func renderWithGomjml(path string) (string, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	// Validation problems are reported alongside the output; only a missing
+	// output is fatal.
+	html, err := mjml.Render(string(source))
+	if html == "" {
+		return "", err
+	}
+	return html, nil
+}
+
+// detectPackageName returns the package of the Go files already in dir, or a
+// name derived from the directory.
+func detectPackageName(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err == nil {
+		fset := token.NewFileSet()
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+				continue
+			}
+			file, err := parser.ParseFile(fset, filepath.Join(dir, entry.Name()), nil, parser.PackageClauseOnly)
+			if err == nil {
+				return strings.TrimSuffix(file.Name.Name, "_test")
+			}
+		}
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = dir
+	}
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, filepath.Base(abs))
+	name = strings.Trim(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "templates_" + name
+	}
+	return strings.TrimSuffix(name, "_")
+}
+
+// goPackagePath returns the argument for go test that selects dir.
+func goPackagePath(dir string) string {
+	if filepath.IsAbs(dir) || strings.HasPrefix(dir, ".") {
+		return dir
+	}
+	return "./" + filepath.ToSlash(dir)
+}
+
+var goldenTestTemplate = template.Must(template.New("golden").Parse(`package {{.Package}}
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/preslavrachev/gomjml/mjml"
+	"github.com/preslavrachev/gomjml/mjml/htmldiff"
+)
+
+// TestGoldenTemplates renders every .mjml file in this directory with gomjml
+// and compares the output with the .html golden file next to it. Create golden
+// files for new templates with "gomjml test init {{.Dir}}"; re-render all of
+// them with --force after an intended change.
+func TestGoldenTemplates(t *testing.T) {
+	templates, err := filepath.Glob("*.mjml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) == 0 {
+		t.Skip("no .mjml templates found")
+	}
+
+	for _, template := range templates {
+		name := strings.TrimSuffix(template, ".mjml")
+		t.Run(name, func(t *testing.T) {
+			source, err := os.ReadFile(template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden, err := os.ReadFile(name + ".html")
+			if err != nil {
+				t.Fatalf("missing golden file: %v", err)
+			}
+
+			html, err := mjml.Render(string(source))
+			if html == "" {
+				t.Fatalf("rendering %s: %v", template, err)
+			}
+			if report := htmldiff.Compare(string(golden), html); !report.Equal() {
+				t.Errorf("%s renders differently from %s.html:\n%s", template, name, report)
+			}
+		})
+	}
+}
+`))
+
+// goldenTestSource returns the generated test file.
+func goldenTestSource(pkgName, dir string) ([]byte, error) {
+	if !token.IsIdentifier(pkgName) {
+		return nil, errors.New("invalid package name " + pkgName)
+	}
+	var buf bytes.Buffer
+	err := goldenTestTemplate.Execute(&buf, struct{ Package, Dir string }{pkgName, filepath.ToSlash(dir)})
+	return buf.Bytes(), err
+}
@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/preslavrachev/gomjml/mjml/htmldiff"
 	"github.com/preslavrachev/gomjml/mjml/testutils"
 )
 
@@ -173,6 +174,11 @@ func runComparison(config *Config) error {
 		return fmt.Errorf("could not copy reference file: %v", err)
 	}
 
+	// Semantic comparison, independent of formatting and attribute order
+	if err := reportSemanticDifferences(referenceFile, gomjmlOutput); err != nil {
+		return fmt.Errorf("semantic comparison failed: %v", err)
+	}
+
 	// Beautify both HTML files
 	referencePretty := filepath.Join(config.OutputDir, config.TestCase+"_reference_pretty.html")
 	gomjmlPretty := filepath.Join(config.OutputDir, config.TestCase+"_gomjml_pretty.html")
@@ -195,6 +201,30 @@ func runComparison(config *Config) error {
 	return nil
 }
 
+// reportSemanticDifferences prints the differences htmldiff finds between the
+// reference and gomjml outputs.
+func reportSemanticDifferences(referenceFile, gomjmlFile string) error {
+	reference, err := os.ReadFile(referenceFile)
+	if err != nil {
+		return err
+	}
+	gomjml, err := os.ReadFile(gomjmlFile)
+	if err != nil {
+		return err
+	}
+
+	report := htmldiff.Compare(string(reference), string(gomjml))
+	if report.Equal() {
+		fmt.Printf("%sSemantic comparison: outputs are equivalent%s\n", ColorGreen, ColorReset)
+		return nil
+	}
+	fmt.Printf("%sSemantic comparison: %d differences%s\n", ColorYellow, len(report.Differences), ColorReset)
+	for _, d := range report.Differences {
+		fmt.Println("  " + strings.ReplaceAll(d.String(), "\n", "\n  "))
+	}
+	return nil
+}
+
 func buildGomjml(config *Config) error {
 	fmt.Println("Building gomjml with debug tags...")
 
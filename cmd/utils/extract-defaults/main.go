@@ -1,9 +1,11 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"go/format"
 	"io/fs"
 	"log"
 	"os"
@@ -19,9 +21,12 @@ type ComponentDefaults struct {
 }
 
 type ExtractorConfig struct {
-	InputPath  string
-	OutputPath string
-	Verbose    bool
+	InputPath     string
+	OutputPath    string
+	JSONInputPath string
+	GoOutputPath  string
+	GoPackage     string
+	Verbose       bool
 }
 
 var (
@@ -44,6 +49,19 @@ var (
 func main() {
 	config := parseFlags()
 
+	// With -json-input the MRML extraction is skipped and a previously written
+	// JSON file is turned into Go code; this is what go:generate runs.
+	if config.JSONInputPath != "" {
+		components, err := readJSONInput(config.JSONInputPath)
+		if err != nil {
+			log.Fatalf("Error reading JSON input: %v", err)
+		}
+		if err := writeGoOutput(components, config); err != nil {
+			log.Fatalf("Error writing Go output: %v", err)
+		}
+		return
+	}
+
 	if config.Verbose {
 		log.Printf("Extracting default attributes from: %s", config.InputPath)
 	}
@@ -65,6 +83,12 @@ func main() {
 	if config.Verbose {
 		log.Printf("Successfully wrote default attributes to: %s", config.OutputPath)
 	}
+
+	if config.GoOutputPath != "" {
+		if err := writeGoOutput(components, config); err != nil {
+			log.Fatalf("Error writing Go output: %v", err)
+		}
+	}
 }
 
 func parseFlags() ExtractorConfig {
@@ -72,6 +96,9 @@ func parseFlags() ExtractorConfig {
 
 	flag.StringVar(&config.InputPath, "input", "mrml/packages/mrml-core/src", "Path to MRML source directory")
 	flag.StringVar(&config.OutputPath, "output", "default-css-attrs.json", "Output JSON file path")
+	flag.StringVar(&config.JSONInputPath, "json-input", "", "Read defaults from this JSON file instead of the MRML source")
+	flag.StringVar(&config.GoOutputPath, "go-output", "", "Also write the defaults as Go source to this file")
+	flag.StringVar(&config.GoPackage, "go-package", "defaults", "Package name of the generated Go source")
 	flag.BoolVar(&config.Verbose, "verbose", false, "Enable verbose output")
 	flag.Parse()
 
@@ -274,3 +301,59 @@ func writeJSONOutput(components map[string]map[string]string, outputPath string,
 
 	return nil
 }
+
+func readJSONInput(inputPath string) (map[string]map[string]string, error) {
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var components map[string]map[string]string
+	if err := json.Unmarshal(data, &components); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return components, nil
+}
+
+func writeGoOutput(components map[string]map[string]string, config ExtractorConfig) error {
+	var names []string
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by extract-defaults; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", config.GoPackage)
+	fmt.Fprintf(&buf, "// attributes holds the default attribute values of each MJML component,\n")
+	fmt.Fprintf(&buf, "// keyed by tag name and then by attribute name.\n")
+	fmt.Fprintf(&buf, "var attributes = map[string]map[string]string{\n")
+	for _, name := range names {
+		attrs := components[name]
+		var keys []string
+		for key := range attrs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		fmt.Fprintf(&buf, "%q: {\n", name)
+		for _, key := range keys {
+			fmt.Fprintf(&buf, "%q: %q,\n", key, attrs[key])
+		}
+		fmt.Fprintf(&buf, "},\n")
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format Go source: %w", err)
+	}
+	if err := os.WriteFile(config.GoOutputPath, source, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if config.Verbose {
+		log.Printf("Successfully wrote Go defaults to: %s", config.GoOutputPath)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Command feed2mjml turns an RSS, Atom or JSON feed into an MJML newsletter.
+//
+// The feed is read from a file, from stdin with "-", or fetched from an
+// http(s) URL. Its items are laid out according to a JSON layout file, whose
+// fields are those of feed.Layout; fields the file leaves out keep the values
+// of feed.DefaultLayout. With -html the newsletter is rendered to HTML instead
+// of written as MJML.
+//
+// Usage:
+//
+//	go run ./cmd/utils/feed2mjml -o digest.mjml https://example.com/feed.xml
+//	go run ./cmd/utils/feed2mjml -config layout.json -html feed.json > digest.html
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/preslavrachev/gomjml/feed"
+	"github.com/preslavrachev/gomjml/mjml"
+)
+
+type Config struct {
+	Source     string
+	LayoutPath string
+	OutputPath string
+	HTML       bool
+}
+
+func main() {
+	config := parseFlags()
+
+	data, err := readSource(config.Source)
+	if err != nil {
+		log.Fatalf("Error reading feed: %v", err)
+	}
+	f, err := feed.Parse(data)
+	if err != nil {
+		log.Fatalf("Error parsing feed: %v", err)
+	}
+
+	layout := feed.DefaultLayout()
+	if config.LayoutPath != "" {
+		layoutData, err := os.ReadFile(config.LayoutPath)
+		if err != nil {
+			log.Fatalf("Error reading layout: %v", err)
+		}
+		if err := json.Unmarshal(layoutData, &layout); err != nil {
+			log.Fatalf("Error parsing layout: %v", err)
+		}
+	}
+
+	output := feed.MJML(f, layout)
+	if config.HTML {
+		if output, err = mjml.Render(output); err != nil {
+			log.Fatalf("Error rendering newsletter: %v", err)
+		}
+	}
+
+	out := io.Writer(os.Stdout)
+	if config.OutputPath != "" {
+		file, err := os.Create(config.OutputPath)
+		if err != nil {
+			log.Fatalf("Error creating output file: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	if _, err := io.WriteString(out, output); err != nil {
+		log.Fatalf("Error writing output: %v", err)
+	}
+}
+
+func parseFlags() Config {
+	var config Config
+
+	flag.StringVar(&config.LayoutPath, "config", "", "JSON layout file (defaults to the built-in layout)")
+	flag.StringVar(&config.OutputPath, "o", "", "Output file (defaults to stdout)")
+	flag.BoolVar(&config.HTML, "html", false, "Render the newsletter to HTML instead of writing MJML")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: feed2mjml [flags] <file|url|->\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	config.Source = flag.Arg(0)
+	return config
+}
+
+// readSource reads the feed from stdin, an http(s) URL or a file.
+func readSource(source string) ([]byte, error) {
+	if source == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.ReadFile(source)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", source, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,377 @@
+// Command parity renders every fixture of the reference test corpus and
+// reports which components and attributes match the reference output.
+//
+// Each fixture is a pair of files, foo.mjml and foo.html, where foo.html was
+// produced by the reference implementation. A fixture passes when gomjml's
+// output is semantically equal to it, as judged by htmldiff. Every component
+// and attribute a fixture uses is credited with its result, so the matrix
+// shows at a glance which ones still produce different output. Fixtures that
+// TestMJMLAgainstExpected does not list are flagged, which replaces keeping
+// track of disabled test cases by hand.
+//
+// Usage:
+//
+//	go run ./cmd/utils/parity -format markdown -o docs/parity.md
+//	go run ./cmd/utils/parity -format json | jq '.fixtures[] | select(.status != "pass")'
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml"
+	"github.com/preslavrachev/gomjml/mjml/htmldiff"
+	"github.com/preslavrachev/gomjml/parser"
+)
+
+type Config struct {
+	TestDataDir string
+	TestFile    string
+	Format      string
+	OutputPath  string
+	Verbose     bool
+}
+
+// Status is the outcome of rendering one fixture.
+type Status string
+
+const (
+	StatusPass  Status = "pass"
+	StatusFail  Status = "fail"
+	StatusError Status = "error"
+)
+
+// FixtureResult is the outcome of one fixture.
+type FixtureResult struct {
+	Name        string   `json:"name"`
+	Status      Status   `json:"status"`
+	InTestSuite bool     `json:"in_test_suite"`
+	Components  []string `json:"components"`
+	Differences int      `json:"differences,omitempty"`
+	Kinds       []string `json:"difference_kinds,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// Coverage counts the fixtures using a component, or one of its attributes,
+// and how many of them pass.
+type Coverage struct {
+	Component string `json:"component"`
+	Attribute string `json:"attribute,omitempty"`
+	Passed    int    `json:"passed"`
+	Total     int    `json:"total"`
+}
+
+// Report is the parity matrix.
+type Report struct {
+	Passed     int             `json:"passed"`
+	Total      int             `json:"total"`
+	Fixtures   []FixtureResult `json:"fixtures"`
+	Components []Coverage      `json:"components"`
+	Attributes []Coverage      `json:"attributes"`
+}
+
+var (
+	// Regex to find the test cases listed in TestMJMLAgainstExpected
+	testCaseRegex = regexp.MustCompile(`\{name:\s*"([^"]+)"`)
+
+	// Regexes to find the random IDs baked into a fixture, by component
+	fixtureIDRegexes = map[string]*regexp.Regexp{
+		"mj-navbar":   regexp.MustCompile(`<input type="checkbox" id="([^"]+)" class="mj-menu-checkbox"`),
+		"mj-carousel": regexp.MustCompile(`name="mj-carousel-radio-([0-9a-f]+)"`),
+	}
+)
+
+func main() {
+	config := parseFlags()
+
+	report, err := buildReport(config)
+	if err != nil {
+		log.Fatalf("Error building parity report: %v", err)
+	}
+
+	out := io.Writer(os.Stdout)
+	if config.OutputPath != "" {
+		f, err := os.Create(config.OutputPath)
+		if err != nil {
+			log.Fatalf("Error creating output file: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	switch config.Format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	default:
+		err = writeMarkdown(out, report)
+	}
+	if err != nil {
+		log.Fatalf("Error writing report: %v", err)
+	}
+
+	if config.Verbose {
+		log.Printf("%d of %d fixtures pass", report.Passed, report.Total)
+	}
+}
+
+func parseFlags() Config {
+	var config Config
+
+	flag.StringVar(&config.TestDataDir, "testdata-dir", "mjml/testdata", "Directory holding the .mjml fixtures and their reference .html output")
+	flag.StringVar(&config.TestFile, "tests", "mjml/integration_test.go", "Integration test file listing the fixtures that run in go test (empty skips the check)")
+	flag.StringVar(&config.Format, "format", "markdown", "Output format: markdown or json")
+	flag.StringVar(&config.OutputPath, "o", "", "Output file (defaults to stdout)")
+	flag.BoolVar(&config.Verbose, "v", false, "Log a summary to stderr")
+	flag.Parse()
+
+	if config.Format != "markdown" && config.Format != "json" {
+		log.Fatalf("Unknown format %q, want markdown or json", config.Format)
+	}
+	return config
+}
+
+// buildReport renders every fixture in the testdata directory and aggregates
+// the results by component and attribute.
+func buildReport(config Config) (*Report, error) {
+	inSuite := map[string]bool{}
+	if config.TestFile != "" {
+		var err error
+		if inSuite, err = listedTestCases(config.TestFile); err != nil {
+			return nil, err
+		}
+	}
+
+	inputs, err := filepath.Glob(filepath.Join(config.TestDataDir, "*.mjml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(inputs)
+
+	report := &Report{}
+	components := map[string]*Coverage{}
+	attributes := map[string]*Coverage{}
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".mjml")
+		expected, err := os.ReadFile(strings.TrimSuffix(input, ".mjml") + ".html")
+		if errors.Is(err, os.ErrNotExist) {
+			continue // Not a fixture without reference output
+		}
+		if err != nil {
+			return nil, err
+		}
+		source, err := os.ReadFile(input)
+		if err != nil {
+			return nil, err
+		}
+
+		result := runFixture(name, string(source), string(expected))
+		result.InTestSuite = inSuite[name]
+
+		tags, attrs := usage(string(source))
+		result.Components = tags
+		for _, tag := range tags {
+			count(components, Coverage{Component: tag}, result.Status)
+		}
+		for _, attr := range attrs {
+			count(attributes, attr, result.Status)
+		}
+
+		report.Total++
+		if result.Status == StatusPass {
+			report.Passed++
+		}
+		report.Fixtures = append(report.Fixtures, result)
+	}
+
+	report.Components = sortedCoverage(components)
+	report.Attributes = sortedCoverage(attributes)
+	return report, nil
+}
+
+// runFixture renders source and compares the output to the reference output.
+func runFixture(name, source, expected string) FixtureResult {
+	result := FixtureResult{Name: name}
+
+	// Navbars and carousels get random IDs; reuse the ones baked into the fixture
+	ids := map[string][]string{}
+	for component, re := range fixtureIDRegexes {
+		for _, match := range re.FindAllStringSubmatch(expected, -1) {
+			if !slices.Contains(ids[component], match[1]) {
+				ids[component] = append(ids[component], match[1])
+			}
+		}
+	}
+	idGenerator := func(component string, index int) string {
+		if index < len(ids[component]) {
+			return ids[component][index]
+		}
+		return ""
+	}
+
+	actual, err := mjml.Render(source, mjml.WithIDGenerator(idGenerator))
+	if err != nil {
+		// Like TestMJMLAgainstExpected, validation errors count against a fixture
+		result.Status = StatusError
+		result.Error = err.Error()
+		return result
+	}
+
+	diff := htmldiff.Compare(expected, actual)
+	if diff.Equal() {
+		result.Status = StatusPass
+		return result
+	}
+	result.Status = StatusFail
+	result.Differences = len(diff.Differences)
+	seen := map[string]bool{}
+	for _, d := range diff.Differences {
+		if kind := d.Kind.String(); !seen[kind] {
+			seen[kind] = true
+			result.Kinds = append(result.Kinds, kind)
+		}
+	}
+	sort.Strings(result.Kinds)
+	return result
+}
+
+// usage returns the MJML tags a document uses and the attributes set on them,
+// each once. Documents that do not parse use nothing.
+func usage(source string) ([]string, []Coverage) {
+	root, err := parser.ParseMJML(source)
+	if err != nil {
+		return nil, nil
+	}
+
+	tags := map[string]bool{}
+	attrs := map[Coverage]bool{}
+	var walk func(node *parser.MJMLNode)
+	walk = func(node *parser.MJMLNode) {
+		tag := node.GetTagName()
+		if tag == "mjml" || strings.HasPrefix(tag, "mj-") {
+			tags[tag] = true
+			for _, attr := range node.Attrs {
+				attrs[Coverage{Component: tag, Attribute: attr.Name.Local}] = true
+			}
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	tagList := make([]string, 0, len(tags))
+	for tag := range tags {
+		tagList = append(tagList, tag)
+	}
+	sort.Strings(tagList)
+	attrList := make([]Coverage, 0, len(attrs))
+	for attr := range attrs {
+		attrList = append(attrList, attr)
+	}
+	return tagList, attrList
+}
+
+// listedTestCases returns the fixtures TestMJMLAgainstExpected runs. Cases in
+// commented-out lines are not listed.
+func listedTestCases(path string) (map[string]bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	listed := map[string]bool{}
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "//") {
+			continue
+		}
+		for _, match := range testCaseRegex.FindAllStringSubmatch(line, -1) {
+			listed[match[1]] = true
+		}
+	}
+	return listed, nil
+}
+
+func count(coverage map[string]*Coverage, key Coverage, status Status) {
+	id := key.Component + " " + key.Attribute
+	c, ok := coverage[id]
+	if !ok {
+		c = &key
+		coverage[id] = c
+	}
+	c.Total++
+	if status == StatusPass {
+		c.Passed++
+	}
+}
+
+func sortedCoverage(coverage map[string]*Coverage) []Coverage {
+	list := make([]Coverage, 0, len(coverage))
+	for _, c := range coverage {
+		list = append(list, *c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Component != list[j].Component {
+			return list[i].Component < list[j].Component
+		}
+		return list[i].Attribute < list[j].Attribute
+	})
+	return list
+}
+
+// writeMarkdown writes the report as Markdown tables.
+func writeMarkdown(w io.Writer, report *Report) error {
+	var sb strings.Builder
+	sb.WriteString("# Parity Matrix\n\n")
+	fmt.Fprintf(&sb, "%d of %d fixtures match the reference output.\n\n", report.Passed, report.Total)
+
+	sb.WriteString("## Components\n\n")
+	sb.WriteString("| Component | Passing | Fixtures |\n|---|---|---|\n")
+	for _, c := range report.Components {
+		fmt.Fprintf(&sb, "| %s %s | %d | %d |\n", mark(c), c.Component, c.Passed, c.Total)
+	}
+
+	sb.WriteString("\n## Attributes\n\n")
+	sb.WriteString("| Component | Attribute | Passing | Fixtures |\n|---|---|---|---|\n")
+	for _, c := range report.Attributes {
+		fmt.Fprintf(&sb, "| %s %s | %s | %d | %d |\n", mark(c), c.Component, c.Attribute, c.Passed, c.Total)
+	}
+
+	sb.WriteString("\n## Fixtures\n\n")
+	sb.WriteString("| Fixture | Status | In test suite | Details |\n|---|---|---|---|\n")
+	for _, f := range report.Fixtures {
+		details := ""
+		switch f.Status {
+		case StatusFail:
+			details = fmt.Sprintf("%d differences (%s)", f.Differences, strings.Join(f.Kinds, ", "))
+		case StatusError:
+			details = strings.ReplaceAll(f.Error, "\n", " ")
+		}
+		inSuite := "no"
+		if f.InTestSuite {
+			inSuite = "yes"
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", f.Name, f.Status, inSuite, strings.ReplaceAll(details, "|", `\|`))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// mark returns a check mark when every fixture using c passes.
+func mark(c Coverage) string {
+	if c.Passed == c.Total {
+		return "✅"
+	}
+	return "❌"
+}
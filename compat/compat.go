@@ -0,0 +1,125 @@
+// Package compat mirrors the mjml2html function of the Node mjml package, so
+// services that shell out to the Node CLI or call mjml2html can switch to
+// gomjml without reshaping their options and error handling.
+//
+// Options decodes from the same JSON object as the Node options, and Result
+// encodes like the Node result:
+//
+//	var opts compat.Options
+//	_ = json.Unmarshal([]byte(`{"validationLevel":"strict","keepComments":false}`), &opts)
+//	result, err := compat.MJML2HTML(input, opts)
+package compat
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/preslavrachev/gomjml/mjml"
+)
+
+// Options mirrors the options object of mjml2html. Node options without an
+// equivalent, such as filePath and preprocessors, are not supported.
+type Options struct {
+	// ValidationLevel is "soft" (the default), "strict" or "skip".
+	ValidationLevel string `json:"validationLevel,omitempty"`
+	// KeepComments keeps the comments of the source; nil keeps them, as in
+	// mjml2html.
+	KeepComments *bool `json:"keepComments,omitempty"`
+	// Beautify is accepted for compatibility and ignored. mjml2html deprecated
+	// it in MJML 4; format the HTML with a separate tool instead.
+	Beautify bool `json:"beautify,omitempty"`
+	// Minify merges and minifies the head CSS. mjml2html also minifies the
+	// HTML, which gomjml does not do.
+	Minify bool `json:"minify,omitempty"`
+	// Fonts maps font names to the stylesheet URLs imported when the document
+	// uses them, replacing the default Google Fonts.
+	Fonts map[string]string `json:"fonts,omitempty"`
+}
+
+// Error mirrors an entry of the errors array returned by mjml2html.
+type Error struct {
+	Line             int    `json:"line"`
+	Message          string `json:"message"`
+	TagName          string `json:"tagName"`
+	FormattedMessage string `json:"formattedMessage"`
+}
+
+// Result mirrors the object returned by mjml2html.
+type Result struct {
+	HTML   string  `json:"html"`
+	Errors []Error `json:"errors"`
+}
+
+// ValidationError is returned with ValidationLevel "strict", where
+// mjml2html throws an error listing the problems.
+type ValidationError struct {
+	Errors []Error
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Errors) == 0 {
+		return "ValidationError"
+	}
+	return "ValidationError: " + e.Errors[0].FormattedMessage
+}
+
+// RenderOptions returns the gomjml render options matching opts.
+func (opts Options) RenderOptions() ([]mjml.RenderOption, error) {
+	var renderOpts []mjml.RenderOption
+	if opts.ValidationLevel != "" {
+		level, err := mjml.ParseValidationLevel(opts.ValidationLevel)
+		if err != nil {
+			return nil, err
+		}
+		renderOpts = append(renderOpts, mjml.WithValidationLevel(level))
+	}
+	if opts.KeepComments != nil && !*opts.KeepComments {
+		renderOpts = append(renderOpts, mjml.WithComments(mjml.CommentsStrip))
+	}
+	if opts.Minify {
+		renderOpts = append(renderOpts, mjml.WithMinifiedHeadStyles())
+	}
+	if opts.Fonts != nil {
+		renderOpts = append(renderOpts, mjml.WithFonts(opts.Fonts))
+	}
+	return renderOpts, nil
+}
+
+// MJML2HTML converts MJML to HTML like mjml2html. Validation problems are
+// listed in Result.Errors, or returned as a *ValidationError with
+// ValidationLevel "strict". Other errors, such as malformed MJML, are
+// returned as they are.
+func MJML2HTML(input string, opts Options) (*Result, error) {
+	renderOpts, err := opts.RenderOptions()
+	if err != nil {
+		return nil, err
+	}
+	strict := false
+	if opts.ValidationLevel != "" {
+		level, _ := mjml.ParseValidationLevel(opts.ValidationLevel) // Checked by RenderOptions
+		strict = level == mjml.ValidationStrict
+	}
+
+	html, renderErr := mjml.Render(input, renderOpts...)
+	result := &Result{HTML: html, Errors: []Error{}}
+	if renderErr == nil {
+		return result, nil
+	}
+
+	var mjmlErr mjml.Error
+	if !errors.As(renderErr, &mjmlErr) {
+		return nil, renderErr
+	}
+	for _, d := range mjmlErr.Details {
+		result.Errors = append(result.Errors, Error{
+			Line:             d.Line,
+			Message:          d.Message,
+			TagName:          d.TagName,
+			FormattedMessage: fmt.Sprintf("Line %d (%s) — %s", d.Line, d.TagName, d.Message),
+		})
+	}
+	if strict {
+		return nil, &ValidationError{Errors: result.Errors}
+	}
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package compat
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const invalidDocument = `<mjml>
+  <mj-body>
+    <mj-section><mj-column>
+      <!-- note -->
+      <mj-text foo="bar" font-family="Raleway, sans-serif">Hello</mj-text>
+    </mj-column></mj-section>
+  </mj-body>
+</mjml>`
+
+func TestMJML2HTML(t *testing.T) {
+	var opts Options
+	if err := json.Unmarshal([]byte(`{
+		"keepComments": false,
+		"beautify": true,
+		"fonts": {"Raleway": "https://fonts.example.com/raleway.css"}
+	}`), &opts); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := MJML2HTML(invalidDocument, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(result.HTML, "note") {
+		t.Error("comment kept with keepComments false")
+	}
+	if !strings.Contains(result.HTML, `@import url(https://fonts.example.com/raleway.css);`) {
+		t.Error("font from the fonts option is not imported")
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("got %d errors, want 1: %+v", len(result.Errors), result.Errors)
+	}
+	got := result.Errors[0]
+	if got.Line != 5 || got.TagName != "mj-text" || !strings.HasPrefix(got.FormattedMessage, "Line 5 (mj-text) — ") {
+		t.Errorf("unexpected error: %+v", got)
+	}
+
+	encoded, _ := json.Marshal(&Result{HTML: "x", Errors: []Error{}})
+	if string(encoded) != `{"html":"x","errors":[]}` {
+		t.Errorf("result encodes as %s", encoded)
+	}
+}
+
+func TestMJML2HTMLStrict(t *testing.T) {
+	result, err := MJML2HTML(invalidDocument, Options{ValidationLevel: "strict"})
+	if result != nil {
+		t.Errorf("MJML2HTML() result = %+v, want none in strict mode", result)
+	}
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 {
+		t.Fatalf("MJML2HTML() error = %v, want a ValidationError", err)
+	}
+
+	if _, err := MJML2HTML(invalidDocument, Options{ValidationLevel: "loud"}); err == nil {
+		t.Error("unknown validation level accepted")
+	}
+}
@@ -0,0 +1,324 @@
+// Package feed turns RSS, Atom and JSON feeds into MJML newsletters.
+//
+// Parse reads a feed in any of the three formats into a Feed, and Generate
+// lays its items out as an MJML document according to a Layout:
+//
+//	f, err := feed.Parse(data)
+//	if err != nil {
+//		return err
+//	}
+//	html, err := mjml.Render(feed.MJML(f, feed.DefaultLayout()))
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Feed is a parsed feed, independent of its format.
+type Feed struct {
+	Title       string
+	Description string
+	Link        string // Home page of the feed
+	Items       []Item // In feed order, which is usually newest first
+}
+
+// Item is an entry of a feed.
+type Item struct {
+	Title     string
+	Link      string
+	Summary   string // Plain text, with the markup of HTML summaries removed
+	ImageURL  string // Enclosed image, or else the first image of the content
+	Published time.Time
+}
+
+// ErrUnknownFormat is returned by Parse for documents that are not RSS 2.0,
+// Atom or JSON Feed.
+var ErrUnknownFormat = errors.New("unknown feed format")
+
+// Parse reads an RSS 2.0, Atom or JSON Feed document. The format is detected
+// from the content.
+func Parse(data []byte) (*Feed, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("{")) {
+		return parseJSON(data)
+	}
+
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+	switch root.XMLName.Local {
+	case "rss":
+		return parseRSS(data)
+	case "feed":
+		return parseAtom(data)
+	}
+	return nil, fmt.Errorf("%w: <%s>", ErrUnknownFormat, root.XMLName.Local)
+}
+
+type rssDocument struct {
+	Channel struct {
+		Title       string    `xml:"title"`
+		Link        string    `xml:"link"`
+		Description string    `xml:"description"`
+		Items       []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	Content     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate     string `xml:"pubDate"`
+	Enclosures  []struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"enclosure"`
+	Media []struct {
+		URL    string `xml:"url,attr"`
+		Medium string `xml:"medium,attr"`
+		Type   string `xml:"type,attr"`
+	} `xml:"http://search.yahoo.com/mrss/ content"`
+	Thumbnail struct {
+		URL string `xml:"url,attr"`
+	} `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+func parseRSS(data []byte) (*Feed, error) {
+	var doc rssDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
+	}
+
+	f := &Feed{
+		Title:       strings.TrimSpace(doc.Channel.Title),
+		Description: textContent(doc.Channel.Description),
+		Link:        strings.TrimSpace(doc.Channel.Link),
+	}
+	for _, entry := range doc.Channel.Items {
+		item := Item{
+			Title:     strings.TrimSpace(entry.Title),
+			Link:      strings.TrimSpace(entry.Link),
+			Summary:   textContent(entry.Description),
+			Published: parseTime(entry.PubDate, time.RFC1123Z, time.RFC1123, "Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST", "2 Jan 2006 15:04:05 -0700"),
+		}
+		for _, enclosure := range entry.Enclosures {
+			if strings.HasPrefix(enclosure.Type, "image/") {
+				item.ImageURL = enclosure.URL
+				break
+			}
+		}
+		for _, media := range entry.Media {
+			if item.ImageURL == "" && (media.Medium == "image" || strings.HasPrefix(media.Type, "image/")) {
+				item.ImageURL = media.URL
+			}
+		}
+		item.ImageURL = firstNonEmpty(item.ImageURL, entry.Thumbnail.URL, firstImage(entry.Content), firstImage(entry.Description))
+		if item.Summary == "" {
+			item.Summary = textContent(entry.Content)
+		}
+		f.Items = append(f.Items, item)
+	}
+	return f, nil
+}
+
+type atomDocument struct {
+	Title    atomText   `xml:"title"`
+	Subtitle atomText   `xml:"subtitle"`
+	Links    []atomLink `xml:"link"`
+	Entries  []struct {
+		Title     atomText   `xml:"title"`
+		Links     []atomLink `xml:"link"`
+		Summary   atomText   `xml:"summary"`
+		Content   atomText   `xml:"content"`
+		Published string     `xml:"published"`
+		Updated   string     `xml:"updated"`
+	} `xml:"entry"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",innerxml"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// html returns the text construct as HTML: text constructs are escaped, html
+// ones hold escaped HTML and xhtml ones hold the markup itself.
+func (t atomText) html() string {
+	if t.Type == "xhtml" {
+		return t.Body
+	}
+	var unescaped string
+	if err := xml.Unmarshal([]byte("<t>"+t.Body+"</t>"), &unescaped); err != nil {
+		return t.Body
+	}
+	if t.Type == "html" {
+		return unescaped
+	}
+	return html.EscapeString(unescaped)
+}
+
+func parseAtom(data []byte) (*Feed, error) {
+	var doc atomDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse Atom feed: %w", err)
+	}
+
+	f := &Feed{
+		Title:       textContent(doc.Title.html()),
+		Description: textContent(doc.Subtitle.html()),
+		Link:        atomHref(doc.Links, "alternate"),
+	}
+	for _, entry := range doc.Entries {
+		item := Item{
+			Title:     textContent(entry.Title.html()),
+			Link:      atomHref(entry.Links, "alternate"),
+			Summary:   textContent(entry.Summary.html()),
+			Published: parseTime(firstNonEmpty(entry.Published, entry.Updated), time.RFC3339),
+		}
+		for _, link := range entry.Links {
+			if link.Rel == "enclosure" && strings.HasPrefix(link.Type, "image/") {
+				item.ImageURL = link.Href
+				break
+			}
+		}
+		item.ImageURL = firstNonEmpty(item.ImageURL, firstImage(entry.Content.html()), firstImage(entry.Summary.html()))
+		if item.Summary == "" {
+			item.Summary = textContent(entry.Content.html())
+		}
+		f.Items = append(f.Items, item)
+	}
+	return f, nil
+}
+
+// atomHref returns the link with relation rel. Links without a relation are
+// alternate links.
+func atomHref(links []atomLink, rel string) string {
+	for _, link := range links {
+		if link.Rel == rel || link.Rel == "" && rel == "alternate" {
+			return strings.TrimSpace(link.Href)
+		}
+	}
+	return ""
+}
+
+type jsonDocument struct {
+	Version     string `json:"version"`
+	Title       string `json:"title"`
+	HomePageURL string `json:"home_page_url"`
+	Description string `json:"description"`
+	Items       []struct {
+		URL           string `json:"url"`
+		Title         string `json:"title"`
+		Summary       string `json:"summary"`
+		ContentHTML   string `json:"content_html"`
+		ContentText   string `json:"content_text"`
+		Image         string `json:"image"`
+		BannerImage   string `json:"banner_image"`
+		DatePublished string `json:"date_published"`
+	} `json:"items"`
+}
+
+func parseJSON(data []byte) (*Feed, error) {
+	var doc jsonDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON feed: %w", err)
+	}
+	if !strings.HasPrefix(doc.Version, "https://jsonfeed.org/version/") {
+		return nil, fmt.Errorf("%w: JSON without a JSON Feed version", ErrUnknownFormat)
+	}
+
+	f := &Feed{
+		Title:       strings.TrimSpace(doc.Title),
+		Description: strings.TrimSpace(doc.Description),
+		Link:        strings.TrimSpace(doc.HomePageURL),
+	}
+	for _, entry := range doc.Items {
+		f.Items = append(f.Items, Item{
+			Title:     strings.TrimSpace(entry.Title),
+			Link:      strings.TrimSpace(entry.URL),
+			Summary:   firstNonEmpty(strings.TrimSpace(entry.Summary), textContent(entry.ContentHTML), strings.TrimSpace(entry.ContentText)),
+			ImageURL:  firstNonEmpty(entry.Image, entry.BannerImage, firstImage(entry.ContentHTML)),
+			Published: parseTime(entry.DatePublished, time.RFC3339),
+		})
+	}
+	return f, nil
+}
+
+// textContent returns the text of an HTML fragment with whitespace collapsed.
+func textContent(fragment string) string {
+	var sb strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(sb.String()), " ")
+		case html.TextToken:
+			sb.Write(tokenizer.Text())
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			// Tags separate words, as block elements and line breaks do when rendered
+			sb.WriteByte(' ')
+		}
+	}
+}
+
+// firstImage returns the source of the first image in an HTML fragment.
+func firstImage(fragment string) string {
+	tokenizer := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return ""
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			if atom.Lookup(name) != atom.Img {
+				continue
+			}
+			for hasAttr {
+				var key, value []byte
+				key, value, hasAttr = tokenizer.TagAttr()
+				if string(key) == "src" {
+					return string(value)
+				}
+			}
+		}
+	}
+}
+
+// parseTime parses value with the first layout that accepts it, returning the
+// zero time when none does.
+func parseTime(value string, layouts ...string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,184 @@
+package feed
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/preslavrachev/gomjml/mjml"
+)
+
+const rssSample = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+  <channel>
+    <title>Weekly Digest</title>
+    <link>https://example.com/</link>
+    <description>News &amp; notes</description>
+    <item>
+      <title>First story</title>
+      <link>https://example.com/first</link>
+      <description>&lt;p&gt;Hello &lt;b&gt;world&lt;/b&gt;&lt;/p&gt;</description>
+      <pubDate>Tue, 06 Oct 2026 09:00:00 +0000</pubDate>
+      <media:content url="https://example.com/first.jpg" medium="image" />
+    </item>
+    <item>
+      <title>Second story</title>
+      <link>https://example.com/second</link>
+      <description>&lt;img src="https://example.com/second.png"&gt;Plain summary</description>
+    </item>
+  </channel>
+</rss>`
+
+const atomSample = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title type="html">Release &lt;em&gt;notes&lt;/em&gt;</title>
+  <link href="https://example.com/" />
+  <link rel="self" href="https://example.com/atom.xml" />
+  <entry>
+    <title>Version 1.2 &amp; more</title>
+    <link rel="alternate" href="https://example.com/v1.2" />
+    <link rel="enclosure" type="image/png" href="https://example.com/v1.2.png" />
+    <updated>2026-10-01T12:00:00Z</updated>
+    <content type="html">&lt;p&gt;Faster rendering.&lt;/p&gt;</content>
+  </entry>
+</feed>`
+
+const jsonSample = `{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "Changelog",
+  "home_page_url": "https://example.com/",
+  "items": [
+    {
+      "id": "1",
+      "url": "https://example.com/one",
+      "title": "One",
+      "content_html": "<p>First <img src=\"https://example.com/one.jpg\"> entry</p>",
+      "date_published": "2026-09-30T08:00:00Z"
+    }
+  ]
+}`
+
+func TestParse(t *testing.T) {
+	t.Run("RSS", func(t *testing.T) {
+		f, err := Parse([]byte(rssSample))
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if f.Title != "Weekly Digest" || f.Description != "News & notes" || f.Link != "https://example.com/" {
+			t.Errorf("unexpected feed fields: %+v", f)
+		}
+		if len(f.Items) != 2 {
+			t.Fatalf("expected 2 items, got %d", len(f.Items))
+		}
+		first := f.Items[0]
+		if first.Summary != "Hello world" {
+			t.Errorf("expected summary without markup, got %q", first.Summary)
+		}
+		if first.ImageURL != "https://example.com/first.jpg" {
+			t.Errorf("expected media:content image, got %q", first.ImageURL)
+		}
+		if first.Published.IsZero() || first.Published.Day() != 6 {
+			t.Errorf("expected pubDate to be parsed, got %v", first.Published)
+		}
+		if f.Items[1].ImageURL != "https://example.com/second.png" {
+			t.Errorf("expected image from description, got %q", f.Items[1].ImageURL)
+		}
+	})
+
+	t.Run("Atom", func(t *testing.T) {
+		f, err := Parse([]byte(atomSample))
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if f.Title != "Release notes" || f.Link != "https://example.com/" {
+			t.Errorf("unexpected feed fields: %+v", f)
+		}
+		if len(f.Items) != 1 {
+			t.Fatalf("expected 1 item, got %d", len(f.Items))
+		}
+		item := f.Items[0]
+		if item.Title != "Version 1.2 & more" || item.Link != "https://example.com/v1.2" {
+			t.Errorf("unexpected item fields: %+v", item)
+		}
+		if item.Summary != "Faster rendering." || item.ImageURL != "https://example.com/v1.2.png" {
+			t.Errorf("unexpected item content: %+v", item)
+		}
+		if item.Published.IsZero() {
+			t.Error("expected updated to be used as the publication date")
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		f, err := Parse([]byte(jsonSample))
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if len(f.Items) != 1 {
+			t.Fatalf("expected 1 item, got %d", len(f.Items))
+		}
+		item := f.Items[0]
+		if item.Summary != "First entry" || item.ImageURL != "https://example.com/one.jpg" {
+			t.Errorf("unexpected item content: %+v", item)
+		}
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		for _, input := range []string{`<html><body></body></html>`, `{"title": "not a feed"}`} {
+			if _, err := Parse([]byte(input)); !errors.Is(err, ErrUnknownFormat) {
+				t.Errorf("Parse(%q): expected ErrUnknownFormat, got %v", input, err)
+			}
+		}
+	})
+}
+
+func TestMJML(t *testing.T) {
+	f, err := Parse([]byte(rssSample))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	f.Items[0].Title = "Fish & <chips>"
+
+	layout := DefaultLayout()
+	layout.MaxItems = 1
+	layout.Footer = "You subscribed to the digest."
+	document := MJML(f, layout)
+
+	html, err := mjml.Render(document)
+	if err != nil {
+		t.Fatalf("generated MJML failed to render: %v\n%s", err, document)
+	}
+	for _, want := range []string{
+		"Fish &amp; &#60;chips&#62;",
+		`href="https://example.com/first"`,
+		`src="https://example.com/first.jpg"`,
+		"October 6, 2026",
+		"Read more",
+		"You subscribed to the digest.",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+	if strings.Contains(html, "Second story") {
+		t.Error("expected MaxItems to leave out the second item")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		text  string
+		limit int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"short", 0, "short"},
+		{"the quick brown fox", 12, "the quick…"},
+		{"one, two, three", 9, "one…"},
+		{"unbroken", 4, "unbr…"},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.text, tt.limit); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+		}
+	}
+}
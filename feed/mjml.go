@@ -0,0 +1,221 @@
+package feed
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/importer"
+	"github.com/preslavrachev/gomjml/parser"
+)
+
+// Layout controls how Generate lays out a feed. The JSON field names are
+// those of the layout files read by cmd/utils/feed2mjml.
+type Layout struct {
+	Title           string `json:"title"`           // Document title (empty uses the feed title)
+	Preview         string `json:"preview"`         // Inbox preview text (empty uses the feed description)
+	Intro           string `json:"intro"`           // Text below the heading (empty uses the feed description)
+	MaxItems        int    `json:"maxItems"`        // Number of items included, from the top of the feed (0 includes all)
+	SummaryLength   int    `json:"summaryLength"`   // Characters of each summary kept, cut at a word (0 keeps them whole)
+	ShowImages      bool   `json:"showImages"`      // Whether item images are shown above their titles
+	ShowDates       bool   `json:"showDates"`       // Whether publication dates are shown below the titles
+	DateFormat      string `json:"dateFormat"`      // Go time layout of the dates (empty uses "January 2, 2006")
+	ButtonText      string `json:"buttonText"`      // Label of the button linking each item (empty leaves it out)
+	Footer          string `json:"footer"`          // Text of the footer, such as an unsubscribe notice (HTML allowed)
+	FontFamily      string `json:"fontFamily"`      // Font family of all text
+	TextColor       string `json:"textColor"`       // Color of text
+	AccentColor     string `json:"accentColor"`     // Color of links and buttons
+	BackgroundColor string `json:"backgroundColor"` // Color behind the content
+	ContentColor    string `json:"contentColor"`    // Color of the content area
+}
+
+// DefaultLayout returns the layout used when no layout is given: the ten
+// most recent items with images, dates and summaries of up to 280 characters.
+func DefaultLayout() Layout {
+	return Layout{
+		MaxItems:        10,
+		SummaryLength:   280,
+		ShowImages:      true,
+		ShowDates:       true,
+		ButtonText:      "Read more",
+		FontFamily:      "Helvetica, Arial, sans-serif",
+		TextColor:       "#333333",
+		AccentColor:     "#1a73e8",
+		BackgroundColor: "#f4f4f4",
+		ContentColor:    "#ffffff",
+	}
+}
+
+// Generate lays out f as an MJML document: a heading with the feed title and
+// description, one section per item, and the footer.
+func Generate(f *Feed, layout Layout) *parser.MJMLNode {
+	root := newNode("mjml")
+
+	head := newNode("mj-head")
+	appendChild(head, newTextNode("mj-title", escape(firstNonEmpty(layout.Title, f.Title))))
+	if preview := firstNonEmpty(layout.Preview, f.Description); preview != "" {
+		appendChild(head, newTextNode("mj-preview", escape(preview)))
+	}
+	attributes := newNode("mj-attributes")
+	all := newNode("mj-all")
+	setAttr(all, "font-family", layout.FontFamily)
+	appendChild(attributes, all)
+	text := newNode("mj-text")
+	setAttr(text, "color", layout.TextColor)
+	setAttr(text, "font-size", "15px")
+	setAttr(text, "line-height", "1.5")
+	appendChild(attributes, text)
+	button := newNode("mj-button")
+	setAttr(button, "background-color", layout.AccentColor)
+	appendChild(attributes, button)
+	appendChild(head, attributes)
+	if layout.AccentColor != "" {
+		appendChild(head, newTextNode("mj-style", "a { color: "+layout.AccentColor+"; }"))
+	}
+	appendChild(root, head)
+
+	body := newNode("mj-body")
+	setAttr(body, "background-color", layout.BackgroundColor)
+
+	heading := newColumn(body, layout)
+	title := newTextNode("mj-text", escape(firstNonEmpty(layout.Title, f.Title)))
+	if f.Link != "" {
+		title.Text = `<a href="` + escape(f.Link) + `" style="color:inherit;text-decoration:none;">` + title.Text + `</a>`
+	}
+	setAttr(title, "font-size", "26px")
+	setAttr(title, "font-weight", "bold")
+	appendChild(heading, title)
+	if intro := firstNonEmpty(layout.Intro, f.Description); intro != "" {
+		appendChild(heading, newTextNode("mj-text", escape(intro)))
+	}
+
+	items := f.Items
+	if layout.MaxItems > 0 && len(items) > layout.MaxItems {
+		items = items[:layout.MaxItems]
+	}
+	for _, item := range items {
+		appendItem(newColumn(body, layout), item, layout)
+	}
+
+	if layout.Footer != "" {
+		footer := newTextNode("mj-text", layout.Footer)
+		setAttr(footer, "font-size", "12px")
+		setAttr(footer, "align", "center")
+		section := newNode("mj-section")
+		column := newNode("mj-column")
+		appendChild(column, footer)
+		appendChild(section, column)
+		appendChild(body, section)
+	}
+	appendChild(root, body)
+
+	return root
+}
+
+// MJML lays out f like Generate and returns the document as MJML markup.
+func MJML(f *Feed, layout Layout) string {
+	return importer.Format(Generate(f, layout))
+}
+
+// appendItem adds the image, title, date, summary and button of item to column.
+func appendItem(column *parser.MJMLNode, item Item, layout Layout) {
+	if layout.ShowImages && item.ImageURL != "" {
+		image := newNode("mj-image")
+		setAttr(image, "src", item.ImageURL)
+		setAttr(image, "alt", item.Title)
+		if item.Link != "" {
+			setAttr(image, "href", item.Link)
+		}
+		appendChild(column, image)
+	}
+
+	title := escape(item.Title)
+	if item.Link != "" {
+		title = `<a href="` + escape(item.Link) + `" style="color:inherit;text-decoration:none;">` + title + `</a>`
+	}
+	titleText := newTextNode("mj-text", title)
+	setAttr(titleText, "font-size", "20px")
+	setAttr(titleText, "font-weight", "bold")
+	appendChild(column, titleText)
+
+	if layout.ShowDates && !item.Published.IsZero() {
+		format := layout.DateFormat
+		if format == "" {
+			format = "January 2, 2006"
+		}
+		date := newTextNode("mj-text", escape(item.Published.Format(format)))
+		setAttr(date, "font-size", "12px")
+		setAttr(date, "color", "#888888")
+		setAttr(date, "padding-top", "0")
+		appendChild(column, date)
+	}
+
+	if summary := truncate(item.Summary, layout.SummaryLength); summary != "" {
+		appendChild(column, newTextNode("mj-text", escape(summary)))
+	}
+
+	if layout.ButtonText != "" && item.Link != "" {
+		button := newTextNode("mj-button", escape(layout.ButtonText))
+		setAttr(button, "href", item.Link)
+		setAttr(button, "align", "left")
+		appendChild(column, button)
+	}
+}
+
+// newColumn appends a section with the content color to body and returns its
+// single column.
+func newColumn(body *parser.MJMLNode, layout Layout) *parser.MJMLNode {
+	section := newNode("mj-section")
+	setAttr(section, "background-color", layout.ContentColor)
+	column := newNode("mj-column")
+	appendChild(section, column)
+	appendChild(body, section)
+	return column
+}
+
+// truncate cuts text to at most limit characters at a word boundary, marking
+// the cut with an ellipsis. A limit of zero or less keeps text whole.
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if limit <= 0 || len(runes) <= limit {
+		return text
+	}
+	cut := string(runes[:limit])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
+
+// textEscaper escapes text for the content of ending tags. Like the attribute
+// escaping of importer.Format, it uses numeric references because the MJML
+// parser decodes &lt; and &gt; into markup before parsing.
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&#60;", ">", "&#62;", `"`, "&#34;")
+
+func escape(text string) string {
+	return textEscaper.Replace(text)
+}
+
+func newNode(tag string) *parser.MJMLNode {
+	return &parser.MJMLNode{XMLName: xml.Name{Local: tag}}
+}
+
+func newTextNode(tag, text string) *parser.MJMLNode {
+	node := newNode(tag)
+	node.Text = text
+	node.MixedContent = []parser.MixedContentPart{{Text: text}}
+	return node
+}
+
+func appendChild(parent, child *parser.MJMLNode) {
+	parent.Children = append(parent.Children, child)
+	parent.MixedContent = append(parent.MixedContent, parser.MixedContentPart{Node: child})
+}
+
+// setAttr sets an attribute, leaving out empty values so that the defaults
+// of the component apply.
+func setAttr(node *parser.MJMLNode, name, value string) {
+	if value == "" {
+		return
+	}
+	node.Attrs = append(node.Attrs, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+}
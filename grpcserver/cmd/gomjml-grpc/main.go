@@ -0,0 +1,30 @@
+// Command gomjml-grpc serves the gomjml RenderService over gRPC.
+//
+//	go run ./cmd/gomjml-grpc -addr :50051
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+
+	"github.com/preslavrachev/gomjml/grpcserver"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	addr := flag.String("addr", ":50051", "address to listen on")
+	flag.Parse()
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	server := grpc.NewServer()
+	grpcserver.Register(server, &grpcserver.Server{})
+
+	log.Printf("gomjml RenderService listening on %s", listener.Addr())
+	if err := server.Serve(listener); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,18 @@
+module github.com/preslavrachev/gomjml/grpcserver
+
+go 1.24.4
+
+require (
+	github.com/preslavrachev/gomjml v0.0.0
+	google.golang.org/grpc v1.72.2
+	google.golang.org/protobuf v1.36.6
+)
+
+require (
+	golang.org/x/net v0.39.0 // indirect
+	golang.org/x/sys v0.32.0 // indirect
+	golang.org/x/text v0.24.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250218202821-56aae31c358a // indirect
+)
+
+replace github.com/preslavrachev/gomjml => ../
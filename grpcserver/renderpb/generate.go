@@ -0,0 +1,5 @@
+// Package renderpb holds the messages and service stubs generated from
+// render.proto.
+package renderpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative render.proto
@@ -0,0 +1,595 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: render.proto
+
+package renderpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Diagnostic_Kind int32
+
+const (
+	Diagnostic_KIND_UNSPECIFIED Diagnostic_Kind = 0
+	// The document is not well-formed MJML.
+	Diagnostic_KIND_PARSE Diagnostic_Kind = 1
+	// The document parses but breaks a component rule.
+	Diagnostic_KIND_VALIDATION Diagnostic_Kind = 2
+	// Rendering failed for another reason.
+	Diagnostic_KIND_RENDER Diagnostic_Kind = 3
+)
+
+// Enum value maps for Diagnostic_Kind.
+var (
+	Diagnostic_Kind_name = map[int32]string{
+		0: "KIND_UNSPECIFIED",
+		1: "KIND_PARSE",
+		2: "KIND_VALIDATION",
+		3: "KIND_RENDER",
+	}
+	Diagnostic_Kind_value = map[string]int32{
+		"KIND_UNSPECIFIED": 0,
+		"KIND_PARSE":       1,
+		"KIND_VALIDATION":  2,
+		"KIND_RENDER":      3,
+	}
+)
+
+func (x Diagnostic_Kind) Enum() *Diagnostic_Kind {
+	p := new(Diagnostic_Kind)
+	*p = x
+	return p
+}
+
+func (x Diagnostic_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Diagnostic_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_render_proto_enumTypes[0].Descriptor()
+}
+
+func (Diagnostic_Kind) Type() protoreflect.EnumType {
+	return &file_render_proto_enumTypes[0]
+}
+
+func (x Diagnostic_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Diagnostic_Kind.Descriptor instead.
+func (Diagnostic_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_render_proto_rawDescGZIP(), []int{5, 0}
+}
+
+type RenderOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Replaces the mj-title of the document.
+	Title string `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	// Replaces the mj-preview of the document.
+	PreviewText string `protobuf:"bytes,2,opt,name=preview_text,json=previewText,proto3" json:"preview_text,omitempty"`
+	// Sanitizes raw HTML embedded in mj-text, mj-raw and mj-table.
+	SanitizeHtml bool `protobuf:"varint,3,opt,name=sanitize_html,json=sanitizeHtml,proto3" json:"sanitize_html,omitempty"`
+	// Reports accessibility problems as validation diagnostics.
+	AccessibilityChecks bool `protobuf:"varint,4,opt,name=accessibility_checks,json=accessibilityChecks,proto3" json:"accessibility_checks,omitempty"`
+	// Values for mj-cond expressions.
+	Variables map[string]string `protobuf:"bytes,5,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Produces AMP for Email markup instead of classic HTML.
+	Amp           bool `protobuf:"varint,6,opt,name=amp,proto3" json:"amp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenderOptions) Reset() {
+	*x = RenderOptions{}
+	mi := &file_render_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenderOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenderOptions) ProtoMessage() {}
+
+func (x *RenderOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_render_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenderOptions.ProtoReflect.Descriptor instead.
+func (*RenderOptions) Descriptor() ([]byte, []int) {
+	return file_render_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RenderOptions) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *RenderOptions) GetPreviewText() string {
+	if x != nil {
+		return x.PreviewText
+	}
+	return ""
+}
+
+func (x *RenderOptions) GetSanitizeHtml() bool {
+	if x != nil {
+		return x.SanitizeHtml
+	}
+	return false
+}
+
+func (x *RenderOptions) GetAccessibilityChecks() bool {
+	if x != nil {
+		return x.AccessibilityChecks
+	}
+	return false
+}
+
+func (x *RenderOptions) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *RenderOptions) GetAmp() bool {
+	if x != nil {
+		return x.Amp
+	}
+	return false
+}
+
+type RenderRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Opaque caller-chosen id echoed in the response.
+	Id            string         `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Mjml          string         `protobuf:"bytes,2,opt,name=mjml,proto3" json:"mjml,omitempty"`
+	Options       *RenderOptions `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenderRequest) Reset() {
+	*x = RenderRequest{}
+	mi := &file_render_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenderRequest) ProtoMessage() {}
+
+func (x *RenderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_render_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenderRequest.ProtoReflect.Descriptor instead.
+func (*RenderRequest) Descriptor() ([]byte, []int) {
+	return file_render_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RenderRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RenderRequest) GetMjml() string {
+	if x != nil {
+		return x.Mjml
+	}
+	return ""
+}
+
+func (x *RenderRequest) GetOptions() *RenderOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type RenderResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Empty when the document could not be parsed.
+	Html          string        `protobuf:"bytes,2,opt,name=html,proto3" json:"html,omitempty"`
+	Diagnostics   []*Diagnostic `protobuf:"bytes,3,rep,name=diagnostics,proto3" json:"diagnostics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenderResponse) Reset() {
+	*x = RenderResponse{}
+	mi := &file_render_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenderResponse) ProtoMessage() {}
+
+func (x *RenderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_render_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenderResponse.ProtoReflect.Descriptor instead.
+func (*RenderResponse) Descriptor() ([]byte, []int) {
+	return file_render_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RenderResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RenderResponse) GetHtml() string {
+	if x != nil {
+		return x.Html
+	}
+	return ""
+}
+
+func (x *RenderResponse) GetDiagnostics() []*Diagnostic {
+	if x != nil {
+		return x.Diagnostics
+	}
+	return nil
+}
+
+type ValidateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mjml          string                 `protobuf:"bytes,1,opt,name=mjml,proto3" json:"mjml,omitempty"`
+	Options       *RenderOptions         `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateRequest) Reset() {
+	*x = ValidateRequest{}
+	mi := &file_render_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateRequest) ProtoMessage() {}
+
+func (x *ValidateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_render_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateRequest.ProtoReflect.Descriptor instead.
+func (*ValidateRequest) Descriptor() ([]byte, []int) {
+	return file_render_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ValidateRequest) GetMjml() string {
+	if x != nil {
+		return x.Mjml
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetOptions() *RenderOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type ValidateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Diagnostics   []*Diagnostic          `protobuf:"bytes,2,rep,name=diagnostics,proto3" json:"diagnostics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateResponse) Reset() {
+	*x = ValidateResponse{}
+	mi := &file_render_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateResponse) ProtoMessage() {}
+
+func (x *ValidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_render_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateResponse.ProtoReflect.Descriptor instead.
+func (*ValidateResponse) Descriptor() ([]byte, []int) {
+	return file_render_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ValidateResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateResponse) GetDiagnostics() []*Diagnostic {
+	if x != nil {
+		return x.Diagnostics
+	}
+	return nil
+}
+
+type Diagnostic struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Kind  Diagnostic_Kind        `protobuf:"varint,1,opt,name=kind,proto3,enum=gomjml.v1.Diagnostic_Kind" json:"kind,omitempty"`
+	Line  int32                  `protobuf:"varint,2,opt,name=line,proto3" json:"line,omitempty"`
+	// 0 when unknown.
+	Column        int32  `protobuf:"varint,3,opt,name=column,proto3" json:"column,omitempty"`
+	Tag           string `protobuf:"bytes,4,opt,name=tag,proto3" json:"tag,omitempty"`
+	Attribute     string `protobuf:"bytes,5,opt,name=attribute,proto3" json:"attribute,omitempty"`
+	Message       string `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Diagnostic) Reset() {
+	*x = Diagnostic{}
+	mi := &file_render_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Diagnostic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Diagnostic) ProtoMessage() {}
+
+func (x *Diagnostic) ProtoReflect() protoreflect.Message {
+	mi := &file_render_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Diagnostic.ProtoReflect.Descriptor instead.
+func (*Diagnostic) Descriptor() ([]byte, []int) {
+	return file_render_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Diagnostic) GetKind() Diagnostic_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return Diagnostic_KIND_UNSPECIFIED
+}
+
+func (x *Diagnostic) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *Diagnostic) GetColumn() int32 {
+	if x != nil {
+		return x.Column
+	}
+	return 0
+}
+
+func (x *Diagnostic) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *Diagnostic) GetAttribute() string {
+	if x != nil {
+		return x.Attribute
+	}
+	return ""
+}
+
+func (x *Diagnostic) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_render_proto protoreflect.FileDescriptor
+
+const file_render_proto_rawDesc = "" +
+	"\n" +
+	"\frender.proto\x12\tgomjml.v1\"\xb7\x02\n" +
+	"\rRenderOptions\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12!\n" +
+	"\fpreview_text\x18\x02 \x01(\tR\vpreviewText\x12#\n" +
+	"\rsanitize_html\x18\x03 \x01(\bR\fsanitizeHtml\x121\n" +
+	"\x14accessibility_checks\x18\x04 \x01(\bR\x13accessibilityChecks\x12E\n" +
+	"\tvariables\x18\x05 \x03(\v2'.gomjml.v1.RenderOptions.VariablesEntryR\tvariables\x12\x10\n" +
+	"\x03amp\x18\x06 \x01(\bR\x03amp\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"g\n" +
+	"\rRenderRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04mjml\x18\x02 \x01(\tR\x04mjml\x122\n" +
+	"\aoptions\x18\x03 \x01(\v2\x18.gomjml.v1.RenderOptionsR\aoptions\"m\n" +
+	"\x0eRenderResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04html\x18\x02 \x01(\tR\x04html\x127\n" +
+	"\vdiagnostics\x18\x03 \x03(\v2\x15.gomjml.v1.DiagnosticR\vdiagnostics\"Y\n" +
+	"\x0fValidateRequest\x12\x12\n" +
+	"\x04mjml\x18\x01 \x01(\tR\x04mjml\x122\n" +
+	"\aoptions\x18\x02 \x01(\v2\x18.gomjml.v1.RenderOptionsR\aoptions\"a\n" +
+	"\x10ValidateResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x127\n" +
+	"\vdiagnostics\x18\x02 \x03(\v2\x15.gomjml.v1.DiagnosticR\vdiagnostics\"\x86\x02\n" +
+	"\n" +
+	"Diagnostic\x12.\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x1a.gomjml.v1.Diagnostic.KindR\x04kind\x12\x12\n" +
+	"\x04line\x18\x02 \x01(\x05R\x04line\x12\x16\n" +
+	"\x06column\x18\x03 \x01(\x05R\x06column\x12\x10\n" +
+	"\x03tag\x18\x04 \x01(\tR\x03tag\x12\x1c\n" +
+	"\tattribute\x18\x05 \x01(\tR\tattribute\x12\x18\n" +
+	"\amessage\x18\x06 \x01(\tR\amessage\"R\n" +
+	"\x04Kind\x12\x14\n" +
+	"\x10KIND_UNSPECIFIED\x10\x00\x12\x0e\n" +
+	"\n" +
+	"KIND_PARSE\x10\x01\x12\x13\n" +
+	"\x0fKIND_VALIDATION\x10\x02\x12\x0f\n" +
+	"\vKIND_RENDER\x10\x032\xdb\x01\n" +
+	"\rRenderService\x12=\n" +
+	"\x06Render\x12\x18.gomjml.v1.RenderRequest\x1a\x19.gomjml.v1.RenderResponse\x12C\n" +
+	"\bValidate\x12\x1a.gomjml.v1.ValidateRequest\x1a\x1b.gomjml.v1.ValidateResponse\x12F\n" +
+	"\vRenderBatch\x12\x18.gomjml.v1.RenderRequest\x1a\x19.gomjml.v1.RenderResponse(\x010\x01B5Z3github.com/preslavrachev/gomjml/grpcserver/renderpbb\x06proto3"
+
+var (
+	file_render_proto_rawDescOnce sync.Once
+	file_render_proto_rawDescData []byte
+)
+
+func file_render_proto_rawDescGZIP() []byte {
+	file_render_proto_rawDescOnce.Do(func() {
+		file_render_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_render_proto_rawDesc), len(file_render_proto_rawDesc)))
+	})
+	return file_render_proto_rawDescData
+}
+
+var file_render_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_render_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_render_proto_goTypes = []any{
+	(Diagnostic_Kind)(0),     // 0: gomjml.v1.Diagnostic.Kind
+	(*RenderOptions)(nil),    // 1: gomjml.v1.RenderOptions
+	(*RenderRequest)(nil),    // 2: gomjml.v1.RenderRequest
+	(*RenderResponse)(nil),   // 3: gomjml.v1.RenderResponse
+	(*ValidateRequest)(nil),  // 4: gomjml.v1.ValidateRequest
+	(*ValidateResponse)(nil), // 5: gomjml.v1.ValidateResponse
+	(*Diagnostic)(nil),       // 6: gomjml.v1.Diagnostic
+	nil,                      // 7: gomjml.v1.RenderOptions.VariablesEntry
+}
+var file_render_proto_depIdxs = []int32{
+	7, // 0: gomjml.v1.RenderOptions.variables:type_name -> gomjml.v1.RenderOptions.VariablesEntry
+	1, // 1: gomjml.v1.RenderRequest.options:type_name -> gomjml.v1.RenderOptions
+	6, // 2: gomjml.v1.RenderResponse.diagnostics:type_name -> gomjml.v1.Diagnostic
+	1, // 3: gomjml.v1.ValidateRequest.options:type_name -> gomjml.v1.RenderOptions
+	6, // 4: gomjml.v1.ValidateResponse.diagnostics:type_name -> gomjml.v1.Diagnostic
+	0, // 5: gomjml.v1.Diagnostic.kind:type_name -> gomjml.v1.Diagnostic.Kind
+	2, // 6: gomjml.v1.RenderService.Render:input_type -> gomjml.v1.RenderRequest
+	4, // 7: gomjml.v1.RenderService.Validate:input_type -> gomjml.v1.ValidateRequest
+	2, // 8: gomjml.v1.RenderService.RenderBatch:input_type -> gomjml.v1.RenderRequest
+	3, // 9: gomjml.v1.RenderService.Render:output_type -> gomjml.v1.RenderResponse
+	5, // 10: gomjml.v1.RenderService.Validate:output_type -> gomjml.v1.ValidateResponse
+	3, // 11: gomjml.v1.RenderService.RenderBatch:output_type -> gomjml.v1.RenderResponse
+	9, // [9:12] is the sub-list for method output_type
+	6, // [6:9] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_render_proto_init() }
+func file_render_proto_init() {
+	if File_render_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_render_proto_rawDesc), len(file_render_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_render_proto_goTypes,
+		DependencyIndexes: file_render_proto_depIdxs,
+		EnumInfos:         file_render_proto_enumTypes,
+		MessageInfos:      file_render_proto_msgTypes,
+	}.Build()
+	File_render_proto = out.File
+	file_render_proto_goTypes = nil
+	file_render_proto_depIdxs = nil
+}
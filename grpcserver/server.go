@@ -0,0 +1,158 @@
+// Package grpcserver serves gomjml over gRPC with the RenderService defined in
+// renderpb/render.proto, letting services written in other languages compile
+// MJML without shelling out to the CLI:
+//
+//	s := grpc.NewServer()
+//	grpcserver.Register(s, &grpcserver.Server{})
+//	s.Serve(listener)
+//
+// It is a separate module so that the gomjml module does not depend on gRPC.
+// The generated code in renderpb is regenerated with go generate ./renderpb.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/preslavrachev/gomjml/grpcserver/renderpb"
+	"github.com/preslavrachev/gomjml/mjml"
+	"github.com/preslavrachev/gomjml/parser"
+	"google.golang.org/grpc"
+)
+
+// Server implements renderpb.RenderServiceServer. The zero value is ready to
+// use.
+type Server struct {
+	renderpb.UnimplementedRenderServiceServer
+
+	// Options are applied to every request before the request's own options.
+	Options []mjml.RenderOption
+}
+
+// Register registers srv as the RenderService of s.
+func Register(s grpc.ServiceRegistrar, srv *Server) {
+	renderpb.RegisterRenderServiceServer(s, srv)
+}
+
+// Render compiles a single document. Problems in the document are returned as
+// diagnostics rather than as an error; the error is only set when ctx is done.
+func (s *Server) Render(ctx context.Context, req *renderpb.RenderRequest) (*renderpb.RenderResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.render(req), nil
+}
+
+// Validate reports the problems in a document without returning HTML.
+func (s *Server) Validate(ctx context.Context, req *renderpb.ValidateRequest) (*renderpb.ValidateResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	resp := s.render(&renderpb.RenderRequest{Mjml: req.GetMjml(), Options: req.GetOptions()})
+	return &renderpb.ValidateResponse{
+		Valid:       len(resp.Diagnostics) == 0,
+		Diagnostics: resp.Diagnostics,
+	}, nil
+}
+
+// RenderBatch renders every request received on stream and sends one response
+// per request, in order, until the client closes its side of the stream.
+func (s *Server) RenderBatch(stream renderpb.RenderService_RenderBatchServer) error {
+	ctx := stream.Context()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(s.render(req)); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) render(req *renderpb.RenderRequest) *renderpb.RenderResponse {
+	opts := append(append([]mjml.RenderOption(nil), s.Options...), renderOptions(req.GetOptions())...)
+	html, err := mjml.Render(req.GetMjml(), opts...)
+	return &renderpb.RenderResponse{
+		Id:          req.GetId(),
+		Html:        html,
+		Diagnostics: diagnostics(err),
+	}
+}
+
+// renderOptions converts the request options to mjml render options.
+func renderOptions(o *renderpb.RenderOptions) []mjml.RenderOption {
+	if o == nil {
+		return nil
+	}
+	var opts []mjml.RenderOption
+	if o.GetTitle() != "" {
+		opts = append(opts, mjml.WithTitle(o.GetTitle()))
+	}
+	if o.GetPreviewText() != "" {
+		opts = append(opts, mjml.WithPreviewText(o.GetPreviewText()))
+	}
+	if o.GetSanitizeHtml() {
+		opts = append(opts, mjml.WithSanitizeHTML())
+	}
+	if o.GetAccessibilityChecks() {
+		opts = append(opts, mjml.WithAccessibilityChecks())
+	}
+	if len(o.GetVariables()) > 0 {
+		vars := make(map[string]any, len(o.GetVariables()))
+		for k, v := range o.GetVariables() {
+			vars[k] = v
+		}
+		opts = append(opts, mjml.WithVariables(vars))
+	}
+	if o.GetAmp() {
+		opts = append(opts, mjml.WithOutputFormat(mjml.FormatAMP))
+	}
+	return opts
+}
+
+// diagnostics converts a render error to diagnostics.
+func diagnostics(err error) []*renderpb.Diagnostic {
+	if err == nil {
+		return nil
+	}
+
+	var parseErr *parser.ParseError
+	if errors.As(err, &parseErr) {
+		diags := make([]*renderpb.Diagnostic, 0, len(parseErr.Errors))
+		for _, e := range parseErr.Errors {
+			diags = append(diags, &renderpb.Diagnostic{
+				Kind:      renderpb.Diagnostic_KIND_PARSE,
+				Line:      int32(e.Line),
+				Column:    int32(e.Column),
+				Tag:       e.Tag,
+				Attribute: e.Attribute,
+				Message:   e.Message,
+			})
+		}
+		return diags
+	}
+
+	var mjmlErr mjml.Error
+	if errors.As(err, &mjmlErr) && len(mjmlErr.Details) > 0 {
+		diags := make([]*renderpb.Diagnostic, 0, len(mjmlErr.Details))
+		for _, d := range mjmlErr.Details {
+			diags = append(diags, &renderpb.Diagnostic{
+				Kind:    renderpb.Diagnostic_KIND_VALIDATION,
+				Line:    int32(d.Line),
+				Tag:     d.TagName,
+				Message: d.Message,
+			})
+		}
+		return diags
+	}
+
+	return []*renderpb.Diagnostic{{Kind: renderpb.Diagnostic_KIND_RENDER, Message: err.Error()}}
+}
@@ -0,0 +1,170 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/preslavrachev/gomjml/grpcserver/renderpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const validDoc = `<mjml><mj-body><mj-section><mj-column><mj-text>Hello</mj-text></mj-column></mj-section></mj-body></mjml>`
+
+// newClient starts a server with a registered Server on an in-memory
+// listener and returns a client connected to it.
+func newClient(t *testing.T) renderpb.RenderServiceClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	Register(server, &Server{})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return renderpb.NewRenderServiceClient(conn)
+}
+
+func TestRender(t *testing.T) {
+	client := newClient(t)
+
+	resp, err := client.Render(context.Background(), &renderpb.RenderRequest{
+		Id:      "welcome",
+		Mjml:    validDoc,
+		Options: &renderpb.RenderOptions{Title: "Welcome"},
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if resp.GetId() != "welcome" {
+		t.Errorf("Id = %q, want %q", resp.GetId(), "welcome")
+	}
+	if !strings.Contains(resp.GetHtml(), "<title>Welcome</title>") || !strings.Contains(resp.GetHtml(), "Hello") {
+		t.Errorf("unexpected HTML: %s", resp.GetHtml())
+	}
+	if len(resp.GetDiagnostics()) != 0 {
+		t.Errorf("unexpected diagnostics: %+v", resp.GetDiagnostics())
+	}
+}
+
+func TestRenderDiagnostics(t *testing.T) {
+	client := newClient(t)
+
+	resp, err := client.Render(context.Background(), &renderpb.RenderRequest{
+		Mjml: "<mjml><mj-body>\n<mj-section foo=\"1\"></mj-section></mj-body></mjml>",
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if resp.GetHtml() == "" {
+		t.Error("validation errors should still produce HTML")
+	}
+	if len(resp.GetDiagnostics()) != 1 {
+		t.Fatalf("got %d diagnostics, want 1", len(resp.GetDiagnostics()))
+	}
+	if d := resp.GetDiagnostics()[0]; d.GetKind() != renderpb.Diagnostic_KIND_VALIDATION || d.GetLine() != 2 || d.GetTag() != "mj-section" {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	}
+
+	resp, err = client.Render(context.Background(), &renderpb.RenderRequest{Mjml: `<mjml><mj-body><mj-image src=x /></mj-body></mjml>`})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if resp.GetHtml() != "" {
+		t.Errorf("parse errors should not produce HTML, got %q", resp.GetHtml())
+	}
+	if len(resp.GetDiagnostics()) != 1 {
+		t.Fatalf("got %d diagnostics, want 1", len(resp.GetDiagnostics()))
+	}
+	if d := resp.GetDiagnostics()[0]; d.GetKind() != renderpb.Diagnostic_KIND_PARSE || d.GetColumn() != 30 || d.GetAttribute() != "src" {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	client := newClient(t)
+
+	resp, err := client.Validate(context.Background(), &renderpb.ValidateRequest{Mjml: validDoc})
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if !resp.GetValid() {
+		t.Errorf("expected valid document, got %+v", resp.GetDiagnostics())
+	}
+
+	resp, err = client.Validate(context.Background(), &renderpb.ValidateRequest{Mjml: `<mjml><mj-body><mj-section>`})
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if resp.GetValid() || len(resp.GetDiagnostics()) == 0 {
+		t.Errorf("expected diagnostics for unclosed tags, got %+v", resp)
+	}
+}
+
+func TestRenderBatch(t *testing.T) {
+	client := newClient(t)
+
+	stream, err := client.RenderBatch(context.Background())
+	if err != nil {
+		t.Fatalf("render batch: %v", err)
+	}
+	reqs := []*renderpb.RenderRequest{
+		{Id: "a", Mjml: validDoc},
+		{Id: "b", Mjml: `<mjml>`},
+		{Id: "c", Mjml: validDoc, Options: &renderpb.RenderOptions{PreviewText: "Hi there"}},
+	}
+	for _, req := range reqs {
+		if err := stream.Send(req); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("close send: %v", err)
+	}
+
+	var sent []*renderpb.RenderResponse
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("recv: %v", err)
+		}
+		sent = append(sent, resp)
+	}
+	if len(sent) != 3 {
+		t.Fatalf("got %d responses, want 3", len(sent))
+	}
+	for i, id := range []string{"a", "b", "c"} {
+		if sent[i].GetId() != id {
+			t.Errorf("response %d has Id %q, want %q", i, sent[i].GetId(), id)
+		}
+	}
+	if len(sent[1].GetDiagnostics()) == 0 {
+		t.Error("expected diagnostics for the malformed document")
+	}
+	if !strings.Contains(sent[2].GetHtml(), "Hi there") {
+		t.Error("expected preview text in the third response")
+	}
+}
+
+func TestRenderCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := (&Server{}).Render(ctx, &renderpb.RenderRequest{Mjml: validDoc}); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,67 @@
+package importer
+
+import (
+	"strings"
+
+	"github.com/preslavrachev/gomjml/parser"
+)
+
+// indentUnit is the indentation used per nesting level in formatted MJML.
+const indentUnit = "  "
+
+// endingTags hold raw content rather than MJML children and are written on one line.
+var endingTags = map[string]struct{}{
+	"mj-text":    {},
+	"mj-button":  {},
+	"mj-title":   {},
+	"mj-preview": {},
+	"mj-style":   {},
+	"mj-raw":     {},
+	"mj-table":   {},
+}
+
+// attrEscaper escapes attribute values. Angle brackets and quotes use numeric
+// references, since the MJML parser decodes &lt;, &gt; and &quot; before
+// parsing and would read them as markup.
+var attrEscaper = strings.NewReplacer(`&`, "&amp;", `<`, "&#60;", `>`, "&#62;", `"`, "&#34;")
+
+// MJML returns the converted document as indented MJML markup.
+func (r *Result) MJML() string {
+	return Format(r.AST)
+}
+
+// Format writes node and its descendants as indented MJML markup. The content
+// of ending tags such as mj-text is written unchanged.
+func Format(node *parser.MJMLNode) string {
+	var sb strings.Builder
+	formatNode(&sb, node, 0)
+	return sb.String()
+}
+
+func formatNode(sb *strings.Builder, node *parser.MJMLNode, depth int) {
+	if node == nil {
+		return
+	}
+	tag := node.GetTagName()
+
+	sb.WriteString(strings.Repeat(indentUnit, depth))
+	sb.WriteString("<" + tag)
+	for _, attr := range node.Attrs {
+		sb.WriteString(" " + attr.Name.Local + `="` + attrEscaper.Replace(attr.Value) + `"`)
+	}
+
+	if _, ok := endingTags[tag]; ok {
+		sb.WriteString(">" + node.Text + "</" + tag + ">\n")
+		return
+	}
+	if len(node.Children) == 0 {
+		sb.WriteString(" />\n")
+		return
+	}
+
+	sb.WriteString(">\n")
+	for _, child := range node.Children {
+		formatNode(sb, child, depth+1)
+	}
+	sb.WriteString(strings.Repeat(indentUnit, depth) + "</" + tag + ">\n")
+}
@@ -0,0 +1,526 @@
+// Package importer converts legacy table-based email HTML into an approximate
+// MJML document so existing templates can be migrated into MJML workflows.
+//
+// The conversion is heuristic. Table rows become mj-section elements, their
+// cells become mj-column elements, and cell content is mapped to mj-image,
+// mj-button, mj-divider and mj-text. Layout tables nested in a single cell are
+// flattened. Anything that has no MJML equivalent is dropped and listed in
+// Result.Unconverted so it can be migrated by hand.
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/parser"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// maxSnippetLength bounds the HTML excerpt stored for each unconverted region.
+const maxSnippetLength = 120
+
+// Result is the outcome of an import.
+type Result struct {
+	AST         *parser.MJMLNode // Root mjml element of the converted document
+	Unconverted []Region         // Parts of the input that were dropped
+}
+
+// Region describes a part of the input HTML that could not be converted.
+type Region struct {
+	Tag    string // HTML tag of the dropped element
+	Reason string // Why the element has no MJML equivalent
+	HTML   string // Leading excerpt of the element's markup
+}
+
+// unsupportedElements lists elements that cannot be represented in MJML.
+var unsupportedElements = map[atom.Atom]string{
+	atom.Script:   "scripts are not supported in email",
+	atom.Form:     "forms have no MJML equivalent",
+	atom.Input:    "form controls have no MJML equivalent",
+	atom.Select:   "form controls have no MJML equivalent",
+	atom.Textarea: "form controls have no MJML equivalent",
+	atom.Button:   "form controls have no MJML equivalent",
+	atom.Iframe:   "embedded frames are not supported in email",
+	atom.Object:   "embedded objects are not supported in email",
+	atom.Embed:    "embedded objects are not supported in email",
+	atom.Video:    "media elements have no MJML equivalent",
+	atom.Audio:    "media elements have no MJML equivalent",
+	atom.Canvas:   "canvas has no MJML equivalent",
+	atom.Svg:      "inline SVG has no MJML equivalent",
+}
+
+// Import converts an HTML email document into an MJML AST.
+func Import(htmlContent string) (*Result, error) {
+	doc, err := html.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	imp := &importer{}
+	root := newNode("mjml")
+
+	head := newNode("mj-head")
+	if title := findFirst(doc, atom.Title); title != nil {
+		if text := strings.TrimSpace(textContent(title)); text != "" {
+			appendChild(head, newTextNode("mj-title", text))
+		}
+	}
+	for _, style := range findAll(doc, atom.Style) {
+		if css := strings.TrimSpace(textContent(style)); css != "" {
+			appendChild(head, newTextNode("mj-style", css))
+		}
+	}
+	if len(head.Children) > 0 {
+		appendChild(root, head)
+	}
+
+	body := newNode("mj-body")
+	if bodyEl := findFirst(doc, atom.Body); bodyEl != nil {
+		if bg := backgroundColor(bodyEl); bg != "" {
+			setAttr(body, "background-color", bg)
+		}
+		imp.convertBlocks(bodyEl, body)
+	}
+	appendChild(root, body)
+
+	return &Result{AST: root, Unconverted: imp.unconverted}, nil
+}
+
+type importer struct {
+	unconverted []Region
+}
+
+// convertBlocks appends the sections for the block-level content of n to parent.
+// Loose content between layout tables is collected into single-column sections.
+func (imp *importer) convertBlocks(n *html.Node, parent *parser.MJMLNode) {
+	var loose []*html.Node
+	flush := func() {
+		if len(loose) == 0 {
+			return
+		}
+		column := newNode("mj-column")
+		for _, c := range loose {
+			imp.convertContent(c, column)
+		}
+		loose = nil
+		if len(column.Children) > 0 {
+			section := newNode("mj-section")
+			appendChild(section, column)
+			appendChild(parent, section)
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case c.Type == html.ElementNode && c.DataAtom == atom.Table:
+			flush()
+			imp.convertTable(c, parent)
+		case c.Type == html.ElementNode && (c.DataAtom == atom.Div || c.DataAtom == atom.Center) && findFirst(c, atom.Table) != nil:
+			flush()
+			imp.convertBlocks(c, parent)
+		default:
+			loose = append(loose, c)
+		}
+	}
+	flush()
+}
+
+// convertTable turns each row of a layout table into a section. A row with a
+// single cell that wraps further tables is treated as a wrapper and flattened.
+func (imp *importer) convertTable(table *html.Node, parent *parser.MJMLNode) {
+	tableBg := backgroundColor(table)
+	for _, row := range tableRows(table) {
+		cells := childElements(row, atom.Td, atom.Th)
+		if len(cells) == 0 {
+			continue
+		}
+		if len(cells) == 1 && findFirst(cells[0], atom.Table) != nil && !hasDirectContent(cells[0]) {
+			imp.convertBlocks(cells[0], parent)
+			continue
+		}
+
+		section := newNode("mj-section")
+		if bg := firstNonEmpty(backgroundColor(row), tableBg); bg != "" {
+			setAttr(section, "background-color", bg)
+		}
+		for _, cell := range cells {
+			column := newNode("mj-column")
+			if width := attr(cell, "width"); width != "" {
+				setAttr(column, "width", cssLength(width))
+			}
+			if valign := attr(cell, "valign"); valign != "" {
+				setAttr(column, "vertical-align", valign)
+			}
+			if isButtonCell(cell) {
+				appendChild(column, buttonNode(cell))
+			} else {
+				if bg := backgroundColor(cell); bg != "" {
+					setAttr(column, "background-color", bg)
+				}
+				for c := cell.FirstChild; c != nil; c = c.NextSibling {
+					imp.convertContent(c, column)
+				}
+			}
+			if align := attr(cell, "align"); align != "" {
+				for _, content := range column.Children {
+					if content.GetAttribute("align") == "" {
+						setAttr(content, "align", align)
+					}
+				}
+			}
+			appendChild(section, column)
+		}
+		appendChild(parent, section)
+	}
+}
+
+// convertContent maps the content of a cell to MJML content elements. Runs of
+// inline content and text blocks are merged into a single mj-text.
+func (imp *importer) convertContent(n *html.Node, column *parser.MJMLNode) {
+	switch n.Type {
+	case html.CommentNode:
+		return
+	case html.TextNode:
+		if strings.TrimSpace(n.Data) != "" {
+			appendText(column, html.EscapeString(n.Data))
+		}
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	if reason, ok := unsupportedElements[n.DataAtom]; ok {
+		imp.report(n, reason)
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Table, atom.Tbody, atom.Thead, atom.Tfoot, atom.Tr, atom.Td, atom.Th, atom.Div, atom.Center:
+		// Nested layout containers inside a column are flattened.
+		if n.DataAtom == atom.Td || n.DataAtom == atom.Th || n.DataAtom == atom.Div {
+			if isButtonCell(n) {
+				appendChild(column, buttonNode(n))
+				return
+			}
+		}
+		if n.DataAtom == atom.Div && !containsBlock(n) {
+			appendText(column, renderHTML(n))
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			imp.convertContent(c, column)
+		}
+	case atom.Img:
+		appendChild(column, imageNode(n, ""))
+	case atom.A:
+		if img := onlyChildElement(n); img != nil && img.DataAtom == atom.Img {
+			appendChild(column, imageNode(img, attr(n, "href")))
+			return
+		}
+		if isButtonLink(n) {
+			appendChild(column, buttonNode(n))
+			return
+		}
+		appendText(column, renderHTML(n))
+	case atom.Hr:
+		appendChild(column, newNode("mj-divider"))
+	case atom.Br:
+		// Breaks only matter between pieces of text; between blocks they add nothing.
+		if n := len(column.Children); n > 0 && column.Children[n-1].GetTagName() == "mj-text" {
+			appendText(column, "<br />")
+		}
+	default:
+		appendText(column, renderHTML(n))
+	}
+}
+
+func (imp *importer) report(n *html.Node, reason string) {
+	snippet := renderHTML(n)
+	if len(snippet) > maxSnippetLength {
+		snippet = snippet[:maxSnippetLength] + "..."
+	}
+	imp.unconverted = append(imp.unconverted, Region{Tag: n.Data, Reason: reason, HTML: snippet})
+}
+
+// appendText adds markup to the trailing mj-text of column, starting a new one
+// when the last content element is not text.
+func appendText(column *parser.MJMLNode, markup string) {
+	if n := len(column.Children); n > 0 && column.Children[n-1].GetTagName() == "mj-text" {
+		last := column.Children[n-1]
+		last.Text += markup
+		last.MixedContent = []parser.MixedContentPart{{Text: last.Text}}
+		return
+	}
+	appendChild(column, newTextNode("mj-text", markup))
+}
+
+func imageNode(img *html.Node, href string) *parser.MJMLNode {
+	node := newNode("mj-image")
+	setAttr(node, "src", attr(img, "src"))
+	setAttr(node, "alt", attr(img, "alt"))
+	if width := attr(img, "width"); width != "" {
+		setAttr(node, "width", cssLength(width))
+	}
+	if height := attr(img, "height"); height != "" {
+		setAttr(node, "height", cssLength(height))
+	}
+	if href != "" {
+		setAttr(node, "href", href)
+	}
+	return node
+}
+
+// buttonNode converts a styled link, or a cell wrapping a single link, into mj-button.
+func buttonNode(n *html.Node) *parser.MJMLNode {
+	link := n
+	bg := backgroundColor(n)
+	if n.DataAtom != atom.A {
+		link = findFirst(n, atom.A)
+		if linkBg := backgroundColor(link); linkBg != "" {
+			bg = linkBg
+		}
+	}
+
+	node := newNode("mj-button")
+	setAttr(node, "href", attr(link, "href"))
+	if bg != "" {
+		setAttr(node, "background-color", bg)
+	}
+	styles := inlineStyles(link)
+	if color := styles["color"]; color != "" {
+		setAttr(node, "color", color)
+	}
+	if radius := firstNonEmpty(inlineStyles(n)["border-radius"], styles["border-radius"]); radius != "" {
+		setAttr(node, "border-radius", radius)
+	}
+
+	var content strings.Builder
+	for c := link.FirstChild; c != nil; c = c.NextSibling {
+		content.WriteString(renderHTML(c))
+	}
+	node.Text = strings.TrimSpace(content.String())
+	node.MixedContent = []parser.MixedContentPart{{Text: node.Text}}
+	return node
+}
+
+// isButtonLink reports whether a link is styled as a standalone button.
+func isButtonLink(a *html.Node) bool {
+	styles := inlineStyles(a)
+	return backgroundColor(a) != "" && (styles["display"] == "inline-block" || styles["display"] == "block" || styles["padding"] != "")
+}
+
+// isButtonCell reports whether a cell only wraps a single link on a background color.
+func isButtonCell(n *html.Node) bool {
+	if backgroundColor(n) == "" {
+		return false
+	}
+	link := onlyChildElement(n)
+	return link != nil && link.DataAtom == atom.A && onlyChildElement(link) == nil && strings.TrimSpace(textContent(link)) != ""
+}
+
+func newNode(tag string) *parser.MJMLNode {
+	return &parser.MJMLNode{
+		XMLName:      xml.Name{Local: tag},
+		Children:     make([]*parser.MJMLNode, 0),
+		MixedContent: make([]parser.MixedContentPart, 0),
+	}
+}
+
+func newTextNode(tag, text string) *parser.MJMLNode {
+	node := newNode(tag)
+	node.Text = text
+	node.MixedContent = []parser.MixedContentPart{{Text: text}}
+	return node
+}
+
+func appendChild(parent, child *parser.MJMLNode) {
+	parent.Children = append(parent.Children, child)
+	parent.MixedContent = append(parent.MixedContent, parser.MixedContentPart{Node: child})
+}
+
+func setAttr(node *parser.MJMLNode, name, value string) {
+	node.Attrs = append(node.Attrs, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+}
+
+func attr(n *html.Node, name string) string {
+	if n == nil {
+		return ""
+	}
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+// inlineStyles parses the style attribute of n into a property map.
+func inlineStyles(n *html.Node) map[string]string {
+	styles := make(map[string]string)
+	for _, decl := range strings.Split(attr(n, "style"), ";") {
+		if name, value, ok := strings.Cut(decl, ":"); ok {
+			styles[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+		}
+	}
+	return styles
+}
+
+// backgroundColor returns the bgcolor attribute or CSS background color of n.
+func backgroundColor(n *html.Node) string {
+	if n == nil {
+		return ""
+	}
+	styles := inlineStyles(n)
+	if bg := styles["background-color"]; bg != "" {
+		return bg
+	}
+	if bg := styles["background"]; bg != "" && !strings.Contains(bg, "url(") {
+		return bg
+	}
+	return attr(n, "bgcolor")
+}
+
+// cssLength converts an HTML width or height attribute to a CSS length.
+func cssLength(value string) string {
+	if strings.HasSuffix(value, "%") || strings.HasSuffix(value, "px") {
+		return value
+	}
+	return value + "px"
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// tableRows returns the rows of table, looking through row groups but not into nested tables.
+func tableRows(table *html.Node) []*html.Node {
+	var rows []*html.Node
+	for c := table.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		switch c.DataAtom {
+		case atom.Tr:
+			rows = append(rows, c)
+		case atom.Tbody, atom.Thead, atom.Tfoot:
+			rows = append(rows, childElements(c, atom.Tr)...)
+		}
+	}
+	return rows
+}
+
+func childElements(n *html.Node, tags ...atom.Atom) []*html.Node {
+	var out []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		for _, tag := range tags {
+			if c.DataAtom == tag {
+				out = append(out, c)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// onlyChildElement returns the single element child of n when n has no other
+// element children and no non-whitespace text.
+func onlyChildElement(n *html.Node) *html.Node {
+	var only *html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.ElementNode:
+			if only != nil {
+				return nil
+			}
+			only = c
+		case html.TextNode:
+			if strings.TrimSpace(c.Data) != "" {
+				return nil
+			}
+		}
+	}
+	return only
+}
+
+// hasDirectContent reports whether n has text or non-table elements beside its tables.
+func hasDirectContent(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.TextNode:
+			if strings.TrimSpace(c.Data) != "" {
+				return true
+			}
+		case html.ElementNode:
+			if c.DataAtom != atom.Table && c.DataAtom != atom.Br {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsBlock reports whether n contains layout elements that need their own MJML mapping.
+func containsBlock(n *html.Node) bool {
+	for _, tag := range []atom.Atom{atom.Table, atom.Img, atom.Hr, atom.Div} {
+		if findFirst(n, tag) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func findFirst(n *html.Node, tag atom.Atom) *html.Node {
+	if n == nil {
+		return nil
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.DataAtom == tag {
+			return c
+		}
+		if found := findFirst(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func findAll(n *html.Node, tag atom.Atom) []*html.Node {
+	var out []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.DataAtom == tag {
+			out = append(out, c)
+		}
+		out = append(out, findAll(c, tag)...)
+	}
+	return out
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(textContent(c))
+	}
+	return sb.String()
+}
+
+func renderHTML(n *html.Node) string {
+	var sb strings.Builder
+	if err := html.Render(&sb, n); err != nil {
+		return ""
+	}
+	return sb.String()
+}
@@ -0,0 +1,96 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preslavrachev/gomjml/mjml"
+)
+
+const legacyEmail = `<html><head><title>Spring sale</title><style>p { margin: 0; }</style></head>
+<body bgcolor="#eeeeee">
+<table width="600" align="center"><tr><td>
+  <table width="100%"><tr><td align="center"><a href="https://example.com"><img src="https://example.com/logo.png" alt="Logo" width="200"></a></td></tr></table>
+  <table width="100%" bgcolor="#ffffff"><tr>
+    <td width="300" valign="top"><h1>Spring sale</h1><p>Save <b>20%</b> this week.</p>Line one<br>Line two</td>
+    <td width="300"><img src="https://example.com/shoe.png"><hr></td>
+  </tr></table>
+  <table><tr><td bgcolor="#ff6600" style="border-radius:4px"><a href="https://example.com/buy" style="color:#ffffff">Shop now</a></td></tr></table>
+</td></tr></table>
+<form action="/subscribe"><input name="email"></form>
+<script>track()</script>
+</body></html>`
+
+func TestImport(t *testing.T) {
+	result, err := Import(legacyEmail)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	want := `<mjml>
+  <mj-head>
+    <mj-title>Spring sale</mj-title>
+    <mj-style>p { margin: 0; }</mj-style>
+  </mj-head>
+  <mj-body background-color="#eeeeee">
+    <mj-section>
+      <mj-column>
+        <mj-image src="https://example.com/logo.png" alt="Logo" width="200px" href="https://example.com" align="center" />
+      </mj-column>
+    </mj-section>
+    <mj-section background-color="#ffffff">
+      <mj-column width="300px" vertical-align="top">
+        <mj-text><h1>Spring sale</h1><p>Save <b>20%</b> this week.</p>Line one<br />Line two</mj-text>
+      </mj-column>
+      <mj-column width="300px">
+        <mj-image src="https://example.com/shoe.png" alt="" />
+        <mj-divider />
+      </mj-column>
+    </mj-section>
+    <mj-section>
+      <mj-column>
+        <mj-button href="https://example.com/buy" background-color="#ff6600" color="#ffffff" border-radius="4px">Shop now</mj-button>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>
+`
+	if got := result.MJML(); got != want {
+		t.Errorf("unexpected MJML:\n%s\nwant:\n%s", got, want)
+	}
+
+	if len(result.Unconverted) != 2 {
+		t.Fatalf("expected 2 unconverted regions, got %+v", result.Unconverted)
+	}
+	if result.Unconverted[0].Tag != "form" || result.Unconverted[1].Tag != "script" {
+		t.Errorf("unexpected unconverted regions: %+v", result.Unconverted)
+	}
+}
+
+func TestImportOutputRenders(t *testing.T) {
+	result, err := Import(legacyEmail)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	html, err := mjml.Render(result.MJML())
+	if err != nil {
+		t.Fatalf("rendering imported MJML: %v", err)
+	}
+	for _, want := range []string{"Spring sale", "Shop now", "https://example.com/shoe.png"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %q in rendered output", want)
+		}
+	}
+}
+
+func TestImportLooseContent(t *testing.T) {
+	result, err := Import(`<body><p>Hello</p><img src="a.png" alt="A"></body>`)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	body := result.AST.Children[0]
+	if len(body.Children) != 1 || len(body.Children[0].Children[0].Children) != 2 {
+		t.Fatalf("expected one section with text and image, got:\n%s", result.MJML())
+	}
+}
@@ -0,0 +1,192 @@
+package mjml
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+
+	"github.com/preslavrachev/gomjml/mjml/components"
+	"github.com/preslavrachev/gomjml/mjml/constants"
+	"github.com/preslavrachev/gomjml/mjml/styles"
+)
+
+// minContrastRatio is the WCAG 2.1 AA threshold for normal-size text.
+const minContrastRatio = 4.5
+
+var (
+	a11yTableTag = regexp.MustCompile(`<table\b[^>]*>`)
+	a11yImgTag   = regexp.MustCompile(`<img\b[^>]*>`)
+	a11yRoleAttr = regexp.MustCompile(`\srole="`)
+	a11yAltAttr  = regexp.MustCompile(`\salt="`)
+)
+
+// WithAccessibilityChecks reports accessibility problems in the rendered email:
+// mj-image and mj-carousel-image elements without alt text, tables without
+// role="presentation" and images without an alt attribute in embedded HTML, a
+// missing lang attribute on the mjml root, and text or button colors whose
+// contrast against their background is below the WCAG AA ratio of 4.5:1.
+// Problems are returned as an Error alongside the rendered HTML, like attribute
+// validation errors.
+func WithAccessibilityChecks() RenderOption {
+	return func(opts *RenderOpts) {
+		opts.AccessibilityChecks = true
+	}
+}
+
+// WithAccessibilityAutoFix enables WithAccessibilityChecks and repairs what can
+// be repaired mechanically: role="presentation" is added to tables without a
+// role and alt="" to images without alt text. Only the remaining problems,
+// such as a missing lang or low contrast, are reported.
+func WithAccessibilityAutoFix() RenderOption {
+	return func(opts *RenderOpts) {
+		opts.AccessibilityChecks = true
+		opts.AccessibilityAutoFix = true
+	}
+}
+
+// ErrAccessibility returns an Error describing a single accessibility problem.
+func ErrAccessibility(tagName, message string, line int) *Error {
+	return &Error{
+		Message: "MJML accessibility issues",
+		Details: []ErrorDetail{{Line: line, Message: message, TagName: tagName}},
+	}
+}
+
+// checkAccessibility inspects the component tree and the rendered document
+// and returns the document, repaired in auto-fix mode, with any problems found.
+func checkAccessibility(comp Component, document string, opts *RenderOpts) (string, *Error) {
+	if !opts.AccessibilityChecks {
+		return document, nil
+	}
+
+	var issues *Error
+	report := func(tagName, message string, line int) {
+		if issues == nil {
+			issues = ErrAccessibility(tagName, message, line)
+		} else {
+			issues.Append(ErrAccessibility(tagName, message, line))
+		}
+	}
+
+	if root, ok := comp.(*MJMLComponent); ok && root.Node.GetAttribute("lang") == "" && opts.OverrideLang == "" {
+		report("mjml", `missing lang attribute; screen readers need it to pick a pronunciation`, root.Node.GetLineNumber())
+	}
+
+	checkComponentAccessibility(comp, "#ffffff", opts.AccessibilityAutoFix, report)
+
+	document = a11yTableTag.ReplaceAllStringFunc(document, func(tag string) string {
+		if a11yRoleAttr.MatchString(tag) {
+			return tag
+		}
+		if opts.AccessibilityAutoFix {
+			return `<table role="presentation"` + tag[len("<table"):]
+		}
+		report("table", `layout table without role="presentation"`, 0)
+		return tag
+	})
+	document = a11yImgTag.ReplaceAllStringFunc(document, func(tag string) string {
+		if a11yAltAttr.MatchString(tag) {
+			return tag
+		}
+		if opts.AccessibilityAutoFix {
+			return `<img alt=""` + tag[len("<img"):]
+		}
+		report("img", "image without alt attribute", 0)
+		return tag
+	})
+
+	return document, issues
+}
+
+// attributeResolver is implemented by every component through BaseComponent.
+type attributeResolver interface {
+	GetAttributeWithDefault(comp Component, name string) string
+}
+
+// checkComponentAccessibility walks comp and its descendants. background is the
+// nearest background color painted behind comp, used for contrast checks.
+func checkComponentAccessibility(comp Component, background string, autoFix bool, report func(tagName, message string, line int)) {
+	resolver, ok := comp.(attributeResolver)
+	if !ok {
+		return
+	}
+	attr := func(name string) string {
+		return resolver.GetAttributeWithDefault(comp, name)
+	}
+
+	switch v := comp.(type) {
+	case *components.MJBodyComponent, *components.MJWrapperComponent, *components.MJSectionComponent,
+		*components.MJColumnComponent, *components.MJHeroComponent:
+		if bg := attr(constants.MJMLBackgroundColor); bg != "" {
+			background = bg
+		}
+	case *components.MJTextComponent:
+		if bg := attr(constants.MJMLContainerBackgroundColor); bg != "" {
+			background = bg
+		}
+		checkContrast(v.GetTagName(), attr(constants.MJMLColor), background, v.Node.GetLineNumber(), report)
+	case *components.MJButtonComponent:
+		checkContrast(v.GetTagName(), attr(constants.MJMLColor), attr(constants.MJMLBackgroundColor), v.Node.GetLineNumber(), report)
+	case *components.MJImageComponent:
+		if !autoFix && !hasAltText(v.BaseComponent, v.GetTagName()) {
+			report(v.GetTagName(), `missing alt attribute; use alt="" for decorative images`, v.Node.GetLineNumber())
+		}
+	case *components.MJCarouselImageComponent:
+		if !autoFix && !hasAltText(v.BaseComponent, v.GetTagName()) {
+			report(v.GetTagName(), `missing alt attribute; use alt="" for decorative images`, v.Node.GetLineNumber())
+		}
+	}
+
+	for _, child := range componentChildren(comp) {
+		checkComponentAccessibility(child, background, autoFix, report)
+	}
+}
+
+// hasAltText reports whether alt was set explicitly, even to "", on the element,
+// through mj-class or through mj-attributes.
+func hasAltText(bc *components.BaseComponent, tagName string) bool {
+	if _, ok := bc.Attrs[constants.MJMLAlt]; ok {
+		return true
+	}
+	return bc.GetClassAttribute(constants.MJMLAlt) != "" || bc.GlobalAttributes().GetGlobalAttribute(tagName, constants.MJMLAlt) != ""
+}
+
+func checkContrast(tagName, foreground, background string, line int, report func(tagName, message string, line int)) {
+	ratio, ok := contrastRatio(foreground, background)
+	if !ok || ratio >= minContrastRatio {
+		return
+	}
+	report(tagName, fmt.Sprintf("low contrast between %s and %s (%.2f:1, at least %.1f:1 required)",
+		foreground, background, ratio, minContrastRatio), line)
+}
+
+// contrastRatio returns the WCAG contrast ratio of two hex colors. ok is false
+// when either color is not a hex color, since other formats cannot be compared.
+func contrastRatio(foreground, background string) (ratio float64, ok bool) {
+	fg, ok := relativeLuminance(foreground)
+	if !ok {
+		return 0, false
+	}
+	bg, ok := relativeLuminance(background)
+	if !ok {
+		return 0, false
+	}
+	lighter, darker := math.Max(fg, bg), math.Min(fg, bg)
+	return (lighter + 0.05) / (darker + 0.05), true
+}
+
+func relativeLuminance(color string) (float64, bool) {
+	r, g, b, ok := styles.Color{Value: color}.RGB()
+	if !ok {
+		return 0, false
+	}
+
+	channel := func(value uint8) float64 {
+		c := float64(value) / 255
+		if c <= 0.03928 {
+			return c / 12.92
+		}
+		return math.Pow((c+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(r) + 0.7152*channel(g) + 0.0722*channel(b), true
+}
@@ -0,0 +1,90 @@
+package mjml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const accessibilityDoc = `<mjml>
+<mj-body>
+<mj-section background-color="#ffffff">
+<mj-column>
+<mj-image src="https://example.com/logo.png" />
+<mj-image src="https://example.com/spacer.png" alt="" />
+<mj-text color="#cccccc">Hard to read</mj-text>
+<mj-text color="#333333">Easy to read</mj-text>
+<mj-button background-color="#ffff00" color="#ffffff">Pale</mj-button>
+<mj-raw><table><tr><td><img src="https://example.com/raw.png"></td></tr></table></mj-raw>
+</mj-column>
+</mj-section>
+</mj-body>
+</mjml>`
+
+func accessibilityMessages(t *testing.T, err error) []string {
+	t.Helper()
+	var mjmlErr Error
+	if !errors.As(err, &mjmlErr) {
+		t.Fatalf("expected an mjml Error, got %v", err)
+	}
+	var messages []string
+	for _, detail := range mjmlErr.Details {
+		messages = append(messages, detail.TagName+": "+detail.Message)
+	}
+	return messages
+}
+
+func TestAccessibilityChecks(t *testing.T) {
+	html, err := Render(accessibilityDoc, WithAccessibilityChecks())
+	if html == "" {
+		t.Fatal("expected rendered HTML alongside the diagnostics")
+	}
+	got := strings.Join(accessibilityMessages(t, err), "\n")
+
+	for _, want := range []string{
+		"mjml: missing lang attribute",
+		`mj-image: missing alt attribute; use alt="" for decorative images`,
+		"mj-text: low contrast between #cccccc and #ffffff (1.61:1, at least 4.5:1 required)",
+		"mj-button: low contrast between #ffffff and #ffff00",
+		`table: layout table without role="presentation"`,
+		"img: image without alt attribute",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected diagnostic %q in\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "mj-image:"); n != 1 {
+		t.Errorf("expected only the image without alt to be reported, got %d", n)
+	}
+	if strings.Contains(got, "#333333") {
+		t.Error("did not expect a contrast diagnostic for readable text")
+	}
+}
+
+func TestAccessibilityAutoFix(t *testing.T) {
+	html, err := Render(accessibilityDoc, WithAccessibilityAutoFix())
+	if !strings.Contains(html, `<table role="presentation"><tr><td><img alt="" src="https://example.com/raw.png">`) {
+		t.Error("expected role and alt to be injected into embedded HTML")
+	}
+	got := strings.Join(accessibilityMessages(t, err), "\n")
+	for _, notWant := range []string{"mj-image:", "table:", "img:"} {
+		if strings.Contains(got, notWant) {
+			t.Errorf("did not expect %q diagnostics after auto-fix:\n%s", notWant, got)
+		}
+	}
+	if !strings.Contains(got, "mjml: missing lang attribute") || !strings.Contains(got, "low contrast") {
+		t.Errorf("expected unfixable problems to be reported:\n%s", got)
+	}
+}
+
+func TestAccessibilityChecksCleanDocument(t *testing.T) {
+	const doc = `<mjml lang="en"><mj-body><mj-section><mj-column>
+<mj-image src="https://example.com/logo.png" alt="Logo" />
+<mj-text>Hello</mj-text>
+<mj-button href="#">Go</mj-button>
+</mj-column></mj-section></mj-body></mjml>`
+
+	if _, err := Render(doc, WithAccessibilityChecks()); err != nil {
+		t.Fatalf("expected no diagnostics, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestAccordionNestedContent verifies that markup inside mj-accordion-title and
+// mj-accordion-text is emitted with its attributes rather than flattened.
+func TestAccordionNestedContent(t *testing.T) {
+	input := `<mjml>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-accordion>
+          <mj-accordion-element>
+            <mj-accordion-title>Why <b>use</b> it?</mj-accordion-title>
+            <mj-accordion-text>Read <a href="https://example.com/docs" style="color:#f45e43">the docs</a> first.<br/>Thanks!</mj-accordion-text>
+          </mj-accordion-element>
+        </mj-accordion>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if !strings.Contains(html, `Why <b>use</b> it?`) {
+		t.Error("expected nested markup in the accordion title")
+	}
+	if !strings.Contains(html, `Read <a href="https://example.com/docs" style="color:#f45e43">the docs</a> first.<br>Thanks!`) {
+		t.Error("expected nested link with attributes in the accordion text")
+	}
+}
+
+// TestAccordionAttributeInheritance verifies that icon settings resolve per element
+// before falling back to the accordion, and that title/text styles honour mj-attributes.
+func TestAccordionAttributeInheritance(t *testing.T) {
+	input := `<mjml>
+  <mj-head>
+    <mj-attributes>
+      <mj-accordion-title font-family="Georgia, serif" padding="12px" />
+      <mj-accordion-text background-color="#fafafa" />
+    </mj-attributes>
+  </mj-head>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-accordion icon-wrapped-url="https://example.com/parent-plus.png">
+          <mj-accordion-element icon-unwrapped-url="https://example.com/minus.png">
+            <mj-accordion-title>First</mj-accordion-title>
+            <mj-accordion-text>One</mj-accordion-text>
+          </mj-accordion-element>
+          <mj-accordion-element icon-wrapped-url="https://example.com/plus.png">
+            <mj-accordion-title>Second</mj-accordion-title>
+            <mj-accordion-text>Two</mj-accordion-text>
+          </mj-accordion-element>
+        </mj-accordion>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	for _, src := range []string{
+		`src="https://example.com/parent-plus.png"`,
+		`src="https://example.com/minus.png"`,
+		`src="https://example.com/plus.png"`,
+	} {
+		if !strings.Contains(html, src) {
+			t.Errorf("expected icon %s", src)
+		}
+	}
+	if got := strings.Count(html, `src="https://example.com/parent-plus.png"`); got != 1 {
+		t.Errorf("expected the accordion icon only on the element without an override, got %d", got)
+	}
+	if got := strings.Count(html, `font-family:Georgia, serif`); got != 2 {
+		t.Errorf("expected mj-attributes font-family on both titles, got %d", got)
+	}
+	if !strings.Contains(html, `padding:12px`) {
+		t.Error("expected mj-attributes padding on the accordion titles")
+	}
+	if got := strings.Count(html, `background:#fafafa`); got != 2 {
+		t.Errorf("expected mj-attributes background-color on both texts, got %d", got)
+	}
+}
@@ -0,0 +1,190 @@
+package mjml
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml/components"
+	"github.com/preslavrachev/gomjml/mjml/options"
+	"github.com/preslavrachev/gomjml/mjml/styles"
+)
+
+// OutputFormat is an alias for convenience
+type OutputFormat = options.OutputFormat
+
+// Supported output formats
+const (
+	FormatHTML = options.FormatHTML
+	FormatAMP  = options.FormatAMP
+)
+
+// ampRuntimeScript and ampBoilerplate are required in the head of every AMP email.
+const (
+	ampRuntimeScript = `<script async src="https://cdn.ampproject.org/v0.js"></script>`
+	ampBoilerplate   = `<style amp4email-boilerplate>body{visibility:hidden}</style>`
+)
+
+var (
+	// Downlevel-revealed markers (<!--[if !mso]><!--> ... <!--<![endif]-->) keep their content.
+	ampRevealedOpen  = regexp.MustCompile(`<!--\[if [^\]]*\]><!-->`)
+	ampRevealedClose = regexp.MustCompile(`<!--<!\[endif\]-->`)
+	// Downlevel-hidden blocks only target Outlook and are removed entirely.
+	msoHiddenBlock    = regexp.MustCompile(`(?s)<!--\[if [^\]]*\]>.*?<!\[endif\]-->`)
+	ampHTMLOpenTag    = regexp.MustCompile(`<html[^>]*>`)
+	ampHeadSection    = regexp.MustCompile(`(?s)<head>(.*?)</head>`)
+	ampStyleTag       = regexp.MustCompile(`(?s)<style[^>]*>(.*?)</style>`)
+	ampStylesheetLink = regexp.MustCompile(`<link[^>]*rel="stylesheet"[^>]*>`)
+	ampHTTPEquivMeta  = regexp.MustCompile(`<meta http-equiv="[^"]*" content="[^"]*">`)
+	ampCSSImport      = regexp.MustCompile(`@import url\([^)]*\);?`)
+	ampImportant      = regexp.MustCompile(`\s*!\s*important`)
+	ampOpenTag        = regexp.MustCompile(`<[a-zA-Z][^>]*>`)
+	ampStyleAttribute = regexp.MustCompile(`(?i)(\sstyle=)("[^"]*"|'[^']*')`)
+	ampImgTag         = regexp.MustCompile(`<img\b([^>]*?)\s*/?>`)
+	ampTagAttribute   = regexp.MustCompile(`([a-zA-Z_:][-a-zA-Z0-9_:.]*)="([^"]*)"`)
+)
+
+// WithOutputFormat selects the output markup dialect. FormatAMP produces
+// AMP for Email markup: amp-img instead of img, the AMP runtime and boilerplate
+// in the head, a single amp-custom stylesheet, and no MSO conditionals.
+// Rendering fails when the document uses components that AMP cannot express.
+func WithOutputFormat(format OutputFormat) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.OutputFormat = format
+	}
+}
+
+// validateAMPComponents reports components that rely on interactive form controls
+// or other constructs disallowed by the AMP for Email specification.
+func validateAMPComponents(comp Component) *Error {
+	var validationErr *Error
+	report := func(tagName string, line int, reason string) {
+		detail := &Error{
+			Message: "AMP validation error",
+			Details: []ErrorDetail{{
+				Line:    line,
+				Message: "<" + tagName + "> is not supported in AMP output: " + reason,
+				TagName: tagName,
+			}},
+		}
+		if validationErr == nil {
+			validationErr = detail
+		} else {
+			validationErr.Append(detail)
+		}
+	}
+
+	var walk func(Component)
+	walk = func(c Component) {
+		switch v := c.(type) {
+		case *components.MJCarouselComponent:
+			report(v.GetTagName(), v.Node.GetLineNumber(), "radio inputs are not allowed")
+			return
+		case *components.MJAccordionComponent:
+			report(v.GetTagName(), v.Node.GetLineNumber(), "checkbox inputs are not allowed")
+			return
+		case *components.MJNavbarComponent:
+			if v.GetAttributeWithDefault(v, "hamburger") == "hamburger" {
+				report(v.GetTagName(), v.Node.GetLineNumber(), "the hamburger menu requires checkbox inputs")
+				return
+			}
+		}
+		for _, child := range componentChildren(c) {
+			walk(child)
+		}
+	}
+	walk(comp)
+
+	return validationErr
+}
+
+// convertToAMP rewrites a rendered email document into AMP for Email markup.
+func convertToAMP(document string) string {
+	document = ampRevealedOpen.ReplaceAllString(document, "")
+	document = ampRevealedClose.ReplaceAllString(document, "")
+	document = msoHiddenBlock.ReplaceAllString(document, "")
+
+	document = ampHTMLOpenTag.ReplaceAllStringFunc(document, func(tag string) string {
+		var b strings.Builder
+		b.WriteString(`<html ⚡4email data-css-strict`)
+		for _, attr := range ampTagAttribute.FindAllStringSubmatch(tag, -1) {
+			if attr[1] == "lang" || attr[1] == "dir" {
+				b.WriteString(" " + attr[1] + `="` + attr[2] + `"`)
+			}
+		}
+		b.WriteString(">")
+		return b.String()
+	})
+
+	document = ampHeadSection.ReplaceAllStringFunc(document, func(head string) string {
+		inner := ampHeadSection.FindStringSubmatch(head)[1]
+
+		var css strings.Builder
+		for _, style := range ampStyleTag.FindAllStringSubmatch(inner, -1) {
+			css.WriteString(strings.TrimSpace(style[1]))
+		}
+		inner = ampStyleTag.ReplaceAllString(inner, "")
+		inner = ampStylesheetLink.ReplaceAllString(inner, "")
+		inner = ampHTTPEquivMeta.ReplaceAllString(inner, "")
+
+		customCSS := ampCSSImport.ReplaceAllString(css.String(), "")
+		customCSS = ampImportant.ReplaceAllString(customCSS, "")
+
+		var b strings.Builder
+		b.WriteString(`<head><meta charset="utf-8">`)
+		b.WriteString(ampRuntimeScript)
+		b.WriteString(ampBoilerplate)
+		b.WriteString(inner)
+		if customCSS != "" {
+			b.WriteString(`<style amp-custom>`)
+			b.WriteString(customCSS)
+			b.WriteString(`</style>`)
+		}
+		b.WriteString(`</head>`)
+		return b.String()
+	})
+
+	// AMP rejects !important in CSS; text content keeps it as written
+	document = ampOpenTag.ReplaceAllStringFunc(document, func(tag string) string {
+		return ampStyleAttribute.ReplaceAllStringFunc(tag, func(attr string) string {
+			return ampImportant.ReplaceAllString(attr, "")
+		})
+	})
+	document = ampImgTag.ReplaceAllStringFunc(document, convertImgToAMP)
+
+	return document
+}
+
+// convertImgToAMP turns an <img> tag into an <amp-img> element. Images with numeric
+// dimensions use the intrinsic layout; anything else falls back to flex-item,
+// which does not require explicit dimensions.
+func convertImgToAMP(tag string) string {
+	var (
+		b             strings.Builder
+		width, height int
+	)
+	b.WriteString("<amp-img")
+	for _, attr := range ampTagAttribute.FindAllStringSubmatch(tag, -1) {
+		name, value := attr[1], attr[2]
+		switch name {
+		case "width":
+			width, _ = strconv.Atoi(styles.HTMLDimension(value))
+			if width <= 0 {
+				continue
+			}
+		case "height":
+			height, _ = strconv.Atoi(styles.HTMLDimension(value))
+			if height <= 0 {
+				continue
+			}
+		}
+		b.WriteString(" " + name + `="` + value + `"`)
+	}
+	if width > 0 && height > 0 {
+		b.WriteString(` layout="intrinsic"`)
+	} else {
+		b.WriteString(` layout="flex-item"`)
+	}
+	b.WriteString("></amp-img>")
+	return b.String()
+}
@@ -0,0 +1,117 @@
+package mjml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRenderAMPOutput(t *testing.T) {
+	input := `<mjml>
+  <mj-head><mj-title>AMP</mj-title></mj-head>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-image src="https://example.com/a.png" width="100px" height="50px" />
+        <mj-image src="https://example.com/b.png" />
+        <mj-text>Hello</mj-text>
+        <mj-button href="https://example.com">Go</mj-button>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(input, WithOutputFormat(FormatAMP))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`<html ⚡4email data-css-strict lang="und" dir="auto">`,
+		`<head><meta charset="utf-8">` + ampRuntimeScript + ampBoilerplate,
+		`<style amp-custom>`,
+		`<amp-img alt="" height="50" src="https://example.com/a.png" width="100"`,
+		`layout="intrinsic"></amp-img>`,
+		`layout="flex-item"></amp-img>`,
+		"Hello",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("AMP output should contain %q", want)
+		}
+	}
+
+	for _, forbidden := range []string{"<img", "[if mso", "<![endif]", "!important", "xmlns:v", "http-equiv", "@import", "<link"} {
+		if strings.Contains(html, forbidden) {
+			t.Errorf("AMP output should not contain %q", forbidden)
+		}
+	}
+
+	if n := strings.Count(html, "<style"); n != 2 {
+		t.Errorf("expected boilerplate and amp-custom style tags only, got %d style tags", n)
+	}
+}
+
+func TestRenderAMPImportantOnlyInCSS(t *testing.T) {
+	input := `<mjml>
+  <mj-head><mj-style>.note { color: red !important; }</mj-style></mj-head>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-text>Use <code>!important</code> sparingly. <span style="color: blue !important">Blue</span></mj-text>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(input, WithOutputFormat(FormatAMP))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{".note { color: red; }", `<span style="color: blue">`, "<code>!important</code>"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("AMP output should contain %q", want)
+		}
+	}
+	if n := strings.Count(html, "!important"); n != 1 {
+		t.Errorf("expected !important only in the text content, found it %d times", n)
+	}
+}
+
+func TestRenderAMPUnsupportedComponents(t *testing.T) {
+	input := `<mjml>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-carousel>
+          <mj-carousel-image src="https://example.com/a.png" />
+        </mj-carousel>
+        <mj-accordion>
+          <mj-accordion-element>
+            <mj-accordion-title>Q</mj-accordion-title>
+            <mj-accordion-text>A</mj-accordion-text>
+          </mj-accordion-element>
+        </mj-accordion>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(input, WithOutputFormat(FormatAMP))
+	if html != "" {
+		t.Errorf("expected no output for invalid AMP document")
+	}
+	var mjmlErr Error
+	if !errors.As(err, &mjmlErr) {
+		t.Fatalf("expected mjml.Error, got %v", err)
+	}
+	if len(mjmlErr.Details) != 2 {
+		t.Fatalf("expected 2 validation details, got %d: %v", len(mjmlErr.Details), err)
+	}
+	if mjmlErr.Details[0].TagName != "mj-carousel" || mjmlErr.Details[1].TagName != "mj-accordion" {
+		t.Errorf("unexpected validation details: %v", err)
+	}
+
+	if _, err := Render(input); err != nil {
+		t.Errorf("classic HTML rendering should still succeed: %v", err)
+	}
+}
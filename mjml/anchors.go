@@ -0,0 +1,135 @@
+package mjml
+
+import (
+	"encoding/xml"
+	"html"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/parser"
+)
+
+// anchorTag and tocTag are gomjml extension elements for linking to parts of
+// long emails, such as the stories of a digest newsletter.
+//
+//	<mj-anchor name="events" label="Upcoming events" />
+//	<mj-section>...</mj-section>
+//
+// mj-anchor marks a jump target with an empty <a name id> element, which
+// clients without id support still follow. mj-toc, used where an mj-text
+// could be, renders a list of links to every named mj-anchor in the document,
+// labelled with their label attribute or else their name. Its attributes are
+// those of mj-text. Both are resolved before the component tree is built, into
+// mj-raw and mj-text, so they never reach the output themselves.
+const (
+	anchorTag = "mj-anchor"
+	tocTag    = "mj-toc"
+)
+
+// expandExtensions resolves the mj-include elements of WithIncludes, the
+// gomjml extension elements of node and the relative URLs of WithBaseURL.
+// Includes are expanded first, so the other steps see the included content,
+// and mj-cond before anchors, so that anchors in excluded content are not
+// listed.
+func expandExtensions(node *MJMLNode, opts *RenderOpts) (*MJMLNode, error) {
+	node, err := expandIncludes(node, opts)
+	if err != nil {
+		return nil, err
+	}
+	return resolveBaseURL(expandAnchors(expandConditionals(node, opts.Variables)), opts.BaseURL), nil
+}
+
+// expandAnchors returns node with every mj-anchor replaced by an mj-raw holding
+// the anchor and every mj-toc by an mj-text listing the anchors. Like
+// expandConditionals, it copies the nodes it changes instead of modifying
+// them; subtrees without either element are returned as is.
+func expandAnchors(node *MJMLNode) *MJMLNode {
+	if node == nil || !containsAnchors(node) {
+		return node
+	}
+	return replaceAnchors(node, collectAnchors(node, nil))
+}
+
+func replaceAnchors(node *MJMLNode, anchors []*MJMLNode) *MJMLNode {
+	if !containsAnchors(node) {
+		return node
+	}
+
+	replace := func(child *MJMLNode) *MJMLNode {
+		switch child.GetTagName() {
+		case anchorTag:
+			return anchorNode(child)
+		case tocTag:
+			return tocNode(child, anchors)
+		}
+		return replaceAnchors(child, anchors)
+	}
+
+	expanded := *node
+	expanded.Children = make([]*MJMLNode, 0, len(node.Children))
+	for _, child := range node.Children {
+		expanded.Children = append(expanded.Children, replace(child))
+	}
+	expanded.MixedContent = make([]parser.MixedContentPart, len(node.MixedContent))
+	for i, part := range node.MixedContent {
+		if part.Node != nil {
+			part.Node = replace(part.Node)
+		}
+		expanded.MixedContent[i] = part
+	}
+	return &expanded
+}
+
+// anchorNode returns the mj-raw an mj-anchor element is replaced by. An anchor
+// without a name has nothing to link to and renders nothing.
+func anchorNode(anchor *MJMLNode) *MJMLNode {
+	raw := &MJMLNode{XMLName: xml.Name{Local: "mj-raw"}, LineNumber: anchor.LineNumber}
+	if name := anchor.GetAttribute("name"); name != "" {
+		escaped := html.EscapeString(name)
+		raw.Text = `<a name="` + escaped + `" id="` + escaped + `"></a>`
+	}
+	return raw
+}
+
+// tocNode returns the mj-text an mj-toc element is replaced by, keeping its
+// attributes, with one line per anchor.
+func tocNode(toc *MJMLNode, anchors []*MJMLNode) *MJMLNode {
+	links := make([]string, 0, len(anchors))
+	for _, anchor := range anchors {
+		name := anchor.GetAttribute("name")
+		label := anchor.GetAttribute("label")
+		if label == "" {
+			label = name
+		}
+		links = append(links, `<a href="#`+html.EscapeString(name)+`">`+html.EscapeString(label)+`</a>`)
+	}
+	return &MJMLNode{
+		XMLName:    xml.Name{Local: "mj-text"},
+		Attrs:      toc.Attrs,
+		Text:       strings.Join(links, "<br>"),
+		LineNumber: toc.LineNumber,
+	}
+}
+
+// collectAnchors appends the named mj-anchor elements of node to anchors, in
+// document order.
+func collectAnchors(node *MJMLNode, anchors []*MJMLNode) []*MJMLNode {
+	for _, child := range node.Children {
+		if child.GetTagName() == anchorTag {
+			if child.GetAttribute("name") != "" {
+				anchors = append(anchors, child)
+			}
+			continue
+		}
+		anchors = collectAnchors(child, anchors)
+	}
+	return anchors
+}
+
+func containsAnchors(node *MJMLNode) bool {
+	for _, child := range node.Children {
+		if tag := child.GetTagName(); tag == anchorTag || tag == tocTag || containsAnchors(child) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+const anchorsInput = `<mjml>
+  <mj-body>
+    <mj-section><mj-column>
+      <mj-toc font-size="16px" />
+    </mj-column></mj-section>
+    <mj-anchor name="news" label="News &amp; updates" />
+    <mj-section><mj-column><mj-text>News</mj-text></mj-column></mj-section>
+    <mj-cond test="premium">
+      <mj-anchor name="offers" label="Member offers" />
+      <mj-section><mj-column><mj-text>Offers</mj-text></mj-column></mj-section>
+    </mj-cond>
+    <mj-anchor name="events" />
+    <mj-section><mj-column><mj-text>Events</mj-text></mj-column></mj-section>
+  </mj-body>
+</mjml>`
+
+func TestAnchorsAndTableOfContents(t *testing.T) {
+	html, err := Render(anchorsInput)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`font-size:16px;line-height:1;text-align:left;color:#000000;"><a href="#news">News &amp; updates</a><br><a href="#events">events</a></div>`,
+		`<a name="news" id="news"></a>`,
+		`<a name="events" id="events"></a>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+	if strings.Contains(html, "offers") || strings.Contains(html, "mj-anchor") || strings.Contains(html, "mj-toc") {
+		t.Error("expected excluded anchors and the extension tags to be left out")
+	}
+	if strings.Index(html, `<a name="news"`) > strings.Index(html, ">News</div>") {
+		t.Error("expected the anchor before its section")
+	}
+
+	premium, err := Render(anchorsInput, WithVariables(map[string]any{"premium": true}))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(premium, `<br><a href="#offers">Member offers</a><br>`) || !strings.Contains(premium, `<a name="offers" id="offers"></a>`) {
+		t.Error("expected the anchor of included content to be listed")
+	}
+}
+
+func TestAnchorsLeaveDocumentsWithoutThemUnchanged(t *testing.T) {
+	ast, err := ParseMJML(`<mjml><mj-body><mj-section><mj-column><mj-text>Hi</mj-text></mj-column></mj-section></mj-body></mjml>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expandAnchors(ast) != ast {
+		t.Error("expected the AST to be returned as is")
+	}
+}
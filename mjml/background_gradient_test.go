@@ -0,0 +1,63 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestBackgroundGradients verifies that gradients render as background-image
+// with a plain fallback color and never reach Outlook's VML image fallback.
+func TestBackgroundGradients(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name: "section",
+			input: `<mjml><mj-body><mj-section background-color="linear-gradient(to right, #ff5f6d, #ffc371)">
+  <mj-column><mj-text>Hello</mj-text></mj-column>
+</mj-section></mj-body></mjml>`,
+		},
+		{
+			name: "full-width section",
+			input: `<mjml><mj-body><mj-section full-width="full-width" background-url="linear-gradient(to right, #ff5f6d, #ffc371)">
+  <mj-column><mj-text>Hello</mj-text></mj-column>
+</mj-section></mj-body></mjml>`,
+		},
+		{
+			name: "wrapper",
+			input: `<mjml><mj-body><mj-wrapper background-color="linear-gradient(to right, #ff5f6d, #ffc371)">
+  <mj-section><mj-column><mj-text>Hello</mj-text></mj-column></mj-section>
+</mj-wrapper></mj-body></mjml>`,
+		},
+		{
+			name: "hero",
+			input: `<mjml><mj-body><mj-hero background-color="linear-gradient(to right, #ff5f6d, #ffc371)" height="200px">
+  <mj-text>Hello</mj-text>
+</mj-hero></mj-body></mjml>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, err := Render(tt.input)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if !strings.Contains(html, "background-image:linear-gradient(to right, #ff5f6d, #ffc371)") {
+				t.Error("expected gradient as background-image")
+			}
+			if !strings.Contains(html, "#ff5f6d") || strings.Contains(html, "background:linear-gradient") {
+				t.Error("expected the first color stop as plain background fallback")
+			}
+			if strings.Contains(html, "url(linear-gradient") || strings.Contains(html, "url('linear-gradient") {
+				t.Error("gradient must not be wrapped in url()")
+			}
+			for _, vml := range []string{"<v:fill", "<v:image", `src="linear-gradient`} {
+				if strings.Contains(html, vml) {
+					t.Errorf("unexpected VML image fallback %q for a gradient", vml)
+				}
+			}
+		})
+	}
+}
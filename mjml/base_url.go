@@ -0,0 +1,114 @@
+package mjml
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml/styles"
+	"github.com/preslavrachev/gomjml/parser"
+)
+
+// WithBaseURL resolves relative URLs in the attributes of MJML elements, such
+// as the src of images and social icons, the href of buttons and the
+// background-url of sections and heroes, against baseURL. Templates can then
+// be written with relative asset paths and rendered for different
+// environments:
+//
+//	mjml.Render(source, mjml.WithBaseURL("https://cdn.example.com/newsletter/"))
+//
+// Attributes set in mj-attributes and mj-class are resolved too. Absolute
+// URLs, fragment links such as "#top", merge tags such as {{url}} and the
+// HTML content of mj-text and mj-raw are left as written, as are defaults from
+// WithDefaultAttributes. A baseURL that is not absolute is ignored.
+func WithBaseURL(baseURL string) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.BaseURL = baseURL
+	}
+}
+
+// resolveBaseURL returns node with the relative URLs of its link attributes
+// resolved against baseURL. Like expandConditionals, it copies the nodes it
+// changes instead of modifying them, as the AST may be cached.
+func resolveBaseURL(node *MJMLNode, baseURL string) *MJMLNode {
+	if node == nil || baseURL == "" {
+		return node
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil || !base.IsAbs() {
+		return node
+	}
+	return resolveNodeURLs(node, base)
+}
+
+func resolveNodeURLs(node *MJMLNode, base *url.URL) *MJMLNode {
+	var attrs []xml.Attr
+	for i, attr := range node.Attrs {
+		if _, ok := linkAttributes[attr.Name.Local]; !ok {
+			continue
+		}
+		resolved, ok := resolveURL(base, attr.Value)
+		if !ok {
+			continue
+		}
+		if attrs == nil {
+			attrs = append([]xml.Attr(nil), node.Attrs...)
+		}
+		attrs[i].Value = resolved
+	}
+
+	var replaced map[*MJMLNode]*MJMLNode
+	for _, child := range node.Children {
+		if resolved := resolveNodeURLs(child, base); resolved != child {
+			if replaced == nil {
+				replaced = make(map[*MJMLNode]*MJMLNode)
+			}
+			replaced[child] = resolved
+		}
+	}
+
+	if attrs == nil && replaced == nil {
+		return node
+	}
+	resolved := *node
+	if attrs != nil {
+		resolved.Attrs = attrs
+	}
+	if replaced != nil {
+		resolved.Children = make([]*MJMLNode, len(node.Children))
+		for i, child := range node.Children {
+			if r, ok := replaced[child]; ok {
+				child = r
+			}
+			resolved.Children[i] = child
+		}
+		resolved.MixedContent = make([]parser.MixedContentPart, len(node.MixedContent))
+		for i, part := range node.MixedContent {
+			if r, ok := replaced[part.Node]; ok {
+				part.Node = r
+			}
+			resolved.MixedContent[i] = part
+		}
+	}
+	return &resolved
+}
+
+// resolveURL returns value resolved against base, and false when value is
+// left as written.
+func resolveURL(base *url.URL, value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.HasPrefix(value, "#") || isMergeTag(value) || styles.IsGradient(value) {
+		return "", false
+	}
+	ref, err := url.Parse(value)
+	if err != nil || ref.IsAbs() {
+		return "", false
+	}
+	return base.ResolveReference(ref).String(), true
+}
+
+// isMergeTag reports whether value holds a placeholder an ESP or templating
+// engine fills in after rendering, such as {{url}}, *|UNSUB|* or %%view_url%%.
+func isMergeTag(value string) bool {
+	return strings.Contains(value, "{{") || strings.Contains(value, "*|") || strings.Contains(value, "%%")
+}
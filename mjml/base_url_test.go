@@ -0,0 +1,64 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBaseURL(t *testing.T) {
+	input := `<mjml>
+  <mj-head>
+    <mj-attributes>
+      <mj-class name="hero-bg" background-url="img/hero.jpg" />
+    </mj-attributes>
+  </mj-head>
+  <mj-body>
+    <mj-section mj-class="hero-bg">
+      <mj-column>
+        <mj-image src="img/logo.png" href="/" />
+        <mj-button href="../offers?id=1">Shop</mj-button>
+        <mj-button href="https://example.org/absolute">Absolute</mj-button>
+        <mj-button href="#top">Top</mj-button>
+        <mj-button href="{{unsubscribe_url}}">Unsubscribe</mj-button>
+        <mj-text><a href="inline.html">Inline</a></mj-text>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	resetASTCache()
+	defer resetASTCache()
+
+	html, err := Render(input, WithCache(), WithBaseURL("https://cdn.example.com/news/"))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`src="https://cdn.example.com/news/img/logo.png"`,
+		`href="https://cdn.example.com/"`,
+		`href="https://cdn.example.com/offers?id=1"`,
+		`url('https://cdn.example.com/news/img/hero.jpg')`,
+		`href="https://example.org/absolute"`,
+		`href="#top"`,
+		`href="{{unsubscribe_url}}"`,
+		`href="inline.html"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected output to contain %s", want)
+		}
+	}
+
+	// The cached AST is shared between renders and must not change
+	plain, err := Render(input, WithCache())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(plain, `src="img/logo.png"`) {
+		t.Error("expected WithBaseURL to leave the AST unchanged")
+	}
+
+	if ignored, err := Render(input, WithBaseURL("/relative/")); err != nil || !strings.Contains(ignored, `src="img/logo.png"`) {
+		t.Errorf("expected a relative base URL to be ignored, err = %v", err)
+	}
+}
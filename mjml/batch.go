@@ -0,0 +1,127 @@
+package mjml
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// BatchInput is one document of a RenderBatch call.
+type BatchInput struct {
+	ID      string         // Caller's identifier, copied to the result
+	MJML    string         // MJML source
+	Options []RenderOption // Options applied after those of the batch, such as per-recipient variables
+}
+
+// BatchResult is the outcome of rendering one BatchInput.
+type BatchResult struct {
+	ID       string
+	Result   *RenderResult // nil when rendering failed; set with Err for validation issues, as from RenderWithAST
+	Err      error
+	Duration time.Duration // Time spent rendering the document (0 if it was never started)
+}
+
+// BatchStats aggregates the timings of a batch.
+type BatchStats struct {
+	Rendered int           // Documents rendered without error
+	Failed   int           // Documents that failed or were never started
+	Total    time.Duration // Sum of the render times, across all workers
+	Mean     time.Duration // Mean render time of the documents that were started
+	Max      time.Duration // Longest render time
+}
+
+// WithBatchConcurrency limits RenderBatch to rendering n documents at once. By
+// default it renders GOMAXPROCS documents at once.
+func WithBatchConcurrency(n int) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.BatchConcurrency = n
+	}
+}
+
+// RenderBatch renders many documents on a pool of workers, with the shared AST
+// cache enabled so that a template sent to many recipients is parsed once.
+// opts apply to every document, followed by its own options.
+//
+// Results are in the order of inputs. A document that fails to render sets
+// the Err of its result and does not stop the others. When ctx is done, the
+// documents not yet started fail with ctx.Err(), which is also returned; the
+// results of those already rendered are kept.
+func RenderBatch(ctx context.Context, inputs []BatchInput, opts ...RenderOption) ([]BatchResult, error) {
+	batchOpts := &RenderOpts{}
+	for _, opt := range opts {
+		opt(batchOpts)
+	}
+	workers := batchOpts.BatchConcurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(inputs))
+
+	shared := append([]RenderOption{WithCache()}, opts...)
+	results := make([]BatchResult, len(inputs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = renderBatchInput(inputs[i], shared)
+			}
+		}()
+	}
+
+	next := 0
+feed:
+	for ; next < len(inputs) && ctx.Err() == nil; next++ {
+		select {
+		case indexes <- next:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if next < len(inputs) {
+		for i := next; i < len(inputs); i++ {
+			results[i] = BatchResult{ID: inputs[i].ID, Err: ctx.Err()}
+		}
+		return results, ctx.Err()
+	}
+	return results, nil
+}
+
+func renderBatchInput(input BatchInput, shared []RenderOption) BatchResult {
+	opts := shared
+	if len(input.Options) > 0 {
+		opts = append(append(make([]RenderOption, 0, len(shared)+len(input.Options)), shared...), input.Options...)
+	}
+
+	start := time.Now()
+	result, err := RenderWithAST(input.MJML, opts...)
+	return BatchResult{ID: input.ID, Result: result, Err: err, Duration: time.Since(start)}
+}
+
+// SummarizeBatch aggregates the outcomes and render times of results.
+func SummarizeBatch(results []BatchResult) BatchStats {
+	var stats BatchStats
+	started := 0
+	for _, r := range results {
+		if r.Err == nil {
+			stats.Rendered++
+		} else {
+			stats.Failed++
+		}
+		if r.Duration > 0 {
+			started++
+		}
+		stats.Total += r.Duration
+		stats.Max = max(stats.Max, r.Duration)
+	}
+	if started > 0 {
+		stats.Mean = stats.Total / time.Duration(started)
+	}
+	return stats
+}
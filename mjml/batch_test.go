@@ -0,0 +1,84 @@
+package mjml
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRenderBatch(t *testing.T) {
+	resetASTCache()
+	defer resetASTCache()
+
+	var calls int32
+	origParse := ParseMJML
+	ParseMJML = func(s string) (*MJMLNode, error) {
+		atomic.AddInt32(&calls, 1)
+		return origParse(s)
+	}
+	defer func() { ParseMJML = origParse }()
+
+	tpl := `<mjml><mj-body><mj-section><mj-column>` +
+		`<mj-cond test="vip"><mj-text>Welcome back</mj-text></mj-cond>` +
+		`<mj-text>Hello</mj-text></mj-column></mj-section></mj-body></mjml>`
+
+	inputs := []BatchInput{
+		{ID: "a", MJML: tpl},
+		{ID: "b", MJML: tpl, Options: []RenderOption{WithVariables(map[string]any{"vip": true})}},
+		{ID: "broken", MJML: `<mjml><mj-body>`},
+		{ID: "c", MJML: tpl},
+	}
+	results, err := RenderBatch(context.Background(), inputs, WithBatchConcurrency(2))
+	if err != nil {
+		t.Fatalf("RenderBatch failed: %v", err)
+	}
+	if len(results) != len(inputs) {
+		t.Fatalf("expected %d results, got %d", len(inputs), len(results))
+	}
+
+	for i, r := range results {
+		if r.ID != inputs[i].ID {
+			t.Errorf("result %d: expected ID %q, got %q", i, inputs[i].ID, r.ID)
+		}
+	}
+	if results[2].Err == nil {
+		t.Error("expected the broken document to fail")
+	}
+	for _, i := range []int{0, 1, 3} {
+		if results[i].Err != nil || results[i].Result == nil {
+			t.Fatalf("result %d: unexpected error %v", i, results[i].Err)
+		}
+	}
+	if strings.Contains(results[0].Result.HTML, "Welcome back") || !strings.Contains(results[1].Result.HTML, "Welcome back") {
+		t.Error("expected per-input options to apply to their document only")
+	}
+
+	// The template is parsed once and shared; the broken document is not cached
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("expected 2 parses, got %d", got)
+	}
+
+	stats := SummarizeBatch(results)
+	if stats.Rendered != 3 || stats.Failed != 1 {
+		t.Errorf("expected 3 rendered and 1 failed, got %+v", stats)
+	}
+	if stats.Total <= 0 || stats.Max <= 0 || stats.Mean <= 0 || stats.Max > stats.Total {
+		t.Errorf("unexpected timings: %+v", stats)
+	}
+}
+
+func TestRenderBatchCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tpl := `<mjml><mj-body><mj-section><mj-column><mj-text>Hi</mj-text></mj-column></mj-section></mj-body></mjml>`
+	results, err := RenderBatch(ctx, []BatchInput{{ID: "a", MJML: tpl}, {ID: "b", MJML: tpl}}, WithBatchConcurrency(1))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(results) != 2 || results[1].ID != "b" || !errors.Is(results[1].Err, context.Canceled) {
+		t.Errorf("expected documents not started to fail with the context error, got %+v", results)
+	}
+}
@@ -0,0 +1,55 @@
+package mjml
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestBodyAttributeSources verifies that the css-class, background-color and
+// width of mj-body resolve from the element, mj-class and mj-attributes alike,
+// and that a custom width reaches the Outlook tables of sections and wrappers.
+func TestBodyAttributeSources(t *testing.T) {
+	rootDiv := regexp.MustCompile(`<body[^>]*><div[^>]*>`)
+	tests := map[string]string{
+		"element":       `<mjml><mj-body width="480px" css-class="themed" background-color="#eeeeee">%s</mj-body></mjml>`,
+		"mj-class":      `<mjml><mj-head><mj-attributes><mj-class name="b" width="480px" css-class="themed" background-color="#eeeeee" /></mj-attributes></mj-head><mj-body mj-class="b">%s</mj-body></mjml>`,
+		"mj-attributes": `<mjml><mj-head><mj-attributes><mj-body width="480px" css-class="themed" background-color="#eeeeee" /></mj-attributes></mj-head><mj-body>%s</mj-body></mjml>`,
+	}
+	const content = `<mj-section><mj-column><mj-text>One</mj-text></mj-column></mj-section>` +
+		`<mj-wrapper padding="0px"><mj-section><mj-column><mj-text>Two</mj-text></mj-column></mj-section></mj-wrapper>`
+
+	for name, document := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := RenderWithAST(strings.Replace(document, "%s", content, 1))
+			if err != nil {
+				t.Fatalf("render failed: %v", err)
+			}
+			html := result.HTML
+			if result.BodyWidth != 480 {
+				t.Errorf("expected BodyWidth 480, got %d", result.BodyWidth)
+			}
+			if !strings.Contains(html, `<body style="word-spacing:normal;background-color:#eeeeee;">`) {
+				t.Error("expected the body background color on the body tag")
+			}
+			div := rootDiv.FindString(html)
+			if !strings.Contains(div, `class="themed"`) || !strings.Contains(div, "background-color:#eeeeee;") {
+				t.Errorf("expected the css-class and background on the outermost div, got %s", div)
+			}
+			if strings.Contains(html, "600px") || strings.Contains(html, `width="600"`) {
+				t.Error("expected no element sized to the default 600px body")
+			}
+			if got := strings.Count(html, `style="width:480px;" width="480"`); got < 3 {
+				t.Errorf("expected the section, wrapper and nested section Outlook tables at 480px, got %d", got)
+			}
+		})
+	}
+
+	result, err := RenderWithAST(`<mjml><mj-body><mj-section><mj-column><mj-text>Hi</mj-text></mj-column></mj-section></mj-body></mjml>`)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if result.BodyWidth != 600 {
+		t.Errorf("expected the default BodyWidth of 600, got %d", result.BodyWidth)
+	}
+}
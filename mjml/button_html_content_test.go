@@ -120,3 +120,33 @@ func TestButtonHTMLContentPreserved(t *testing.T) {
 		})
 	}
 }
+
+// TestButtonAttributeMatrix covers attributes that MJML applies to the button cell
+// and link beyond the basic fixtures: letter-spacing, text-align, name/title and the
+// link width, which subtracts asymmetric inner padding and border widths.
+func TestButtonAttributeMatrix(t *testing.T) {
+	input := `<mjml><mj-body><mj-section><mj-column>
+  <mj-button href="https://example.com" name="cta" title="Go now" width="200px"
+    border-left="2px solid #ff0000" inner-padding="10px 20px 10px 30px"
+    letter-spacing="2px" text-align="left"><img src="https://example.com/i.png" width="10" /> Go <span style="color:#ff0000">now</span></mj-button>
+  <mj-button width="50%">Percent</mj-button>
+</mj-column></mj-section></mj-body></mjml>`
+
+	html, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`mso-padding-alt:10px 20px 10px 30px;text-align:left;background:#414141;"`,
+		`<a href="https://example.com" name="cta" title="Go now" target="_blank"`,
+		`display:inline-block;width:148px;`,
+		`line-height:120%;letter-spacing:2px;margin:0;`,
+		`<img src="https://example.com/i.png" width="10" /> Go <span style="color:#ff0000">now</span></a>`,
+		`<p style="display:inline-block;background:#414141;`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s in output", want)
+		}
+	}
+}
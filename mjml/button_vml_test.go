@@ -0,0 +1,77 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOutlookVMLButtons(t *testing.T) {
+	const doc = `<mjml><mj-body><mj-section><mj-column>
+<mj-button href="https://example.com" width="200px" border-radius="10px" background-color="#ff6600" color="#ffffff">Buy now</mj-button>
+</mj-column></mj-section></mj-body></mjml>`
+
+	t.Run("disabled by default", func(t *testing.T) {
+		html, err := Render(doc)
+		if err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		if strings.Contains(html, "v:roundrect") {
+			t.Fatal("expected no VML button without WithOutlookVMLButtons")
+		}
+	})
+
+	t.Run("renders roundrect", func(t *testing.T) {
+		html, err := Render(doc, WithOutlookVMLButtons())
+		if err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		// 13px * 120% line box + 10px + 10px inner padding = 35px; 10/35 = 28%
+		for _, want := range []string{
+			`<!--[if mso | IE]><v:roundrect xmlns:v="urn:schemas-microsoft-com:vml"`,
+			`href="https://example.com" style="height:35px;v-text-anchor:middle;width:200px;" arcsize="28%" stroke="f" fillcolor="#ff6600"><w:anchorlock/>`,
+			`<center style="color:#ffffff;font-family:Ubuntu, Helvetica, Arial, sans-serif;font-size:13px;font-weight:normal;">Buy now</center></v:roundrect><![endif]--><!--[if !mso | IE]><!--><table`,
+			`</table><!--<![endif]--></td>`,
+		} {
+			if !strings.Contains(html, want) {
+				t.Errorf("expected output to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("explicit height and border", func(t *testing.T) {
+		const bordered = `<mjml><mj-body><mj-section><mj-column>
+<mj-button href="#" width="300px" height="60px" border="2px solid #000000" border-radius="40px">Go</mj-button>
+</mj-column></mj-section></mj-body></mjml>`
+		html, err := Render(bordered, WithOutlookVMLButtons())
+		if err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		if !strings.Contains(html, `style="height:60px;v-text-anchor:middle;width:300px;" arcsize="50%" strokecolor="#000000" strokeweight="2px"`) {
+			t.Error("expected explicit height, capped arcsize and stroke attributes")
+		}
+	})
+
+	t.Run("skipped without pixel width or href", func(t *testing.T) {
+		const plain = `<mjml><mj-body><mj-section><mj-column>
+<mj-button href="#">Auto width</mj-button>
+<mj-button width="200px">No link</mj-button>
+</mj-column></mj-section></mj-body></mjml>`
+		html, err := Render(plain, WithOutlookVMLButtons())
+		if err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		if strings.Contains(html, "v:roundrect") {
+			t.Fatal("expected the plain table fallback")
+		}
+	})
+
+	t.Run("stripped from AMP", func(t *testing.T) {
+		html, err := Render(doc, WithOutlookVMLButtons(), WithOutputFormat(FormatAMP))
+		if err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		if strings.Contains(html, "v:roundrect") || !strings.Contains(html, "Buy now") {
+			t.Fatal("expected AMP output to keep only the table button")
+		}
+	})
+}
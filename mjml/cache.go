@@ -0,0 +1,234 @@
+package mjml
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// ASTCacheStats is a snapshot of the AST cache counters and current size.
+type ASTCacheStats struct {
+	Hits       uint64 // Lookups served from the cache
+	Misses     uint64 // Lookups that required parsing (including expired entries)
+	Evictions  uint64 // Entries removed to stay within MaxEntries/MaxBytes
+	Entries    int    // Entries currently cached
+	Bytes      int64  // Estimated memory held by cached entries
+	MaxEntries int    // Entry limit (0 means unlimited)
+	MaxBytes   int64  // Size limit in bytes (0 means unlimited)
+}
+
+// CacheStats returns the current AST cache statistics.
+func CacheStats() ASTCacheStats {
+	return astCache.stats()
+}
+
+// SetASTCacheLimits bounds the AST cache by number of entries and by estimated
+// size in bytes. When either limit is exceeded, the least recently used
+// templates are evicted. A limit of zero or less disables that bound; both are
+// disabled by default. Unlike the TTL, limits may be changed at any time and
+// take effect immediately.
+func SetASTCacheLimits(maxEntries int, maxBytes int64) {
+	astCache.setLimits(maxEntries, maxBytes)
+}
+
+// cachedAST wraps an MJML AST with a fixed expiration time.
+// The AST is immutable once stored in the cache to avoid concurrent mutation;
+// only the output size, guarded by the store's mutex, changes as it is rendered.
+type cachedAST struct {
+	key        uint64
+	node       *MJMLNode
+	size       int64
+	expires    time.Time
+	outputSize int // Rolling average of the rendered output size in bytes (0 before the first render)
+}
+
+// astCacheStore is an LRU cache of parsed templates keyed by template hash.
+//
+// WHY a single mutex: every hit reorders the recency list, so reads are writes
+// as far as the LRU is concerned. Critical sections are a map lookup plus a
+// list splice, which keeps contention low compared to the cost of parsing.
+type astCacheStore struct {
+	mu         sync.Mutex
+	order      *list.List               // front is most recently used; values are *cachedAST
+	items      map[uint64]*list.Element // template hash -> element in order
+	bytes      int64
+	maxEntries int
+	maxBytes   int64
+	hits       uint64
+	misses     uint64
+	evictions  uint64
+}
+
+func newASTCacheStore() *astCacheStore {
+	return &astCacheStore{
+		order: list.New(),
+		items: make(map[uint64]*list.Element),
+	}
+}
+
+// get returns the cached AST for key if present and not expired at now.
+// Expired entries are removed and reported as misses.
+func (c *astCacheStore) get(key uint64, now time.Time) (*MJMLNode, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	entry := el.Value.(*cachedAST)
+	if !now.Before(entry.expires) {
+		c.removeElement(el)
+		c.misses++
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	c.hits++
+	return entry.node, true
+}
+
+// add stores node under key and evicts least recently used entries until the
+// cache is back within its limits.
+func (c *astCacheStore) add(key uint64, node *MJMLNode, size int64, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cachedAST{key: key, node: node, size: size, expires: expires}
+	if el, ok := c.items[key]; ok {
+		c.bytes -= el.Value.(*cachedAST).size
+		el.Value = entry
+		c.order.MoveToFront(el)
+	} else {
+		c.items[key] = c.order.PushFront(entry)
+	}
+	c.bytes += size
+	c.evictOverLimit()
+}
+
+// outputSizeHint returns the buffer size to pre-allocate for rendering the
+// template cached under key, learned from its earlier renders. It neither
+// counts as a lookup nor changes the entry's recency.
+func (c *astCacheStore) outputSizeHint(key uint64) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return 0, false
+	}
+	average := el.Value.(*cachedAST).outputSize
+	if average == 0 {
+		return 0, false
+	}
+	return outputSizeHint(average), true
+}
+
+// recordOutputSize folds the output size of a render into the rolling average
+// kept for the template cached under key. Templates no longer cached are
+// ignored, so the estimate lives and expires with the AST.
+func (c *astCacheStore) recordOutputSize(key uint64, size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*cachedAST)
+		entry.outputSize = rollingOutputSize(entry.outputSize, size)
+	}
+}
+
+// evictOverLimit must be called with c.mu held.
+func (c *astCacheStore) evictOverLimit() {
+	for c.order.Len() > 0 &&
+		((c.maxEntries > 0 && c.order.Len() > c.maxEntries) ||
+			(c.maxBytes > 0 && c.bytes > c.maxBytes)) {
+		c.removeElement(c.order.Back())
+		c.evictions++
+	}
+}
+
+// removeElement must be called with c.mu held.
+func (c *astCacheStore) removeElement(el *list.Element) {
+	entry := c.order.Remove(el).(*cachedAST)
+	delete(c.items, entry.key)
+	c.bytes -= entry.size
+}
+
+// removeExpired drops every entry whose expiration is not after now.
+func (c *astCacheStore) removeExpired(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for el := c.order.Back(); el != nil; {
+		prev := el.Prev()
+		if !now.Before(el.Value.(*cachedAST).expires) {
+			c.removeElement(el)
+		}
+		el = prev
+	}
+}
+
+func (c *astCacheStore) setLimits(maxEntries int, maxBytes int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxEntries = max(maxEntries, 0)
+	c.maxBytes = max(maxBytes, 0)
+	c.evictOverLimit()
+}
+
+func (c *astCacheStore) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// clear removes all entries and resets the counters. Limits are kept.
+func (c *astCacheStore) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.items = make(map[uint64]*list.Element)
+	c.bytes = 0
+	c.hits, c.misses, c.evictions = 0, 0, 0
+}
+
+func (c *astCacheStore) stats() ASTCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return ASTCacheStats{
+		Hits:       c.hits,
+		Misses:     c.misses,
+		Evictions:  c.evictions,
+		Entries:    c.order.Len(),
+		Bytes:      c.bytes,
+		MaxEntries: c.maxEntries,
+		MaxBytes:   c.maxBytes,
+	}
+}
+
+// astNodeOverhead approximates the fixed per-node cost of an MJMLNode
+// (struct header, slice headers and the mixed content entry pointing at it).
+const astNodeOverhead = 160
+
+// estimateASTSize approximates the memory retained by a parsed AST. It counts
+// string payloads plus a fixed overhead per node and attribute, which is
+// accurate enough for enforcing a byte budget without reflection.
+func estimateASTSize(node *MJMLNode) int64 {
+	if node == nil {
+		return 0
+	}
+	size := int64(astNodeOverhead + len(node.XMLName.Local) + len(node.Text))
+	for _, attr := range node.Attrs {
+		size += int64(64 + len(attr.Name.Local) + len(attr.Value))
+	}
+	for _, part := range node.MixedContent {
+		size += int64(len(part.Text))
+	}
+	for _, child := range node.Children {
+		size += estimateASTSize(child)
+	}
+	return size
+}
@@ -5,14 +5,13 @@ import (
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/preslavrachev/gomjml/parser"
 )
 
 // helper to clear cache and stop cleanup between tests
 func resetASTCache() {
-	astCache.Range(func(key, _ interface{}) bool {
-		astCache.Delete(key)
-		return true
-	})
+	astCache.clear()
 	StopASTCacheCleanup()
 }
 
@@ -41,8 +40,7 @@ func TestCachingDisabledByDefault(t *testing.T) {
 		t.Fatalf("expected 2 parses, got %d", calls)
 	}
 
-	entries := 0
-	astCache.Range(func(_, _ interface{}) bool { entries++; return true })
+	entries := astCache.len()
 	if entries != 0 {
 		t.Fatalf("expected cache to remain empty, got %d entries", entries)
 	}
@@ -78,8 +76,7 @@ func TestCachingStoresAndReusesAST(t *testing.T) {
 		t.Fatalf("expected cached AST to be reused")
 	}
 
-	entries := 0
-	astCache.Range(func(_, _ interface{}) bool { entries++; return true })
+	entries := astCache.len()
 	if entries != 1 {
 		t.Fatalf("expected 1 cache entry, got %d", entries)
 	}
@@ -240,8 +237,7 @@ func TestCacheSeparateTemplates(t *testing.T) {
 		t.Fatalf("expected 2 parses for different templates, got %d", calls)
 	}
 
-	entries := 0
-	astCache.Range(func(_, _ interface{}) bool { entries++; return true })
+	entries := astCache.len()
 	if entries != 2 {
 		t.Fatalf("expected 2 cache entries, got %d", entries)
 	}
@@ -287,3 +283,127 @@ func TestSetASTCacheCleanupIntervalOnce(t *testing.T) {
 		t.Fatalf("second cleanup interval set should be ignored, got %v", astCacheCleanupInterval)
 	}
 }
+
+func TestCacheLRUEvictionByEntries(t *testing.T) {
+	resetASTCache()
+	defer resetASTCache()
+	SetASTCacheLimits(2, 0)
+	defer SetASTCacheLimits(0, 0)
+
+	var calls int32
+	origParse := ParseMJML
+	ParseMJML = func(s string) (*MJMLNode, error) {
+		atomic.AddInt32(&calls, 1)
+		return origParse(s)
+	}
+	defer func() { ParseMJML = origParse }()
+
+	tpl1 := `<mjml><mj-body><mj-section><mj-column><mj-text>one</mj-text></mj-column></mj-section></mj-body></mjml>`
+	tpl2 := `<mjml><mj-body><mj-section><mj-column><mj-text>two</mj-text></mj-column></mj-section></mj-body></mjml>`
+	tpl3 := `<mjml><mj-body><mj-section><mj-column><mj-text>three</mj-text></mj-column></mj-section></mj-body></mjml>`
+
+	for _, tpl := range []string{tpl1, tpl2, tpl1, tpl3} {
+		if _, err := parseAST(tpl, true); err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+	}
+	// tpl2 was least recently used when tpl3 arrived, so tpl1 must still be cached.
+	if _, err := parseAST(tpl1, true); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 parses, got %d", calls)
+	}
+
+	stats := CacheStats()
+	if stats.Entries != 2 || stats.Evictions != 1 {
+		t.Fatalf("expected 2 entries and 1 eviction, got %+v", stats)
+	}
+	if stats.Hits != 2 || stats.Misses != 3 {
+		t.Fatalf("expected 2 hits and 3 misses, got %+v", stats)
+	}
+	if stats.MaxEntries != 2 {
+		t.Fatalf("expected MaxEntries 2, got %d", stats.MaxEntries)
+	}
+}
+
+func TestCacheLRUEvictionByBytes(t *testing.T) {
+	resetASTCache()
+	defer resetASTCache()
+	defer SetASTCacheLimits(0, 0)
+
+	tpl1 := `<mjml><mj-body><mj-section><mj-column><mj-text>one</mj-text></mj-column></mj-section></mj-body></mjml>`
+	tpl2 := `<mjml><mj-body><mj-section><mj-column><mj-text>two</mj-text></mj-column></mj-section></mj-body></mjml>`
+
+	if _, err := parseAST(tpl1, true); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	entrySize := CacheStats().Bytes
+	if entrySize <= 0 {
+		t.Fatalf("expected positive entry size, got %d", entrySize)
+	}
+
+	SetASTCacheLimits(0, entrySize+entrySize/2)
+	if _, err := parseAST(tpl2, true); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	stats := CacheStats()
+	if stats.Entries != 1 || stats.Evictions != 1 {
+		t.Fatalf("expected 1 entry after byte-based eviction, got %+v", stats)
+	}
+	if stats.Bytes > stats.MaxBytes {
+		t.Fatalf("cache size %d exceeds limit %d", stats.Bytes, stats.MaxBytes)
+	}
+
+	// Tightening the limit evicts immediately.
+	SetASTCacheLimits(0, 1)
+	if stats := CacheStats(); stats.Entries != 0 || stats.Bytes != 0 {
+		t.Fatalf("expected empty cache after shrinking limit, got %+v", stats)
+	}
+}
+
+func TestCacheLearnsOutputSize(t *testing.T) {
+	resetASTCache()
+	defer resetASTCache()
+
+	tpl := `<mjml><mj-body><mj-section><mj-column><mj-text>sized</mj-text></mj-column></mj-section></mj-body></mjml>`
+	key := astCacheKey(tpl, parser.ParseOptions{})
+
+	if _, err := Render(tpl); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if _, ok := astCache.outputSizeHint(key); ok {
+		t.Fatal("expected no size hint without caching")
+	}
+
+	html, err := Render(tpl, WithCache())
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	hint, ok := astCache.outputSizeHint(key)
+	if !ok {
+		t.Fatal("expected a size hint after a cached render")
+	}
+	if hint < len(html) || hint > len(html)+len(html)/8 {
+		t.Errorf("size hint = %d, want slightly above the output size %d", hint, len(html))
+	}
+
+	stats := CacheStats()
+	if stats.Hits != 0 || stats.Misses != 1 {
+		t.Errorf("size hints must not count as lookups, got %+v", stats)
+	}
+}
+
+func TestRollingOutputSize(t *testing.T) {
+	average := rollingOutputSize(0, 1000)
+	if average != 1000 {
+		t.Fatalf("first render: average = %d, want 1000", average)
+	}
+	if average = rollingOutputSize(average, 2000); average != 1250 {
+		t.Errorf("second render: average = %d, want 1250", average)
+	}
+	if hint := outputSizeHint(1600); hint != 1700 {
+		t.Errorf("outputSizeHint(1600) = %d, want 1700", hint)
+	}
+}
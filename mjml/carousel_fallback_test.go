@@ -0,0 +1,127 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const carouselFallbackInput = `<mjml><mj-body><mj-section><mj-column>
+  <mj-carousel>
+    <mj-carousel-image src="https://example.com/1.png" />
+    <mj-carousel-image src="https://example.com/2.png" />
+    <mj-carousel-image src="https://example.com/3.png" />
+  </mj-carousel>
+</mj-column></mj-section></mj-body></mjml>`
+
+// TestCarouselWithoutFormInputs checks that a carousel degrades to its first
+// image in clients that strip form inputs, such as Gmail: without the radio
+// buttons no :checked rule applies, so only the inline styles decide what
+// shows.
+func TestCarouselWithoutFormInputs(t *testing.T) {
+	stripInputs := func(doc *html.Node) error {
+		var inputs []*html.Node
+		for n := range doc.Descendants() {
+			if n.DataAtom == atom.Input {
+				inputs = append(inputs, n)
+			}
+		}
+		for _, n := range inputs {
+			n.Parent.RemoveChild(n)
+		}
+		return nil
+	}
+
+	var visible, hidden []string
+	var arrows []string
+	inspect := func(doc *html.Node) error {
+		for n := range doc.Descendants() {
+			if n.DataAtom != atom.Div {
+				continue
+			}
+			class, style := htmlAttr(n, "class"), htmlAttr(n, "style")
+			switch {
+			case strings.HasPrefix(class, "mj-carousel-image mj-carousel-image-"):
+				if strings.Contains(style, "display:none") {
+					hidden = append(hidden, class)
+				} else {
+					visible = append(visible, class)
+				}
+			case class == "mj-carousel-previous-icons" || class == "mj-carousel-next-icons":
+				if !strings.Contains(style, "display:none") {
+					arrows = append(arrows, class)
+				}
+			}
+		}
+		return nil
+	}
+
+	output, err := Render(carouselFallbackInput, WithHTMLTransform(stripInputs), WithHTMLTransform(inspect))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if strings.Contains(output, "<input") {
+		t.Fatal("expected the inputs to be stripped")
+	}
+	// The Outlook fallback is a comment, so the parsed tree only holds the
+	// interactive carousel
+	if len(visible) != 1 || visible[0] != "mj-carousel-image mj-carousel-image-1" {
+		t.Errorf("visible images = %q, want only the first image", visible)
+	}
+	if len(hidden) != 2 {
+		t.Errorf("hidden images = %q, want the second and third image", hidden)
+	}
+	if len(arrows) != 0 {
+		t.Errorf("arrows visible without inputs: %q", arrows)
+	}
+	for _, rule := range []string{
+		".mj-carousel noinput { display:block !important; }",
+		".mj-carousel noinput .mj-carousel-image-1 { display: block !important;  }",
+		".mj-carousel noinput .mj-carousel-thumbnails { display: none !important; }",
+	} {
+		if !strings.Contains(output, rule) {
+			t.Errorf("output missing noinput rule %q", rule)
+		}
+	}
+}
+
+// TestCarouselOutlookFallbacks checks the rules for Outlook on the web, which
+// marks its message container with an owa attribute, and the first-image
+// fallback for Outlook on Windows.
+func TestCarouselOutlookFallbacks(t *testing.T) {
+	output, err := Render(carouselFallbackInput)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if !strings.Contains(output, "[owa] .mj-carousel-thumbnail { display: none !important; }") {
+		t.Error("expected thumbnails to be hidden in Outlook on the web")
+	}
+	if got := strings.Count(output, `class="mj-carousel-thumbnail `); got != 3 {
+		t.Errorf("got %d thumbnails, want 3", got)
+	}
+
+	start := strings.LastIndex(output, "<!--[if mso]>")
+	if start < strings.Index(output, `<div class="mj-carousel">`) {
+		t.Fatal("expected an Outlook fallback")
+	}
+	fallback := output[start:]
+	fallback = fallback[:strings.Index(fallback, "<![endif]-->")]
+	if !strings.Contains(fallback, "mj-carousel-image-1") || !strings.Contains(fallback, "https://example.com/1.png") {
+		t.Errorf("expected the first image in the Outlook fallback, got %q", fallback)
+	}
+	if strings.Contains(fallback, "https://example.com/2.png") || strings.Contains(fallback, "<input") {
+		t.Errorf("expected only the first image in the Outlook fallback, got %q", fallback)
+	}
+}
+
+func htmlAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+package mjml
+
+import (
+	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml/components"
+)
+
+// Chunk is the markup of one top-level body block, such as an mj-section,
+// mj-wrapper, mj-hero or mj-raw.
+type Chunk struct {
+	Index   int    // Position among the children of mj-body
+	TagName string // Tag of the block
+	Line    int    // Source line of the block
+	HTML    string // Rendered markup of the block
+}
+
+// WithChunkCallback calls fn with each top-level body block as soon as it is
+// rendered, in document order, so that a preview can be flushed to the client
+// while the rest of the email renders. An error returned by fn aborts the
+// render and is returned by it, e.g. when the client has gone away.
+//
+// The chunks concatenate to the content of the body's wrapper div as Render
+// returns it, but Outlook conditional comments may open in one chunk and close
+// in the next. The document head is only written once the body is complete,
+// because it depends on the fonts and styles the body uses. Chunks are taken
+// before the document is post-processed, so they are not converted to AMP,
+// escaped for ASCII output or passed through WithHTMLTransform.
+//
+// The callback shortens the time to the first bytes, not the memory a render
+// needs: the body is still buffered to build and post-process the finished
+// document, which is returned as a whole, so memory use stays proportional to
+// the size of the document. Template.Render writes it to an io.Writer.
+func WithChunkCallback(fn func(Chunk) error) RenderOption {
+	return func(opts *RenderOpts) {
+		existingHandler := opts.BlockHandler
+		opts.BlockHandler = func(index int, tagName string, line int, markup string) error {
+			if existingHandler != nil {
+				if err := existingHandler(index, tagName, line, markup); err != nil {
+					return err
+				}
+			}
+			if strings.Contains(markup, components.SourceMapMarkerDelimiter) {
+				markup, _ = extractSourceMap(markup)
+			}
+			markup = normalizeGroupColumnClassOrder(markup)
+			return fn(Chunk{Index: index, TagName: tagName, Line: line, HTML: markup})
+		}
+	}
+}
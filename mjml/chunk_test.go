@@ -0,0 +1,97 @@
+package mjml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const chunkInput = `<mjml>
+  <mj-body>
+    <mj-section><mj-column><mj-text>First</mj-text></mj-column></mj-section>
+    <mj-raw><p>Raw</p></mj-raw>
+    <mj-wrapper>
+      <mj-section><mj-column><mj-text>Second</mj-text></mj-column></mj-section>
+    </mj-wrapper>
+  </mj-body>
+</mjml>`
+
+func TestChunkCallback(t *testing.T) {
+	var chunks []Chunk
+	output, err := Render(chunkInput, WithChunkCallback(func(chunk Chunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := []struct {
+		tag  string
+		line int
+		text string
+	}{
+		{"mj-section", 3, "First"},
+		{"mj-raw", 4, "<p>Raw</p>"},
+		{"mj-wrapper", 5, "Second"},
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("got %d chunks, want %d", len(chunks), len(want))
+	}
+	var body strings.Builder
+	for i, chunk := range chunks {
+		if chunk.Index != i || chunk.TagName != want[i].tag || chunk.Line != want[i].line {
+			t.Errorf("chunk %d = %d %s line %d, want %s line %d", i, chunk.Index, chunk.TagName, chunk.Line, want[i].tag, want[i].line)
+		}
+		if !strings.Contains(chunk.HTML, want[i].text) {
+			t.Errorf("chunk %d does not contain %q", i, want[i].text)
+		}
+		body.WriteString(chunk.HTML)
+	}
+	if !strings.Contains(output, body.String()) {
+		t.Error("expected the chunks to concatenate to the rendered body")
+	}
+
+	plain, err := Render(chunkInput)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if output != plain {
+		t.Error("chunk callback changed the output")
+	}
+}
+
+func TestChunkCallbackAbortsRender(t *testing.T) {
+	errGone := errors.New("client gone")
+	calls := 0
+	_, err := Render(chunkInput, WithChunkCallback(func(Chunk) error {
+		calls++
+		return errGone
+	}))
+	if !errors.Is(err, errGone) {
+		t.Fatalf("Render() error = %v, want %v", err, errGone)
+	}
+	if calls != 1 {
+		t.Errorf("callback called %d times after failing, want 1", calls)
+	}
+}
+
+func TestChunkCallbackWithSourceMap(t *testing.T) {
+	collect := func(opts ...RenderOption) []string {
+		var chunks []string
+		opts = append(opts, WithChunkCallback(func(chunk Chunk) error {
+			chunks = append(chunks, chunk.HTML)
+			return nil
+		}))
+		if _, err := Render(chunkInput, opts...); err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		return chunks
+	}
+
+	want := collect()
+	got := collect(WithSourceMap())
+	if strings.Join(got, "") != strings.Join(want, "") {
+		t.Error("expected chunks without source map markers")
+	}
+}
@@ -0,0 +1,127 @@
+package mjml
+
+import (
+	"encoding/xml"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml/globals"
+	"github.com/preslavrachev/gomjml/mjml/options"
+	"github.com/preslavrachev/gomjml/parser"
+)
+
+// applyGlobalAttributes collects the mj-attributes of ast, and those of its
+// isolated includes, into the render options, scoping them to this render,
+// and returns ast with the element defaults nested in mj-class definitions
+// applied.
+func applyGlobalAttributes(ast *MJMLNode, renderOpts *RenderOpts) *MJMLNode {
+	renderOpts.GlobalAttributes = newGlobalAttributes(ast, renderOpts.DefaultAttributes)
+	renderOpts.IncludeAttributes = nil
+	if renderOpts.IsolateIncludes {
+		renderOpts.IncludeAttributes = collectIncludeAttributes(ast, renderOpts.DefaultAttributes, nil)
+	}
+	return applyClassChildDefaults(ast, renderOpts.GlobalAttributes, renderOpts.IncludeAttributes)
+}
+
+// collectIncludeAttributes adds the attributes of every AttributeScope in
+// node to scopes.
+func collectIncludeAttributes(node *MJMLNode, defaults map[string]map[string]string, scopes options.IncludeAttributes) options.IncludeAttributes {
+	if head := node.AttributeScope; head != nil {
+		if _, ok := scopes[head]; !ok {
+			if scopes == nil {
+				scopes = make(options.IncludeAttributes)
+			}
+			ga := globals.NewGlobalAttributes()
+			ga.SetDefaults(defaults)
+			ga.ProcessAttributesFromHead(head)
+			scopes[head] = ga
+		}
+	}
+	for _, child := range node.Children {
+		scopes = collectIncludeAttributes(child, defaults, scopes)
+	}
+	return scopes
+}
+
+// applyClassChildDefaults returns node with the defaults of nested mj-class
+// elements written onto the elements they apply to, as MJML does:
+//
+//	<mj-class name="dark"><mj-text color="#ffffff" /></mj-class>
+//	...
+//	<mj-section mj-class="dark"><mj-column><mj-text>White</mj-text>...
+//
+// The classes of the nearest ancestor with an mj-class apply. Their defaults
+// take precedence over mj-attributes and the element's own classes but not
+// over attributes written on the element, which are kept. Like
+// expandConditionals, it copies the nodes it changes instead of modifying
+// them. The content of isolated includes uses the classes of its own scope.
+func applyClassChildDefaults(node *MJMLNode, ga *globals.GlobalAttributes, scopes options.IncludeAttributes) *MJMLNode {
+	if node == nil || !ga.HasClassChildAttributes() && len(scopes) == 0 {
+		return node
+	}
+	return applyParentClassDefaults(node, "", ga, scopes)
+}
+
+func applyParentClassDefaults(node *MJMLNode, parentClass string, ga *globals.GlobalAttributes, scopes options.IncludeAttributes) *MJMLNode {
+	if scoped, ok := scopes[node.AttributeScope]; ok && scoped != ga {
+		// Classes of the including document do not reach into an isolated include
+		ga, parentClass = scoped, ""
+	}
+
+	var attrs []xml.Attr
+	if parentClass != "" {
+		defaults := make(map[string]string)
+		for _, className := range strings.Fields(parentClass) {
+			maps.Copy(defaults, ga.ClassChildAttributes(className, node.GetTagName()))
+		}
+		for _, name := range slices.Sorted(maps.Keys(defaults)) {
+			if node.GetAttribute(name) != "" {
+				continue
+			}
+			if attrs == nil {
+				attrs = slices.Clone(node.Attrs)
+			}
+			attrs = append(attrs, xml.Attr{Name: xml.Name{Local: name}, Value: defaults[name]})
+		}
+	}
+
+	childClass := parentClass
+	if class := node.GetAttribute("mj-class"); class != "" {
+		childClass = class
+	}
+	var replaced map[*MJMLNode]*MJMLNode
+	for _, child := range node.Children {
+		if applied := applyParentClassDefaults(child, childClass, ga, scopes); applied != child {
+			if replaced == nil {
+				replaced = make(map[*MJMLNode]*MJMLNode)
+			}
+			replaced[child] = applied
+		}
+	}
+
+	if attrs == nil && replaced == nil {
+		return node
+	}
+	applied := *node
+	if attrs != nil {
+		applied.Attrs = attrs
+	}
+	if replaced != nil {
+		applied.Children = make([]*MJMLNode, len(node.Children))
+		for i, child := range node.Children {
+			if r, ok := replaced[child]; ok {
+				child = r
+			}
+			applied.Children[i] = child
+		}
+		applied.MixedContent = make([]parser.MixedContentPart, len(node.MixedContent))
+		for i, part := range node.MixedContent {
+			if r, ok := replaced[part.Node]; ok {
+				part.Node = r
+			}
+			applied.MixedContent[i] = part
+		}
+	}
+	return &applied
+}
@@ -0,0 +1,75 @@
+package mjml
+
+import (
+	"regexp"
+
+	"github.com/preslavrachev/gomjml/mjml/options"
+)
+
+// Client is an alias for convenience
+type Client = options.Client
+
+// Email clients with output tweaks
+const (
+	ClientOutlookDesktop = options.ClientOutlookDesktop
+	ClientOutlookWeb     = options.ClientOutlookWeb
+	ClientAppleMail      = options.ClientAppleMail
+)
+
+// WithTargetClients enables output tweaks for the given email clients on top
+// of the default output, which stays the same as MJML's:
+//
+//   - ClientOutlookDesktop renders linked buttons with VML (see
+//     WithOutlookVMLButtons) and adds mso-line-height-rule:exactly to mj-text
+//     line heights, which Outlook otherwise treats as a minimum.
+//   - ClientOutlookWeb adds [owa] rules that give Outlook.com and Office 365
+//     the desktop column layout, like <mjml owa="desktop">. Outlook on the web
+//     drops media queries and would otherwise stack all columns.
+//   - ClientAppleMail adds the x-apple-disable-message-reformatting meta tag
+//     so iOS Mail does not rescale the message.
+//
+// The tweaks are harmless in other clients.
+func WithTargetClients(clients ...Client) RenderOption {
+	return func(opts *RenderOpts) {
+		for _, client := range clients {
+			switch client {
+			case ClientOutlookDesktop:
+				opts.OutlookVMLButtons = true
+				opts.TextLineHeightRule = true
+			case ClientOutlookWeb:
+				opts.ForceOWADesktop = true
+			case ClientAppleMail:
+				opts.DisableAppleReformatting = true
+			}
+		}
+	}
+}
+
+var (
+	// Downlevel-revealed markers (<!--[if !mso]><!--> ... <!--<![endif]-->)
+	// keep their content for every client but Outlook.
+	msoRevealedOpen  = regexp.MustCompile(`<!--\[if [^\]]*mso[^\]]*\]><!-->`)
+	msoRevealedClose = regexp.MustCompile(`<!--<!\[endif\]-->`)
+	// Downlevel-hidden blocks, which hold the Outlook tables and VML.
+	msoOnlyBlock = regexp.MustCompile(`(?s)<!--\[if [^\]]*mso[^\]]*\]>.*?<!\[endif\]-->`)
+)
+
+// WithoutOutlookSupport leaves out everything that only Outlook desktop
+// reads: MSO conditional comments with the ghost tables and VML inside them,
+// the Office settings in the head and the VML and Office namespaces. Content
+// revealed to other clients is kept. The output is much smaller, for products
+// that only show the email in modern clients, such as mobile apps and web
+// previews; Outlook desktop renders it without its fixed-width layout.
+func WithoutOutlookSupport() RenderOption {
+	return func(opts *RenderOpts) {
+		opts.OmitOutlookSupport = true
+	}
+}
+
+// stripOutlookSupport removes MSO conditional comments from a rendered
+// document, keeping the content of downlevel-revealed ones.
+func stripOutlookSupport(document string) string {
+	document = msoRevealedOpen.ReplaceAllString(document, "")
+	document = msoRevealedClose.ReplaceAllString(document, "")
+	return msoOnlyBlock.ReplaceAllString(document, "")
+}
@@ -0,0 +1,96 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+const clientsDocument = `<mjml><mj-body><mj-section>
+  <mj-column><mj-text line-height="20px">Hello</mj-text></mj-column>
+  <mj-column><mj-button href="https://example.com" width="200px">Go</mj-button></mj-column>
+</mj-section></mj-body></mjml>`
+
+func TestWithTargetClients(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  Client
+		want    []string
+		notWant []string
+	}{
+		{
+			name:   "outlook desktop",
+			client: ClientOutlookDesktop,
+			want:   []string{"line-height:20px;mso-line-height-rule:exactly;", "<v:roundrect"},
+		},
+		{
+			name:   "outlook web",
+			client: ClientOutlookWeb,
+			want:   []string{`<style type="text/css">[owa] .mj-column-per-50 { width:50% !important; max-width: 50%; }</style>`},
+		},
+		{
+			name:   "apple mail",
+			client: ClientAppleMail,
+			want:   []string{`<meta name="x-apple-disable-message-reformatting">`},
+		},
+	}
+
+	plain, err := Render(clientsDocument)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, err := Render(clientsDocument, WithTargetClients(tt.client))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(html, want) {
+					t.Errorf("output for %s missing %q", tt.client, want)
+				}
+				if strings.Contains(plain, want) {
+					t.Errorf("default output unexpectedly contains %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestOWADesktopAttribute(t *testing.T) {
+	html, err := Render(strings.Replace(clientsDocument, "<mjml>", `<mjml owa="desktop">`, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html, "[owa] .mj-column-per-50") {
+		t.Error(`<mjml owa="desktop"> did not add [owa] column rules`)
+	}
+}
+
+func TestWithoutOutlookSupport(t *testing.T) {
+	input := `<mjml><mj-body><mj-section background-url="https://example.com/bg.png"><mj-column>
+  <mj-text>Hello</mj-text>
+  <mj-button href="https://example.com">Go</mj-button>
+</mj-column></mj-section></mj-body></mjml>`
+
+	full, err := Render(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html, err := Render(input, WithoutOutlookSupport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, unwanted := range []string{"<!--[if", "<![endif]", "<v:", "<o:", "xmlns:v", "xmlns:o"} {
+		if strings.Contains(html, unwanted) {
+			t.Errorf("output contains %q", unwanted)
+		}
+	}
+	for _, want := range []string{"Hello", `href="https://example.com"`, `<div class="mj-column-per-100 mj-outlook-group-fix"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if len(html) >= len(full)*3/4 {
+		t.Errorf("output is %d bytes, want well below the %d bytes with Outlook support", len(html), len(full))
+	}
+}
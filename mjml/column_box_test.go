@@ -0,0 +1,164 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestColumnBoxModelWidth verifies that column padding, border and inner-border all
+// reduce the width available to child components, as in MJML's column box model.
+func TestColumnBoxModelWidth(t *testing.T) {
+	input := `<mjml>
+  <mj-body>
+    <mj-section>
+      <mj-column padding="10px" border="2px solid #000" inner-border="3px solid #ccc">
+        <mj-image src="https://example.com/a.png" />
+      </mj-column>
+      <mj-column padding-left="5px" border-right="4px dashed red">
+        <mj-image src="https://example.com/b.png" />
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	// 300 - 2*10 padding - 2*2 border - 2*3 inner-border - 2*25 image padding
+	if !strings.Contains(html, `src="https://example.com/a.png" width="220"`) {
+		t.Error("expected first image width to account for padding, border and inner-border")
+	}
+	// 300 - 5 padding-left - 4 border-right - 2*25 image padding
+	if !strings.Contains(html, `src="https://example.com/b.png" width="241"`) {
+		t.Error("expected second image width to account for side-specific padding and border")
+	}
+}
+
+// TestColumnVerticalAlignFromAttributes verifies that vertical-align set through
+// mj-attributes reaches the Outlook cell as well as the column itself.
+func TestColumnVerticalAlignFromAttributes(t *testing.T) {
+	input := `<mjml>
+  <mj-head>
+    <mj-attributes>
+      <mj-column vertical-align="middle" />
+    </mj-attributes>
+  </mj-head>
+  <mj-body>
+    <mj-section>
+      <mj-column><mj-text>Hello</mj-text></mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if !strings.Contains(html, `<td class="" style="vertical-align:middle;width:600px;" >`) {
+		t.Error("expected vertical-align from mj-attributes on the Outlook cell")
+	}
+	if !strings.Contains(html, `display:inline-block;vertical-align:middle;width:100%;`) {
+		t.Error("expected vertical-align from mj-attributes on the column")
+	}
+}
+
+// TestColumnInnerBorder verifies that inner-border attributes style the inner
+// table of a padded column while border styles stay on the gutter cell.
+func TestColumnInnerBorder(t *testing.T) {
+	input := `<mjml>
+  <mj-body>
+    <mj-section>
+      <mj-column padding="10px" border="1px solid #000" background-color="#eeeeee"
+        inner-background-color="#ffffff" inner-border="solid 2px #ccc" inner-border-left="4px dashed red" inner-border-radius="8px">
+        <mj-image src="https://example.com/a.png" />
+      </mj-column>
+      <mj-column inner-border="5px solid #ccc">
+        <mj-image src="https://example.com/b.png" />
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if !strings.Contains(html, `<td style="background-color:#eeeeee;border:1px solid #000;vertical-align:top;padding:10px;">`) {
+		t.Error("expected the outer border and background on the gutter cell")
+	}
+	if !strings.Contains(html, `width="100%" style="background-color:#ffffff;border:solid 2px #ccc;border-left:4px dashed red;border-radius:8px;border-collapse:separate;"`) {
+		t.Error("expected inner-border styles and separate borders on the inner table")
+	}
+	// 300 - 2*10 padding - 2*1 border - 4 inner-border-left - 2 inner-border-right - 2*25 image padding
+	if !strings.Contains(html, `src="https://example.com/a.png" width="222"`) {
+		t.Error("expected inner-border widths written after the style to reduce the content width")
+	}
+	// Without padding there is no gutter and MJML ignores inner-border styles,
+	// but the content width still accounts for them.
+	if strings.Contains(html, "5px solid #ccc") {
+		t.Error("inner-border should not be rendered on a column without padding")
+	}
+	if !strings.Contains(html, `src="https://example.com/b.png" width="240"`) {
+		t.Error("expected inner-border to reduce the content width of an unpadded column")
+	}
+}
+
+// TestSectionBorderReducesColumnWidth verifies that section borders narrow the
+// columns, with border-left and border-right taking precedence over border,
+// and that the border styles land on the section's inner cell.
+func TestSectionBorderReducesColumnWidth(t *testing.T) {
+	input := `<mjml>
+  <mj-body>
+    <mj-section border="10px solid blue" border-left="20px solid red">
+      <mj-column><mj-image src="https://example.com/a.png" /></mj-column>
+    </mj-section>
+    <mj-section border-right="20px solid red">
+      <mj-column><mj-image src="https://example.com/b.png" /></mj-column>
+      <mj-column><mj-text>Hello</mj-text></mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if !strings.Contains(html, `<td style="border:10px solid blue;border-left:20px solid red;direction:ltr;`) {
+		t.Error("expected the border shorthand followed by the side override on the inner cell")
+	}
+	// 600 - 20 border-left - 10 border-right = 570; minus 2*25 image padding
+	if !strings.Contains(html, `vertical-align:top;width:570px;`) || !strings.Contains(html, `src="https://example.com/a.png" width="520"`) {
+		t.Error("expected the first section's column to be narrowed by both borders")
+	}
+	// (600 - 20 border-right) / 2 = 290; minus 2*25 image padding
+	if !strings.Contains(html, `vertical-align:top;width:290px;`) || !strings.Contains(html, `src="https://example.com/b.png" width="240"`) {
+		t.Error("expected the second section's columns to share the width left by border-right")
+	}
+}
+
+// TestHeroPaddingReducesChildWidth verifies that hero padding reduces the width
+// available to its children, as in MJML's hero child context.
+func TestHeroPaddingReducesChildWidth(t *testing.T) {
+	input := `<mjml>
+  <mj-body>
+    <mj-hero padding="0 40px">
+      <mj-image src="https://example.com/a.png" />
+    </mj-hero>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	// 600 - 2*40 hero padding - 2*25 image padding
+	if !strings.Contains(html, `src="https://example.com/a.png" width="470"`) {
+		t.Error("expected image width to account for the hero padding")
+	}
+}
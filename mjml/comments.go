@@ -0,0 +1,27 @@
+package mjml
+
+import (
+	"github.com/preslavrachev/gomjml/mjml/options"
+)
+
+// CommentMode is an alias for convenience
+type CommentMode = options.CommentMode
+
+// Comment modes
+const (
+	CommentsPreserve = options.CommentsPreserve
+	CommentsStrip    = options.CommentsStrip
+	CommentsRawOnly  = options.CommentsRawOnly
+)
+
+// WithComments controls what happens to HTML comments from the MJML source.
+// CommentsPreserve (the default) keeps them in the output, CommentsStrip
+// removes them all, and CommentsRawOnly keeps only those inside mj-raw.
+// Conditional comments such as <!--[if mso]> are always kept. Stripping
+// comments helps keep messages below Gmail's clipping limit. The mode applies
+// while parsing, so it has no effect on RenderFromAST.
+func WithComments(mode CommentMode) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.Comments = mode
+	}
+}
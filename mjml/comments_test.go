@@ -0,0 +1,72 @@
+package mjml
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWithComments(t *testing.T) {
+	input := `<mjml>
+  <mj-head><!-- head comment --><mj-title>T</mj-title></mj-head>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-text><!-- text comment -->Hello<!--[if mso]><b>Outlook</b><![endif]--></mj-text>
+        <mj-button><!-- button comment -->Go</mj-button>
+        <mj-raw><!-- raw comment --><!--[if !mso]><!--><p>r</p><!--<![endif]--></mj-raw>
+      </mj-column>
+    </mj-section>
+    <mj-section><!-- section comment --></mj-section>
+  </mj-body>
+</mjml>`
+
+	tests := []struct {
+		mode CommentMode
+		kept []string
+		gone []string
+	}{
+		{CommentsPreserve, []string{"text comment", "button comment", "raw comment", "section comment"}, nil},
+		{CommentsStrip, nil, []string{"text comment", "button comment", "raw comment", "section comment"}},
+		{CommentsRawOnly, []string{"raw comment"}, []string{"text comment", "button comment", "section comment"}},
+	}
+	for _, tt := range tests {
+		html, err := Render(input, WithComments(tt.mode))
+		if err != nil {
+			t.Fatalf("mode %d: Render failed: %v", tt.mode, err)
+		}
+		for _, want := range tt.kept {
+			if !strings.Contains(html, want) {
+				t.Errorf("mode %d: %q should be kept", tt.mode, want)
+			}
+		}
+		for _, unwanted := range tt.gone {
+			if strings.Contains(html, unwanted) {
+				t.Errorf("mode %d: %q should be stripped", tt.mode, unwanted)
+			}
+		}
+		for _, conditional := range []string{"<!--[if mso]><b>Outlook</b><![endif]-->", "<!--[if !mso]><!--><p>r</p><!--<![endif]-->"} {
+			if !strings.Contains(html, conditional) {
+				t.Errorf("mode %d: conditional comment %q should be kept", tt.mode, conditional)
+			}
+		}
+	}
+}
+
+func TestWithCommentsStripMatchesCommentFreeSource(t *testing.T) {
+	input, err := os.ReadFile("testdata/comment.mjml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stripped, err := Render(string(input), WithComments(CommentsStrip))
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected, err := Render(strings.Replace(string(input), "<!-- A comment -->", "", 1))
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if stripped != expected {
+		t.Errorf("stripped output differs from rendering the source without comments:\n%s\n%s", stripped, expected)
+	}
+}
@@ -0,0 +1,45 @@
+package mjml
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml/options"
+)
+
+// Compatibility is an alias for convenience
+type Compatibility = options.Compatibility
+
+// Reference implementations
+const (
+	CompatMRML  = options.CompatMRML
+	CompatMJML4 = options.CompatMJML4
+)
+
+// WithCompatibility selects the reference implementation the output matches
+// byte for byte. Both produce equivalent emails; they differ in how Outlook
+// conditional comments are serialized:
+//
+//   - CompatMRML (the default) matches MRML, which the test fixtures come
+//     from. A section with a single column holding right-aligned mj-text gets
+//     its Outlook table and cell in separate conditional comments, and the
+//     head gets an empty style tag.
+//   - CompatMJML4 matches the MJML 4 CLI, which always opens the Outlook table
+//     and cell in one conditional comment and writes no empty style tag.
+func WithCompatibility(compat Compatibility) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.Compatibility = compat
+	}
+}
+
+// ParseCompatibility converts a reference implementation name ("mrml" or
+// "mjml4") to a Compatibility. Matching is case-insensitive.
+func ParseCompatibility(name string) (Compatibility, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "mrml":
+		return CompatMRML, nil
+	case "mjml4", "mjml":
+		return CompatMJML4, nil
+	}
+	return CompatMRML, fmt.Errorf("unknown compatibility %q (expected mrml or mjml4)", name)
+}
@@ -0,0 +1,55 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preslavrachev/gomjml/mjml/htmldiff"
+)
+
+func TestWithCompatibility(t *testing.T) {
+	input := `<mjml><mj-body>
+  <mj-section><mj-column><mj-text align="right">Right</mj-text></mj-column></mj-section>
+</mj-body></mjml>`
+
+	const (
+		splitColumn  = `<tr><![endif]--><!--[if mso | IE]><td style="vertical-align:top;width:600px;"><![endif]-->`
+		mergedColumn = `<tr><td class="" style="vertical-align:top;width:600px;" ><![endif]-->`
+		emptyStyle   = `<style type="text/css"></style>`
+	)
+
+	mrml, err := Render(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(mrml, splitColumn) || !strings.Contains(mrml, emptyStyle) {
+		t.Error("MRML output should split the Outlook column and include an empty style tag")
+	}
+
+	mjml4, err := Render(input, WithCompatibility(CompatMJML4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(mjml4, mergedColumn) {
+		t.Error("MJML 4 output should open the Outlook column in one conditional comment")
+	}
+	if strings.Contains(mjml4, emptyStyle) {
+		t.Error("MJML 4 output should not include an empty style tag")
+	}
+
+	if report := htmldiff.Compare(mrml, mjml4); !report.Equal() {
+		t.Errorf("compatibility modes should only differ in serialization:\n%s", report)
+	}
+}
+
+func TestParseCompatibility(t *testing.T) {
+	for name, want := range map[string]Compatibility{"mrml": CompatMRML, "MJML4": CompatMJML4, " mjml ": CompatMJML4} {
+		got, err := ParseCompatibility(name)
+		if err != nil || got != want {
+			t.Errorf("ParseCompatibility(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseCompatibility("mjml3"); err == nil {
+		t.Error("expected an error for an unknown implementation")
+	}
+}
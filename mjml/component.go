@@ -8,7 +8,6 @@ import (
 
 	"github.com/preslavrachev/gomjml/mjml/components"
 	"github.com/preslavrachev/gomjml/mjml/constants"
-	"github.com/preslavrachev/gomjml/mjml/debug"
 	"github.com/preslavrachev/gomjml/mjml/options"
 	"github.com/preslavrachev/gomjml/parser"
 )
@@ -30,8 +29,8 @@ func CreateComponent(node *parser.MJMLNode, opts *options.RenderOpts) (Component
 	tagName := node.GetTagName()
 
 	// Log component creation
-	if debug.Enabled() {
-		debug.DebugLogWithData("component", "create", "Creating component", map[string]any{
+	if opts.Logger.Enabled() {
+		opts.Logger.LogWithData("component", "create", "Creating component", map[string]any{
 			"tag_name":     tagName,
 			"has_children": len(node.Children) > 0,
 			"attr_count":   len(node.Attrs),
@@ -102,9 +101,10 @@ func CreateComponent(node *parser.MJMLNode, opts *options.RenderOpts) (Component
 	case "mj-raw":
 		return components.NewMJRawComponent(node, opts), nil
 	default:
-		if debug.Enabled() {
-			debug.DebugLogError("component", "create-error", "Unknown component type", fmt.Errorf("unknown component: %s", tagName))
+		if opts.InvalidTagReporter != nil && !isUnsupportedTag(tagName) {
+			opts.InvalidTagReporter(tagName, node.GetLineNumber())
 		}
+		opts.Logger.LogError("component", "create-error", "Unknown component type", fmt.Errorf("unknown component: %s", tagName))
 		return nil, fmt.Errorf("unknown component: %s", tagName)
 	}
 }
@@ -114,16 +114,56 @@ func createMJMLComponent(node *parser.MJMLNode, opts *options.RenderOpts) (*MJML
 	// Default to LangUndetermined if not specified - this is the proper fallback
 	// per emailmarkup.org accessibility guidelines: "It's not nearly as good as
 	// setting a language but it's much better than setting nothing"
-	if langAttr := node.GetAttribute("lang"); langAttr != "" {
+	if opts.OverrideLang != "" {
+		opts.Lang = opts.OverrideLang
+	} else if langAttr := node.GetAttribute("lang"); langAttr != "" {
 		opts.Lang = langAttr
 	} else {
 		opts.Lang = constants.LangUndetermined
 	}
 
+	// The direction is stored the same way so an rtl document can cascade it
+	// to the direction of sections, columns and text.
+	if opts.OverrideDir != "" {
+		opts.Dir = opts.OverrideDir
+	} else if dirAttr := node.GetAttribute("dir"); dirAttr != "" {
+		opts.Dir = dirAttr
+	} else {
+		opts.Dir = constants.DirAuto
+	}
+
+	// The MJML release matched selects behaviors that changed between releases
+	version := opts.OverrideVersion
+	if version == "" {
+		version = node.GetAttribute("version")
+	}
+	features, err := FeaturesForVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	opts.Features = features
+
+	// Static components cached across renders are scoped to the template
+	// and the options it is rendered with
+	if opts.FragmentCache != nil {
+		opts.TemplateHash = templateHash(node, opts)
+	}
+
 	comp := &MJMLComponent{
 		BaseComponent: components.NewBaseComponent(node, opts),
 	}
 
+	// mj-raw elements with position="file-start" may also sit directly in the
+	// mjml root; they are written before the doctype wherever they appear.
+	for _, childNode := range node.Children {
+		if childNode.GetTagName() != "mj-raw" {
+			continue
+		}
+		if raw := components.NewMJRawComponent(childNode, opts); raw.IsFileStart() {
+			comp.fileStartRaws = append(comp.fileStartRaws, raw)
+		}
+	}
+
 	// Find head and body components
 	if headNode := node.FindFirstChild("mj-head"); headNode != nil {
 		head := components.NewMJHeadComponent(headNode, opts)
@@ -131,18 +171,15 @@ func createMJMLComponent(node *parser.MJMLNode, opts *options.RenderOpts) (*MJML
 		// Process head children
 		for _, childNode := range headNode.Children {
 			if childComponent, err := CreateComponent(childNode, opts); err == nil {
+				if raw, ok := childComponent.(*components.MJRawComponent); ok && raw.IsFileStart() {
+					comp.fileStartRaws = append(comp.fileStartRaws, raw)
+					continue
+				}
 				head.Children = append(head.Children, childComponent)
 			}
 		}
 
-		if inlineStyles := collectInlineClassStyles(head, opts); len(inlineStyles) > 0 {
-			if opts.InlineClassStyles == nil {
-				opts.InlineClassStyles = make(map[string][]options.InlineStyle, len(inlineStyles))
-			}
-			for className, declarations := range inlineStyles {
-				opts.InlineClassStyles[className] = append(opts.InlineClassStyles[className], declarations...)
-			}
-		}
+		opts.InlineRules = append(opts.InlineRules, collectInlineRules(head, opts)...)
 
 		comp.Head = head
 	}
@@ -154,30 +191,22 @@ func createMJMLComponent(node *parser.MJMLNode, opts *options.RenderOpts) (*MJML
 		// Process body children
 		for _, childNode := range bodyNode.Children {
 			if childComponent, err := CreateComponent(childNode, opts); err == nil {
+				if raw, ok := childComponent.(*components.MJRawComponent); ok && raw.IsFileStart() {
+					comp.fileStartRaws = append(comp.fileStartRaws, raw)
+					continue
+				}
 				body.Children = append(body.Children, childComponent)
 			}
 		}
 
 		// Process nested children (sections/wrappers -> columns -> content)
+		var wrapperOpts []*options.RenderOpts
 		for _, child := range body.Children {
 			switch comp := child.(type) {
 			case *components.MJSectionComponent:
 				processSectionChildren(comp, opts)
 			case *components.MJWrapperComponent:
-				// Create wrapper child opts with InsideWrapper flag
-				// TODO: Evaluate if cloning the opts is the best option here.
-				wrapperChildOpts := *opts // Copy the options
-				wrapperChildOpts.InsideWrapper = true
-				for _, childNode := range comp.Node.Children {
-					if childComponent, err := CreateComponent(childNode, &wrapperChildOpts); err == nil {
-						comp.Children = append(comp.Children, childComponent)
-
-						// Process wrapper's section children
-						if section, ok := childComponent.(*components.MJSectionComponent); ok {
-							processSectionChildren(section, &wrapperChildOpts)
-						}
-					}
-				}
+				wrapperOpts = append(wrapperOpts, processWrapperChildren(comp, opts))
 			case *components.MJHeroComponent:
 				// Process hero children
 				processComponentChildren(comp, comp.Node, opts)
@@ -186,6 +215,7 @@ func createMJMLComponent(node *parser.MJMLNode, opts *options.RenderOpts) (*MJML
 				processComponentChildren(comp, comp.Node, opts)
 			}
 		}
+		comp.wrapperOpts = wrapperOpts
 	}
 
 	return comp, nil
@@ -209,13 +239,14 @@ func processComponentChildren(component Component, node *parser.MJMLNode, opts *
 				comp.Children = append(comp.Children, childComponent)
 
 				// Process accordion element children (title and text)
-				if accordionElement, ok := childComponent.(*components.MJAccordionElementComponent); ok {
-					for _, elementChildNode := range childNode.Children {
-						if elementChildComponent, err := CreateComponent(elementChildNode, opts); err == nil {
-							accordionElement.Children = append(accordionElement.Children, elementChildComponent)
-						}
-					}
-				}
+				processComponentChildren(childComponent, childNode, opts)
+			}
+		}
+	case *components.MJAccordionElementComponent:
+		// Process accordion title and text children
+		for _, childNode := range node.Children {
+			if childComponent, err := CreateComponent(childNode, opts); err == nil {
+				comp.Children = append(comp.Children, childComponent)
 			}
 		}
 	case *components.MJNavbarComponent:
@@ -243,6 +274,26 @@ func processComponentChildren(component Component, node *parser.MJMLNode, opts *
 	}
 }
 
+// processWrapperChildren processes the sections of a wrapper component, which
+// render with their own copy of the options flagged InsideWrapper. It returns
+// that copy.
+func processWrapperChildren(wrapper *components.MJWrapperComponent, opts *options.RenderOpts) *options.RenderOpts {
+	// TODO: Evaluate if cloning the opts is the best option here.
+	wrapperChildOpts := *opts // Copy the options
+	wrapperChildOpts.InsideWrapper = true
+	for _, childNode := range wrapper.Node.Children {
+		if childComponent, err := CreateComponent(childNode, &wrapperChildOpts); err == nil {
+			wrapper.Children = append(wrapper.Children, childComponent)
+
+			// Process wrapper's section children
+			if section, ok := childComponent.(*components.MJSectionComponent); ok {
+				processSectionChildren(section, &wrapperChildOpts)
+			}
+		}
+	}
+	return &wrapperChildOpts
+}
+
 // processSectionChildren processes the children of a section component (columns and groups)
 func processSectionChildren(section *components.MJSectionComponent, opts *options.RenderOpts) {
 	for _, colNode := range section.Node.Children {
@@ -252,37 +303,91 @@ func processSectionChildren(section *components.MJSectionComponent, opts *option
 			// Handle different column types
 			switch col := colComponent.(type) {
 			case *components.MJColumnComponent:
-				// Process column children
-				for _, contentNode := range col.Node.Children {
-					if contentComponent, err := CreateComponent(contentNode, opts); err == nil {
-						col.Children = append(col.Children, contentComponent)
-
-						// Process nested children (e.g., social elements within social component)
-						processComponentChildren(contentComponent, contentNode, opts)
-					}
-				}
+				processColumnChildren(col, opts)
 			case *components.MJGroupComponent:
-				// Process group children (columns within the group)
-				for _, groupChildNode := range col.Node.Children {
-					if groupChildComponent, err := CreateComponent(groupChildNode, opts); err == nil {
-						col.Children = append(col.Children, groupChildComponent)
-
-						// Process column children within the group
-						if groupColumn, ok := groupChildComponent.(*components.MJColumnComponent); ok {
-							for _, contentNode := range groupColumn.Node.Children {
-								if contentComponent, err := CreateComponent(contentNode, opts); err == nil {
-									groupColumn.Children = append(groupColumn.Children, contentComponent)
-
-									// Process nested children (e.g., social elements within social component)
-									processComponentChildren(contentComponent, contentNode, opts)
-								}
-							}
-						}
-					}
+				processGroupChildren(col, opts)
+			}
+		}
+	}
+}
+
+// processGroupChildren processes the columns within a group and their content
+func processGroupChildren(group *components.MJGroupComponent, opts *options.RenderOpts) {
+	for _, groupChildNode := range group.Node.Children {
+		if groupChildComponent, err := CreateComponent(groupChildNode, opts); err == nil {
+			group.Children = append(group.Children, groupChildComponent)
+
+			if groupColumn, ok := groupChildComponent.(*components.MJColumnComponent); ok {
+				processColumnChildren(groupColumn, opts)
+			}
+		}
+	}
+}
+
+// processColumnChildren processes the content components of a column
+func processColumnChildren(col *components.MJColumnComponent, opts *options.RenderOpts) {
+	for _, contentNode := range col.Node.Children {
+		if contentComponent, err := CreateComponent(contentNode, opts); err == nil {
+			col.Children = append(col.Children, contentComponent)
+
+			// Process nested children (e.g., social elements within social component)
+			processComponentChildren(contentComponent, contentNode, opts)
+		}
+	}
+}
+
+// createComponentTree creates the component for node together with its
+// descendants, like createMJMLComponent does for a whole document. It backs
+// Component.Clone. A root gets a copy of opts without the per-document state
+// collected while building, so the copy renders independently.
+func createComponentTree(node *parser.MJMLNode, opts *options.RenderOpts) (Component, error) {
+	if node.GetTagName() == "mjml" {
+		rootOpts := *opts
+		rootOpts.InlineRules = nil
+		rootOpts.FontTracker = options.NewFontTracker()
+		if rootOpts.IDCounters != nil {
+			rootOpts.IDCounters = make(map[string]int)
+		}
+		return createMJMLComponent(node, &rootOpts)
+	}
+
+	comp, err := CreateComponent(node, opts)
+	if err != nil {
+		return nil, err
+	}
+	switch c := comp.(type) {
+	case *components.MJHeadComponent:
+		for _, childNode := range node.Children {
+			if childComponent, err := CreateComponent(childNode, opts); err == nil {
+				c.Children = append(c.Children, childComponent)
+			}
+		}
+	case *components.MJBodyComponent:
+		for _, childNode := range node.Children {
+			if childComponent, err := createComponentTree(childNode, opts); err == nil {
+				// file-start mj-raw belongs to the document, see createMJMLComponent
+				if raw, ok := childComponent.(*components.MJRawComponent); ok && raw.IsFileStart() {
+					continue
 				}
+				c.Children = append(c.Children, childComponent)
 			}
 		}
+	case *components.MJSectionComponent:
+		processSectionChildren(c, opts)
+	case *components.MJWrapperComponent:
+		processWrapperChildren(c, opts)
+	case *components.MJGroupComponent:
+		processGroupChildren(c, opts)
+	case *components.MJColumnComponent:
+		processColumnChildren(c, opts)
+	default:
+		processComponentChildren(comp, node, opts)
 	}
+	return comp, nil
+}
+
+func init() {
+	components.SetTreeBuilder(createComponentTree)
 }
 
 // RenderComponentString renders the given Component to a string.
@@ -296,3 +401,38 @@ func RenderComponentString(c Component) (string, error) {
 	}
 	return output.String(), nil
 }
+
+// componentChildren returns the child components of container components and nil
+// for leaf components. It gives tree walkers a single traversal entry point.
+func componentChildren(comp Component) []Component {
+	switch v := comp.(type) {
+	case *MJMLComponent:
+		if v.Body == nil {
+			return nil
+		}
+		return []Component{v.Body}
+	case *components.MJBodyComponent:
+		return v.Children
+	case *components.MJSectionComponent:
+		return v.Children
+	case *components.MJColumnComponent:
+		return v.Children
+	case *components.MJWrapperComponent:
+		return v.Children
+	case *components.MJGroupComponent:
+		return v.Children
+	case *components.MJHeroComponent:
+		return v.Children
+	case *components.MJSocialComponent:
+		return v.Children
+	case *components.MJAccordionComponent:
+		return v.Children
+	case *components.MJAccordionElementComponent:
+		return v.Children
+	case *components.MJNavbarComponent:
+		return v.Children
+	case *components.MJCarouselComponent:
+		return v.Children
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preslavrachev/gomjml/mjml/components"
+)
+
+const cloneDocument = `<mjml><mj-body>
+  <mj-hero background-url="https://example.com/default.jpg" background-height="300px" background-width="600px">
+    <mj-text>Hello</mj-text>
+  </mj-hero>
+  <mj-section><mj-column><mj-text color="#ff0000">Footer</mj-text></mj-column></mj-section>
+</mj-body></mjml>`
+
+func TestComponentCloneAndSetAttribute(t *testing.T) {
+	ast, err := ParseMJML(cloneDocument)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original, err := NewFromAST(ast)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := RenderComponentString(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	copied, err := original.Clone()
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	body := copied.(*MJMLComponent).Body
+	body.Children[0].SetAttribute("background-url", "https://example.com/segment.jpg")
+	footer := body.Children[1].(*components.MJSectionComponent).Children[0].(*components.MJColumnComponent).Children[0]
+	footer.RemoveAttribute("color")
+
+	html, err := RenderComponentString(copied)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html, "https://example.com/segment.jpg") || strings.Contains(html, "default.jpg") {
+		t.Error("expected the clone to use the new background-url")
+	}
+	if strings.Contains(html, "#ff0000") {
+		t.Error("expected the removed color to fall back to the default")
+	}
+
+	got, err := RenderComponentString(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Error("changing the clone modified the original")
+	}
+	if ast.Children[0].Children[0].GetAttribute("background-url") != "https://example.com/default.jpg" {
+		t.Error("changing the clone modified the original AST")
+	}
+}
+
+func TestComponentCloneSubtree(t *testing.T) {
+	ast, err := ParseMJML(cloneDocument)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := NewFromAST(ast)
+	if err != nil {
+		t.Fatal(err)
+	}
+	section := root.(*MJMLComponent).Body.Children[1]
+	want, err := RenderComponentString(section)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	copied, err := section.Clone()
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	got, err := RenderComponentString(copied)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("cloned section renders differently:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestSetAttributeLeavesAST(t *testing.T) {
+	ast, err := ParseMJML(cloneDocument)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := NewFromAST(ast)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hero := root.(*MJMLComponent).Body.Children[0]
+	hero.SetAttribute("background-url", "https://example.com/segment.jpg")
+	hero.RemoveAttribute("background-height")
+
+	html, err := RenderComponentString(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html, "https://example.com/segment.jpg") {
+		t.Error("expected the component to use the new background-url")
+	}
+	heroNode := ast.Children[0].Children[0]
+	if heroNode.GetAttribute("background-url") != "https://example.com/default.jpg" || heroNode.GetAttribute("background-height") != "300px" {
+		t.Error("changing the component modified the AST it was built from")
+	}
+}
@@ -2,8 +2,8 @@ package components
 
 import (
 	"io"
-	"strings"
 
+	"github.com/preslavrachev/gomjml/mjml/components/defaults"
 	"github.com/preslavrachev/gomjml/mjml/constants"
 	"github.com/preslavrachev/gomjml/mjml/fonts"
 	"github.com/preslavrachev/gomjml/mjml/html"
@@ -46,6 +46,7 @@ func (c *MJAccordionComponent) Render(w io.StringWriter) error {
 	// Add CSS class if specified
 	if cssClass != "" {
 		tdTag.AddAttribute(constants.AttrClass, cssClass)
+		c.ApplyInlineStyles(tdTag, cssClass)
 	}
 
 	// Add container background color first if specified (MRML order)
@@ -92,7 +93,7 @@ func (c *MJAccordionComponent) Render(w io.StringWriter) error {
 		if accordionElement, ok := child.(*MJAccordionElementComponent); ok {
 			accordionElement.SetContainerWidth(c.GetContainerWidth())
 			accordionElement.inheritFromParent(c)
-			if err := accordionElement.Render(w); err != nil {
+			if err := c.RenderChild(accordionElement, w); err != nil {
 				return err
 			}
 		}
@@ -111,32 +112,7 @@ func (c *MJAccordionComponent) GetTagName() string {
 }
 
 func (c *MJAccordionComponent) GetDefaultAttribute(name string) string {
-	switch name {
-	case constants.MJMLBorder:
-		return "2px solid black"
-	case constants.MJMLFontFamily:
-		return fonts.DefaultFontStack
-	case "icon-align":
-		return constants.VAlignMiddle
-	case "icon-height":
-		return "32px"
-	case "icon-position":
-		return constants.AlignRight
-	case "icon-unwrapped-alt":
-		return "-"
-	case "icon-unwrapped-url":
-		return "https://i.imgur.com/w4uTygT.png"
-	case "icon-width":
-		return "32px"
-	case "icon-wrapped-alt":
-		return "+"
-	case "icon-wrapped-url":
-		return "https://i.imgur.com/bIXv1bk.png"
-	case constants.MJMLPadding:
-		return "10px 25px"
-	default:
-		return ""
-	}
+	return defaults.Get(c.GetTagName(), name)
 }
 
 // MJAccordionTextComponent represents the mj-accordion-text component
@@ -151,58 +127,7 @@ func NewMJAccordionTextComponent(node *parser.MJMLNode, opts *options.RenderOpts
 }
 
 func (c *MJAccordionTextComponent) Render(w io.StringWriter) error {
-	// Render the raw content inside the accordion text
-	content := strings.TrimSpace(c.Node.Text)
-	if content != "" {
-		if _, err := w.WriteString(content); err != nil {
-			return err
-		}
-	}
-
-	// Also render any child HTML elements
-	for _, child := range c.Node.Children {
-		if err := c.renderHTMLChild(w, child); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func (c *MJAccordionTextComponent) renderHTMLChild(w io.StringWriter, node *parser.MJMLNode) error {
-	// Simple HTML rendering for child elements like <span>
-	tagName := node.XMLName.Local
-	if tagName != "" {
-		if _, err := w.WriteString("<" + tagName + ">"); err != nil {
-			return err
-		}
-
-		// Render text content
-		if content := strings.TrimSpace(node.Text); content != "" {
-			if _, err := w.WriteString(content); err != nil {
-				return err
-			}
-		}
-
-		// Render children recursively
-		for _, child := range node.Children {
-			if err := c.renderHTMLChild(w, child); err != nil {
-				return err
-			}
-		}
-
-		if _, err := w.WriteString("</" + tagName + ">"); err != nil {
-			return err
-		}
-	} else {
-		// Text node
-		if content := strings.TrimSpace(node.Text); content != "" {
-			if _, err := w.WriteString(content); err != nil {
-				return err
-			}
-		}
-	}
-	return nil
+	return writeAccordionInnerHTML(w, c.BaseComponent)
 }
 
 func (c *MJAccordionTextComponent) GetTagName() string {
@@ -211,17 +136,11 @@ func (c *MJAccordionTextComponent) GetTagName() string {
 
 func (c *MJAccordionTextComponent) GetDefaultAttribute(name string) string {
 	switch name {
-	case constants.MJMLFontSize:
-		return "13px"
-	case constants.MJMLLineHeight:
-		return "1"
-	case constants.MJMLPadding:
-		return "16px"
 	case constants.MJMLFontFamily:
+		// Not an MRML default; keeps the accordion font stack on the text
 		return fonts.DefaultFontStack
-	default:
-		return ""
 	}
+	return defaults.Get(c.GetTagName(), name)
 }
 
 // MJAccordionTitleComponent represents the mj-accordion-title component
@@ -236,14 +155,7 @@ func NewMJAccordionTitleComponent(node *parser.MJMLNode, opts *options.RenderOpt
 }
 
 func (c *MJAccordionTitleComponent) Render(w io.StringWriter) error {
-	// Render the raw content inside the accordion title
-	content := strings.TrimSpace(c.Node.Text)
-	if content != "" {
-		if _, err := w.WriteString(content); err != nil {
-			return err
-		}
-	}
-	return nil
+	return writeAccordionInnerHTML(w, c.BaseComponent)
 }
 
 func (c *MJAccordionTitleComponent) GetTagName() string {
@@ -252,15 +164,11 @@ func (c *MJAccordionTitleComponent) GetTagName() string {
 
 func (c *MJAccordionTitleComponent) GetDefaultAttribute(name string) string {
 	switch name {
-	case constants.MJMLFontSize:
-		return "13px"
-	case constants.MJMLPadding:
-		return "16px"
 	case constants.MJMLFontFamily:
+		// Not an MRML default; keeps the accordion font stack on the title
 		return fonts.DefaultFontStack
-	default:
-		return ""
 	}
+	return defaults.Get(c.GetTagName(), name)
 }
 
 // MJAccordionElementComponent represents the mj-accordion-element component
@@ -288,19 +196,13 @@ func (c *MJAccordionElementComponent) Render(w io.StringWriter) error {
 	iconWrappedAlt := c.getAttribute("icon-wrapped-alt")
 	iconUnwrappedAlt := c.getAttribute("icon-unwrapped-alt")
 
-	// Start accordion element row
-	if _, err := w.WriteString("<tr"); err != nil {
-		return err
-	}
-
-	// Add CSS class to row if specified
+	// Start accordion element row, with the CSS class if specified
+	trTag := html.NewHTMLTag("tr")
 	if cssClass != "" {
-		if _, err := w.WriteString(" class=\"" + cssClass + "\""); err != nil {
-			return err
-		}
+		trTag.AddAttribute(constants.AttrClass, cssClass)
+		c.ApplyInlineStyles(trTag, cssClass)
 	}
-
-	if _, err := w.WriteString(">"); err != nil {
+	if err := trTag.RenderOpen(w); err != nil {
 		return err
 	}
 
@@ -389,11 +291,10 @@ func (c *MJAccordionElementComponent) Render(w io.StringWriter) error {
 func (c *MJAccordionElementComponent) renderTitle(w io.StringWriter, titleComponent *MJAccordionTitleComponent, iconAlign, iconHeight, iconWidth, iconWrappedUrl, iconUnwrappedUrl, iconWrappedAlt, iconUnwrappedAlt string) error {
 	border := c.parentAccordion.GetAttributeWithDefault(c.parentAccordion, constants.MJMLBorder)
 	fontSize := titleComponent.GetAttributeWithDefault(titleComponent, constants.MJMLFontSize)
-	// Only get font-family if explicitly set on title element
-	fontFamily := ""
-	if value := titleComponent.Node.GetAttribute(constants.MJMLFontFamily); value != "" {
-		fontFamily = value
-		titleComponent.TrackFontFamily(value)
+	// Only get font-family if set on the title itself (not the component default)
+	fontFamily := accordionOwnAttribute(titleComponent, constants.MJMLFontFamily)
+	if fontFamily != "" {
+		titleComponent.TrackFontFamily(fontFamily)
 	}
 	padding := titleComponent.GetAttributeWithDefault(titleComponent, constants.MJMLPadding)
 	paddingTop := titleComponent.GetAttributeWithDefault(titleComponent, constants.MJMLPaddingTop)
@@ -402,9 +303,9 @@ func (c *MJAccordionElementComponent) renderTitle(w io.StringWriter, titleCompon
 	paddingRight := titleComponent.GetAttributeWithDefault(titleComponent, constants.MJMLPaddingRight)
 
 	// Get title-specific attributes
-	backgroundColor := titleComponent.Node.GetAttribute(constants.MJMLBackgroundColor)
-	color := titleComponent.Node.GetAttribute(constants.MJMLColor)
-	cssClass := titleComponent.Node.GetAttribute(constants.MJMLCSSClass)
+	backgroundColor := accordionOwnAttribute(titleComponent, constants.MJMLBackgroundColor)
+	color := accordionOwnAttribute(titleComponent, constants.MJMLColor)
+	cssClass := accordionOwnAttribute(titleComponent, constants.MJMLCSSClass)
 
 	// Get icon position to determine order
 	iconPosition := c.getAttribute("icon-position")
@@ -442,6 +343,7 @@ func (c *MJAccordionElementComponent) renderTitle(w io.StringWriter, titleCompon
 	// Add CSS class if specified
 	if cssClass != "" {
 		tdTag.AddAttribute(constants.AttrClass, cssClass)
+		c.ApplyInlineStyles(tdTag, cssClass)
 	}
 
 	// Add background color first if specified (MRML order)
@@ -483,7 +385,7 @@ func (c *MJAccordionElementComponent) renderTitle(w io.StringWriter, titleCompon
 	}
 
 	// Render title content
-	if err := titleComponent.Render(w); err != nil {
+	if err := c.RenderChild(titleComponent, w); err != nil {
 		return err
 	}
 
@@ -533,8 +435,8 @@ func (c *MJAccordionElementComponent) renderIconCell(w io.StringWriter, iconAlig
 
 	// Wrapped state icon (plus)
 	wrappedImg := html.NewHTMLTag("img").
-		AddAttribute(constants.AttrSrc, iconWrappedUrl).
-		AddAttribute(constants.AttrAlt, iconWrappedAlt).
+		AddAttribute(constants.AttrSrc, c.escapeAttribute(iconWrappedUrl)).
+		AddAttribute(constants.AttrAlt, c.escapeAttribute(iconWrappedAlt)).
 		AddAttribute(constants.AttrClass, "mj-accordion-more").
 		AddStyle(constants.CSSDisplay, constants.DisplayNone).
 		AddStyle(constants.CSSWidth, iconWidth).
@@ -546,8 +448,8 @@ func (c *MJAccordionElementComponent) renderIconCell(w io.StringWriter, iconAlig
 
 	// Unwrapped state icon (minus)
 	unwrappedImg := html.NewHTMLTag("img").
-		AddAttribute(constants.AttrSrc, iconUnwrappedUrl).
-		AddAttribute(constants.AttrAlt, iconUnwrappedAlt).
+		AddAttribute(constants.AttrSrc, c.escapeAttribute(iconUnwrappedUrl)).
+		AddAttribute(constants.AttrAlt, c.escapeAttribute(iconUnwrappedAlt)).
 		AddAttribute(constants.AttrClass, "mj-accordion-less").
 		AddStyle(constants.CSSDisplay, constants.DisplayNone).
 		AddStyle(constants.CSSWidth, iconWidth).
@@ -568,11 +470,10 @@ func (c *MJAccordionElementComponent) renderIconCell(w io.StringWriter, iconAlig
 func (c *MJAccordionElementComponent) renderContent(w io.StringWriter, textComponent *MJAccordionTextComponent) error {
 	border := c.parentAccordion.GetAttributeWithDefault(c.parentAccordion, constants.MJMLBorder)
 	fontSize := textComponent.GetAttributeWithDefault(textComponent, constants.MJMLFontSize)
-	// Only get font-family if explicitly set on text element
-	fontFamily := ""
-	if value := textComponent.Node.GetAttribute(constants.MJMLFontFamily); value != "" {
-		fontFamily = value
-		textComponent.TrackFontFamily(value)
+	// Only get font-family if set on the text itself (not the component default)
+	fontFamily := accordionOwnAttribute(textComponent, constants.MJMLFontFamily)
+	if fontFamily != "" {
+		textComponent.TrackFontFamily(fontFamily)
 	}
 	lineHeight := textComponent.GetAttributeWithDefault(textComponent, constants.MJMLLineHeight)
 	padding := textComponent.GetAttributeWithDefault(textComponent, constants.MJMLPadding)
@@ -582,9 +483,9 @@ func (c *MJAccordionElementComponent) renderContent(w io.StringWriter, textCompo
 	paddingRight := textComponent.GetAttributeWithDefault(textComponent, constants.MJMLPaddingRight)
 
 	// Get text-specific attributes
-	backgroundColor := textComponent.Node.GetAttribute(constants.MJMLBackgroundColor)
-	color := textComponent.Node.GetAttribute(constants.MJMLColor)
-	cssClass := textComponent.Node.GetAttribute(constants.MJMLCSSClass)
+	backgroundColor := accordionOwnAttribute(textComponent, constants.MJMLBackgroundColor)
+	color := accordionOwnAttribute(textComponent, constants.MJMLColor)
+	cssClass := accordionOwnAttribute(textComponent, constants.MJMLCSSClass)
 
 	// Start content section
 	divTag := html.NewHTMLTag("div").AddAttribute(constants.AttrClass, "mj-accordion-content")
@@ -611,6 +512,7 @@ func (c *MJAccordionElementComponent) renderContent(w io.StringWriter, textCompo
 	// Add CSS class if specified
 	if cssClass != "" {
 		tdTag.AddAttribute(constants.AttrClass, cssClass)
+		c.ApplyInlineStyles(tdTag, cssClass)
 	}
 
 	// Add background color first if specified (MRML order)
@@ -655,7 +557,7 @@ func (c *MJAccordionElementComponent) renderContent(w io.StringWriter, textCompo
 	}
 
 	// Render text content
-	if err := textComponent.Render(w); err != nil {
+	if err := c.RenderChild(textComponent, w); err != nil {
 		return err
 	}
 
@@ -698,21 +600,77 @@ func (c *MJAccordionElementComponent) GetDefaultAttribute(name string) string {
 	}
 }
 
+// accordionInheritedAttributes lists the attributes mj-accordion passes down to its
+// elements, mirroring the children attributes of MJML's accordion component.
+var accordionInheritedAttributes = map[string]struct{}{
+	constants.MJMLBorder: {},
+	"icon-align":         {},
+	"icon-width":         {},
+	"icon-height":        {},
+	"icon-position":      {},
+	"icon-wrapped-url":   {},
+	"icon-wrapped-alt":   {},
+	"icon-unwrapped-url": {},
+	"icon-unwrapped-alt": {},
+}
+
 func (c *MJAccordionElementComponent) getAttribute(name string) string {
-	// 1. Check explicit element attribute first
+	// 1. Explicit element attributes and mj-class definitions
 	if value := c.Node.GetAttribute(name); value != "" {
 		return value
 	}
+	if value := c.GetClassAttribute(name); value != "" {
+		return value
+	}
 
-	// 2. Check parent accordion attributes (but not for font-family or css-class)
-	if c.parentAccordion != nil && name != constants.MJMLFontFamily && name != constants.MJMLCSSClass {
-		if value := c.parentAccordion.Node.GetAttribute(name); value != "" {
+	// 2. Attributes passed down by the parent accordion take precedence over mj-attributes
+	if _, inherited := accordionInheritedAttributes[name]; inherited && c.parentAccordion != nil {
+		if value := c.parentAccordion.GetAttributeWithDefault(c.parentAccordion, name); value != "" {
 			return value
 		}
 	}
 
-	// 3. Fall back to component defaults
-	return c.GetDefaultAttribute(name)
+	// 3. mj-attributes, then component defaults
+	return c.GetAttributeWithDefault(c, name)
+}
+
+// accordionOwnAttribute resolves an attribute set on an accordion title or text through
+// its element attributes, mj-class or mj-attributes, ignoring component defaults so that
+// optional styles are only emitted when requested.
+func accordionOwnAttribute(comp Component, name string) string {
+	var bc *BaseComponent
+	switch v := comp.(type) {
+	case *MJAccordionTitleComponent:
+		bc = v.BaseComponent
+	case *MJAccordionTextComponent:
+		bc = v.BaseComponent
+	default:
+		return ""
+	}
+	if value := bc.Attrs[name]; value != "" {
+		return value
+	}
+	if value := bc.GetClassAttribute(name); value != "" {
+		return value
+	}
+	return bc.getGlobalAttribute(comp.GetTagName(), name)
+}
+
+// writeAccordionInnerHTML writes the HTML content of an accordion title or text.
+// Both are ending tags in MJML, so nested markup is emitted with its attributes
+// intact and whitespace collapsed the same way as mj-text content.
+func writeAccordionInnerHTML(w io.StringWriter, bc *BaseComponent) error {
+	text := &MJTextComponent{BaseComponent: bc}
+	innerHTML, err := text.buildRawInnerHTML()
+	if err != nil {
+		return err
+	}
+	innerHTML = bc.SanitizeHTML(innerHTML)
+	if innerHTML == "" {
+		return nil
+	}
+	_, err = w.WriteString(normalizeVoidHTMLTags(innerHTML))
+	return err
 }
 
 // inheritFromParent sets the parent reference for attribute inheritance
@@ -3,12 +3,15 @@ package components
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
 
 	_ "embed"
 
 	"github.com/preslavrachev/gomjml/mjml/options"
+	"github.com/preslavrachev/gomjml/mjml/styles"
 	"github.com/preslavrachev/gomjml/parser"
 )
 
@@ -19,6 +22,8 @@ var (
 	allowedAttributesOnce sync.Once
 	allowedAttributes     map[string]map[string]string
 	allowedAttributeSets  map[string]map[string]struct{}
+	allowedValueTypes     map[string]map[string]styles.ValueType
+	allowedAttributeNames map[string][]string // Sorted attribute names per component
 	allowedAttributesErr  error
 )
 
@@ -26,6 +31,8 @@ func ensureAllowedAttributesLoaded() {
 	allowedAttributesOnce.Do(func() {
 		allowedAttributes = make(map[string]map[string]string)
 		allowedAttributeSets = make(map[string]map[string]struct{})
+		allowedValueTypes = make(map[string]map[string]styles.ValueType)
+		allowedAttributeNames = make(map[string][]string)
 
 		if err := json.Unmarshal(allowedCSSAttributesJSON, &allowedAttributes); err != nil {
 			allowedAttributesErr = fmt.Errorf("failed to parse allowed CSS attributes: %w", err)
@@ -34,10 +41,18 @@ func ensureAllowedAttributesLoaded() {
 
 		for component, attrs := range allowedAttributes {
 			set := make(map[string]struct{}, len(attrs))
-			for attr := range attrs {
+			valueTypes := make(map[string]styles.ValueType)
+			for attr, typ := range attrs {
 				set[attr] = struct{}{}
+				if valueType, ok := styles.ParseValueType(typ); ok {
+					valueTypes[attr] = valueType
+				} else if _, ok := urlAttributes[attr]; ok {
+					valueTypes[attr] = styles.URLType{}
+				}
 			}
 			allowedAttributeSets[component] = set
+			allowedValueTypes[component] = valueTypes
+			allowedAttributeNames[component] = slices.Sorted(maps.Keys(set))
 		}
 	})
 
@@ -73,6 +88,14 @@ var globalAllowedAttributes = map[string]struct{}{
 	"class":     {},
 }
 
+// extensionAllowedAttributes lists gomjml-specific attributes accepted on top of
+// the MJML allowlist for a component.
+var extensionAllowedAttributes = map[string]map[string]struct{}{
+	"mj-text":   {"i18n-key": {}},
+	"mj-button": {"i18n-key": {}},
+	"mj-image":  {"srcset-widths": {}},
+}
+
 func isGloballyAllowedAttribute(attrName string) bool {
 	if attrName == "" {
 		return false
@@ -104,6 +127,44 @@ func validateComponentAttributes(node *parser.MJMLNode, opts *options.RenderOpts
 		if _, exists := allowedSet[name]; exists {
 			continue
 		}
+		if _, exists := extensionAllowedAttributes[tagName][name]; exists {
+			continue
+		}
 		opts.InvalidAttributeReporter(tagName, name, line)
 	}
 }
+
+// urlAttributes are the string attributes that hold a URL, whose values are
+// validated as URLs.
+var urlAttributes = map[string]struct{}{
+	"href":               {},
+	"src":                {},
+	"background-url":     {},
+	"icon-wrapped-url":   {},
+	"icon-unwrapped-url": {},
+	"left-icon":          {},
+	"right-icon":         {},
+}
+
+// validateAttributeValues reports attributes whose value does not match the
+// type MJML declares for them, such as "10em" for a padding that accepts px
+// and %, "middle" for an align that accepts left, center and right, or a color
+// that is not one.
+func validateAttributeValues(node *parser.MJMLNode, opts *options.RenderOpts) {
+	if node == nil || opts == nil || opts.InvalidValueReporter == nil {
+		return
+	}
+
+	ensureAllowedAttributesLoaded()
+	tagName := node.GetTagName()
+	valueTypes := allowedValueTypes[tagName]
+	for _, attr := range node.Attrs {
+		valueType, ok := valueTypes[attr.Name.Local]
+		if !ok || attr.Value == "" {
+			continue
+		}
+		if err := valueType.Validate(attr.Value); err != nil {
+			opts.InvalidValueReporter(tagName, attr.Name.Local, attr.Value, err, node.GetLineNumber())
+		}
+	}
+}
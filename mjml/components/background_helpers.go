@@ -1,9 +1,12 @@
 package components
 
 import (
+	"fmt"
 	"html"
 	"strconv"
 	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml/styles"
 )
 
 // parseBackgroundPosition converts CSS keywords/percent/length into canonical (xKeyword, yKeyword)
@@ -20,7 +23,7 @@ func parseBackgroundPosition(raw string) (string, string) {
 		}
 		return v, "center"
 	}
-	if len(toks) >= 2 {
+	if len(toks) == 2 {
 		// Determine which is x vs y similar to MJML JS logic
 		v1, v2 := toks[0], toks[1]
 		if isVertical(v1) || (v1 == "center" && isHorizontal(v2)) {
@@ -28,7 +31,9 @@ func parseBackgroundPosition(raw string) (string, string) {
 		}
 		return v1, v2
 	}
-	return "center", "center"
+	// MJML does not support edge offsets ("right 10px bottom 20px") and falls
+	// back to its default position.
+	return "center", "top"
 }
 
 func isHorizontal(v string) bool {
@@ -109,48 +114,69 @@ func computeVMLSize(size string) (sizeAttr string, aspect string) {
 }
 
 // AIDEV-NOTE: VML positioning depends on background-repeat mode - see docs/vml-background-positioning.md
-func computeVMLPosition(posX, posY, _ string, repeat string) (originX, originY, posValX, posValY string) {
+func computeVMLPosition(posX, posY, size string, repeat string) (originX, originY, posValX, posValY string) {
+	// Outlook cannot use the image's natural size with "frame", so auto-sized
+	// backgrounds are tiled and anchored at the top center like MJML does.
+	if size == "auto" {
+		return "0.5", "0", "0.5", "0"
+	}
+
 	// VML positioning depends on background-repeat mode:
 	// - repeat (tile): Direct mapping - center → 0.5
-	// - no-repeat (frame): Shifted mapping - center → 0 (0.5 - 0.5 = 0)
-	isFrameMode := repeat == "no-repeat"
-
-	mapDecimal := func(v string, _ bool) string {
-		var baseVal float64
-		switch v {
-		case "left", "top":
-			baseVal = 0
-		case "center":
-			baseVal = 0.5
-		case "right", "bottom":
-			baseVal = 1
-		default:
-			// percent (e.g. 30%) => 0.3
-			if strings.HasSuffix(v, "%") {
-				p := strings.TrimSuffix(v, "%")
-				if f, err := strconv.ParseFloat(p, 64); err == nil {
-					baseVal = f / 100.0
-				} else {
-					baseVal = 0.5 // default
-				}
-			} else {
-				baseVal = 0.5 // default
-			}
-		}
+	// - anything else (frame): Shifted mapping - center → 0 (0.5 - 0.5 = 0)
+	isTileMode := repeat == "repeat"
 
-		// Apply frame mode adjustment if needed
-		if isFrameMode {
-			baseVal = baseVal - 0.5
-		}
+	decX := vmlDecimal(backgroundPercent(posX, "left", "right", 50), isTileMode)
+	decY := vmlDecimal(backgroundPercent(posY, "top", "bottom", 0), isTileMode)
+
+	return decX, decY, decX, decY
+}
 
-		return strconv.FormatFloat(baseVal, 'f', -1, 64)
+// backgroundPercent maps a background position on one axis to a percentage.
+// Keywords map to 0/50/100; lengths such as "10px" have no VML equivalent and
+// fall back to fallback, matching MJML.
+func backgroundPercent(v, start, end string, fallback float64) float64 {
+	switch v {
+	case start:
+		return 0
+	case "center":
+		return 50
+	case end:
+		return 100
+	}
+	if l, err := styles.ParseLength(v); err == nil && l.Unit == styles.UnitPercent {
+		return l.Value
 	}
+	return fallback
+}
 
-	// Get decimal values with repeat-mode consideration
-	decX := mapDecimal(posX, true)
-	decY := mapDecimal(posY, false)
+// vmlDecimal converts a percentage to a VML origin/position value.
+func vmlDecimal(percent float64, isTileMode bool) string {
+	value := percent / 100
+	if !isTileMode {
+		value -= 0.5
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
 
-	return decX, decY, decX, decY
+// encodeBackgroundURL percent-encodes the characters that would terminate a
+// CSS url('...') or an HTML attribute early, so the same value can be used for
+// the background attribute, the inline style and the VML src.
+func encodeBackgroundURL(url string) string {
+	if !strings.ContainsAny(url, " \t\n\r\"'()\\<>") {
+		return url
+	}
+	var sb strings.Builder
+	sb.Grow(len(url) + 8)
+	for i := 0; i < len(url); i++ {
+		switch c := url[i]; c {
+		case ' ', '\t', '\n', '\r', '"', '\'', '(', ')', '\\', '<', '>':
+			fmt.Fprintf(&sb, "%%%02X", c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
 }
 
 func htmlEscape(s string) string {
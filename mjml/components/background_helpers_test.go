@@ -0,0 +1,69 @@
+package components
+
+import "testing"
+
+func TestParseBackgroundPosition(t *testing.T) {
+	tests := []struct {
+		raw          string
+		wantX, wantY string
+	}{
+		{"top", "center", "top"},
+		{"left", "left", "center"},
+		{"30%", "30%", "center"},
+		{"bottom right", "right", "bottom"},
+		{"right bottom", "right", "bottom"},
+		{"center left", "left", "center"},
+		{"center top", "center", "top"},
+		{"10px 20%", "10px", "20%"},
+		{"right 10px bottom 20px", "center", "top"},
+	}
+	for _, tt := range tests {
+		x, y := parseBackgroundPosition(tt.raw)
+		if x != tt.wantX || y != tt.wantY {
+			t.Errorf("parseBackgroundPosition(%q) = (%q, %q), want (%q, %q)", tt.raw, x, y, tt.wantX, tt.wantY)
+		}
+	}
+}
+
+func TestComputeVMLPosition(t *testing.T) {
+	tests := []struct {
+		posX, posY, size, repeat string
+		wantX, wantY             string
+	}{
+		{"center", "center", "cover", "repeat", "0.5", "0.5"},
+		{"center", "center", "cover", "no-repeat", "0", "0"},
+		{"right", "bottom", "cover", "no-repeat", "0.5", "0.5"},
+		{"left", "top", "cover", "repeat", "0", "0"},
+		{"25%", "75%", "cover", "no-repeat", "-0.25", "0.25"},
+		{"25%", "75%", "cover", "repeat", "0.25", "0.75"},
+		{"12.5%", "center", "contain", "repeat", "0.125", "0.5"},
+		// Lengths have no VML equivalent: x falls back to 50%, y to 0%.
+		{"10px", "20px", "cover", "repeat", "0.5", "0"},
+		{"10px", "20px", "cover", "no-repeat", "0", "-0.5"},
+		// Only "repeat" tiles; repeat-x and friends use the framed mapping.
+		{"center", "top", "cover", "repeat-x", "0", "-0.5"},
+		// Auto size is always tiled from the top center.
+		{"right", "bottom", "auto", "no-repeat", "0.5", "0"},
+	}
+	for _, tt := range tests {
+		originX, originY, posX, posY := computeVMLPosition(tt.posX, tt.posY, tt.size, tt.repeat)
+		if originX != tt.wantX || originY != tt.wantY || posX != tt.wantX || posY != tt.wantY {
+			t.Errorf("computeVMLPosition(%q, %q, %q, %q) = origin (%s, %s) position (%s, %s), want (%s, %s)",
+				tt.posX, tt.posY, tt.size, tt.repeat, originX, originY, posX, posY, tt.wantX, tt.wantY)
+		}
+	}
+}
+
+func TestEncodeBackgroundURL(t *testing.T) {
+	tests := map[string]string{
+		"https://example.com/bg.png":               "https://example.com/bg.png",
+		"https://example.com/a%20b.png?x=1&y=2":    "https://example.com/a%20b.png?x=1&y=2",
+		"https://example.com/my image (1).png":     "https://example.com/my%20image%20%281%29.png",
+		`https://example.com/it's"quoted"\<x>.png`: "https://example.com/it%27s%22quoted%22%5C%3Cx%3E.png",
+	}
+	for input, want := range tests {
+		if got := encodeBackgroundURL(input); got != want {
+			t.Errorf("encodeBackgroundURL(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
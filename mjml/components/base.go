@@ -5,12 +5,15 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/preslavrachev/gomjml/mjml/constants"
+	"github.com/preslavrachev/gomjml/mjml/cssmatch"
 	"github.com/preslavrachev/gomjml/mjml/debug"
 	"github.com/preslavrachev/gomjml/mjml/globals"
 	"github.com/preslavrachev/gomjml/mjml/html"
 	"github.com/preslavrachev/gomjml/mjml/options"
+	"github.com/preslavrachev/gomjml/mjml/sanitize"
 	"github.com/preslavrachev/gomjml/mjml/styles"
 	"github.com/preslavrachev/gomjml/parser"
 )
@@ -52,6 +55,9 @@ type Component interface {
 	GetSiblings() int
 	GetRawSiblings() int
 	IsRawElement() bool
+	SetAttribute(name, value string)
+	RemoveAttribute(name string)
+	Clone() (Component, error)
 }
 
 // BaseComponent provides common functionality for all components
@@ -75,34 +81,12 @@ func NewBaseComponent(node *parser.MJMLNode, opts *options.RenderOpts) *BaseComp
 		attrs[name] = normalizeAttributeValue(name, attr.Value)
 	}
 
-	var classNames []string
-	var classAttrs map[string]string
-	if classAttr, ok := attrs["mj-class"]; ok && classAttr != "" {
-		classNames = strings.Fields(classAttr)
-		if len(classNames) > 0 {
-			classAttrs = make(map[string]string)
-			cssClassParts := make([]string, 0, len(classNames)) // pre-allocate with capacity
-			for _, className := range classNames {
-				if ca := globals.GetClassAttributes(className); ca != nil {
-					for k, v := range ca {
-						if k == "css-class" {
-							cssClassParts = append(cssClassParts, v)
-							continue
-						}
-						classAttrs[k] = normalizeAttributeValue(k, v) // last class wins
-					}
-				}
-			}
-			if len(cssClassParts) > 0 {
-				classAttrs["css-class"] = strings.Join(cssClassParts, " ")
-			}
-		}
-	}
-
 	if opts == nil {
 		opts = &options.RenderOpts{}
 	}
 
+	classNames, classAttrs := resolveClassAttributes(globalAttributes(node, opts), attrs["mj-class"])
+
 	bc := &BaseComponent{
 		Node:           node,
 		Attrs:          attrs,
@@ -116,10 +100,38 @@ func NewBaseComponent(node *parser.MJMLNode, opts *options.RenderOpts) *BaseComp
 	}
 
 	validateComponentAttributes(node, opts)
+	validateAttributeValues(node, opts)
+	validateHref(node, opts)
 
 	return bc
 }
 
+// resolveClassAttributes splits an mj-class value and merges the attributes
+// of the named mj-class definitions; css-class values are concatenated.
+func resolveClassAttributes(globalAttrs *globals.GlobalAttributes, classAttr string) ([]string, map[string]string) {
+	classNames := strings.Fields(classAttr)
+	if len(classNames) == 0 {
+		return nil, nil
+	}
+	classAttrs := make(map[string]string)
+	cssClassParts := make([]string, 0, len(classNames)) // pre-allocate with capacity
+	for _, className := range classNames {
+		if ca := globalAttrs.GetClassAttributes(className); ca != nil {
+			for k, v := range ca {
+				if k == "css-class" {
+					cssClassParts = append(cssClassParts, v)
+					continue
+				}
+				classAttrs[k] = normalizeAttributeValue(k, v) // last class wins
+			}
+		}
+	}
+	if len(cssClassParts) > 0 {
+		classAttrs["css-class"] = strings.Join(cssClassParts, " ")
+	}
+	return classNames, classAttrs
+}
+
 func normalizeAttributeValue(name, value string) string {
 	if value == "" {
 		return value
@@ -132,6 +144,133 @@ func normalizeAttributeValue(name, value string) string {
 	return value
 }
 
+// GetNode returns the MJML AST node this component was created from
+func (bc *BaseComponent) GetNode() *parser.MJMLNode {
+	return bc.Node
+}
+
+// SetAttribute sets an attribute as if it had been written on the element in
+// the MJML source. The component gets its own copy of its AST node first, so
+// the parsed document, which may be shared or cached, keeps its attributes.
+func (bc *BaseComponent) SetAttribute(name, value string) {
+	bc.copyNode()
+	bc.Node.SetAttribute(name, value)
+	bc.Attrs[name] = normalizeAttributeValue(name, value)
+	if name == "mj-class" {
+		bc.classNames, bc.classAttrs = resolveClassAttributes(bc.GlobalAttributes(), value)
+	}
+}
+
+// RemoveAttribute removes an attribute set on the element, so the value
+// falls back to mj-class, mj-attributes and component defaults. Like
+// SetAttribute, it leaves the AST node the component was built from as is.
+func (bc *BaseComponent) RemoveAttribute(name string) {
+	bc.copyNode()
+	bc.Node.RemoveAttribute(name)
+	delete(bc.Attrs, name)
+	if name == "mj-class" {
+		bc.classNames, bc.classAttrs = nil, nil
+	}
+}
+
+// copyNode replaces the component's AST node with a shallow copy. The node's
+// SetAttribute and RemoveAttribute replace the attribute slice, so the copy
+// can be changed without touching the original; children are shared.
+func (bc *BaseComponent) copyNode() {
+	copied := *bc.Node
+	bc.Node = &copied
+}
+
+// treeBuilder creates a component and its descendants from an AST node. It
+// is installed by the mjml package, which maps tags to components.
+var treeBuilder func(node *parser.MJMLNode, opts *options.RenderOpts) (Component, error)
+
+// SetTreeBuilder installs the function Clone uses to rebuild components. The
+// mjml package calls it on initialization.
+func SetTreeBuilder(build func(node *parser.MJMLNode, opts *options.RenderOpts) (Component, error)) {
+	treeBuilder = build
+}
+
+// Clone returns a copy of the component and its descendants built from a
+// deep copy of its AST node, so the copy can be changed with SetAttribute
+// without affecting the original. A copied mjml root gets its own render
+// options; other copies share the original's so they can be placed back
+// into the same tree.
+func (bc *BaseComponent) Clone() (Component, error) {
+	if treeBuilder == nil {
+		return nil, fmt.Errorf("cannot clone %s: no component tree builder installed", bc.Node.GetTagName())
+	}
+	return treeBuilder(bc.Node.Clone(), bc.RenderOpts)
+}
+
+// countingWriter wraps an io.StringWriter and tracks the number of bytes written through it
+type countingWriter struct {
+	w io.StringWriter
+	n int
+}
+
+func (cw *countingWriter) WriteString(s string) (int, error) {
+	n, err := cw.w.WriteString(s)
+	cw.n += n
+	return n, err
+}
+
+// Source map markers delimit the output of every child component when a source
+// map is requested. NUL never appears in rendered HTML, so the markers cannot
+// collide with content; they are removed before the document is returned.
+const (
+	SourceMapMarkerDelimiter = "\x00"
+	SourceMapEndMarker       = SourceMapMarkerDelimiter + "-" + SourceMapMarkerDelimiter
+)
+
+// sourceMapStartMarker opens the output range of child, recording its tag and source line.
+func sourceMapStartMarker(child Component) string {
+	line := 0
+	if node, ok := child.(interface{ GetNode() *parser.MJMLNode }); ok {
+		line = node.GetNode().GetLineNumber()
+	}
+	return SourceMapMarkerDelimiter + "+" + child.GetTagName() + ":" + strconv.Itoa(line) + SourceMapMarkerDelimiter
+}
+
+// RenderChild renders a child component. When a profiler is configured, the
+// child's render duration and output size are reported along with its depth.
+// When a source map is requested, the child's output is wrapped in markers.
+func (bc *BaseComponent) RenderChild(child Component, w io.StringWriter) error {
+	if bc.RenderOpts == nil || !bc.RenderOpts.SourceMap {
+		return bc.renderChildProfiled(child, w)
+	}
+
+	if _, err := w.WriteString(sourceMapStartMarker(child)); err != nil {
+		return err
+	}
+	if err := bc.renderChildProfiled(child, w); err != nil {
+		return err
+	}
+	_, err := w.WriteString(SourceMapEndMarker)
+	return err
+}
+
+func (bc *BaseComponent) renderChildProfiled(child Component, w io.StringWriter) error {
+	opts := bc.RenderOpts
+	if opts == nil || opts.Profiler == nil {
+		return bc.renderFragment(child, w)
+	}
+
+	depth := opts.ProfileDepth
+	opts.ProfileDepth++
+	cw := &countingWriter{w: w}
+	start := time.Now()
+	err := bc.renderFragment(child, cw)
+	elapsed := time.Since(start)
+	opts.ProfileDepth--
+	if err != nil {
+		return err
+	}
+
+	opts.Profiler(child.GetTagName(), depth, elapsed, cw.n)
+	return nil
+}
+
 // IsRawElement returns whether this component should be treated as a raw element.
 // Base components are not raw by default.
 func (bc *BaseComponent) IsRawElement() bool {
@@ -179,7 +318,7 @@ func (bc *BaseComponent) GetAttributeFast(comp Component, name string) string {
 	}
 
 	// 3. Global attributes
-	if globalValue := globals.GetGlobalAttribute(comp.GetTagName(), name); globalValue != "" {
+	if globalValue := bc.getGlobalAttribute(comp.GetTagName(), name); globalValue != "" {
 		return normalizeAttributeValue(name, globalValue)
 	}
 
@@ -196,8 +335,8 @@ func (bc *BaseComponent) GetAttributeFast(comp Component, name string) string {
 func (bc *BaseComponent) GetAttributeWithDefault(comp Component, name string) string {
 	// 1. Check element attributes first
 	if value, exists := bc.Attrs[name]; exists && value != "" {
-		if debug.Enabled() {
-			debug.DebugLogWithData(comp.GetTagName(), "attr-element", "Using element attribute", map[string]interface{}{
+		if bc.logger().Enabled() {
+			bc.logger().LogWithData(comp.GetTagName(), "attr-element", "Using element attribute", map[string]interface{}{
 				"attr_name":  name,
 				"attr_value": value,
 			})
@@ -211,8 +350,8 @@ func (bc *BaseComponent) GetAttributeWithDefault(comp Component, name string) st
 
 	// 2. Check mj-class definitions
 	if classValue := bc.getClassAttribute(name); classValue != "" {
-		if debug.Enabled() {
-			debug.DebugLogWithData(comp.GetTagName(), "attr-class", "Using mj-class attribute", map[string]interface{}{
+		if bc.logger().Enabled() {
+			bc.logger().LogWithData(comp.GetTagName(), "attr-class", "Using mj-class attribute", map[string]interface{}{
 				"attr_name":  name,
 				"attr_value": classValue,
 				"classes":    bc.Attrs["mj-class"],
@@ -225,10 +364,10 @@ func (bc *BaseComponent) GetAttributeWithDefault(comp Component, name string) st
 		return classValue
 	}
 
-	// 3. Check global attributes if available (we'll get this via external function)
+	// 3. Check global attributes from mj-attributes
 	if globalValue := bc.getGlobalAttribute(comp.GetTagName(), name); globalValue != "" {
-		if debug.Enabled() {
-			debug.DebugLogWithData(comp.GetTagName(), "attr-global", "Using global attribute", map[string]interface{}{
+		if bc.logger().Enabled() {
+			bc.logger().LogWithData(comp.GetTagName(), "attr-global", "Using global attribute", map[string]interface{}{
 				"attr_name":  name,
 				"attr_value": globalValue,
 			})
@@ -243,8 +382,8 @@ func (bc *BaseComponent) GetAttributeWithDefault(comp Component, name string) st
 	// 4. Check component defaults via interface method (properly calls overridden method)
 	defaultValue := comp.GetDefaultAttribute(name)
 	if defaultValue != "" {
-		if debug.Enabled() {
-			debug.DebugLogWithData(comp.GetTagName(), "attr-default", "Using default attribute", map[string]interface{}{
+		if bc.logger().Enabled() {
+			bc.logger().LogWithData(comp.GetTagName(), "attr-default", "Using default attribute", map[string]interface{}{
 				"attr_name":  name,
 				"attr_value": defaultValue,
 			})
@@ -259,10 +398,61 @@ func (bc *BaseComponent) GetAttributeWithDefault(comp Component, name string) st
 	return ""
 }
 
-// getGlobalAttribute gets a global attribute value from the global store
+// getGlobalAttribute gets a global attribute value from the mj-attributes that apply to the element
 func (bc *BaseComponent) getGlobalAttribute(componentName, attrName string) string {
-	// Access global attributes via globals package
-	return globals.GetGlobalAttribute(componentName, attrName)
+	return bc.GlobalAttributes().GetGlobalAttribute(componentName, attrName)
+}
+
+// GlobalAttributes returns the mj-attributes and mj-class definitions that
+// apply to the element: those of the isolated include it comes from, or else
+// the document's.
+func (bc *BaseComponent) GlobalAttributes() *globals.GlobalAttributes {
+	return globalAttributes(bc.Node, bc.RenderOpts)
+}
+
+func globalAttributes(node *parser.MJMLNode, opts *options.RenderOpts) *globals.GlobalAttributes {
+	if node.AttributeScope != nil {
+		if ga, ok := opts.IncludeAttributes[node.AttributeScope]; ok {
+			return ga
+		}
+	}
+	return opts.GlobalAttributes
+}
+
+// logger returns the debug event logger of the render
+func (bc *BaseComponent) logger() debug.Logger {
+	return bc.RenderOpts.Logger
+}
+
+// escapeAttribute escapes a value from the source for an HTML attribute,
+// following the markup escaping policy of the render.
+func (bc *BaseComponent) escapeAttribute(value string) string {
+	return html.EscapeAttribute(value, bc.RenderOpts.MarkupEscaping)
+}
+
+// escapeText escapes text from the source, following the markup escaping
+// policy of the render.
+func (bc *BaseComponent) escapeText(value string) string {
+	return html.EscapeText(value, bc.RenderOpts.MarkupEscaping)
+}
+
+// escapedMixedContent returns the content of the element, including child
+// HTML elements, escaped for output.
+func (bc *BaseComponent) escapedMixedContent() string {
+	return bc.Node.GetEscapedMixedContent(bc.escapeText, bc.escapeAttribute)
+}
+
+// inlineImage returns the source that replaces an image source under the
+// render's inline image mode, or src itself when images are not inlined.
+func (bc *BaseComponent) inlineImage(src string) (string, error) {
+	if bc.RenderOpts.ImageInliner == nil {
+		return src, nil
+	}
+	inlined, err := bc.RenderOpts.ImageInliner.Inline(src)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", bc.Node.GetTagName(), err)
+	}
+	return inlined, nil
 }
 
 // getClassAttribute retrieves an attribute value from mj-class definitions if present
@@ -418,6 +608,12 @@ func (bc *BaseComponent) ApplyBackgroundStyles(tag *html.HTMLTag, comp Component
 	bgSize := bc.GetAttributeFast(comp, "background-size")
 	bgPosition := bc.GetAttributeFast(comp, "background-position")
 
+	// A gradient in either attribute is layered over its fallback color.
+	bgcolor, gradient, bgImage := styles.SplitBackground(bgcolor, bgImage)
+	if bgImage == "" {
+		bgImage = gradient
+	}
+
 	// When only a transparent background color is specified, MRML outputs only
 	// background-color without the shorthand background property.
 	if bgImage == "" && bgcolor == "transparent" {
@@ -433,6 +629,17 @@ func (bc *BaseComponent) ApplyBackgroundStyles(tag *html.HTMLTag, comp Component
 		bgPosition)
 }
 
+// BackgroundFallbackColor returns the background color for places that only
+// accept a plain color, such as bgcolor attributes and VML fills. A gradient
+// in background-color or background-url yields its first color stop.
+func (bc *BaseComponent) BackgroundFallbackColor(comp Component) string {
+	fallback, _, _ := styles.SplitBackground(
+		bc.GetAttributeWithDefault(comp, constants.MJMLBackgroundColor),
+		bc.GetAttributeWithDefault(comp, constants.MJMLBackgroundUrl),
+	)
+	return fallback
+}
+
 // ApplyBorderStyles applies border-related CSS styles to an HTML tag
 func (bc *BaseComponent) ApplyBorderStyles(tag *html.HTMLTag, comp Component) *html.HTMLTag {
 	border := bc.GetAttributeFast(comp, constants.MJMLBorder)
@@ -474,6 +681,44 @@ func (bc *BaseComponent) TrackFontFamily(fontFamily string) {
 	}
 }
 
+// SanitizeHTML runs an embedded raw HTML fragment through the configured
+// sanitization policy and removes its event handler attributes when asked to.
+// The fragment is returned unchanged when sanitization is disabled.
+func (bc *BaseComponent) SanitizeHTML(fragment string) string {
+	if !bc.SanitizesHTML() {
+		return fragment
+	}
+	if bc.RenderOpts.Sanitizer != nil {
+		fragment = bc.RenderOpts.Sanitizer.Sanitize(fragment)
+	}
+	if bc.RenderOpts.StripEventHandlers {
+		fragment = sanitize.StripEventHandlers(fragment)
+	}
+	return fragment
+}
+
+// SanitizesHTML reports whether SanitizeHTML changes embedded raw HTML
+func (bc *BaseComponent) SanitizesHTML() bool {
+	return bc.RenderOpts != nil && (bc.RenderOpts.Sanitizer != nil || bc.RenderOpts.StripEventHandlers)
+}
+
+// Localize returns the translation of the element's i18n-key for the document
+// language, or content when no translator is configured, the element has no key,
+// or the translator returns "". Translations are inserted as HTML.
+func (bc *BaseComponent) Localize(content string) string {
+	if bc.RenderOpts == nil || bc.RenderOpts.Translator == nil {
+		return content
+	}
+	key := bc.Node.GetAttribute(constants.MJMLI18nKey)
+	if key == "" {
+		return content
+	}
+	if translated := bc.RenderOpts.Translator(key, bc.RenderOpts.Lang); translated != "" {
+		return translated
+	}
+	return content
+}
+
 // ApplyFontStyles applies font-related CSS styles to an HTML tag
 func (bc *BaseComponent) ApplyFontStyles(tag *html.HTMLTag) *html.HTMLTag {
 	fontFamily := bc.GetAttribute("font-family")
@@ -513,6 +758,13 @@ func (bc *BaseComponent) ApplyDimensionStyles(tag *html.HTMLTag) *html.HTMLTag {
 	return styles.ApplyDimensionStyles(tag, width, height, minWidth, maxWidth, minHeight, maxHeight)
 }
 
+// IsRTL reports whether the document direction is right-to-left. Components
+// whose direction or alignment defaults assume left-to-right text consult it
+// so rtl documents render correctly without per-element attributes.
+func (bc *BaseComponent) IsRTL() bool {
+	return bc.RenderOpts != nil && strings.EqualFold(bc.RenderOpts.Dir, constants.DirRTL)
+}
+
 // AddDebugAttribute adds a debug attribute to an HTML tag for component traceability
 // This helps identify which MJML component generated which HTML elements during testing
 func (bc *BaseComponent) AddDebugAttribute(tag *html.HTMLTag, componentType string) {
@@ -536,11 +788,14 @@ func (bc *BaseComponent) GetCSSClass() string {
 // BuildClassAttribute combines existing CSS classes with the css-class attribute
 // Usage: component.BuildClassAttribute("mj-column-per-100", "mj-outlook-group-fix")
 func (bc *BaseComponent) BuildClassAttribute(existingClasses ...string) string {
-	// Determine css-class from element or mj-class definitions
+	// Determine css-class from the element, mj-class definitions or mj-attributes
 	cssClass := bc.GetCSSClass()
 	if cssClass == "" {
 		cssClass = bc.getClassAttribute("css-class")
 	}
+	if cssClass == "" {
+		cssClass = bc.getGlobalAttribute(bc.Node.GetTagName(), "css-class")
+	}
 
 	// Count total classes
 	total := 0
@@ -612,41 +867,39 @@ func (bc *BaseComponent) SetClassAttribute(tag *html.HTMLTag, existingClasses ..
 	return classAttr
 }
 
-// ApplyInlineStyles appends inline CSS declarations matching the provided class
-// attribute to the tag. Declarations retain their original ordering.
+// InlinesStyles reports whether the document has inline mj-style rules to
+// apply to rendered elements.
+func (bc *BaseComponent) InlinesStyles() bool {
+	return bc.RenderOpts != nil && len(bc.RenderOpts.InlineRules) > 0
+}
+
+// ApplyInlineStyles adds the inline mj-style declarations that match the tag
+// with the provided class attribute. The tag is matched without ancestors, so
+// only selectors describing the element itself apply.
+//
+// As in MJML, the declarations come first in the style attribute, in cascade
+// order, followed by the component's own styles, whether those are added
+// before or after this call. A property the component sets itself keeps the
+// component's value unless the mj-style declaration is !important.
 func (bc *BaseComponent) ApplyInlineStyles(tag *html.HTMLTag, classAttr string) {
-	if bc.RenderOpts == nil || len(bc.RenderOpts.InlineClassStyles) == 0 {
+	if !bc.InlinesStyles() {
 		return
 	}
 
-	for _, className := range strings.Fields(classAttr) {
-		if declarations, ok := bc.RenderOpts.InlineClassStyles[className]; ok {
-			for _, decl := range declarations {
-				tag.AddStyle(decl.Property, decl.Value)
-			}
-		}
+	element := cssmatch.NewElement(tag.Name(), "", classAttr, nil)
+	for _, decl := range cssmatch.Declarations(bc.RenderOpts.InlineRules, element) {
+		tag.AddRuleStyle(decl.Property, decl.Value)
 	}
 }
 
 // BuildInlineStyleString returns the serialized inline style string for the
-// provided class attribute, preserving declaration order.
-func (bc *BaseComponent) BuildInlineStyleString(classAttr string) string {
-	if bc.RenderOpts == nil || len(bc.RenderOpts.InlineClassStyles) == 0 || classAttr == "" {
+// element, with declarations in cascade order.
+func (bc *BaseComponent) BuildInlineStyleString(element cssmatch.Element) string {
+	if bc.RenderOpts == nil || len(bc.RenderOpts.InlineRules) == 0 {
 		return ""
 	}
 
-	var builder strings.Builder
-	for _, className := range strings.Fields(classAttr) {
-		if declarations, ok := bc.RenderOpts.InlineClassStyles[className]; ok {
-			for _, decl := range declarations {
-				builder.WriteString(decl.Property)
-				builder.WriteString(":")
-				builder.WriteString(decl.Value)
-				builder.WriteString(";")
-			}
-		}
-	}
-	return builder.String()
+	return serializeDeclarations(cssmatch.Declarations(bc.RenderOpts.InlineRules, element))
 }
 
 // GetMSOClassAttribute returns the MSO conditional comment class attribute with -outlook suffix
@@ -4,6 +4,7 @@ import (
 	"io"
 	"strings"
 
+	"github.com/preslavrachev/gomjml/mjml/components/defaults"
 	"github.com/preslavrachev/gomjml/mjml/constants"
 	"github.com/preslavrachev/gomjml/mjml/html"
 	"github.com/preslavrachev/gomjml/mjml/options"
@@ -66,24 +67,35 @@ func (c *MJBodyComponent) GetEffectiveWidthString() string {
 	return GetDefaultBodyWidth()
 }
 
+// bodyDir returns the direction of the body div: the document direction, so
+// inner blocks inherit it, falling back to auto.
+func (c *MJBodyComponent) bodyDir() string {
+	if c.RenderOpts.Dir != "" {
+		return c.RenderOpts.Dir
+	}
+	return constants.DirAuto
+}
+
 // Render implements optimized Writer-based rendering for MJBodyComponent
 func (c *MJBodyComponent) Render(w io.StringWriter) error {
-	backgroundColor := c.GetAttribute("background-color")
+	backgroundColor := c.GetAttributeWithDefault(c, "background-color")
 	langAttr := c.RenderOpts.Lang
 
 	// Build class attribute: just use the user's css-class if present
 	classAttr := c.BuildClassAttribute("")
 	bodyDiv := html.NewHTMLTag("div")
-	bodyDiv.AddAttribute("aria-roledescription", "email").
-		AddAttribute("role", "article")
+	if !c.RenderOpts.Features.OmitRootAccessibility {
+		bodyDiv.AddAttribute("aria-roledescription", "email").
+			AddAttribute("role", "article")
 
-	if langAttr != "" {
-		bodyDiv.AddAttribute("lang", langAttr).
-			AddAttribute("dir", constants.DirAuto)
-	}
+		if langAttr != "" {
+			bodyDiv.AddAttribute("lang", langAttr).
+				AddAttribute("dir", c.bodyDir())
+		}
 
-	if title := strings.TrimSpace(c.RenderOpts.Title); title != "" {
-		bodyDiv.AddAttribute(constants.AttrAriaLabel, title)
+		if title := strings.TrimSpace(c.RenderOpts.Title); title != "" {
+			bodyDiv.AddAttribute(constants.AttrAriaLabel, title)
+		}
 	}
 
 	if classAttr != "" {
@@ -91,8 +103,8 @@ func (c *MJBodyComponent) Render(w io.StringWriter) error {
 		c.ApplyInlineStyles(bodyDiv, classAttr)
 	}
 
-	if backgroundColor != nil && *backgroundColor != "" {
-		bodyDiv.AddStyle("background-color", *backgroundColor)
+	if backgroundColor != "" {
+		bodyDiv.AddStyle("background-color", backgroundColor)
 	}
 
 	if err := bodyDiv.RenderOpen(w); err != nil {
@@ -113,29 +125,16 @@ func (c *MJBodyComponent) Render(w io.StringWriter) error {
 		}
 	}
 
-	for _, child := range c.Children {
-		switch comp := child.(type) {
-		case *MJSectionComponent:
-			remainingBlocks--
-			if c.RenderOpts != nil {
-				c.RenderOpts.RemainingBodySections = remainingBlocks
-			}
-			if err := comp.Render(w); err != nil {
-				return err
-			}
-			continue
-		case *MJWrapperComponent:
+	for i, child := range c.Children {
+		switch child.(type) {
+		case *MJSectionComponent, *MJWrapperComponent:
 			remainingBlocks--
 			if c.RenderOpts != nil {
 				c.RenderOpts.RemainingBodySections = remainingBlocks
 			}
-			if err := comp.Render(w); err != nil {
-				return err
-			}
-			continue
 		}
 
-		if err := child.Render(w); err != nil {
+		if err := c.renderBlock(i, child, w); err != nil {
 			return err
 		}
 	}
@@ -149,13 +148,49 @@ func (c *MJBodyComponent) Render(w io.StringWriter) error {
 	return err
 }
 
-func (c *MJBodyComponent) GetDefaultAttribute(name string) string {
-	switch name {
-	case "width":
-		return DefaultBodyWidth
-	default:
-		return ""
+// renderBlock renders the top-level body child at index, reporting its output
+// size when a size reporter is configured and handing its markup to the block
+// handler before writing it.
+func (c *MJBodyComponent) renderBlock(index int, child Component, w io.StringWriter) error {
+	render := func(w io.StringWriter) error { return c.RenderChild(child, w) }
+	if c.RenderOpts != nil && c.RenderOpts.BlockRenderer != nil {
+		renderChild := render
+		render = func(w io.StringWriter) error { return c.RenderOpts.BlockRenderer(index, w, renderChild) }
+	}
+	if c.RenderOpts == nil || (c.RenderOpts.ComponentSizeReporter == nil && c.RenderOpts.BlockHandler == nil) {
+		return render(w)
 	}
+
+	line := 0
+	if node, ok := child.(interface{ GetNode() *parser.MJMLNode }); ok {
+		line = node.GetNode().GetLineNumber()
+	}
+
+	if c.RenderOpts.BlockHandler != nil {
+		var block strings.Builder
+		if err := render(&block); err != nil {
+			return err
+		}
+		if c.RenderOpts.ComponentSizeReporter != nil {
+			c.RenderOpts.ComponentSizeReporter(child.GetTagName(), line, block.Len())
+		}
+		if err := c.RenderOpts.BlockHandler(index, child.GetTagName(), line, block.String()); err != nil {
+			return err
+		}
+		_, err := w.WriteString(block.String())
+		return err
+	}
+
+	cw := &countingWriter{w: w}
+	if err := render(cw); err != nil {
+		return err
+	}
+	c.RenderOpts.ComponentSizeReporter(child.GetTagName(), line, cw.n)
+	return nil
+}
+
+func (c *MJBodyComponent) GetDefaultAttribute(name string) string {
+	return defaults.Get(c.GetTagName(), name)
 }
 
 // GetDefaultBodyWidth returns the default body width as a string with units
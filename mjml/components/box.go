@@ -0,0 +1,63 @@
+package components
+
+import (
+	"github.com/preslavrachev/gomjml/mjml/styles"
+)
+
+// boxWidths holds the horizontal padding and border widths of a component in
+// pixels. Container components use it to work out the width they pass on to
+// their children.
+type boxWidths struct {
+	paddingLeft, paddingRight int
+	borderLeft, borderRight   int
+}
+
+// horizontalBox resolves the horizontal box of comp like MJML's getBoxWidths.
+// padding names the padding shorthand, such as "padding" or "inner-padding",
+// and its -left and -right attributes take precedence over it. borders name
+// border shorthands, such as "border" and "inner-border", whose widths add up;
+// their -left and -right attributes take precedence as well, even when they
+// are "none".
+func (bc *BaseComponent) horizontalBox(comp Component, padding string, borders ...string) boxWidths {
+	var box boxWidths
+	box.paddingLeft, box.paddingRight = horizontalShorthandValues(bc.GetAttributeWithDefault(comp, padding))
+	if pl := bc.GetAttributeWithDefault(comp, padding+"-left"); pl != "" {
+		if px, ok := styles.ParsePixels(pl); ok {
+			box.paddingLeft = int(px)
+		}
+	}
+	if pr := bc.GetAttributeWithDefault(comp, padding+"-right"); pr != "" {
+		if px, ok := styles.ParsePixels(pr); ok {
+			box.paddingRight = int(px)
+		}
+	}
+
+	for _, border := range borders {
+		left := styles.ParseBorderWidth(bc.GetAttributeWithDefault(comp, border))
+		right := left
+		if bl := bc.GetAttributeWithDefault(comp, border+"-left"); bl != "" {
+			left = styles.ParseBorderWidth(bl)
+		}
+		if br := bc.GetAttributeWithDefault(comp, border+"-right"); br != "" {
+			right = styles.ParseBorderWidth(br)
+		}
+		box.borderLeft += left
+		box.borderRight += right
+	}
+	return box
+}
+
+// horizontal returns the total horizontal padding and border width.
+func (b boxWidths) horizontal() int {
+	return b.paddingLeft + b.paddingRight + b.borderLeft + b.borderRight
+}
+
+// contentWidth returns the width left for content in a box of the given outer
+// width. When padding and borders leave no room it returns width unchanged,
+// so children still get a usable width.
+func (b boxWidths) contentWidth(width int) int {
+	if content := width - b.horizontal(); content > 0 {
+		return content
+	}
+	return width
+}
@@ -0,0 +1,75 @@
+package components
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/preslavrachev/gomjml/mjml/options"
+	"github.com/preslavrachev/gomjml/parser"
+)
+
+// TestHorizontalBox checks the box model shared by the container components:
+// side attributes override their shorthands, even with zero or "none", and
+// several border attributes add up.
+func TestHorizontalBox(t *testing.T) {
+	tests := []struct {
+		name    string
+		attrs   map[string]string
+		borders []string
+		want    int // content width in a 600px box
+	}{
+		{
+			name:  "padding shorthand",
+			attrs: map[string]string{"padding": "10px 20px"},
+			want:  560,
+		},
+		{
+			name:  "three-value padding",
+			attrs: map[string]string{"padding": "10px 20px 5px"},
+			want:  560,
+		},
+		{
+			name:  "padding side overrides shorthand",
+			attrs: map[string]string{"padding": "10px 20px", "padding-left": "0px"},
+			want:  580,
+		},
+		{
+			name:    "border shorthand",
+			attrs:   map[string]string{"border": "2px solid #000"},
+			borders: []string{"border"},
+			want:    596,
+		},
+		{
+			name:    "border side overrides shorthand even when none",
+			attrs:   map[string]string{"border": "2px solid #000", "border-left": "none"},
+			borders: []string{"border"},
+			want:    598,
+		},
+		{
+			name:    "borders add up",
+			attrs:   map[string]string{"padding": "10px", "border": "2px solid #000", "inner-border": "3px solid #ccc"},
+			borders: []string{"border", "inner-border"},
+			want:    570,
+		},
+		{
+			name:  "no room falls back to the outer width",
+			attrs: map[string]string{"padding": "0 300px"},
+			want:  600,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &parser.MJMLNode{}
+			for name, value := range tt.attrs {
+				node.Attrs = append(node.Attrs, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+			}
+			component := NewMJWrapperComponent(node, &options.RenderOpts{})
+
+			box := component.horizontalBox(component, "padding", tt.borders...)
+			if got := box.contentWidth(600); got != tt.want {
+				t.Errorf("contentWidth(600) = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,10 +5,11 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/preslavrachev/gomjml/mjml/components/defaults"
 	"github.com/preslavrachev/gomjml/mjml/constants"
-	"github.com/preslavrachev/gomjml/mjml/fonts"
 	"github.com/preslavrachev/gomjml/mjml/html"
 	"github.com/preslavrachev/gomjml/mjml/options"
+	"github.com/preslavrachev/gomjml/mjml/styles"
 	"github.com/preslavrachev/gomjml/parser"
 )
 
@@ -24,45 +25,33 @@ func NewMJButtonComponent(node *parser.MJMLNode, opts *options.RenderOpts) *MJBu
 	}
 }
 
-// calculateInnerWidth calculates the inner width of the button by subtracting horizontal padding
-func (c *MJButtonComponent) calculateInnerWidth(width, innerPadding string) string {
-	if width == "" {
+// calculateInnerWidth calculates the width of the button link, mirroring MJML's
+// calculateAWidth: a pixel width minus the horizontal inner padding and the left
+// and right border widths. Widths in other units leave the link unsized.
+func (c *MJButtonComponent) calculateInnerWidth(width string) string {
+	length, err := styles.ParseLength(width)
+	if err != nil || length.Unit != styles.UnitPx {
 		return ""
 	}
+	widthVal := int(length.Value)
 
-	// Parse width (remove "px" suffix)
-	widthStr := strings.TrimSuffix(width, "px")
-	widthVal, err := strconv.Atoi(widthStr)
-	if err != nil {
-		return ""
-	}
-
-	// Parse inner-padding (format: "10px 25px" or "10px")
-	parts := strings.Fields(innerPadding)
-	if len(parts) == 0 {
-		return width
-	}
-
-	// Get horizontal padding (right padding)
-	horizontalPadding := parts[0]
-	if len(parts) >= 2 {
-		horizontalPadding = parts[1]
+	box := c.horizontalBox(c, constants.MJMLInnerPadding, constants.MJMLBorder)
+	if innerWidth := widthVal - box.horizontal(); innerWidth > 0 {
+		return strconv.Itoa(innerWidth) + "px"
 	}
+	return width
+}
 
-	// Parse horizontal padding
-	paddingStr := strings.TrimSuffix(horizontalPadding, "px")
-	paddingVal, err := strconv.Atoi(paddingStr)
+// horizontalShorthandValues returns the left and right pixel values of a CSS
+// box shorthand such as padding, accepting one to four values.
+func horizontalShorthandValues(shorthand string) (left, right int) {
+	sides, err := styles.ExpandShorthand(shorthand)
 	if err != nil {
-		return width
+		return 0, 0
 	}
-
-	// Calculate inner width (subtract padding from both sides)
-	innerWidth := widthVal - (paddingVal * 2)
-	if innerWidth <= 0 {
-		return width
-	}
-
-	return strconv.Itoa(innerWidth) + "px"
+	leftPx, _ := styles.ParsePixels(sides[3])
+	rightPx, _ := styles.ParsePixels(sides[1])
+	return int(leftPx), int(rightPx)
 }
 
 func (c *MJButtonComponent) GetTagName() string {
@@ -71,10 +60,10 @@ func (c *MJButtonComponent) GetTagName() string {
 
 // Render implements optimized Writer-based rendering for MJButtonComponent
 func (c *MJButtonComponent) Render(w io.StringWriter) error {
-	// Get text content - use GetMixedContent() to support HTML inside button
+	// Get text content with its HTML to support HTML inside button
 	// This preserves HTML tags like <strong>, <em>, etc. per MJML spec
 	// (mj-button is an "ending tag" that can contain HTML code)
-	textContent := c.Node.GetMixedContent()
+	textContent := c.Localize(c.escapedMixedContent())
 	if textContent == "" {
 		textContent = "Button"
 	}
@@ -88,7 +77,7 @@ func (c *MJButtonComponent) Render(w io.StringWriter) error {
 	padding := c.GetAttributeWithDefault(c, constants.MJMLPadding)
 	target := c.GetAttributeWithDefault(c, constants.MJMLTarget)
 	verticalAlign := c.GetAttributeWithDefault(c, constants.MJMLVerticalAlign)
-	href := c.GetAttributeWithDefault(c, constants.MJMLHref)
+	href := c.escapeAttribute(normalizeHref(c.GetAttributeWithDefault(c, constants.MJMLHref)))
 	width := c.GetAttributeWithDefault(c, constants.MJMLWidth)
 	containerBackground := c.GetAttributeWithDefault(c, constants.MJMLContainerBackgroundColor)
 	borderTop := c.GetAttributeWithDefault(c, constants.MJMLBorderTop)
@@ -110,6 +99,8 @@ func (c *MJButtonComponent) Render(w io.StringWriter) error {
 	lineHeight := c.GetAttributeWithDefault(c, constants.MJMLLineHeight)
 	textDecoration := c.GetAttributeWithDefault(c, constants.MJMLTextDecoration)
 	textTransform := c.GetAttributeWithDefault(c, "text-transform")
+	letterSpacing := c.GetAttributeWithDefault(c, "letter-spacing")
+	textAlign := c.GetAttributeWithDefault(c, constants.MJMLTextAlign)
 
 	// Determine if we use <a> or <p> tag
 	tagName := "p"
@@ -155,6 +146,19 @@ func (c *MJButtonComponent) Render(w io.StringWriter) error {
 		return err
 	}
 
+	// Optional bulletproof VML button for Outlook; other clients get the table below
+	vmlButton := false
+	if c.RenderOpts != nil && c.RenderOpts.OutlookVMLButtons && href != "" {
+		vml := c.buildOutlookVMLButton(textContent, href, width, height, innerPadding, border, borderRadius, backgroundColor,
+			color, fontFamily, fontSize, fontWeight, fontStyle, lineHeight)
+		if vml != "" {
+			vmlButton = true
+			if _, err := w.WriteString("<!--[if mso | IE]>" + vml + "<![endif]--><!--[if !mso | IE]><!-->"); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Button table structure
 	tableTag := html.NewHTMLTag("table")
 	c.AddDebugAttribute(tableTag, "button")
@@ -210,8 +214,11 @@ func (c *MJButtonComponent) Render(w io.StringWriter) error {
 	if fontStyle != "" {
 		buttonTdTag.AddStyle(constants.CSSFontStyle, fontStyle)
 	}
-	buttonTdTag.AddStyle("mso-padding-alt", innerPadding).
-		AddStyle(constants.CSSBackground, backgroundColor)
+	buttonTdTag.AddStyle("mso-padding-alt", innerPadding)
+	if textAlign != "" {
+		buttonTdTag.AddStyle(constants.CSSTextAlign, textAlign)
+	}
+	buttonTdTag.AddStyle(constants.CSSBackground, backgroundColor)
 
 	if err := buttonTdTag.RenderOpen(w); err != nil {
 		return err
@@ -221,18 +228,24 @@ func (c *MJButtonComponent) Render(w io.StringWriter) error {
 	contentTag := html.NewHTMLTag(tagName)
 	if href != "" {
 		contentTag.AddAttribute(constants.AttrHref, href)
+		if name := c.GetAttributeWithDefault(c, constants.MJMLName); name != "" {
+			contentTag.AddAttribute(constants.MJMLName, c.escapeAttribute(name))
+		}
+		if title := c.GetAttributeWithDefault(c, constants.MJMLTitle); title != "" {
+			contentTag.AddAttribute(constants.AttrTitle, c.escapeAttribute(title))
+		}
 		if target != "" {
 			contentTag.AddAttribute(constants.AttrTarget, target)
 		}
 		// Add rel attribute if specified
 		rel := c.GetAttributeWithDefault(c, constants.AttrRel)
 		if rel != "" {
-			contentTag.AddAttribute(constants.AttrRel, rel)
+			contentTag.AddAttribute(constants.AttrRel, c.escapeAttribute(rel))
 		}
 	}
 
 	// Calculate inner width for anchor tag
-	innerWidth := c.calculateInnerWidth(width, innerPadding)
+	innerWidth := c.calculateInnerWidth(width)
 
 	// Apply button content styles in MRML order
 	contentTag.AddStyle(constants.CSSDisplay, constants.DisplayInlineBlock)
@@ -252,8 +265,13 @@ func (c *MJButtonComponent) Render(w io.StringWriter) error {
 	}
 
 	contentTag.AddStyle(constants.CSSFontWeight, fontWeight).
-		AddStyle(constants.CSSLineHeight, lineHeight).
-		AddStyle(constants.CSSMargin, "0").
+		AddStyle(constants.CSSLineHeight, lineHeight)
+
+	if letterSpacing != "" {
+		contentTag.AddStyle(constants.CSSLetterSpacing, letterSpacing)
+	}
+
+	contentTag.AddStyle(constants.CSSMargin, "0").
 		AddStyle(constants.CSSTextDecoration, textDecoration).
 		AddStyle(constants.CSSTextTransform, textTransform).
 		AddStyle(constants.CSSPadding, innerPadding).
@@ -278,6 +296,11 @@ func (c *MJButtonComponent) Render(w io.StringWriter) error {
 	if err := tableTag.RenderClose(w); err != nil {
 		return err
 	}
+	if vmlButton {
+		if _, err := w.WriteString("<!--<![endif]-->"); err != nil {
+			return err
+		}
+	}
 	if err := tdTag.RenderClose(w); err != nil {
 		return err
 	}
@@ -288,41 +311,102 @@ func (c *MJButtonComponent) Render(w io.StringWriter) error {
 	return nil
 }
 
-func (c *MJButtonComponent) GetDefaultAttribute(name string) string {
-	switch name {
-	case "align":
-		return "center"
-	case "background-color":
-		return "#414141"
-	case "border":
-		return "none"
-	case "border-radius":
-		return "3px"
-	case "color":
-		return "#ffffff"
-	case "font-family":
-		return fonts.DefaultFontStack
-	case "font-size":
-		return "13px"
-	case "font-weight":
-		return "normal"
-	case "inner-padding":
-		return "10px 25px"
-	case "line-height":
-		return "120%"
-	case "padding":
-		return "10px 25px"
-	case "target":
-		return "_blank"
-	case "text-decoration":
-		return "none"
-	case "text-transform":
-		return "none"
-	case "vertical-align":
-		return "middle"
-	case "href":
+// buildOutlookVMLButton returns a v:roundrect button for Outlook desktop, whose whole
+// area is clickable unlike the padded link of the table fallback. VML needs fixed
+// dimensions, so "" is returned unless width is in pixels; height falls back to the
+// line box plus vertical inner padding and borders.
+func (c *MJButtonComponent) buildOutlookVMLButton(content, href, width, height, innerPadding, border, borderRadius,
+	backgroundColor, color, fontFamily, fontSize, fontWeight, fontStyle, lineHeight string) string {
+	widthLength, err := styles.ParseLength(width)
+	if err != nil || widthLength.Unit != styles.UnitPx || widthLength.Value < 1 {
 		return ""
-	default:
+	}
+	widthPx := int(widthLength.Value)
+
+	borderWidth := 0
+	if border != "" && border != "none" {
+		borderWidth = styles.ParseBorderWidth(border)
+	}
+
+	heightPx := 0
+	if heightLength, err := styles.ParseLength(height); err == nil && heightLength.Unit == styles.UnitPx {
+		heightPx = int(heightLength.Value)
+	}
+	if heightPx <= 0 {
+		paddingTop, paddingBottom := verticalShorthandValues(innerPadding)
+		heightPx = lineBoxHeight(fontSize, lineHeight) + paddingTop + paddingBottom + 2*borderWidth
+	}
+
+	arcsize := 0
+	if radius, err := styles.ExpandShorthand(borderRadius); err == nil {
+		if radiusPx, ok := styles.ParsePixels(radius[0]); ok && radiusPx >= 1 {
+			arcsize = min(int(radiusPx)*100/min(widthPx, heightPx), 50)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`<v:roundrect xmlns:v="urn:schemas-microsoft-com:vml" xmlns:w="urn:schemas-microsoft-com:office:word" href="`)
+	sb.WriteString(href)
+	sb.WriteString(`" style="height:` + strconv.Itoa(heightPx) + `px;v-text-anchor:middle;width:` + strconv.Itoa(widthPx) + `px;"`)
+	sb.WriteString(` arcsize="` + strconv.Itoa(arcsize) + `%"`)
+	if borderWidth > 0 {
+		sb.WriteString(` strokecolor="` + styles.ParseBorderColor(border) + `" strokeweight="` + strconv.Itoa(borderWidth) + `px"`)
+	} else {
+		sb.WriteString(` stroke="f"`)
+	}
+	sb.WriteString(` fillcolor="` + backgroundColor + `"><w:anchorlock/>`)
+
+	center := html.NewHTMLTag("center").
+		AddStyle(constants.CSSColor, color).
+		AddStyle(constants.CSSFontFamily, fontFamily).
+		AddStyle(constants.CSSFontSize, fontSize)
+	if fontStyle != "" {
+		center.AddStyle(constants.CSSFontStyle, fontStyle)
+	}
+	center.AddStyle(constants.CSSFontWeight, fontWeight)
+	if err := center.RenderOpen(&sb); err != nil {
 		return ""
 	}
+	sb.WriteString(content)
+	sb.WriteString("</center></v:roundrect>")
+
+	return sb.String()
+}
+
+// verticalShorthandValues returns the top and bottom pixel values of a CSS box
+// shorthand such as padding, accepting one to four values.
+func verticalShorthandValues(shorthand string) (top, bottom int) {
+	sides, err := styles.ExpandShorthand(shorthand)
+	if err != nil {
+		return 0, 0
+	}
+	topPx, _ := styles.ParsePixels(sides[0])
+	bottomPx, _ := styles.ParsePixels(sides[2])
+	return int(topPx), int(bottomPx)
+}
+
+// lineBoxHeight approximates the rendered height in pixels of one line of text
+// for a pixel font-size and a percentage, pixel or unitless line-height.
+func lineBoxHeight(fontSize, lineHeight string) int {
+	size, ok := styles.ParsePixels(fontSize)
+	if !ok {
+		return 0
+	}
+
+	length, err := styles.ParseLength(lineHeight)
+	if err != nil {
+		return int(size)
+	}
+	if length.Unit == styles.UnitNone {
+		// A unitless line-height is a factor of the font size
+		return int(size * length.Value)
+	}
+	if px, ok := length.ToPixels(size); ok {
+		return int(px)
+	}
+	return int(size)
+}
+
+func (c *MJButtonComponent) GetDefaultAttribute(name string) string {
+	return defaults.Get(c.GetTagName(), name)
 }
@@ -6,7 +6,10 @@ import (
 	"strings"
 	"sync/atomic"
 
+	"github.com/preslavrachev/gomjml/mjml/components/defaults"
+	"github.com/preslavrachev/gomjml/mjml/cssmatch"
 	"github.com/preslavrachev/gomjml/mjml/options"
+	"github.com/preslavrachev/gomjml/mjml/styles"
 	"github.com/preslavrachev/gomjml/parser"
 )
 
@@ -78,7 +81,7 @@ func (c *MJCarouselComponent) Render(w io.StringWriter) error {
 	}
 	inlineStyle := ""
 	if classAttr != "" {
-		inlineStyle = c.BuildInlineStyleString(classAttr)
+		inlineStyle = c.BuildInlineStyleString(cssmatch.NewElement("td", "", classAttr, nil))
 	}
 	if _, err := w.WriteString(` style="`); err != nil {
 		return err
@@ -127,36 +130,12 @@ func (c *MJCarouselComponent) GenerateCSS() string {
 }
 
 func (c *MJCarouselComponent) GetDefaultAttribute(name string) string {
-	// TODO: Consider more performant approaches to attribute matching than switch statements,
-	// such as static map[string]string lookups or compile-time generated code for components
-	// with many default attributes (10+ attributes). Switch statements may have O(n) lookup
-	// time while map lookups are O(1) average case.
 	switch name {
-	case "align":
-		return "center"
-	case "border-radius":
-		return "6px"
-	case "icon-width":
-		return "44px"
-	case "left-icon":
-		return "https://i.imgur.com/xTh3hln.png"
-	case "right-icon":
-		return "https://i.imgur.com/os7o9kz.png"
-	case "tb-border":
-		return "2px solid transparent"
-	case "tb-border-radius":
-		return "6px"
-	case "tb-hover-border-color":
-		return "#fead0d"
-	case "tb-selected-border-color":
-		return "#cccccc"
 	case "tb-width":
+		// Not an MRML default; MRML derives the thumbnail width from the images
 		return "110px"
-	case "thumbnails":
-		return "visible"
-	default:
-		return ""
 	}
+	return defaults.Get(c.GetTagName(), name)
 }
 
 // MJCarouselImageComponent represents the mj-carousel-image component
@@ -180,12 +159,7 @@ func (c *MJCarouselImageComponent) GetTagName() string {
 }
 
 func (c *MJCarouselImageComponent) GetDefaultAttribute(name string) string {
-	switch name {
-	case "target":
-		return "_blank"
-	default:
-		return ""
-	}
+	return defaults.Get(c.GetTagName(), name)
 }
 
 // buildCarouselCSS builds the carousel CSS content as a string
@@ -345,6 +319,9 @@ func (c *MJCarouselComponent) buildCarouselCSS(carouselID string, imageCount int
 	css.WriteString("    }\n")
 	css.WriteString("    \n\n")
 
+	// Fallbacks for clients without interactive support, as in MJML: clients
+	// that strip form inputs keep the inline styles, which show only the first
+	// image and hide the arrows, and Outlook on the web hides the thumbnails.
 	css.WriteString("      .mj-carousel noinput { display:block !important; }\n")
 	css.WriteString("      .mj-carousel noinput .mj-carousel-image-1 { display: block !important;  }\n")
 	css.WriteString("      .mj-carousel noinput .mj-carousel-arrows,\n")
@@ -381,6 +358,11 @@ func (c *MJCarouselComponent) generateCarouselID() string {
 		return c.id
 	}
 
+	if id := c.callerID(c.GetTagName()); id != "" {
+		c.id = id
+		return c.id
+	}
+
 	if isTestMode() {
 		idx := int(carouselTestIndex.Add(1)) - 1
 		if idx < len(carouselTestIDs) {
@@ -406,8 +388,16 @@ func (c *MJCarouselComponent) getCarouselImages() []*MJCarouselImageComponent {
 
 // renderCarouselContent renders the main carousel HTML content
 func (c *MJCarouselComponent) renderCarouselContent(w io.StringWriter, carouselID string, carouselImages []*MJCarouselImageComponent) error {
-	leftIcon := c.GetAttributeWithDefault(c, "left-icon")
-	rightIcon := c.GetAttributeWithDefault(c, "right-icon")
+	leftIcon, err := c.inlineImage(c.GetAttributeWithDefault(c, "left-icon"))
+	if err != nil {
+		return err
+	}
+	rightIcon, err := c.inlineImage(c.GetAttributeWithDefault(c, "right-icon"))
+	if err != nil {
+		return err
+	}
+	leftIcon = c.escapeAttribute(leftIcon)
+	rightIcon = c.escapeAttribute(rightIcon)
 	iconWidth := c.GetAttributeWithDefault(c, "icon-width")
 	thumbnails := c.GetAttributeWithDefault(c, "thumbnails")
 
@@ -515,6 +505,11 @@ func (c *MJCarouselComponent) renderThumbnails(w io.StringWriter, carouselID str
 		if src == "" {
 			src = img.Node.GetAttribute("src")
 		}
+		src, err := c.inlineImage(src)
+		if err != nil {
+			return err
+		}
+		src = c.escapeAttribute(src)
 		href := fmt.Sprintf("#%d", imageNum)
 		target := img.GetAttributeWithDefault(img, "target")
 
@@ -532,10 +527,10 @@ func (c *MJCarouselComponent) renderThumbnails(w io.StringWriter, carouselID str
 		}
 
 		// Thumbnail label and image
-		alt := img.Node.GetAttribute("alt")
+		alt := c.escapeAttribute(img.Node.GetAttribute("alt"))
 		altAttr := fmt.Sprintf(` alt="%s"`, alt)
 		if _, err := w.WriteString(fmt.Sprintf(`<label for="mj-carousel-%s-radio-%d"><img style="display:block;width:100%%;height:auto;" src="%s"%s width="%s"></label>`,
-			carouselID, imageNum, src, altAttr, strings.TrimSuffix(tbWidth, "px"))); err != nil {
+			carouselID, imageNum, src, altAttr, styles.HTMLDimension(tbWidth))); err != nil {
 			return err
 		}
 
@@ -613,7 +608,7 @@ func (c *MJCarouselComponent) renderPreviousIcons(w io.StringWriter, carouselID
 	iconWidth := c.GetAttributeWithDefault(c, "icon-width")
 
 	for i := 1; i <= imageCount; i++ {
-		iconWidthValue := strings.TrimSuffix(iconWidth, "px")
+		iconWidthValue := styles.HTMLDimension(iconWidth)
 		if _, err := w.WriteString(fmt.Sprintf(`<label for="mj-carousel-%s-radio-%d" class="mj-carousel-previous mj-carousel-previous-%d"><img src="%s" alt="previous" style="display:block;width:%s;height:auto;" width="%s"></label>`,
 			carouselID, i, i, leftIcon, iconWidth, iconWidthValue)); err != nil {
 			return err
@@ -635,7 +630,7 @@ func (c *MJCarouselComponent) renderNextIcons(w io.StringWriter, carouselID stri
 	iconWidth := c.GetAttributeWithDefault(c, "icon-width")
 
 	for i := 1; i <= imageCount; i++ {
-		iconWidthValue := strings.TrimSuffix(iconWidth, "px")
+		iconWidthValue := styles.HTMLDimension(iconWidth)
 		if _, err := w.WriteString(fmt.Sprintf(`<label for="mj-carousel-%s-radio-%d" class="mj-carousel-next mj-carousel-next-%d"><img src="%s" alt="next" style="display:block;width:%s;height:auto;" width="%s"></label>`,
 			carouselID, i, i, rightIcon, iconWidth, iconWidthValue)); err != nil {
 			return err
@@ -650,11 +645,15 @@ func (c *MJCarouselComponent) renderNextIcons(w io.StringWriter, carouselID stri
 
 // renderCarouselImageContent renders a single carousel image
 func (c *MJCarouselComponent) renderCarouselImageContent(w io.StringWriter, img *MJCarouselImageComponent, imageNum int, width string, isFallback bool) error {
-	src := img.Node.GetAttribute("src")
+	src, err := c.inlineImage(img.Node.GetAttribute("src"))
+	if err != nil {
+		return err
+	}
+	src = c.escapeAttribute(src)
 	borderRadius := c.GetAttributeWithDefault(c, "border-radius")
-	alt := img.Node.GetAttribute("alt")
-	title := img.Node.GetAttribute("title")
-	href := img.Node.GetAttribute("href")
+	alt := c.escapeAttribute(img.Node.GetAttribute("alt"))
+	title := c.escapeAttribute(img.Node.GetAttribute("title"))
+	href := c.escapeAttribute(img.Node.GetAttribute("href"))
 
 	// Container div with CSS classes
 	styleAttr := ""
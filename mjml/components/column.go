@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/preslavrachev/gomjml/mjml/components/defaults"
 	"github.com/preslavrachev/gomjml/mjml/constants"
 	"github.com/preslavrachev/gomjml/mjml/html"
 	"github.com/preslavrachev/gomjml/mjml/options"
@@ -35,72 +36,18 @@ func (c *MJColumnComponent) GetTagName() string {
 }
 
 // calculateEffectiveContentWidth calculates the available content width for column children
-// by subtracting the column's own padding from its actual width (not container width)
+// by subtracting the column's horizontal padding, border and inner-border from its actual
+// width (not container width), matching MJML's column box model.
 // AIDEV-NOTE: width-flow-core; column must subtract paddings before SetContainerWidth() calls to children
 func (c *MJColumnComponent) calculateEffectiveContentWidth() int {
 	// Use the column's own width, not the container width from section
 	columnWidth := c.GetWidthAsPixel()
 	containerWidth := 600 // fallback
-	if len(columnWidth) > 2 && columnWidth[len(columnWidth)-2:] == "px" {
-		// Parse without allocating substring
-		if value, err := strconv.Atoi(columnWidth[:len(columnWidth)-2]); err == nil {
-			containerWidth = value
-		}
-	}
-
-	// Get column padding (defaults to no padding) using full attribute resolution
-	padding := c.GetAttributeWithDefault(c, "padding")
-	leftPadding, rightPadding := c.parsePaddingLeftRight(padding)
-
-	// Subtract total horizontal padding
-	effectiveWidth := containerWidth - leftPadding - rightPadding
-	if effectiveWidth < 0 {
-		effectiveWidth = containerWidth // fallback
-	}
-
-	return effectiveWidth
-}
-
-// parsePaddingLeftRight parses CSS padding shorthand to get left and right padding values in pixels
-// Optimized to avoid allocations by parsing in place
-func (c *MJColumnComponent) parsePaddingLeftRight(padding string) (left, right int) {
-	if len(padding) == 0 {
-		return 0, 0
-	}
-
-	// Fast path: single value like "20px"
-	if len(padding) > 2 && padding[len(padding)-2:] == "px" {
-		// Parse without allocating substring
-		if value, err := strconv.Atoi(padding[:len(padding)-2]); err == nil {
-			return value, value // same value for all sides
-		}
-	}
-
-	// Handle "10px 25px" format by finding space separator
-	spaceIdx := -1
-	for i := 0; i < len(padding); i++ {
-		if padding[i] == ' ' {
-			spaceIdx = i
-			break
-		}
-	}
-
-	if spaceIdx > 0 {
-		// Find second token (skip spaces)
-		secondStart := spaceIdx + 1
-		for secondStart < len(padding) && padding[secondStart] == ' ' {
-			secondStart++
-		}
-
-		if secondStart < len(padding) && len(padding) > secondStart+2 && padding[len(padding)-2:] == "px" {
-			// Parse second value (left/right padding)
-			if value, err := strconv.Atoi(padding[secondStart : len(padding)-2]); err == nil {
-				return value, value
-			}
-		}
+	if width, err := styles.ParseLength(columnWidth); err == nil && width.Unit == styles.UnitPx {
+		containerWidth = int(width.Value)
 	}
 
-	return 0, 0
+	return c.horizontalBox(c, constants.MJMLPadding, constants.MJMLBorder, "inner-border").contentWidth(containerWidth)
 }
 
 // Render implements optimized Writer-based rendering for MJColumnComponent
@@ -167,9 +114,13 @@ func (c *MJColumnComponent) renderColumnToWriter(w io.StringWriter) error {
 
 // requiresSingleColumnSplit determines whether a single-column section should
 // emit the Outlook table and td wrappers as separate conditional comment
-// blocks. This matches the MJML output when components inside the column need
-// their own alignment handling (for example, mj-text align="right").
+// blocks. This matches the MRML output when components inside the column need
+// their own alignment handling (for example, mj-text align="right"); MJML 4
+// always emits them in one block.
 func (c *MJColumnComponent) requiresSingleColumnSplit() bool {
+	if c.RenderOpts != nil && c.RenderOpts.Compatibility == options.CompatMJML4 {
+		return false
+	}
 	for _, child := range c.Children {
 		switch comp := child.(type) {
 		case *MJTextComponent:
@@ -225,32 +176,34 @@ func (c *MJColumnComponent) renderColumnWithStylesToWriter(w io.StringWriter, in
 		verticalAlign := c.GetAttributeWithDefault(c, "vertical-align")
 		innerTable.AddStyle("vertical-align", verticalAlign)
 	} else {
-		// Gutter path: apply "none" border defaults to inner table to
-		// match MRML output when global attributes specify them.
-		border := c.GetAttributeFast(c, constants.MJMLBorder)
-		borderTop := c.GetAttributeFast(c, "border-top")
-		borderRight := c.GetAttributeFast(c, "border-right")
-		borderBottom := c.GetAttributeFast(c, "border-bottom")
-		borderLeft := c.GetAttributeFast(c, "border-left")
-		if border == "none" || borderTop == "none" || borderRight == "none" || borderBottom == "none" || borderLeft == "none" {
-			styles.ApplyBorderStyles(innerTable,
-				border,
-				"",
-				borderTop,
-				borderRight,
-				borderBottom,
-				borderLeft,
-			)
+		// Gutter path: the outer border and background belong to the gutter
+		// td, and the inner table takes the inner-* attributes instead, in
+		// MJML's property order.
+		innerBorder := c.GetAttributeFast(c, "inner-border")
+		innerBorderTop := c.GetAttributeFast(c, "inner-border-top")
+		innerBorderRight := c.GetAttributeFast(c, "inner-border-right")
+		innerBorderBottom := c.GetAttributeFast(c, "inner-border-bottom")
+		innerBorderLeft := c.GetAttributeFast(c, "inner-border-left")
+
+		innerTable.MaybeAddStyleString("background-color", innerBg)
+		innerTable.MaybeAddStyleString("border", innerBorder)
+		innerTable.MaybeAddStyleString("border-bottom", innerBorderBottom)
+		innerTable.MaybeAddStyleString("border-left", innerBorderLeft)
+		innerTable.MaybeAddStyleString("border-radius", innerBR)
+		innerTable.MaybeAddStyleString("border-right", innerBorderRight)
+		innerTable.MaybeAddStyleString("border-top", innerBorderTop)
+
+		// Collapsed borders ignore border-radius, so a rounded inner border
+		// needs the separate model.
+		if innerBR != "" && hasVisibleBorder(innerBorder, innerBorderTop, innerBorderRight, innerBorderBottom, innerBorderLeft) {
+			innerTable.AddStyle("border-collapse", "separate")
 		}
 	}
 
-	// Apply inner background/border overrides when present
-	if innerBg != "" {
+	// Apply inner background override when present
+	if innerBg != "" && includeStyles {
 		innerTable.AddStyle("background-color", innerBg)
 	}
-	if innerBR != "" && !includeStyles {
-		innerTable.AddStyle("border-radius", innerBR)
-	}
 
 	if err := innerTable.RenderOpen(w); err != nil {
 		return err
@@ -267,7 +220,7 @@ func (c *MJColumnComponent) renderColumnWithStylesToWriter(w io.StringWriter, in
 	for _, child := range c.Children {
 		// Set container width for child (like section does)
 		child.SetContainerWidth(effectiveWidth)
-		if err := child.Render(w); err != nil {
+		if err := c.RenderChild(child, w); err != nil {
 			return err
 		}
 	}
@@ -296,15 +249,14 @@ func (c *MJColumnComponent) GetDefaultAttribute(name string) string {
 	case "width":
 		// Follow MRML logic: if no width specified, calculate auto width
 		return c.getAutoWidthPercent()
-	case "vertical-align":
-		return "top"
-	case "direction":
-		return "ltr"
 	case "text-align":
 		return "left"
-	default:
-		return ""
+	case "direction":
+		if c.IsRTL() {
+			return constants.DirRTL
+		}
 	}
+	return defaults.Get(c.GetTagName(), name)
 }
 
 // getAutoWidthPercent calculates the auto width percentage based on MRML logic
@@ -413,14 +365,7 @@ func (c *MJColumnComponent) GetEffectiveWidth() int {
 // GetMSOTDStyles returns the styles that should be applied to the MSO conditional TD
 // This is called by the parent section component, matching MRML's set_style_td_outlook
 func (c *MJColumnComponent) GetMSOTDStyles() map[string]string {
-	getAttr := func(name string) string {
-		if attr := c.GetAttribute(name); attr != nil {
-			return *attr
-		}
-		return c.GetDefaultAttribute(name)
-	}
-
-	verticalAlign := getAttr("vertical-align")
+	verticalAlign := c.GetAttributeWithDefault(c, "vertical-align")
 
 	// Calculate pixel width for MSO conditional using MRML logic
 	msoPixelWidth := c.GetWidthAsPixel()
@@ -431,6 +376,16 @@ func (c *MJColumnComponent) GetMSOTDStyles() map[string]string {
 	}
 }
 
+// hasVisibleBorder reports whether any of the border values draws a border.
+func hasVisibleBorder(borders ...string) bool {
+	for _, border := range borders {
+		if border != "" && border != "none" && styles.ParseBorderWidth(border) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // hasGutter checks if the column has any padding attributes
 func (c *MJColumnComponent) hasGutter() bool {
 	paddingAttrs := []string{constants.MJMLPadding, constants.MJMLPaddingTop, constants.MJMLPaddingRight, constants.MJMLPaddingBottom, constants.MJMLPaddingLeft}
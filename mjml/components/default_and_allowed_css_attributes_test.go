@@ -21,7 +21,7 @@ type allowedAttributesData map[string]map[string]string
 func loadDefaultAttributesFromJSON(t *testing.T) defaultAttributesData {
 	t.Helper()
 
-	jsonFile, err := os.ReadFile("testdata/default-css-attrs.json")
+	jsonFile, err := os.ReadFile("defaults/default-css-attrs.json")
 	if err != nil {
 		t.Fatalf("Failed to read default-css-attrs.json: %v", err)
 	}
@@ -0,0 +1,27 @@
+// Package defaults holds the default attribute values of the MJML components,
+// as extracted from MRML. The data lives in default-css-attrs.json; run
+// go generate after changing it to refresh the generated lookup table.
+package defaults
+
+//go:generate go run ../../../cmd/utils/extract-defaults -json-input default-css-attrs.json -go-output defaults_gen.go
+
+// Get returns the default value of attribute name for the component tagName,
+// or "" when it has none.
+func Get(tagName, name string) string {
+	return attributes[tagName][name]
+}
+
+// Attributes returns a copy of the default attributes of the component
+// tagName, or nil when it has none.
+func Attributes(tagName string) map[string]string {
+	attrs, ok := attributes[tagName]
+	if !ok {
+		return nil
+	}
+
+	copyAttrs := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		copyAttrs[k] = v
+	}
+	return copyAttrs
+}
@@ -0,0 +1,31 @@
+package defaults
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// TestGeneratedTableMatchesJSON fails when default-css-attrs.json was changed
+// without running go generate.
+func TestGeneratedTableMatchesJSON(t *testing.T) {
+	data, err := os.ReadFile("default-css-attrs.json")
+	if err != nil {
+		t.Fatalf("failed to read default-css-attrs.json: %v", err)
+	}
+	var expected map[string]map[string]string
+	if err := json.Unmarshal(data, &expected); err != nil {
+		t.Fatalf("failed to parse default-css-attrs.json: %v", err)
+	}
+
+	if !reflect.DeepEqual(attributes, expected) {
+		t.Error("defaults_gen.go is out of date; run go generate ./mjml/components/defaults")
+	}
+	if got := Get("mj-button", "background-color"); got != "#414141" {
+		t.Errorf("Get(mj-button, background-color) = %q, want #414141", got)
+	}
+	if got := Get("mj-button", "unknown"); got != "" {
+		t.Errorf("Get(mj-button, unknown) = %q, want empty", got)
+	}
+}
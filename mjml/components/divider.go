@@ -2,9 +2,11 @@ package components
 
 import (
 	"io"
+	"math"
 	"strconv"
 	"strings"
 
+	"github.com/preslavrachev/gomjml/mjml/components/defaults"
 	"github.com/preslavrachev/gomjml/mjml/constants"
 	"github.com/preslavrachev/gomjml/mjml/html"
 	"github.com/preslavrachev/gomjml/mjml/options"
@@ -26,23 +28,10 @@ func NewMJDividerComponent(node *parser.MJMLNode, opts *options.RenderOpts) *MJD
 
 func (c *MJDividerComponent) GetDefaultAttribute(name string) string {
 	switch name {
-	case "align":
-		return "center"
-	case "border-color":
-		return "#000000"
-	case "border-style":
-		return "solid"
-	case "border-width":
-		return "4px"
 	case "container-background-color":
 		return "transparent"
-	case "padding":
-		return "10px 25px"
-	case "width":
-		return "100%"
-	default:
-		return ""
 	}
+	return defaults.Get(c.GetTagName(), name)
 }
 
 func (c *MJDividerComponent) getAttribute(name string) string {
@@ -134,7 +123,7 @@ func (c *MJDividerComponent) Render(w io.StringWriter) error {
 	}
 
 	// Parse divider padding to get accurate left + right values
-	leftPadding, rightPadding := c.parseDividerPaddingLeftRight(padding)
+	leftPadding, rightPadding := horizontalShorthandValues(padding)
 
 	// Override with individual padding attributes if present
 	if pl := c.GetAttributeFast(c, constants.MJMLPaddingLeft); pl != "" {
@@ -148,17 +137,7 @@ func (c *MJDividerComponent) Render(w io.StringWriter) error {
 		}
 	}
 
-	availableWidth := containerWidth - leftPadding - rightPadding
-	msoWidth := availableWidth
-
-	// Apply width attribute (supports percentages)
-	if size, err := styles.ParseSize(width); err == nil {
-		if size.IsPercent() {
-			msoWidth = int(float64(availableWidth) * size.Value() / 100.0)
-		} else {
-			msoWidth = int(size.Value())
-		}
-	}
+	msoWidth := dividerOutlookWidth(width, containerWidth-leftPadding-rightPadding)
 
 	// Build MSO table directly to writer to avoid fmt.Sprintf allocation
 	if _, err := w.WriteString(`<!--[if mso | IE]><table align="`); err != nil {
@@ -194,16 +173,16 @@ func (c *MJDividerComponent) Render(w io.StringWriter) error {
 	if _, err := w.WriteString(`;width:`); err != nil {
 		return err
 	}
-	if _, err := w.WriteString(strconv.Itoa(msoWidth)); err != nil {
+	if _, err := w.WriteString(msoWidth); err != nil {
 		return err
 	}
-	if _, err := w.WriteString(`px;" role="presentation" width="`); err != nil {
+	if _, err := w.WriteString(`;" role="presentation" width="`); err != nil {
 		return err
 	}
-	if _, err := w.WriteString(strconv.Itoa(msoWidth)); err != nil {
+	if _, err := w.WriteString(msoWidth); err != nil {
 		return err
 	}
-	if _, err := w.WriteString(`px" ><tr><td style="height:0;line-height:0;"> &nbsp; </td></tr></table><![endif]-->`); err != nil {
+	if _, err := w.WriteString(`" ><tr><td style="height:0;line-height:0;"> &nbsp; </td></tr></table><![endif]-->`); err != nil {
 		return err
 	}
 
@@ -221,37 +200,23 @@ func (c *MJDividerComponent) GetTagName() string {
 	return "mj-divider"
 }
 
-// parseDividerPaddingLeftRight parses CSS padding shorthand to get left and right padding values in pixels
-// Optimized to avoid allocations by parsing in place
-func (c *MJDividerComponent) parseDividerPaddingLeftRight(padding string) (left, right int) {
-	if padding == "" {
-		return 0, 0
-	}
-
-	parts := strings.Fields(padding)
-	parse := func(part string) int {
-		px, err := styles.ParsePixel(part)
-		if err != nil || px == nil {
-			return 0
+// dividerOutlookWidth returns the pixel width of the Outlook divider table.
+// Percentages apply to availableWidth (the container minus the divider's
+// horizontal padding) and may produce fractional pixels, like MJML. Pixel
+// widths are kept as written; anything else (auto, calc(), em) spans the whole
+// available width.
+func dividerOutlookWidth(width string, availableWidth int) string {
+	pixels := float64(availableWidth)
+	if size, err := styles.ParseSize(strings.TrimSpace(width)); err == nil {
+		if size.IsPercent() {
+			pixels = pixels * size.Value() / 100
+		} else {
+			pixels = size.Value()
 		}
-		return int(px.Value)
-	}
-
-	switch len(parts) {
-	case 1:
-		v := parse(parts[0])
-		return v, v
-	case 2:
-		v := parse(parts[1])
-		return v, v
-	case 3:
-		v := parse(parts[1])
-		return v, v
-	case 4:
-		return parse(parts[3]), parse(parts[1])
-	default:
-		return 0, 0
 	}
+	// Round away floating point noise such as 183.14999999999998
+	pixels = math.Round(pixels*100) / 100
+	return strconv.FormatFloat(pixels, 'f', -1, 64) + "px"
 }
 
 func (c *MJDividerComponent) marginForAlign(align string) string {
@@ -0,0 +1,27 @@
+package components
+
+import "testing"
+
+func TestDividerOutlookWidth(t *testing.T) {
+	tests := []struct {
+		width     string
+		available int
+		want      string
+	}{
+		{"100%", 550, "550px"},
+		{"50%", 550, "275px"},
+		{"33%", 550, "181.5px"},
+		{"33.3%", 550, "183.15px"},
+		{"300px", 550, "300px"},
+		{"250.5px", 550, "250.5px"},
+		{"300", 550, "300px"},
+		{"auto", 550, "550px"},
+		{"calc(100% - 20px)", 550, "550px"},
+		{"", 550, "550px"},
+	}
+	for _, tt := range tests {
+		if got := dividerOutlookWidth(tt.width, tt.available); got != tt.want {
+			t.Errorf("dividerOutlookWidth(%q, %d) = %q, want %q", tt.width, tt.available, got, tt.want)
+		}
+	}
+}
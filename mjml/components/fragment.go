@@ -0,0 +1,138 @@
+package components
+
+import (
+	"hash/maphash"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml/options"
+	"github.com/preslavrachev/gomjml/parser"
+)
+
+// fragmentTags lists the components whose markup RenderOpts.FragmentCache may
+// keep: their output depends only on their resolved attributes, their content
+// and the layout they are rendered in, and they hand out no IDs.
+var fragmentTags = map[string]bool{
+	"mj-divider": true,
+	"mj-spacer":  true,
+	"mj-social":  true,
+}
+
+var fragmentSeed = maphash.MakeSeed()
+
+// componentBase gives access to the BaseComponent every component embeds
+type componentBase interface {
+	base() *BaseComponent
+}
+
+func (bc *BaseComponent) base() *BaseComponent {
+	return bc
+}
+
+// renderFragment renders child, writing its markup from the fragment cache
+// when it is a static component rendered before with the same resolved
+// attributes, content and layout. Caching is skipped while images are inlined
+// or a source map is recorded, since both depend on more than the markup.
+func (bc *BaseComponent) renderFragment(child Component, w io.StringWriter) error {
+	opts := bc.RenderOpts
+	if opts == nil || opts.FragmentCache == nil || opts.FontTracker == nil ||
+		opts.ImageInliner != nil || opts.SourceMap || !fragmentTags[child.GetTagName()] {
+		return child.Render(w)
+	}
+
+	key := fragmentKey(child, opts)
+	if fragment, ok := opts.FragmentCache.Get(opts.TemplateHash, key); ok {
+		for _, font := range fragment.Fonts {
+			opts.FontTracker.AddFont(font)
+		}
+		_, err := w.WriteString(fragment.Markup)
+		return err
+	}
+
+	var markup strings.Builder
+	stopRecording := opts.FontTracker.Record()
+	err := child.Render(&markup)
+	fonts := stopRecording()
+	if err != nil {
+		return err
+	}
+	opts.FragmentCache.Add(opts.TemplateHash, key, options.Fragment{Markup: markup.String(), Fonts: fonts})
+	_, err = w.WriteString(markup.String())
+	return err
+}
+
+// fragmentKey identifies the markup of a static component: its resolved
+// attributes and content and those of its children, together with the layout
+// context it is rendered in.
+func fragmentKey(child Component, opts *options.RenderOpts) uint64 {
+	var h maphash.Hash
+	h.SetSeed(fragmentSeed)
+	for _, n := range []int{
+		child.GetContainerWidth(),
+		child.GetSiblings(),
+		child.GetRawSiblings(),
+		opts.GroupColumnCount,
+	} {
+		h.WriteString(strconv.Itoa(n))
+		h.WriteByte(0)
+	}
+	for _, flag := range []bool{opts.InsideGroup, opts.InsideHero, opts.InsideWrapper} {
+		h.WriteString(strconv.FormatBool(flag))
+	}
+	writeFragmentComponent(&h, child)
+	return h.Sum64()
+}
+
+func writeFragmentComponent(h *maphash.Hash, comp Component) {
+	tag := comp.GetTagName()
+	h.WriteString(tag)
+	h.WriteByte(0)
+
+	b, ok := comp.(componentBase)
+	if !ok {
+		return
+	}
+	bc := b.base()
+	ensureAllowedAttributesLoaded()
+	for _, name := range allowedAttributeNames[tag] {
+		h.WriteString(name)
+		h.WriteByte('=')
+		h.WriteString(bc.GetAttributeFast(comp, name))
+		h.WriteByte(0)
+	}
+	if bc.Node != nil {
+		writeFragmentNode(h, bc.Node)
+	}
+	for _, child := range bc.Children {
+		h.WriteByte(1)
+		writeFragmentComponent(h, child)
+	}
+	h.WriteByte(2)
+}
+
+// writeFragmentNode writes the source attributes and content of node, leaving
+// out line numbers so that fragments survive edits elsewhere in the template.
+func writeFragmentNode(h *maphash.Hash, node *parser.MJMLNode) {
+	for _, attr := range node.Attrs {
+		h.WriteString(attr.Name.Space)
+		h.WriteByte(':')
+		h.WriteString(attr.Name.Local)
+		h.WriteByte('=')
+		h.WriteString(attr.Value)
+		h.WriteByte(0)
+	}
+	h.WriteString(node.Text)
+	h.WriteByte(0)
+	for _, part := range node.MixedContent {
+		if part.Node != nil {
+			h.WriteByte(3)
+			h.WriteString(part.Node.GetTagName())
+			writeFragmentNode(h, part.Node)
+		} else {
+			h.WriteByte(4)
+			h.WriteString(part.Text)
+		}
+	}
+	h.WriteByte(5)
+}
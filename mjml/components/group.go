@@ -3,11 +3,14 @@ package components
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
+	"github.com/preslavrachev/gomjml/mjml/components/defaults"
 	"github.com/preslavrachev/gomjml/mjml/constants"
 	"github.com/preslavrachev/gomjml/mjml/html"
 	"github.com/preslavrachev/gomjml/mjml/options"
+	"github.com/preslavrachev/gomjml/mjml/styles"
 	"github.com/preslavrachev/gomjml/parser"
 )
 
@@ -45,21 +48,45 @@ func NewMJGroupComponent(node *parser.MJMLNode, opts *options.RenderOpts) *MJGro
 
 func (c *MJGroupComponent) GetDefaultAttribute(name string) string {
 	switch name {
-	case "direction":
-		return "ltr"
 	case "vertical-align":
 		return defaultVerticalAlign
 	case "width":
-		return "100%"
-	default:
-		return ""
+		// Like MJML, a group without a width shares the section equally with
+		// its non-raw siblings.
+		siblings := c.GetNonRawSiblings()
+		if siblings < 1 {
+			siblings = 1
+		}
+		return strconv.FormatFloat(100/float64(siblings), 'f', -1, 64) + "%"
+	case "direction":
+		if c.IsRTL() {
+			return constants.DirRTL
+		}
 	}
+	return defaults.Get(c.GetTagName(), name)
 }
 
 func (c *MJGroupComponent) getAttribute(name string) string {
 	return c.GetAttributeWithDefault(c, name)
 }
 
+// GetColumnClass returns the responsive class for the group's width and the
+// parsed width, like MJColumnComponent.GetColumnClass.
+func (c *MJGroupComponent) GetColumnClass() (string, styles.Size) {
+	groupWidth := c.getAttribute("width")
+	if strings.HasSuffix(groupWidth, "px") {
+		var widthPx int
+		fmt.Sscanf(groupWidth, "%dpx", &widthPx)
+		return fmt.Sprintf("mj-column-px-%d", widthPx), styles.NewPixelSize(float64(widthPx))
+	}
+
+	percent := 100.0
+	if strings.HasSuffix(groupWidth, "%") {
+		fmt.Sscanf(groupWidth, "%f%%", &percent)
+	}
+	return generateDecimalCSSClass(percent), styles.NewPercentSize(percent)
+}
+
 func (c *MJGroupComponent) GetTagName() string {
 	return "mj-group"
 }
@@ -69,7 +96,6 @@ func (c *MJGroupComponent) Render(w io.StringWriter) error {
 	direction := c.getAttribute("direction")
 	verticalAlign := c.getAttribute("vertical-align")
 	backgroundColor := c.getAttribute("background-color")
-	groupWidth := c.getAttribute("width")
 
 	// Count mj-column children to calculate width per column
 	columnCount := 0
@@ -80,30 +106,10 @@ func (c *MJGroupComponent) Render(w io.StringWriter) error {
 	}
 
 	// Determine group width based on attribute and container width
-	var widthClass string
-	var groupWidthPx int
-	var childWidthPx int
-
-	containerWidth := c.GetEffectiveWidth()
-
-	if strings.HasSuffix(groupWidth, "px") {
-		// Pixel width provided explicitly
-		fmt.Sscanf(groupWidth, "%dpx", &groupWidthPx)
-		widthClass = fmt.Sprintf("mj-column-px-%d", groupWidthPx)
-	} else if strings.HasSuffix(groupWidth, "%") {
-		// Percentage width – compute relative to container width
-		var percent float64
-		fmt.Sscanf(groupWidth, "%f%%", &percent)
-		groupWidthPx = int(float64(containerWidth) * percent / 100.0)
-		widthClass = generateDecimalCSSClass(percent)
-	} else {
-		// Fallback to 100% of container width
-		groupWidthPx = containerWidth
-		widthClass = "mj-column-per-100"
-	}
-
-	if columnCount > 0 {
-		childWidthPx = groupWidthPx / columnCount
+	widthClass, groupSize := c.GetColumnClass()
+	groupWidthPx := int(groupSize.Value())
+	if groupSize.IsPercent() {
+		groupWidthPx = int(float64(c.GetEffectiveWidth()) * groupSize.Value() / 100.0)
 	}
 
 	// Determine Outlook-specific class name (css-class + "-outlook")
@@ -153,7 +159,7 @@ func (c *MJGroupComponent) Render(w io.StringWriter) error {
 	renderedColumns := 0
 	for _, child := range c.Children {
 		if child.IsRawElement() {
-			if err := child.Render(w); err != nil {
+			if err := c.RenderChild(child, w); err != nil {
 				return err
 			}
 			continue
@@ -162,33 +168,16 @@ func (c *MJGroupComponent) Render(w io.StringWriter) error {
 			isFirstColumn := renderedColumns == 0
 			isLastColumn := renderedColumns == columnCount-1
 
-			// Set the column width based on group's width distribution
-			if columnComp.GetAttribute("width") == nil {
-				if strings.HasSuffix(groupWidth, "px") {
-					// For pixel-based groups, set pixel width for each column
-					columnComp.Attrs["width"] = getPixelWidthString(childWidthPx)
-				} else {
-					// For percentage-based groups, calculate percentage per column
-					percentagePerColumn := 100.0 / float64(columnCount)
-					percentageWidth := fmt.Sprintf("%.15f%%", percentagePerColumn)
-					// Remove trailing zeros for cleaner output
-					percentageWidth = strings.TrimRight(percentageWidth, "0")
-					percentageWidth = strings.TrimRight(percentageWidth, ".")
-					if !strings.HasSuffix(percentageWidth, "%") {
-						percentageWidth += "%"
-					}
-					columnComp.Attrs["width"] = percentageWidth
-				}
-			}
-
 			// Set mobile-width signal for MRML compatibility (like group/render.rs:93)
 			columnComp.Attrs["mobile-width"] = "mobile-width"
 
 			// Ensure child columns receive the group's full width for internal calculations
 			columnComp.SetContainerWidth(groupWidthPx)
 
-			// MSO conditional TD for each column with correct width and vertical alignment
-			msoWidth := getPixelWidthString(childWidthPx)
+			// Like MJML, each column is sized on its own: px widths are kept,
+			// percentages apply to the group width, and columns without a
+			// width take an equal share. The Outlook cell uses the same width.
+			msoWidth := columnComp.GetWidthAsPixel()
 			colVAlign := columnComp.GetAttributeWithDefault(columnComp, constants.MJMLVerticalAlign)
 
 			if err := html.RenderMSOGroupTDOpen(w, "", colVAlign, msoWidth, backgroundColor, isFirstColumn); err != nil {
@@ -202,7 +191,7 @@ func (c *MJGroupComponent) Render(w io.StringWriter) error {
 			columnComp.RenderOpts = &childOpts
 
 			// Render column content with padding support table wrapper
-			if err := child.Render(w); err != nil {
+			if err := c.RenderChild(child, w); err != nil {
 				return err
 			}
 
@@ -2,8 +2,10 @@ package components
 
 import (
 	"fmt"
+	"html"
 	"io"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/preslavrachev/gomjml/mjml/options"
 	"github.com/preslavrachev/gomjml/parser"
@@ -49,6 +51,13 @@ func (c *MJTitleComponent) Render(w io.StringWriter) error {
 	return nil // Title is handled in MJML component head processing
 }
 
+// Title returns the document title escaped for output. It is written both as
+// text and in the body's aria-label attribute, so it is escaped as an
+// attribute value.
+func (c *MJTitleComponent) Title() string {
+	return c.escapeAttribute(strings.TrimSpace(c.Node.Text))
+}
+
 func (c *MJTitleComponent) GetTagName() string {
 	return "mj-title"
 }
@@ -106,11 +115,32 @@ func (c *MJPreviewComponent) Render(w io.StringWriter) error {
 		return nil
 	}
 
-	normalizedText := strings.Join(strings.Fields(c.Node.Text), " ")
+	padTo := 0
+	if c.RenderOpts != nil {
+		padTo = c.RenderOpts.PreheaderPadding
+	}
+	return WritePreviewText(w, c.escapeText(c.Node.Text), padTo)
+}
+
+// preheaderFiller follows the preview text so mail clients show blank space
+// instead of the first lines of the body. The zero-width non-joiner keeps
+// clients from collapsing the non-breaking spaces.
+const preheaderFiller = "&nbsp;&zwnj;"
+
+// WritePreviewText writes the hidden preheader div MJML emits for mj-preview.
+// Whitespace in text is collapsed; nothing is written when text is blank.
+// When padTo exceeds the number of characters in text, one filler sequence
+// per missing character is appended.
+func WritePreviewText(w io.StringWriter, text string, padTo int) error {
+	normalizedText := strings.Join(strings.Fields(text), " ")
 	if normalizedText == "" {
 		return nil
 	}
 
+	if missing := padTo - utf8.RuneCountInString(html.UnescapeString(normalizedText)); missing > 0 {
+		normalizedText += strings.Repeat(preheaderFiller, missing)
+	}
+
 	previewHTML := fmt.Sprintf(
 		`<div style="display:none;font-size:1px;color:#ffffff;line-height:1px;max-height:0px;max-width:0px;opacity:0;overflow:hidden;">%s</div>`,
 		normalizedText,
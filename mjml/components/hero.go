@@ -3,14 +3,24 @@ package components
 import (
 	"fmt"
 	"io"
+	"math"
+	"strconv"
 	"strings"
 
+	"github.com/preslavrachev/gomjml/mjml/components/defaults"
 	"github.com/preslavrachev/gomjml/mjml/constants"
 	"github.com/preslavrachev/gomjml/mjml/html"
 	"github.com/preslavrachev/gomjml/mjml/options"
+	"github.com/preslavrachev/gomjml/mjml/styles"
 	"github.com/preslavrachev/gomjml/parser"
 )
 
+// Hero layout modes
+const (
+	HeroModeFixedHeight = "fixed-height"
+	HeroModeFluidHeight = "fluid-height"
+)
+
 // MJHeroComponent represents the mj-hero component
 type MJHeroComponent struct {
 	*BaseComponent
@@ -34,11 +44,19 @@ func (c *MJHeroComponent) Render(w io.StringWriter) error {
 	padding := c.GetAttributeWithDefault(c, constants.MJMLPadding)
 	verticalAlign := c.GetAttributeWithDefault(c, constants.MJMLVerticalAlign)
 	width := c.GetAttributeWithDefault(c, constants.MJMLWidth)
+	borderRadius := c.GetAttributeWithDefault(c, constants.MJMLBorderRadius)
+	innerBackgroundColor := c.GetAttributeWithDefault(c, constants.MJMLInnerBackgroundColor)
+	fluidHeight := c.GetAttributeWithDefault(c, constants.MJMLMode) == HeroModeFluidHeight
+
+	// A CSS gradient is not an image: Outlook gets no VML image and shows the
+	// fallback color instead.
+	backgroundColor, backgroundGradient, backgroundUrl := styles.SplitBackground(backgroundColor, backgroundUrl)
+	gradientOnly := backgroundGradient != "" && backgroundUrl == ""
 
-	// Calculate effective height by subtracting padding
+	// The content cell gets the height left inside the vertical padding
 	effectiveHeight := height
-	if height != "" && height != "0px" {
-		effectiveHeight = c.calculateEffectiveHeight(height, padding)
+	if px, ok := c.fixedHeight(height); ok {
+		effectiveHeight = fmt.Sprintf("%dpx", px)
 	}
 
 	// Calculate container width - use parent width or default 600px
@@ -69,15 +87,17 @@ func (c *MJHeroComponent) Render(w io.StringWriter) error {
 		vmlStyle = fmt.Sprintf("border:0;height:%s;mso-position-horizontal:center;position:absolute;top:0;width:%s;z-index:-3;", backgroundHeight, widthAttr)
 	}
 
-	vmlImage := html.NewHTMLTag("v:image").
-		AddAttribute(constants.AttrStyle, vmlStyle)
-	if backgroundUrl != "" {
-		vmlImage.AddAttribute("src", backgroundUrl)
-	}
-	vmlImage.AddAttribute("xmlns:v", "urn:schemas-microsoft-com:vml")
+	if !gradientOnly {
+		vmlImage := html.NewHTMLTag("v:image").
+			AddAttribute(constants.AttrStyle, vmlStyle)
+		if backgroundUrl != "" {
+			vmlImage.AddAttribute("src", c.escapeAttribute(backgroundUrl))
+		}
+		vmlImage.AddAttribute("xmlns:v", "urn:schemas-microsoft-com:vml")
 
-	if err := vmlImage.RenderSelfClosing(w); err != nil {
-		return err
+		if err := vmlImage.RenderSelfClosing(w); err != nil {
+			return err
+		}
 	}
 
 	if _, err := w.WriteString("<![endif]-->"); err != nil {
@@ -120,19 +140,40 @@ func (c *MJHeroComponent) Render(w io.StringWriter) error {
 		return err
 	}
 
+	// In fluid-height mode the hero height follows the background image aspect ratio:
+	// empty cells on either side carry a percentage padding-bottom, so the content
+	// cell has no fixed height of its own.
+	fluidRatio := ""
+	if fluidHeight {
+		fluidRatio = heroBackgroundRatio(backgroundWidth, backgroundHeight)
+		if err := renderHeroFluidCell(w, fluidRatio); err != nil {
+			return err
+		}
+	}
+
 	// Main TD with background and height using HTMLTag builder
-	tdTag := html.NewHTMLTag("td").
-		AddAttribute(constants.AttrHeight, strings.TrimSuffix(effectiveHeight, "px")).
-		AddStyle(constants.CSSBackground, backgroundColor).
-		AddStyle(constants.CSSBackgroundPosition, backgroundPosition).
-		AddStyle(constants.CSSBackgroundRepeat, backgroundRepeat).
-		AddStyle(constants.CSSPadding, padding).
-		AddStyle(constants.CSSVerticalAlign, verticalAlign).
-		AddStyle(constants.CSSHeight, effectiveHeight)
+	tdTag := html.NewHTMLTag("td")
+	if !fluidHeight {
+		tdTag.AddAttribute(constants.AttrHeight, styles.HTMLDimension(effectiveHeight))
+	}
+	tdTag.AddStyle(constants.CSSBackground, backgroundColor)
+	if gradientOnly {
+		tdTag.AddStyle(constants.CSSBackgroundImage, backgroundGradient)
+	}
+	tdTag.AddStyle(constants.CSSBackgroundPosition, backgroundPosition).
+		AddStyle(constants.CSSBackgroundRepeat, backgroundRepeat)
+	if borderRadius != "" {
+		tdTag.AddStyle(constants.CSSBorderRadius, borderRadius)
+	}
+	tdTag.AddStyle(constants.CSSPadding, padding).
+		AddStyle(constants.CSSVerticalAlign, verticalAlign)
+	if !fluidHeight {
+		tdTag.AddStyle(constants.CSSHeight, effectiveHeight)
+	}
 
 	// Add background image if provided
 	if backgroundUrl != "" {
-		tdTag.AddAttribute(constants.AttrBackground, backgroundUrl)
+		tdTag.AddAttribute(constants.AttrBackground, c.escapeAttribute(backgroundUrl))
 		// Add CSS shorthand background for modern email clients
 		shorthandBg := fmt.Sprintf("%s url('%s') %s %s / cover", backgroundColor, backgroundUrl, backgroundRepeat, backgroundPosition)
 		tdTag.AddStyle(constants.CSSBackground, shorthandBg)
@@ -161,15 +202,18 @@ func (c *MJHeroComponent) Render(w io.StringWriter) error {
 		return err
 	}
 
-	msoInnerTable := fmt.Sprintf(`<table border="0" cellpadding="0" cellspacing="0" style="width:%s;" width="%d" ><tr><td style=""><![endif]-->`, containerWidthPx, containerWidth)
+	msoInnerTable := fmt.Sprintf(`<table border="0" cellpadding="0" cellspacing="0" style="width:%s;" width="%d" ><tr><td style="%s"><![endif]-->`, containerWidthPx, containerWidth, c.outlookInnerTDStyle(innerBackgroundColor))
 	if _, err := w.WriteString(msoInnerTable); err != nil {
 		return err
 	}
 
 	// Hero content container using HTMLTag builder
 	heroContentTag := html.NewHTMLTag("div").
-		AddAttribute(constants.AttrClass, "mj-hero-content").
-		AddStyle(constants.CSSMargin, "0px auto")
+		AddAttribute(constants.AttrClass, "mj-hero-content")
+	if innerBackgroundColor != "" {
+		heroContentTag.AddStyle(constants.CSSBackgroundColor, innerBackgroundColor)
+	}
+	heroContentTag.AddStyle(constants.CSSMargin, "0px auto")
 	if width != "" {
 		heroContentTag.AddStyle(constants.CSSWidth, width)
 	}
@@ -223,16 +267,17 @@ func (c *MJHeroComponent) Render(w io.StringWriter) error {
 	}
 
 	// Render child components
+	childWidth := c.horizontalBox(c, constants.MJMLPadding).contentWidth(containerWidth)
 	for _, child := range c.Children {
 		if child.IsRawElement() {
-			if err := child.Render(w); err != nil {
+			if err := c.RenderChild(child, w); err != nil {
 				return err
 			}
 			continue
 		}
 
-		// Set container width for children
-		child.SetContainerWidth(containerWidth)
+		// Children get the width left inside the hero's horizontal padding
+		child.SetContainerWidth(childWidth)
 
 		// Set hero context for child rendering
 		childOpts := *c.RenderOpts // Copy the options
@@ -249,7 +294,7 @@ func (c *MJHeroComponent) Render(w io.StringWriter) error {
 			// Add more component types as needed
 		}
 
-		if err := child.Render(w); err != nil {
+		if err := c.RenderChild(child, w); err != nil {
 			return err
 		}
 	}
@@ -269,8 +314,18 @@ func (c *MJHeroComponent) Render(w io.StringWriter) error {
 		return err
 	}
 
-	// Close main TD, tr, tbody, table, and div
-	if _, err := w.WriteString("</td></tr></tbody></table></div>"); err != nil {
+	// Close main TD
+	if _, err := w.WriteString("</td>"); err != nil {
+		return err
+	}
+	if fluidHeight {
+		if err := renderHeroFluidCell(w, fluidRatio); err != nil {
+			return err
+		}
+	}
+
+	// Close tr, tbody, table, and div
+	if _, err := w.WriteString("</tr></tbody></table></div>"); err != nil {
 		return err
 	}
 
@@ -286,70 +341,51 @@ func (c *MJHeroComponent) GetTagName() string {
 	return "mj-hero"
 }
 
-// calculateEffectiveHeight calculates the effective height by subtracting top and bottom padding
-func (c *MJHeroComponent) calculateEffectiveHeight(height, padding string) string {
-	if height == "" || height == "0px" {
-		return height
-	}
-
-	// Parse height value
-	heightPx := strings.TrimSuffix(height, "px")
-	heightVal := 0
-	if _, err := fmt.Sscanf(heightPx, "%d", &heightVal); err != nil {
-		return height // Return original if parsing fails
-	}
-
-	// Parse padding value
-	paddingPx := strings.TrimSuffix(padding, "px")
-	paddingVal := 0
-	if paddingPx != "" && paddingPx != "0" {
-		if _, err := fmt.Sscanf(paddingPx, "%d", &paddingVal); err != nil {
-			paddingVal = 0 // Default to 0 if parsing fails
-		}
-	}
-
-	// Get individual padding overrides
-	topPadding := paddingVal
-	bottomPadding := paddingVal
-
-	if paddingTopAttr := c.GetAttribute(constants.MJMLPaddingTop); paddingTopAttr != nil {
-		topPaddingPx := strings.TrimSuffix(*paddingTopAttr, "px")
-		if _, err := fmt.Sscanf(topPaddingPx, "%d", &topPadding); err != nil {
-			topPadding = paddingVal // Fallback to general padding if parsing fails
+// outlookInnerTDStyle builds the style for the Outlook-only cell wrapping the hero
+// content, which carries the inner background color and inner padding.
+func (c *MJHeroComponent) outlookInnerTDStyle(innerBackgroundColor string) string {
+	var style strings.Builder
+	if innerBackgroundColor != "" {
+		style.WriteString("background-color:" + innerBackgroundColor + ";")
+	}
+	for _, attr := range []string{
+		constants.MJMLInnerPadding,
+		constants.MJMLInnerPadding + "-top",
+		constants.MJMLInnerPadding + "-left",
+		constants.MJMLInnerPadding + "-right",
+		constants.MJMLInnerPadding + "-bottom",
+	} {
+		if value := c.GetAttributeWithDefault(c, attr); value != "" {
+			style.WriteString(strings.TrimPrefix(attr, "inner-") + ":" + value + ";")
 		}
 	}
+	return style.String()
+}
 
-	if paddingBottomAttr := c.GetAttribute(constants.MJMLPaddingBottom); paddingBottomAttr != nil {
-		bottomPaddingPx := strings.TrimSuffix(*paddingBottomAttr, "px")
-		if _, err := fmt.Sscanf(bottomPaddingPx, "%d", &bottomPadding); err != nil {
-			bottomPadding = paddingVal // Fallback to general padding if parsing fails
-		}
+// heroBackgroundRatio returns the background height as a percentage of its width,
+// rounded to two decimals like MJML does. It returns "" when either dimension is
+// missing or not a pixel value.
+func heroBackgroundRatio(backgroundWidth, backgroundHeight string) string {
+	bgWidth, okW := styles.ParsePixels(backgroundWidth)
+	bgHeight, okH := styles.ParsePixels(backgroundHeight)
+	if !okW || !okH || bgWidth <= 0 {
+		return ""
 	}
+	ratio := math.Round(bgHeight/bgWidth*10000) / 100
+	return strconv.FormatFloat(ratio, 'f', -1, 64) + "%"
+}
 
-	// Calculate effective height = original height - top padding - bottom padding
-	effectiveHeightVal := heightVal - topPadding - bottomPadding
-	if effectiveHeightVal < 0 {
-		effectiveHeightVal = 0
+// renderHeroFluidCell writes one of the empty cells that size a fluid-height hero.
+func renderHeroFluidCell(w io.StringWriter, ratio string) error {
+	td := html.NewHTMLTag("td").
+		AddStyle(constants.CSSWidth, "0.01%")
+	if ratio != "" {
+		td.AddStyle(constants.CSSPaddingBottom, ratio).
+			AddStyle("mso-padding-bottom-alt", "0")
 	}
-
-	return fmt.Sprintf("%dpx", effectiveHeightVal)
+	return td.RenderSelfClosing(w)
 }
 
 func (c *MJHeroComponent) GetDefaultAttribute(name string) string {
-	switch name {
-	case constants.MJMLBackgroundColor:
-		return "#ffffff"
-	case constants.MJMLBackgroundPosition:
-		return "center center"
-	case constants.MJMLHeight:
-		return "0px"
-	case constants.MJMLMode:
-		return "fixed-height"
-	case constants.MJMLPadding:
-		return "0px"
-	case constants.MJMLVerticalAlign:
-		return constants.VAlignTop
-	default:
-		return ""
-	}
+	return defaults.Get(c.GetTagName(), name)
 }
@@ -0,0 +1,166 @@
+package components
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/preslavrachev/gomjml/mjml/constants"
+	"github.com/preslavrachev/gomjml/mjml/styles"
+	nethtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// averageGlyphWidth is the average advance of a glyph in the proportional
+// fonts emails use, as a fraction of the font size.
+const averageGlyphWidth = 0.5
+
+// FixedHeight returns the height in pixels of the content cell of a
+// fixed-height hero. ok is false in fluid-height mode and when the hero has
+// no height, as its cell then grows with the content.
+func (c *MJHeroComponent) FixedHeight() (int, bool) {
+	if c.GetAttributeWithDefault(c, constants.MJMLMode) == HeroModeFluidHeight {
+		return 0, false
+	}
+	height := c.GetAttributeWithDefault(c, constants.MJMLHeight)
+	if declared, err := styles.ParseLength(height); err != nil || declared.Value == 0 {
+		return 0, false
+	}
+	return c.fixedHeight(height)
+}
+
+// fixedHeight computes the content cell height like MJML: the integer part of
+// height less the top and bottom padding, whatever their units. Padding taller
+// than the hero gives a negative height, as it does in MJML. ok is false when
+// height has no numeric value.
+func (c *MJHeroComponent) fixedHeight(height string) (int, bool) {
+	length, err := styles.ParseLength(height)
+	if err != nil {
+		return 0, false
+	}
+	top, bottom := c.verticalPadding(c, constants.MJMLPadding)
+	return int(length.Value) - top - bottom, true
+}
+
+// EstimateContentHeight returns a rough estimate of the height in pixels of
+// the hero's children. Text is wrapped at an average glyph width of half its
+// font size, and block elements and line breaks in it start new lines.
+// Images without a pixel height and raw HTML only count their padding.
+func (c *MJHeroComponent) EstimateContentHeight() int {
+	height := 0
+	for _, child := range c.Children {
+		height += estimateHeight(child)
+	}
+	return height
+}
+
+// estimateHeight returns a rough estimate of the height in pixels of comp,
+// including its padding.
+func estimateHeight(comp Component) int {
+	switch v := comp.(type) {
+	case *MJTextComponent:
+		top, bottom := v.verticalPadding(v, constants.MJMLPadding)
+		lines := v.estimateLines()
+		height := lines * lineBoxHeight(v.GetAttributeWithDefault(v, constants.MJMLFontSize), v.GetAttributeWithDefault(v, constants.MJMLLineHeight))
+		if px, ok := styles.ParsePixels(v.GetAttributeWithDefault(v, constants.MJMLHeight)); ok {
+			height = max(height, int(px))
+		}
+		return top + height + bottom
+	case *MJButtonComponent:
+		top, bottom := v.verticalPadding(v, constants.MJMLPadding)
+		innerTop, innerBottom := v.verticalPadding(v, constants.MJMLInnerPadding)
+		height := innerTop + lineBoxHeight(v.GetAttributeWithDefault(v, constants.MJMLFontSize), v.GetAttributeWithDefault(v, constants.MJMLLineHeight)) + innerBottom
+		if px, ok := styles.ParsePixels(v.GetAttributeWithDefault(v, constants.MJMLHeight)); ok {
+			height = max(height, int(px))
+		}
+		return top + height + bottom
+	case *MJImageComponent:
+		top, bottom := v.verticalPadding(v, constants.MJMLPadding)
+		height, _ := styles.ParsePixels(v.GetAttributeWithDefault(v, constants.MJMLHeight))
+		return top + int(height) + bottom
+	case *MJSpacerComponent:
+		top, bottom := v.verticalPadding(v, constants.MJMLPadding)
+		height, _ := styles.ParsePixels(v.GetAttributeWithDefault(v, constants.MJMLHeight))
+		return top + int(height) + bottom
+	case *MJDividerComponent:
+		top, bottom := v.verticalPadding(v, constants.MJMLPadding)
+		height, _ := styles.ParsePixels(v.GetAttributeWithDefault(v, "border-width"))
+		return top + int(height) + bottom
+	}
+	return 0
+}
+
+// estimateLines returns the number of lines the text is likely to wrap to in
+// the width left inside its padding.
+func (c *MJTextComponent) estimateLines() int {
+	content, err := c.buildRawInnerHTML()
+	if err != nil {
+		return 0
+	}
+
+	fontSize, ok := styles.ParsePixels(c.GetAttributeWithDefault(c, constants.MJMLFontSize))
+	if !ok || fontSize <= 0 {
+		return 0
+	}
+	width := c.horizontalBox(c, constants.MJMLPadding).contentWidth(c.GetContainerWidth())
+	perLine := max(int(float64(width)/(fontSize*averageGlyphWidth)), 1)
+
+	lines := 0
+	wrap := func(paragraph string) {
+		if glyphs := utf8.RuneCountInString(strings.Join(strings.Fields(paragraph), " ")); glyphs > 0 {
+			lines += int(math.Ceil(float64(glyphs) / float64(perLine)))
+		}
+	}
+
+	var paragraph strings.Builder
+	tokenizer := nethtml.NewTokenizer(strings.NewReader(content))
+	for {
+		switch tokenizer.Next() {
+		case nethtml.ErrorToken:
+			wrap(paragraph.String())
+			return lines
+		case nethtml.TextToken:
+			paragraph.Write(tokenizer.Text())
+		case nethtml.StartTagToken, nethtml.EndTagToken, nethtml.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			switch atom.Lookup(name) {
+			case atom.Br:
+				// An empty line still takes up a line
+				if strings.TrimSpace(paragraph.String()) == "" {
+					lines++
+				}
+				fallthrough
+			case atom.P, atom.Div, atom.Li, atom.Tr, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+				wrap(paragraph.String())
+				paragraph.Reset()
+			}
+		}
+	}
+}
+
+// verticalPadding returns the top and bottom padding of comp the way MJML's
+// getShorthandAttrValue reads them: the -top and -bottom attributes take
+// precedence over the shorthand named by padding, and each value counts by
+// its number whatever its unit.
+func (bc *BaseComponent) verticalPadding(comp Component, padding string) (top, bottom int) {
+	if sides, err := styles.ExpandShorthand(bc.GetAttributeWithDefault(comp, padding)); err == nil {
+		top, bottom = leadingInt(sides[0]), leadingInt(sides[2])
+	}
+	if value := bc.GetAttributeWithDefault(comp, padding+"-top"); value != "" {
+		top = leadingInt(value)
+	}
+	if value := bc.GetAttributeWithDefault(comp, padding+"-bottom"); value != "" {
+		bottom = leadingInt(value)
+	}
+	return top, bottom
+}
+
+// leadingInt returns the integer part of the number a CSS length starts
+// with, like JavaScript's parseInt, or 0 when it has none.
+func leadingInt(value string) int {
+	length, err := styles.ParseLength(strings.TrimSpace(value))
+	if err != nil {
+		return 0
+	}
+	return int(length.Value)
+}
@@ -0,0 +1,35 @@
+package components
+
+import "testing"
+
+func TestHeroBackgroundRatio(t *testing.T) {
+	tests := []struct {
+		width, height string
+		want          string
+	}{
+		{"600px", "469px", "78.17%"},
+		{"600px", "300px", "50%"},
+		{"", "300px", ""},
+		{"100%", "300px", ""},
+	}
+	for _, tt := range tests {
+		if got := heroBackgroundRatio(tt.width, tt.height); got != tt.want {
+			t.Errorf("heroBackgroundRatio(%q, %q) = %q, want %q", tt.width, tt.height, got, tt.want)
+		}
+	}
+}
+
+func TestLeadingInt(t *testing.T) {
+	tests := map[string]int{
+		"20px":   20,
+		"10%":    10,
+		"12.9px": 12,
+		" 0 ":    0,
+		"auto":   0,
+	}
+	for value, want := range tests {
+		if got := leadingInt(value); got != want {
+			t.Errorf("leadingInt(%q) = %d, want %d", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml/constants"
+	"github.com/preslavrachev/gomjml/mjml/options"
+	"github.com/preslavrachev/gomjml/parser"
+)
+
+// hrefScheme returns the lowercased scheme of href, or "" when href is
+// relative. Like browsers, it ignores whitespace and control characters, so
+// "java\tscript:" has the scheme "javascript".
+func hrefScheme(href string) string {
+	var scheme strings.Builder
+	for i := 0; i < len(href); i++ {
+		c := href[i]
+		switch {
+		case c <= ' ':
+			continue
+		case c == ':':
+			return strings.ToLower(scheme.String())
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z',
+			scheme.Len() > 0 && ('0' <= c && c <= '9' || c == '+' || c == '-' || c == '.'):
+			scheme.WriteByte(c)
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// isWebHref reports whether href is an http(s) URL or a relative one.
+func isWebHref(href string) bool {
+	switch hrefScheme(href) {
+	case "", "http", "https":
+		return true
+	}
+	return false
+}
+
+// normalizeHref prepares a link target for an href attribute. Web URLs are
+// kept as written, like MJML does. For other schemes such as mailto:, tel: and
+// sms:, surrounding whitespace is removed, whitespace in tel: and sms: numbers
+// is dropped, and spaces, quotes and angle brackets are percent-encoded, as
+// RFC 6068 and RFC 3966 require; mail clients otherwise cut the link short at
+// the first space.
+func normalizeHref(href string) string {
+	if isWebHref(href) {
+		return href
+	}
+	href = strings.TrimSpace(href)
+
+	number, query := href, ""
+	if i := strings.IndexByte(href, '?'); i >= 0 {
+		number, query = href[:i], href[i:]
+	}
+	switch hrefScheme(href) {
+	case "tel", "sms":
+		number = strings.Join(strings.Fields(number), "")
+	}
+	return hrefEscaper.Replace(number + query)
+}
+
+var hrefEscaper = strings.NewReplacer(
+	" ", "%20",
+	"\t", "%09",
+	"\n", "%0A",
+	"\r", "%0D",
+	`"`, "%22",
+	"<", "%3C",
+	">", "%3E",
+)
+
+// validateHref reports an href that runs script. Email clients strip such
+// links, so they never work and usually point at a templating mistake.
+func validateHref(node *parser.MJMLNode, opts *options.RenderOpts) {
+	if node == nil || opts == nil || opts.UnsafeHrefReporter == nil {
+		return
+	}
+	href := node.GetAttribute(constants.MJMLHref)
+	switch hrefScheme(href) {
+	case "javascript", "vbscript":
+		opts.UnsafeHrefReporter(node.GetTagName(), href, node.GetLineNumber())
+	}
+}
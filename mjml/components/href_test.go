@@ -0,0 +1,35 @@
+package components
+
+import "testing"
+
+func TestHrefScheme(t *testing.T) {
+	tests := map[string]string{
+		"https://example.com":    "https",
+		"MAILTO:a@example.com":   "mailto",
+		"tel:+15551234567":       "tel",
+		" java\tscript:alert(1)": "javascript",
+		"/path:with-colon":       "",
+		"#top":                   "",
+		"1tel:123":               "",
+	}
+	for href, want := range tests {
+		if got := hrefScheme(href); got != want {
+			t.Errorf("hrefScheme(%q) = %q, want %q", href, got, want)
+		}
+	}
+}
+
+func TestNormalizeHref(t *testing.T) {
+	tests := map[string]string{
+		"https://example.com/a b?x=1&y=2":                "https://example.com/a b?x=1&y=2",
+		" mailto:a@example.com?subject=Hello there ":     "mailto:a@example.com?subject=Hello%20there",
+		"tel:+1 (555) 123-4567":                          "tel:+1(555)123-4567",
+		"sms:+1 555 123 4567?body=See you <soon>":        "sms:+15551234567?body=See%20you%20%3Csoon%3E",
+		`mailto:a@example.com?subject="Quoted"&cc=b@c.d`: "mailto:a@example.com?subject=%22Quoted%22&cc=b@c.d",
+	}
+	for href, want := range tests {
+		if got := normalizeHref(href); got != want {
+			t.Errorf("normalizeHref(%q) = %q, want %q", href, got, want)
+		}
+	}
+}
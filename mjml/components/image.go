@@ -3,8 +3,11 @@ package components
 import (
 	"fmt"
 	"io"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/preslavrachev/gomjml/mjml/components/defaults"
 	"github.com/preslavrachev/gomjml/mjml/constants"
 	"github.com/preslavrachev/gomjml/mjml/html"
 	"github.com/preslavrachev/gomjml/mjml/options"
@@ -35,12 +38,16 @@ func (c *MJImageComponent) Render(w io.StringWriter) error {
 	border := c.GetAttributeWithDefault(c, constants.MJMLBorder)
 	borderRadius := c.GetAttributeWithDefault(c, constants.MJMLBorderRadius)
 	height := c.GetAttributeWithDefault(c, constants.MJMLHeight)
-	href := c.GetAttributeWithDefault(c, constants.MJMLHref)
+	href := normalizeHref(c.GetAttributeWithDefault(c, constants.MJMLHref))
 	padding := c.GetAttributeWithDefault(c, constants.MJMLPadding)
 	rel := c.GetAttributeWithDefault(c, "rel")
 	src := c.GetAttributeWithDefault(c, constants.MJMLSrc)
 	target := c.GetAttributeWithDefault(c, constants.MJMLTarget)
 	title := c.GetAttributeWithDefault(c, constants.MJMLTitle)
+	name := c.GetAttributeWithDefault(c, constants.MJMLName)
+	srcset := c.GetAttributeWithDefault(c, "srcset")
+	sizes := c.GetAttributeWithDefault(c, "sizes")
+	usemap := c.GetAttributeWithDefault(c, constants.AttrUsemap)
 
 	widthAttr := c.GetAttribute("width")
 	width := ""
@@ -66,16 +73,16 @@ func (c *MJImageComponent) Render(w io.StringWriter) error {
 		return fmt.Errorf("mj-image requires src attribute")
 	}
 
-	// Parse width to remove 'px' suffix for img width attribute
-	imgWidth := width
-	if strings.HasSuffix(width, "px") {
-		imgWidth = strings.TrimSuffix(width, "px")
+	// The img width and height attributes take pixels without a unit
+	imgWidth := styles.HTMLDimension(width)
+	if srcset == "" {
+		srcset, sizes = c.responsiveSrcset(src, imgWidth, sizes, fluidOnMobile == "true")
 	}
-
-	// Parse height to remove 'px' suffix for img height attribute
-	imgHeight := height
-	if strings.HasSuffix(height, "px") {
-		imgHeight = strings.TrimSuffix(height, "px")
+	imgHeight := styles.HTMLDimension(height)
+	if height == "auto" {
+		if scaled := c.scaledIntrinsicHeight(src, imgWidth); scaled != "" {
+			imgHeight = scaled
+		}
 	}
 
 	// Create TR element
@@ -149,36 +156,56 @@ func (c *MJImageComponent) Render(w io.StringWriter) error {
 	// Optional link wrapper
 	if href != "" {
 		linkTag := html.NewHTMLTag("a").
-			AddAttribute(constants.AttrHref, href)
+			AddAttribute(constants.AttrHref, c.escapeAttribute(href))
 
 		if rel != "" {
-			linkTag.AddAttribute(constants.AttrRel, rel)
+			linkTag.AddAttribute(constants.AttrRel, c.escapeAttribute(rel))
 		}
 		if target != "" {
 			linkTag.AddAttribute(constants.AttrTarget, target)
 		}
+		if name != "" {
+			linkTag.AddAttribute(constants.MJMLName, c.escapeAttribute(name))
+		}
+		if title != "" {
+			linkTag.AddAttribute(constants.AttrTitle, c.escapeAttribute(title))
+		}
 
 		if err := linkTag.RenderOpen(w); err != nil {
 			return err
 		}
 	}
 
+	src, err := c.inlineImage(src)
+	if err != nil {
+		return err
+	}
+
 	// Image element with styles
 	imgTag := html.NewHTMLTag("img")
 	c.AddDebugAttribute(imgTag, "image")
 
 	// Set image attributes following MJML ordering.
-	imgTag.AddAttribute("alt", alt)
+	imgTag.AddAttribute("alt", c.escapeAttribute(alt))
 	if imgHeight != "" {
 		imgTag.AddAttribute(constants.AttrHeight, imgHeight)
 	}
-	imgTag.AddAttribute(constants.AttrSrc, src)
+	imgTag.AddAttribute(constants.AttrSrc, c.escapeAttribute(src))
+	if srcset != "" {
+		imgTag.AddAttribute("srcset", c.escapeAttribute(srcset))
+	}
+	if sizes != "" {
+		imgTag.AddAttribute("sizes", c.escapeAttribute(sizes))
+	}
 	if title != "" {
-		imgTag.AddAttribute(constants.AttrTitle, title)
+		imgTag.AddAttribute(constants.AttrTitle, c.escapeAttribute(title))
 	}
 	if imgWidth != "" {
 		imgTag.AddAttribute(constants.AttrWidth, imgWidth)
 	}
+	if usemap != "" {
+		imgTag.AddAttribute(constants.AttrUsemap, c.escapeAttribute(usemap))
+	}
 
 	// Apply image styles
 	imgTag.AddStyle(constants.CSSBorder, border).
@@ -225,79 +252,81 @@ func (c *MJImageComponent) Render(w io.StringWriter) error {
 
 func (c *MJImageComponent) GetDefaultAttribute(name string) string {
 	switch name {
-	case "align":
-		return "center"
-	case "alt":
-		return ""
-	case "border":
-		return "0"
-	case "border-radius":
-		return ""
-	case "font-size":
-		return "13px"
-	case "height":
-		return "auto"
-	case "href":
-		return ""
-	case "padding":
-		return "10px 25px"
-	case "rel":
-		return ""
-	case "src":
-		return ""
-	case "target":
-		return "_blank"
-	case "title":
-		return ""
 	case "width":
 		return c.calculateDefaultWidth()
 	case "fluid-on-mobile":
 		return "false"
-	case constants.MJMLContainerBackgroundColor:
-		return ""
-	default:
-		return ""
 	}
+	return defaults.Get(c.GetTagName(), name)
 }
 
-// calculateDefaultWidth calculates the default width for the image
-// based on the container width minus horizontal padding
-func (c *MJImageComponent) calculateDefaultWidth() string {
-	containerWidth := c.GetEffectiveWidth()
-
-	// Determine horizontal padding using shorthand and individual overrides
-	padding := c.GetAttributeWithDefault(c, constants.MJMLPadding)
-	left, right := 0.0, 0.0
-	if spacing, err := styles.ParseSpacing(padding); err == nil && spacing != nil {
-		left, right = spacing.Left, spacing.Right
-	} else if parts := strings.Fields(padding); len(parts) == 3 {
-		if lr, err := styles.ParsePixel(parts[1]); err == nil && lr != nil {
-			left, right = lr.Value, lr.Value
-		}
+// scaledIntrinsicHeight returns the pixel height of src at width, from the
+// intrinsic size supplied by the render's ImageSizer, or "" when it is
+// unknown. It becomes the height attribute of an image whose height is auto,
+// so clients can reserve the image's space before it loads; the CSS height
+// stays auto.
+func (c *MJImageComponent) scaledIntrinsicHeight(src, width string) string {
+	if c.RenderOpts.ImageSizer == nil {
+		return ""
 	}
-	if pl := c.GetAttributeWithDefault(c, constants.MJMLPaddingLeft); pl != "" {
-		if px, err := styles.ParsePixel(pl); err == nil && px != nil {
-			left = px.Value
-		}
+	renderedWidth, err := strconv.Atoi(width)
+	if err != nil || renderedWidth <= 0 {
+		return ""
 	}
-	if pr := c.GetAttributeWithDefault(c, constants.MJMLPaddingRight); pr != "" {
-		if px, err := styles.ParsePixel(pr); err == nil && px != nil {
-			right = px.Value
-		}
+	intrinsicWidth, intrinsicHeight, ok := c.RenderOpts.ImageSizer(src)
+	if !ok || intrinsicWidth <= 0 || intrinsicHeight <= 0 {
+		return ""
 	}
-	horizontalPadding := int(left + right)
+	return strconv.Itoa((renderedWidth*intrinsicHeight + intrinsicWidth/2) / intrinsicWidth)
+}
 
-	// Subtract border widths if present
-	if border := c.GetAttributeWithDefault(c, constants.MJMLBorder); border != "" && border != "none" {
-		bw := styles.ParseBorderWidth(border)
-		horizontalPadding += bw * 2
+// responsiveSrcset returns the srcset and sizes generated for src when the
+// render has an ImageResizer, or "" and sizes unchanged when it has none or
+// no widths apply. A sizes attribute given on the element is kept.
+func (c *MJImageComponent) responsiveSrcset(src, width, sizes string, fluidOnMobile bool) (string, string) {
+	resizer := c.RenderOpts.ImageResizer
+	if resizer == nil || c.RenderOpts.ImageInliner != nil {
+		return "", sizes
+	}
+	widths := c.RenderOpts.ResponsiveImageWidths
+	if attr := c.Node.GetAttribute("srcset-widths"); attr != "" {
+		widths = parseSrcsetWidths(attr)
+	}
+	widths = slices.DeleteFunc(slices.Clone(widths), func(w int) bool { return w <= 0 })
+	slices.Sort(widths)
+	widths = slices.Compact(widths)
+	if len(widths) == 0 {
+		return "", sizes
+	}
+
+	candidates := make([]string, len(widths))
+	for i, w := range widths {
+		candidates[i] = resizer(src, w) + " " + strconv.Itoa(w) + "w"
+	}
+	if sizes == "" && width != "" {
+		sizes = width + "px"
+		if fluidOnMobile {
+			sizes = "(max-width: 479px) 100vw, " + sizes
+		}
 	}
+	return strings.Join(candidates, ", "), sizes
+}
 
-	// Calculate available width
-	availableWidth := containerWidth - horizontalPadding
-	if availableWidth <= 0 {
-		availableWidth = containerWidth
+// parseSrcsetWidths reads a comma-separated list of pixel widths, such as
+// "320, 640px", skipping entries that are not numbers.
+func parseSrcsetWidths(value string) []int {
+	var widths []int
+	for _, part := range strings.Split(value, ",") {
+		if w, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(part), "px")); err == nil {
+			widths = append(widths, w)
+		}
 	}
+	return widths
+}
 
-	return getPixelWidthString(availableWidth)
+// calculateDefaultWidth calculates the default width for the image
+// based on the container width minus horizontal padding and borders
+func (c *MJImageComponent) calculateDefaultWidth() string {
+	box := c.horizontalBox(c, constants.MJMLPadding, constants.MJMLBorder)
+	return getPixelWidthString(box.contentWidth(c.GetEffectiveWidth()))
 }
@@ -4,7 +4,7 @@ import (
 	"strings"
 
 	"github.com/preslavrachev/gomjml/mjml/constants"
-	"github.com/preslavrachev/gomjml/mjml/options"
+	"github.com/preslavrachev/gomjml/mjml/cssmatch"
 )
 
 type inlineHTMLAttr struct {
@@ -16,19 +16,40 @@ type inlineHTMLAttr struct {
 }
 
 // ApplyInlineStylesToHTMLContent processes an HTML fragment and inlines CSS declarations
-// collected from mj-style inline rules onto elements with matching class attributes.
+// collected from mj-style inline rules onto the elements they match. Elements are
+// matched with their ancestors in the fragment, topped by the component's
+// css-class, so descendant selectors such as ".footer a" work.
 func (bc *BaseComponent) ApplyInlineStylesToHTMLContent(html string) string {
-	if html == "" || bc == nil || bc.RenderOpts == nil || len(bc.RenderOpts.InlineClassStyles) == 0 {
+	if html == "" || bc == nil || bc.RenderOpts == nil || len(bc.RenderOpts.InlineRules) == 0 {
 		return html
 	}
-	return applyInlineStylesToHTML(html, bc.RenderOpts.InlineClassStyles, bc)
+
+	var root cssmatch.Element
+	if cssClass := bc.GetCSSClass(); cssClass != "" {
+		root = cssmatch.NewElement("", "", cssClass, nil)
+	}
+	return applyInlineStylesToHTML(html, bc.RenderOpts.InlineRules, root)
 }
 
-func applyInlineStylesToHTML(html string, styles map[string][]options.InlineStyle, bc *BaseComponent) string {
+// openHTMLElement is an element of an HTML fragment whose end tag is pending.
+type openHTMLElement struct {
+	name    string
+	element cssmatch.Element
+}
+
+func applyInlineStylesToHTML(html string, rules []cssmatch.Rule, root cssmatch.Element) string {
 	if html == "" {
 		return html
 	}
 
+	var open []openHTMLElement
+	parent := func() cssmatch.Element {
+		if len(open) == 0 {
+			return root
+		}
+		return open[len(open)-1].element
+	}
+
 	var builder strings.Builder
 	builder.Grow(len(html))
 
@@ -56,6 +77,9 @@ func applyInlineStylesToHTML(html string, styles map[string][]options.InlineStyl
 				builder.WriteString(html[lt:])
 				break
 			}
+			if next == '/' {
+				open = closeHTMLElement(open, html[lt+2:end])
+			}
 			builder.WriteString(html[lt : end+1])
 			i = end + 1
 			continue
@@ -68,7 +92,10 @@ func applyInlineStylesToHTML(html string, styles map[string][]options.InlineStyl
 		}
 
 		original := html[lt : end+1]
-		rebuilt := inlineStylesInTag(original, styles, bc)
+		rebuilt, tagName, element, selfClosing := inlineStylesInTag(original, rules, parent())
+		if element != nil && !selfClosing && !isVoidHTMLElement(strings.ToLower(tagName)) {
+			open = append(open, openHTMLElement{name: tagName, element: element})
+		}
 		builder.WriteString(rebuilt)
 		i = end + 1
 	}
@@ -96,36 +123,44 @@ func findTagEnd(value string, start int) int {
 	return -1
 }
 
-func inlineStylesInTag(tag string, styles map[string][]options.InlineStyle, bc *BaseComponent) string {
-	if len(styles) == 0 {
-		return tag
+// closeHTMLElement pops the innermost open element named by an end tag along
+// with any unclosed elements inside it. Stray end tags are ignored.
+func closeHTMLElement(open []openHTMLElement, endTag string) []openHTMLElement {
+	name := strings.TrimSpace(endTag)
+	for i := len(open) - 1; i >= 0; i-- {
+		if strings.EqualFold(open[i].name, name) {
+			return open[:i]
+		}
 	}
+	return open
+}
 
+// inlineStylesInTag adds the declarations of the rules matching a start tag to
+// its style attribute. It also returns the tag's name and element, so callers
+// can track ancestors.
+func inlineStylesInTag(tag string, rules []cssmatch.Rule, parent cssmatch.Element) (string, string, cssmatch.Element, bool) {
 	tagName, attrs, selfClosing, closingSuffix := parseTag(tag)
-	if tagName == "" || len(attrs) == 0 {
-		return tag
+	if tagName == "" {
+		return tag, "", nil, false
 	}
 
-	var classValue string
-	classIndex := -1
+	var classValue, idValue string
 	styleIndex := -1
 
 	for idx, attr := range attrs {
 		if strings.EqualFold(attr.Name, constants.AttrClass) {
 			classValue = attr.Value
-			classIndex = idx
+		} else if strings.EqualFold(attr.Name, constants.AttrID) {
+			idValue = attr.Value
 		} else if strings.EqualFold(attr.Name, constants.AttrStyle) {
 			styleIndex = idx
 		}
 	}
 
-	if classIndex == -1 {
-		return tag
-	}
-
-	inlineStyle := bc.BuildInlineStyleString(classValue)
+	element := cssmatch.NewElement(tagName, idValue, classValue, parent)
+	inlineStyle := serializeDeclarations(cssmatch.Declarations(rules, element))
 	if inlineStyle == "" {
-		return tag
+		return tag, tagName, element, selfClosing
 	}
 
 	if styleIndex >= 0 {
@@ -162,6 +197,17 @@ func inlineStylesInTag(tag string, styles map[string][]options.InlineStyle, bc *
 		builder.WriteString(closingSuffix)
 	}
 	builder.WriteByte('>')
+	return builder.String(), tagName, element, selfClosing
+}
+
+func serializeDeclarations(declarations []cssmatch.Declaration) string {
+	var builder strings.Builder
+	for _, decl := range declarations {
+		builder.WriteString(decl.Property)
+		builder.WriteString(":")
+		builder.WriteString(decl.Value)
+		builder.WriteString(";")
+	}
 	return builder.String()
 }
 
@@ -3,10 +3,11 @@ package components
 import (
 	"io"
 	"strings"
+	"sync"
 	"sync/atomic"
 
+	"github.com/preslavrachev/gomjml/mjml/components/defaults"
 	"github.com/preslavrachev/gomjml/mjml/constants"
-	"github.com/preslavrachev/gomjml/mjml/fonts"
 	"github.com/preslavrachev/gomjml/mjml/html"
 	"github.com/preslavrachev/gomjml/mjml/options"
 	"github.com/preslavrachev/gomjml/parser"
@@ -15,13 +16,18 @@ import (
 // navbarTestIDs contains hardcoded IDs matching MJML fixture output for integration tests.
 // These are the actual IDs generated by the official MJML compiler for our test fixtures.
 // Only used when EnableTestMode() is called.
+// Integration tests may replace them per fixture via SetNavbarTestIDs.
 var navbarTestIDs = []string{
 	"d6c604f477854d07", // mj-navbar.html
 	"506dcbbef738f2f3", // mj-navbar-ico.html
-	"3ef6b37f9efd2ffa", // mj-navbar-multiple.html
+	"37ba61e0417d0cf9", // mj-navbar-multiple.html (first navbar)
+	"953f015148205fbf", // mj-navbar-multiple.html (second navbar)
 }
 
-var navbarTestIndex atomic.Int32
+var (
+	navbarTestIDsMu sync.Mutex
+	navbarTestIndex atomic.Int32
+)
 
 // MJNavbarComponent represents the mj-navbar component
 type MJNavbarComponent struct {
@@ -40,17 +46,15 @@ func (c *MJNavbarComponent) Render(w io.StringWriter) error {
 	baseURL := c.getAttribute("base-url")
 	hamburger := c.getAttribute("hamburger")
 
-	// Generate unique ID for checkbox
-	checkboxID := c.generateCheckboxID()
-
 	// Start table cell wrapper
 	if err := c.renderCellOpen(w, align); err != nil {
 		return err
 	}
 
-	// Render hamburger checkbox and trigger (mobile only)
+	// Render hamburger checkbox and trigger (mobile only). Each hamburger navbar gets
+	// its own checkbox ID so several menus in one email toggle independently.
 	if hamburger != "" {
-		if err := c.renderHamburgerToggle(w, checkboxID); err != nil {
+		if err := c.renderHamburgerToggle(w, c.generateCheckboxID()); err != nil {
 			return err
 		}
 	}
@@ -83,6 +87,7 @@ func (c *MJNavbarComponent) renderCellOpen(w io.StringWriter, align string) erro
 	// Only add CSS class if it's not empty
 	if cssClass := c.getAttribute(constants.MJMLCSSClass); cssClass != "" {
 		cellTag.AddAttribute(constants.AttrClass, cssClass)
+		c.ApplyInlineStyles(cellTag, cssClass)
 	}
 
 	return cellTag.RenderOpen(w)
@@ -333,10 +338,16 @@ func (c *MJNavbarComponent) renderMSOTableCellClose(w io.StringWriter, index, to
 }
 
 func (c *MJNavbarComponent) generateCheckboxID() string {
+	if id := c.callerID(c.GetTagName()); id != "" {
+		return id
+	}
 	if isTestMode() {
 		idx := int(navbarTestIndex.Add(1)) - 1
-		if idx < len(navbarTestIDs) {
-			return navbarTestIDs[idx]
+		navbarTestIDsMu.Lock()
+		ids := navbarTestIDs
+		navbarTestIDsMu.Unlock()
+		if idx < len(ids) {
+			return ids[idx]
 		}
 	}
 	return genRandomHexString(16)
@@ -355,32 +366,7 @@ func (c *MJNavbarComponent) GetTagName() string {
 }
 
 func (c *MJNavbarComponent) GetDefaultAttribute(name string) string {
-	switch name {
-	case constants.MJMLAlign:
-		return constants.AlignCenter
-	case "ico-align":
-		return constants.AlignCenter
-	case "ico-close":
-		return "&#8855;"
-	case "ico-color":
-		return "#000000"
-	case "ico-font-family":
-		return fonts.DefaultFontStack
-	case "ico-font-size":
-		return "30px"
-	case "ico-line-height":
-		return "30px"
-	case "ico-open":
-		return "&#9776;"
-	case "ico-padding":
-		return "10px"
-	case "ico-text-decoration":
-		return constants.TextDecorationNone
-	case "ico-text-transform":
-		return "uppercase"
-	default:
-		return ""
-	}
+	return defaults.Get(c.GetTagName(), name)
 }
 
 // MJNavbarLinkComponent represents the mj-navbar-link component
@@ -417,16 +403,10 @@ func (c *MJNavbarLinkComponent) RenderWithBaseURL(w io.StringWriter, baseURL str
 	paddingBottom := c.getAttribute(constants.MJMLPaddingBottom)
 	paddingLeft := c.getAttribute(constants.MJMLPaddingLeft)
 
-	// Build full URL by combining base URL and href
+	// MJML prefixes the navbar base-url verbatim, without normalizing slashes
 	fullHref := href
-	if baseURL != "" && href != "" {
-		if strings.HasSuffix(baseURL, "/") && strings.HasPrefix(href, "/") {
-			fullHref = baseURL + href[1:] // Remove duplicate slash
-		} else if !strings.HasSuffix(baseURL, "/") && !strings.HasPrefix(href, "/") {
-			fullHref = baseURL + "/" + href // Add missing slash
-		} else {
-			fullHref = baseURL + href
-		}
+	if baseURL != "" {
+		fullHref = baseURL + href
 	}
 
 	// Build CSS class attribute
@@ -436,7 +416,7 @@ func (c *MJNavbarLinkComponent) RenderWithBaseURL(w io.StringWriter, baseURL str
 	}
 
 	linkTag := html.NewHTMLTag("a").
-		AddAttribute(constants.AttrHref, fullHref).
+		AddAttribute(constants.AttrHref, c.escapeAttribute(fullHref)).
 		AddAttribute(constants.AttrTarget, target).
 		AddAttribute(constants.AttrClass, cssClass).
 		AddStyle(constants.CSSDisplay, constants.DisplayInlineBlock).
@@ -448,10 +428,11 @@ func (c *MJNavbarLinkComponent) RenderWithBaseURL(w io.StringWriter, baseURL str
 		AddStyle(constants.CSSTextDecoration, textDecoration).
 		AddStyle("text-transform", textTransform).
 		AddStyle(constants.CSSPadding, padding)
+	c.ApplyInlineStyles(linkTag, cssClass)
 
 	// Only add rel attribute if it's not empty
 	if rel := c.getAttribute("rel"); rel != "" {
-		linkTag.AddAttribute(constants.AttrRel, rel)
+		linkTag.AddAttribute(constants.AttrRel, c.escapeAttribute(rel))
 	}
 
 	// Only add individual padding properties if they're not empty
@@ -478,7 +459,7 @@ func (c *MJNavbarLinkComponent) RenderWithBaseURL(w io.StringWriter, baseURL str
 	}
 
 	// Render link content (text)
-	content := strings.TrimSpace(c.Node.Text)
+	content := c.escapeText(strings.TrimSpace(c.Node.Text))
 	if _, err := w.WriteString(content); err != nil {
 		return err
 	}
@@ -503,26 +484,5 @@ func (c *MJNavbarLinkComponent) GetTagName() string {
 }
 
 func (c *MJNavbarLinkComponent) GetDefaultAttribute(name string) string {
-	switch name {
-	case constants.CSSColor:
-		return "#000000"
-	case constants.MJMLFontFamily:
-		return fonts.DefaultFontStack
-	case constants.MJMLFontSize:
-		return "13px"
-	case constants.MJMLFontWeight:
-		return constants.FontWeightNormal
-	case constants.MJMLLineHeight:
-		return "22px"
-	case constants.MJMLPadding:
-		return "15px 10px"
-	case constants.MJMLTarget:
-		return constants.TargetBlank
-	case constants.MJMLTextDecoration:
-		return constants.TextDecorationNone
-	case "text-transform":
-		return "uppercase"
-	default:
-		return ""
-	}
+	return defaults.Get(c.GetTagName(), name)
 }
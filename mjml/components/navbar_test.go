@@ -2,6 +2,7 @@ package components
 
 import (
 	"encoding/xml"
+	"strings"
 	"testing"
 
 	"github.com/preslavrachev/gomjml/mjml/options"
@@ -105,3 +106,65 @@ func TestNavbarIDGeneration(t *testing.T) {
 		}
 	})
 }
+
+func TestNavbarLinkBaseURLConcatenation(t *testing.T) {
+	tests := []struct {
+		baseURL, href, want string
+	}{
+		{"https://example.com", "/hello", `href="https://example.com/hello"`},
+		{"https://example.com/", "hello", `href="https://example.com/hello"`},
+		{"https://example.com/?ref=", "nav", `href="https://example.com/?ref=nav"`},
+		{"", "/hello", `href="/hello"`},
+	}
+
+	for _, tt := range tests {
+		node := &parser.MJMLNode{
+			XMLName: xml.Name{Local: "mj-navbar-link"},
+			Attrs:   []xml.Attr{{Name: xml.Name{Local: "href"}, Value: tt.href}},
+			Text:    "Link",
+		}
+		link := NewMJNavbarLinkComponent(node, &options.RenderOpts{})
+
+		var out strings.Builder
+		if err := link.RenderWithBaseURL(&out, tt.baseURL); err != nil {
+			t.Fatalf("RenderWithBaseURL() error = %v", err)
+		}
+		if !strings.Contains(out.String(), tt.want) {
+			t.Errorf("base %q + href %q: expected %s in %s", tt.baseURL, tt.href, tt.want, out.String())
+		}
+	}
+}
+
+func TestNavbarOnlyHamburgerConsumesTestIDs(t *testing.T) {
+	ctrl := testmode.LockForTesting()
+	defer ctrl.Release()
+	ctrl.Enable()
+	defer resetNavbarTestIndex()
+
+	originalIDs := navbarTestIDs
+	SetNavbarTestIDs("aaaaaaaaaaaaaaaa", "bbbbbbbbbbbbbbbb")
+	defer func() { SetNavbarTestIDs(originalIDs...) }()
+
+	opts := &options.RenderOpts{}
+	plain := NewMJNavbarComponent(&parser.MJMLNode{XMLName: xml.Name{Local: "mj-navbar"}}, opts)
+	hamburgerNode := &parser.MJMLNode{
+		XMLName: xml.Name{Local: "mj-navbar"},
+		Attrs:   []xml.Attr{{Name: xml.Name{Local: "hamburger"}, Value: "hamburger"}},
+	}
+	first := NewMJNavbarComponent(hamburgerNode, opts)
+	second := NewMJNavbarComponent(hamburgerNode, opts)
+
+	var out strings.Builder
+	for _, navbar := range []*MJNavbarComponent{plain, first, second} {
+		if err := navbar.Render(&out); err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+	}
+
+	html := out.String()
+	for _, id := range []string{"aaaaaaaaaaaaaaaa", "bbbbbbbbbbbbbbbb"} {
+		if !strings.Contains(html, `id="`+id+`"`) || !strings.Contains(html, `for="`+id+`"`) {
+			t.Errorf("expected checkbox and label to use ID %s", id)
+		}
+	}
+}
@@ -5,6 +5,22 @@ import (
 	"strings"
 )
 
+// callerID returns the caller-provided ID for the next element of the given
+// component type, or "" when no RenderOpts.IDGenerator is configured. Indexes
+// start at 0 for each component type and each render.
+func (bc *BaseComponent) callerID(component string) string {
+	opts := bc.RenderOpts
+	if opts == nil || opts.IDGenerator == nil {
+		return ""
+	}
+	if opts.IDCounters == nil {
+		opts.IDCounters = make(map[string]int)
+	}
+	index := opts.IDCounters[component]
+	opts.IDCounters[component] = index + 1
+	return opts.IDGenerator(component, index)
+}
+
 // genRandomHexString generates a random hexadecimal string of the specified length.
 // This replicates MJML's genRandomHexString function which uses Math.random()
 // to generate random hex digits for unique element IDs.
@@ -42,9 +42,21 @@ func (c *MJRawComponent) IsRawElement() bool { return true }
 // GetDefaultAttribute returns default values for the component's attributes
 func (c *MJRawComponent) GetDefaultAttribute(name string) string { return "" }
 
-// Render writes the original content trimmed of leading/trailing whitespace
+// IsFileStart reports whether the content belongs at the start of the
+// document, before the doctype (position="file-start").
+func (c *MJRawComponent) IsFileStart() bool {
+	return c.Node != nil && c.Node.GetAttribute("position") == "file-start"
+}
+
+// Render writes the original content trimmed of leading/trailing whitespace.
+// Content captured verbatim by the parser is written unchanged.
 func (c *MJRawComponent) Render(w io.StringWriter) error {
-	content := strings.TrimSpace(c.Content)
+	if c.Node != nil && c.Node.Verbatim {
+		_, err := w.WriteString(c.SanitizeHTML(c.Content))
+		return err
+	}
+
+	content := strings.TrimSpace(c.SanitizeHTML(c.Content))
 	if strings.Contains(content, "<!--") {
 		content = conditionalCommentGapAfter.ReplaceAllString(content, "${1}${2}")
 		content = conditionalCommentGapBefore.ReplaceAllString(content, "${1}${2}")
@@ -59,6 +71,7 @@ func (c *MJRawComponent) Render(w io.StringWriter) error {
 		attrs := strings.TrimRightFunc(parts[2], unicode.IsSpace)
 		return "<" + parts[1] + attrs + ">"
 	})
+	content = c.ApplyInlineStylesToHTMLContent(content)
 
 	if _, err := w.WriteString(content); err != nil {
 		return err
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/preslavrachev/gomjml/mjml/components/defaults"
 	"github.com/preslavrachev/gomjml/mjml/constants"
 	"github.com/preslavrachev/gomjml/mjml/html"
 	"github.com/preslavrachev/gomjml/mjml/options"
@@ -46,6 +47,13 @@ func (c *MJSectionComponent) GetTagName() string {
 	return "mj-section"
 }
 
+// hasBackgroundImage reports whether the section has a background image that
+// needs VML in Outlook. Gradients do not count.
+func (c *MJSectionComponent) hasBackgroundImage() bool {
+	_, _, imageURL := styles.SplitBackground("", c.GetAttributeWithDefault(c, constants.MJMLBackgroundUrl))
+	return imageURL != ""
+}
+
 // Render implements optimized Writer-based rendering for MJSectionComponent
 func (c *MJSectionComponent) Render(w io.StringWriter) error {
 	c.wrapperMSOClosed = false
@@ -70,6 +78,11 @@ func (c *MJSectionComponent) Render(w io.StringWriter) error {
 	borderBottom := c.GetAttributeFast(c, constants.MJMLBorderBottom)
 	borderLeft := c.GetAttributeFast(c, constants.MJMLBorderLeft)
 
+	// A CSS gradient is not an image: it gets no VML and falls back to a plain
+	// color in Outlook and in bgcolor attributes.
+	backgroundColor, backgroundGradient, backgroundUrl := styles.SplitBackground(backgroundColor, backgroundUrl)
+	backgroundUrl = encodeBackgroundURL(backgroundUrl)
+
 	// Check if we have a background image for VML generation
 	hasBackgroundImage := backgroundUrl != ""
 	isFullWidth := fullWidth != ""
@@ -100,7 +113,7 @@ func (c *MJSectionComponent) Render(w io.StringWriter) error {
 				// Also add the background attribute for email client compatibility (use same encoding as VML src)
 				outerTable.AddAttribute("background", htmlEscape(backgroundUrl))
 			}
-		} else if backgroundColor != "" {
+		} else if backgroundColor != "" || backgroundGradient != "" {
 			// Apply background color only when provided
 			c.ApplyBackgroundStyles(outerTable, c)
 		}
@@ -384,7 +397,7 @@ func (c *MJSectionComponent) Render(w io.StringWriter) error {
 				sectionDiv.AddStyle("background-repeat", backgroundRepeat)
 				sectionDiv.AddStyle("background-size", backgroundSize)
 			}
-		} else if backgroundColor != "" {
+		} else if backgroundColor != "" || backgroundGradient != "" {
 			// Color-only background
 			c.ApplyBackgroundStyles(sectionDiv, c)
 		}
@@ -618,7 +631,7 @@ func (c *MJSectionComponent) Render(w io.StringWriter) error {
 	// AIDEV-NOTE: width-flow-start; section initiates width flow by passing effective width to columns
 	for _, child := range c.Children {
 		if child.IsRawElement() {
-			if err := child.Render(w); err != nil {
+			if err := c.RenderChild(child, w); err != nil {
 				return err
 			}
 			continue
@@ -632,10 +645,7 @@ func (c *MJSectionComponent) Render(w io.StringWriter) error {
 		// Generate MSO TD for each column (within shared MSO table)
 		if columnComp, ok := child.(*MJColumnComponent); ok {
 			getAttr := func(name string) string {
-				if attr := columnComp.GetAttribute(name); attr != nil {
-					return *attr
-				}
-				return columnComp.GetDefaultAttribute(name)
+				return columnComp.GetAttributeWithDefault(columnComp, name)
 			}
 
 			if needsSharedMSOTable && columnCount > 0 {
@@ -685,7 +695,7 @@ func (c *MJSectionComponent) Render(w io.StringWriter) error {
 					return err
 				}
 
-				if err := columnComp.Render(w); err != nil {
+				if err := c.RenderChild(columnComp, w); err != nil {
 					return err
 				}
 
@@ -719,7 +729,7 @@ func (c *MJSectionComponent) Render(w io.StringWriter) error {
 						return err
 					}
 
-					if err := columnComp.Render(w); err != nil {
+					if err := c.RenderChild(columnComp, w); err != nil {
 						return err
 					}
 
@@ -743,7 +753,7 @@ func (c *MJSectionComponent) Render(w io.StringWriter) error {
 					return err
 				}
 
-				if err := columnComp.Render(w); err != nil {
+				if err := c.RenderChild(columnComp, w); err != nil {
 					return err
 				}
 
@@ -782,7 +792,7 @@ func (c *MJSectionComponent) Render(w io.StringWriter) error {
 				return err
 			}
 
-			if err := columnComp.Render(w); err != nil {
+			if err := c.RenderChild(columnComp, w); err != nil {
 				return err
 			}
 
@@ -799,7 +809,7 @@ func (c *MJSectionComponent) Render(w io.StringWriter) error {
 		}
 
 		// Use optimized rendering with fallback to string-based
-		if err := child.Render(w); err != nil {
+		if err := c.RenderChild(child, w); err != nil {
 			return err
 		}
 	}
@@ -909,34 +919,10 @@ func (c *MJSectionComponent) Render(w io.StringWriter) error {
 }
 
 func (c *MJSectionComponent) GetDefaultAttribute(name string) string {
-	switch name {
-	case "background-color":
-		return ""
-	case constants.MJMLBackgroundUrl:
-		return ""
-	case "background-position":
-		return "top center"
-	case "background-position-x":
-		return ""
-	case "background-position-y":
-		return ""
-	case "background-repeat":
-		return "repeat"
-	case "background-size":
-		return "auto"
-	case "direction":
-		return "ltr"
-	case "full-width":
-		return ""
-	case "padding":
-		return "20px 0"
-	case "text-align":
-		return "center"
-	case "text-padding":
-		return "4px 4px 4px 0"
-	default:
-		return ""
+	if name == "direction" && c.IsRTL() {
+		return constants.DirRTL
 	}
+	return defaults.Get(c.GetTagName(), name)
 }
 
 // ConsumedWrapperMSOTable reports whether this section handled closing the wrapper's
@@ -948,40 +934,8 @@ func (c *MJSectionComponent) ConsumedWrapperMSOTable() bool {
 }
 
 // getInnerContentWidth calculates the inner content width for the section after accounting for
-// horizontal padding overrides. The value is used for width propagation to child columns/groups
-// so MSO fallback tables match MJML's Outlook output.
+// horizontal padding and borders. The value is used for width propagation to child
+// columns/groups so MSO fallback tables match MJML's Outlook output.
 func (c *MJSectionComponent) getInnerContentWidth() int {
-	effectiveWidth := c.GetEffectiveWidth()
-	paddingValue := c.GetAttributeWithDefault(c, "padding")
-
-	var spacing *styles.Spacing
-	if paddingValue != "" {
-		if parsed, err := styles.ParseSpacing(paddingValue); err == nil && parsed != nil {
-			spacing = parsed
-			effectiveWidth -= int(parsed.Left + parsed.Right)
-		}
-	}
-
-	if paddingLeftAttr := c.GetAttribute(constants.MJMLPaddingLeft); paddingLeftAttr != nil && *paddingLeftAttr != "" {
-		if px, err := styles.ParsePixel(*paddingLeftAttr); err == nil && px != nil {
-			if spacing != nil {
-				effectiveWidth += int(spacing.Left)
-			}
-			effectiveWidth -= int(px.Value)
-		}
-	}
-
-	if paddingRightAttr := c.GetAttribute(constants.MJMLPaddingRight); paddingRightAttr != nil && *paddingRightAttr != "" {
-		if px, err := styles.ParsePixel(*paddingRightAttr); err == nil && px != nil {
-			if spacing != nil {
-				effectiveWidth += int(spacing.Right)
-			}
-			effectiveWidth -= int(px.Value)
-		}
-	}
-
-	if effectiveWidth <= 0 {
-		return c.GetEffectiveWidth()
-	}
-	return effectiveWidth
+	return c.horizontalBox(c, constants.MJMLPadding, constants.MJMLBorder).contentWidth(c.GetEffectiveWidth())
 }
@@ -4,124 +4,156 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 
+	"github.com/preslavrachev/gomjml/mjml/components/defaults"
 	"github.com/preslavrachev/gomjml/mjml/constants"
-	"github.com/preslavrachev/gomjml/mjml/debug"
-	"github.com/preslavrachev/gomjml/mjml/fonts"
 	"github.com/preslavrachev/gomjml/mjml/html"
 	"github.com/preslavrachev/gomjml/mjml/options"
+	"github.com/preslavrachev/gomjml/mjml/styles"
 	"github.com/preslavrachev/gomjml/parser"
 )
 
-// stripPxSuffix removes the "px" suffix from a CSS value if present
-func stripPxSuffix(value string) string {
-	return strings.TrimSuffix(value, "px")
-}
-
-// socialNetworkDefaults describes the default MJML metadata for a social network.
-type socialNetworkDefaults struct {
-	backgroundColor        string
-	iconURL                string
-	shareURLTemplate       string
-	shareURLSkipSubstrings []string
+// DefaultSocialIconBaseURL is where MJML hosts the icons of the built-in social networks.
+const DefaultSocialIconBaseURL = "https://www.mailjet.com/images/theme/v1/icons/ico-social/"
+
+// SocialNetworkDefaults describes the default metadata for a social network:
+// the values an mj-social-element with a matching name uses when the
+// corresponding attributes are not set.
+type SocialNetworkDefaults struct {
+	// BackgroundColor is the default icon background color.
+	BackgroundColor string
+	// IconURL is the default icon image.
+	IconURL string
+	// ShareURLTemplate turns href into a share link. The "[[URL]]" placeholder
+	// is replaced with the element's href; leave empty to use href as is.
+	ShareURLTemplate string
+	// ShareURLSkipSubstrings lists substrings that mark an href as already
+	// being a network URL, in which case the template is not applied.
+	ShareURLSkipSubstrings []string
 }
 
 // baseSocialNetworkDefaults matches MJML's default network definitions.
-var baseSocialNetworkDefaults = map[string]socialNetworkDefaults{
+var baseSocialNetworkDefaults = map[string]SocialNetworkDefaults{
 	"facebook": {
-		backgroundColor:        "#3b5998",
-		iconURL:                "https://www.mailjet.com/images/theme/v1/icons/ico-social/facebook.png",
-		shareURLTemplate:       "https://www.facebook.com/sharer/sharer.php?u=[[URL]]",
-		shareURLSkipSubstrings: []string{"facebook.com/sharer"},
+		BackgroundColor:        "#3b5998",
+		IconURL:                DefaultSocialIconBaseURL + "facebook.png",
+		ShareURLTemplate:       "https://www.facebook.com/sharer/sharer.php?u=[[URL]]",
+		ShareURLSkipSubstrings: []string{"facebook.com/sharer"},
 	},
 	"twitter": {
-		backgroundColor:        "#55acee",
-		iconURL:                "https://www.mailjet.com/images/theme/v1/icons/ico-social/twitter.png",
-		shareURLTemplate:       "https://twitter.com/intent/tweet?url=[[URL]]",
-		shareURLSkipSubstrings: []string{"twitter.com/", "x.com/"},
+		BackgroundColor:        "#55acee",
+		IconURL:                DefaultSocialIconBaseURL + "twitter.png",
+		ShareURLTemplate:       "https://twitter.com/intent/tweet?url=[[URL]]",
+		ShareURLSkipSubstrings: []string{"twitter.com/", "x.com/"},
 	},
 	"x": {
-		backgroundColor:        "#000000",
-		iconURL:                "https://www.mailjet.com/images/theme/v1/icons/ico-social/twitter-x.png",
-		shareURLTemplate:       "https://twitter.com/intent/tweet?url=[[URL]]",
-		shareURLSkipSubstrings: []string{"twitter.com/", "x.com/"},
+		BackgroundColor:        "#000000",
+		IconURL:                DefaultSocialIconBaseURL + "twitter-x.png",
+		ShareURLTemplate:       "https://twitter.com/intent/tweet?url=[[URL]]",
+		ShareURLSkipSubstrings: []string{"twitter.com/", "x.com/"},
 	},
 	"google": {
-		backgroundColor:        "#dc4e41",
-		iconURL:                "https://www.mailjet.com/images/theme/v1/icons/ico-social/google-plus.png",
-		shareURLTemplate:       "https://plus.google.com/share?url=[[URL]]",
-		shareURLSkipSubstrings: []string{"plus.google.com/share"},
+		BackgroundColor:        "#dc4e41",
+		IconURL:                DefaultSocialIconBaseURL + "google-plus.png",
+		ShareURLTemplate:       "https://plus.google.com/share?url=[[URL]]",
+		ShareURLSkipSubstrings: []string{"plus.google.com/share"},
 	},
 	"pinterest": {
-		backgroundColor:        "#bd081c",
-		iconURL:                "https://www.mailjet.com/images/theme/v1/icons/ico-social/pinterest.png",
-		shareURLTemplate:       "https://pinterest.com/pin/create/button/?url=[[URL]]&media=&description=",
-		shareURLSkipSubstrings: []string{"pinterest.com/pin/create/button"},
+		BackgroundColor:        "#bd081c",
+		IconURL:                DefaultSocialIconBaseURL + "pinterest.png",
+		ShareURLTemplate:       "https://pinterest.com/pin/create/button/?url=[[URL]]&media=&description=",
+		ShareURLSkipSubstrings: []string{"pinterest.com/pin/create/button"},
 	},
 	"linkedin": {
-		backgroundColor:        "#0077b5",
-		iconURL:                "https://www.mailjet.com/images/theme/v1/icons/ico-social/linkedin.png",
-		shareURLTemplate:       "https://www.linkedin.com/shareArticle?mini=true&url=[[URL]]&title=&summary=&source=",
-		shareURLSkipSubstrings: []string{"linkedin.com/shareArticle"},
+		BackgroundColor:        "#0077b5",
+		IconURL:                DefaultSocialIconBaseURL + "linkedin.png",
+		ShareURLTemplate:       "https://www.linkedin.com/shareArticle?mini=true&url=[[URL]]&title=&summary=&source=",
+		ShareURLSkipSubstrings: []string{"linkedin.com/shareArticle"},
 	},
 	"instagram": {
-		backgroundColor: "#3f729b",
-		iconURL:         "https://www.mailjet.com/images/theme/v1/icons/ico-social/instagram.png",
+		BackgroundColor: "#3f729b",
+		IconURL:         DefaultSocialIconBaseURL + "instagram.png",
 	},
 	"web": {
-		backgroundColor: "#4BADE9",
-		iconURL:         "https://www.mailjet.com/images/theme/v1/icons/ico-social/web.png",
+		BackgroundColor: "#4BADE9",
+		IconURL:         DefaultSocialIconBaseURL + "web.png",
 	},
 	"snapchat": {
-		backgroundColor: "#FFFA54",
-		iconURL:         "https://www.mailjet.com/images/theme/v1/icons/ico-social/snapchat.png",
+		BackgroundColor: "#FFFA54",
+		IconURL:         DefaultSocialIconBaseURL + "snapchat.png",
 	},
 	"youtube": {
-		backgroundColor: "#EB3323",
-		iconURL:         "https://www.mailjet.com/images/theme/v1/icons/ico-social/youtube.png",
+		BackgroundColor: "#EB3323",
+		IconURL:         DefaultSocialIconBaseURL + "youtube.png",
 	},
 	"tumblr": {
-		backgroundColor:        "#344356",
-		iconURL:                "https://www.mailjet.com/images/theme/v1/icons/ico-social/tumblr.png",
-		shareURLTemplate:       "https://www.tumblr.com/widgets/share/tool?canonicalUrl=[[URL]]",
-		shareURLSkipSubstrings: []string{"tumblr.com/widgets/share"},
+		BackgroundColor:        "#344356",
+		IconURL:                DefaultSocialIconBaseURL + "tumblr.png",
+		ShareURLTemplate:       "https://www.tumblr.com/widgets/share/tool?canonicalUrl=[[URL]]",
+		ShareURLSkipSubstrings: []string{"tumblr.com/widgets/share"},
 	},
 	"github": {
-		backgroundColor: "#000000",
-		iconURL:         "https://www.mailjet.com/images/theme/v1/icons/ico-social/github.png",
+		BackgroundColor: "#000000",
+		IconURL:         DefaultSocialIconBaseURL + "github.png",
 	},
 	"xing": {
-		backgroundColor:        "#296366",
-		iconURL:                "https://www.mailjet.com/images/theme/v1/icons/ico-social/xing.png",
-		shareURLTemplate:       "https://www.xing.com/app/user?op=share&url=[[URL]]",
-		shareURLSkipSubstrings: []string{"xing.com/app/user?op=share"},
+		BackgroundColor:        "#296366",
+		IconURL:                DefaultSocialIconBaseURL + "xing.png",
+		ShareURLTemplate:       "https://www.xing.com/app/user?op=share&url=[[URL]]",
+		ShareURLSkipSubstrings: []string{"xing.com/app/user?op=share"},
 	},
 	"vimeo": {
-		backgroundColor: "#53B4E7",
-		iconURL:         "https://www.mailjet.com/images/theme/v1/icons/ico-social/vimeo.png",
+		BackgroundColor: "#53B4E7",
+		IconURL:         DefaultSocialIconBaseURL + "vimeo.png",
 	},
 	"medium": {
-		backgroundColor: "#000000",
-		iconURL:         "https://www.mailjet.com/images/theme/v1/icons/ico-social/medium.png",
+		BackgroundColor: "#000000",
+		IconURL:         DefaultSocialIconBaseURL + "medium.png",
 	},
 	"soundcloud": {
-		backgroundColor: "#EF7F31",
-		iconURL:         "https://www.mailjet.com/images/theme/v1/icons/ico-social/soundcloud.png",
+		BackgroundColor: "#EF7F31",
+		IconURL:         DefaultSocialIconBaseURL + "soundcloud.png",
 	},
 	"dribbble": {
-		backgroundColor: "#D95988",
-		iconURL:         "https://www.mailjet.com/images/theme/v1/icons/ico-social/dribbble.png",
+		BackgroundColor: "#D95988",
+		IconURL:         DefaultSocialIconBaseURL + "dribbble.png",
 	},
 }
 
 const shareURLPlaceholder = "[[URL]]"
 
+// socialNetworksMu guards baseSocialNetworkDefaults against registrations
+// happening concurrently with renders.
+var socialNetworksMu sync.RWMutex
+
+// RegisterSocialNetwork adds a social network, or replaces the defaults of an
+// existing one, so mj-social-element name="<name>" picks up its icon,
+// background color and share link. Like the built-in networks, the
+// "<name>-noshare" variant uses href without the share template.
+// It is safe to call concurrently with rendering, but is typically called
+// once during program initialization.
+func RegisterSocialNetwork(name string, defaults SocialNetworkDefaults) {
+	skip := make([]string, len(defaults.ShareURLSkipSubstrings))
+	for i, substr := range defaults.ShareURLSkipSubstrings {
+		skip[i] = strings.ToLower(substr)
+	}
+	defaults.ShareURLSkipSubstrings = skip
+
+	socialNetworksMu.Lock()
+	defer socialNetworksMu.Unlock()
+	baseSocialNetworkDefaults[name] = defaults
+}
+
 // getSocialNetworkDefaults resolves MJML defaults for a given social element name.
-func getSocialNetworkDefaults(name string) (socialNetworkDefaults, bool) {
+func getSocialNetworkDefaults(name string) (SocialNetworkDefaults, bool) {
 	if name == "" {
-		return socialNetworkDefaults{}, false
+		return SocialNetworkDefaults{}, false
 	}
 
+	socialNetworksMu.RLock()
+	defer socialNetworksMu.RUnlock()
+
 	if defaults, exists := baseSocialNetworkDefaults[name]; exists {
 		return defaults, true
 	}
@@ -131,14 +163,14 @@ func getSocialNetworkDefaults(name string) (socialNetworkDefaults, bool) {
 		if defaults, exists := baseSocialNetworkDefaults[baseName]; exists {
 			// Copy the struct to avoid mutating the base definition.
 			resolved := defaults
-			if strings.HasSuffix(name, "-noshare") && resolved.shareURLTemplate != "" {
-				resolved.shareURLTemplate = shareURLPlaceholder
+			if strings.HasSuffix(name, "-noshare") && resolved.ShareURLTemplate != "" {
+				resolved.ShareURLTemplate = shareURLPlaceholder
 			}
 			return resolved, true
 		}
 	}
 
-	return socialNetworkDefaults{}, false
+	return SocialNetworkDefaults{}, false
 }
 
 // Attributes that mj-social-element is allowed to inherit from its parent.
@@ -172,33 +204,12 @@ func NewMJSocialComponent(node *parser.MJMLNode, opts *options.RenderOpts) *MJSo
 
 func (c *MJSocialComponent) GetDefaultAttribute(name string) string {
 	switch name {
-	case constants.MJMLAlign:
-		return constants.AlignCenter
-	case constants.MJMLBorderRadius:
-		return "3px"
-	case constants.MJMLColor:
-		return "#333333"
-	case constants.MJMLFontFamily:
-		return fonts.DefaultFontStack
-	case constants.MJMLFontSize:
-		return "13px"
-	case constants.MJMLIconSize:
-		return "20px"
 	case constants.MJMLInnerPadding:
 		return "4px"
-	case constants.MJMLLineHeight:
-		return "22px"
-	case constants.MJMLMode:
-		return "horizontal"
-	case constants.MJMLPadding:
-		return "10px 25px"
 	case constants.MJMLTableLayout:
 		return "auto"
-	case constants.MJMLTextDecoration:
-		return constants.TextDecorationNone
-	default:
-		return ""
 	}
+	return defaults.Get(c.GetTagName(), name)
 }
 
 func (c *MJSocialComponent) getAttribute(name string) string {
@@ -245,10 +256,11 @@ func (c *MJSocialComponent) Render(w io.StringWriter) error {
 	cssClass := c.Node.GetAttribute(constants.MJMLCSSClass)
 	if cssClass != "" {
 		td.AddAttribute(constants.AttrClass, cssClass)
+		c.ApplyInlineStyles(td, cssClass)
 	}
 
 	// Add container background color if specified
-	containerBg := c.Node.GetAttribute("container-background-color")
+	containerBg := c.getAttribute(constants.MJMLContainerBackgroundColor)
 	if containerBg != "" {
 		td.AddStyle(constants.CSSBackground, containerBg)
 	}
@@ -298,7 +310,7 @@ func (c *MJSocialComponent) Render(w io.StringWriter) error {
 				socialElement.SetContainerWidth(c.GetContainerWidth())
 				socialElement.InheritFromParent(c)
 				socialElement.SetVerticalMode(true)
-				if err := socialElement.Render(w); err != nil {
+				if err := c.RenderChild(socialElement, w); err != nil {
 					return err
 				}
 			}
@@ -348,7 +360,7 @@ func (c *MJSocialComponent) Render(w io.StringWriter) error {
 		// Render social elements with coordinated MSO wrappers
 		for i, socialElement := range socialElements {
 			previousWrap := socialElement.SetMSOConditionalWrap(false)
-			if err := socialElement.Render(w); err != nil {
+			if err := c.RenderChild(socialElement, w); err != nil {
 				socialElement.SetMSOConditionalWrap(previousWrap)
 				return err
 			}
@@ -407,50 +419,19 @@ func NewMJSocialElementComponent(node *parser.MJMLNode, opts *options.RenderOpts
 
 func (c *MJSocialElementComponent) GetDefaultAttribute(name string) string {
 	switch name {
-	case constants.MJMLAlign:
-		return constants.AlignLeft
-	case constants.MJMLAlt:
-		return ""
-	case constants.MJMLBorderRadius:
-		return "3px"
-	case constants.MJMLColor:
-		return "#000"
-	case constants.MJMLFontFamily:
-		return fonts.DefaultFontStack
-	case constants.MJMLFontSize:
-		return "13px"
 	case constants.MJMLFontStyle:
 		return constants.FontStyleNormal
 	case constants.MJMLFontWeight:
 		return constants.FontWeightNormal
-	case constants.MJMLHref:
-		return ""
 	case constants.MJMLIconSize:
 		return "20px"
-	case constants.MJMLIconHeight:
-		return "" // No default, falls back to icon-size
-	case constants.MJMLLineHeight:
-		return "1"
-	case constants.MJMLName:
-		return ""
-	case constants.MJMLPadding:
-		return "4px"
 	case "src":
-		if defaults, ok := getSocialNetworkDefaults(c.Node.GetAttribute("name")); ok {
-			return defaults.iconURL
+		if network, ok := getSocialNetworkDefaults(c.Node.GetAttribute("name")); ok {
+			return c.resolveIconURL(network.IconURL)
 		}
 		return ""
-	case constants.MJMLTarget:
-		return constants.TargetBlank
-	case constants.MJMLTextDecoration:
-		return constants.TextDecorationNone
-	case constants.MJMLTextPadding:
-		return "4px 4px 4px 0"
-	case constants.MJMLVerticalAlign:
-		return constants.VAlignMiddle
-	default:
-		return ""
 	}
+	return defaults.Get(c.GetTagName(), name)
 }
 
 func (c *MJSocialElementComponent) getAttribute(name string) string {
@@ -476,8 +457,8 @@ func (c *MJSocialElementComponent) getAttribute(name string) string {
 		if _, inheritable := socialElementInheritableAttributes[name]; inheritable {
 			// First check parent's explicit attribute
 			if parentValue := c.parentSocial.Node.GetAttribute(name); parentValue != "" {
-				if debug.Enabled() {
-					debug.DebugLogWithData(
+				if c.logger().Enabled() {
+					c.logger().LogWithData(
 						"social-attr",
 						"parent-explicit",
 						"Using parent explicit attribute",
@@ -495,8 +476,8 @@ func (c *MJSocialElementComponent) getAttribute(name string) string {
 			}
 			// Then check parent's resolved attribute (includes global attributes)
 			if parentResolved := c.parentSocial.getAttribute(name); parentResolved != "" {
-				if debug.Enabled() {
-					debug.DebugLogWithData(
+				if c.logger().Enabled() {
+					c.logger().LogWithData(
 						"social-attr",
 						"parent-resolved",
 						"Using parent resolved attribute",
@@ -523,7 +504,7 @@ func (c *MJSocialElementComponent) getAttribute(name string) string {
 	// 5. Check platform-specific defaults (for background-color)
 	if name == constants.MJMLBackgroundColor {
 		if defaults, ok := getSocialNetworkDefaults(c.Node.GetAttribute("name")); ok {
-			return defaults.backgroundColor
+			return defaults.BackgroundColor
 		}
 	}
 
@@ -531,6 +512,22 @@ func (c *MJSocialElementComponent) getAttribute(name string) string {
 	return c.GetDefaultAttribute(name)
 }
 
+// resolveIconURL rebases a built-in network icon onto RenderOpts.SocialIconBaseURL
+// when one is configured. Icons hosted elsewhere are returned unchanged.
+func (c *MJSocialElementComponent) resolveIconURL(iconURL string) string {
+	if c.RenderOpts == nil || c.RenderOpts.SocialIconBaseURL == "" {
+		return iconURL
+	}
+	if file, ok := strings.CutPrefix(iconURL, DefaultSocialIconBaseURL); ok {
+		baseURL := c.RenderOpts.SocialIconBaseURL
+		if !strings.HasSuffix(baseURL, "/") {
+			baseURL += "/"
+		}
+		return baseURL + file
+	}
+	return iconURL
+}
+
 // InheritFromParent sets the parent reference for attribute inheritance
 func (c *MJSocialElementComponent) InheritFromParent(parent *MJSocialComponent) {
 	c.parentSocial = parent
@@ -556,17 +553,22 @@ func (c *MJSocialElementComponent) Render(w io.StringWriter) error {
 	if iconHeight == "" {
 		iconHeight = iconSize // fallback to icon-size
 	}
-	src := c.getAttribute("src")
-	href := c.getAttribute("href")
-	alt := c.getAttribute("alt")
+	src, err := c.inlineImage(c.getAttribute("src"))
+	if err != nil {
+		return err
+	}
+	src = c.escapeAttribute(src)
+	href := c.escapeAttribute(normalizeHref(c.getAttribute("href")))
+	alt := c.escapeAttribute(c.getAttribute("alt"))
 
-	// Handle special sharing URL generation for known platforms
+	// Handle special sharing URL generation for known platforms. mailto:,
+	// tel: and other non-web links are used as they are.
 	nameAttr := c.Node.GetAttribute("name")
-	if href != "" {
-		if defaults, ok := getSocialNetworkDefaults(nameAttr); ok && defaults.shareURLTemplate != "" {
+	if href != "" && isWebHref(href) {
+		if defaults, ok := getSocialNetworkDefaults(nameAttr); ok && defaults.ShareURLTemplate != "" {
 			hrefLower := strings.ToLower(href)
 			skipShare := false
-			for _, pattern := range defaults.shareURLSkipSubstrings {
+			for _, pattern := range defaults.ShareURLSkipSubstrings {
 				if strings.Contains(hrefLower, pattern) {
 					skipShare = true
 					break
@@ -574,7 +576,11 @@ func (c *MJSocialElementComponent) Render(w io.StringWriter) error {
 			}
 
 			if !skipShare {
-				template := defaults.shareURLTemplate
+				// MJML writes the share templates unescaped
+				template := defaults.ShareURLTemplate
+				if c.RenderOpts.MarkupEscaping == html.EscapeSafe {
+					template = html.EscapeAttribute(template, html.EscapeSafe)
+				}
 				if strings.Contains(template, shareURLPlaceholder) {
 					href = strings.ReplaceAll(template, shareURLPlaceholder, href)
 				} else {
@@ -589,166 +595,96 @@ func (c *MJSocialElementComponent) Render(w io.StringWriter) error {
 	backgroundColor := c.getAttribute("background-color")
 	borderRadius := c.getAttribute("border-radius")
 
-	// Skip rendering if no src provided
-	if src == "" {
+	// Elements without an icon, such as "Unsubscribe" links in a footer, are
+	// rendered as their text alone; with neither there is nothing to render
+	textContent := c.escapedMixedContent()
+	if src == "" && textContent == "" {
 		return nil
 	}
 
-	if c.verticalMode {
-		// Vertical mode: render as table row without MSO conditionals
-		if _, err := w.WriteString("<tr>"); err != nil {
-			return err
+	// Both modes render the same row, as in MJML: an icon cell and an
+	// optional text cell, with the link on both. Horizontally the row is
+	// wrapped in its own inline table.
+	var outerTable *html.HTMLTag
+	if !c.verticalMode {
+		if c.wrapMSO {
+			if _, err := w.WriteString("<!--[if mso | IE]><td><![endif]-->"); err != nil {
+				return err
+			}
 		}
 
-		// Icon cell
-		iconTd := html.NewHTMLTag("td").
-			AddStyle("padding", padding).
-			AddStyle("vertical-align", "middle")
-
-		if err := iconTd.RenderOpen(w); err != nil {
-			return err
+		// Outer table (inline-table display) - inherit align from parent
+		align := "center" // default
+		if c.parentSocial != nil {
+			if parentAlign := c.parentSocial.getAttribute("align"); parentAlign != "" {
+				align = parentAlign
+			}
 		}
 
-		// Inner table with background color
-		innerTable := html.NewHTMLTag("table").
+		outerTable = html.NewHTMLTag("table")
+		c.AddDebugAttribute(outerTable, "social-element")
+		outerTable.
 			AddAttribute("border", "0").
 			AddAttribute("cellpadding", "0").
 			AddAttribute("cellspacing", "0").
 			AddAttribute("role", "presentation").
-			AddStyle("background", backgroundColor).
-			AddStyle("border-radius", borderRadius).
-			AddStyle("width", iconSize)
-
-		if err := innerTable.RenderOpen(w); err != nil {
-			return err
-		}
-		if _, err := w.WriteString("<tbody><tr>"); err != nil {
-			return err
-		}
-
-		// Icon cell
-		iconInnerTd := html.NewHTMLTag("td").
-			AddStyle("font-size", "0").
-			AddStyle("height", iconHeight).
-			AddStyle("vertical-align", "middle").
-			AddStyle("width", iconSize)
-
-		if err := iconInnerTd.RenderOpen(w); err != nil {
-			return err
-		}
-
-		// Image without link in vertical mode (as per MRML output)
-		heightAttr := stripPxSuffix(iconHeight)
-		widthAttr := stripPxSuffix(iconSize)
-
-		img := html.NewHTMLTag("img")
-		img.AddAttribute("alt", alt)
-		img.AddAttribute("height", heightAttr).
-			AddAttribute("src", src).
-			AddAttribute("width", widthAttr).
-			AddStyle("border-radius", borderRadius).
-			AddStyle("display", "block")
-
-		if err := img.RenderVoid(w); err != nil {
-			return err
-		}
+			AddAttribute("align", align).
+			AddStyle("float", "none").
+			AddStyle("display", "inline-table")
 
-		if err := iconInnerTd.RenderClose(w); err != nil {
+		if err := outerTable.RenderOpen(w); err != nil {
 			return err
 		}
-		if _, err := w.WriteString("</tr></tbody>"); err != nil {
-			return err
-		}
-		if err := innerTable.RenderClose(w); err != nil {
-			return err
-		}
-		if err := iconTd.RenderClose(w); err != nil {
-			return err
-		}
-
-		// Text content cell
-		textContent := c.Node.Text
-		if textContent != "" {
-			textTd := html.NewHTMLTag("td").
-				AddStyle("vertical-align", "middle").
-				AddStyle("padding", c.getAttribute("text-padding"))
-
-			if err := textTd.RenderOpen(w); err != nil {
-				return err
-			}
-
-			// Text content with span (no link in vertical mode as per MRML)
-			textSpan := html.NewHTMLTag("span").
-				AddStyle("color", c.getAttribute("color")).
-				AddStyle("font-size", c.getAttribute("font-size")).
-				AddStyle("font-family", c.getAttribute("font-family")).
-				AddStyle("line-height", c.getAttribute("line-height")).
-				AddStyle("text-decoration", c.getAttribute("text-decoration"))
-
-			if err := textSpan.RenderOpen(w); err != nil {
-				return err
-			}
-			if _, err := w.WriteString(textContent); err != nil {
-				return err
-			}
-			if err := textSpan.RenderClose(w); err != nil {
-				return err
-			}
-			if err := textTd.RenderClose(w); err != nil {
-				return err
-			}
-		}
-
-		if _, err := w.WriteString("</tr>"); err != nil {
+		if _, err := w.WriteString("<tbody>"); err != nil {
 			return err
 		}
+	}
 
-		return nil
+	// Add CSS class to tr if specified on individual social element
+	tr := html.NewHTMLTag("tr")
+	if cssClass := c.Node.GetAttribute("css-class"); cssClass != "" {
+		tr.AddAttribute(constants.AttrClass, cssClass)
+		c.ApplyInlineStyles(tr, cssClass)
+	}
+	if err := tr.RenderOpen(w); err != nil {
+		return err
 	}
 
-	// Horizontal mode: MSO conditional for individual social element
-	if c.wrapMSO {
-		if _, err := w.WriteString("<!--[if mso | IE]><td><![endif]-->"); err != nil {
+	if src != "" {
+		if err := c.renderIconCell(w, padding, iconSize, iconHeight, src, alt, href, target, backgroundColor, borderRadius); err != nil {
 			return err
 		}
 	}
-
-	// Outer table (inline-table display) - inherit align from parent
-	align := "center" // default
-	if c.parentSocial != nil {
-		if parentAlign := c.parentSocial.getAttribute("align"); parentAlign != "" {
-			align = parentAlign
-		}
+	if err := c.renderTextCell(w, textContent, href, target); err != nil {
+		return err
 	}
 
-	outerTable := html.NewHTMLTag("table")
-	c.AddDebugAttribute(outerTable, "social-element")
-	outerTable.
-		AddAttribute("border", "0").
-		AddAttribute("cellpadding", "0").
-		AddAttribute("cellspacing", "0").
-		AddAttribute("role", "presentation").
-		AddAttribute("align", align).
-		AddStyle("float", "none").
-		AddStyle("display", "inline-table")
+	if _, err := w.WriteString("</tr>"); err != nil {
+		return err
+	}
+	if outerTable == nil {
+		return nil
+	}
 
-	if err := outerTable.RenderOpen(w); err != nil {
+	if _, err := w.WriteString("</tbody>"); err != nil {
+		return err
+	}
+	if err := outerTable.RenderClose(w); err != nil {
 		return err
 	}
 
-	// Add CSS class to tr if specified on individual social element
-	cssClass := c.Node.GetAttribute("css-class")
-	if cssClass != "" {
-		trTag := fmt.Sprintf("<tbody><tr class=\"%s\">", cssClass)
-		if _, err := w.WriteString(trTag); err != nil {
-			return err
-		}
-	} else {
-		if _, err := w.WriteString("<tbody><tr>"); err != nil {
+	// Close MSO conditional
+	if c.wrapMSO {
+		if _, err := w.WriteString("<!--[if mso | IE]></td><![endif]-->"); err != nil {
 			return err
 		}
 	}
 
+	return nil
+}
+
+// renderIconCell writes the padded cell holding the icon, linked when href is set
+func (c *MJSocialElementComponent) renderIconCell(w io.StringWriter, padding, iconSize, iconHeight, src, alt, href, target, backgroundColor, borderRadius string) error {
 	// Padding cell
 	paddingTd := html.NewHTMLTag("td")
 
@@ -761,16 +697,13 @@ func (c *MJSocialElementComponent) Render(w io.StringWriter) error {
 		}
 	}
 
-	// Handle padding and padding-bottom specially
-	paddingBottom := c.Node.GetAttribute("padding-bottom")
-	if paddingBottom != "" {
-		paddingTd.AddStyle("padding", iconPadding).
-			AddStyle("padding-bottom", paddingBottom)
-	} else {
-		paddingTd.AddStyle("padding", iconPadding)
-	}
-
-	paddingTd.AddStyle("vertical-align", "middle")
+	// Individual sides from the element override the padding shorthand
+	paddingTd.AddStyle("padding", iconPadding).
+		MaybeAddStyleString("padding-top", c.Node.GetAttribute("padding-top")).
+		MaybeAddStyleString("padding-right", c.Node.GetAttribute("padding-right")).
+		MaybeAddStyleString("padding-bottom", c.Node.GetAttribute("padding-bottom")).
+		MaybeAddStyleString("padding-left", c.Node.GetAttribute("padding-left")).
+		AddStyle("vertical-align", "middle")
 
 	if err := paddingTd.RenderOpen(w); err != nil {
 		return err
@@ -793,16 +726,10 @@ func (c *MJSocialElementComponent) Render(w io.StringWriter) error {
 		return err
 	}
 
-	// Icon cell
-	iconTd := html.NewHTMLTag("td")
-
-	// Handle icon-padding if specified (check both element and inherited from parent)
-	iconPaddingAttr := c.getAttribute("icon-padding")
-	if iconPaddingAttr != "" {
-		iconTd.AddStyle("padding", iconPaddingAttr)
-	}
-
-	iconTd.AddStyle("font-size", "0").
+	// Icon cell, with icon-padding from the element or inherited from mj-social
+	iconTd := html.NewHTMLTag("td").
+		MaybeAddStyleString("padding", c.getAttribute("icon-padding")).
+		AddStyle("font-size", "0").
 		AddStyle("height", iconHeight).
 		AddStyle("vertical-align", "middle").
 		AddStyle("width", iconSize)
@@ -812,29 +739,22 @@ func (c *MJSocialElementComponent) Render(w io.StringWriter) error {
 	}
 
 	// Image with optional link - remove "px" suffix from dimensions for HTML attributes
-	heightAttr := stripPxSuffix(iconHeight)
-	widthAttr := stripPxSuffix(iconSize)
-
-	img := html.NewHTMLTag("img")
-	img.AddAttribute("alt", alt)
-
-	img.AddAttribute("height", heightAttr).
+	img := html.NewHTMLTag("img").
+		AddAttribute("alt", alt).
+		AddAttribute("height", styles.HTMLDimension(iconHeight)).
 		AddAttribute("src", src).
-		AddAttribute("width", widthAttr)
+		AddAttribute("width", styles.HTMLDimension(iconSize))
 
 	// Add title attribute if specified
-	title := c.Node.GetAttribute("title")
-	if title != "" {
-		img.AddAttribute("title", title)
+	if title := c.Node.GetAttribute("title"); title != "" {
+		img.AddAttribute("title", c.escapeAttribute(title))
 	}
 
 	img.AddStyle("border-radius", borderRadius).
 		AddStyle("display", "block")
 
 	if href != "" {
-		link := html.NewHTMLTag("a").
-			AddAttribute("href", href).
-			AddAttribute("target", target)
+		link := c.newLinkTag(href, target)
 		if err := link.RenderOpen(w); err != nil {
 			return err
 		}
@@ -859,15 +779,24 @@ func (c *MJSocialElementComponent) Render(w io.StringWriter) error {
 	if err := innerTable.RenderClose(w); err != nil {
 		return err
 	}
-	if err := paddingTd.RenderClose(w); err != nil {
-		return err
+	return paddingTd.RenderClose(w)
+}
+
+// newLinkTag creates the anchor wrapping the icon and the text
+func (c *MJSocialElementComponent) newLinkTag(href, target string) *html.HTMLTag {
+	link := html.NewHTMLTag("a").AddAttribute("href", href)
+	if rel := c.getAttribute("rel"); rel != "" {
+		link.AddAttribute("rel", c.escapeAttribute(rel))
 	}
+	return link.AddAttribute("target", target)
+}
 
-	// Render text content if present - INSIDE the same <tr>
-	// Use GetMixedContent to preserve HTML tags like <b>, <i>, etc. within text
-	textContent := c.Node.GetMixedContent()
-	if debug.Enabled() {
-		debug.DebugLogWithData(
+// renderTextCell writes the cell holding the element's text, if it has any.
+// The text is the escaped mixed content, which keeps tags like <b> and <i>,
+// and is linked when href is set.
+func (c *MJSocialElementComponent) renderTextCell(w io.StringWriter, textContent, href, target string) error {
+	if c.logger().Enabled() {
+		c.logger().LogWithData(
 			"social-element",
 			"content-selection",
 			"Selected text content source",
@@ -880,77 +809,58 @@ func (c *MJSocialElementComponent) Render(w io.StringWriter) error {
 			},
 		)
 	}
-	if textContent != "" {
-		// Text cell with padding and styling
-		textTd := html.NewHTMLTag("td").
-			AddStyle("vertical-align", c.getAttribute("vertical-align")).
-			AddStyle("padding", c.getAttribute("text-padding"))
-
-		if err := textTd.RenderOpen(w); err != nil {
-			return err
-		}
+	if textContent == "" {
+		return nil
+	}
 
-		// Text content with social styling - use <a> if href present, <span> otherwise
-		var textElement *html.HTMLTag
-		if href != "" {
-			// Use <a> tag when there's a link
-			textElement = html.NewHTMLTag("a").
-				AddAttribute("href", href).
-				AddAttribute("target", target)
-		} else {
-			// Use <span> tag when no link
-			textElement = html.NewHTMLTag("span")
-		}
+	// Text cell with padding and styling. Like MRML, the element's own align
+	// is accepted but not rendered; the row follows the mj-social align.
+	textTd := html.NewHTMLTag("td").
+		AddStyle("vertical-align", c.getAttribute("vertical-align")).
+		AddStyle("padding", c.getAttribute("text-padding"))
 
-		// Add styling - maintain MRML CSS property order
-		textElement.AddStyle("color", c.getAttribute("color")).
-			AddStyle("font-size", c.getAttribute("font-size"))
+	if err := textTd.RenderOpen(w); err != nil {
+		return err
+	}
 
-		// Add font-weight after font-size (inherited from parent or explicit)
-		fontWeight := c.getAttribute("font-weight")
-		if fontWeight != "" && fontWeight != "normal" { // Only add if not default
-			textElement.AddStyle("font-weight", fontWeight)
-		}
+	// Text content with social styling - use <a> if href present, <span> otherwise
+	var textElement *html.HTMLTag
+	if href != "" {
+		textElement = c.newLinkTag(href, target)
+	} else {
+		textElement = html.NewHTMLTag("span")
+	}
 
-		// Add font-style after font-weight (inherited from parent or explicit)
-		fontStyle := c.getAttribute("font-style")
-		if fontStyle != "" && fontStyle != "normal" { // Only add if not default
-			textElement.AddStyle("font-style", fontStyle)
-		}
+	// Add styling - maintain MRML CSS property order
+	textElement.AddStyle("color", c.getAttribute("color")).
+		AddStyle("font-size", c.getAttribute("font-size"))
 
-		textElement.AddStyle("font-family", c.getAttribute("font-family")).
-			AddStyle("line-height", c.getAttribute("line-height")).
-			AddStyle("text-decoration", c.getAttribute("text-decoration"))
+	// Add font-weight after font-size (inherited from parent or explicit)
+	fontWeight := c.getAttribute("font-weight")
+	if fontWeight != "" && fontWeight != "normal" { // Only add if not default
+		textElement.AddStyle("font-weight", fontWeight)
+	}
 
-		if err := textElement.RenderOpen(w); err != nil {
-			return err
-		}
-		if _, err := w.WriteString(textContent); err != nil {
-			return err
-		}
-		if err := textElement.RenderClose(w); err != nil {
-			return err
-		}
-		if err := textTd.RenderClose(w); err != nil {
-			return err
-		}
+	// Add font-style after font-weight (inherited from parent or explicit)
+	fontStyle := c.getAttribute("font-style")
+	if fontStyle != "" && fontStyle != "normal" { // Only add if not default
+		textElement.AddStyle("font-style", fontStyle)
 	}
 
-	if _, err := w.WriteString("</tr></tbody>"); err != nil {
+	textElement.AddStyle("font-family", c.getAttribute("font-family")).
+		AddStyle("line-height", c.getAttribute("line-height")).
+		AddStyle("text-decoration", c.getAttribute("text-decoration"))
+
+	if err := textElement.RenderOpen(w); err != nil {
 		return err
 	}
-	if err := outerTable.RenderClose(w); err != nil {
+	if _, err := w.WriteString(textContent); err != nil {
 		return err
 	}
-
-	// Close MSO conditional
-	if c.wrapMSO {
-		if _, err := w.WriteString("<!--[if mso | IE]></td><![endif]-->"); err != nil {
-			return err
-		}
+	if err := textElement.RenderClose(w); err != nil {
+		return err
 	}
-
-	return nil
+	return textTd.RenderClose(w)
 }
 
 func (c *MJSocialElementComponent) GetTagName() string {
@@ -88,3 +88,123 @@ func findMJMLElement(node *parser.MJMLNode, tagName string) *parser.MJMLNode {
 	}
 	return nil
 }
+
+func TestSocialElementIconBaseURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		mjml        string
+		baseURL     string
+		expectedSrc string
+	}{
+		{
+			name:        "default_host",
+			mjml:        `<mj-social><mj-social-element name="facebook">Share</mj-social-element></mj-social>`,
+			expectedSrc: DefaultSocialIconBaseURL + "facebook.png",
+		},
+		{
+			name:        "custom_host",
+			mjml:        `<mj-social><mj-social-element name="github-noshare">Code</mj-social-element></mj-social>`,
+			baseURL:     "https://cdn.example.com/icons",
+			expectedSrc: "https://cdn.example.com/icons/github.png",
+		},
+		{
+			name:        "explicit_src_wins",
+			mjml:        `<mj-social><mj-social-element name="facebook" src="https://example.com/fb.png">Share</mj-social-element></mj-social>`,
+			baseURL:     "https://cdn.example.com/icons/",
+			expectedSrc: "https://example.com/fb.png",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.ParseMJML(tt.mjml)
+			if err != nil {
+				t.Fatalf("Failed to parse MJML: %v", err)
+			}
+
+			socialElement := findMJMLElement(doc, "mj-social-element")
+			if socialElement == nil {
+				t.Fatal("mj-social-element not found")
+			}
+
+			socialComp := NewMJSocialElementComponent(socialElement, &options.RenderOpts{SocialIconBaseURL: tt.baseURL})
+
+			var buf strings.Builder
+			if err := socialComp.Render(&buf); err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+
+			if output := buf.String(); !strings.Contains(output, `src="`+tt.expectedSrc+`"`) {
+				t.Errorf("Expected src=%q in output, got:\n%s", tt.expectedSrc, output)
+			}
+		})
+	}
+}
+
+func TestRegisterSocialNetwork(t *testing.T) {
+	RegisterSocialNetwork("mastodon", SocialNetworkDefaults{
+		BackgroundColor:        "#6364FF",
+		IconURL:                "https://cdn.example.com/icons/mastodon.png",
+		ShareURLTemplate:       "https://mastodonshare.com/?url=[[URL]]",
+		ShareURLSkipSubstrings: []string{"MastodonShare.com"},
+	})
+	t.Cleanup(func() {
+		socialNetworksMu.Lock()
+		delete(baseSocialNetworkDefaults, "mastodon")
+		socialNetworksMu.Unlock()
+	})
+
+	tests := []struct {
+		name     string
+		mjml     string
+		expected []string
+	}{
+		{
+			name: "share_template",
+			mjml: `<mj-social><mj-social-element name="mastodon" href="https://example.com">Toot</mj-social-element></mj-social>`,
+			expected: []string{
+				`href="https://mastodonshare.com/?url=https://example.com"`,
+				`src="https://cdn.example.com/icons/mastodon.png"`,
+				`background:#6364FF`,
+			},
+		},
+		{
+			name:     "noshare_variant",
+			mjml:     `<mj-social><mj-social-element name="mastodon-noshare" href="https://mastodon.social/@me">Follow</mj-social-element></mj-social>`,
+			expected: []string{`href="https://mastodon.social/@me"`, `src="https://cdn.example.com/icons/mastodon.png"`},
+		},
+		{
+			name:     "skip_substring_case_insensitive",
+			mjml:     `<mj-social><mj-social-element name="mastodon" href="https://mastodonshare.com/?url=x">Toot</mj-social-element></mj-social>`,
+			expected: []string{`href="https://mastodonshare.com/?url=x"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.ParseMJML(tt.mjml)
+			if err != nil {
+				t.Fatalf("Failed to parse MJML: %v", err)
+			}
+
+			socialElement := findMJMLElement(doc, "mj-social-element")
+			if socialElement == nil {
+				t.Fatal("mj-social-element not found")
+			}
+
+			socialComp := NewMJSocialElementComponent(socialElement, &options.RenderOpts{})
+
+			var buf strings.Builder
+			if err := socialComp.Render(&buf); err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+
+			output := buf.String()
+			for _, want := range tt.expected {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected %s in output, got:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
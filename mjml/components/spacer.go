@@ -3,6 +3,7 @@ package components
 import (
 	"io"
 
+	"github.com/preslavrachev/gomjml/mjml/components/defaults"
 	"github.com/preslavrachev/gomjml/mjml/constants"
 	"github.com/preslavrachev/gomjml/mjml/html"
 	"github.com/preslavrachev/gomjml/mjml/options"
@@ -111,10 +112,5 @@ func (c *MJSpacerComponent) GetTagName() string {
 }
 
 func (c *MJSpacerComponent) GetDefaultAttribute(name string) string {
-	switch name {
-	case constants.MJMLHeight:
-		return "20px"
-	default:
-		return ""
-	}
+	return defaults.Get(c.GetTagName(), name)
 }
@@ -4,8 +4,8 @@ import (
 	"io"
 	"strings"
 
+	"github.com/preslavrachev/gomjml/mjml/components/defaults"
 	"github.com/preslavrachev/gomjml/mjml/constants"
-	"github.com/preslavrachev/gomjml/mjml/fonts"
 	"github.com/preslavrachev/gomjml/mjml/html"
 	"github.com/preslavrachev/gomjml/mjml/options"
 	"github.com/preslavrachev/gomjml/parser"
@@ -90,7 +90,15 @@ func (c *MJTableComponent) Render(w io.StringWriter) error {
 	}
 
 	// Write the inner HTML content (TR, TH, TD elements)
-	if err := c.writeInnerTableContent(w); err != nil {
+	if c.SanitizesHTML() || c.InlinesStyles() {
+		var inner strings.Builder
+		if err := c.writeInnerTableContent(&inner); err != nil {
+			return err
+		}
+		if _, err := w.WriteString(c.ApplyInlineStylesToHTMLContent(c.SanitizeHTML(inner.String()))); err != nil {
+			return err
+		}
+	} else if err := c.writeInnerTableContent(w); err != nil {
 		return err
 	}
 
@@ -114,32 +122,7 @@ func (c *MJTableComponent) GetTagName() string {
 }
 
 func (c *MJTableComponent) GetDefaultAttribute(name string) string {
-	switch name {
-	case "align":
-		return "left"
-	case constants.MJMLBorder:
-		return "none"
-	case "cellpadding":
-		return "0"
-	case "cellspacing":
-		return "0"
-	case "color":
-		return "#000000"
-	case "font-family":
-		return fonts.DefaultFontStack
-	case "font-size":
-		return "13px"
-	case "line-height":
-		return "22px"
-	case "padding":
-		return "10px 25px"
-	case "table-layout":
-		return "auto"
-	case "width":
-		return "100%"
-	default:
-		return ""
-	}
+	return defaults.Get(c.GetTagName(), name)
 }
 
 // writeInnerTableContent writes the inner HTML content (TR, TH, TD elements) to the writer
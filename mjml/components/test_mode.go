@@ -15,6 +15,16 @@ func isTestMode() bool {
 	return testmode.IsEnabled()
 }
 
+// SetNavbarTestIDs replaces the IDs handed out to hamburger navbars in test mode
+// and rewinds the counter, so each fixture can supply the IDs its reference output
+// was generated with. ONLY use this in test code.
+func SetNavbarTestIDs(ids ...string) {
+	navbarTestIDsMu.Lock()
+	navbarTestIDs = ids
+	navbarTestIDsMu.Unlock()
+	navbarTestIndex.Store(0)
+}
+
 // resetNavbarTestIndex resets the navbar test ID counter.
 // ONLY for use in unit tests when switching between test modes.
 func resetNavbarTestIndex() {
@@ -5,9 +5,9 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/preslavrachev/gomjml/mjml/components/defaults"
 	"github.com/preslavrachev/gomjml/mjml/constants"
-	"github.com/preslavrachev/gomjml/mjml/debug"
-	"github.com/preslavrachev/gomjml/mjml/fonts"
+	"github.com/preslavrachev/gomjml/mjml/cssmatch"
 	"github.com/preslavrachev/gomjml/mjml/html"
 	"github.com/preslavrachev/gomjml/mjml/options"
 	"github.com/preslavrachev/gomjml/parser"
@@ -37,9 +37,9 @@ func (c *MJTextComponent) GetTagName() string {
 
 // Render implements optimized Writer-based rendering for MJTextComponent
 func (c *MJTextComponent) Render(w io.StringWriter) error {
-	if debug.Enabled() {
-		debug.DebugLog("mj-text", "render-start", "Starting text component rendering")
-		debug.DebugLogWithData("mj-text", "content", "Processing text content", map[string]any{
+	if c.logger().Enabled() {
+		c.logger().Log("mj-text", "render-start", "Starting text component rendering")
+		c.logger().LogWithData("mj-text", "content", "Processing text content", map[string]any{
 			"container_width": c.GetContainerWidth(),
 		})
 	}
@@ -119,6 +119,9 @@ func (c *MJTextComponent) Render(w io.StringWriter) error {
 	}
 	if lineHeight != "" {
 		divTag.AddStyle("line-height", lineHeight)
+		if c.RenderOpts != nil && c.RenderOpts.TextLineHeightRule {
+			divTag.AddStyle("mso-line-height-rule", "exactly")
+		}
 	}
 	if textAlign != "" {
 		divTag.AddStyle("text-align", textAlign)
@@ -143,6 +146,7 @@ func (c *MJTextComponent) Render(w io.StringWriter) error {
 	if err != nil {
 		return err
 	}
+	innerHTML = c.SanitizeHTML(c.Localize(innerHTML))
 	if innerHTML != "" {
 		normalized := normalizeVoidHTMLTags(innerHTML)
 		normalized = c.ApplyInlineStylesToHTMLContent(normalized)
@@ -164,22 +168,10 @@ func (c *MJTextComponent) Render(w io.StringWriter) error {
 }
 
 func (c *MJTextComponent) GetDefaultAttribute(name string) string {
-	switch name {
-	case constants.MJMLFontSize:
-		return "13px"
-	case constants.MJMLColor:
-		return "#000000"
-	case constants.MJMLAlign:
-		return constants.AlignLeft
-	case constants.MJMLFontFamily:
-		return fonts.DefaultFontStack
-	case constants.MJMLLineHeight:
-		return "1"
-	case constants.MJMLPadding:
-		return "10px 25px"
-	default:
-		return ""
+	if name == "align" && c.IsRTL() {
+		return "right"
 	}
+	return defaults.Get(c.GetTagName(), name)
 }
 
 // writeRawInnerHTML writes the original inner HTML content of the mj-text element to the writer
@@ -212,7 +204,7 @@ func (c *MJTextComponent) buildRawInnerHTML() (string, error) {
 				continue
 			}
 
-			normalized := collapseTextWhitespace(part.Text)
+			normalized := collapseHTMLWhitespace(part.Text)
 			if i == 0 {
 				normalized = strings.TrimLeft(normalized, " ")
 			}
@@ -243,7 +235,7 @@ func (c *MJTextComponent) buildRawInnerHTML() (string, error) {
 	}
 
 	// Fallback: no mixed content, use trimmed and collapsed text content
-	normalized := collapseTextWhitespace(c.Node.Text)
+	normalized := collapseHTMLWhitespace(c.Node.Text)
 	normalized = strings.TrimSpace(normalized)
 	return c.restoreHTMLEntities(normalized), nil
 }
@@ -332,6 +324,74 @@ func collapseTextWhitespace(value string) string {
 	return builder.String()
 }
 
+// whitespaceSensitiveElements are the elements whose content keeps its
+// whitespace when mj-text content is collapsed.
+var whitespaceSensitiveElements = []string{"pre", "textarea", "script", "style"}
+
+// collapseHTMLWhitespace collapses whitespace runs in HTML like
+// collapseTextWhitespace, but keeps comments, including conditional comments,
+// and the content of whitespace-sensitive elements as written.
+func collapseHTMLWhitespace(value string) string {
+	var builder strings.Builder
+	builder.Grow(len(value))
+
+	for value != "" {
+		start, end := nextPreservedRange(value)
+		if start < 0 {
+			builder.WriteString(collapseTextWhitespace(value))
+			break
+		}
+		builder.WriteString(collapseTextWhitespace(value[:start]))
+		builder.WriteString(value[start:end])
+		value = value[end:]
+	}
+	return builder.String()
+}
+
+// nextPreservedRange returns the bounds of the first comment or
+// whitespace-sensitive element in value, or -1 when there is none. An
+// unterminated range extends to the end of value.
+func nextPreservedRange(value string) (start, end int) {
+	lower := strings.ToLower(value)
+	start, closing := -1, ""
+	if idx := strings.Index(lower, "<!--"); idx >= 0 {
+		start, closing = idx, "-->"
+	}
+	for _, name := range whitespaceSensitiveElements {
+		idx := strings.Index(lower, "<"+name)
+		for idx >= 0 && !isTagNameEnd(lower, idx+1+len(name)) {
+			next := strings.Index(lower[idx+1:], "<"+name)
+			if next < 0 {
+				idx = -1
+				break
+			}
+			idx += 1 + next
+		}
+		if idx >= 0 && (start < 0 || idx < start) {
+			start, closing = idx, "</"+name+">"
+		}
+	}
+	if start < 0 {
+		return -1, -1
+	}
+	if idx := strings.Index(lower[start:], closing); idx >= 0 {
+		return start, start + idx + len(closing)
+	}
+	return start, len(value)
+}
+
+// isTagNameEnd reports whether a tag name ends at index i of s.
+func isTagNameEnd(s string, i int) bool {
+	if i >= len(s) {
+		return false
+	}
+	switch s[i] {
+	case ' ', '\t', '\n', '\r', '>', '/':
+		return true
+	}
+	return false
+}
+
 func containsCollapsibleWhitespace(value string) bool {
 	for _, r := range value {
 		if r == ' ' || r == '\n' || r == '\r' || r == '\t' {
@@ -357,20 +417,20 @@ func (c *MJTextComponent) reconstructHTMLElement(node *parser.MJMLNode, w io.Str
 	}
 
 	classAttr := ""
+	idAttr := ""
 	for _, attr := range node.Attrs {
 		name := attr.Name.Local
 		if attr.Name.Space != "" {
 			name = attr.Name.Space + ":" + name
 		}
-		if name == constants.AttrClass {
+		switch name {
+		case constants.AttrClass:
 			classAttr = attr.Value
-			break
+		case constants.AttrID:
+			idAttr = attr.Value
 		}
 	}
-	inlineStyle := ""
-	if classAttr != "" {
-		inlineStyle = c.BuildInlineStyleString(classAttr)
-	}
+	inlineStyle := c.BuildInlineStyleString(cssmatch.NewElement(tagName, idAttr, classAttr, nil))
 
 	styleApplied := false
 	// Attributes
@@ -487,6 +547,10 @@ func isVoidHTMLElement(tagName string) bool {
 	return voidElements[tagName]
 }
 
+// mergeInlineStyleValues merges inline mj-style declarations into an element's
+// style attribute in the order ApplyInlineStyles uses for tags: declarations
+// first, then the element's own properties. A declaration for a property the
+// element sets itself is dropped unless it is !important.
 func mergeInlineStyleValues(existing, inline string) string {
 	if existing == "" {
 		return inline
@@ -505,9 +569,16 @@ func mergeInlineStyleValues(existing, inline string) string {
 		return trimmedExisting
 	}
 
-	if !strings.HasSuffix(trimmedExisting, ";") {
-		trimmedExisting += ";"
+	own := make(map[string]bool)
+	for _, decl := range cssmatch.ParseDeclarations(trimmedExisting) {
+		own[decl.Property] = true
+	}
+	var declarations []cssmatch.Declaration
+	for _, decl := range cssmatch.ParseDeclarations(trimmedInline) {
+		if !own[decl.Property] || html.IsImportant(decl.Value) {
+			declarations = append(declarations, decl)
+		}
 	}
 
-	return trimmedExisting + trimmedInline
+	return serializeDeclarations(declarations) + trimmedExisting
 }
@@ -84,6 +84,42 @@ func TestMJTextRenderHTMLAsIs(t *testing.T) {
 			expected: "Line1<br>Image: <img src=\"test.jpg\"><br />End",
 			desc:     "Mixed void elements: unclosed preserved, self-closing normalized",
 		},
+		{
+			name:     "Comment containing closing tag",
+			mjml:     `<mj-text>Before <!-- </mj-text> --> after</mj-text>`,
+			expected: "Before <!-- </mj-text> --> after",
+			desc:     "A </mj-text> inside a comment should not end the mj-text",
+		},
+		{
+			name:     "Nested MSO conditional",
+			mjml:     "<mj-text><!--[if mso]>\n  <table><tr><td>\n<![endif]-->Hi<!--[if mso]></td></tr></table><![endif]--></mj-text>",
+			expected: "<!--[if mso]>\n  <table><tr><td>\n<![endif]-->Hi<!--[if mso]></td></tr></table><![endif]-->",
+			desc:     "User-authored conditional comments should be kept byte for byte",
+		},
+		{
+			name:     "Script and style fragments",
+			mjml:     "<mj-text><style>.a > b { color:red }</style><script>// note\nif (a < b && c) { w(\"</mj-text>\"); }</script></mj-text>",
+			expected: "<style>.a > b { color:red }</style><script>// note\nif (a < b && c) { w(\"</mj-text>\"); }</script>",
+			desc:     "Script and style content should keep its line breaks and may contain </mj-text>",
+		},
+		{
+			name:     "Preformatted text",
+			mjml:     "<mj-text>Code:   <pre>a\n    b</pre></mj-text>",
+			expected: "Code: <pre>a\n    b</pre>",
+			desc:     "Whitespace inside <pre> should be preserved",
+		},
+		{
+			name:     "Escaped markup",
+			mjml:     `<mj-text>Use &lt;b&gt; for <span title="a &quot;b&quot;">bold</span></mj-text>`,
+			expected: `Use &lt;b&gt; for <span title="a &quot;b&quot;">bold</span>`,
+			desc:     "Escaped markup should stay escaped",
+		},
+		{
+			name:     "CDATA section followed by markup",
+			mjml:     `<mj-text><![CDATA[x]]> <b>y</b><br></mj-text>`,
+			expected: `<![CDATA[x]]> <b>y</b><br>`,
+			desc:     "A CDATA section that is not the whole content should be kept as written",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -5,10 +5,10 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/preslavrachev/gomjml/mjml/components/defaults"
 	"github.com/preslavrachev/gomjml/mjml/constants"
 	"github.com/preslavrachev/gomjml/mjml/html"
 	"github.com/preslavrachev/gomjml/mjml/options"
-	"github.com/preslavrachev/gomjml/mjml/styles"
 	"github.com/preslavrachev/gomjml/parser"
 )
 
@@ -25,98 +25,21 @@ func NewMJWrapperComponent(node *parser.MJMLNode, opts *options.RenderOpts) *MJW
 }
 
 func (c *MJWrapperComponent) GetDefaultAttribute(name string) string {
-	switch name {
-	case "background-position":
-		return "top center"
-	case "background-repeat":
-		return "repeat"
-	case "background-size":
-		return "auto"
-	case "direction":
-		return "ltr"
-	case "padding":
-		return "20px 0"
-	case "text-align":
-		return "center"
-	case "text-padding":
-		return "4px 4px 4px 0"
-	default:
-		return ""
+	if name == "direction" && c.IsRTL() {
+		return constants.DirRTL
 	}
+	return defaults.Get(c.GetTagName(), name)
 }
 
 func (c *MJWrapperComponent) getAttribute(name string) string {
 	return c.GetAttributeWithDefault(c, name)
 }
 
-// getBorderWidth calculates total horizontal border width taking into account
-// shorthand border, border-left, and border-right overrides.
-func (c *MJWrapperComponent) getBorderWidth() int {
-	left, right := c.getBorderLRWidths()
-	return left + right
-}
-
-// getBorderLRWidths returns individual left and right border widths in pixels.
-func (c *MJWrapperComponent) getBorderLRWidths() (int, int) {
-	var left, right int
-	if border := c.getAttribute("border"); border != "" {
-		w := styles.ParseBorderWidth(border)
-		left, right = w, w
-	}
-	if bl := c.getAttribute("border-left"); bl != "" {
-		if w := styles.ParseBorderWidth(bl); w > 0 {
-			left = w
-		}
-	}
-	if br := c.getAttribute("border-right"); br != "" {
-		if w := styles.ParseBorderWidth(br); w > 0 {
-			right = w
-		}
-	}
-	return left, right
-}
-
-// getEffectiveWidth calculates width minus border width
+// getEffectiveWidth calculates the width available to child sections: the body
+// width minus the wrapper's horizontal padding and borders.
+// AIDEV-NOTE: wrapper-width-flow; wrapper padding reduces child containerWidth
 func (c *MJWrapperComponent) getEffectiveWidth() int {
-	baseWidth := GetDefaultBodyWidthPixels()
-	borderLeft, borderRight := c.getBorderLRWidths()
-	effectiveWidth := baseWidth - borderLeft - borderRight
-
-	// AIDEV-NOTE: wrapper-width-flow; wrapper padding reduces child containerWidth
-	// Subtract horizontal padding (handle both shorthand and individual properties)
-	// This ensures child sections receive reduced containerWidth accounting for wrapper padding
-	padding := c.getAttribute("padding")
-	if padding != "" {
-		if sp, err := styles.ParseSpacing(padding); err == nil && sp != nil {
-			effectiveWidth -= int(sp.Left + sp.Right)
-		}
-	}
-
-	// Individual properties override shorthand
-	if pl := c.getAttribute(constants.MJMLPaddingLeft); pl != "" {
-		if px, err := styles.ParsePixel(pl); err == nil && px != nil {
-			// If we already subtracted from shorthand, add it back first
-			if padding != "" {
-				if sp, err := styles.ParseSpacing(padding); err == nil && sp != nil {
-					effectiveWidth += int(sp.Left)
-				}
-			}
-			effectiveWidth -= int(px.Value)
-		}
-	}
-	if pr := c.getAttribute(constants.MJMLPaddingRight); pr != "" {
-		if px, err := styles.ParsePixel(pr); err == nil && px != nil {
-			// If we already subtracted from shorthand, add it back first
-			if padding != "" {
-				if sp, err := styles.ParseSpacing(padding); err == nil && sp != nil {
-					effectiveWidth += int(sp.Right)
-				}
-			}
-			effectiveWidth -= int(px.Value)
-		}
-	}
-
-	return effectiveWidth
+	return c.horizontalBox(c, constants.MJMLPadding, constants.MJMLBorder).contentWidth(c.GetEffectiveWidth())
 }
 
 // getChildAlign returns the align attribute for a section child if specified.
@@ -135,7 +58,7 @@ func getWrapperSectionBackground(section *MJSectionComponent, wrapperGap string)
 	if wrapperGap != "" && section.GetAttributeWithDefault(section, "full-width") == "" {
 		return ""
 	}
-	return section.GetAttributeWithDefault(section, "background-color")
+	return section.BackgroundFallbackColor(section)
 }
 
 func getWrapperSectionGap(gap string, sectionIndex int) string {
@@ -189,8 +112,7 @@ func (c *MJWrapperComponent) shouldUseOuterOnlyMSOWrapper() bool {
 		hasSection = true
 
 		fullWidth := section.GetAttributeWithDefault(section, "full-width")
-		backgroundURL := section.GetAttributeWithDefault(section, constants.MJMLBackgroundUrl)
-		consumesWrapperTable := fullWidth != "" && backgroundURL != ""
+		consumesWrapperTable := fullWidth != "" && section.hasBackgroundImage()
 
 		anyConsumer = anyConsumer || consumesWrapperTable
 		if !consumesWrapperTable {
@@ -235,21 +157,12 @@ func (c *MJWrapperComponent) renderFullWidthToWriter(w io.StringWriter) error {
 	textAlign := c.getAttribute("text-align")
 	direction := c.getAttribute("direction")
 	cssClass := c.getAttribute("css-class")
-	wrapperBgColor := c.getAttribute("background-color")
+	wrapperBgColor := c.BackgroundFallbackColor(c)
 	wrapperGap := c.getAttribute("gap")
+	borderRadius := c.getAttribute("border-radius")
 
 	// Calculate effective content width by subtracting horizontal padding and border widths
-	effectiveWidth := GetDefaultBodyWidthPixels() - c.getBorderWidth()
-	if pl := c.getAttribute(constants.MJMLPaddingLeft); pl != "" {
-		if px, err := styles.ParsePixel(pl); err == nil && px != nil {
-			effectiveWidth -= int(px.Value)
-		}
-	}
-	if pr := c.getAttribute(constants.MJMLPaddingRight); pr != "" {
-		if px, err := styles.ParsePixel(pr); err == nil && px != nil {
-			effectiveWidth -= int(px.Value)
-		}
-	}
+	effectiveWidth := c.getEffectiveWidth()
 
 	continueMSOComment := false
 	if c.RenderOpts != nil && c.RenderOpts.PendingMSOSectionClose {
@@ -296,8 +209,8 @@ func (c *MJWrapperComponent) renderFullWidthToWriter(w io.StringWriter) error {
 	}
 
 	msoTable.AddAttribute("role", "presentation")
-	msoTable.AddAttribute("style", "width:"+GetDefaultBodyWidth()+";")
-	msoTable.AddAttribute("width", strconv.Itoa(GetDefaultBodyWidthPixels()))
+	msoTable.AddAttribute("style", "width:"+c.GetEffectiveWidthString()+";")
+	msoTable.AddAttribute("width", strconv.Itoa(c.GetEffectiveWidth()))
 
 	// Add bgcolor to MSO table if background-color is set (after width to match expected order)
 	if wrapperBgColor != "" {
@@ -323,7 +236,11 @@ func (c *MJWrapperComponent) renderFullWidthToWriter(w io.StringWriter) error {
 	// Inner constrained div (standard MRML pattern)
 	innerDiv := html.NewHTMLTag("div").
 		AddStyle("margin", "0px auto").
-		AddStyle("max-width", GetDefaultBodyWidth())
+		AddStyle("max-width", c.GetEffectiveWidthString())
+	if borderRadius != "" {
+		innerDiv.AddStyle("border-radius", borderRadius)
+		innerDiv.AddStyle("overflow", "hidden")
+	}
 
 	if err := innerDiv.RenderOpen(w); err != nil {
 		return err
@@ -337,6 +254,9 @@ func (c *MJWrapperComponent) renderFullWidthToWriter(w io.StringWriter) error {
 		AddAttribute("role", "presentation").
 		AddAttribute("align", "center").
 		AddStyle("width", "100%")
+	if borderRadius != "" {
+		innerTable.AddStyle(constants.CSSBorderCollapse, constants.BorderCollapseSeparate)
+	}
 
 	if err := innerTable.RenderOpen(w); err != nil {
 		return err
@@ -363,6 +283,9 @@ func (c *MJWrapperComponent) renderFullWidthToWriter(w io.StringWriter) error {
 	if borderTop := c.getAttribute("border-top"); borderTop != "" {
 		innerTd.AddStyle("border-top", borderTop)
 	}
+	if borderRadius != "" {
+		innerTd.AddStyle("border-radius", borderRadius)
+	}
 
 	innerTd.AddStyle("direction", direction).
 		AddStyle("font-size", "0px").
@@ -406,7 +329,7 @@ func (c *MJWrapperComponent) renderFullWidthToWriter(w io.StringWriter) error {
 	splitMSOWrapper := c.hasFullWidthSectionChild()
 	msoBgColor := firstBgColor
 	if msoBgColor == "" && splitMSOWrapper {
-		msoBgColor = c.getAttribute("background-color")
+		msoBgColor = c.BackgroundFallbackColor(c)
 	}
 
 	useOuterOnlyMSO := c.shouldUseOuterOnlyMSOWrapper()
@@ -475,8 +398,8 @@ func (c *MJWrapperComponent) renderFullWidthToWriter(w io.StringWriter) error {
 	for i, child := range c.Children {
 		if child.IsRawElement() {
 			// Inject raw content inside the MSO transition block so Outlook maintains table structure
-			if err := html.RenderMSOSectionTransitionWithContent(w, GetDefaultBodyWidthPixels(), effectiveWidth, "", "", false, forceWrapperTableRaw, "", func(sw io.StringWriter) error {
-				return child.Render(sw)
+			if err := html.RenderMSOSectionTransitionWithContent(w, c.GetEffectiveWidth(), effectiveWidth, "", "", false, forceWrapperTableRaw, "", func(sw io.StringWriter) error {
+				return c.RenderChild(child, sw)
 			}); err != nil {
 				return err
 			}
@@ -494,7 +417,7 @@ func (c *MJWrapperComponent) renderFullWidthToWriter(w io.StringWriter) error {
 			if sectionComp, ok := child.(*MJSectionComponent); ok {
 				nextBgColor = getWrapperSectionBackground(sectionComp, wrapperGap)
 				if nextBgColor == "" && delegatedWrapperBackground {
-					if sectionComp.GetAttributeWithDefault(sectionComp, "full-width") != "" && sectionComp.GetAttributeWithDefault(sectionComp, constants.MJMLBackgroundUrl) == "" {
+					if sectionComp.GetAttributeWithDefault(sectionComp, "full-width") != "" && !sectionComp.hasBackgroundImage() {
 						nextBgColor = wrapperBgColor
 					}
 				}
@@ -505,7 +428,7 @@ func (c *MJWrapperComponent) renderFullWidthToWriter(w io.StringWriter) error {
 					closeWrapper = false
 				}
 			}
-			if err := html.RenderMSOSectionTransition(w, GetDefaultBodyWidthPixels(), effectiveWidth, getChildAlign(child), nextBgColor, closeWrapper, forceWrapperTableSections, getWrapperSectionGap(wrapperGap, currentSectionIndex)); err != nil {
+			if err := html.RenderMSOSectionTransition(w, c.GetEffectiveWidth(), effectiveWidth, getChildAlign(child), nextBgColor, closeWrapper, forceWrapperTableSections, getWrapperSectionGap(wrapperGap, currentSectionIndex)); err != nil {
 				return err
 			}
 		}
@@ -519,8 +442,8 @@ func (c *MJWrapperComponent) renderFullWidthToWriter(w io.StringWriter) error {
 
 		if delegatedWrapperBackground {
 			if sectionComp, ok := child.(*MJSectionComponent); ok {
-				if sectionComp.GetAttributeWithDefault(sectionComp, "full-width") != "" && sectionComp.GetAttributeWithDefault(sectionComp, constants.MJMLBackgroundUrl) == "" {
-					sectionBg := sectionComp.GetAttributeWithDefault(sectionComp, "background-color")
+				if sectionComp.GetAttributeWithDefault(sectionComp, "full-width") != "" && !sectionComp.hasBackgroundImage() {
+					sectionBg := sectionComp.BackgroundFallbackColor(sectionComp)
 					if sectionBg == "" {
 						sectionBg = wrapperBgColor
 					}
@@ -531,7 +454,7 @@ func (c *MJWrapperComponent) renderFullWidthToWriter(w io.StringWriter) error {
 				}
 			}
 		}
-		if err := child.Render(w); err != nil {
+		if err := c.RenderChild(child, w); err != nil {
 			return err
 		}
 		if child.GetTagName() == "mj-section" {
@@ -595,7 +518,7 @@ func (c *MJWrapperComponent) renderSimpleToWriter(w io.StringWriter) error {
 	direction := c.getAttribute("direction")
 	cssClass := c.getAttribute("css-class")
 	borderRadius := c.getAttribute("border-radius")
-	wrapperBgColor := c.getAttribute("background-color")
+	wrapperBgColor := c.BackgroundFallbackColor(c)
 	wrapperGap := c.getAttribute("gap")
 	effectiveWidth := c.getEffectiveWidth()
 
@@ -627,8 +550,8 @@ func (c *MJWrapperComponent) renderSimpleToWriter(w io.StringWriter) error {
 	}
 
 	msoTable.AddAttribute("role", "presentation")
-	msoTable.AddAttribute("style", "width:"+GetDefaultBodyWidth()+";")
-	msoTable.AddAttribute("width", strconv.Itoa(GetDefaultBodyWidthPixels()))
+	msoTable.AddAttribute("style", "width:"+c.GetEffectiveWidthString()+";")
+	msoTable.AddAttribute("width", strconv.Itoa(c.GetEffectiveWidth()))
 
 	// Add bgcolor to MSO table if background-color is set (after width to match expected order)
 	if wrapperBgColor != "" {
@@ -667,7 +590,7 @@ func (c *MJWrapperComponent) renderSimpleToWriter(w io.StringWriter) error {
 	wrapperDiv.AddStyle("margin", "0px auto")
 
 	// Order styles to match MJML output: margin -> max-width -> border-radius -> overflow
-	wrapperDiv.AddStyle("max-width", GetDefaultBodyWidth())
+	wrapperDiv.AddStyle("max-width", c.GetEffectiveWidthString())
 
 	if borderRadius != "" {
 		wrapperDiv.AddStyle("border-radius", borderRadius)
@@ -766,7 +689,7 @@ func (c *MJWrapperComponent) renderSimpleToWriter(w io.StringWriter) error {
 	splitMSOWrapper := c.hasFullWidthSectionChild()
 	msoBgColor := firstBgColor
 	if msoBgColor == "" && splitMSOWrapper {
-		msoBgColor = c.getAttribute("background-color")
+		msoBgColor = c.BackgroundFallbackColor(c)
 	}
 
 	// For basic wrapper, we need a specific MSO conditional pattern
@@ -838,7 +761,7 @@ func (c *MJWrapperComponent) renderSimpleToWriter(w io.StringWriter) error {
 	for i, child := range c.Children {
 		if child.IsRawElement() {
 			if err := html.RenderMSOSectionTransitionWithContent(w, outerWidth, effectiveWidth, "", "", false, forceWrapperTableRaw, "", func(sw io.StringWriter) error {
-				return child.Render(sw)
+				return c.RenderChild(child, sw)
 			}); err != nil {
 				return err
 			}
@@ -856,7 +779,7 @@ func (c *MJWrapperComponent) renderSimpleToWriter(w io.StringWriter) error {
 			if sectionComp, ok := child.(*MJSectionComponent); ok {
 				nextBgColor = getWrapperSectionBackground(sectionComp, wrapperGap)
 				if nextBgColor == "" && delegatedWrapperBackground {
-					if sectionComp.GetAttributeWithDefault(sectionComp, "full-width") != "" && sectionComp.GetAttributeWithDefault(sectionComp, constants.MJMLBackgroundUrl) == "" {
+					if sectionComp.GetAttributeWithDefault(sectionComp, "full-width") != "" && !sectionComp.hasBackgroundImage() {
 						nextBgColor = wrapperBgColor
 					}
 				}
@@ -881,8 +804,8 @@ func (c *MJWrapperComponent) renderSimpleToWriter(w io.StringWriter) error {
 
 		if delegatedWrapperBackground {
 			if sectionComp, ok := child.(*MJSectionComponent); ok {
-				if sectionComp.GetAttributeWithDefault(sectionComp, "full-width") != "" && sectionComp.GetAttributeWithDefault(sectionComp, constants.MJMLBackgroundUrl) == "" {
-					sectionBg := sectionComp.GetAttributeWithDefault(sectionComp, "background-color")
+				if sectionComp.GetAttributeWithDefault(sectionComp, "full-width") != "" && !sectionComp.hasBackgroundImage() {
+					sectionBg := sectionComp.BackgroundFallbackColor(sectionComp)
 					if sectionBg == "" {
 						sectionBg = wrapperBgColor
 					}
@@ -893,7 +816,7 @@ func (c *MJWrapperComponent) renderSimpleToWriter(w io.StringWriter) error {
 				}
 			}
 		}
-		if err := child.Render(w); err != nil {
+		if err := c.RenderChild(child, w); err != nil {
 			return err
 		}
 		if child.GetTagName() == "mj-section" {
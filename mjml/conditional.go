@@ -0,0 +1,207 @@
+package mjml
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/parser"
+)
+
+// conditionalTag is the gomjml extension element that includes its children only
+// when its test expression holds for the variables passed via WithVariables.
+//
+//	<mj-cond test="user.plan == 'premium' && !unsubscribed">
+//	  <mj-text>Thanks for being a premium member!</mj-text>
+//	</mj-cond>
+//
+// mj-cond may wrap any MJML elements and is resolved before the component tree
+// is built, so it never reaches the output. Inside ending tags such as mj-text
+// the content is raw HTML and mj-cond is not interpreted.
+const conditionalTag = "mj-cond"
+
+// WithVariables sets the values that mj-cond test expressions are evaluated
+// against. Nested maps are addressed with dotted paths such as "user.plan".
+func WithVariables(vars map[string]any) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.Variables = vars
+	}
+}
+
+// expandConditionals returns node with every mj-cond element replaced by its
+// children when its test passes and removed otherwise. Parsed ASTs may be shared
+// through the cache, so affected nodes are copied instead of modified; subtrees
+// without mj-cond are returned as is.
+func expandConditionals(node *MJMLNode, vars map[string]any) *MJMLNode {
+	if node == nil || !containsConditional(node) {
+		return node
+	}
+
+	expanded := *node
+	expanded.Children = make([]*MJMLNode, 0, len(node.Children))
+	for _, child := range node.Children {
+		expanded.Children = append(expanded.Children, expandConditionalChild(child, vars)...)
+	}
+
+	expanded.MixedContent = make([]parser.MixedContentPart, 0, len(node.MixedContent))
+	for _, part := range node.MixedContent {
+		if part.Node == nil {
+			expanded.MixedContent = append(expanded.MixedContent, part)
+			continue
+		}
+		if part.Node.GetTagName() != conditionalTag {
+			expanded.MixedContent = append(expanded.MixedContent, parser.MixedContentPart{Node: expandConditionals(part.Node, vars)})
+			continue
+		}
+		if evaluateCondition(part.Node.GetAttribute("test"), vars) {
+			inner := expandConditionals(part.Node, vars)
+			expanded.MixedContent = append(expanded.MixedContent, inner.MixedContent...)
+		}
+	}
+
+	return &expanded
+}
+
+// expandConditionalChild returns the nodes that child contributes to its parent.
+func expandConditionalChild(child *MJMLNode, vars map[string]any) []*MJMLNode {
+	if child.GetTagName() != conditionalTag {
+		return []*MJMLNode{expandConditionals(child, vars)}
+	}
+	if !evaluateCondition(child.GetAttribute("test"), vars) {
+		return nil
+	}
+	return expandConditionals(child, vars).Children
+}
+
+func containsConditional(node *MJMLNode) bool {
+	for _, child := range node.Children {
+		if child.GetTagName() == conditionalTag || containsConditional(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateCondition evaluates a test expression. The grammar is deliberately
+// small: terms joined by "||" and "&&" (with && binding tighter), where a term
+// is a variable path, optionally negated with "!", or a comparison of a path
+// with a literal using "==" or "!=". Literals may be quoted with single or
+// double quotes, and operators inside quoted literals are part of the literal;
+// values are compared by their string form. Unknown variables are empty, and
+// an empty expression is false.
+func evaluateCondition(expr string, vars map[string]any) bool {
+	for _, alternative := range splitOutsideQuotes(expr, "||") {
+		matched := true
+		for _, term := range splitOutsideQuotes(alternative, "&&") {
+			if !evaluateTerm(strings.TrimSpace(term), vars) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func evaluateTerm(term string, vars map[string]any) bool {
+	if term == "" {
+		return false
+	}
+	if left, right, ok := cutOutsideQuotes(term, "!="); ok {
+		return !compareVariable(left, right, vars)
+	}
+	if left, right, ok := cutOutsideQuotes(term, "=="); ok {
+		return compareVariable(left, right, vars)
+	}
+	if negated, ok := strings.CutPrefix(term, "!"); ok {
+		return !evaluateTerm(strings.TrimSpace(negated), vars)
+	}
+	return isTruthy(lookupVariable(term, vars))
+}
+
+// splitOutsideQuotes splits s around each sep that is not inside a quoted
+// literal.
+func splitOutsideQuotes(s, sep string) []string {
+	var parts []string
+	for {
+		before, after, ok := cutOutsideQuotes(s, sep)
+		if !ok {
+			return append(parts, s)
+		}
+		parts = append(parts, before)
+		s = after
+	}
+}
+
+// cutOutsideQuotes is strings.Cut for the first sep that is not inside a
+// quoted literal. A literal left unclosed runs to the end of s.
+func cutOutsideQuotes(s, sep string) (before, after string, found bool) {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case strings.HasPrefix(s[i:], sep):
+			return s[:i], s[i+len(sep):], true
+		}
+	}
+	return s, "", false
+}
+
+func compareVariable(path, literal string, vars map[string]any) bool {
+	value := lookupVariable(strings.TrimSpace(path), vars)
+	literal = strings.TrimSpace(literal)
+	if len(literal) >= 2 && (literal[0] == '\'' || literal[0] == '"') && literal[len(literal)-1] == literal[0] {
+		literal = literal[1 : len(literal)-1]
+	}
+	if value == nil {
+		return literal == ""
+	}
+	return fmt.Sprint(value) == literal
+}
+
+// lookupVariable resolves a dotted path through nested string-keyed maps.
+func lookupVariable(path string, vars map[string]any) any {
+	var current any = vars
+	for _, key := range strings.Split(path, ".") {
+		switch m := current.(type) {
+		case map[string]any:
+			value, ok := m[key]
+			if !ok {
+				return nil
+			}
+			current = value
+		case map[string]string:
+			value, ok := m[key]
+			if !ok {
+				return nil
+			}
+			current = value
+		default:
+			return nil
+		}
+	}
+	return current
+}
+
+// isTruthy reports whether value counts as true in a condition: nil, false,
+// zero numbers, empty strings and empty collections are false.
+func isTruthy(value any) bool {
+	if value == nil {
+		return false
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return v.Len() > 0
+	case reflect.Pointer, reflect.Interface:
+		return !v.IsNil()
+	}
+	return !v.IsZero()
+}
@@ -0,0 +1,116 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConditionalContent(t *testing.T) {
+	const doc = `<mjml><mj-body>
+<mj-cond test="showBanner"><mj-section><mj-column><mj-text>Banner</mj-text></mj-column></mj-section></mj-cond>
+<mj-section><mj-column>
+<mj-text>Hello</mj-text>
+<mj-cond test="user.plan == 'premium'"><mj-text>Premium perks</mj-text><mj-button href="#">Claim</mj-button></mj-cond>
+<mj-cond test="!user.plan || user.plan != 'premium'"><mj-text>Upgrade today</mj-text></mj-cond>
+</mj-column></mj-section>
+</mj-body></mjml>`
+
+	tests := []struct {
+		name    string
+		vars    map[string]any
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "no variables",
+			want:    []string{"Hello", "Upgrade today"},
+			notWant: []string{"Banner", "Premium perks", "Claim"},
+		},
+		{
+			name:    "premium user",
+			vars:    map[string]any{"showBanner": true, "user": map[string]any{"plan": "premium"}},
+			want:    []string{"Banner", "Hello", "Premium perks", "Claim"},
+			notWant: []string{"Upgrade today"},
+		},
+		{
+			name:    "free user",
+			vars:    map[string]any{"showBanner": 0, "user": map[string]any{"plan": "free"}},
+			want:    []string{"Hello", "Upgrade today"},
+			notWant: []string{"Banner", "Premium perks"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, err := Render(doc, WithVariables(tt.vars))
+			if err != nil {
+				t.Fatalf("render: %v", err)
+			}
+			if strings.Contains(html, "mj-cond") {
+				t.Error("mj-cond leaked into the output")
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(html, s) {
+					t.Errorf("expected %q in output", s)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(html, s) {
+					t.Errorf("did not expect %q in output", s)
+				}
+			}
+		})
+	}
+}
+
+func TestConditionalContentDoesNotMutateCachedAST(t *testing.T) {
+	resetASTCache()
+	const doc = `<mjml><mj-body><mj-section><mj-column><mj-cond test="on"><mj-text>Shown</mj-text></mj-cond></mj-column></mj-section></mj-body></mjml>`
+
+	on, err := Render(doc, WithCache(), WithVariables(map[string]any{"on": true}))
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	off, err := Render(doc, WithCache())
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(on, "Shown") || strings.Contains(off, "Shown") {
+		t.Fatal("expected each render to evaluate conditions against its own variables")
+	}
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	vars := map[string]any{
+		"count":  3,
+		"empty":  "",
+		"tags":   []string{"a"},
+		"labels": map[string]string{"tier": "gold"},
+		"plan":   "a||b",
+		"note":   "x && y",
+		"mode":   "a!=b",
+	}
+	tests := map[string]bool{
+		"count":                              true,
+		"empty":                              false,
+		"missing":                            false,
+		"!missing":                           true,
+		"tags":                               true,
+		"count == 3":                         true,
+		`labels.tier == "gold"`:              true,
+		"labels.tier != gold":                false,
+		"empty && count":                     false,
+		"empty || count":                     true,
+		`plan == "a||b"`:                     true,
+		`plan == "a"`:                        false,
+		`plan == 'a||b' && note == "x && y"`: true,
+		`mode != "a==b"`:                     true,
+		`mode == "a!=b"`:                     true,
+		"":                                   false,
+	}
+	for expr, want := range tests {
+		if got := evaluateCondition(expr, vars); got != want {
+			t.Errorf("evaluateCondition(%q) = %v, want %v", expr, got, want)
+		}
+	}
+}
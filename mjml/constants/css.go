@@ -151,6 +151,7 @@ const (
 	MJMLCSSClass                 = "css-class"
 	MJMLContainerBackgroundColor = "container-background-color"
 	MJMLInnerPadding             = "inner-padding"
+	MJMLInnerBackgroundColor     = "inner-background-color"
 	MJMLTextPadding              = "text-padding"
 	MJMLIconSize                 = "icon-size"
 	MJMLIconHeight               = "icon-height"
@@ -165,6 +166,7 @@ const (
 	MJMLTitle                    = "title"
 	MJMLFullWidth                = "full-width"
 	MJMLFluidOnMobile            = "fluid-on-mobile"
+	MJMLI18nKey                  = "i18n-key"
 )
 
 // Common CSS values
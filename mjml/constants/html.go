@@ -32,4 +32,5 @@ const (
 	// it's much better than setting nothing"
 	LangUndetermined = "und"
 	DirAuto          = "auto"
+	DirRTL           = "rtl"
 )
@@ -0,0 +1,150 @@
+package cssmatch
+
+import (
+	"reflect"
+	"testing"
+)
+
+// tree builds body > table#main.layout > td.cell > p.note > a
+func tree() (body, table, td, p, a Element) {
+	body = NewElement("body", "", "", nil)
+	table = NewElement("table", "main", "layout", body)
+	td = NewElement("td", "", "cell", table)
+	p = NewElement("p", "", "note intro", td)
+	a = NewElement("a", "", "", p)
+	return
+}
+
+func TestSelectorMatch(t *testing.T) {
+	_, table, td, p, a := tree()
+
+	tests := []struct {
+		selector string
+		element  Element
+		want     bool
+	}{
+		{"a", a, true},
+		{"A", a, true},
+		{"p", a, false},
+		{"*", a, true},
+		{".note", p, true},
+		{".note.intro", p, true},
+		{".note.missing", p, false},
+		{"p.note", p, true},
+		{"td.note", p, false},
+		{"#main", table, true},
+		{"table#main.layout", table, true},
+		{"#other", table, false},
+		{"p a", a, true},
+		{"table a", a, true},
+		{"#main .note a", a, true},
+		{"td > p > a", a, true},
+		{"td > a", a, false},
+		{"td>p", p, true},
+		{"table > p", p, false},
+		{".layout td > p a", a, true},
+		{".missing a", a, false},
+		{"td p td", td, false},
+	}
+	for _, tt := range tests {
+		selector, err := Parse(tt.selector)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.selector, err)
+		}
+		if got := selector.Match(tt.element); got != tt.want {
+			t.Errorf("%q.Match(%s) = %v, want %v", tt.selector, tt.element.TagName(), got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, selector := range []string{"", "a:hover", "a[href]", "h1 + p", "h1 ~ p", "> p", "p >", "p > > a", "#a#b", ".", "p,"} {
+		if _, err := Parse(selector); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", selector)
+		}
+	}
+}
+
+func TestSpecificity(t *testing.T) {
+	tests := map[string]Specificity{
+		"*":                 {0, 0, 0},
+		"p":                 {0, 0, 1},
+		"p a":               {0, 0, 2},
+		".note":             {0, 1, 0},
+		"p.note.intro":      {0, 2, 1},
+		"#main":             {1, 0, 0},
+		"#main td > p.note": {1, 1, 2},
+	}
+	for selector, want := range tests {
+		s, err := Parse(selector)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", selector, err)
+		}
+		if got := s.Specificity(); got != want {
+			t.Errorf("%q specificity = %v, want %v", selector, got, want)
+		}
+	}
+
+	if !(Specificity{0, 5, 5}).Less(Specificity{1, 0, 0}) {
+		t.Error("an id should outrank any number of classes")
+	}
+	if (Specificity{0, 1, 0}).Less(Specificity{0, 1, 0}) {
+		t.Error("equal specificities are not less")
+	}
+}
+
+func TestParseStylesheet(t *testing.T) {
+	rules := ParseStylesheet(`
+		/* comment { color: red } */
+		@import url("x.css");
+		@media (max-width: 480px) { .note { color: red; } }
+		p, .note { color: #333; margin: 0 }
+		a:hover { color: blue }
+		.bg { background: url("data:image/png;base64,AAAA") no-repeat }
+	`)
+
+	var selectors []string
+	for _, rule := range rules {
+		selectors = append(selectors, rule.Selector.String())
+	}
+	if want := []string{"p", ".note", ".bg"}; !reflect.DeepEqual(selectors, want) {
+		t.Fatalf("selectors = %v, want %v", selectors, want)
+	}
+
+	wantDecls := []Declaration{{"color", "#333"}, {"margin", "0"}}
+	if !reflect.DeepEqual(rules[1].Declarations, wantDecls) {
+		t.Errorf("declarations = %v, want %v", rules[1].Declarations, wantDecls)
+	}
+	if got := rules[2].Declarations[0].Value; got != `url("data:image/png;base64,AAAA") no-repeat` {
+		t.Errorf("background value = %q", got)
+	}
+}
+
+func TestDeclarationsCascade(t *testing.T) {
+	rules := ParseStylesheet(`
+		#main .note a { color: green }
+		a { color: red; text-decoration: none }
+		.note a { color: blue; font-weight: bold }
+		p a { color: orange }
+	`)
+	_, _, _, _, a := tree()
+
+	got := Declarations(rules, a)
+	want := []Declaration{
+		{"color", "green"},
+		{"text-decoration", "none"},
+		{"font-weight", "bold"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Declarations = %v, want %v", got, want)
+	}
+
+	matched := Match(rules, a)
+	var order []string
+	for _, rule := range matched {
+		order = append(order, rule.Selector.String())
+	}
+	if want := []string{"a", "p a", ".note a", "#main .note a"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("cascade order = %v, want %v", order, want)
+	}
+}
@@ -0,0 +1,264 @@
+// Package cssmatch matches CSS selectors against element trees and orders the
+// matching rules by specificity, the way a browser cascades a stylesheet.
+//
+// It supports the selectors found in email stylesheets: type (p), universal
+// (*), class (.note), id (#header), compounds of these (p.note#intro) and the
+// descendant (div p) and child (div > p) combinators. Pseudo-classes, attribute
+// selectors and sibling combinators are rejected by Parse.
+//
+// Elements are described through the Element interface, so the same rules can
+// be matched against rendered HTML and against MJML nodes.
+package cssmatch
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Element is the view of a document element needed for matching.
+type Element interface {
+	TagName() string
+	ID() string
+	HasClass(name string) bool
+	Parent() Element // nil for the root of the tree being matched
+}
+
+// Specificity orders selectors as (id count, class count, type count).
+type Specificity [3]int
+
+// Less reports whether s is less specific than other.
+func (s Specificity) Less(other Specificity) bool {
+	for i := range s {
+		if s[i] != other[i] {
+			return s[i] < other[i]
+		}
+	}
+	return false
+}
+
+// compound is a sequence of simple selectors that must all match one element.
+type compound struct {
+	tag     string // "" or "*" matches any tag
+	id      string
+	classes []string
+}
+
+// Combinators joining two compounds.
+const (
+	descendant = ' '
+	child      = '>'
+)
+
+// Selector is a parsed complex selector such as "table.main td > a".
+type Selector struct {
+	text        string
+	compounds   []compound
+	combinators []byte // combinators[i] joins compounds[i] and compounds[i+1]
+	specificity Specificity
+}
+
+// Parse parses a single selector. Selector lists ("a, b") must be split first.
+func Parse(selector string) (*Selector, error) {
+	text := strings.TrimSpace(selector)
+	if text == "" {
+		return nil, fmt.Errorf("cssmatch: empty selector")
+	}
+
+	s := &Selector{text: text}
+	pos := 0
+	pendingCombinator := byte(0)
+	for pos < len(text) {
+		switch c := text[pos]; {
+		case isSpace(c):
+			pos++
+			if pendingCombinator == 0 && len(s.compounds) > 0 {
+				pendingCombinator = descendant
+			}
+			continue
+		case c == '>':
+			if len(s.compounds) == 0 {
+				return nil, fmt.Errorf("cssmatch: %q starts with a combinator", text)
+			}
+			if pendingCombinator == child {
+				return nil, fmt.Errorf("cssmatch: %q has two combinators in a row", text)
+			}
+			pendingCombinator = child
+			pos++
+			continue
+		case c == '+' || c == '~':
+			return nil, fmt.Errorf("cssmatch: sibling combinator %q is not supported in %q", c, text)
+		}
+
+		comp, next, err := parseCompound(text, pos)
+		if err != nil {
+			return nil, err
+		}
+		if len(s.compounds) > 0 {
+			s.combinators = append(s.combinators, pendingCombinator)
+		}
+		s.compounds = append(s.compounds, comp)
+		pendingCombinator = 0
+		pos = next
+	}
+	if pendingCombinator == child {
+		return nil, fmt.Errorf("cssmatch: %q ends with a combinator", text)
+	}
+
+	for _, comp := range s.compounds {
+		if comp.id != "" {
+			s.specificity[0]++
+		}
+		s.specificity[1] += len(comp.classes)
+		if comp.tag != "" && comp.tag != "*" {
+			s.specificity[2]++
+		}
+	}
+	return s, nil
+}
+
+func parseCompound(text string, pos int) (compound, int, error) {
+	var comp compound
+	start := pos
+
+	if text[pos] == '*' {
+		comp.tag = "*"
+		pos++
+	} else if name, next := readIdent(text, pos); name != "" {
+		comp.tag = strings.ToLower(name)
+		pos = next
+	}
+
+	for pos < len(text) {
+		c := text[pos]
+		if isSpace(c) || c == '>' || c == '+' || c == '~' {
+			break
+		}
+		switch c {
+		case '.', '#':
+			name, next := readIdent(text, pos+1)
+			if name == "" {
+				return comp, pos, fmt.Errorf("cssmatch: expected a name after %q in %q", c, text)
+			}
+			if c == '.' {
+				comp.classes = append(comp.classes, name)
+			} else if comp.id != "" && comp.id != name {
+				return comp, pos, fmt.Errorf("cssmatch: %q has two ids in one compound", text)
+			} else {
+				comp.id = name
+			}
+			pos = next
+		case ':':
+			return comp, pos, fmt.Errorf("cssmatch: pseudo-class in %q is not supported", text)
+		case '[':
+			return comp, pos, fmt.Errorf("cssmatch: attribute selector in %q is not supported", text)
+		default:
+			return comp, pos, fmt.Errorf("cssmatch: unexpected %q in %q", c, text)
+		}
+	}
+
+	if pos == start {
+		return comp, pos, fmt.Errorf("cssmatch: unexpected %q in %q", text[pos], text)
+	}
+	return comp, pos, nil
+}
+
+// readIdent reads a CSS identifier starting at pos.
+func readIdent(text string, pos int) (string, int) {
+	start := pos
+	for pos < len(text) {
+		c := text[pos]
+		if c == '-' || c == '_' || c >= 0x80 ||
+			(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			pos++
+			continue
+		}
+		break
+	}
+	return text[start:pos], pos
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// String returns the selector text.
+func (s *Selector) String() string {
+	return s.text
+}
+
+// Specificity returns the selector's specificity.
+func (s *Selector) Specificity() Specificity {
+	return s.specificity
+}
+
+// Match reports whether el matches the selector.
+func (s *Selector) Match(el Element) bool {
+	if el == nil {
+		return false
+	}
+	return s.matchFrom(len(s.compounds)-1, el)
+}
+
+// matchFrom matches compounds[:i+1] with compounds[i] anchored at el, walking
+// ancestors right to left and backtracking across descendant combinators.
+func (s *Selector) matchFrom(i int, el Element) bool {
+	if !s.compounds[i].match(el) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+
+	parent := el.Parent()
+	if s.combinators[i-1] == child {
+		return parent != nil && s.matchFrom(i-1, parent)
+	}
+	for ; parent != nil; parent = parent.Parent() {
+		if s.matchFrom(i-1, parent) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *compound) match(el Element) bool {
+	if c.tag != "" && c.tag != "*" && !strings.EqualFold(c.tag, el.TagName()) {
+		return false
+	}
+	if c.id != "" && c.id != el.ID() {
+		return false
+	}
+	for _, class := range c.classes {
+		if !el.HasClass(class) {
+			return false
+		}
+	}
+	return true
+}
+
+// element is the Element returned by NewElement.
+type element struct {
+	tag     string
+	id      string
+	classes []string
+	parent  Element
+}
+
+// NewElement describes an element by its tag name, id attribute and class
+// attribute (a space-separated list). parent may be nil.
+func NewElement(tag, id, class string, parent Element) Element {
+	return &element{tag: tag, id: id, classes: strings.Fields(class), parent: parent}
+}
+
+func (e *element) TagName() string { return e.tag }
+func (e *element) ID() string      { return e.id }
+func (e *element) Parent() Element { return e.parent }
+
+func (e *element) HasClass(name string) bool {
+	for _, class := range e.classes {
+		if class == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package cssmatch
+
+import (
+	"sort"
+	"strings"
+)
+
+// Declaration is a single CSS property and value.
+type Declaration struct {
+	Property string
+	Value    string
+}
+
+// Rule pairs one selector with the declarations of its rule block. A rule
+// with a selector list becomes one Rule per selector, sharing declarations.
+type Rule struct {
+	Selector     *Selector
+	Declarations []Declaration
+}
+
+// ParseStylesheet parses the style rules of css in source order. At-rules such
+// as @media, comments, and selectors Parse rejects are skipped, since they
+// cannot be applied inline.
+func ParseStylesheet(css string) []Rule {
+	text := stripComments(css)
+
+	var rules []Rule
+	for {
+		text = strings.TrimLeft(text, " \t\n\r\f")
+		if text == "" {
+			return rules
+		}
+		if text[0] == '@' {
+			text = skipAtRule(text)
+			continue
+		}
+
+		open := strings.IndexByte(text, '{')
+		if open < 0 {
+			return rules
+		}
+		selectorList := text[:open]
+		text = text[open+1:]
+
+		body := text
+		if end := strings.IndexByte(text, '}'); end >= 0 {
+			body = text[:end]
+			text = text[end+1:]
+		} else {
+			text = ""
+		}
+
+		declarations := ParseDeclarations(body)
+		if len(declarations) == 0 {
+			continue
+		}
+		for _, part := range strings.Split(selectorList, ",") {
+			selector, err := Parse(part)
+			if err != nil {
+				continue
+			}
+			rules = append(rules, Rule{Selector: selector, Declarations: declarations})
+		}
+	}
+}
+
+// ParseDeclarations parses the body of a rule block or a style attribute.
+// Semicolons inside quotes and parentheses (as in data: URLs) do not split.
+func ParseDeclarations(body string) []Declaration {
+	var declarations []Declaration
+	for _, part := range splitDeclarations(body) {
+		colon := strings.IndexByte(part, ':')
+		if colon < 0 {
+			continue
+		}
+		property := strings.TrimSpace(part[:colon])
+		value := strings.TrimSpace(part[colon+1:])
+		if property == "" || value == "" {
+			continue
+		}
+		declarations = append(declarations, Declaration{Property: property, Value: value})
+	}
+	return declarations
+}
+
+func splitDeclarations(body string) []string {
+	var parts []string
+	depth := 0
+	quote := byte(0)
+	start := 0
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case c == ';' && depth == 0:
+			parts = append(parts, body[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, body[start:])
+}
+
+func stripComments(css string) string {
+	if !strings.Contains(css, "/*") {
+		return css
+	}
+	var sb strings.Builder
+	for {
+		start := strings.Index(css, "/*")
+		if start < 0 {
+			sb.WriteString(css)
+			return sb.String()
+		}
+		sb.WriteString(css[:start])
+		end := strings.Index(css[start+2:], "*/")
+		if end < 0 {
+			return sb.String()
+		}
+		css = css[start+2+end+2:]
+	}
+}
+
+// skipAtRule returns text after the at-rule it starts with, which ends either
+// at a semicolon or with its balanced block.
+func skipAtRule(text string) string {
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case ';':
+			if depth == 0 {
+				return text[i+1:]
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth <= 0 {
+				return text[i+1:]
+			}
+		}
+	}
+	return ""
+}
+
+// Match returns the rules whose selector matches el, in cascade order: by
+// ascending specificity, keeping stylesheet order between equal specificities.
+func Match(rules []Rule, el Element) []Rule {
+	var matched []Rule
+	for _, rule := range rules {
+		if rule.Selector.Match(el) {
+			matched = append(matched, rule)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].Selector.Specificity().Less(matched[j].Selector.Specificity())
+	})
+	return matched
+}
+
+// Declarations resolves the declarations that apply to el. Each property is
+// listed once, at its first position, with the value of the winning rule.
+func Declarations(rules []Rule, el Element) []Declaration {
+	var resolved []Declaration
+	index := make(map[string]int)
+	for _, rule := range Match(rules, el) {
+		for _, decl := range rule.Declarations {
+			if i, ok := index[decl.Property]; ok {
+				resolved[i].Value = decl.Value
+				continue
+			}
+			index[decl.Property] = len(resolved)
+			resolved = append(resolved, decl)
+		}
+	}
+	return resolved
+}
@@ -0,0 +1,82 @@
+package debug
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// Logger sends the debug events of a render to a slog.Logger, so they reach
+// the application's structured logging with its levels, sampling and
+// tracing. The zero Logger falls back to the package-level functions, which
+// only log when built with the "debug" tag.
+type Logger struct {
+	logger *slog.Logger
+}
+
+// NewLogger returns a Logger that writes to logger. A nil logger gives the
+// zero Logger.
+func NewLogger(logger *slog.Logger) Logger {
+	return Logger{logger: logger}
+}
+
+// Enabled reports whether debug events are logged. Callers use it to guard
+// building event data.
+func (l Logger) Enabled() bool {
+	if l.logger == nil {
+		return Enabled()
+	}
+	return l.logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// Log logs a debug event with component, phase, and formatted message.
+func (l Logger) Log(component, phase, message string, args ...interface{}) {
+	if l.logger == nil {
+		DebugLog(component, phase, message, args...)
+		return
+	}
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+	l.logger.Debug(message, "component", component, "phase", phase)
+}
+
+// LogWithData logs a debug event with structured data. The data keys become
+// attributes, in sorted order.
+func (l Logger) LogWithData(component, phase, message string, data map[string]interface{}) {
+	if l.logger == nil {
+		DebugLogWithData(component, phase, message, data)
+		return
+	}
+	attrs := make([]slog.Attr, 0, len(data)+2)
+	attrs = append(attrs, slog.String("component", component), slog.String("phase", phase))
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		attrs = append(attrs, slog.Any(key, data[key]))
+	}
+	l.logger.LogAttrs(context.Background(), slog.LevelDebug, message, attrs...)
+}
+
+// LogTiming logs timing information for performance analysis.
+func (l Logger) LogTiming(component, phase, message string, durationMs int64) {
+	if l.logger == nil {
+		DebugLogTiming(component, phase, message, durationMs)
+		return
+	}
+	l.logger.Debug(message, "component", component, "phase", phase, "duration_ms", durationMs)
+}
+
+// LogError logs an error condition during rendering. With a slog.Logger it
+// is logged at warning level, so it needs no Enabled guard.
+func (l Logger) LogError(component, phase, message string, err error) {
+	if l.logger == nil {
+		DebugLogError(component, phase, message, err)
+		return
+	}
+	l.logger.Warn(message, "component", component, "phase", phase, "error", err)
+}
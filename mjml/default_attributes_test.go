@@ -0,0 +1,183 @@
+package mjml
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWithDefaultAttributes(t *testing.T) {
+	defaults := map[string]map[string]string{
+		"mj-text": {"font-family": "Georgia, serif", "color": "#123456"},
+		"mj-all":  {"padding": "7px"},
+	}
+
+	t.Run("applies_without_head", func(t *testing.T) {
+		input := `<mjml><mj-body><mj-section><mj-column>
+  <mj-text>Hello</mj-text>
+</mj-column></mj-section></mj-body></mjml>`
+
+		html, err := Render(input, WithDefaultAttributes(defaults))
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		if !strings.Contains(html, `font-family:Georgia, serif;`) || !strings.Contains(html, `color:#123456;`) {
+			t.Error("expected mj-text defaults to apply")
+		}
+		if !strings.Contains(html, `padding:7px;`) {
+			t.Error("expected mj-all default to apply")
+		}
+	})
+
+	t.Run("document_attributes_win", func(t *testing.T) {
+		input := `<mjml>
+  <mj-head>
+    <mj-attributes>
+      <mj-all font-family="Arial, sans-serif" />
+      <mj-text padding="3px" />
+    </mj-attributes>
+  </mj-head>
+  <mj-body><mj-section><mj-column>
+    <mj-text>Hello</mj-text>
+    <mj-text color="#ff0000">Inline</mj-text>
+  </mj-column></mj-section></mj-body>
+</mjml>`
+
+		html, err := Render(input, WithDefaultAttributes(defaults))
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		if strings.Contains(html, "Georgia") {
+			t.Error("expected the document's mj-all font-family to override the caller default")
+		}
+		if !strings.Contains(html, `padding:3px;word-break:break-word;`) || strings.Contains(html, `padding:7px;word-break:break-word;`) {
+			t.Error("expected the document's mj-text padding to override the caller default")
+		}
+		if !strings.Contains(html, `color:#123456;`) || !strings.Contains(html, `color:#ff0000;`) {
+			t.Error("expected caller color default with the inline attribute taking precedence")
+		}
+	})
+}
+
+func TestConcurrentRendersKeepOwnAttributes(t *testing.T) {
+	const document = `<mjml>
+  <mj-head><mj-attributes>
+    <mj-text color="%s" />
+    <mj-class name="accent" padding="%dpx" />
+  </mj-attributes></mj-head>
+  <mj-body><mj-section><mj-column>
+    <mj-text mj-class="accent">Hello</mj-text>
+  </mj-column></mj-section></mj-body>
+</mjml>`
+	colors := []string{"#111111", "#222222", "#333333", "#444444"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		n := i % len(colors)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			html, err := Render(fmt.Sprintf(document, colors[n], n+1))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if want := fmt.Sprintf("padding:%dpx;", n+1); !strings.Contains(html, want) {
+				t.Errorf("render %d is missing its mj-class %s", n, want)
+			}
+			for j, color := range colors {
+				if got := strings.Contains(html, "color:"+color+";"); got != (j == n) {
+					t.Errorf("render %d: contains mj-text color %s = %v", n, color, got)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMultipleAttributesBlocks(t *testing.T) {
+	html, err := Render(`<mjml>
+  <mj-head>
+    <mj-attributes>
+      <mj-all font-family="Georgia" />
+      <mj-text color="#111111" font-size="20px" />
+      <mj-class name="accent" padding="7px" />
+    </mj-attributes>
+    <mj-attributes>
+      <mj-all font-family="Verdana" />
+      <mj-text color="#222222" />
+      <mj-class name="accent" align="right" />
+    </mj-attributes>
+  </mj-head>
+  <mj-body><mj-section><mj-column>
+    <mj-text mj-class="accent">Hello</mj-text>
+  </mj-column></mj-section></mj-body>
+</mjml>`, WithValidationLevel(ValidationStrict))
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	for _, want := range []string{"font-family:Verdana;", "color:#222222;", "font-size:20px;", "padding:7px;", "text-align:right;"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s from the merged mj-attributes blocks", want)
+		}
+	}
+	for _, stale := range []string{"Georgia", "#111111"} {
+		if strings.Contains(html, stale) {
+			t.Errorf("expected the later block to override %s", stale)
+		}
+	}
+}
+
+func TestNestedClassDefaults(t *testing.T) {
+	resetASTCache()
+	defer resetASTCache()
+
+	const document = `<mjml>
+  <mj-head><mj-attributes>
+    <mj-text color="#111111" />
+    <mj-class name="dark" background-color="#000000">
+      <mj-text color="#ffffff" />
+      <mj-button background-color="#ff0000" />
+    </mj-class>
+  </mj-attributes></mj-head>
+  <mj-body>
+    <mj-section mj-class="dark"><mj-column>
+      <mj-text>Light</mj-text>
+      <mj-text color="#00ff00">Own</mj-text>
+      <mj-button href="#">Go</mj-button>
+    </mj-column></mj-section>
+    <mj-section><mj-column><mj-text>Default</mj-text></mj-column></mj-section>
+  </mj-body>
+</mjml>`
+
+	for i := 0; i < 2; i++ {
+		html, err := Render(document, WithCache(), WithValidationLevel(ValidationStrict))
+		if err != nil {
+			t.Fatalf("render failed: %v", err)
+		}
+		for _, want := range []string{"color:#ffffff;", "color:#00ff00;", "color:#111111;", "background:#ff0000;", "background:#000000;"} {
+			if !strings.Contains(html, want) {
+				t.Errorf("render %d: expected %s", i, want)
+			}
+		}
+	}
+
+	ast, err := ParseMJML(document)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	html, err := RenderFromAST(ast)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if !strings.Contains(html, "color:#ffffff;") || !strings.Contains(html, "color:#111111;") {
+		t.Error("expected RenderFromAST to apply mj-attributes")
+	}
+	if applied := applyClassChildDefaults(ast, newGlobalAttributes(ast, nil), nil); applied == ast {
+		t.Error("expected the nested class defaults to produce a copy of the AST")
+	}
+	if strings.Contains(fmt.Sprint(ast.Children[1].Children[0].Children[0].Children[0].Attrs), "#ffffff") {
+		t.Error("expected the parsed AST to be left unchanged")
+	}
+}
@@ -0,0 +1,60 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+const directionDocument = `<mjml><mj-body>
+  <mj-wrapper><mj-section>
+    <mj-column><mj-text>Hello</mj-text></mj-column>
+    <mj-column direction="ltr"><mj-text align="left">Code</mj-text></mj-column>
+  </mj-section></mj-wrapper>
+</mj-body></mjml>`
+
+func TestRTLDirectionCascades(t *testing.T) {
+	html, err := Render(strings.Replace(directionDocument, "<mjml>", `<mjml lang="ar" dir="rtl">`, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<html lang="ar" dir="rtl"`,
+		`lang="ar" dir="rtl"><!--[if mso | IE]>`,
+		`text-align:right;color:#000000;">Hello</div>`,
+		`text-align:left;color:#000000;">Code</div>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	// wrapper, section and the first column inherit rtl; the second column
+	// keeps its explicit direction
+	if got := strings.Count(html, "direction:rtl"); got != 3 {
+		t.Errorf("got %d direction:rtl styles, want 3", got)
+	}
+	if got := strings.Count(html, "direction:ltr"); got != 1 {
+		t.Errorf("got %d direction:ltr styles, want 1", got)
+	}
+}
+
+func TestWithLangAndDir(t *testing.T) {
+	rtl, err := Render(strings.Replace(directionDocument, "<mjml>", `<mjml lang="ar" dir="rtl">`, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	html, err := Render(directionDocument, WithLang("ar"), WithDir("rtl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if html != rtl {
+		t.Error("WithLang and WithDir should match setting lang and dir on the mjml element")
+	}
+
+	ltr, err := Render(strings.Replace(directionDocument, "<mjml>", `<mjml lang="ar" dir="rtl">`, 1), WithLang("en"), WithDir("ltr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(ltr, `<html lang="en" dir="ltr"`) || strings.Contains(ltr, "direction:rtl") {
+		t.Error("render options should replace the document's lang and dir")
+	}
+}
@@ -0,0 +1,30 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDividerBorderStylesAndWidths verifies that every border-style reaches both
+// the divider and its Outlook table, and that percentage widths produce the
+// same fractional pixel widths as MJML.
+func TestDividerBorderStylesAndWidths(t *testing.T) {
+	for _, style := range []string{"solid", "dashed", "dotted", "double", "groove", "ridge", "inset", "outset", "none", "hidden"} {
+		input := `<mjml><mj-body><mj-section><mj-column>
+  <mj-divider border-style="` + style + `" border-width="2px" border-color="#cccccc" width="33%" />
+</mj-column></mj-section></mj-body></mjml>`
+
+		html, err := Render(input)
+		if err != nil {
+			t.Fatalf("%s: Render() error = %v", style, err)
+		}
+		border := "border-top:" + style + " 2px #cccccc;"
+		if got := strings.Count(html, border); got != 2 {
+			t.Errorf("%s: expected border on divider and Outlook table, found %d", style, got)
+		}
+		// 600px column minus 25px padding on each side, times 33%
+		if !strings.Contains(html, `width:181.5px;" role="presentation" width="181.5px"`) {
+			t.Errorf("%s: expected a 181.5px Outlook table", style)
+		}
+	}
+}
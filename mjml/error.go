@@ -66,3 +66,42 @@ func ErrInvalidAttribute(tagName, attrName string, line int) *Error {
 		},
 	}
 }
+
+func ErrInvalidAttributeValue(tagName, attrName, value string, problem error, line int) *Error {
+	return &Error{
+		Message: "MJML compilation error",
+		Details: []ErrorDetail{
+			{
+				Line:    line,
+				Message: fmt.Sprintf("Attribute '%s' of <%s> has invalid value %q: %v", attrName, tagName, value, problem),
+				TagName: tagName,
+			},
+		},
+	}
+}
+
+func ErrUnsafeHref(tagName, href string, line int) *Error {
+	return &Error{
+		Message: "MJML compilation error",
+		Details: []ErrorDetail{
+			{
+				Line:    line,
+				Message: fmt.Sprintf("Attribute 'href' of <%s> runs script (%q); email clients remove such links", tagName, href),
+				TagName: tagName,
+			},
+		},
+	}
+}
+
+func ErrUnknownTag(tagName string, line int) *Error {
+	return &Error{
+		Message: "MJML compilation error",
+		Details: []ErrorDetail{
+			{
+				Line:    line,
+				Message: fmt.Sprintf("Element <%s> doesn't exist or is not registered", tagName),
+				TagName: tagName,
+			},
+		},
+	}
+}
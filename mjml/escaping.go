@@ -0,0 +1,300 @@
+package mjml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/preslavrachev/gomjml/mjml/html"
+	"github.com/preslavrachev/gomjml/mjml/options"
+)
+
+// TextEscaping is an alias for convenience
+type TextEscaping = options.TextEscaping
+
+// Text escaping modes
+const (
+	EscapeUTF8     = options.EscapeUTF8
+	EscapeNonASCII = options.EscapeNonASCII
+)
+
+// WithTextEscaping selects how non-ASCII characters appear in the output.
+// EscapeUTF8 (the default) writes them as UTF-8. EscapeNonASCII writes pure
+// ASCII for old mail gateways that mangle 8-bit content: characters become
+// numeric character references such as &#x1F600;, and CSS escapes such as
+// \01F600 inside style elements, where references are not decoded. Entities
+// such as &nbsp; in the source are decoded while parsing, so this is also the
+// way to get them back as references.
+func WithTextEscaping(mode TextEscaping) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.TextEscaping = mode
+	}
+}
+
+// InvisibleCharacters is an alias for convenience
+type InvisibleCharacters = options.InvisibleCharacters
+
+// Invisible character policies
+const (
+	InvisibleAsRendered = options.InvisibleAsRendered
+	InvisibleRaw        = options.InvisibleRaw
+	InvisibleNumeric    = options.InvisibleNumeric
+	InvisibleNamed      = options.InvisibleNamed
+)
+
+// WithInvisibleCharacters selects how no-break spaces, soft hyphens and
+// zero-width characters such as U+200B and U+200C appear in the output, as
+// some clients render a raw U+00A0 differently from &nbsp;. The policy
+// applies to characters from the source and to those gomjml writes itself,
+// such as the &nbsp;&zwnj; padding of the preview text, whether they are
+// written as characters or as references. InvisibleAsRendered (the default)
+// leaves them as the components write them, matching MJML. The content of
+// style and script elements is left unchanged, and EscapeNonASCII turns raw
+// characters into numeric references afterwards.
+func WithInvisibleCharacters(policy InvisibleCharacters) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.InvisibleCharacters = policy
+	}
+}
+
+// MarkupEscaping is an alias for convenience
+type MarkupEscaping = html.EscapePolicy
+
+// Markup escaping policies
+const (
+	EscapeMJML = html.EscapeMJML
+	EscapeSafe = html.EscapeSafe
+)
+
+// WithMarkupEscaping selects how attribute values and text taken from the
+// source, such as hrefs, alt text, button labels and the title, are escaped.
+// EscapeMJML (the default) matches MJML's output for a well-formed source.
+// EscapeSafe escapes every markup character, for consumers that parse the
+// output as XML. Raw HTML, such as mj-text and mj-raw content, is written as
+// is under both policies.
+func WithMarkupEscaping(policy MarkupEscaping) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.MarkupEscaping = policy
+	}
+}
+
+// escapeOutput applies the character policies of opts to a rendered HTML
+// document.
+func escapeOutput(document string, opts *RenderOpts) string {
+	if opts.InvisibleCharacters != InvisibleAsRendered {
+		document = writeInvisibleCharacters(document, opts.InvisibleCharacters)
+	}
+	if opts.TextEscaping == EscapeNonASCII {
+		document = escapeNonASCII(document)
+	}
+	return document
+}
+
+// escapeNonASCII replaces every non-ASCII character in an HTML document with a
+// numeric character reference, or with a CSS or JavaScript escape inside style
+// and script elements. Invalid UTF-8 bytes are left unchanged.
+func escapeNonASCII(document string) string {
+	first := -1
+	for i := 0; i < len(document); i++ {
+		if document[i] >= utf8.RuneSelf {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return document
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(document) + len(document)/8)
+
+	rawEnd := "" // Closing tag of the style or script element we are in
+	written := 0
+	for i := 0; i < len(document); {
+		c := document[i]
+		if c == '<' {
+			if rawEnd == "" {
+				rawEnd = rawTextEnd(document[i:])
+			} else if hasPrefixFold(document[i:], rawEnd) {
+				rawEnd = ""
+			}
+			i++
+			continue
+		}
+		if c < utf8.RuneSelf {
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(document[i:])
+		if r == utf8.RuneError && size == 1 {
+			i++
+			continue
+		}
+		sb.WriteString(document[written:i])
+		switch rawEnd {
+		case "</style":
+			fmt.Fprintf(&sb, `\%06X`, r)
+		case "</script":
+			if r > 0xFFFF {
+				r -= 0x10000
+				fmt.Fprintf(&sb, `\u%04X\u%04X`, 0xD800+(r>>10), 0xDC00+(r&0x3FF))
+			} else {
+				fmt.Fprintf(&sb, `\u%04X`, r)
+			}
+		default:
+			fmt.Fprintf(&sb, "&#x%X;", r)
+		}
+		i += size
+		written = i
+	}
+	sb.WriteString(document[written:])
+	return sb.String()
+}
+
+// rawTextEnd returns the closing tag prefix when s starts with a style or
+// script start tag, or "" otherwise.
+func rawTextEnd(s string) string {
+	for _, tag := range []string{"style", "script"} {
+		if len(s) > len(tag)+1 && hasPrefixFold(s[1:], tag) {
+			switch s[len(tag)+1] {
+			case '>', ' ', '\t', '\n', '\r', '/':
+				return "</" + tag
+			}
+		}
+	}
+	return ""
+}
+
+// hasPrefixFold reports whether s starts with prefix, ignoring ASCII case.
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// invisibleCharacters maps the characters WithInvisibleCharacters controls to
+// their HTML entity names, or to "" when HTML defines none.
+var invisibleCharacters = map[rune]string{
+	'\u00A0': "nbsp",
+	'\u00AD': "shy",
+	'\u034F': "", // Combining grapheme joiner, used to pad preview text
+	'\u200B': "ZeroWidthSpace",
+	'\u200C': "zwnj",
+	'\u200D': "zwj",
+	'\u202F': "", // Narrow no-break space
+	'\u2060': "NoBreak",
+	'\uFEFF': "", // Zero-width no-break space
+}
+
+// invisibleByName maps the entity names of invisibleCharacters back to them.
+var invisibleByName = func() map[string]rune {
+	byName := make(map[string]rune, len(invisibleCharacters))
+	for r, name := range invisibleCharacters {
+		if name != "" {
+			byName[name] = r
+		}
+	}
+	return byName
+}()
+
+// writeInvisibleCharacters rewrites the invisible characters of an HTML
+// document, and the character references to them, as policy requires. Style
+// and script elements, where references are not decoded, are left unchanged.
+func writeInvisibleCharacters(document string, policy InvisibleCharacters) string {
+	var sb strings.Builder
+	rawEnd := "" // Closing tag of the style or script element we are in
+	written := 0
+	for i := 0; i < len(document); {
+		c := document[i]
+		if c == '<' {
+			if rawEnd == "" {
+				rawEnd = rawTextEnd(document[i:])
+			} else if hasPrefixFold(document[i:], rawEnd) {
+				rawEnd = ""
+			}
+			i++
+			continue
+		}
+		if rawEnd != "" || (c != '&' && c < utf8.RuneSelf) {
+			i++
+			continue
+		}
+
+		var r rune
+		var size int
+		if c == '&' {
+			r, size = invisibleReference(document[i:])
+		} else if r, size = utf8.DecodeRuneInString(document[i:]); !isInvisible(r) {
+			i += size
+			continue
+		}
+		if size == 0 {
+			i++
+			continue
+		}
+		encoded := encodeInvisible(r, policy)
+		if encoded != document[i:i+size] {
+			if sb.Len() == 0 {
+				sb.Grow(len(document) + len(document)/16)
+			}
+			sb.WriteString(document[written:i])
+			sb.WriteString(encoded)
+			written = i + size
+		}
+		i += size
+	}
+	if written == 0 {
+		return document
+	}
+	sb.WriteString(document[written:])
+	return sb.String()
+}
+
+// invisibleReference decodes the character reference at the start of s when
+// it refers to an invisible character, returning the character and the length
+// of the reference, or a zero length otherwise.
+func invisibleReference(s string) (rune, int) {
+	end := strings.IndexByte(s, ';')
+	if end < 2 || end > 16 {
+		return 0, 0
+	}
+	ref := s[1:end]
+	if ref[0] != '#' {
+		r, ok := invisibleByName[ref]
+		if !ok {
+			return 0, 0
+		}
+		return r, end + 1
+	}
+	var n uint64
+	var err error
+	if ref = ref[1:]; len(ref) > 1 && (ref[0] == 'x' || ref[0] == 'X') {
+		n, err = strconv.ParseUint(ref[1:], 16, 32)
+	} else {
+		n, err = strconv.ParseUint(ref, 10, 32)
+	}
+	if err != nil || !isInvisible(rune(n)) {
+		return 0, 0
+	}
+	return rune(n), end + 1
+}
+
+// encodeInvisible writes the invisible character r as policy requires.
+func encodeInvisible(r rune, policy InvisibleCharacters) string {
+	switch policy {
+	case InvisibleNamed:
+		if name := invisibleCharacters[r]; name != "" {
+			return "&" + name + ";"
+		}
+		fallthrough
+	case InvisibleNumeric:
+		return fmt.Sprintf("&#x%X;", r)
+	default:
+		return string(r)
+	}
+}
+
+func isInvisible(r rune) bool {
+	_, ok := invisibleCharacters[r]
+	return ok
+}
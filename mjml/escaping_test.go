@@ -0,0 +1,160 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEscapeNonASCII(t *testing.T) {
+	tests := map[string]string{
+		"plain ascii":                            "plain ascii",
+		"<p title=\"é\">😀&nbsp; </p>":            "<p title=\"&#xE9;\">&#x1F600;&nbsp;&#xA0;</p>",
+		`<style>.a::after{content:"→"}</style>ü`: `<style>.a::after{content:"\002192"}</style>&#xFC;`,
+		`<STYLE type="text/css">"é"</Style>é`:    `<STYLE type="text/css">"\0000E9"</Style>&#xE9;`,
+		`<script>x("😀é")</script>`:               `<script>x("\uD83D\uDE00\u00E9")</script>`,
+		"<styles>é</styles>":                     "<styles>&#xE9;</styles>",
+		"bad \xff byte":                          "bad \xff byte",
+	}
+	for input, want := range tests {
+		if got := escapeNonASCII(input); got != want {
+			t.Errorf("escapeNonASCII(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestWithTextEscaping(t *testing.T) {
+	input := `<mjml><mj-head><mj-title>Café</mj-title><mj-style>.x { font-family: "Ünïcode"; }</mj-style></mj-head>` +
+		`<mj-body><mj-section><mj-column>` +
+		`<mj-text>Hello 😀&nbsp;&copy; – Grüße</mj-text>` +
+		`<mj-image src="https://example.com/a.png" alt="Straße" />` +
+		`</mj-column></mj-section></mj-body></mjml>`
+
+	utf8HTML, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(utf8HTML, "Hello 😀&#xA0;© – Grüße") {
+		t.Error("default output should contain UTF-8 characters")
+	}
+
+	asciiHTML, err := Render(input, WithTextEscaping(EscapeNonASCII))
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for i := 0; i < len(asciiHTML); i++ {
+		if asciiHTML[i] >= 0x80 {
+			t.Fatalf("non-ASCII byte at %d: %q", i, asciiHTML[max(0, i-20):min(len(asciiHTML), i+20)])
+		}
+	}
+	for _, want := range []string{
+		"<title>Caf&#xE9;</title>",
+		"Hello &#x1F600;&#xA0;&#xA9; &#x2013; Gr&#xFC;&#xDF;e",
+		`alt="Stra&#xDF;e"`,
+		`"\0000DCn\0000EFcode"`,
+	} {
+		if !strings.Contains(asciiHTML, want) {
+			t.Errorf("escaped output missing %q", want)
+		}
+	}
+
+	ast, err := ParseMJML(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromAST, err := RenderFromAST(ast, WithTextEscaping(EscapeNonASCII))
+	if err != nil {
+		t.Fatalf("RenderFromAST failed: %v", err)
+	}
+	if !strings.Contains(fromAST, "Gr&#xFC;&#xDF;e") {
+		t.Error("RenderFromAST should escape non-ASCII characters too")
+	}
+}
+
+func TestWriteInvisibleCharacters(t *testing.T) {
+	input := "a\u00A0b&nbsp;c&#160;d&#x200b;e\u200C&amp;nbsp;&copy;\uFEFF<style>p{content:\"\u00A0\"}</style>&zwj;"
+	tests := map[InvisibleCharacters]string{
+		InvisibleRaw:     "a\u00A0b\u00A0c\u00A0d\u200Be\u200C&amp;nbsp;&copy;\uFEFF<style>p{content:\"\u00A0\"}</style>\u200D",
+		InvisibleNumeric: "a&#xA0;b&#xA0;c&#xA0;d&#x200B;e&#x200C;&amp;nbsp;&copy;&#xFEFF;<style>p{content:\"\u00A0\"}</style>&#x200D;",
+		InvisibleNamed:   "a&nbsp;b&nbsp;c&nbsp;d&ZeroWidthSpace;e&zwnj;&amp;nbsp;&copy;&#xFEFF;<style>p{content:\"\u00A0\"}</style>&zwj;",
+	}
+	for policy, want := range tests {
+		if got := writeInvisibleCharacters(input, policy); got != want {
+			t.Errorf("policy %d: got %q, want %q", policy, got, want)
+		}
+	}
+	if got := writeInvisibleCharacters("plain &amp; text", InvisibleNamed); got != "plain &amp; text" {
+		t.Errorf("expected text without invisible characters unchanged, got %q", got)
+	}
+}
+
+func TestWithInvisibleCharacters(t *testing.T) {
+	input := `<mjml><mj-head><mj-preview>Hi</mj-preview></mj-head><mj-body><mj-section><mj-column>` +
+		`<mj-text>One&nbsp;two</mj-text>` +
+		"<mj-button href=\"#\">Go\u200Bnow</mj-button>" +
+		`</mj-column></mj-section></mj-body></mjml>`
+
+	defaultHTML, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	named, err := Render(input, WithInvisibleCharacters(InvisibleNamed), WithPreheaderPadding(4))
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, want := range []string{"One&nbsp;two", "Go&ZeroWidthSpace;now", "Hi&nbsp;&zwnj;"} {
+		if !strings.Contains(named, want) {
+			t.Errorf("named output missing %q", want)
+		}
+	}
+
+	raw, err := Render(input, WithInvisibleCharacters(InvisibleRaw), WithPreheaderPadding(4))
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(raw, "One\u00A0two") || strings.Contains(raw, "&nbsp;") || strings.Contains(raw, "&#xA0;") {
+		t.Error("raw output should contain no-break spaces as characters")
+	}
+	if got := writeInvisibleCharacters(raw, InvisibleNamed); got != named {
+		t.Error("expected the policies to round-trip")
+	}
+	if strings.Contains(defaultHTML, "&ZeroWidthSpace;") || !strings.Contains(defaultHTML, "One&#xA0;two") {
+		t.Error("default output should be left as rendered")
+	}
+}
+
+func TestWithMarkupEscaping(t *testing.T) {
+	input := `<mjml><mj-head><mj-title>Fish &amp;amp; Chips</mj-title></mj-head><mj-body><mj-section><mj-column>` +
+		`<mj-button href="https://example.com/?a=1&amp;b=2" title='Say "hi"'>Tom &amp; Jerry &amp;amp; <b>R&amp;D</b></mj-button>` +
+		`<mj-image src="https://example.com/a.png?w=1&h=2" alt="1 &lt 2" />` +
+		`</mj-column></mj-section></mj-body></mjml>`
+
+	mjmlHTML, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, want := range []string{
+		"<title>Fish &amp;amp; Chips</title>",
+		`aria-label="Fish &amp;amp; Chips"`,
+		`href="https://example.com/?a=1&b=2" title="Say &quot;hi&quot;"`,
+		"Tom & Jerry &amp;amp; <b>R&amp;D</b>",
+		`src="https://example.com/a.png?w=1&h=2"`,
+	} {
+		if !strings.Contains(mjmlHTML, want) {
+			t.Errorf("default output missing %q", want)
+		}
+	}
+
+	safeHTML, err := Render(input, WithMarkupEscaping(EscapeSafe))
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, want := range []string{
+		`href="https://example.com/?a=1&amp;b=2" title="Say &quot;hi&quot;"`,
+		"Tom &amp; Jerry &amp;amp; <b>R&amp;D</b>",
+		`src="https://example.com/a.png?w=1&amp;h=2"`,
+	} {
+		if !strings.Contains(safeHTML, want) {
+			t.Errorf("safe output missing %q", want)
+		}
+	}
+}
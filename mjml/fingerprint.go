@@ -0,0 +1,32 @@
+package mjml
+
+import (
+	"hash/fnv"
+	"io"
+	"strconv"
+)
+
+// TemplateHash returns the fingerprint of an MJML source that RenderWithAST
+// reports in RenderResult.TemplateHash, so that a web layer can tell whether a
+// template changed before rendering it again.
+//
+// Unlike the seeded hash keying the AST cache, it is the same in every
+// process, so it can be stored or compared between servers. It covers the
+// source only: the same template rendered with different options has the same
+// TemplateHash, and callers varying the options should key on them as well.
+func TemplateHash(mjmlContent string) uint64 {
+	return fingerprint(mjmlContent)
+}
+
+// ETag returns a strong HTTP entity tag for the rendered HTML, derived from
+// OutputHash.
+func (r *RenderResult) ETag() string {
+	return `"` + strconv.FormatUint(r.OutputHash, 16) + `"`
+}
+
+// fingerprint is the 64-bit FNV-1a hash of s.
+func fingerprint(s string) uint64 {
+	h := fnv.New64a()
+	io.WriteString(h, s)
+	return h.Sum64()
+}
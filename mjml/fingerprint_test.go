@@ -0,0 +1,47 @@
+package mjml
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestRenderResultHashes(t *testing.T) {
+	resetASTCache()
+	defer resetASTCache()
+
+	tpl := `<mjml><mj-body><mj-section><mj-column><mj-text>Hello</mj-text></mj-column></mj-section></mj-body></mjml>`
+
+	first, err := RenderWithAST(tpl)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	second, err := RenderWithAST(tpl, WithCache())
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	if first.TemplateHash == 0 || first.TemplateHash != TemplateHash(tpl) {
+		t.Errorf("expected TemplateHash to match TemplateHash(tpl), got %x", first.TemplateHash)
+	}
+	// FNV-1a is stable across processes, unlike the seeded cache key
+	if got := TemplateHash(""); got != 0xcbf29ce484222325 {
+		t.Errorf("expected the FNV-1a offset basis for an empty template, got %x", got)
+	}
+	if first.OutputHash != fingerprint(first.HTML) || first.OutputHash != second.OutputHash || first.ETag() != second.ETag() {
+		t.Error("expected identical renders to have the same output hash and ETag")
+	}
+	if etag := first.ETag(); etag != `"`+strconv.FormatUint(first.OutputHash, 16)+`"` {
+		t.Errorf("unexpected ETag %s", etag)
+	}
+
+	changed, err := RenderWithAST(tpl, WithDir("rtl"))
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if changed.TemplateHash != first.TemplateHash {
+		t.Error("expected options to leave the template hash unchanged")
+	}
+	if changed.OutputHash == first.OutputHash {
+		t.Error("expected different output to have a different output hash")
+	}
+}
@@ -134,3 +134,43 @@ func TestFontsBlockOrderingRelativeToMediaQueries(t *testing.T) {
 		t.Errorf("expected fonts conditional block to precede first @media rule: fontBlockIdx=%d mediaIdx=%d", fontBlockIdx, mediaIdx)
 	}
 }
+
+// TestMJFontFileEmitsFontFace verifies that mj-font declarations pointing to font
+// files become one @font-face rule per font, while stylesheets are still imported.
+func TestMJFontFileEmitsFontFace(t *testing.T) {
+	input := `<mjml>
+  <mj-head>
+    <mj-font name="Brand" href="https://cdn.example.com/brand.woff" />
+    <mj-font name="Brand" href="https://cdn.example.com/brand.woff2" />
+    <mj-font name="Raleway" href="https://fonts.googleapis.com/css?family=Raleway" />
+  </mj-head>
+  <mj-body><mj-section><mj-column>
+    <mj-text font-family="Brand, Arial, sans-serif">Hello</mj-text>
+  </mj-column></mj-section></mj-body>
+</mjml>`
+
+	html, err := Render(input, WithExternalResources(ExternalResourcesHinted))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{
+		`@font-face { font-family: 'Brand'; src: url(https://cdn.example.com/brand.woff2) format('woff2'), url(https://cdn.example.com/brand.woff) format('woff'); }`,
+		`@import url(https://fonts.googleapis.com/css?family=Raleway);`,
+		`<link rel="preconnect" href="https://cdn.example.com" crossorigin>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if strings.Contains(html, "@import url(https://cdn.example.com") || strings.Contains(html, `<link href="https://cdn.example.com`) {
+		t.Error("font files must not be imported as stylesheets")
+	}
+
+	html, err = Render(input, WithExternalResources(ExternalResourcesOffline))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if strings.Contains(html, "@font-face") {
+		t.Error("expected offline output to leave out @font-face rules")
+	}
+}
@@ -38,11 +38,17 @@ func DetectDefaultFonts(hasTextComponents, hasSocialComponents, hasButtonCompone
 
 // GetGoogleFontURL checks if a font family corresponds to a Google Font and returns its URL
 func GetGoogleFontURL(fontFamily string) string {
+	return FontURL(fontFamily, GoogleFontsMapping)
+}
+
+// FontURL returns the URL mapping holds for a font named in the font family,
+// or "" when it names none
+func FontURL(fontFamily string, mapping map[string]string) string {
 	// Clean up the font family string - remove quotes and extra whitespace
 	fontFamily = strings.Trim(fontFamily, `"' `)
 
-	// Check each Google Font mapping
-	for fontName, url := range GoogleFontsMapping {
+	// Check each font mapping
+	for fontName, url := range mapping {
 		// Case-insensitive check and see if the font family contains this font name
 		if strings.Contains(strings.ToLower(fontFamily), strings.ToLower(fontName)) {
 			return url
@@ -54,11 +60,17 @@ func GetGoogleFontURL(fontFamily string) string {
 
 // ConvertFontFamiliesToURLs converts a slice of font families to Google Font URLs
 func ConvertFontFamiliesToURLs(fontFamilies []string) []string {
+	return FontURLs(fontFamilies, GoogleFontsMapping)
+}
+
+// FontURLs converts a slice of font families to the URLs mapping holds for
+// them, without duplicates
+func FontURLs(fontFamilies []string, mapping map[string]string) []string {
 	var urls []string
 	seen := make(map[string]bool)
 
 	for _, fontFamily := range fontFamilies {
-		if url := GetGoogleFontURL(fontFamily); url != "" {
+		if url := FontURL(fontFamily, mapping); url != "" {
 			if !seen[url] {
 				urls = append(urls, url)
 				seen[url] = true
@@ -96,3 +108,72 @@ func BuildFontsTags(fontsToImport []string) string {
 
 	return result.String()
 }
+
+// fontFileFormats maps font file extensions to their CSS format() names, in
+// the order browsers should prefer them.
+var fontFileFormats = []struct{ extension, format string }{
+	{".woff2", "woff2"},
+	{".woff", "woff"},
+	{".ttf", "truetype"},
+	{".otf", "opentype"},
+	{".eot", "embedded-opentype"},
+}
+
+// FontFileFormat returns the CSS format() name of a font file URL, judged by
+// its extension, such as "woff2" for ".../font.woff2?v=2". It returns "" for
+// URLs of stylesheets and anything else that is not a font file.
+func FontFileFormat(url string) string {
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		url = url[:i]
+	}
+	url = strings.ToLower(url)
+	for _, f := range fontFileFormats {
+		if strings.HasSuffix(url, f.extension) {
+			return f.format
+		}
+	}
+	return ""
+}
+
+// FontFace is a web font loaded straight from font files with an @font-face
+// rule, rather than from a stylesheet.
+type FontFace struct {
+	Family string   // Font family name used in font-family declarations
+	URLs   []string // Font files, in any order
+}
+
+// BuildFontFaces generates an @font-face rule for each font, for mj-font
+// declarations whose href is a font file. A font given in several formats gets
+// one rule whose src lists them as fallbacks, woff2 first, so each client
+// downloads only the best format it supports.
+func BuildFontFaces(faces []FontFace) string {
+	if len(faces) == 0 {
+		return ""
+	}
+
+	var result strings.Builder
+	result.WriteString("<!--[if !mso]><!-->")
+	result.WriteString("<style type=\"text/css\">")
+	for _, face := range faces {
+		family := strings.ReplaceAll(face.Family, "'", `\'`)
+		result.WriteString(fmt.Sprintf("@font-face { font-family: '%s'; src: ", family))
+		n := 0
+		for _, f := range fontFileFormats {
+			for _, url := range face.URLs {
+				if FontFileFormat(url) != f.format {
+					continue
+				}
+				if n > 0 {
+					result.WriteString(", ")
+				}
+				result.WriteString(fmt.Sprintf("url(%s) format('%s')", url, f.format))
+				n++
+			}
+		}
+		result.WriteString("; }")
+	}
+	result.WriteString("</style>")
+	result.WriteString("<!--<![endif]-->")
+
+	return result.String()
+}
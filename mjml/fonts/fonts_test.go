@@ -99,3 +99,38 @@ func TestBuildFontsTags_MultipleFonts(t *testing.T) {
 		t.Errorf("unexpected number of link tags before style; got %d want %d", strings.Count(out[:styleIdx], "<link "), len(urls))
 	}
 }
+
+func TestFontFileFormat(t *testing.T) {
+	tests := map[string]string{
+		"https://cdn.example.com/brand.woff2":          "woff2",
+		"https://cdn.example.com/brand.WOFF?v=3":       "woff",
+		"https://cdn.example.com/brand.ttf#iefix":      "truetype",
+		"https://cdn.example.com/brand.otf":            "opentype",
+		"https://cdn.example.com/brand.eot":            "embedded-opentype",
+		GoogleFontsMapping["Ubuntu"]:                   "",
+		"https://cdn.example.com/fonts.css?f=a.woff2x": "",
+	}
+	for url, want := range tests {
+		if got := FontFileFormat(url); got != want {
+			t.Errorf("FontFileFormat(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+func TestBuildFontFaces(t *testing.T) {
+	out := BuildFontFaces([]FontFace{
+		{Family: "Brand", URLs: []string{"https://cdn.example.com/brand.woff", "https://cdn.example.com/brand.woff2"}},
+		{Family: "Owner's Hand", URLs: []string{"https://cdn.example.com/hand.ttf"}},
+	})
+
+	want := `<!--[if !mso]><!--><style type="text/css">` +
+		`@font-face { font-family: 'Brand'; src: url(https://cdn.example.com/brand.woff2) format('woff2'), url(https://cdn.example.com/brand.woff) format('woff'); }` +
+		`@font-face { font-family: 'Owner\'s Hand'; src: url(https://cdn.example.com/hand.ttf) format('truetype'); }` +
+		`</style><!--<![endif]-->`
+	if out != want {
+		t.Errorf("BuildFontFaces() =\n%s\nwant\n%s", out, want)
+	}
+	if BuildFontFaces(nil) != "" {
+		t.Error("expected no output without fonts")
+	}
+}
@@ -0,0 +1,67 @@
+package mjml
+
+import (
+	"fmt"
+
+	"github.com/preslavrachev/gomjml/mjml/options"
+)
+
+// FragmentCache is an alias for convenience
+type FragmentCache = options.FragmentCache
+
+// FragmentCacheStats is an alias for convenience
+type FragmentCacheStats = options.FragmentCacheStats
+
+// NewFragmentCache creates a cache for WithFragmentCache holding the fragments
+// of up to maxTemplates templates; the least recently rendered template is
+// dropped first. A limit of zero or less keeps every template.
+func NewFragmentCache(maxTemplates int) *FragmentCache {
+	return options.NewFragmentCache(maxTemplates)
+}
+
+// WithFragmentCache keeps the markup of static components (mj-divider,
+// mj-spacer and mj-social) in cache across renders, for templates rendered
+// many times with only their text changing. A component's markup is reused
+// when its resolved attributes, its content and its place in the layout match
+// an earlier render of the same template. Fragments belong to the template
+// hash, which covers the mj-head, the mjml and mj-body attributes and the
+// render options that change component markup, such as the output format,
+// WithoutOutlookSupport and the MJML version, so changing any of those starts
+// from an empty set of fragments. Options given as functions, such as
+// WithTranslator, cannot be compared and must stay the same across renders
+// sharing a cache. Fragments are not cached while images are inlined or a
+// source map is recorded.
+//
+// Example:
+//
+//	cache := mjml.NewFragmentCache(16)
+//	for _, recipient := range recipients {
+//		html, err := mjml.Render(personalize(template, recipient), mjml.WithFragmentCache(cache))
+//		// ...
+//	}
+func WithFragmentCache(cache *FragmentCache) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.FragmentCache = cache
+	}
+}
+
+// templateHash hashes the parts of a document that every static component
+// depends on: the mjml and mj-body attributes, the mj-head and the render
+// options that change how components are written.
+func templateHash(root *MJMLNode, opts *RenderOpts) uint64 {
+	h := newNodeHash()
+	fmt.Fprintf(h, "%d|%t|%d|%+v|%d|%d|%d|%d|%t|%t|%t|%t|%t|%t|%p|%q|%q|%q|%q\x00",
+		opts.OutputFormat, opts.OmitOutlookSupport, opts.Compatibility, opts.Features,
+		opts.MarkupEscaping, opts.TextEscaping, opts.InvisibleCharacters, opts.Comments,
+		opts.DebugTags, opts.RawPassthrough, opts.StripEventHandlers, opts.OutlookVMLButtons,
+		opts.TextLineHeightRule, opts.ForceOWADesktop, opts.Sanitizer,
+		opts.SocialIconBaseURL, opts.BaseURL, opts.OverrideLang, opts.OverrideDir)
+	writeNodeAttrs(h, root)
+	if head := root.FindFirstChild("mj-head"); head != nil {
+		writeNode(h, head)
+	}
+	if body := root.FindFirstChild("mj-body"); body != nil {
+		writeNodeAttrs(h, body)
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,146 @@
+package mjml
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const fragmentTemplate = `<mjml>
+  <mj-head><mj-attributes><mj-all font-family="Roboto, Arial" /></mj-attributes></mj-head>
+  <mj-body>
+    <mj-section><mj-column>
+      <mj-text>Hello %s</mj-text>
+      <mj-divider border-color="#cccccc" />
+      <mj-spacer height="20px" />
+      <mj-social><mj-social-element name="facebook" href="https://example.com">Share</mj-social-element></mj-social>
+    </mj-column></mj-section>
+    <mj-section><mj-group><mj-column><mj-divider /></mj-column><mj-column><mj-divider /></mj-column></mj-group></mj-section>
+  </mj-body>
+</mjml>`
+
+func TestFragmentCacheReusesStaticComponents(t *testing.T) {
+	cache := NewFragmentCache(4)
+
+	for i, name := range []string{"Ada", "Grace", "Alan"} {
+		template := fmt.Sprintf(fragmentTemplate, name)
+		want, err := Render(template)
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		got, err := Render(template, WithFragmentCache(cache))
+		if err != nil {
+			t.Fatalf("Render() with fragment cache error = %v", err)
+		}
+		if got != want {
+			t.Fatalf("render %d with fragment cache differs from an uncached render", i)
+		}
+		if !strings.Contains(got, "Hello "+name) {
+			t.Errorf("render %d lost its text", i)
+		}
+		if !strings.Contains(got, "fonts.googleapis.com/css?family=Roboto") {
+			t.Errorf("render %d lost the fonts of cached fragments", i)
+		}
+	}
+
+	// Divider, spacer and social in the first section, and the dividers of
+	// the group, which render at a different width and share one fragment
+	stats := cache.Stats()
+	if stats.Misses != 4 || stats.Hits != 1+2*5 {
+		t.Errorf("Stats() = %+v, want 4 misses and 11 hits", stats)
+	}
+	if stats.Templates != 1 || stats.Fragments != 4 {
+		t.Errorf("Stats() = %+v, want 4 fragments of 1 template", stats)
+	}
+}
+
+func TestFragmentCacheScopedToTemplate(t *testing.T) {
+	cache := NewFragmentCache(1)
+
+	template := fmt.Sprintf(fragmentTemplate, "Ada")
+	if _, err := Render(template, WithFragmentCache(cache)); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	// A different head resolves the same attributes differently
+	changed := strings.Replace(template, "Roboto, Arial", "Lato, Arial", 1)
+	want, err := Render(changed)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	got, err := Render(changed, WithFragmentCache(cache))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got != want {
+		t.Error("fragments of another template were reused")
+	}
+	if stats := cache.Stats(); stats.Templates != 1 || stats.Fragments != 4 {
+		t.Errorf("Stats() = %+v, want the first template evicted", stats)
+	}
+
+	cache.Reset()
+	if stats := cache.Stats(); stats != (FragmentCacheStats{}) {
+		t.Errorf("Stats() after Reset() = %+v, want zero", stats)
+	}
+}
+
+func TestFragmentCacheScopedToOptions(t *testing.T) {
+	cache := NewFragmentCache(0)
+	template := fmt.Sprintf(fragmentTemplate, "Ada")
+
+	for _, opts := range [][]RenderOption{
+		nil,
+		{WithoutOutlookSupport()},
+		{WithOutputFormat(FormatAMP)},
+		{WithMJMLVersion("4.13")},
+		{WithCompatibility(CompatMJML4)},
+		nil,
+	} {
+		want, err := Render(template, opts...)
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		got, err := Render(template, append(opts, WithFragmentCache(cache))...)
+		if err != nil {
+			t.Fatalf("Render() with fragment cache error = %v", err)
+		}
+		if got != want {
+			t.Errorf("fragments rendered with other options were reused")
+		}
+	}
+	if stats := cache.Stats(); stats.Templates != 5 {
+		t.Errorf("Stats() = %+v, want fragments of 5 option sets", stats)
+	}
+}
+
+func TestFragmentCacheMatchesFixtures(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.mjml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewFragmentCache(0)
+	ids := WithIDGenerator(func(component string, index int) string {
+		return fmt.Sprintf("%s-%d", component, index)
+	})
+	for _, input := range inputs {
+		source, err := os.ReadFile(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, wantErr := Render(string(source), ids)
+		for range 2 {
+			got, err := Render(string(source), ids, WithFragmentCache(cache))
+			if (err == nil) != (wantErr == nil) || got != want {
+				t.Errorf("%s: output with fragment cache differs", filepath.Base(input))
+				break
+			}
+		}
+	}
+	if cache.Stats().Hits == 0 {
+		t.Error("expected fixtures to reuse fragments")
+	}
+}
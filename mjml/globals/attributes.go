@@ -4,7 +4,10 @@ import (
 	"github.com/preslavrachev/gomjml/parser"
 )
 
-// GlobalAttributes stores global attribute definitions from mj-attributes
+// GlobalAttributes stores global attribute definitions from mj-attributes.
+// Each render builds its own store and passes it to components through the
+// render options, so concurrent renders of different documents do not share
+// attribute state.
 type GlobalAttributes struct {
 	// all stores mj-all global attributes that apply to all components
 	all map[string]string
@@ -12,6 +15,12 @@ type GlobalAttributes struct {
 	componentDefaults map[string]map[string]string
 	// classDefaults stores mj-class definitions
 	classDefaults map[string]map[string]string
+	// classChildDefaults stores the element defaults nested in mj-class
+	// definitions, by class name and then tag name
+	classChildDefaults map[string]map[string]map[string]string
+	// fallbackDefaults stores caller-provided defaults layered beneath mj-attributes,
+	// keyed by component tag name or "mj-all"
+	fallbackDefaults map[string]map[string]string
 }
 
 // NewGlobalAttributes creates a new global attributes store
@@ -23,7 +32,10 @@ func NewGlobalAttributes() *GlobalAttributes {
 	}
 }
 
-// ProcessAttributesFromHead processes mj-attributes from the head component
+// ProcessAttributesFromHead processes the mj-attributes blocks of the head in
+// order. As in MJML, a later block adds to the definitions of earlier ones,
+// and a later value for the same tag, class and attribute replaces the
+// earlier one.
 func (ga *GlobalAttributes) ProcessAttributesFromHead(headNode *parser.MJMLNode) {
 	if headNode == nil {
 		return
@@ -37,6 +49,20 @@ func (ga *GlobalAttributes) ProcessAttributesFromHead(headNode *parser.MJMLNode)
 	}
 }
 
+// SetDefaults layers caller-provided attribute defaults beneath the document's
+// mj-attributes. Keys are component tag names (e.g. "mj-text") or "mj-all";
+// any value from the document's mj-attributes, including mj-all, takes precedence.
+func (ga *GlobalAttributes) SetDefaults(defaults map[string]map[string]string) {
+	ga.fallbackDefaults = make(map[string]map[string]string, len(defaults))
+	for tagName, attrs := range defaults {
+		copied := make(map[string]string, len(attrs))
+		for name, value := range attrs {
+			copied[name] = value
+		}
+		ga.fallbackDefaults[tagName] = copied
+	}
+}
+
 // processAttributesElement processes a single mj-attributes element
 func (ga *GlobalAttributes) processAttributesElement(attributesNode *parser.MJMLNode) {
 	for _, child := range attributesNode.Children {
@@ -69,6 +95,22 @@ func (ga *GlobalAttributes) processAttributesElement(attributesNode *parser.MJML
 				}
 				ga.classDefaults[className][attr.Name.Local] = attr.Value
 			}
+			// Elements nested in mj-class set defaults for those elements
+			// inside components using the class; like MJML, a later
+			// definition replaces all defaults of the same element
+			for _, nested := range child.Children {
+				if ga.classChildDefaults == nil {
+					ga.classChildDefaults = make(map[string]map[string]map[string]string)
+				}
+				if ga.classChildDefaults[className] == nil {
+					ga.classChildDefaults[className] = make(map[string]map[string]string)
+				}
+				attrs := make(map[string]string, len(nested.Attrs))
+				for _, attr := range nested.Attrs {
+					attrs[attr.Name.Local] = attr.Value
+				}
+				ga.classChildDefaults[className][nested.XMLName.Local] = attrs
+			}
 		default:
 			// Process component-specific defaults (e.g., mj-text)
 			if ga.componentDefaults[tagName] == nil {
@@ -81,8 +123,13 @@ func (ga *GlobalAttributes) processAttributesElement(attributesNode *parser.MJML
 	}
 }
 
-// GetGlobalAttribute gets a global attribute value for a component
+// GetGlobalAttribute gets a global attribute value for a component. A nil
+// store has no attributes.
 func (ga *GlobalAttributes) GetGlobalAttribute(componentName, attrName string) string {
+	if ga == nil {
+		return ""
+	}
+
 	// Check component-specific defaults first
 	if componentDefaults, exists := ga.componentDefaults[componentName]; exists {
 		if value, exists := componentDefaults[attrName]; exists {
@@ -95,11 +142,22 @@ func (ga *GlobalAttributes) GetGlobalAttribute(componentName, attrName string) s
 		return value
 	}
 
+	// Check caller-provided defaults, component-specific before mj-all
+	if value, exists := ga.fallbackDefaults[componentName][attrName]; exists {
+		return value
+	}
+	if value, exists := ga.fallbackDefaults["mj-all"][attrName]; exists {
+		return value
+	}
+
 	return ""
 }
 
 // GetClassAttribute gets an attribute value for a named mj-class
 func (ga *GlobalAttributes) GetClassAttribute(className, attrName string) string {
+	if ga == nil {
+		return ""
+	}
 	if classAttrs, exists := ga.classDefaults[className]; exists {
 		if value, ok := classAttrs[attrName]; ok {
 			return value
@@ -110,40 +168,26 @@ func (ga *GlobalAttributes) GetClassAttribute(className, attrName string) string
 
 // GetClassAttributes returns all attributes for a given mj-class
 func (ga *GlobalAttributes) GetClassAttributes(className string) map[string]string {
+	if ga == nil {
+		return nil
+	}
 	if attrs, exists := ga.classDefaults[className]; exists {
 		return attrs
 	}
 	return nil
 }
 
-// Global instance (will be set during rendering)
-var instance *GlobalAttributes
-
-// SetGlobalAttributes sets the global attributes instance
-func SetGlobalAttributes(ga *GlobalAttributes) {
-	instance = ga
-}
-
-// GetGlobalAttribute is a package-level function to access global attributes
-func GetGlobalAttribute(componentName, attrName string) string {
-	if instance == nil {
-		return ""
-	}
-	return instance.GetGlobalAttribute(componentName, attrName)
-}
-
-// GetClassAttribute is a package-level function to access mj-class definitions
-func GetClassAttribute(className, attrName string) string {
-	if instance == nil {
-		return ""
+// ClassChildAttributes returns the defaults that the mj-class named className
+// sets for tagName elements nested in components using the class.
+func (ga *GlobalAttributes) ClassChildAttributes(className, tagName string) map[string]string {
+	if ga == nil {
+		return nil
 	}
-	return instance.GetClassAttribute(className, attrName)
+	return ga.classChildDefaults[className][tagName]
 }
 
-// GetClassAttributes is a package-level function to access full mj-class attribute maps
-func GetClassAttributes(className string) map[string]string {
-	if instance == nil {
-		return nil
-	}
-	return instance.GetClassAttributes(className)
+// HasClassChildAttributes reports whether any mj-class sets defaults for
+// nested elements.
+func (ga *GlobalAttributes) HasClassChildAttributes() bool {
+	return ga != nil && len(ga.classChildDefaults) > 0
 }
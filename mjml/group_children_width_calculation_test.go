@@ -212,3 +212,74 @@ func extractCSSClasses(html string) []string {
 
 	return classes
 }
+
+func TestGroupMixedChildrenWidths(t *testing.T) {
+	tests := []struct {
+		name      string
+		mjml      string
+		wantWidth []string
+		wantClass []string
+	}{
+		{
+			name: "px, percent and auto columns",
+			mjml: `<mjml><mj-body><mj-section><mj-group>` +
+				`<mj-column width="150px"><mj-text>A</mj-text></mj-column>` +
+				`<mj-column width="25%"><mj-text>B</mj-text></mj-column>` +
+				`<mj-column><mj-text>C</mj-text></mj-column>` +
+				`</mj-group></mj-section></mj-body></mjml>`,
+			wantWidth: []string{"width:150px;", "width:150px;", "width:200px;"},
+			wantClass: []string{"mj-column-per-100 mj-outlook-group-fix"},
+		},
+		{
+			name: "group beside a column",
+			mjml: `<mjml><mj-body><mj-section>` +
+				`<mj-column><mj-text>A</mj-text></mj-column>` +
+				`<mj-group><mj-column><mj-text>B</mj-text></mj-column></mj-group>` +
+				`</mj-section></mj-body></mjml>`,
+			wantWidth: []string{"width:300px;"},
+			wantClass: []string{"mj-column-per-50 mj-outlook-group-fix"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, err := Render(tt.mjml)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			groupHTML := html[strings.Index(html, "mj-outlook-group-fix")-200:]
+			for _, want := range tt.wantWidth {
+				idx := strings.Index(groupHTML, `<td style="vertical-align:top;`+want)
+				if idx < 0 {
+					t.Fatalf("Outlook cell with %q not found in:\n%s", want, groupHTML)
+				}
+				groupHTML = groupHTML[idx+1:]
+			}
+			for _, want := range tt.wantClass {
+				if !strings.Contains(html, want) {
+					t.Errorf("expected class %q in output", want)
+				}
+				className := strings.Fields(want)[0]
+				if !strings.Contains(html, "."+className+" {") {
+					t.Errorf("expected media query rule for %q", className)
+				}
+			}
+		})
+	}
+}
+
+func TestGroupDirectionRTLKeepsSourceOrder(t *testing.T) {
+	html, err := Render(`<mjml><mj-body><mj-section><mj-group direction="rtl">` +
+		`<mj-column><mj-text>First</mj-text></mj-column>` +
+		`<mj-column><mj-text>Second</mj-text></mj-column>` +
+		`</mj-group></mj-section></mj-body></mjml>`)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(html, "direction:rtl;") {
+		t.Error("expected direction:rtl on the group")
+	}
+	if first, second := strings.Index(html, "First"), strings.Index(html, "Second"); first < 0 || second < first {
+		t.Error("expected columns to keep their source order")
+	}
+}
@@ -0,0 +1,36 @@
+package mjml
+
+import "github.com/preslavrachev/gomjml/mjml/options"
+
+// HeadPosition is an alias for convenience
+type HeadPosition = options.HeadPosition
+
+// HeadHTML is an alias for convenience
+type HeadHTML = options.HeadHTML
+
+// Positions in the head for markup added with WithHeadHTML
+const (
+	HeadStart     = options.HeadStart
+	HeadAfterMeta = options.HeadAfterMeta
+	HeadEnd       = options.HeadEnd
+)
+
+// WithHeadHTML adds markup to the head of the rendered document, such as meta
+// tags, scripts for a web view or blocks an ESP expects, without an mj-raw in
+// the template. position selects where it goes:
+//
+//   - HeadStart writes it right after <head>, before the title.
+//   - HeadAfterMeta writes it after the meta tags of the renderer, before the
+//     styles and font imports.
+//   - HeadEnd writes it right before </head>, after the mj-raw content of
+//     mj-head.
+//
+// Markup added at the same position is written in the order it was added. It
+// is not escaped or validated, but is part of the document that AMP
+// conversion and HTML transforms process. Custom RenderOptions can add markup
+// with RenderOpts.AddHeadHTML.
+func WithHeadHTML(position HeadPosition, markup string) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.AddHeadHTML(position, markup)
+	}
+}
@@ -0,0 +1,45 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHeadHTML(t *testing.T) {
+	input := `<mjml>
+  <mj-head><mj-raw><meta name="from-template" content="1" /></mj-raw></mj-head>
+  <mj-body><mj-section><mj-column><mj-text>Hello</mj-text></mj-column></mj-section></mj-body>
+</mjml>`
+
+	trackingScript := func(opts *RenderOpts) {
+		opts.AddHeadHTML(HeadEnd, `<script src="https://example.com/view.js"></script>`)
+	}
+	html, err := Render(input,
+		WithHeadHTML(HeadEnd, `<meta name="esp-block" content="1">`),
+		WithHeadHTML(HeadAfterMeta, `<meta name="robots" content="noindex">`),
+		WithHeadHTML(HeadStart, `<base target="_blank">`),
+		trackingScript,
+	)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	head := html[:strings.Index(html, "</head>")+len("</head>")]
+	for _, want := range []string{
+		`<head><base target="_blank"><title>`,
+		`<meta name="viewport" content="width=device-width,initial-scale=1"><meta name="robots" content="noindex"><style type="text/css">`,
+		`<meta name="from-template" content="1"><meta name="esp-block" content="1"><script src="https://example.com/view.js"></script></head>`,
+	} {
+		if !strings.Contains(head, want) {
+			t.Errorf("expected head to contain %q", want)
+		}
+	}
+
+	plain, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if strings.Contains(plain, "esp-block") || strings.Contains(plain, "robots") {
+		t.Error("head markup leaked into a render without the option")
+	}
+}
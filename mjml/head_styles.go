@@ -0,0 +1,355 @@
+package mjml
+
+import (
+	"strings"
+)
+
+// WithMergedHeadStyles combines the head CSS (base styles, responsive column
+// classes, component styles and mj-style blocks) into a single style tag.
+// Identical rules are emitted once, at the position of their last occurrence,
+// and media queries with the same condition are merged into one block. This
+// shrinks documents with many carousels, accordions or navbars considerably.
+// Styles inside Outlook conditional comments, font imports and style tags with
+// a media attribute are left as they are.
+func WithMergedHeadStyles() RenderOption {
+	return func(opts *RenderOpts) {
+		opts.MergeHeadStyles = true
+	}
+}
+
+// WithMinifiedHeadStyles merges the head CSS like WithMergedHeadStyles and
+// also removes comments and insignificant whitespace from it.
+func WithMinifiedHeadStyles() RenderOption {
+	return func(opts *RenderOpts) {
+		opts.MergeHeadStyles = true
+		opts.MinifyHeadStyles = true
+	}
+}
+
+const headStyleOpenTag = `<style type="text/css">`
+
+// headStyleRegistry collects the CSS of the head style tags so it can be
+// written out once, deduplicated and merged.
+type headStyleRegistry struct {
+	minify bool
+	items  []*cssItem
+}
+
+// cssItem is a top-level CSS statement: a rule, an at-rule with a block, or a
+// statement at-rule such as @import.
+type cssItem struct {
+	prelude   string     // Selector list or at-rule prelude, whitespace collapsed
+	body      string     // Declarations (or opaque at-rule content), whitespace collapsed
+	children  []*cssItem // Rules of a conditional group rule (@media, @supports)
+	statement bool       // Whether the item is a statement ending in ';'
+}
+
+func newHeadStyleRegistry(minify bool) *headStyleRegistry {
+	return &headStyleRegistry{minify: minify}
+}
+
+// add collects the content of every <style type="text/css"> tag in markup and
+// returns the remaining markup, which must be written unchanged.
+func (r *headStyleRegistry) add(markup string) string {
+	var rest strings.Builder
+	for {
+		start := strings.Index(markup, headStyleOpenTag)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(markup[start:], "</style>")
+		if end < 0 {
+			break
+		}
+		rest.WriteString(markup[:start])
+		r.items = append(r.items, parseCSSItems(markup[start+len(headStyleOpenTag):start+end])...)
+		markup = markup[start+end+len("</style>"):]
+	}
+	rest.WriteString(markup)
+	return strings.TrimSpace(rest.String())
+}
+
+// String returns the merged stylesheet, or "" when nothing was collected.
+func (r *headStyleRegistry) String() string {
+	items := mergeCSSItems(r.items)
+	if len(items) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for i, item := range items {
+		if i > 0 && !r.minify {
+			sb.WriteByte('\n')
+		}
+		item.write(&sb, r.minify, "")
+	}
+	return sb.String()
+}
+
+// key identifies items that are interchangeable in the cascade.
+func (item *cssItem) key() string {
+	if item.children != nil {
+		return "@" + minifyCSS(item.prelude, false)
+	}
+	return minifyCSS(item.prelude, false) + "{" + strings.TrimSuffix(minifyCSS(item.body, true), ";")
+}
+
+func (item *cssItem) write(sb *strings.Builder, minify bool, indent string) {
+	prelude, body := item.prelude, item.body
+	if minify {
+		prelude = minifyCSS(prelude, false)
+		body = strings.TrimSuffix(minifyCSS(body, true), ";")
+	}
+
+	sb.WriteString(indent)
+	sb.WriteString(prelude)
+	switch {
+	case item.statement:
+		sb.WriteByte(';')
+	case item.children != nil:
+		if minify {
+			sb.WriteByte('{')
+			for _, child := range item.children {
+				child.write(sb, true, "")
+			}
+			sb.WriteByte('}')
+			return
+		}
+		sb.WriteString(" {\n")
+		for _, child := range item.children {
+			child.write(sb, false, indent+"  ")
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(indent)
+		sb.WriteByte('}')
+	case minify:
+		sb.WriteByte('{')
+		sb.WriteString(body)
+		sb.WriteByte('}')
+	default:
+		sb.WriteString(" { ")
+		sb.WriteString(body)
+		sb.WriteString(" }")
+	}
+}
+
+// mergeCSSItems merges conditional group rules with the same prelude and drops
+// repeated rules. Merged blocks and surviving rules keep the position of their
+// last occurrence, so the cascade is unchanged for identical rules. @charset
+// and @import statements are moved to the front, where CSS requires them.
+func mergeCSSItems(items []*cssItem) []*cssItem {
+	groups := make(map[string]*cssItem)
+	for _, item := range items {
+		if item.children == nil {
+			continue
+		}
+		key := item.key()
+		if group, ok := groups[key]; ok {
+			group.children = append(group.children, item.children...)
+		} else {
+			groups[key] = &cssItem{prelude: item.prelude, children: append([]*cssItem{}, item.children...)}
+		}
+	}
+
+	seen := make(map[string]struct{}, len(items))
+	kept := make([]*cssItem, 0, len(items))
+	for i := len(items) - 1; i >= 0; i-- {
+		item := items[i]
+		key := item.key()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		if group, ok := groups[key]; ok {
+			group.children = mergeCSSItems(group.children)
+			item = group
+		}
+		kept = append(kept, item)
+	}
+
+	merged := make([]*cssItem, 0, len(kept))
+	for i := len(kept) - 1; i >= 0; i-- {
+		if kept[i].statement {
+			merged = append(merged, kept[i])
+		}
+	}
+	for i := len(kept) - 1; i >= 0; i-- {
+		if !kept[i].statement {
+			merged = append(merged, kept[i])
+		}
+	}
+	return merged
+}
+
+// parseCSSItems splits a stylesheet into its top-level statements. The
+// contents of @media and @supports blocks are parsed recursively; other
+// blocks are kept as opaque text.
+func parseCSSItems(css string) []*cssItem {
+	css = collapseCSS(css)
+
+	var items []*cssItem
+	for pos := 0; pos < len(css); {
+		end, delim := scanCSS(css, pos, "{;}")
+		prelude := strings.TrimSpace(css[pos:end])
+		switch delim {
+		case ';':
+			if prelude != "" {
+				items = append(items, &cssItem{prelude: prelude, statement: true})
+			}
+			pos = end + 1
+			continue
+		case '}', 0:
+			// Stray closing brace or trailing text without a block.
+			pos = end + 1
+			continue
+		}
+
+		closing, _ := scanCSS(css, end+1, "}")
+		inner := css[end+1 : min(closing, len(css))]
+		pos = closing + 1
+		if prelude == "" {
+			continue
+		}
+
+		item := &cssItem{prelude: prelude}
+		lower := strings.ToLower(prelude)
+		if strings.HasPrefix(lower, "@media") || strings.HasPrefix(lower, "@supports") {
+			item.children = parseCSSItems(inner)
+			if len(item.children) == 0 {
+				continue
+			}
+		} else {
+			item.body = strings.TrimSpace(inner)
+			if item.body == "" && !strings.HasPrefix(prelude, "@") {
+				continue
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// scanCSS returns the position of the first byte of delims found at nesting
+// depth zero from pos, skipping strings and parenthesized text, and the byte
+// found (0 when the end of css is reached). A '}' delimiter matches the
+// closing brace of the block starting just before pos.
+func scanCSS(css string, pos int, delims string) (int, byte) {
+	depth := 0
+	parens := 0
+	for i := pos; i < len(css); i++ {
+		c := css[i]
+		switch c {
+		case '"', '\'':
+			i = skipCSSString(css, i)
+			continue
+		case '(':
+			parens++
+			continue
+		case ')':
+			if parens > 0 {
+				parens--
+			}
+			continue
+		}
+		if parens > 0 {
+			continue
+		}
+		if depth == 0 && strings.IndexByte(delims, c) >= 0 {
+			return i, c
+		}
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+	}
+	return len(css), 0
+}
+
+// skipCSSString returns the index of the quote closing the string opened at i.
+func skipCSSString(css string, i int) int {
+	quote := css[i]
+	for j := i + 1; j < len(css); j++ {
+		switch css[j] {
+		case '\\':
+			j++
+		case quote:
+			return j
+		}
+	}
+	return len(css) - 1
+}
+
+// collapseCSS removes comments and collapses whitespace outside strings.
+func collapseCSS(css string) string {
+	var sb strings.Builder
+	sb.Grow(len(css))
+	space := false
+	for i := 0; i < len(css); i++ {
+		c := css[i]
+		switch {
+		case c == '/' && i+1 < len(css) && css[i+1] == '*':
+			end := strings.Index(css[i+2:], "*/")
+			if end < 0 {
+				i = len(css)
+			} else {
+				i += end + 3
+			}
+			space = true
+			continue
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			space = true
+			continue
+		}
+
+		if space && sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		space = false
+		if c == '"' || c == '\'' {
+			end := skipCSSString(css, i)
+			sb.WriteString(css[i : end+1])
+			i = end
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// minifyCSS removes the spaces around punctuation in collapsed CSS. In
+// declarations (declarations true) that is ':', ';', ',', '!' and braces; in
+// selectors, the combinators and commas. Spaces before '(' are kept, since
+// "and (" in a media query needs them.
+func minifyCSS(css string, declarations bool) string {
+	tight := ",>+~{}"
+	if declarations {
+		tight = ",:;!{}"
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(css))
+	prev := byte(0)
+	for i := 0; i < len(css); i++ {
+		c := css[i]
+		if c == '"' || c == '\'' {
+			end := skipCSSString(css, i)
+			sb.WriteString(css[i : end+1])
+			i, prev = end, css[end]
+			continue
+		}
+		if c == ' ' {
+			next := byte(0)
+			if i+1 < len(css) {
+				next = css[i+1]
+			}
+			if prev == 0 || next == 0 || strings.IndexByte(tight, prev) >= 0 || strings.IndexByte(tight, next) >= 0 {
+				continue
+			}
+		}
+		sb.WriteByte(c)
+		prev = c
+	}
+	return sb.String()
+}
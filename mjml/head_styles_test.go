@@ -0,0 +1,96 @@
+package mjml
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestHeadStyleRegistryMerge(t *testing.T) {
+	r := newHeadStyleRegistry(false)
+	rest := r.add(`<style type="text/css">
+		/* base */
+		.a { color: red; }
+		@media only screen and (min-width:480px) { .col { width: 50% !important; } }
+		.b { color: blue }
+	</style><style media="screen">.moz { width: 1px; }</style>`)
+	if rest != `<style media="screen">.moz { width: 1px; }</style>` {
+		t.Errorf("remaining markup = %q", rest)
+	}
+	r.add(`<style type="text/css">@import url("https://example.com/font.css");
+		.a {color:red}
+		@media only screen and (min-width:480px) { .col { width:50% !important } .other { display: none; } }
+		.content::after { content: "a  b" }</style>`)
+
+	want := `@import url("https://example.com/font.css");
+.b { color: blue }
+.a { color:red }
+@media only screen and (min-width:480px) {
+  .col { width:50% !important }
+  .other { display: none; }
+}
+.content::after { content: "a  b" }`
+	if got := r.String(); got != want {
+		t.Errorf("merged CSS =\n%s\nwant\n%s", got, want)
+	}
+
+	r.minify = true
+	wantMin := `@import url("https://example.com/font.css");.b{color:blue}.a{color:red}` +
+		`@media only screen and (min-width:480px){.col{width:50%!important}.other{display:none}}` +
+		`.content::after{content:"a  b"}`
+	if got := r.String(); got != wantMin {
+		t.Errorf("minified CSS =\n%s\nwant\n%s", got, wantMin)
+	}
+}
+
+func TestMergedHeadStyles(t *testing.T) {
+	const carousel = `<mj-carousel><mj-carousel-image src="https://example.com/1.jpg" /><mj-carousel-image src="https://example.com/2.jpg" /></mj-carousel>`
+	const accordion = `<mj-accordion><mj-accordion-element><mj-accordion-title>Q</mj-accordion-title><mj-accordion-text>A</mj-accordion-text></mj-accordion-element></mj-accordion>`
+	input := `<mjml><mj-head><mj-style>.custom { color: red; } .mj-carousel { -webkit-user-select: none; -moz-user-select: none; user-select: none; }</mj-style></mj-head><mj-body>` +
+		`<mj-section><mj-column>` + carousel + accordion + `</mj-column></mj-section>` +
+		`<mj-section><mj-column>` + carousel + accordion + `</mj-column></mj-section>` +
+		`</mj-body></mjml>`
+
+	ids := WithIDGenerator(func(component string, index int) string {
+		return fmt.Sprintf("%s-%d", component, index)
+	})
+	plain, err := Render(input, ids)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	merged, err := Render(input, ids, WithMergedHeadStyles())
+	if err != nil {
+		t.Fatalf("Render with merged head styles failed: %v", err)
+	}
+	minified, err := Render(input, ids, WithMinifiedHeadStyles())
+	if err != nil {
+		t.Fatalf("Render with minified head styles failed: %v", err)
+	}
+
+	if len(merged) >= len(plain) || len(minified) >= len(merged) {
+		t.Errorf("expected plain > merged > minified, got %d, %d, %d bytes", len(plain), len(merged), len(minified))
+	}
+	if plainBody, mergedBody := plain[strings.Index(plain, "<body"):], merged[strings.Index(merged, "<body"):]; plainBody != mergedBody {
+		t.Error("merging head styles must not change the body")
+	}
+
+	for name, html := range map[string]string{"merged": merged, "minified": minified} {
+		head := html[:strings.Index(html, "</head>")]
+		head = head[strings.LastIndex(head, "<![endif]-->"):]
+		if n := strings.Count(head, `<style type="text/css">`); n != 1 {
+			t.Errorf("%s: %d style tags outside conditional comments, want 1", name, n)
+		}
+		if n := strings.Count(head, ".mj-carousel {") + strings.Count(head, ".mj-carousel{"); n != 1 {
+			t.Errorf("%s: carousel rule emitted %d times, want once", name, n)
+		}
+		if n := strings.Count(head, "noinput.mj-accordion-checkbox"); n != 1 {
+			t.Errorf("%s: accordion rule emitted %d times, want once", name, n)
+		}
+		if !strings.Contains(head, ".custom") {
+			t.Errorf("%s: mj-style rules missing", name)
+		}
+		if !strings.Contains(head, `<style media="screen and (min-width:480px)">`) {
+			t.Errorf("%s: style tags with a media attribute must be kept", name)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package mjml
+
+import (
+	"fmt"
+
+	"github.com/preslavrachev/gomjml/mjml/components"
+)
+
+// WithHeroOverflowChecks reports fixed-height mj-hero elements whose content
+// is likely taller than the height left inside their padding. MJML renders
+// them without complaint, and the content spills over the background image,
+// which Outlook draws at its own fixed size. The content height is a rough
+// estimate: text is wrapped at an average glyph width of half its font size,
+// and images only count when they have a pixel height. Problems are returned
+// as an Error alongside the rendered HTML, like accessibility problems.
+func WithHeroOverflowChecks() RenderOption {
+	return func(opts *RenderOpts) {
+		opts.HeroOverflowChecks = true
+	}
+}
+
+// ErrHeroOverflow returns an Error describing a fixed-height hero whose
+// content, estimated at content pixels, does not fit in its height pixels.
+func ErrHeroOverflow(content, height, line int) *Error {
+	return &Error{
+		Message: "MJML layout issues",
+		Details: []ErrorDetail{{
+			Line:    line,
+			Message: fmt.Sprintf("content is about %dpx tall but the fixed height leaves %dpx for it; it will overflow", content, height),
+			TagName: "mj-hero",
+		}},
+	}
+}
+
+// checkHeroOverflow walks the component tree and reports the fixed-height
+// heroes whose content is estimated to be taller than they are.
+func checkHeroOverflow(comp Component, opts *RenderOpts) *Error {
+	if !opts.HeroOverflowChecks {
+		return nil
+	}
+
+	var issues *Error
+	var walk func(comp Component)
+	walk = func(comp Component) {
+		if hero, ok := comp.(*components.MJHeroComponent); ok {
+			if height, ok := hero.FixedHeight(); ok {
+				if content := hero.EstimateContentHeight(); content > height {
+					if issues == nil {
+						issues = ErrHeroOverflow(content, height, hero.Node.GetLineNumber())
+					} else {
+						issues.Append(ErrHeroOverflow(content, height, hero.Node.GetLineNumber()))
+					}
+				}
+			}
+			return
+		}
+		for _, child := range componentChildren(comp) {
+			walk(child)
+		}
+	}
+	walk(comp)
+	return issues
+}
@@ -0,0 +1,138 @@
+package mjml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestHeroFluidHeightMode verifies that fluid-height heroes size themselves from the
+// background aspect ratio using padding-bottom spacer cells instead of a fixed height.
+func TestHeroFluidHeightMode(t *testing.T) {
+	input := `<mjml>
+  <mj-body>
+    <mj-hero mode="fluid-height" background-width="600px" background-height="469px"
+      background-url="https://example.com/bg.jpg" background-color="#2a3448"
+      vertical-align="middle" background-position="top center" padding="20px">
+      <mj-text>GO TO SPACE</mj-text>
+    </mj-hero>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	spacer := `<td style="width:0.01%;padding-bottom:78.17%;mso-padding-bottom-alt:0;" />`
+	if got := strings.Count(html, spacer); got != 2 {
+		t.Errorf("expected 2 fluid spacer cells, got %d", got)
+	}
+	if !strings.Contains(html, spacer+`<td background="https://example.com/bg.jpg"`) {
+		t.Error("expected content cell to follow the leading spacer cell")
+	}
+	if !strings.Contains(html, `</td>`+spacer+`</tr>`) {
+		t.Error("expected trailing spacer cell after the content cell")
+	}
+	if !strings.Contains(html, `vertical-align:middle`) || !strings.Contains(html, `background-position:top center`) {
+		t.Error("expected vertical-align and background-position on the hero cell")
+	}
+	if !strings.Contains(html, `<v:image style="border:0;height:469px;mso-position-horizontal:center;position:absolute;top:0;width:600px;z-index:-3;" src="https://example.com/bg.jpg"`) {
+		t.Error("expected VML background fallback for Outlook")
+	}
+
+	heroCell := html[strings.Index(html, `<td background=`):]
+	heroCell = heroCell[:strings.Index(heroCell, ">")]
+	if strings.Contains(heroCell, "height") {
+		t.Errorf("fluid-height hero cell must not have a fixed height: %s", heroCell)
+	}
+}
+
+func TestHeroInnerBackgroundAndBorderRadius(t *testing.T) {
+	input := `<mjml>
+  <mj-body>
+    <mj-hero height="300px" border-radius="8px" inner-background-color="#ffffff" inner-padding="10px">
+      <mj-text>Hello</mj-text>
+    </mj-hero>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`background-repeat:no-repeat;border-radius:8px;padding:0px;`,
+		`<td style="background-color:#ffffff;padding:10px;">`,
+		`<div class="mj-hero-content" style="background-color:#ffffff;margin:0px auto;`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+}
+
+// TestHeroFixedHeight checks the content cell height against MJML, which takes
+// the number of the height and the padding whatever their units.
+func TestHeroFixedHeight(t *testing.T) {
+	tests := []struct {
+		attrs string
+		want  string
+	}{
+		{`height="350px"`, `height="350" style="background:#ffffff;background-position:center center;background-repeat:no-repeat;padding:0px;vertical-align:top;height:350px;"`},
+		{`height="350px" padding="20px 0" padding-bottom="30px"`, `height="300" style="background:#ffffff;background-position:center center;background-repeat:no-repeat;padding:20px 0;vertical-align:top;height:300px;padding-bottom:30px;"`},
+		{`height="400px" background-position="top left" padding="10%"`, `height="380" style="background:#ffffff;background-position:top left;background-repeat:no-repeat;padding:10%;vertical-align:top;height:380px;"`},
+		{`height="50px" padding="40px"`, `height="-30" style="background:#ffffff;background-position:center center;background-repeat:no-repeat;padding:40px;vertical-align:top;height:-30px;"`},
+	}
+	for _, tt := range tests {
+		html, err := Render(`<mjml><mj-body><mj-hero ` + tt.attrs + `><mj-text>Hi</mj-text></mj-hero></mj-body></mjml>`)
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		if !strings.Contains(html, `<td `+tt.want+`>`) {
+			t.Errorf("hero with %s: expected cell <td %s>", tt.attrs, tt.want)
+		}
+	}
+}
+
+func TestHeroOverflowChecks(t *testing.T) {
+	input := `<mjml>
+  <mj-body>
+    <mj-hero height="120px" padding="20px">
+      <mj-text font-size="20px" line-height="30px">A headline long enough to wrap onto a second line in the hero</mj-text>
+      <mj-button>Go</mj-button>
+    </mj-hero>
+    <mj-hero height="400px" padding="20px">
+      <mj-text>Fits</mj-text>
+    </mj-hero>
+    <mj-hero mode="fluid-height" height="10px" background-width="600px" background-height="300px">
+      <mj-text>Follows the background</mj-text>
+    </mj-hero>
+  </mj-body>
+</mjml>`
+
+	want, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	html, err := Render(input, WithHeroOverflowChecks())
+	var mjmlErr Error
+	if !errors.As(err, &mjmlErr) {
+		t.Fatalf("Render() error = %v, want an overflow report", err)
+	}
+	if html != want {
+		t.Error("overflow checks changed the output")
+	}
+	if len(mjmlErr.Details) != 1 {
+		t.Fatalf("got %d problems, want 1: %v", len(mjmlErr.Details), err)
+	}
+	// Two 30px lines and 20px padding, and a 35px button with 20px padding,
+	// in the 80px left inside the hero's padding
+	detail := mjmlErr.Details[0]
+	if detail.Line != 3 || detail.TagName != "mj-hero" ||
+		detail.Message != "content is about 135px tall but the fixed height leaves 80px for it; it will overflow" {
+		t.Errorf("unexpected problem %+v", detail)
+	}
+}
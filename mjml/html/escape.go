@@ -0,0 +1,102 @@
+package html
+
+import "strings"
+
+// EscapePolicy selects how attribute values and text taken from the MJML
+// source are escaped in the HTML output.
+type EscapePolicy int
+
+const (
+	// EscapeMJML reproduces MJML, which copies values from the source as
+	// written. The parser decodes entities, so characters are escaped again
+	// only where the output would otherwise mean something else: " in
+	// attribute values, <, and an & that would start a character reference,
+	// such as the one in "&amp;lt;". Other ampersands stay bare, as in
+	// "?a=1&b=2".
+	EscapeMJML EscapePolicy = iota
+	// EscapeSafe escapes every &, <, >, " and ', for consumers that parse the
+	// output as XML or embed it in other markup.
+	EscapeSafe
+)
+
+var safeEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&#39;")
+
+// EscapeAttribute escapes a value for a double-quoted HTML attribute.
+//
+// Example:
+//
+//	html.EscapeAttribute(`say "hi"`, html.EscapeMJML)  // "say &quot;hi&quot;"
+//	html.EscapeAttribute("/?a=1&b=2", html.EscapeSafe) // "/?a=1&amp;b=2"
+func EscapeAttribute(value string, policy EscapePolicy) string {
+	if policy == EscapeSafe {
+		return safeEscaper.Replace(value)
+	}
+	return escapeMJML(value, true)
+}
+
+// EscapeText escapes text content.
+//
+// Example:
+//
+//	html.EscapeText("R&D <3", html.EscapeMJML)       // "R&amp;D &lt;3"
+//	html.EscapeText("Tom & Jerry's", html.EscapeSafe) // "Tom &amp; Jerry&#39;s"
+func EscapeText(value string, policy EscapePolicy) string {
+	if policy == EscapeSafe {
+		return safeEscaper.Replace(value)
+	}
+	return escapeMJML(value, false)
+}
+
+// escapeMJML escapes < and ambiguous ampersands, and " when attribute is set.
+// In an attribute an & only starts a reference when a name ends with ";" or
+// it is followed by "#"; text also decodes legacy names such as "&copy", so
+// there any & followed by a letter or "#" is escaped.
+func escapeMJML(value string, attribute bool) string {
+	if !strings.ContainsAny(value, `&<"`) {
+		return value
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(value) + 8)
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '<':
+			sb.WriteString("&lt;")
+		case c == '"' && attribute:
+			sb.WriteString("&quot;")
+		case c == '&' && startsReference(value[i+1:], attribute):
+			sb.WriteString("&amp;")
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// startsReference reports whether an & followed by rest would be read as a
+// character reference.
+func startsReference(rest string, attribute bool) bool {
+	if rest == "" {
+		return false
+	}
+	if rest[0] == '#' {
+		return true
+	}
+	if !attribute {
+		return isASCIILetter(rest[0])
+	}
+	for i := 0; i < len(rest); i++ {
+		switch c := rest[i]; {
+		case c == ';':
+			return i > 0
+		case isASCIILetter(c), '0' <= c && c <= '9':
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
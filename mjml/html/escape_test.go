@@ -0,0 +1,42 @@
+package html
+
+import "testing"
+
+func TestEscapeAttribute(t *testing.T) {
+	tests := []struct {
+		input, mjml, safe string
+	}{
+		{"https://x.com/?a=1&b=2", "https://x.com/?a=1&b=2", "https://x.com/?a=1&amp;b=2"},
+		{"?q=&lt;&#60;", "?q=&amp;lt;&amp;#60;", "?q=&amp;lt;&amp;#60;"},
+		{`say "hi" <b>`, "say &quot;hi&quot; &lt;b>", "say &quot;hi&quot; &lt;b&gt;"},
+		{"it's & done;", "it's & done;", "it&#39;s &amp; done;"},
+		{"&copy=1", "&copy=1", "&amp;copy=1"},
+	}
+	for _, tt := range tests {
+		if got := EscapeAttribute(tt.input, EscapeMJML); got != tt.mjml {
+			t.Errorf("EscapeAttribute(%q, EscapeMJML) = %q, want %q", tt.input, got, tt.mjml)
+		}
+		if got := EscapeAttribute(tt.input, EscapeSafe); got != tt.safe {
+			t.Errorf("EscapeAttribute(%q, EscapeSafe) = %q, want %q", tt.input, got, tt.safe)
+		}
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		input, mjml, safe string
+	}{
+		{"Tom & Jerry", "Tom & Jerry", "Tom &amp; Jerry"},
+		{"R&D", "R&amp;D", "R&amp;D"},
+		{`1 < 2 > "0"`, `1 &lt; 2 > "0"`, "1 &lt; 2 &gt; &quot;0&quot;"},
+		{"&#169;", "&amp;#169;", "&amp;#169;"},
+	}
+	for _, tt := range tests {
+		if got := EscapeText(tt.input, EscapeMJML); got != tt.mjml {
+			t.Errorf("EscapeText(%q, EscapeMJML) = %q, want %q", tt.input, got, tt.mjml)
+		}
+		if got := EscapeText(tt.input, EscapeSafe); got != tt.safe {
+			t.Errorf("EscapeText(%q, EscapeSafe) = %q, want %q", tt.input, got, tt.safe)
+		}
+	}
+}
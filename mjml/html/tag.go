@@ -18,6 +18,7 @@ type HTMLTag struct {
 	attributes []AttributeProperty
 	classes    []string
 	styles     []StyleProperty
+	ruleStyles []StyleProperty
 }
 
 // AttributeProperty represents a single HTML attribute with its name and value.
@@ -51,6 +52,11 @@ func NewHTMLTag(name string) *HTMLTag {
 	}
 }
 
+// Name returns the element name of the tag.
+func (t *HTMLTag) Name() string {
+	return t.name
+}
+
 // AddStyle adds a CSS style property to the HTML tag.
 // Styles are added in order and will appear in the rendered style attribute
 // in the same sequence they were added.
@@ -93,6 +99,22 @@ func (t *HTMLTag) MaybeAddStyleString(name, value string) *HTMLTag {
 	return t
 }
 
+// AddRuleStyle adds a CSS property taken from a stylesheet rule, such as an
+// inline mj-style. Rule styles are rendered before the tag's own styles,
+// whenever either is added, the way MJML inlines stylesheets. A property the
+// tag sets itself keeps its own value, unless the rule value is !important.
+//
+// Returns the HTMLTag to enable method chaining.
+//
+// Example:
+//
+//	tag.AddStyle("color", "#000000").AddRuleStyle("box-shadow", "0 1px 2px #cccccc")
+//	// style="box-shadow:0 1px 2px #cccccc;color:#000000;"
+func (t *HTMLTag) AddRuleStyle(name, value string) *HTMLTag {
+	t.ruleStyles = append(t.ruleStyles, StyleProperty{name, value})
+	return t
+}
+
 // AddAttribute adds an HTML attribute to the tag.
 // If an attribute with the same name already exists, it will be overwritten.
 //
@@ -270,11 +292,11 @@ func (t *HTMLTag) renderAttributes(w io.StringWriter) error {
 	}
 
 	// Add inline styles
-	if len(t.styles) > 0 {
+	if styles := t.resolvedStyles(); len(styles) > 0 {
 		if _, err := w.WriteString(` style="`); err != nil {
 			return err
 		}
-		for _, style := range t.styles {
+		for _, style := range styles {
 			if _, err := w.WriteString(style.Name); err != nil {
 				return err
 			}
@@ -294,3 +316,43 @@ func (t *HTMLTag) renderAttributes(w io.StringWriter) error {
 	}
 	return nil
 }
+
+// resolvedStyles returns the rule styles followed by the tag's own styles,
+// dropping whichever of a rule and an own property with the same name loses.
+func (t *HTMLTag) resolvedStyles() []StyleProperty {
+	if len(t.ruleStyles) == 0 {
+		return t.styles
+	}
+
+	important := make(map[string]bool, len(t.ruleStyles))
+	for _, style := range t.ruleStyles {
+		important[style.Name] = IsImportant(style.Value)
+	}
+	own := make(map[string]bool, len(t.styles))
+	for _, style := range t.styles {
+		own[style.Name] = true
+	}
+
+	resolved := make([]StyleProperty, 0, len(t.ruleStyles)+len(t.styles))
+	for _, style := range t.ruleStyles {
+		if !own[style.Name] || important[style.Name] {
+			resolved = append(resolved, style)
+		}
+	}
+	for _, style := range t.styles {
+		if !important[style.Name] {
+			resolved = append(resolved, style)
+		}
+	}
+	return resolved
+}
+
+// IsImportant reports whether a CSS value carries the !important flag.
+func IsImportant(value string) bool {
+	value = strings.TrimSpace(value)
+	if !strings.HasSuffix(value, "important") {
+		return false
+	}
+	value = strings.TrimSpace(strings.TrimSuffix(value, "important"))
+	return strings.HasSuffix(value, "!")
+}
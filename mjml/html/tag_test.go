@@ -375,3 +375,24 @@ func TestStyleOrdering(t *testing.T) {
 		t.Errorf("Expected '%s', got '%s'", expected, result)
 	}
 }
+
+// TestRuleStyleOrdering verifies that rule styles render before the tag's own
+// styles and lose to them unless !important
+func TestRuleStyleOrdering(t *testing.T) {
+	tag := NewHTMLTag("div").
+		AddStyle("margin", "0px auto").
+		AddRuleStyle("box-shadow", "0 1px 2px #ccc").
+		AddRuleStyle("margin", "10px").
+		AddRuleStyle("color", "red !important").
+		AddStyle("color", "#000000").
+		AddStyle("max-width", "600px")
+
+	var buf strings.Builder
+	if err := tag.RenderOpen(&buf); err != nil {
+		t.Fatalf("RenderOpen failed: %v", err)
+	}
+	expected := `<div style="box-shadow:0 1px 2px #ccc;color:red !important;margin:0px auto;max-width:600px;">`
+	if result := buf.String(); result != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, result)
+	}
+}
@@ -0,0 +1,186 @@
+package htmldiff
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ignoredAttributePrefix marks attributes added by gomjml's debug builds.
+const ignoredAttributePrefix = "data-mj-debug"
+
+// compareDOM parses both documents and compares them element by element.
+func compareDOM(expected, actual string, report *Report) error {
+	expectedDoc, err := goquery.NewDocumentFromReader(strings.NewReader(expected))
+	if err != nil {
+		return fmt.Errorf("parsing expected document: %w", err)
+	}
+	actualDoc, err := goquery.NewDocumentFromReader(strings.NewReader(actual))
+	if err != nil {
+		return fmt.Errorf("parsing actual document: %w", err)
+	}
+
+	compareChildren(expectedDoc.Selection, actualDoc.Selection, "", report)
+	return nil
+}
+
+// compareChildren compares the element children of two matching elements.
+// When the number of children differs the subtrees are not compared further,
+// since positional comparison would report every following element.
+func compareChildren(expected, actual *goquery.Selection, path string, report *Report) {
+	expectedChildren := expected.Children()
+	actualChildren := actual.Children()
+	if expectedChildren.Length() != actualChildren.Length() {
+		report.add(Difference{
+			Kind:     KindStructure,
+			Path:     path,
+			Message:  "child elements differ",
+			Expected: describeElements(expectedChildren),
+			Actual:   describeElements(actualChildren),
+		})
+		return
+	}
+
+	tagCounts := make(map[string]int, expectedChildren.Length())
+	expectedChildren.Each(func(_ int, child *goquery.Selection) {
+		tagCounts[goquery.NodeName(child)]++
+	})
+	for i := 0; i < expectedChildren.Length(); i++ {
+		child := expectedChildren.Eq(i)
+		compareElement(child, actualChildren.Eq(i), childPath(path, child, i, tagCounts[goquery.NodeName(child)] > 1), report)
+	}
+}
+
+// compareElement compares a pair of elements at the same position.
+func compareElement(expected, actual *goquery.Selection, path string, report *Report) {
+	expectedTag := goquery.NodeName(expected)
+	actualTag := goquery.NodeName(actual)
+	if expectedTag != actualTag {
+		report.add(Difference{
+			Kind:     KindStructure,
+			Path:     path,
+			Message:  "element differs",
+			Expected: "<" + expectedTag + ">",
+			Actual:   "<" + actualTag + ">",
+		})
+		return
+	}
+
+	compareAttributes(expected, actual, path, report)
+	compareChildren(expected, actual, path, report)
+
+	expectedText := ownText(expected)
+	actualText := ownText(actual)
+	if expectedTag == "style" {
+		if !cssEqual(expectedText, actualText) {
+			report.add(Difference{Kind: KindCSS, Path: path, Message: "style sheet differs", Expected: expectedText, Actual: actualText})
+		}
+		return
+	}
+	if expectedText != actualText {
+		report.add(Difference{Kind: KindText, Path: path, Message: "text differs", Expected: expectedText, Actual: actualText})
+	}
+}
+
+// compareAttributes reports differing attributes. style is compared as a set
+// of declarations and class as a set of class names.
+func compareAttributes(expected, actual *goquery.Selection, path string, report *Report) {
+	expectedAttrs := attributes(expected)
+	actualAttrs := attributes(actual)
+
+	names := make([]string, 0, len(expectedAttrs)+len(actualAttrs))
+	for name := range expectedAttrs {
+		names = append(names, name)
+	}
+	for name := range actualAttrs {
+		if _, ok := expectedAttrs[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		expectedVal, inExpected := expectedAttrs[name]
+		actualVal, inActual := actualAttrs[name]
+
+		switch name {
+		case "style":
+			if diff := CompareStyles(expectedVal, actualVal); !diff.IsEmpty() {
+				report.add(Difference{Kind: KindStyle, Path: path, Message: diff.String(), Expected: expectedVal, Actual: actualVal})
+			}
+			continue
+		case "class":
+			if normalizeClass(expectedVal) == normalizeClass(actualVal) {
+				continue
+			}
+		default:
+			if inExpected == inActual && expectedVal == actualVal {
+				continue
+			}
+		}
+
+		switch {
+		case !inActual:
+			report.add(Difference{Kind: KindAttribute, Path: path, Message: "missing attribute " + name, Expected: expectedVal})
+		case !inExpected:
+			report.add(Difference{Kind: KindAttribute, Path: path, Message: "unexpected attribute " + name, Actual: actualVal})
+		default:
+			report.add(Difference{Kind: KindAttribute, Path: path, Message: "attribute " + name + " differs", Expected: expectedVal, Actual: actualVal})
+		}
+	}
+}
+
+// attributes returns the attributes of the first element of sel, without
+// gomjml debug attributes.
+func attributes(sel *goquery.Selection) map[string]string {
+	attrs := make(map[string]string)
+	if sel.Length() == 0 {
+		return attrs
+	}
+	for _, attr := range sel.Get(0).Attr {
+		if strings.HasPrefix(attr.Key, ignoredAttributePrefix) {
+			continue
+		}
+		attrs[attr.Key] = attr.Val
+	}
+	return attrs
+}
+
+// ownText returns the text directly inside sel, with whitespace collapsed.
+func ownText(sel *goquery.Selection) string {
+	return strings.Join(strings.Fields(sel.Contents().Not("*").Text()), " ")
+}
+
+func normalizeClass(class string) string {
+	parts := strings.Fields(class)
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
+
+// childPath appends the element at index i to path. The position is included
+// as :nth-child when the parent has other element children with the same tag.
+func childPath(path string, sel *goquery.Selection, i int, ambiguous bool) string {
+	segment := goquery.NodeName(sel)
+	if ambiguous {
+		segment += ":nth-child(" + strconv.Itoa(i+1) + ")"
+	}
+	if path == "" {
+		return segment
+	}
+	return path + " > " + segment
+}
+
+// describeElements lists the tag names of sel, such as "<tr>, <tr>".
+func describeElements(sel *goquery.Selection) string {
+	if sel.Length() == 0 {
+		return "(none)"
+	}
+	tags := make([]string, sel.Length())
+	sel.Each(func(i int, s *goquery.Selection) {
+		tags[i] = "<" + goquery.NodeName(s) + ">"
+	})
+	return strings.Join(tags, ", ")
+}
@@ -0,0 +1,139 @@
+// Package htmldiff compares rendered email HTML semantically. It reports the
+// differences that matter to email clients (element structure, attributes,
+// inline styles, head CSS, Outlook conditional comments and void element
+// serialization) while ignoring attribute order, style declaration order and
+// insignificant whitespace.
+//
+// It is the comparison used by gomjml's own MRML fixture tests, exposed so
+// projects migrating from mjml-js can verify that gomjml produces equivalent
+// output for their templates:
+//
+//	report := htmldiff.Compare(mjmlJSOutput, gomjmlOutput)
+//	if !report.Equal() {
+//		fmt.Println(report)
+//	}
+package htmldiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind classifies a Difference.
+type Kind int
+
+const (
+	KindStructure      Kind = iota // Elements missing, extra or of a different type
+	KindAttribute                  // An attribute other than style differs
+	KindStyle                      // Inline style declarations differ
+	KindText                       // Text content differs
+	KindCSS                        // The content of a style tag differs
+	KindMSOConditional             // Outlook conditional comments differ
+	KindMSOTable                   // Table attributes inside conditional comments differ
+	KindSerialization              // Void elements are serialized differently
+)
+
+var kindNames = [...]string{
+	KindStructure:      "structure",
+	KindAttribute:      "attribute",
+	KindStyle:          "style",
+	KindText:           "text",
+	KindCSS:            "css",
+	KindMSOConditional: "mso-conditional",
+	KindMSOTable:       "mso-table",
+	KindSerialization:  "serialization",
+}
+
+// String returns the lowercase name of the kind, such as "style".
+func (k Kind) String() string {
+	if k >= 0 && int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Difference is a single semantic difference between two documents.
+type Difference struct {
+	Kind     Kind
+	Path     string // Element path such as "html > body > div > table:nth-child(2)"; empty for document-wide checks
+	Message  string // Human-readable description
+	Expected string // Expected value, when the difference concerns a single value
+	Actual   string // Actual value, when the difference concerns a single value
+}
+
+// String formats the difference on one or more lines.
+func (d Difference) String() string {
+	var sb strings.Builder
+	sb.WriteString("[")
+	sb.WriteString(d.Kind.String())
+	sb.WriteString("] ")
+	if d.Path != "" {
+		sb.WriteString(d.Path)
+		sb.WriteString(": ")
+	}
+	sb.WriteString(d.Message)
+	if d.Expected != "" || d.Actual != "" {
+		sb.WriteString("\n    expected: ")
+		sb.WriteString(d.Expected)
+		sb.WriteString("\n    actual:   ")
+		sb.WriteString(d.Actual)
+	}
+	return sb.String()
+}
+
+// Report is the result of Compare.
+type Report struct {
+	Differences []Difference
+}
+
+// Equal reports whether the documents are semantically equivalent.
+func (r Report) Equal() bool {
+	return len(r.Differences) == 0
+}
+
+// Filter returns the differences of the given kinds.
+func (r Report) Filter(kinds ...Kind) []Difference {
+	var out []Difference
+	for _, d := range r.Differences {
+		for _, k := range kinds {
+			if d.Kind == k {
+				out = append(out, d)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// String lists the differences, one per line, or "no differences".
+func (r Report) String() string {
+	if r.Equal() {
+		return "no differences"
+	}
+	lines := make([]string, len(r.Differences))
+	for i, d := range r.Differences {
+		lines[i] = d.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *Report) add(d Difference) {
+	r.Differences = append(r.Differences, d)
+}
+
+// Compare compares the expected and actual HTML documents. Both are first
+// passed through Normalize, so serialization changes between MJML versions
+// are not reported.
+func Compare(expected, actual string) Report {
+	expected = Normalize(expected)
+	actual = Normalize(actual)
+
+	var report Report
+	compareMSOTables(expected, actual, &report)
+	compareMSOConditionals(expected, actual, &report)
+	if err := compareDOM(expected, actual, &report); err != nil {
+		report.add(Difference{Kind: KindStructure, Message: err.Error()})
+	}
+	compareVoidElements(expected, actual, &report)
+	return report
+}
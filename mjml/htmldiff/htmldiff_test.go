@@ -0,0 +1,119 @@
+package htmldiff
+
+import (
+	"strings"
+	"testing"
+)
+
+const baseDocument = `<!doctype html><html><head><style type="text/css">.a { color:red } .b { color:blue }</style></head>` +
+	`<body><div class="x y" style="color:red;padding:0"><!--[if mso | IE]><table align="center" width="600"><tr><td><![endif]-->` +
+	`<p>Hello <b>world</b></p><img src="a.png" alt=""><!--[if mso | IE]></td></tr></table><![endif]--></div></body></html>`
+
+func TestCompareIgnoresSerializationDetails(t *testing.T) {
+	reordered := strings.NewReplacer(
+		`class="x y" style="color:red;padding:0"`, `style="padding: 0; color: red;" class="y x"`,
+		`<table align="center" width="600">`, `<table width="600" align="center">`,
+		`.a { color:red } .b { color:blue }`, `.b{color:blue}.a{color:red}`,
+		`<p>Hello <b>`, "<p>\n  Hello\n  <b>",
+	).Replace(baseDocument)
+
+	if report := Compare(baseDocument, reordered); !report.Equal() {
+		t.Errorf("expected equivalent documents, got:\n%s", report)
+	}
+}
+
+func TestCompareReportsDifferences(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   string
+		to     string
+		kind   Kind
+		path   string
+		detail string
+	}{
+		{
+			name: "inline style", from: `padding:0"`, to: `padding:10px"`,
+			kind: KindStyle, path: "html > body > div", detail: "padding=0→10px",
+		},
+		{
+			name: "attribute", from: `src="a.png"`, to: `src="b.png"`,
+			kind: KindAttribute, path: "html > body > div > img", detail: "attribute src differs",
+		},
+		{
+			name: "missing element", from: `<img src="a.png" alt="">`, to: ``,
+			kind: KindStructure, path: "html > body > div", detail: "child elements differ",
+		},
+		{
+			name: "text", from: `Hello`, to: `Goodbye`,
+			kind: KindText, path: "html > body > div > p", detail: "text differs",
+		},
+		{
+			name: "head css", from: `.b { color:blue }`, to: `.b { color:green }`,
+			kind: KindCSS, path: "html > head > style", detail: "style sheet differs",
+		},
+		{
+			name: "conditional table", from: `width="600"`, to: `width="550"`,
+			kind: KindMSOTable, detail: "width of conditional table 1 differs",
+		},
+		{
+			name: "conditional comment", from: `<![endif]--></div>`, to: `<![endif]--><!--[if mso]><br><![endif]--></div>`,
+			kind: KindMSOConditional, detail: "number of mso opening comments differs",
+		},
+		{
+			name: "void element", from: `alt="">`, to: `alt="" />`,
+			kind: KindSerialization, detail: "serialization of <img> differs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := strings.Replace(baseDocument, tt.from, tt.to, 1)
+			report := Compare(baseDocument, actual)
+
+			diffs := report.Filter(tt.kind)
+			if len(diffs) == 0 {
+				t.Fatalf("expected a %s difference, got:\n%s", tt.kind, report)
+			}
+			if diffs[0].Path != tt.path {
+				t.Errorf("path = %q, want %q", diffs[0].Path, tt.path)
+			}
+			if !strings.Contains(diffs[0].Message, tt.detail) {
+				t.Errorf("message = %q, want it to contain %q", diffs[0].Message, tt.detail)
+			}
+		})
+	}
+}
+
+func TestCSSEqual(t *testing.T) {
+	tests := []struct {
+		name  string
+		css1  string
+		css2  string
+		equal bool
+	}{
+		{"identical", ".mj-column-per-100 { width:100% }", ".mj-column-per-100 { width:100% }", true},
+		{"rule order", ".a { width:100% } .b { width:50% }", ".b { width:50% } .a { width:100% }", true},
+		{"whitespace", ".a{width:100%}.b{width:50%}", ".a { width: 100% } .b { width: 50% }", true},
+		{"content", ".a { width:100% }", ".a { width:50% }", false},
+		{"media query order", "@media only screen { .a { width:100% } .b { width:50% } }", "@media only screen { .b { width:50% } .a { width:100% } }", true},
+		{"missing firefox rules", ".moz-text-html .a { width:100% }", ".a { width:100% }", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cssEqual(tt.css1, tt.css2); got != tt.equal {
+				t.Errorf("cssEqual() = %v, want %v", got, tt.equal)
+			}
+		})
+	}
+}
+
+func TestNormalizeMergesSplitConditionals(t *testing.T) {
+	split := `<!--[if mso | IE]><table role="presentation"><tr><![endif]-->` + "\n" +
+		`<!--[if mso | IE]><td style="width:600px;"><![endif]-->`
+	want := `<!--[if mso | IE]><table class="" role="presentation"><tr><td class="" style="width:600px;" ><![endif]-->`
+
+	if got := Normalize(split); got != want {
+		t.Errorf("Normalize() = %q, want %q", got, want)
+	}
+}
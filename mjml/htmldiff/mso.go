@@ -0,0 +1,225 @@
+package htmldiff
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// msoConditionals are the conditional comment markers emitted by MJML.
+var msoConditionals = []struct {
+	marker string
+	name   string
+}{
+	{"<!--[if mso]>", "mso opening"},
+	{"<!--[if !mso]><!-->", "not-mso opening"},
+	{"<!--<![endif]-->", "not-mso endif"},
+	{"<!--[if mso | IE]>", "mso-or-ie opening"},
+	{"<!--[if !mso | IE]><!-->", "not-mso-or-ie opening"},
+	{"<!--[if IE] mso |>", "ie-mso opening"},
+	{"<!--[if lte mso 11]>", "mso-lte-11 opening"},
+	{"<![endif]-->", "endif"},
+}
+
+// msoTableAttributes are the table attributes Outlook relies on.
+var msoTableAttributes = []string{"bgcolor", "width", "align", "cellpadding", "cellspacing"}
+
+var msoTableAttributePatterns = func() map[string]*regexp.Regexp {
+	patterns := make(map[string]*regexp.Regexp, len(msoTableAttributes))
+	for _, attr := range msoTableAttributes {
+		patterns[attr] = regexp.MustCompile(`<!--\[if mso.*?<table[^>]*` + attr + `="([^"]*)"`)
+	}
+	return patterns
+}()
+
+var (
+	msoBlockPattern     = regexp.MustCompile(`<!--\[if[^>]*>[\s\S]*?<!\[endif\]-->`)
+	whitespacePattern   = regexp.MustCompile(`\s+`)
+	attributePattern    = regexp.MustCompile(`([a-zA-Z0-9:-]+)="([^"]*)"`)
+	msoTableTagPatterns = []struct {
+		tag     string
+		pattern *regexp.Regexp
+	}{
+		{"table", regexp.MustCompile(`<table([^>]*)>`)},
+		{"td", regexp.MustCompile(`<td([^>]*)>`)},
+	}
+)
+
+// voidElements are the HTML void elements whose serialization is checked.
+var voidElements = []string{"br", "hr", "img", "input", "meta", "link", "area", "base", "col", "embed", "source", "track", "wbr"}
+
+var voidElementPatterns = func() map[string]*regexp.Regexp {
+	patterns := make(map[string]*regexp.Regexp, len(voidElements))
+	for _, tag := range voidElements {
+		patterns[tag] = regexp.MustCompile(`<` + tag + `(?:\s[^>]*)?/?>`)
+	}
+	return patterns
+}()
+
+// compareMSOTables compares the Outlook-only table attributes. Conditional
+// comments are invisible to the DOM comparison, so they are matched in the
+// source text.
+func compareMSOTables(expected, actual string, report *Report) {
+	for _, attr := range msoTableAttributes {
+		pattern := msoTableAttributePatterns[attr]
+		expectedValues := submatches(pattern, expected)
+		actualValues := submatches(pattern, actual)
+
+		if len(expectedValues) != len(actualValues) {
+			report.add(Difference{
+				Kind:     KindMSOTable,
+				Message:  fmt.Sprintf("number of conditional tables with %s differs", attr),
+				Expected: fmt.Sprint(len(expectedValues)),
+				Actual:   fmt.Sprint(len(actualValues)),
+			})
+			continue
+		}
+		for i, expectedValue := range expectedValues {
+			if expectedValue != actualValues[i] {
+				report.add(Difference{
+					Kind:     KindMSOTable,
+					Message:  fmt.Sprintf("%s of conditional table %d differs", attr, i+1),
+					Expected: expectedValue,
+					Actual:   actualValues[i],
+				})
+			}
+		}
+	}
+}
+
+// compareMSOConditionals compares the number of each kind of conditional
+// comment and, when those match, the content of every conditional block.
+func compareMSOConditionals(expected, actual string, report *Report) {
+	countsMatch := true
+	for _, c := range msoConditionals {
+		expectedCount := strings.Count(expected, c.marker)
+		actualCount := strings.Count(actual, c.marker)
+		if expectedCount != actualCount {
+			countsMatch = false
+			report.add(Difference{
+				Kind:     KindMSOConditional,
+				Message:  "number of " + c.name + " comments differs",
+				Expected: fmt.Sprint(expectedCount),
+				Actual:   fmt.Sprint(actualCount),
+			})
+		}
+	}
+	if !countsMatch {
+		return
+	}
+
+	expectedBlocks := msoBlocks(expected)
+	actualBlocks := msoBlocks(actual)
+	if len(expectedBlocks) != len(actualBlocks) {
+		report.add(Difference{
+			Kind:     KindMSOConditional,
+			Message:  "number of conditional blocks differs",
+			Expected: fmt.Sprint(len(expectedBlocks)),
+			Actual:   fmt.Sprint(len(actualBlocks)),
+		})
+		return
+	}
+	for i, block := range expectedBlocks {
+		if block != actualBlocks[i] {
+			report.add(Difference{
+				Kind:     KindMSOConditional,
+				Message:  fmt.Sprintf("conditional block %d differs", i+1),
+				Expected: block,
+				Actual:   actualBlocks[i],
+			})
+		}
+	}
+}
+
+// compareVoidElements compares how often each void element is written as
+// <tag> and as <tag/>. The DOM treats both alike, but some email clients and
+// XHTML processing do not.
+func compareVoidElements(expected, actual string, report *Report) {
+	expectedLower := strings.ToLower(expected)
+	actualLower := strings.ToLower(actual)
+	for _, tag := range voidElements {
+		expectedOpen, expectedClosed := countVoidForms(expectedLower, tag)
+		actualOpen, actualClosed := countVoidForms(actualLower, tag)
+		if expectedOpen != actualOpen || expectedClosed != actualClosed {
+			report.add(Difference{
+				Kind:     KindSerialization,
+				Message:  "serialization of <" + tag + "> differs",
+				Expected: fmt.Sprintf("%d unclosed, %d self-closed", expectedOpen, expectedClosed),
+				Actual:   fmt.Sprintf("%d unclosed, %d self-closed", actualOpen, actualClosed),
+			})
+		}
+	}
+}
+
+func countVoidForms(html, tag string) (open, closed int) {
+	for _, match := range voidElementPatterns[tag].FindAllString(html, -1) {
+		if strings.HasSuffix(match, "/>") {
+			closed++
+		} else {
+			open++
+		}
+	}
+	return open, closed
+}
+
+// msoBlocks returns the conditional comment blocks of html with whitespace
+// collapsed and table and td attributes sorted.
+func msoBlocks(html string) []string {
+	var blocks []string
+	for _, match := range msoBlockPattern.FindAllString(html, -1) {
+		block := whitespacePattern.ReplaceAllString(strings.TrimSpace(match), " ")
+		for _, t := range msoTableTagPatterns {
+			block = sortTagAttributes(block, t.tag, t.pattern)
+		}
+		if block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// sortTagAttributes rewrites every tag matched by pattern with its attributes
+// in alphabetical order.
+func sortTagAttributes(block, tag string, pattern *regexp.Regexp) string {
+	return pattern.ReplaceAllStringFunc(block, func(tagStr string) string {
+		matches := attributePattern.FindAllStringSubmatch(tagStr, -1)
+		if len(matches) == 0 {
+			return tagStr
+		}
+
+		values := make(map[string]string, len(matches))
+		keys := make([]string, 0, len(matches))
+		for _, m := range matches {
+			if _, ok := values[m[1]]; !ok {
+				keys = append(keys, m[1])
+			}
+			values[m[1]] = m[2]
+		}
+		sort.Strings(keys)
+
+		var b strings.Builder
+		b.WriteString("<")
+		b.WriteString(tag)
+		for _, key := range keys {
+			b.WriteString(" ")
+			b.WriteString(key)
+			b.WriteString(`="`)
+			b.WriteString(values[key])
+			b.WriteString(`"`)
+		}
+		b.WriteString(">")
+		return b.String()
+	})
+}
+
+// submatches returns the first capture group of every match of pattern.
+func submatches(pattern *regexp.Regexp, text string) []string {
+	var results []string
+	for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+		if len(match) > 1 {
+			results = append(results, match[1])
+		}
+	}
+	return results
+}
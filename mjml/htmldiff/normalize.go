@@ -0,0 +1,116 @@
+package htmldiff
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	msoSplitOpenPattern = regexp.MustCompile(
+		`<!--\[if mso \| IE\]><table([^>]*)><tr><!\[endif\]-->\s*<!--\[if mso \| IE\]><td([^>]*)><!\[endif\]-->`,
+	)
+	msoSplitClosePattern = regexp.MustCompile(
+		`<!--\[if mso \| IE\]>\s*</td>\s*<!\[endif\]-->\s*<!--\[if mso \| IE\]>\s*</tr>\s*</table>\s*<!\[endif\]-->`,
+	)
+	msoTableOpenPattern          = regexp.MustCompile(`<!--\[if mso \| IE\]><table([^>]*)>`)
+	emptyStylePattern            = regexp.MustCompile(`(?is)<style[^>]*>\s*</style>`)
+	rootDivPattern               = regexp.MustCompile(`<body([^>]*)><div([^>]*)>`)
+	mustacheAfterClosingPattern  = regexp.MustCompile(`(-->|>)\s+(\{\{)`)
+	mustacheBeforeOpeningPattern = regexp.MustCompile(`(\}\})\s+(<!--|<)`)
+	viewportPattern              = regexp.MustCompile(`(<meta[^>]*name="viewport"[^>]*content=")([^"]*)(")`)
+)
+
+// Normalize rewrites serialization differences between MJML and MRML versions
+// so that Compare focuses on semantic differences:
+//
+//   - Outlook table and cell openings split over two conditional comments are
+//     merged, as current MJML emits them, and so are the matching closings.
+//   - Conditional tables get the empty class attribute MJML adds.
+//   - Empty style tags are removed.
+//   - The root div gets MJML's role and aria-roledescription attributes.
+//   - Whitespace between tags and {{ }} template markers is removed.
+//   - Spaces after commas in the viewport meta tag are removed.
+func Normalize(html string) string {
+	normalized := msoSplitOpenPattern.ReplaceAllStringFunc(html, func(match string) string {
+		submatches := msoSplitOpenPattern.FindStringSubmatch(match)
+		if len(submatches) != 3 {
+			return match
+		}
+
+		tableAttrs := submatches[1]
+		tdAttrs := strings.TrimSpace(submatches[2])
+		if !strings.Contains(tdAttrs, "class=") {
+			if tdAttrs == "" {
+				tdAttrs = `class=""`
+			} else {
+				tdAttrs = `class="" ` + tdAttrs
+			}
+		}
+		return fmt.Sprintf("<!--[if mso | IE]><table%s><tr><td %s ><![endif]-->", tableAttrs, tdAttrs)
+	})
+
+	normalized = msoSplitClosePattern.ReplaceAllString(normalized, "<!--[if mso | IE]></td></tr></table><![endif]-->")
+
+	normalized = msoTableOpenPattern.ReplaceAllStringFunc(normalized, func(match string) string {
+		if strings.Contains(match, "class=") {
+			return match
+		}
+		if idx := strings.Index(match, `cellspacing="0"`); idx != -1 {
+			insertPos := idx + len(`cellspacing="0"`)
+			return match[:insertPos] + ` class=""` + match[insertPos:]
+		}
+		if idx := strings.Index(match, `role="presentation"`); idx != -1 {
+			return match[:idx] + `class="" ` + match[idx:]
+		}
+		return strings.Replace(match, "<table", `<table class=""`, 1)
+	})
+
+	normalized = emptyStylePattern.ReplaceAllString(normalized, "")
+
+	normalized = rootDivPattern.ReplaceAllStringFunc(normalized, func(match string) string {
+		submatches := rootDivPattern.FindStringSubmatch(match)
+		if len(submatches) != 3 {
+			return match
+		}
+
+		attrs := attributePattern.FindAllStringSubmatch(submatches[2], -1)
+		values := make(map[string]string, len(attrs)+2)
+		keys := make([]string, 0, len(attrs)+2)
+		for _, m := range attrs {
+			values[m[1]] = m[2]
+			keys = append(keys, m[1])
+		}
+		if _, ok := values["aria-roledescription"]; !ok {
+			values["aria-roledescription"] = "email"
+			keys = append(keys, "aria-roledescription")
+		}
+		if _, ok := values["role"]; !ok {
+			values["role"] = "article"
+			keys = append(keys, "role")
+		}
+		sort.Strings(keys)
+
+		var b strings.Builder
+		for _, key := range keys {
+			b.WriteString(" ")
+			b.WriteString(key)
+			b.WriteString(`="`)
+			b.WriteString(values[key])
+			b.WriteString(`"`)
+		}
+		return fmt.Sprintf("<body%s><div%s>", submatches[1], b.String())
+	})
+
+	normalized = mustacheAfterClosingPattern.ReplaceAllString(normalized, "$1$2")
+	normalized = mustacheBeforeOpeningPattern.ReplaceAllString(normalized, "$1$2")
+
+	return viewportPattern.ReplaceAllStringFunc(normalized, func(match string) string {
+		submatches := viewportPattern.FindStringSubmatch(match)
+		if len(submatches) != 4 {
+			return match
+		}
+		return submatches[1] + strings.ReplaceAll(submatches[2], ", ", ",") + submatches[3]
+	})
+}
@@ -0,0 +1,125 @@
+package htmldiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StyleDiff lists the differences between two inline style attributes.
+type StyleDiff struct {
+	Missing    map[string]string    // Property: expected value
+	Mismatched map[string][2]string // Property: [expected, actual]
+	Extra      map[string]string    // Property: actual value
+}
+
+// CompareStyles compares two style attribute values as sets of declarations,
+// ignoring order and whitespace. Later declarations of a property win.
+func CompareStyles(expected, actual string) StyleDiff {
+	expectedProps := parseStyle(expected)
+	actualProps := parseStyle(actual)
+
+	diff := StyleDiff{
+		Missing:    make(map[string]string),
+		Mismatched: make(map[string][2]string),
+		Extra:      make(map[string]string),
+	}
+	for prop, expectedValue := range expectedProps {
+		if actualValue, ok := actualProps[prop]; !ok {
+			diff.Missing[prop] = expectedValue
+		} else if actualValue != expectedValue {
+			diff.Mismatched[prop] = [2]string{expectedValue, actualValue}
+		}
+	}
+	for prop, actualValue := range actualProps {
+		if _, ok := expectedProps[prop]; !ok {
+			diff.Extra[prop] = actualValue
+		}
+	}
+	return diff
+}
+
+// IsEmpty reports whether the styles are equivalent.
+func (d StyleDiff) IsEmpty() bool {
+	return len(d.Missing) == 0 && len(d.Mismatched) == 0 && len(d.Extra) == 0
+}
+
+// String summarizes the difference, such as
+// "missing: color=red | wrong values: padding=0→10px".
+func (d StyleDiff) String() string {
+	if d.IsEmpty() {
+		return ""
+	}
+
+	var parts []string
+	if len(d.Missing) > 0 {
+		parts = append(parts, "missing: "+joinSorted(d.Missing, func(prop, value string) string {
+			return prop + "=" + value
+		}))
+	}
+	if len(d.Mismatched) > 0 {
+		values := make(map[string]string, len(d.Mismatched))
+		for prop, pair := range d.Mismatched {
+			values[prop] = fmt.Sprintf("%s→%s", pair[0], pair[1])
+		}
+		parts = append(parts, "wrong values: "+joinSorted(values, func(prop, value string) string {
+			return prop + "=" + value
+		}))
+	}
+	if len(d.Extra) > 0 {
+		parts = append(parts, "extra: "+joinSorted(d.Extra, func(prop, value string) string {
+			return prop + "=" + value
+		}))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// parseStyle parses "prop: value; prop2: value2" into a map. Declarations
+// without a value are ignored.
+func parseStyle(style string) map[string]string {
+	props := make(map[string]string)
+	for _, decl := range strings.Split(style, ";") {
+		prop, value, ok := strings.Cut(decl, ":")
+		if !ok {
+			continue
+		}
+		prop = strings.TrimSpace(prop)
+		value = strings.TrimSpace(value)
+		if prop != "" && value != "" {
+			props[prop] = value
+		}
+	}
+	return props
+}
+
+func joinSorted(m map[string]string, format func(key, value string) string) string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	items := make([]string, len(keys))
+	for i, key := range keys {
+		items[i] = format(key, m[key])
+	}
+	return strings.Join(items, ", ")
+}
+
+// cssEqual compares the content of two style tags. Rule order and whitespace
+// are ignored by comparing the sorted characters, which is coarse but does not
+// depend on a CSS parser. Fewer Firefox (.moz-text-html) rules than expected
+// always count as a difference.
+func cssEqual(expected, actual string) bool {
+	if strings.Count(actual, ".moz-text-html") < strings.Count(expected, ".moz-text-html") {
+		return false
+	}
+	return sortedCSSChars(expected) == sortedCSSChars(actual)
+}
+
+// sortedCSSChars removes whitespace from css and sorts its characters.
+func sortedCSSChars(css string) string {
+	runes := []rune(strings.Join(strings.Fields(css), ""))
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return string(runes)
+}
@@ -0,0 +1,77 @@
+package mjml
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWithIDGenerator(t *testing.T) {
+	input := `<mjml>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-carousel>
+          <mj-carousel-image src="https://example.com/1.png" />
+          <mj-carousel-image src="https://example.com/2.png" />
+        </mj-carousel>
+        <mj-navbar hamburger="hamburger">
+          <mj-navbar-link href="/a">A</mj-navbar-link>
+        </mj-navbar>
+        <mj-navbar hamburger="hamburger">
+          <mj-navbar-link href="/b">B</mj-navbar-link>
+        </mj-navbar>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	gen := func(component string, index int) string {
+		return fmt.Sprintf("%s-%d", strings.TrimPrefix(component, "mj-"), index)
+	}
+
+	first, err := Render(input, WithIDGenerator(gen))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	second, err := Render(input, WithIDGenerator(gen))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if first != second {
+		t.Error("expected byte-identical output across renders with the same generator")
+	}
+
+	for _, want := range []string{
+		`mj-carousel-carousel-0-radio`,
+		`id="navbar-0"`,
+		`id="navbar-1"`,
+	} {
+		if !strings.Contains(first, want) {
+			t.Errorf("expected %q in output", want)
+		}
+	}
+}
+
+func TestWithIDGeneratorTemplate(t *testing.T) {
+	input := `<mjml><mj-body><mj-section><mj-column>
+  <mj-navbar hamburger="hamburger"><mj-navbar-link href="/a">A</mj-navbar-link></mj-navbar>
+</mj-column></mj-section></mj-body></mjml>`
+
+	tmpl, err := Compile(input, WithIDGenerator(func(component string, index int) string {
+		return fmt.Sprintf("nav%d", index)
+	}))
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		html, err := tmpl.RenderString()
+		if err != nil {
+			t.Fatalf("RenderString() error = %v", err)
+		}
+		if !strings.Contains(html, `id="nav0"`) {
+			t.Errorf("render %d: expected the generator index to restart for each render", i)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package mjml
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestImageResponsiveAndMapAttributes verifies that srcset, sizes, usemap, name and
+// title reach the rendered image and link, and that query strings are kept intact.
+func TestImageResponsiveAndMapAttributes(t *testing.T) {
+	input := `<mjml>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-image src="https://example.com/a.png?w=300&h=100"
+          srcset="https://example.com/a.png?w=300 300w, https://example.com/a.png?w=600 600w"
+          sizes="(max-width: 480px) 100vw, 300px"
+          usemap="#links" title="Logo" name="logo"
+          href="https://example.com/?utm_source=mail&utm_medium=email" />
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`<a href="https://example.com/?utm_source=mail&utm_medium=email" target="_blank" name="logo" title="Logo">`,
+		`src="https://example.com/a.png?w=300&h=100"`,
+		`srcset="https://example.com/a.png?w=300 300w, https://example.com/a.png?w=600 600w"`,
+		`sizes="(max-width: 480px) 100vw, 300px"`,
+		`title="Logo"`,
+		`usemap="#links"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s in output", want)
+		}
+	}
+}
+
+// TestWithImageSizer verifies that an image sizer gives images with an auto
+// height a height attribute scaled to their rendered width, and leaves
+// explicit heights and unknown sizes alone.
+func TestWithImageSizer(t *testing.T) {
+	input := `<mjml><mj-body><mj-section><mj-column>
+  <mj-image src="https://example.com/wide.png" />
+  <mj-image src="https://example.com/wide.png" width="300px" />
+  <mj-image src="https://example.com/wide.png" height="90px" />
+  <mj-image src="https://example.com/unknown.png" />
+</mj-column></mj-section></mj-body></mjml>`
+
+	var calls []string
+	sizer := func(src string) (int, int, bool) {
+		calls = append(calls, src)
+		if src == "https://example.com/wide.png" {
+			return 1200, 401, true
+		}
+		return 0, 0, false
+	}
+	html, err := Render(input, WithImageSizer(sizer))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`height="184" src="https://example.com/wide.png" width="550"`,
+		`height="100" src="https://example.com/wide.png" width="300"`,
+		`height="90" src="https://example.com/wide.png" width="550"`,
+		`height="auto" src="https://example.com/unknown.png" width="550"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s in output", want)
+		}
+	}
+	if strings.Contains(html, "height:184px") {
+		t.Error("the CSS height should stay auto")
+	}
+	if len(calls) != 3 {
+		t.Errorf("sizer called for %v; images with an explicit height should not be sized", calls)
+	}
+}
+
+// TestImageResponsiveSrcset verifies the srcset and sizes generated by
+// WithResponsiveImages, the srcset-widths override and that images with their
+// own srcset are left alone.
+func TestImageResponsiveSrcset(t *testing.T) {
+	input := `<mjml>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-image src="https://cdn.example.com/hero.jpg" fluid-on-mobile="true" />
+        <mj-image src="https://cdn.example.com/logo.png" width="200px" srcset-widths="400, 200px, 400" />
+        <mj-image src="https://cdn.example.com/own.png" srcset="https://cdn.example.com/own.png 1x" />
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	resizer := func(src string, width int) string {
+		return src + "?w=" + strconv.Itoa(width)
+	}
+	// Strict validation fails on attributes it does not know
+	html, err := Render(input, WithResponsiveImages([]int{1200, 600, 0, 320}, resizer), WithValidationLevel(ValidationStrict))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`src="https://cdn.example.com/hero.jpg" srcset="https://cdn.example.com/hero.jpg?w=320 320w, https://cdn.example.com/hero.jpg?w=600 600w, https://cdn.example.com/hero.jpg?w=1200 1200w" sizes="(max-width: 479px) 100vw, 550px"`,
+		`srcset="https://cdn.example.com/logo.png?w=200 200w, https://cdn.example.com/logo.png?w=400 400w" sizes="200px"`,
+		`srcset="https://cdn.example.com/own.png 1x"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s in output", want)
+		}
+	}
+	if strings.Contains(html, "own.png?w=") {
+		t.Error("expected an explicit srcset to be kept")
+	}
+
+	plain, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if strings.Contains(plain, "?w=") {
+		t.Error("expected no generated srcset without WithResponsiveImages")
+	}
+}
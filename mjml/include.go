@@ -0,0 +1,315 @@
+package mjml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/parser"
+)
+
+const includeTag = "mj-include"
+
+// WithIncludes expands mj-include elements with files read from fsys, such as
+// os.DirFS of the template directory:
+//
+//	<mj-include path="./partials/header.mjml" />
+//
+// Paths are relative to the including file, or to the root of fsys for the
+// rendered document, and may not leave fsys; ".mjml" is added to MJML paths
+// without an extension. An included MJML document has its mj-body content
+// inserted in place of the mj-include and its mj-head content appended to the
+// document head. A file without an mjml root is inserted as is, as mj-body
+// content, or as mj-head content when the mj-include is in mj-head.
+// type="css" adds the file to the head as an mj-style, inlined with
+// css-inline="inline", and type="html" inserts it as an mj-raw.
+//
+// As in MJML, the mj-attributes of every file apply to the whole document.
+// Blocks apply in head order, so those of included files, appended in the
+// order the includes appear, override the including document's for the same
+// attribute; WithIsolatedIncludes keeps them apart instead. A file that cannot
+// be read or includes itself fails the render. Without WithIncludes,
+// mj-include elements are ignored.
+func WithIncludes(fsys fs.FS) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.Includes = fsys
+	}
+}
+
+// WithIsolatedIncludes keeps the mj-attributes and mj-class definitions of each
+// file included into mj-body from applying outside it, and those of the
+// including document from applying inside it, so shared partials render the
+// same in every template. Defaults from WithDefaultAttributes still apply
+// everywhere, and the other head elements of included files, such as mj-font
+// and mj-style, still go to the document head. Includes in mj-head are part of
+// the document head either way.
+func WithIsolatedIncludes() RenderOption {
+	return func(opts *RenderOpts) {
+		opts.IsolateIncludes = true
+	}
+}
+
+// includeExpander expands the mj-include elements of one document.
+type includeExpander struct {
+	fsys      fs.FS
+	isolate   bool
+	parseOpts parser.ParseOptions
+	open      []string // Files being expanded, to detect include cycles
+}
+
+// expandIncludes returns node with its mj-include elements replaced by the
+// content of the included files. Like expandConditionals, it copies the nodes
+// it changes instead of modifying them.
+func expandIncludes(node *MJMLNode, opts *RenderOpts) (*MJMLNode, error) {
+	if node == nil || opts.Includes == nil || !containsTag(node, includeTag) {
+		return node, nil
+	}
+	e := &includeExpander{fsys: opts.Includes, isolate: opts.IsolateIncludes, parseOpts: parseOptions(opts)}
+	expanded, head, err := e.expand(node, ".", node.GetTagName() == "mj-head")
+	if err != nil {
+		return nil, err
+	}
+	return appendToHead(expanded, head), nil
+}
+
+// expand returns node with the includes in it expanded, along with the head
+// elements of included files that go to the document head. dir is the
+// directory of the file node comes from.
+func (e *includeExpander) expand(node *MJMLNode, dir string, inHead bool) (*MJMLNode, []*MJMLNode, error) {
+	if !containsTag(node, includeTag) {
+		return node, nil, nil
+	}
+
+	var head []*MJMLNode
+	replaced := make(map[*MJMLNode][]*MJMLNode, len(node.Children))
+	for _, child := range node.Children {
+		var nodes, childHead []*MJMLNode
+		var err error
+		if child.GetTagName() == includeTag {
+			nodes, childHead, err = e.include(child, dir, inHead)
+		} else {
+			var expanded *MJMLNode
+			expanded, childHead, err = e.expand(child, dir, inHead || child.GetTagName() == "mj-head")
+			nodes = []*MJMLNode{expanded}
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		replaced[child] = nodes
+		head = append(head, childHead...)
+	}
+
+	expanded := *node
+	expanded.Children = make([]*MJMLNode, 0, len(node.Children))
+	for _, child := range node.Children {
+		expanded.Children = append(expanded.Children, replaced[child]...)
+	}
+	expanded.MixedContent = make([]parser.MixedContentPart, 0, len(node.MixedContent))
+	for _, part := range node.MixedContent {
+		if part.Node == nil {
+			expanded.MixedContent = append(expanded.MixedContent, part)
+			continue
+		}
+		for _, n := range replaced[part.Node] {
+			expanded.MixedContent = append(expanded.MixedContent, parser.MixedContentPart{Node: n})
+		}
+	}
+	return &expanded, head, nil
+}
+
+// include returns the nodes an mj-include element is replaced by and the head
+// elements the included file adds to the document head.
+func (e *includeExpander) include(node *MJMLNode, dir string, inHead bool) ([]*MJMLNode, []*MJMLNode, error) {
+	includePath := node.GetAttribute("path")
+	if includePath == "" {
+		return nil, nil, fmt.Errorf("mj-include on line %d has no path", node.LineNumber)
+	}
+	kind := node.GetAttribute("type")
+	name, err := resolveIncludePath(dir, includePath, kind)
+	if err != nil {
+		return nil, nil, fmt.Errorf("mj-include on line %d: %w", node.LineNumber, err)
+	}
+	if slices.Contains(e.open, name) {
+		return nil, nil, fmt.Errorf("mj-include on line %d: %s includes itself", node.LineNumber, name)
+	}
+	data, err := fs.ReadFile(e.fsys, name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("mj-include on line %d: %w", node.LineNumber, err)
+	}
+
+	switch kind {
+	case "css":
+		style := &MJMLNode{XMLName: xml.Name{Local: "mj-style"}, Text: string(data), LineNumber: node.LineNumber}
+		if node.GetAttribute("css-inline") == "inline" {
+			style.Attrs = []xml.Attr{{Name: xml.Name{Local: "inline"}, Value: "inline"}}
+		}
+		if inHead {
+			return []*MJMLNode{style}, nil, nil
+		}
+		return nil, []*MJMLNode{style}, nil
+	case "html":
+		raw := &MJMLNode{XMLName: xml.Name{Local: "mj-raw"}, Text: string(data), LineNumber: node.LineNumber}
+		return []*MJMLNode{raw}, nil, nil
+	}
+
+	content := string(data)
+	if !hasMJMLRoot(content) {
+		wrapper := "mj-body"
+		if inHead {
+			wrapper = "mj-head"
+		}
+		content = "<mjml><" + wrapper + ">" + content + "</" + wrapper + "></mjml>"
+	}
+	root, err := parser.ParseMJMLWithOptions(content, e.parseOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("mj-include %s: %w", name, err)
+	}
+
+	e.open = append(e.open, name)
+	root, nested, err := e.expand(root, path.Dir(name), false)
+	e.open = e.open[:len(e.open)-1]
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var headChildren []*MJMLNode
+	headNode := root.FindFirstChild("mj-head")
+	if headNode != nil {
+		headChildren = headNode.Children
+	}
+	if inHead {
+		return append(slices.Clone(headChildren), nested...), nil, nil
+	}
+
+	var body []*MJMLNode
+	if bodyNode := root.FindFirstChild("mj-body"); bodyNode != nil {
+		body = bodyNode.Children
+	}
+	if !e.isolate {
+		return body, append(slices.Clone(headChildren), nested...), nil
+	}
+
+	// The included file's mj-attributes stay with its content, which was
+	// parsed for this include alone and can be marked in place.
+	scope := headNode
+	if scope == nil {
+		scope = &MJMLNode{XMLName: xml.Name{Local: "mj-head"}}
+	}
+	for _, n := range body {
+		setAttributeScope(n, scope)
+	}
+	var head []*MJMLNode
+	for _, child := range headChildren {
+		if child.GetTagName() != "mj-attributes" {
+			head = append(head, child)
+		}
+	}
+	return body, append(head, nested...), nil
+}
+
+// resolveIncludePath returns the name in the include file system of the file
+// an mj-include path in dir refers to.
+func resolveIncludePath(dir, includePath, kind string) (string, error) {
+	name := includePath
+	if kind != "css" && kind != "html" && path.Ext(name) == "" {
+		name += ".mjml"
+	}
+	if strings.HasPrefix(name, "/") {
+		name = path.Clean(strings.TrimLeft(name, "/"))
+	} else {
+		name = path.Join(dir, name)
+	}
+	if !fs.ValidPath(name) {
+		return "", fmt.Errorf("path %q is outside the include directory", includePath)
+	}
+	return name, nil
+}
+
+// hasMJMLRoot reports whether content is a full MJML document rather than a
+// fragment, skipping an XML declaration and comments before the root.
+func hasMJMLRoot(content string) bool {
+	content = strings.TrimSpace(content)
+	for {
+		switch {
+		case strings.HasPrefix(content, "<?"):
+			end := strings.Index(content, "?>")
+			if end < 0 {
+				return false
+			}
+			content = strings.TrimSpace(content[end+2:])
+		case strings.HasPrefix(content, "<!--"):
+			end := strings.Index(content, "-->")
+			if end < 0 {
+				return false
+			}
+			content = strings.TrimSpace(content[end+3:])
+		default:
+			rest, ok := strings.CutPrefix(content, "<mjml")
+			return ok && (rest == "" || strings.IndexByte(">/ \t\r\n", rest[0]) >= 0)
+		}
+	}
+}
+
+// setAttributeScope marks node and its descendants as content of the include
+// whose head is scope. Content of nested isolated includes keeps its own scope.
+func setAttributeScope(node *MJMLNode, scope *MJMLNode) {
+	if node.AttributeScope != nil {
+		return
+	}
+	node.AttributeScope = scope
+	for _, child := range node.Children {
+		setAttributeScope(child, scope)
+	}
+}
+
+// appendToHead returns root with elements appended to its mj-head, which is
+// created when the document has none.
+func appendToHead(root *MJMLNode, elements []*MJMLNode) *MJMLNode {
+	if len(elements) == 0 {
+		return root
+	}
+	index := slices.IndexFunc(root.Children, func(child *MJMLNode) bool { return child.GetTagName() == "mj-head" })
+
+	head := &MJMLNode{XMLName: xml.Name{Local: "mj-head"}}
+	if index >= 0 {
+		copied := *root.Children[index]
+		head = &copied
+	}
+	head.Children = append(slices.Clone(head.Children), elements...)
+	parts := slices.Clone(head.MixedContent)
+	for _, element := range elements {
+		parts = append(parts, parser.MixedContentPart{Node: element})
+	}
+	head.MixedContent = parts
+
+	updated := *root
+	updated.Children = slices.Clone(root.Children)
+	updated.MixedContent = slices.Clone(root.MixedContent)
+	if index >= 0 {
+		old := root.Children[index]
+		updated.Children[index] = head
+		for i := range updated.MixedContent {
+			if updated.MixedContent[i].Node == old {
+				updated.MixedContent[i].Node = head
+			}
+		}
+		return &updated
+	}
+	updated.Children = slices.Insert(updated.Children, 0, head)
+	updated.MixedContent = slices.Insert(updated.MixedContent, 0, parser.MixedContentPart{Node: head})
+	return &updated
+}
+
+// containsTag reports whether an element named tag is among the descendants
+// of node.
+func containsTag(node *MJMLNode, tag string) bool {
+	for _, child := range node.Children {
+		if child.GetTagName() == tag || containsTag(child, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithIncludes(t *testing.T) {
+	files := fstest.MapFS{
+		"partials/header.mjml": {Data: []byte(`<mjml>
+  <mj-head><mj-style>.header { color: red; }</mj-style></mj-head>
+  <mj-body>
+    <mj-section><mj-column><mj-text>Header</mj-text></mj-column></mj-section>
+    <mj-include path="./footer" />
+  </mj-body>
+</mjml>`)},
+		"partials/footer.mjml": {Data: []byte(`<mj-section><mj-column><mj-text>Footer</mj-text></mj-column></mj-section>`)},
+		"styles.css":           {Data: []byte(`.inlined { color: blue; }`)},
+		"banner.html":          {Data: []byte(`<div class="banner">Banner</div>`)},
+	}
+	input := `<mjml>
+  <mj-head><mj-include path="styles.css" type="css" /></mj-head>
+  <mj-body>
+    <mj-include path="partials/header.mjml" />
+    <mj-include path="banner.html" type="html" />
+  </mj-body>
+</mjml>`
+
+	html, err := Render(input, WithIncludes(files))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{">Header<", ">Footer<", `<div class="banner">Banner</div>`, ".header { color: red; }", ".inlined { color: blue; }"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("output is missing %q", want)
+		}
+	}
+	if strings.Index(html, ">Header<") > strings.Index(html, ">Footer<") || strings.Index(html, ">Footer<") > strings.Index(html, "Banner") {
+		t.Error("included content is out of order")
+	}
+
+	ignored, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() without WithIncludes error = %v", err)
+	}
+	if strings.Contains(ignored, ">Header<") {
+		t.Error("mj-include was expanded without WithIncludes")
+	}
+}
+
+func TestWithIncludesErrors(t *testing.T) {
+	files := fstest.MapFS{
+		"loop.mjml": {Data: []byte(`<mj-include path="loop.mjml" />`)},
+	}
+	tests := map[string]string{
+		"missing file": `<mjml><mj-body><mj-include path="missing.mjml" /></mj-body></mjml>`,
+		"cycle":        `<mjml><mj-body><mj-include path="loop.mjml" /></mj-body></mjml>`,
+		"outside":      `<mjml><mj-body><mj-include path="../secret.mjml" /></mj-body></mjml>`,
+		"no path":      `<mjml><mj-body><mj-include /></mj-body></mjml>`,
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Render(input, WithIncludes(files)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+const includeAttributesPartial = `<mjml>
+  <mj-head>
+    <mj-attributes>
+      <mj-button background-color="#00ff00" />
+      <mj-class name="partial" color="#0000ff" />
+    </mj-attributes>
+  </mj-head>
+  <mj-body>
+    <mj-section><mj-column>
+      <mj-text>Partial text</mj-text>
+      <mj-button href="#">Partial button</mj-button>
+    </mj-column></mj-section>
+  </mj-body>
+</mjml>`
+
+const includeAttributesDocument = `<mjml>
+  <mj-head>
+    <mj-attributes>
+      <mj-text color="#ff0000" />
+      <mj-button background-color="#ff00ff" />
+    </mj-attributes>
+  </mj-head>
+  <mj-body>
+    <mj-section><mj-column>
+      <mj-button href="#">Parent button</mj-button>
+      <mj-text mj-class="partial">Parent text</mj-text>
+    </mj-column></mj-section>
+    <mj-include path="partial.mjml" />
+  </mj-body>
+</mjml>`
+
+func TestIncludeAttributeCascade(t *testing.T) {
+	files := fstest.MapFS{"partial.mjml": {Data: []byte(includeAttributesPartial)}}
+	html, err := Render(includeAttributesDocument, WithIncludes(files))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	partialText := elementStyle(t, html, "Partial text")
+	if !strings.Contains(partialText, "color:#ff0000") {
+		t.Errorf("parent mj-attributes do not reach the partial: %s", partialText)
+	}
+	// The partial's later mj-attributes block wins for the whole document.
+	if strings.Contains(html, "#ff00ff") || strings.Count(html, `bgcolor="#00ff00"`) != 2 {
+		t.Error("partial mj-button background does not override the parent's everywhere")
+	}
+	if parentText := elementStyle(t, html, "Parent text"); !strings.Contains(parentText, "color:#0000ff") {
+		t.Errorf("mj-class defined in the partial is not usable by the parent: %s", parentText)
+	}
+}
+
+func TestNestedIncludeAttributeOrder(t *testing.T) {
+	files := fstest.MapFS{
+		"a.mjml": {Data: []byte(`<mjml>
+  <mj-head><mj-attributes><mj-text color="#aaaaaa" font-size="20px" /></mj-attributes></mj-head>
+  <mj-body><mj-include path="b.mjml" /></mj-body>
+</mjml>`)},
+		"b.mjml": {Data: []byte(`<mjml>
+  <mj-head><mj-attributes><mj-text color="#bbbbbb" /></mj-attributes></mj-head>
+  <mj-body><mj-section><mj-column><mj-text>Nested</mj-text></mj-column></mj-section></mj-body>
+</mjml>`)},
+	}
+	input := `<mjml><mj-body><mj-include path="a.mjml" /></mj-body></mjml>`
+
+	html, err := Render(input, WithIncludes(files))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if style := elementStyle(t, html, "Nested"); !strings.Contains(style, "color:#bbbbbb") || !strings.Contains(style, "font-size:20px") {
+		t.Errorf("attributes of the innermost include should apply last: %s", style)
+	}
+
+	html, err = Render(input, WithIncludes(files), WithIsolatedIncludes())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if style := elementStyle(t, html, "Nested"); !strings.Contains(style, "color:#bbbbbb") || strings.Contains(style, "font-size:20px") {
+		t.Errorf("nested isolated include should use only its own attributes: %s", style)
+	}
+}
+
+func TestWithIsolatedIncludes(t *testing.T) {
+	files := fstest.MapFS{"partial.mjml": {Data: []byte(includeAttributesPartial)}}
+	html, err := Render(includeAttributesDocument, WithIncludes(files), WithIsolatedIncludes(),
+		WithDefaultAttributes(map[string]map[string]string{"mj-text": {"font-size": "15px"}}))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	partialText := elementStyle(t, html, "Partial text")
+	if strings.Contains(partialText, "color:#ff0000") {
+		t.Errorf("parent mj-attributes reach into an isolated partial: %s", partialText)
+	}
+	if !strings.Contains(partialText, "font-size:15px") {
+		t.Errorf("caller defaults do not apply inside an isolated partial: %s", partialText)
+	}
+	if !strings.Contains(elementStyle(t, html, "Parent text"), "color:#ff0000") {
+		t.Error("parent mj-attributes no longer apply to the parent")
+	}
+	if strings.Count(html, `bgcolor="#ff00ff"`) != 1 || strings.Count(html, `bgcolor="#00ff00"`) != 1 {
+		t.Error("each mj-button should keep the background of its own file")
+	}
+	if strings.Contains(elementStyle(t, html, "Parent text"), "#0000ff") {
+		t.Error("mj-class of an isolated partial is usable by the parent")
+	}
+}
+
+// elementStyle returns the style attribute of the div that directly holds text.
+func elementStyle(t *testing.T, html, text string) string {
+	t.Helper()
+	end := strings.Index(html, ">"+text+"<")
+	if end < 0 {
+		t.Fatalf("output does not contain %q", text)
+	}
+	start := strings.LastIndex(html[:end], "<div")
+	return html[start:end]
+}
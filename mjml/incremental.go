@@ -0,0 +1,364 @@
+package mjml
+
+import (
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/preslavrachev/gomjml/mjml/options"
+	"github.com/preslavrachev/gomjml/parser"
+)
+
+// Patch replaces one subtree of an MJML AST.
+type Patch struct {
+	// Path holds the child indexes leading from the mjml root to the replaced
+	// node: []int{1, 0} is the first child of the root's second child. An
+	// empty Path replaces the whole document.
+	Path []int
+	// Node is the new subtree.
+	Node *MJMLNode
+}
+
+// Apply returns a copy of ast with the patch applied. Only the nodes along
+// Path are copied, so ast itself, which may be shared through the AST cache,
+// is left unchanged.
+func (p Patch) Apply(ast *MJMLNode) (*MJMLNode, error) {
+	if p.Node == nil {
+		return nil, errors.New("patch has no replacement node")
+	}
+	node := ast
+	for depth, index := range p.Path {
+		if node == nil || index < 0 || index >= len(node.Children) {
+			return nil, fmt.Errorf("patch path %v: no node at %v", p.Path, p.Path[:depth+1])
+		}
+		node = node.Children[index]
+	}
+	return replaceNode(ast, p.Path, p.Node), nil
+}
+
+// replaceNode copies node with the descendant at path replaced by replacement.
+func replaceNode(node *MJMLNode, path []int, replacement *MJMLNode) *MJMLNode {
+	if len(path) == 0 {
+		return replacement
+	}
+	old := node.Children[path[0]]
+	child := replaceNode(old, path[1:], replacement)
+
+	copied := *node
+	copied.Children = append([]*MJMLNode(nil), node.Children...)
+	copied.Children[path[0]] = child
+	if len(node.MixedContent) > 0 {
+		copied.MixedContent = append([]parser.MixedContentPart(nil), node.MixedContent...)
+		for i := range copied.MixedContent {
+			if copied.MixedContent[i].Node == old {
+				copied.MixedContent[i].Node = child
+			}
+		}
+	}
+	return &copied
+}
+
+// IncrementalRenderer renders successive versions of a document, such as the
+// edits of a live preview, reusing the markup of top-level body blocks
+// (mj-section, mj-wrapper, mj-hero, mj-raw) that did not change since the
+// previous render. A block is reused when its subtree, its position, the
+// mj-head, the mjml and mj-body attributes and the Outlook chaining state
+// around it all match; the head is always regenerated. Profiler callbacks
+// only cover re-rendered blocks.
+//
+// An IncrementalRenderer is safe for concurrent use; renders are serialized.
+type IncrementalRenderer struct {
+	mu     sync.Mutex
+	opts   []RenderOption
+	blocks map[uint64]*renderedBlock
+}
+
+// renderedBlock is the markup of a top-level body block together with the
+// render state changes it caused.
+type renderedBlock struct {
+	markup               string
+	fonts                []string
+	attachments          []Attachment   // Inlined images the markup references
+	ids                  map[string]int // IDs handed out per component
+	pendingMSOClose      bool
+	requireEmptyStyleTag bool
+}
+
+// NewIncrementalRenderer creates an IncrementalRenderer that renders with opts.
+func NewIncrementalRenderer(opts ...RenderOption) *IncrementalRenderer {
+	return &IncrementalRenderer{opts: opts}
+}
+
+// RenderPatch applies patch to old and renders the resulting document. The
+// patched AST is returned in the result for the next edit.
+func (r *IncrementalRenderer) RenderPatch(old *MJMLNode, patch Patch) (*RenderResult, error) {
+	ast, err := patch.Apply(old)
+	if err != nil {
+		return nil, err
+	}
+	return r.Render(ast)
+}
+
+// Render renders ast, reusing the blocks it shares with the previous render.
+// Blocks not used by this render are dropped from the renderer.
+func (r *IncrementalRenderer) Render(ast *MJMLNode) (*RenderResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	renderOpts := &RenderOpts{
+		FontTracker: options.NewFontTracker(),
+	}
+	for _, opt := range r.opts {
+		opt(renderOpts)
+	}
+
+	validation := collectValidation(renderOpts)
+
+	ast, err := expandExtensions(ast, renderOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	ast = applyGlobalAttributes(ast, renderOpts)
+
+	component, err := CreateComponent(ast, renderOpts)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.strictErr(); err != nil {
+		return nil, err
+	}
+
+	root, ok := component.(*MJMLComponent)
+	if ok && root.Body == nil {
+		// Mirrors RenderWithAST for documents without an mj-body.
+		return &RenderResult{HTML: "MJML badly formatted", AST: ast, OutputHash: fingerprint("MJML badly formatted")}, nil
+	}
+
+	if renderOpts.OutputFormat == FormatAMP {
+		if ampErr := validateAMPComponents(component); ampErr != nil {
+			return nil, *ampErr
+		}
+	}
+
+	blocks := make(map[uint64]*renderedBlock, len(r.blocks))
+	if ok {
+		renderOpts.BlockRenderer = r.blockRenderer(root, renderOpts, blocks)
+	}
+
+	var html strings.Builder
+	if err := component.Render(&html); err != nil {
+		return nil, err
+	}
+	r.blocks = blocks
+
+	htmlOutput, a11yErr := checkAccessibility(component, html.String(), renderOpts)
+	if a11yErr != nil {
+		validation.add(a11yErr)
+	}
+	if heroErr := checkHeroOverflow(component, renderOpts); heroErr != nil {
+		validation.add(heroErr)
+	}
+	htmlOutput, sourceMap, err := finishRender(htmlOutput, renderOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	htmlOutput = normalizeGroupColumnClassOrder(htmlOutput)
+	return &RenderResult{
+		HTML:        htmlOutput,
+		AST:         ast,
+		SourceMap:   sourceMap,
+		Attachments: attachments(renderOpts),
+		OutputHash:  fingerprint(htmlOutput),
+		BodyWidth:   bodyWidth(component),
+	}, validation.result()
+}
+
+// blockRenderer returns the BlockRenderer that looks up the blocks of
+// root's body in the previous render and records them in blocks.
+func (r *IncrementalRenderer) blockRenderer(root *MJMLComponent, opts *RenderOpts, blocks map[uint64]*renderedBlock) options.BlockRenderer {
+	context := documentContextHash(root)
+
+	return func(index int, w io.StringWriter, render func(io.StringWriter) error) error {
+		child, ok := root.Body.Children[index].(interface{ GetNode() *MJMLNode })
+		if !ok {
+			return render(w)
+		}
+		key := blockHash(context, index, child.GetNode(), opts)
+
+		block, ok := r.blocks[key]
+		if !ok {
+			var err error
+			if block, err = renderBlock(opts, render); err != nil {
+				return err
+			}
+		}
+		blocks[key] = block
+
+		for _, font := range block.fonts {
+			opts.FontTracker.AddFont(font)
+		}
+		for _, attachment := range block.attachments {
+			opts.ImageInliner.Add(attachment)
+		}
+		if len(block.ids) > 0 && opts.IDCounters == nil {
+			opts.IDCounters = make(map[string]int, len(block.ids))
+		}
+		for component, n := range block.ids {
+			opts.IDCounters[component] += n
+		}
+		opts.PendingMSOSectionClose = block.pendingMSOClose
+		opts.RequireEmptyStyleTag = opts.RequireEmptyStyleTag || block.requireEmptyStyleTag
+		_, err := w.WriteString(block.markup)
+		return err
+	}
+}
+
+// renderBlock renders a block in isolation from the render state that
+// accumulated before it, so the state changes it causes can be replayed when
+// its markup is reused. The caller applies those changes.
+func renderBlock(opts *RenderOpts, render func(io.StringWriter) error) (*renderedBlock, error) {
+	requireEmptyStyleTag := opts.RequireEmptyStyleTag
+	idsBefore := make(map[string]int, len(opts.IDCounters))
+	for component, n := range opts.IDCounters {
+		idsBefore[component] = n
+	}
+
+	opts.RequireEmptyStyleTag = false
+	defer func() { opts.RequireEmptyStyleTag = requireEmptyStyleTag }()
+
+	stopRecording := opts.FontTracker.Record()
+	stopRecordingImages := func() []Attachment { return nil }
+	if opts.ImageInliner != nil {
+		stopRecordingImages = opts.ImageInliner.Record()
+	}
+	var markup strings.Builder
+	err := render(&markup)
+	fonts := stopRecording()
+	attachments := stopRecordingImages()
+	if err != nil {
+		return nil, err
+	}
+
+	block := &renderedBlock{
+		markup:               markup.String(),
+		fonts:                fonts,
+		attachments:          attachments,
+		pendingMSOClose:      opts.PendingMSOSectionClose,
+		requireEmptyStyleTag: opts.RequireEmptyStyleTag,
+	}
+	for component, n := range opts.IDCounters {
+		if delta := n - idsBefore[component]; delta > 0 {
+			if block.ids == nil {
+				block.ids = make(map[string]int)
+			}
+			block.ids[component] = delta
+		}
+	}
+	// The caller replays the ID changes, so undo them here.
+	for component := range block.ids {
+		opts.IDCounters[component] = idsBefore[component]
+	}
+	return block, nil
+}
+
+// documentContextHash hashes the parts of a document that every body block
+// depends on: the root and mj-body attributes, the mj-head and the body's
+// children (their number and which of them are mj-raw).
+func documentContextHash(root *MJMLComponent) uint64 {
+	h := newNodeHash()
+	writeNodeAttrs(h, root.Node)
+	if head := root.Node.FindFirstChild("mj-head"); head != nil {
+		writeNode(h, head)
+	}
+	if body := root.Body.Node; body != nil {
+		writeNodeAttrs(h, body)
+		for _, child := range body.Children {
+			h.WriteString(child.GetTagName())
+			h.WriteByte(0)
+		}
+	}
+	return h.Sum64()
+}
+
+// blockHash identifies the markup of the body block at index: the block's
+// subtree together with the render state it is rendered in.
+func blockHash(context uint64, index int, node *MJMLNode, opts *RenderOpts) uint64 {
+	h := newNodeHash()
+	h.WriteString(strconv.FormatUint(context, 16))
+	h.WriteString(strconv.Itoa(index))
+	h.WriteByte(0)
+	h.WriteString(strconv.FormatBool(opts.PendingMSOSectionClose))
+	h.WriteString(strconv.Itoa(opts.RemainingBodySections))
+	h.WriteByte(0)
+
+	ids := make([]string, 0, len(opts.IDCounters))
+	for component, n := range opts.IDCounters {
+		ids = append(ids, component+"="+strconv.Itoa(n))
+	}
+	sort.Strings(ids)
+	h.WriteString(strings.Join(ids, ","))
+	h.WriteByte(0)
+
+	writeNode(h, node)
+	return h.Sum64()
+}
+
+func newNodeHash() *maphash.Hash {
+	templateHashSeedOnce.Do(func() {
+		hashSeed = maphash.MakeSeed()
+	})
+	h := &maphash.Hash{}
+	h.SetSeed(hashSeed)
+	return h
+}
+
+// writeNode writes a node and its subtree, including source line numbers
+// since they end up in source maps, to h.
+func writeNode(h *maphash.Hash, node *MJMLNode) {
+	writeNodeAttrs(h, node)
+	h.WriteString(strconv.Itoa(node.LineNumber))
+	h.WriteByte(0)
+	h.WriteString(node.Text)
+	h.WriteByte(0)
+	h.WriteString(strconv.FormatBool(node.Verbatim))
+
+	if len(node.MixedContent) > 0 {
+		for _, part := range node.MixedContent {
+			if part.Node != nil {
+				h.WriteByte(1)
+				writeNode(h, part.Node)
+			} else {
+				h.WriteByte(2)
+				h.WriteString(part.Text)
+			}
+		}
+	} else {
+		for _, child := range node.Children {
+			h.WriteByte(1)
+			writeNode(h, child)
+		}
+	}
+	h.WriteByte(3)
+}
+
+func writeNodeAttrs(h *maphash.Hash, node *MJMLNode) {
+	h.WriteString(node.XMLName.Space)
+	h.WriteByte(':')
+	h.WriteString(node.XMLName.Local)
+	for _, attr := range node.Attrs {
+		h.WriteByte(0)
+		h.WriteString(attr.Name.Space)
+		h.WriteByte(':')
+		h.WriteString(attr.Name.Local)
+		h.WriteByte('=')
+		h.WriteString(attr.Value)
+	}
+	h.WriteByte(0)
+}
@@ -0,0 +1,101 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const incrementalDocument = `<mjml>
+  <mj-head><mj-title>Preview</mj-title></mj-head>
+  <mj-body>
+    <mj-section><mj-column><mj-text font-family="Roboto, sans-serif">First</mj-text></mj-column></mj-section>
+    <mj-wrapper>
+      <mj-section><mj-column><mj-text font-family="Roboto, sans-serif">Second</mj-text></mj-column></mj-section>
+    </mj-wrapper>
+    <mj-section><mj-column><mj-button href="#">Third</mj-button></mj-column></mj-section>
+  </mj-body>
+</mjml>`
+
+func TestIncrementalRendererMatchesRender(t *testing.T) {
+	sectionRenders := 0
+	r := NewIncrementalRenderer(WithProfiler(func(component string, _ int, _ time.Duration, _ int) {
+		if component == "mj-section" || component == "mj-wrapper" {
+			sectionRenders++
+		}
+	}))
+
+	ast, err := ParseMJML(incrementalDocument)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	want, _ := Render(incrementalDocument)
+	if result.HTML != want {
+		t.Fatal("initial render differs from Render()")
+	}
+
+	// Replace the text of the first section; its font is still used by the
+	// reused wrapper, so the font import must survive.
+	edited := strings.Replace(incrementalDocument, `<mj-text font-family="Roboto, sans-serif">First</mj-text>`, `<mj-text>Edited</mj-text>`, 1)
+	replacement, err := ParseMJML(`<mj-text>Edited</mj-text>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sectionRenders = 0
+	patched, err := r.RenderPatch(result.AST, Patch{Path: []int{1, 0, 0, 0}, Node: replacement})
+	if err != nil {
+		t.Fatalf("RenderPatch() error = %v", err)
+	}
+	want, _ = Render(edited)
+	if patched.HTML != want {
+		t.Fatalf("patched render differs from Render() of the edited document:\n got: %s\nwant: %s", patched.HTML, want)
+	}
+	if sectionRenders != 1 {
+		t.Errorf("rendered %d top-level blocks, want only the patched one", sectionRenders)
+	}
+	if !strings.Contains(result.AST.Children[1].Children[0].Children[0].Children[0].Text, "First") {
+		t.Error("RenderPatch modified the old AST")
+	}
+}
+
+func TestIncrementalRendererHeadChangeRerendersBlocks(t *testing.T) {
+	r := NewIncrementalRenderer()
+	ast, err := ParseMJML(incrementalDocument)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := r.Render(ast)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	head, err := ParseMJML(`<mj-head><mj-attributes><mj-text color="#ff0000" /></mj-attributes></mj-head>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patched, err := r.RenderPatch(result.AST, Patch{Path: []int{0}, Node: head})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(patched.HTML, "color:#ff0000") {
+		t.Error("blocks were reused although mj-attributes changed")
+	}
+}
+
+func TestPatchApplyInvalidPath(t *testing.T) {
+	ast, err := ParseMJML(incrementalDocument)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (Patch{Path: []int{1, 7}, Node: ast}).Apply(ast); err == nil {
+		t.Error("expected an error for a path past the last child")
+	}
+	if _, err := (Patch{Path: []int{0}}).Apply(ast); err == nil {
+		t.Error("expected an error for a patch without a node")
+	}
+}
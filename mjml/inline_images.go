@@ -0,0 +1,265 @@
+package mjml
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/preslavrachev/gomjml/mjml/options"
+)
+
+// InlineImageMode is an alias for convenience
+type InlineImageMode = options.InlineImageMode
+
+// Inline image modes
+const (
+	InlineImagesOff     = options.InlineImagesOff
+	InlineImagesCID     = options.InlineImagesCID
+	InlineImagesDataURI = options.InlineImagesDataURI
+)
+
+// AssetLoader is an alias for convenience
+type AssetLoader = options.AssetLoader
+
+// Attachment is an alias for convenience
+type Attachment = options.Attachment
+
+// WithInlineImages embeds the images of mj-image, mj-social-element and
+// mj-carousel in the message, so it needs no image hosting. loader returns the
+// content of each source; returning nil data keeps a source as it is, for
+// images that should stay remote. When the loader returns no content type, it
+// is detected from the data for PNG, JPEG, GIF, WebP, AVIF, BMP, ICO and SVG
+// images.
+//
+// InlineImagesCID replaces sources with cid: references. The images are
+// returned in RenderResult.Attachments by RenderWithAST, to be attached to the
+// message as related MIME parts with the given Content-IDs.
+// InlineImagesDataURI replaces sources with base64 data URIs, which most
+// webmail clients block; prefer CID unless the output is only viewed in a
+// browser. A source the loader fails on fails the render.
+func WithInlineImages(mode InlineImageMode, loader AssetLoader) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.ImageInliner = nil
+		if mode != InlineImagesOff && loader != nil {
+			opts.ImageInliner = newImageInliner(mode, loader)
+		}
+	}
+}
+
+// attachments returns the attachments referenced from the rendered document,
+// or nil when images are not inlined as attachments.
+func attachments(opts *RenderOpts) []Attachment {
+	if opts.ImageInliner == nil {
+		return nil
+	}
+	if attachments := opts.ImageInliner.Attachments(); len(attachments) > 0 {
+		return attachments
+	}
+	return nil
+}
+
+// imageInliner is the options.ImageInliner of WithInlineImages.
+type imageInliner struct {
+	mu          sync.Mutex
+	mode        InlineImageMode
+	loader      AssetLoader
+	inlined     map[string]string // Rewritten source per original source
+	attachments []Attachment      // Attachments in first-use order
+	contentIDs  map[string]bool   // Content-IDs of attachments
+
+	recording []Attachment // Attachments used since Record, nil when not recording
+	recorded  map[string]bool
+}
+
+func newImageInliner(mode InlineImageMode, loader AssetLoader) *imageInliner {
+	return &imageInliner{
+		mode:       mode,
+		loader:     loader,
+		inlined:    make(map[string]string),
+		contentIDs: make(map[string]bool),
+	}
+}
+
+// Inline returns the source that replaces src: a cid: reference or a data
+// URI, depending on the mode. Sources that are already cid: references or
+// data URIs are returned unchanged, and every source is loaded once.
+func (in *imageInliner) Inline(src string) (string, error) {
+	if in.mode == InlineImagesOff || in.loader == nil || src == "" || hasSchemeFold(src, "cid:") || hasSchemeFold(src, "data:") {
+		return src, nil
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if rewritten, ok := in.inlined[src]; ok {
+		in.record(src)
+		return rewritten, nil
+	}
+
+	data, contentType, err := in.loader(src)
+	if err != nil {
+		return "", fmt.Errorf("load image %q: %w", src, err)
+	}
+	if data == nil {
+		in.inlined[src] = src
+		return src, nil
+	}
+	if contentType == "" {
+		contentType = detectImageType(data)
+	}
+
+	if in.mode == InlineImagesDataURI {
+		rewritten := "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data)
+		in.inlined[src] = rewritten
+		return rewritten, nil
+	}
+
+	attachment := Attachment{
+		ContentID:   contentID(src),
+		Filename:    attachmentFilename(src),
+		ContentType: contentType,
+		Data:        data,
+		Source:      src,
+	}
+	in.add(attachment)
+	in.record(src)
+	return in.inlined[src], nil
+}
+
+// Add registers an attachment that was used by markup rendered earlier, such
+// as a block reused by an incremental render.
+func (in *imageInliner) Add(attachment Attachment) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if _, ok := in.inlined[attachment.Source]; !ok {
+		in.add(attachment)
+	}
+	in.record(attachment.Source)
+}
+
+// Attachments returns the attachments referenced from the document in the
+// order they were first used.
+func (in *imageInliner) Attachments() []Attachment {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	attachments := make([]Attachment, len(in.attachments))
+	copy(attachments, in.attachments)
+	return attachments
+}
+
+// Record starts collecting the attachments used through the inliner,
+// including ones it already holds, until the returned function is called.
+// That function returns the collected attachments in first-use order.
+func (in *imageInliner) Record() (stop func() []Attachment) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.recording = []Attachment{}
+	in.recorded = make(map[string]bool)
+
+	return func() []Attachment {
+		in.mu.Lock()
+		defer in.mu.Unlock()
+		recorded := in.recording
+		in.recording = nil
+		in.recorded = nil
+		return recorded
+	}
+}
+
+func (in *imageInliner) add(attachment Attachment) {
+	in.inlined[attachment.Source] = "cid:" + attachment.ContentID
+	if !in.contentIDs[attachment.ContentID] {
+		in.contentIDs[attachment.ContentID] = true
+		in.attachments = append(in.attachments, attachment)
+	}
+}
+
+func (in *imageInliner) record(src string) {
+	if in.recording == nil || in.recorded[src] {
+		return
+	}
+	for _, attachment := range in.attachments {
+		if attachment.Source == src {
+			in.recorded[src] = true
+			in.recording = append(in.recording, attachment)
+			return
+		}
+	}
+}
+
+// imageSignatures maps the leading bytes of image formats to their MIME
+// types. A zero byte in a signature's mask matches any byte.
+var imageSignatures = []struct {
+	prefix      []byte
+	mask        []byte
+	contentType string
+}{
+	{prefix: []byte("\x89PNG\r\n\x1a\n"), contentType: "image/png"},
+	{prefix: []byte("\xff\xd8\xff"), contentType: "image/jpeg"},
+	{prefix: []byte("GIF87a"), contentType: "image/gif"},
+	{prefix: []byte("GIF89a"), contentType: "image/gif"},
+	{prefix: []byte("RIFF\x00\x00\x00\x00WEBPVP"), mask: []byte("\xff\xff\xff\xff\x00\x00\x00\x00\xff\xff\xff\xff\xff\xff"), contentType: "image/webp"},
+	{prefix: []byte("\x00\x00\x00\x00ftypavif"), mask: []byte("\x00\x00\x00\x00\xff\xff\xff\xff\xff\xff\xff\xff"), contentType: "image/avif"},
+	{prefix: []byte("BM"), contentType: "image/bmp"},
+	{prefix: []byte("\x00\x00\x01\x00"), contentType: "image/x-icon"},
+}
+
+// detectImageType returns the MIME type of image data from its leading bytes,
+// or application/octet-stream for formats it does not know. It replaces
+// http.DetectContentType, which would pull net/http into every build,
+// including the WASM one.
+func detectImageType(data []byte) string {
+	for _, sig := range imageSignatures {
+		if len(data) < len(sig.prefix) {
+			continue
+		}
+		matched := true
+		for i, b := range sig.prefix {
+			if sig.mask != nil && sig.mask[i] == 0 {
+				continue
+			}
+			if data[i] != b {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return sig.contentType
+		}
+	}
+
+	text := bytes.TrimLeft(data, "\ufeff \t\r\n")
+	if bytes.HasPrefix(text, []byte("<svg")) || bytes.HasPrefix(text, []byte("<?xml")) && bytes.Contains(text, []byte("<svg")) {
+		return "image/svg+xml"
+	}
+	return "application/octet-stream"
+}
+
+// contentID derives a Content-ID from src, so the same image keeps its
+// Content-ID across renders
+func contentID(src string) string {
+	h := fnv.New64a()
+	h.Write([]byte(src))
+	return fmt.Sprintf("img-%016x@gomjml", h.Sum64())
+}
+
+// attachmentFilename returns the last path segment of src, without a query
+// or fragment, or "image" when there is none
+func attachmentFilename(src string) string {
+	if i := strings.IndexAny(src, "?#"); i >= 0 {
+		src = src[:i]
+	}
+	name := path.Base(strings.ReplaceAll(src, `\`, "/"))
+	if name == "." || name == "/" || name == "" || strings.HasSuffix(name, ":") {
+		return "image"
+	}
+	return name
+}
+
+func hasSchemeFold(s, scheme string) bool {
+	return len(s) >= len(scheme) && strings.EqualFold(s[:len(scheme)], scheme)
+}
@@ -0,0 +1,127 @@
+package mjml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const inlineImagesDocument = `<mjml><mj-body>
+  <mj-section><mj-column>
+    <mj-image src="https://example.com/img/logo.png?v=2" />
+    <mj-image src="https://example.com/img/logo.png?v=2" />
+    <mj-image src="https://cdn.example.com/remote.png" />
+    <mj-social><mj-social-element src="https://example.com/img/icon.gif" href="https://example.com">Us</mj-social-element></mj-social>
+  </mj-column></mj-section>
+</mj-body></mjml>`
+
+func TestWithInlineImages(t *testing.T) {
+	gif := []byte("GIF89a\x01\x00\x01\x00")
+	loads := 0
+	loader := func(src string) ([]byte, string, error) {
+		loads++
+		switch {
+		case strings.HasPrefix(src, "https://cdn."):
+			return nil, "", nil
+		case strings.HasSuffix(src, ".gif"):
+			return gif, "", nil
+		}
+		return []byte("png"), "image/png", nil
+	}
+
+	t.Run("cid", func(t *testing.T) {
+		loads = 0
+		result, err := RenderWithAST(inlineImagesDocument, WithInlineImages(InlineImagesCID, loader))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loads != 3 {
+			t.Errorf("loaded %d images, want each source loaded once", loads)
+		}
+		if len(result.Attachments) != 2 {
+			t.Fatalf("got %d attachments, want 2: %+v", len(result.Attachments), result.Attachments)
+		}
+		logo, icon := result.Attachments[0], result.Attachments[1]
+		if logo.Filename != "logo.png" || logo.ContentType != "image/png" || icon.ContentType != "image/gif" {
+			t.Errorf("unexpected attachments: %+v", result.Attachments)
+		}
+		for _, attachment := range result.Attachments {
+			if !strings.Contains(result.HTML, `src="cid:`+attachment.ContentID+`"`) {
+				t.Errorf("output does not reference %s", attachment.ContentID)
+			}
+		}
+		if !strings.Contains(result.HTML, `src="https://cdn.example.com/remote.png"`) {
+			t.Error("source the loader skipped was rewritten")
+		}
+	})
+
+	t.Run("data uri", func(t *testing.T) {
+		result, err := RenderWithAST(inlineImagesDocument, WithInlineImages(InlineImagesDataURI, loader))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Attachments) != 0 {
+			t.Errorf("got %d attachments, want none", len(result.Attachments))
+		}
+		if !strings.Contains(result.HTML, `src="data:image/png;base64,cG5n"`) {
+			t.Error("mj-image source was not replaced with a data URI")
+		}
+		if !strings.Contains(result.HTML, `src="data:image/gif;base64,`) {
+			t.Error("social icon source was not replaced with a data URI")
+		}
+	})
+
+	t.Run("loader error", func(t *testing.T) {
+		failing := func(string) ([]byte, string, error) { return nil, "", errors.New("not found") }
+		if _, err := Render(inlineImagesDocument, WithInlineImages(InlineImagesCID, failing)); err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("Render() error = %v, want the loader error", err)
+		}
+	})
+
+	t.Run("incremental", func(t *testing.T) {
+		r := NewIncrementalRenderer(WithInlineImages(InlineImagesCID, loader))
+		ast, err := ParseMJML(inlineImagesDocument)
+		if err != nil {
+			t.Fatal(err)
+		}
+		first, err := r.Render(ast)
+		if err != nil {
+			t.Fatal(err)
+		}
+		loads = 0
+		second, err := r.Render(ast)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loads != 0 {
+			t.Errorf("reused block loaded %d images again", loads)
+		}
+		if len(second.Attachments) != len(first.Attachments) {
+			t.Errorf("reused render has %d attachments, want %d", len(second.Attachments), len(first.Attachments))
+		}
+	})
+}
+
+func TestDetectImageType(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"\x89PNG\r\n\x1a\n\x00\x00", "image/png"},
+		{"\xff\xd8\xff\xe0\x00\x10JFIF", "image/jpeg"},
+		{"GIF87a\x01\x00", "image/gif"},
+		{"RIFF\x24\x00\x00\x00WEBPVP8 ", "image/webp"},
+		{"\x00\x00\x00\x1cftypavif\x00", "image/avif"},
+		{"BM\x36\x00", "image/bmp"},
+		{"\x00\x00\x01\x00\x01\x00", "image/x-icon"},
+		{"\n<svg xmlns=\"http://www.w3.org/2000/svg\"/>", "image/svg+xml"},
+		{"<?xml version=\"1.0\"?><svg/>", "image/svg+xml"},
+		{"RIFF\x24\x00\x00\x00WAVEfmt ", "application/octet-stream"},
+		{"png", "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		if got := detectImageType([]byte(tt.data)); got != tt.want {
+			t.Errorf("detectImageType(%q) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
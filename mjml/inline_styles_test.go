@@ -0,0 +1,101 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInlineStylesSelectorsAndSpecificity(t *testing.T) {
+	template := `<mjml>
+  <mj-head>
+    <mj-style inline="inline">
+      a { color: red; text-decoration: none }
+      .footer a { color: #888888 }
+      #legal { font-size: 10px }
+      #legal strong { font-weight: 900 }
+      a:hover { color: blue }
+    </mj-style>
+  </mj-head>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-text css-class="footer"><p id="legal">Read the <a href="https://example.com/terms">terms</a> <strong>now</strong></p></mj-text>
+        <mj-text><p><a href="https://example.com">Home</a> <strong>plain</strong></p></mj-text>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(template)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+
+	for _, want := range []string{
+		`<p id="legal" style="font-size:10px;">`,
+		`<a href="https://example.com/terms" style="color:#888888;text-decoration:none;">terms</a>`,
+		`<strong style="font-weight:900;">now</strong>`,
+		`<a href="https://example.com" style="color:red;text-decoration:none;">Home</a>`,
+		`<strong>plain</strong>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s in output", want)
+		}
+	}
+	if strings.Contains(html, "color:blue") {
+		t.Error("pseudo-class rules must not be inlined")
+	}
+}
+
+func TestInlineStylesApplyToEveryComponentInOrder(t *testing.T) {
+	template := `<mjml>
+  <mj-head>
+    <mj-style inline="inline">
+      .shadow { box-shadow: 0 1px 2px #cccccc; padding: 99px }
+      .loud { color: red !important }
+      .row { background: #eeeeee }
+    </mj-style>
+  </mj-head>
+  <mj-body>
+    <mj-section css-class="shadow">
+      <mj-column css-class="shadow">
+        <mj-text css-class="shadow">Text</mj-text>
+        <mj-button css-class="shadow">Button</mj-button>
+        <mj-image css-class="shadow" src="https://example.com/a.png" />
+        <mj-social><mj-social-element name="facebook" css-class="row">Share</mj-social-element></mj-social>
+        <mj-navbar><mj-navbar-link href="/" css-class="loud">Home</mj-navbar-link></mj-navbar>
+        <mj-table><tr class="row"><td>Cell</td></tr></mj-table>
+        <mj-raw><p class="row" style="margin:0">Raw</p></mj-raw>
+        <mj-text><p class="row" style="background:#ffffff">Own</p></mj-text>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(template)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+
+	for _, want := range []string{
+		// Declarations come first, and the component's own padding wins
+		`<div class="shadow" style="box-shadow:0 1px 2px #cccccc;padding:99px;margin:0px auto;max-width:600px;">`,
+		`class="mj-column-per-100 mj-outlook-group-fix shadow" style="box-shadow:0 1px 2px #cccccc;padding:99px;font-size:0px;`,
+		`<td align="left" class="shadow" style="box-shadow:0 1px 2px #cccccc;font-size:0px;padding:10px 25px;word-break:break-word;">`,
+		`<td align="center" class="shadow" style="box-shadow:0 1px 2px #cccccc;font-size:0px;padding:10px 25px;word-break:break-word;">`,
+		`<tr class="row" style="background:#eeeeee;">`,
+		`class="mj-link loud" style="color:red !important;display:inline-block;font-family:`,
+		`<p class="row" style="background:#eeeeee;margin:0">Raw</p>`,
+		`<p class="row" style="background:#ffffff">Own</p>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s in output", want)
+		}
+	}
+	if strings.Contains(html, "display:inline-block;color:#000000;") {
+		t.Error("expected the !important declaration to replace the navbar link color")
+	}
+	if strings.Count(html, `<tr class="row" style="background:#eeeeee;">`) != 2 {
+		t.Error("expected the social element and table rows to be styled")
+	}
+}
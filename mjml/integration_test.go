@@ -11,6 +11,7 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/preslavrachev/gomjml/mjml/components"
+	"github.com/preslavrachev/gomjml/mjml/htmldiff"
 	"github.com/preslavrachev/gomjml/mjml/testutils"
 )
 
@@ -223,7 +224,7 @@ func TestMJMLAgainstExpected(t *testing.T) {
 		{name: "mj-navbar"},
 		{name: "mj-navbar-ico"},
 		{name: "mj-navbar-align-class"},
-		// {name: "mj-navbar-multiple"}, // This will require rework of the ID generation strategy --- IGNORE --- for now
+		{name: "mj-navbar-multiple"},
 		// // MJ-HERO tests
 		{name: "mj-hero"},
 		{name: "mj-hero-background-color"},
@@ -302,6 +303,11 @@ func TestMJMLAgainstExpected(t *testing.T) {
 					expectedFile)
 			}
 
+			// Hamburger navbars get random checkbox IDs; reuse the ones baked into the fixture
+			if ids := findRegexMatches(expected, navbarCheckboxIDPattern); len(ids) > 0 {
+				components.SetNavbarTestIDs(ids...)
+			}
+
 			// Get actual output from Go implementation (direct library usage)
 			actual, err := Render(string(mjmlContent))
 			if err != nil {
@@ -328,117 +334,21 @@ func TestMJMLAgainstExpected(t *testing.T) {
 				t.Fatalf("Expected the following error: %s, but got none", tc.errHandler(errors.New("no error")))
 			}
 
-			// Collect ALL difference types instead of early returns for comprehensive analysis
-			var allDifferences []string
-
-			// Normalize legacy MJML reference quirks (eg. MRML style conditionals) so that
-			// comparisons operate on semantically equivalent markup. This keeps the testdata
-			// fixtures stable while letting the Go renderer follow the upstream MJML output.
-			normalizedExpected := normalizeMJMLReference(expected)
-			normalizedActual := normalizeMJMLReference(actual)
-
-			// Check for MSO table attribute differences FIRST (before DOM comparison)
-			// because MSO conditionals are not part of DOM and will be ignored by DOM comparison
-			msoTableDiff := checkMSOTableAttributeDifferences(normalizedExpected, normalizedActual)
-			if msoTableDiff != "" {
-				allDifferences = append(allDifferences, "MSO table attribute differences found:\n"+msoTableDiff)
-			}
-
-			// Check for MSO conditional comment differences
-			msoDiff := checkMSOConditionalDifferences(normalizedExpected, normalizedActual)
-			if msoDiff != "" {
-				allDifferences = append(allDifferences, "MSO conditional comment differences found:\n"+msoDiff)
-			}
-
-			// Compare outputs using DOM tree comparison
-			domTreesMatch := compareDOMTrees(normalizedExpected, normalizedActual)
-			if !domTreesMatch {
-				// Check for HTML entity encoding differences
-				entityDiff := checkHTMLEntityDifferences(normalizedExpected, normalizedActual)
-				if entityDiff != "" {
-					allDifferences = append(allDifferences, "HTML entity encoding differences found:\n"+entityDiff)
-				}
-
-				// Check for VML attribute differences
-				vmlDiff := checkVMLAttributeDifferences(normalizedExpected, normalizedActual)
-				if vmlDiff != "" {
-					allDifferences = append(allDifferences, "VML attribute differences found:\n"+vmlDiff)
-				}
-
-				// Check for background CSS property differences
-				bgDiff := checkBackgroundPropertyDifferences(normalizedExpected, normalizedActual)
-				if bgDiff != "" {
-					allDifferences = append(allDifferences, "Background CSS property differences found:\n"+bgDiff)
-				}
-
-				// Enhanced DOM-based diff with debugging
-				domDiff := createDOMDiff(normalizedExpected, normalizedActual)
-				if domDiff != "" {
-					allDifferences = append(allDifferences, "DOM structure differences:\n"+domDiff)
-				}
-
-				// Enhanced debugging: analyze style differences with precise element identification
-				// AIDEV-NOTE: Only log style differences when they actually exist to reduce noise
-				styleResult := testutils.CompareStylesPrecise(normalizedExpected, normalizedActual)
-				if styleResult.ParseError != nil {
-					allDifferences = append(
-						allDifferences,
-						fmt.Sprintf("DOM parsing failed: %v", styleResult.ParseError),
-					)
-				} else if styleResult.HasDifferences {
-					var styleDiffs []string
-					styleDiffs = append(styleDiffs, fmt.Sprintf("Style differences for %s:", tc.name))
-					for _, element := range styleResult.Elements {
-						switch element.Status {
-						case testutils.ElementExtra:
-							componentInfo := ""
-							if element.Component != "" {
-								componentInfo = fmt.Sprintf(" [created by %s]", element.Component)
-							}
-							styleDiffs = append(styleDiffs, fmt.Sprintf("  Extra element[%d]: <%s class=\"%s\" style=\"%s\">%s",
-								element.Index, element.Tag, element.Classes, element.Actual, componentInfo))
-						case testutils.ElementMissing:
-							styleDiffs = append(styleDiffs, fmt.Sprintf("  Missing element[%d]: <%s class=\"%s\" style=\"%s\">",
-								element.Index, element.Tag, element.Classes, element.Expected))
-						case testutils.ElementDifferent:
-							componentInfo := ""
-							if element.Component != "" {
-								componentInfo = fmt.Sprintf(" [created by %s]", element.Component)
-							}
-							styleDiffs = append(styleDiffs, fmt.Sprintf("  Style diff element[%d]: <%s class=\"%s\">%s",
-								element.Index, element.Tag, element.Classes, componentInfo))
-							styleDiffs = append(styleDiffs, fmt.Sprintf("    Expected: style=\"%s\"", element.Expected))
-							styleDiffs = append(styleDiffs, fmt.Sprintf("    Actual:   style=\"%s\"", element.Actual))
-							if !element.StyleDiff.IsEmpty() {
-								styleDiffs = append(styleDiffs, fmt.Sprintf("    %s", element.StyleDiff.String()))
-							}
-						}
-					}
-					if len(styleDiffs) > 0 {
-						allDifferences = append(allDifferences, strings.Join(styleDiffs, "\n"))
-					}
-				}
-			}
-
-			// Check for self-closing tag serialization differences regardless of DOM tree match
-			selfClosingDiff := checkSelfClosingTagDifferences(normalizedExpected, normalizedActual)
-			if selfClosingDiff != "" {
-				allDifferences = append(
-					allDifferences,
-					"Self-closing tag serialization differences found:\n"+selfClosingDiff,
-				)
-			}
-
-			// Report ALL collected differences
-			if len(allDifferences) > 0 {
+			// Compare semantically: DOM structure, attributes, styles, Outlook
+			// conditional comments and void element serialization
+			report := htmldiff.Compare(expected, actual)
+			if !report.Equal() {
 				writeDebugFiles(tc.name, expected, actual)
 				t.Errorf("\n=== COMPREHENSIVE DIFFERENCE ANALYSIS ===\n%s\n===========================================",
-					strings.Join(allDifferences, "\n\n"))
+					strings.Join(append([]string{report.String()}, diagnoseDifferences(expected, actual)...), "\n\n"))
 			}
 		})
 	}
 }
 
+// navbarCheckboxIDPattern captures the hamburger checkbox IDs of a reference fixture in document order.
+const navbarCheckboxIDPattern = `<input type="checkbox" id="([^"]+)" class="mj-menu-checkbox"`
+
 // getTestdataFilename returns the file path for a test MJML file located in the "testdata" directory,
 // using the provided testName as the base filename. The resulting path has the format "testdata/{testName}.mjml".
 func getTestdataFilename(testName string) string {
@@ -452,8 +362,8 @@ func writeDebugFiles(testName, expected, actual string) {
 	os.WriteFile("/tmp/actual_"+testName+".html", []byte(actual), 0o644)
 
 	// Also persist the normalized versions used during comparison for easier diffing
-	normalizedExpected := normalizeMJMLReference(expected)
-	normalizedActual := normalizeMJMLReference(actual)
+	normalizedExpected := htmldiff.Normalize(expected)
+	normalizedActual := htmldiff.Normalize(actual)
 	os.WriteFile("/tmp/normalized_expected_"+testName+".html", []byte(normalizedExpected), 0o644)
 	os.WriteFile("/tmp/normalized_actual_"+testName+".html", []byte(normalizedActual), 0o644)
 }
@@ -518,63 +428,6 @@ func TestComponentCreation(t *testing.T) {
 	}
 }
 
-// TestCSSNormalization tests the CSS content normalization function
-func TestCSSNormalization(t *testing.T) {
-	testCases := []struct {
-		name     string
-		css1     string
-		css2     string
-		expected bool
-	}{
-		{
-			name:     "identical CSS",
-			css1:     ".mj-column-per-100 { width:100% }",
-			css2:     ".mj-column-per-100 { width:100% }",
-			expected: true,
-		},
-		{
-			name:     "different order CSS rules",
-			css1:     ".mj-column-per-100 { width:100% } .mj-column-per-50 { width:50% }",
-			css2:     ".mj-column-per-50 { width:50% } .mj-column-per-100 { width:100% }",
-			expected: true,
-		},
-		{
-			name:     "different whitespace",
-			css1:     ".mj-column-per-100{width:100%}.mj-column-per-50{width:50%}",
-			css2:     ".mj-column-per-100 { width: 100% } .mj-column-per-50 { width: 50% }",
-			expected: true,
-		},
-		{
-			name:     "different content",
-			css1:     ".mj-column-per-100 { width:100% }",
-			css2:     ".mj-column-per-100 { width:50% }",
-			expected: false,
-		},
-		{
-			name:     "complex media query reordering",
-			css1:     "@media only screen { .mj-column-per-100 { width:100% } .mj-column-per-50 { width:50% } }",
-			css2:     "@media only screen { .mj-column-per-50 { width:50% } .mj-column-per-100 { width:100% } }",
-			expected: true,
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			normalized1 := normalizeCSSContent(tc.css1)
-			normalized2 := normalizeCSSContent(tc.css2)
-
-			result := normalized1 == normalized2
-			if result != tc.expected {
-				t.Errorf("Expected %v, got %v", tc.expected, result)
-				t.Logf("CSS1: %s", tc.css1)
-				t.Logf("CSS2: %s", tc.css2)
-				t.Logf("Normalized CSS1: %s", normalized1)
-				t.Logf("Normalized CSS2: %s", normalized2)
-			}
-		})
-	}
-}
-
 // parseStyleProperties parses CSS style string into property map
 func parseStyleProperties(style string) map[string]string {
 	props := make(map[string]string)
@@ -675,188 +528,27 @@ func compareStylePropertiesMaps(expectedProps, actualProps map[string]string) St
 	return diff
 }
 
-// compareDOMTrees compares two HTML strings using DOM tree comparison
-// This approach handles attribute ordering, CSS property ordering, and whitespace normalization
-func compareDOMTrees(expected, actual string) bool {
-	expectedDoc, err := goquery.NewDocumentFromReader(strings.NewReader(expected))
-	if err != nil {
-		return false
-	}
-
-	actualDoc, err := goquery.NewDocumentFromReader(strings.NewReader(actual))
-	if err != nil {
-		return false
-	}
-
-	return compareNodes(expectedDoc.Selection, actualDoc.Selection)
-}
-
-// compareNodes recursively compares two goquery selections (DOM subtrees)
-func compareNodes(expected, actual *goquery.Selection) bool {
-	// Compare number of nodes
-	if expected.Length() != actual.Length() {
-		return false
-	}
-
-	// Compare each node pair
-	equal := true
-	expected.Each(func(i int, expectedNode *goquery.Selection) {
-		if i >= actual.Length() {
-			equal = false
-			return
-		}
-
-		actualNode := actual.Eq(i)
-
-		// Compare node types and tag names
-		expectedTag := goquery.NodeName(expectedNode)
-		actualTag := goquery.NodeName(actualNode)
-		if expectedTag != actualTag {
-			equal = false
-			return
-		}
-
-		// For text nodes, compare text content (normalized)
-		if expectedTag == "#text" {
-			expectedText := strings.TrimSpace(expectedNode.Text())
-			actualText := strings.TrimSpace(actualNode.Text())
-			if expectedText != actualText {
-				equal = false
-				return
-			}
-			return
-		}
-
-		// For element nodes, compare attributes
-		if !compareAttributes(expectedNode, actualNode) {
-			equal = false
-			return
-		}
-
-		// Recursively compare children
-		expectedChildren := expectedNode.Children()
-		actualChildren := actualNode.Children()
-		if !compareNodes(expectedChildren, actualChildren) {
-			equal = false
-			return
-		}
-
-		// Compare text content for elements that might have mixed content
-		expectedText := strings.TrimSpace(expectedNode.Contents().Not("*").Text())
-		actualText := strings.TrimSpace(actualNode.Contents().Not("*").Text())
-
-		// Special handling for style tags - check for specific CSS issues first
-		if expectedTag == "style" {
-			// Check for Firefox-specific .moz-text-html prefix issues
-			if hasFirefoxCSSIssue(expectedText, actualText) {
-				equal = false
-				return
-			}
-			// Then apply general CSS normalization for ordering issues
-			if normalizeCSSContent(expectedText) != normalizeCSSContent(actualText) {
-				equal = false
-				return
-			}
-		} else if expectedText != actualText {
-			equal = false
-			return
-		}
-	})
-
-	return equal
-}
-
-// compareAttributes compares attributes between two nodes, normalizing style attributes
-func compareAttributes(expected, actual *goquery.Selection) bool {
-	// Get all attributes from both nodes
-	expectedAttrs := make(map[string]string)
-	actualAttrs := make(map[string]string)
-
-	// Extract expected attributes
-	if expected.Length() > 0 {
-		node := expected.Get(0)
-		for _, attr := range node.Attr {
-			switch {
-			case attr.Key == "style":
-				expectedAttrs[attr.Key] = normalizeStyleAttribute(attr.Val)
-			case attr.Key == "class":
-				expectedAttrs[attr.Key] = normalizeClassAttribute(attr.Val)
-			case !strings.HasPrefix(attr.Key, "data-mj-debug"):
-				expectedAttrs[attr.Key] = attr.Val
-			}
-		}
-	}
+// diagnoseDifferences collects hints for a failed fixture comparison: entity,
+// VML and background property differences that the DOM comparison normalizes
+// away, and a tag count and style summary of both documents.
+func diagnoseDifferences(expected, actual string) []string {
+	normalizedExpected := htmldiff.Normalize(expected)
+	normalizedActual := htmldiff.Normalize(actual)
 
-	// Extract actual attributes
-	if actual.Length() > 0 {
-		node := actual.Get(0)
-		for _, attr := range node.Attr {
-			switch {
-			case attr.Key == "style":
-				actualAttrs[attr.Key] = normalizeStyleAttribute(attr.Val)
-			case attr.Key == "class":
-				actualAttrs[attr.Key] = normalizeClassAttribute(attr.Val)
-			case !strings.HasPrefix(attr.Key, "data-mj-debug"):
-				actualAttrs[attr.Key] = attr.Val
-			}
-		}
+	var hints []string
+	if entityDiff := checkHTMLEntityDifferences(normalizedExpected, normalizedActual); entityDiff != "" {
+		hints = append(hints, "HTML entity encoding differences found:\n"+entityDiff)
 	}
-
-	// Compare attribute maps
-	if len(expectedAttrs) != len(actualAttrs) {
-		return false
-	}
-
-	for key, expectedVal := range expectedAttrs {
-		actualVal, exists := actualAttrs[key]
-		if !exists || expectedVal != actualVal {
-			return false
-		}
-	}
-
-	return true
-}
-
-func normalizeClassAttribute(class string) string {
-	if class == "" {
-		return ""
-	}
-
-	parts := strings.Fields(class)
-	sort.Strings(parts)
-	return strings.Join(parts, " ")
-}
-
-// normalizeStyleAttribute normalizes CSS style attributes by sorting properties
-func normalizeStyleAttribute(style string) string {
-	if style == "" {
-		return ""
-	}
-
-	// Parse CSS properties
-	props := parseStyleProperties(style)
-
-	// Sort properties by key for consistent comparison
-	var keys []string
-	for key := range props {
-		keys = append(keys, key)
+	if vmlDiff := checkVMLAttributeDifferences(normalizedExpected, normalizedActual); vmlDiff != "" {
+		hints = append(hints, "VML attribute differences found:\n"+vmlDiff)
 	}
-	sort.Strings(keys)
-
-	// Rebuild style string with sorted properties
-	var parts []string
-	for _, key := range keys {
-		if value := strings.TrimSpace(props[key]); value != "" {
-			parts = append(parts, key+": "+value)
-		}
+	if bgDiff := checkBackgroundPropertyDifferences(normalizedExpected, normalizedActual); bgDiff != "" {
+		hints = append(hints, "Background CSS property differences found:\n"+bgDiff)
 	}
-
-	result := strings.Join(parts, "; ")
-	if result != "" && !strings.HasSuffix(result, ";") {
-		result += ";"
+	if domDiff := createDOMDiff(normalizedExpected, normalizedActual); domDiff != "" {
+		hints = append(hints, "DOM structure differences:\n"+domDiff)
 	}
-
-	return result
+	return hints
 }
 
 // createDOMDiff compares two HTML DOM strings and returns a formatted string describing their differences.
@@ -1120,108 +812,6 @@ func getMJMLTagInfo(doc *goquery.Document) map[string]int {
 	return tagCounts
 }
 
-// hasFirefoxCSSIssue checks for specific Firefox CSS issues like missing .moz-text-html prefixes
-func hasFirefoxCSSIssue(expected, actual string) bool {
-	// Only check if this looks like a Firefox-specific style tag
-	if !strings.Contains(expected, ".moz-text-html") {
-		return false // Not a Firefox CSS style, no issue
-	}
-
-	// Simple heuristic: if expected has ".moz-text-html" but actual is missing some instances
-	expectedCount := strings.Count(expected, ".moz-text-html")
-	actualCount := strings.Count(actual, ".moz-text-html")
-
-	// If actual has fewer .moz-text-html prefixes than expected, it's likely an issue
-	return actualCount < expectedCount
-}
-
-// normalizeCSSContent normalizes CSS content for comparison by removing whitespace and sorting characters
-func normalizeCSSContent(css string) string {
-	// Remove all whitespace and newlines
-	normalized := strings.ReplaceAll(css, " ", "")
-	normalized = strings.ReplaceAll(normalized, "\n", "")
-	normalized = strings.ReplaceAll(normalized, "\t", "")
-	normalized = strings.ReplaceAll(normalized, "\r", "")
-
-	// Convert to slice of runes, sort, and convert back
-	runes := []rune(normalized)
-	sort.Slice(runes, func(i, j int) bool {
-		return runes[i] < runes[j]
-	})
-
-	return string(runes)
-}
-
-// checkSelfClosingTagDifferences detects differences in self-closing tag serialization
-// between expected and actual HTML that would be missed by DOM comparison
-func checkSelfClosingTagDifferences(expected, actual string) string {
-	// HTML5 void elements that should be self-closing
-	voidTags := []string{
-		"br",
-		"hr",
-		"img",
-		"input",
-		"meta",
-		"link",
-		"area",
-		"base",
-		"col",
-		"embed",
-		"source",
-		"track",
-		"wbr",
-	}
-
-	var differences []string
-
-	for _, tag := range voidTags {
-		// Count different serialization patterns for this tag
-		expectedUnclosed := countTagPattern(expected, fmt.Sprintf("<%s>", tag))
-		actualUnclosed := countTagPattern(actual, fmt.Sprintf("<%s>", tag))
-
-		expectedSelfClosed := countTagPattern(
-			expected,
-			fmt.Sprintf("<%s/>", tag),
-		) + countTagPattern(
-			expected,
-			fmt.Sprintf("<%s />", tag),
-		)
-		actualSelfClosed := countTagPattern(
-			actual,
-			fmt.Sprintf("<%s/>", tag),
-		) + countTagPattern(
-			actual,
-			fmt.Sprintf("<%s />", tag),
-		)
-
-		// Check for differences in serialization
-		if expectedUnclosed != actualUnclosed || expectedSelfClosed != actualSelfClosed {
-			differences = append(
-				differences,
-				fmt.Sprintf(
-					"<%s> tag serialization mismatch:\n  Expected: %d unclosed + %d self-closed\n  Actual:   %d unclosed + %d self-closed",
-					tag,
-					expectedUnclosed,
-					expectedSelfClosed,
-					actualUnclosed,
-					actualSelfClosed,
-				),
-			)
-		}
-	}
-
-	if len(differences) > 0 {
-		return strings.Join(differences, "\n")
-	}
-
-	return ""
-}
-
-// countTagPattern counts occurrences of a specific tag pattern in HTML
-func countTagPattern(html, pattern string) int {
-	return strings.Count(strings.ToLower(html), strings.ToLower(pattern))
-}
-
 // sortStringChars sorts all characters in a string alphabetically
 // Used to detect if two strings have identical content but different ordering
 func sortStringChars(s string) string {
@@ -1280,66 +870,6 @@ func checkHTMLEntityDifferences(expected, actual string) string {
 	return ""
 }
 
-// checkMSOConditionalDifferences detects differences in MSO conditional comments
-// that would be normalized away by DOM parsing but are still meaningful for email rendering
-func checkMSOConditionalDifferences(expected, actual string) string {
-	// Common MSO conditional patterns that might differ
-	msoPatterns := []struct {
-		pattern string
-		name    string
-	}{
-		{"<!--[if mso]>", "mso-opening"},
-		{"<!--[if !mso]><!-->", "not-mso-opening"},
-		{"<!--<![endif]-->", "endif"},
-		{"<!--[if mso | IE]>", "mso-or-ie-opening"},
-		{"<!--[if !mso | IE]><!-->", "not-mso-or-ie-opening"},
-		{"<!--[if IE] mso |>", "ie-mso-opening"}, // Added missing pattern
-		{"<!--[if lte mso 11]>", "mso-lte-11-opening"},
-		{"<![endif]-->", "simple-endif"},
-	}
-
-	var differences []string
-
-	// Check for count differences first
-	for _, pattern := range msoPatterns {
-		expectedCount := strings.Count(expected, pattern.pattern)
-		actualCount := strings.Count(actual, pattern.pattern)
-
-		if expectedCount != actualCount {
-			differences = append(differences,
-				fmt.Sprintf("MSO conditional %s mismatch: expected %d, actual %d",
-					pattern.name, expectedCount, actualCount))
-		}
-	}
-
-	// Check for sequence/ordering differences by comparing MSO blocks
-	if len(differences) == 0 {
-		// Extract sequences of MSO conditionals + HTML elements for comparison
-		expectedSequence := extractMSOSequences(expected)
-		actualSequence := extractMSOSequences(actual)
-
-		if len(expectedSequence) != len(actualSequence) {
-			differences = append(differences,
-				fmt.Sprintf("MSO sequence count mismatch: expected %d blocks, actual %d blocks",
-					len(expectedSequence), len(actualSequence)))
-		} else {
-			// Compare each sequence block
-			for i, expectedBlock := range expectedSequence {
-				if i < len(actualSequence) && expectedBlock != actualSequence[i] {
-					differences = append(differences,
-						fmt.Sprintf("MSO sequence differs at block %d:\n  Expected: %s\n  Actual: %s",
-							i, expectedBlock, actualSequence[i]))
-				}
-			}
-		}
-	}
-
-	if len(differences) > 0 {
-		return strings.Join(differences, "\n")
-	}
-	return ""
-}
-
 // checkVMLAttributeDifferences detects differences in VML attributes that are critical for email rendering
 func checkVMLAttributeDifferences(expected, actual string) string {
 	vmlPatterns := []struct {
@@ -1383,43 +913,6 @@ func checkVMLAttributeDifferences(expected, actual string) string {
 	return ""
 }
 
-// checkMSOTableAttributeDifferences detects differences in MSO table attributes
-func checkMSOTableAttributeDifferences(expected, actual string) string {
-	msoTableAttrs := []string{"bgcolor", "width", "align", "cellpadding", "cellspacing"}
-
-	var differences []string
-
-	for _, attr := range msoTableAttrs {
-		// Look for MSO conditional table attributes - simplified pattern
-		// Only match <table ... bgcolor="..."> inside MSO conditional blocks
-		expectedPattern := fmt.Sprintf(`<!--\[if mso.*?<table[^>]*%s="([^"]*)"`, attr)
-		actualPattern := expectedPattern
-
-		expectedMatches := findRegexMatches(expected, expectedPattern)
-		actualMatches := findRegexMatches(actual, actualPattern)
-
-		if len(expectedMatches) != len(actualMatches) {
-			differences = append(differences,
-				fmt.Sprintf("MSO table %s attribute count mismatch: expected %d, actual %d",
-					attr, len(expectedMatches), len(actualMatches)))
-		} else {
-			// Check for value differences when counts match
-			for i, expectedVal := range expectedMatches {
-				if i < len(actualMatches) && expectedVal != actualMatches[i] {
-					differences = append(differences,
-						fmt.Sprintf("MSO table %s attribute value mismatch: expected '%s', actual '%s'",
-							attr, expectedVal, actualMatches[i]))
-				}
-			}
-		}
-	}
-
-	if len(differences) > 0 {
-		return strings.Join(differences, "\n")
-	}
-	return ""
-}
-
 // checkBackgroundPropertyDifferences detects differences in CSS background properties
 func checkBackgroundPropertyDifferences(expected, actual string) string {
 	bgProps := []string{
@@ -1489,207 +982,3 @@ func findRegexMatches(text, pattern string) []string {
 	}
 	return results
 }
-
-// normalizeMJMLReference cleans up legacy MRML fixtures so comparisons focus on
-// semantic differences rather than serialization quirks. It removes empty style
-// tags and merges split MSO conditional blocks that MJML now emits as a single
-// table/td wrapper.
-var (
-	mustacheAfterClosingPattern  = regexp.MustCompile(`(-->|>)\s+(\{\{)`)
-	mustacheBeforeOpeningPattern = regexp.MustCompile(`(\}\})\s+(<!--|<)`)
-)
-
-func normalizeMJMLReference(html string) string {
-	normalized := html
-
-	// Merge split MSO conditionals of the form:
-	// <!--[if mso | IE]><table ...><tr><![endif]-->\n<!-- [if mso | IE]><td ...><![endif]-->
-	// into the modern MJML style: <!--[if mso | IE]><table ...><tr><td ...><![endif]-->
-	msoSplitPattern := regexp.MustCompile(
-		`<!--\[if mso \| IE\]><table([^>]*)><tr><!\[endif\]-->\s*<!--\[if mso \| IE\]><td([^>]*)><!\[endif\]-->`,
-	)
-	normalized = msoSplitPattern.ReplaceAllStringFunc(normalized, func(match string) string {
-		submatches := msoSplitPattern.FindStringSubmatch(match)
-		if len(submatches) != 3 {
-			return match
-		}
-
-		tableAttrs := submatches[1]
-		tdAttrs := strings.TrimSpace(submatches[2])
-
-		if !strings.Contains(tdAttrs, "class=") {
-			if tdAttrs == "" {
-				tdAttrs = `class=""`
-			} else {
-				tdAttrs = `class="" ` + tdAttrs
-			}
-		}
-
-		if tdAttrs != "" {
-			tdAttrs = " " + tdAttrs
-		}
-
-		// Preserve attribute spacing but ensure we add a space before the closing angle
-		// bracket to match MJML's serialized output.
-		return fmt.Sprintf("<!--[if mso | IE]><table%s><tr><td%s ><![endif]-->", tableAttrs, tdAttrs)
-	})
-
-	// Merge split closing sequences: <!--[if mso | IE]></td><![endif]--><!--[if mso | IE]></tr></table><![endif]-->
-	msoClosePattern := regexp.MustCompile(
-		`<!--\[if mso \| IE\]>\s*</td>\s*<!\[endif\]-->\s*<!--\[if mso \| IE\]>\s*</tr>\s*</table>\s*<!\[endif\]-->`,
-	)
-	normalized = msoClosePattern.ReplaceAllString(normalized, "<!--[if mso | IE]></td></tr></table><![endif]-->")
-
-	// Ensure MSO tables include the empty class attribute MJML injects
-	// so comparisons are done against the same attribute set.
-	msoTableOpenPattern := regexp.MustCompile(`<!--\[if mso \| IE\]><table([^>]*)>`)
-	normalized = msoTableOpenPattern.ReplaceAllStringFunc(normalized, func(match string) string {
-		if strings.Contains(match, "class=") {
-			return match
-		}
-
-		if idx := strings.Index(match, `cellspacing="0"`); idx != -1 {
-			insertPos := idx + len(`cellspacing="0"`)
-			return match[:insertPos] + ` class=""` + match[insertPos:]
-		}
-
-		if idx := strings.Index(match, `role="presentation"`); idx != -1 {
-			return match[:idx] + ` class="" ` + match[idx:]
-		}
-
-		return strings.Replace(match, "<table", "<table class=\"\"", 1)
-	})
-
-	// Remove empty <style> tags that MRML used to inject but MJML omits.
-	emptyStylePattern := regexp.MustCompile(`(?is)<style[^>]*>\s*</style>`)
-	normalized = emptyStylePattern.ReplaceAllString(normalized, "")
-
-	// Ensure root wrapper div contains the accessibility attributes MJML outputs
-	rootDivPattern := regexp.MustCompile(`<body([^>]*)><div([^>]*)>`)
-	normalized = rootDivPattern.ReplaceAllStringFunc(normalized, func(match string) string {
-		submatches := rootDivPattern.FindStringSubmatch(match)
-		if len(submatches) != 3 {
-			return match
-		}
-
-		bodyAttrs := submatches[1]
-		divAttrs := submatches[2]
-
-		attrRe := regexp.MustCompile(`([a-zA-Z0-9:-]+)="([^"]*)"`)
-		matches := attrRe.FindAllStringSubmatch(divAttrs, -1)
-		attrMap := make(map[string]string, len(matches))
-		keys := make([]string, 0, len(matches))
-		for _, m := range matches {
-			attrMap[m[1]] = m[2]
-			keys = append(keys, m[1])
-		}
-
-		if _, exists := attrMap["aria-roledescription"]; !exists {
-			attrMap["aria-roledescription"] = "email"
-			keys = append(keys, "aria-roledescription")
-		}
-		if _, exists := attrMap["role"]; !exists {
-			attrMap["role"] = "article"
-			keys = append(keys, "role")
-		}
-
-		sort.Strings(keys)
-
-		var b strings.Builder
-		for _, key := range keys {
-			b.WriteString(" ")
-			b.WriteString(key)
-			b.WriteString(`="`)
-			b.WriteString(attrMap[key])
-			b.WriteString(`"`)
-		}
-
-		return fmt.Sprintf("<body%s><div%s>", bodyAttrs, b.String())
-	})
-
-	// Normalize moustache templating markers so trailing whitespace produced by
-	// legacy MRML fixtures doesn't cause mismatches. MJML trims raw content,
-	// so remove any whitespace directly surrounding templating blocks when
-	// they abut HTML tags or conditional comments.
-	normalized = mustacheAfterClosingPattern.ReplaceAllString(normalized, "$1$2")
-	normalized = mustacheBeforeOpeningPattern.ReplaceAllString(normalized, "$1$2")
-
-	// Normalize viewport meta spacing differences (remove spaces after commas)
-	viewportPattern := regexp.MustCompile(`(<meta[^>]*name="viewport"[^>]*content=")([^"]*)(")`)
-	normalized = viewportPattern.ReplaceAllStringFunc(normalized, func(match string) string {
-		submatches := viewportPattern.FindStringSubmatch(match)
-		if len(submatches) != 4 {
-			return match
-		}
-
-		cleaned := strings.ReplaceAll(submatches[2], ", ", ",")
-		return submatches[1] + cleaned + submatches[3]
-	})
-
-	return normalized
-}
-
-// extractMSOSequences extracts sequences of MSO conditional comments and adjacent HTML elements
-// for comparison of ordering differences that DOM parsing would normalize away
-func extractMSOSequences(html string) []string {
-	// Pattern to match MSO conditional blocks with their surrounding content
-	// This captures MSO conditionals and the next few HTML elements following them
-	re := regexp.MustCompile(`<!--\[if[^>]*>[\s\S]*?<!\[endif\]-->`)
-	matches := re.FindAllString(html, -1)
-
-	var sequences []string
-	whitespace := regexp.MustCompile(`\s+`)
-	for _, match := range matches {
-		// Normalize whitespace for comparison and canonicalize attribute ordering
-		normalized := whitespace.ReplaceAllString(strings.TrimSpace(match), " ")
-		normalized = canonicalizeMSOBlock(normalized)
-		if normalized != "" {
-			sequences = append(sequences, normalized)
-		}
-	}
-
-	return sequences
-}
-
-// canonicalizeMSOBlock sorts attributes inside MSO table/td tags so that
-// serialization differences (like attribute ordering) do not trigger
-// false-positive mismatches.
-func canonicalizeMSOBlock(block string) string {
-	canonical := canonicalizeTagAttributes(block, "table")
-	canonical = canonicalizeTagAttributes(canonical, "td")
-	return canonical
-}
-
-func canonicalizeTagAttributes(block, tag string) string {
-	pattern := fmt.Sprintf(`<%s([^>]*)>`, tag)
-	re := regexp.MustCompile(pattern)
-
-	return re.ReplaceAllStringFunc(block, func(tagStr string) string {
-		attrRe := regexp.MustCompile(`([a-zA-Z0-9:-]+)="([^"]*)"`)
-		matches := attrRe.FindAllStringSubmatch(tagStr, -1)
-		if len(matches) == 0 {
-			return tagStr
-		}
-
-		attrMap := make(map[string]string, len(matches))
-		keys := make([]string, 0, len(matches))
-		for _, m := range matches {
-			attrMap[m[1]] = m[2]
-			keys = append(keys, m[1])
-		}
-		sort.Strings(keys)
-
-		var b strings.Builder
-		b.WriteString("<")
-		b.WriteString(tag)
-		for _, key := range keys {
-			b.WriteString(" ")
-			b.WriteString(key)
-			b.WriteString(`="`)
-			b.WriteString(attrMap[key])
-			b.WriteString(`"`)
-		}
-		b.WriteString(">")
-		return b.String()
-	})
-}
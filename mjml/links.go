@@ -0,0 +1,104 @@
+package mjml
+
+import (
+	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml/styles"
+	"golang.org/x/net/html"
+)
+
+// LinkInfo describes a URL referenced by an MJML document.
+type LinkInfo struct {
+	URL       string // Attribute value as written in the template
+	Attribute string // Attribute holding the URL, such as "href" or "src"
+	Component string // Nearest enclosing MJML tag, such as "mj-button" or "mj-text"
+	Element   string // Tag carrying the attribute; differs from Component for HTML inside ending tags
+	Line      int    // Source line of Element, or 0 when the parser did not record it
+}
+
+// linkAttributes lists the attributes that hold URLs on MJML and HTML elements.
+var linkAttributes = map[string]struct{}{
+	"href":               {},
+	"src":                {},
+	"background-url":     {},
+	"icon-wrapped-url":   {},
+	"icon-unwrapped-url": {},
+	"left-icon":          {},
+	"right-icon":         {},
+	"thumbnails-src":     {},
+}
+
+// ExtractLinks returns every URL referenced by ast in document order, including
+// links in the HTML content of ending tags such as mj-text and mj-raw. Values
+// are reported as written, without mj-attributes or component defaults, and are
+// not deduplicated, so callers can validate or rewrite them (for example to add
+// click tracking) before rendering.
+func ExtractLinks(ast *MJMLNode) []LinkInfo {
+	var links []LinkInfo
+	collectLinks(ast, "", &links)
+	return links
+}
+
+func collectLinks(node *MJMLNode, component string, links *[]LinkInfo) {
+	if node == nil {
+		return
+	}
+
+	tagName := node.GetTagName()
+	if tagName == "mjml" || strings.HasPrefix(tagName, "mj-") {
+		component = tagName
+	}
+
+	for _, attr := range node.Attrs {
+		if _, ok := linkAttributes[attr.Name.Local]; ok && attr.Value != "" && !styles.IsGradient(attr.Value) {
+			*links = append(*links, LinkInfo{
+				URL:       attr.Value,
+				Attribute: attr.Name.Local,
+				Component: component,
+				Element:   tagName,
+				Line:      node.LineNumber,
+			})
+		}
+	}
+
+	if strings.Contains(node.Text, "<") {
+		collectHTMLLinks(node.Text, component, node.LineNumber, links)
+	}
+
+	for _, child := range node.Children {
+		collectLinks(child, component, links)
+	}
+}
+
+// collectHTMLLinks reports href and src attributes found in the unparsed HTML
+// content of an ending tag. Lines are counted from startLine, the line of the
+// ending tag itself, and stay 0 when that is unknown.
+func collectHTMLLinks(fragment, component string, startLine int, links *[]LinkInfo) {
+	line := startLine
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return
+		}
+		tokenLine := line
+		if startLine > 0 {
+			line += strings.Count(string(z.Raw()), "\n")
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+		tok := z.Token()
+		for _, attr := range tok.Attr {
+			if (attr.Key == "href" || attr.Key == "src") && attr.Val != "" {
+				*links = append(*links, LinkInfo{
+					URL:       attr.Val,
+					Attribute: attr.Key,
+					Component: component,
+					Element:   tok.Data,
+					Line:      tokenLine,
+				})
+			}
+		}
+	}
+}
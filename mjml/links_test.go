@@ -0,0 +1,46 @@
+package mjml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractLinks(t *testing.T) {
+	const doc = `<mjml>
+<mj-head>
+<mj-font name="Raleway" href="https://fonts.example.com/raleway.css" />
+</mj-head>
+<mj-body>
+<mj-section background-url="https://cdn.example.com/bg.png">
+<mj-column>
+<mj-image src="https://cdn.example.com/logo.png" href="https://example.com/home" />
+<mj-text align="left">Read the
+<a href="https://example.com/blog">blog</a> today</mj-text>
+<mj-button href="https://example.com/buy">Buy</mj-button>
+<mj-social><mj-social-element name="github" href="https://github.com/example">GitHub</mj-social-element></mj-social>
+<mj-raw><p><a href="https://example.com/raw">raw</a></p></mj-raw>
+</mj-column>
+</mj-section>
+</mj-body>
+</mjml>`
+
+	ast, err := parseAST(doc, false)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	got := ExtractLinks(ast)
+	want := []LinkInfo{
+		{URL: "https://fonts.example.com/raleway.css", Attribute: "href", Component: "mj-font", Element: "mj-font", Line: 3},
+		{URL: "https://cdn.example.com/bg.png", Attribute: "background-url", Component: "mj-section", Element: "mj-section", Line: 6},
+		{URL: "https://cdn.example.com/logo.png", Attribute: "src", Component: "mj-image", Element: "mj-image", Line: 8},
+		{URL: "https://example.com/home", Attribute: "href", Component: "mj-image", Element: "mj-image", Line: 8},
+		{URL: "https://example.com/blog", Attribute: "href", Component: "mj-text", Element: "a", Line: 10},
+		{URL: "https://example.com/buy", Attribute: "href", Component: "mj-button", Element: "mj-button", Line: 11},
+		{URL: "https://github.com/example", Attribute: "href", Component: "mj-social-element", Element: "mj-social-element", Line: 12},
+		{URL: "https://example.com/raw", Attribute: "href", Component: "mj-raw", Element: "a", Line: 13},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ExtractLinks mismatch\n got: %+v\nwant: %+v", got, want)
+	}
+}
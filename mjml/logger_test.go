@@ -0,0 +1,38 @@
+package mjml
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWithLogger(t *testing.T) {
+	input := `<mjml><mj-body><mj-section><mj-column>
+  <mj-text>Hello</mj-text>
+  <mj-foo>ignored</mj-foo>
+</mj-column></mj-section></mj-body></mjml>`
+
+	var debugLog bytes.Buffer
+	handler := slog.NewTextHandler(&debugLog, &slog.HandlerOptions{Level: slog.LevelDebug})
+	if _, err := Render(input, WithLogger(handler)); err == nil {
+		t.Fatal("expected a validation error for the unknown tag")
+	}
+	for _, want := range []string{
+		`level=DEBUG msg="Starting MJML rendering" component=mjml phase=render-start content_length=`,
+		`level=DEBUG msg="Starting text component rendering" component=mj-text phase=render-start`,
+		`level=DEBUG msg="Fonts tracked from components" component=font-detection phase=component-tracking`,
+		`level=WARN msg="Unknown component type" component=component phase=create-error error="unknown component: mj-foo"`,
+	} {
+		if !strings.Contains(debugLog.String(), want) {
+			t.Errorf("log missing %q:\n%s", want, debugLog.String())
+		}
+	}
+
+	var warnLog bytes.Buffer
+	handler = slog.NewTextHandler(&warnLog, &slog.HandlerOptions{Level: slog.LevelWarn})
+	_, _ = Render(input, WithLogger(handler))
+	if got := strings.Count(warnLog.String(), "\n"); got != 1 || !strings.Contains(warnLog.String(), "level=WARN") {
+		t.Errorf("expected only the warning at warning level, got:\n%s", warnLog.String())
+	}
+}
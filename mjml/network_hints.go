@@ -0,0 +1,109 @@
+package mjml
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml/fonts"
+	"github.com/preslavrachev/gomjml/mjml/options"
+)
+
+// ExternalResources is an alias for convenience
+type ExternalResources = options.ExternalResources
+
+// Handling of references to external hosts
+const (
+	ExternalResourcesDefault = options.ExternalResourcesDefault
+	ExternalResourcesHinted  = options.ExternalResourcesHinted
+	ExternalResourcesOffline = options.ExternalResourcesOffline
+)
+
+// googleFontsCSSOrigin serves the Google Fonts stylesheets, which load the font
+// files from googleFontsFileOrigin.
+const (
+	googleFontsCSSOrigin  = "https://fonts.googleapis.com"
+	googleFontsFileOrigin = "https://fonts.gstatic.com"
+)
+
+// WithExternalResources controls the references to external hosts the renderer
+// adds to the head:
+//
+//   - ExternalResourcesDefault (the default) imports web fonts like MJML.
+//   - ExternalResourcesHinted also writes <link rel="preconnect"> and
+//     <link rel="dns-prefetch"> hints for the hosts of imported fonts and of
+//     the images and backgrounds in the body, so clients that honor them can
+//     open the connections early.
+//   - ExternalResourcesOffline leaves out the web font imports, for
+//     environments where the email must not load anything the document does
+//     not reference itself. Font stacks fall back to their system fonts; image
+//     URLs in the document are left alone.
+func WithExternalResources(mode ExternalResources) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.ExternalResources = mode
+	}
+}
+
+// buildNetworkHints returns preconnect and dns-prefetch links for the origins
+// of fontURLs, of the font files of fontFaces and of the src and background
+// attributes in body.
+func buildNetworkHints(fontURLs []string, fontFaces []fonts.FontFace, body string) string {
+	var origins []string
+	for _, fontURL := range fontURLs {
+		origins = appendOrigin(origins, fontURL)
+	}
+	var fileOrigins []string
+	if slices.Contains(origins, googleFontsCSSOrigin) {
+		fileOrigins = appendOrigin(fileOrigins, googleFontsFileOrigin)
+	}
+	for _, face := range fontFaces {
+		for _, fontURL := range face.URLs {
+			fileOrigins = appendOrigin(fileOrigins, fontURL)
+		}
+	}
+	for _, origin := range fileOrigins {
+		origins = appendOrigin(origins, origin)
+	}
+	for _, attr := range []string{` src="`, ` background="`} {
+		for rest := body; ; {
+			i := strings.Index(rest, attr)
+			if i < 0 {
+				break
+			}
+			rest = rest[i+len(attr):]
+			end := strings.IndexByte(rest, '"')
+			if end < 0 {
+				break
+			}
+			origins = appendOrigin(origins, rest[:end])
+			rest = rest[end:]
+		}
+	}
+
+	var hints strings.Builder
+	for _, origin := range origins {
+		// Font files are fetched in CORS mode, so their connection must be
+		// opened in the same mode to be reused.
+		crossOrigin := ""
+		if slices.Contains(fileOrigins, origin) {
+			crossOrigin = " crossorigin"
+		}
+		hints.WriteString(`<link rel="preconnect" href="` + origin + `"` + crossOrigin + `>`)
+		hints.WriteString(`<link rel="dns-prefetch" href="` + origin + `">`)
+	}
+	return hints.String()
+}
+
+// appendOrigin appends the scheme and host of an absolute http(s) URL to
+// origins unless it is already there.
+func appendOrigin(origins []string, rawURL string) []string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return origins
+	}
+	origin := u.Scheme + "://" + u.Host
+	if slices.Contains(origins, origin) {
+		return origins
+	}
+	return append(origins, origin)
+}
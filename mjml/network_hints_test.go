@@ -0,0 +1,52 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+const networkHintsDocument = `<mjml><mj-body>
+  <mj-section background-url="https://cdn.example.com/bg.png"><mj-column>
+    <mj-text font-family="Roboto, sans-serif">Hello</mj-text>
+    <mj-image src="https://images.example.org/logo.png?v=1&amp;w=2" />
+    <mj-image src="https://images.example.org/hero.png" />
+    <mj-image src="/relative.png" />
+  </mj-column></mj-section>
+</mj-body></mjml>`
+
+func TestWithExternalResources(t *testing.T) {
+	plain, err := Render(networkHintsDocument)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(plain, `rel="preconnect"`) || strings.Contains(plain, `rel="dns-prefetch"`) {
+		t.Error("default output should not contain network hints")
+	}
+
+	hinted, err := Render(networkHintsDocument, WithExternalResources(ExternalResourcesHinted))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantHints := `<link rel="preconnect" href="https://fonts.googleapis.com"><link rel="dns-prefetch" href="https://fonts.googleapis.com">` +
+		`<link rel="preconnect" href="https://fonts.gstatic.com" crossorigin><link rel="dns-prefetch" href="https://fonts.gstatic.com">` +
+		`<link rel="preconnect" href="https://cdn.example.com"><link rel="dns-prefetch" href="https://cdn.example.com">` +
+		`<link rel="preconnect" href="https://images.example.org"><link rel="dns-prefetch" href="https://images.example.org">` +
+		`<!--[if !mso]><!--><link href="https://fonts.googleapis.com/css?family=Roboto`
+	if !strings.Contains(hinted, wantHints) {
+		t.Errorf("hinted output missing hints before the font imports:\n%s", hinted)
+	}
+	if strings.Replace(hinted, wantHints, `<!--[if !mso]><!--><link href="https://fonts.googleapis.com/css?family=Roboto`, 1) != plain {
+		t.Error("hints should be the only difference from the default output")
+	}
+
+	offline, err := Render(networkHintsDocument, WithExternalResources(ExternalResourcesOffline))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(offline, "fonts.googleapis.com") || strings.Contains(offline, "@import") {
+		t.Error("offline output should not import web fonts")
+	}
+	if !strings.Contains(offline, `src="https://images.example.org/hero.png"`) {
+		t.Error("offline output should keep the document's images")
+	}
+}
@@ -1,12 +1,35 @@
 // Package options contains render options for MJML components
 package options
 
-import "sync"
+import (
+	"container/list"
+	"io"
+	"io/fs"
+	"sync"
+	"time"
+
+	"github.com/preslavrachev/gomjml/mjml/cssmatch"
+	"github.com/preslavrachev/gomjml/mjml/debug"
+	"github.com/preslavrachev/gomjml/mjml/globals"
+	"github.com/preslavrachev/gomjml/mjml/html"
+	"github.com/preslavrachev/gomjml/mjml/sanitize"
+	"github.com/preslavrachev/gomjml/parser"
+	nethtml "golang.org/x/net/html"
+)
 
 // FontTracker tracks font families used by components during rendering
 type FontTracker struct {
 	mu    sync.Mutex
 	fonts map[string]bool // Set of unique font families
+	order []string        // Font families in first-use order
+
+	recordings []*fontRecording // Active recordings, innermost last
+}
+
+// fontRecording collects the font families added while it is active
+type fontRecording struct {
+	seen  map[string]bool
+	fonts []string // In first-use order
 }
 
 // NewFontTracker creates a new font tracker
@@ -24,43 +47,494 @@ func (ft *FontTracker) AddFont(fontFamily string) {
 
 	ft.mu.Lock()
 	defer ft.mu.Unlock()
-	ft.fonts[fontFamily] = true
+	for _, r := range ft.recordings {
+		if !r.seen[fontFamily] {
+			r.seen[fontFamily] = true
+			r.fonts = append(r.fonts, fontFamily)
+		}
+	}
+	if !ft.fonts[fontFamily] {
+		ft.fonts[fontFamily] = true
+		ft.order = append(ft.order, fontFamily)
+	}
 }
 
-// GetFonts returns all tracked font families as a slice
+// GetFonts returns all tracked font families in the order they were first used
 func (ft *FontTracker) GetFonts() []string {
 	ft.mu.Lock()
 	defer ft.mu.Unlock()
 
-	fonts := make([]string, 0, len(ft.fonts))
-	for font := range ft.fonts {
-		fonts = append(fonts, font)
-	}
+	fonts := make([]string, len(ft.order))
+	copy(fonts, ft.order)
 	return fonts
 }
 
+// Record starts collecting the font families added to the tracker, including
+// ones it already tracks, until the returned function is called. That function
+// returns the collected families in first-use order. Recordings may nest, as
+// when a cached fragment renders inside a reused block; each collects the
+// families added while it is active.
+func (ft *FontTracker) Record() (stop func() []string) {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	r := &fontRecording{seen: make(map[string]bool)}
+	ft.recordings = append(ft.recordings, r)
+
+	return func() []string {
+		ft.mu.Lock()
+		defer ft.mu.Unlock()
+		for i, active := range ft.recordings {
+			if active == r {
+				ft.recordings = append(ft.recordings[:i], ft.recordings[i+1:]...)
+				break
+			}
+		}
+		return r.fonts
+	}
+}
+
+// OutputFormat selects the markup dialect produced by the renderer
+type OutputFormat int
+
+const (
+	// FormatHTML produces classic email HTML with MSO/Outlook support (default)
+	FormatHTML OutputFormat = iota
+	// FormatAMP produces AMP for Email (AMP4Email) compliant markup
+	FormatAMP
+)
+
+// ValidationLevel controls how invalid attributes and unknown tags are handled,
+// mirroring the validationLevel option of the MJML reference implementation
+type ValidationLevel int
+
+const (
+	// ValidationSoft renders the document and reports problems alongside it (default)
+	ValidationSoft ValidationLevel = iota
+	// ValidationSkip renders the document without validating it
+	ValidationSkip
+	// ValidationStrict fails on the first problem without rendering
+	ValidationStrict
+)
+
+// String returns the MJML name of the level ("soft", "skip" or "strict")
+func (l ValidationLevel) String() string {
+	switch l {
+	case ValidationSkip:
+		return "skip"
+	case ValidationStrict:
+		return "strict"
+	default:
+		return "soft"
+	}
+}
+
+// TextEscaping controls how non-ASCII characters are written to the output
+type TextEscaping int
+
+const (
+	// EscapeUTF8 writes non-ASCII characters as UTF-8 (default)
+	EscapeUTF8 TextEscaping = iota
+	// EscapeNonASCII writes non-ASCII characters as numeric character references
+	EscapeNonASCII
+)
+
+// InvisibleCharacters controls how no-break spaces and zero-width characters
+// are written to the output
+type InvisibleCharacters int
+
+const (
+	// InvisibleAsRendered keeps them as the components write them, matching
+	// MJML (default)
+	InvisibleAsRendered InvisibleCharacters = iota
+	// InvisibleRaw writes them as UTF-8 characters
+	InvisibleRaw
+	// InvisibleNumeric writes them as numeric character references
+	InvisibleNumeric
+	// InvisibleNamed writes them as named character references where HTML
+	// defines one, and as numeric references otherwise
+	InvisibleNamed
+)
+
+// OfficeNamespaces controls the VML and Office XML namespaces declared on the
+// html element
+type OfficeNamespaces int
+
+const (
+	// OfficeNamespacesAlways declares both namespaces, like MJML (default)
+	OfficeNamespacesAlways OfficeNamespaces = iota
+	// OfficeNamespacesAuto declares the VML namespace only when the body
+	// contains VML
+	OfficeNamespacesAuto
+	// OfficeNamespacesOmit declares neither namespace
+	OfficeNamespacesOmit
+)
+
+// CommentMode controls which HTML comments from the MJML source are kept
+type CommentMode int
+
+const (
+	// CommentsPreserve keeps all comments (default)
+	CommentsPreserve CommentMode = iota
+	// CommentsStrip removes all comments except conditional comments
+	CommentsStrip
+	// CommentsRawOnly keeps comments inside mj-raw and removes the others
+	CommentsRawOnly
+)
+
+// Compatibility selects the reference implementation whose output is matched
+// byte for byte where the two differ
+type Compatibility int
+
+const (
+	// CompatMRML matches MRML, the Rust implementation (default)
+	CompatMRML Compatibility = iota
+	// CompatMJML4 matches the MJML 4 JavaScript implementation
+	CompatMJML4
+)
+
+// String returns the name of the reference implementation ("mrml" or "mjml4")
+func (c Compatibility) String() string {
+	if c == CompatMJML4 {
+		return "mjml4"
+	}
+	return "mrml"
+}
+
+// Features lists the output behaviors that differ between MJML releases. The
+// zero value matches the latest release
+type Features struct {
+	// OmitRootAccessibility leaves out the role, aria-roledescription,
+	// aria-label, lang and dir attributes of the body div and the default
+	// dir="auto" of the html element, which MJML 4.15 added
+	OmitRootAccessibility bool
+}
+
+// Client identifies an email client family with known rendering quirks
+type Client int
+
+const (
+	// ClientOutlookDesktop is Outlook for Windows, which renders with Microsoft Word
+	ClientOutlookDesktop Client = iota
+	// ClientOutlookWeb is Outlook.com and Outlook on the web (Office 365)
+	ClientOutlookWeb
+	// ClientAppleMail is Apple Mail on macOS and iOS
+	ClientAppleMail
+)
+
+// String returns a short name of the client
+func (c Client) String() string {
+	switch c {
+	case ClientOutlookDesktop:
+		return "outlook-desktop"
+	case ClientOutlookWeb:
+		return "outlook-web"
+	case ClientAppleMail:
+		return "apple-mail"
+	default:
+		return "unknown"
+	}
+}
+
+// ExternalResources controls the references to external hosts the renderer
+// adds to the head
+type ExternalResources int
+
+const (
+	// ExternalResourcesDefault imports web fonts like MJML (default)
+	ExternalResourcesDefault ExternalResources = iota
+	// ExternalResourcesHinted also adds preconnect and dns-prefetch hints for
+	// the font and image hosts of the document
+	ExternalResourcesHinted
+	// ExternalResourcesOffline leaves out web font imports
+	ExternalResourcesOffline
+)
+
+// HeadPosition selects where in the head markup added with AddHeadHTML is
+// written
+type HeadPosition int
+
+const (
+	// HeadStart writes markup right after <head>, before the title
+	HeadStart HeadPosition = iota
+	// HeadAfterMeta writes markup after the meta tags of the renderer, before
+	// the styles and font imports
+	HeadAfterMeta
+	// HeadEnd writes markup right before </head>, after the mj-raw content of
+	// mj-head
+	HeadEnd
+)
+
+// HeadHTML is markup added to the head of the document at Position
+type HeadHTML struct {
+	Position HeadPosition
+	HTML     string
+}
+
+// ImageSizer returns the intrinsic width and height in pixels of the image at
+// src, with ok false when they are unknown
+type ImageSizer func(src string) (width, height int, ok bool)
+
+// ImageResizer returns the URL of the image at src scaled to width pixels,
+// typically by adding the resize parameters of an image CDN
+type ImageResizer func(src string, width int) string
+
+// InlineImageMode selects how image sources are embedded in the document
+type InlineImageMode int
+
+const (
+	// InlineImagesOff keeps image sources as they are (default)
+	InlineImagesOff InlineImageMode = iota
+	// InlineImagesCID replaces image sources with cid: references to attachments
+	InlineImagesCID
+	// InlineImagesDataURI replaces image sources with base64 data URIs
+	InlineImagesDataURI
+)
+
+// AssetLoader returns the content and MIME type of the asset at src. An empty
+// content type is detected from the data; nil data and a nil error keep src as
+// it is.
+type AssetLoader func(src string) (data []byte, contentType string, err error)
+
+// Attachment is an image referenced from the document by its Content-ID
+type Attachment struct {
+	ContentID   string // Content-ID without angle brackets, referenced as cid:ContentID
+	Filename    string // File name taken from the source path
+	ContentType string // MIME type of Data
+	Data        []byte // Content returned by the AssetLoader
+	Source      string // Image source the attachment replaces
+}
+
+// ImageInliner rewrites image sources to embedded images and collects the
+// attachments they reference
+type ImageInliner interface {
+	// Inline returns the source that replaces src: a cid: reference or a
+	// data URI, or src itself when it stays remote
+	Inline(src string) (string, error)
+	// Add registers an attachment that was used by markup rendered earlier,
+	// such as a block reused by an incremental render
+	Add(attachment Attachment)
+	// Attachments returns the attachments referenced from the document in
+	// the order they were first used
+	Attachments() []Attachment
+	// Record starts collecting the attachments used through the inliner until
+	// the returned function is called, which returns them in first-use order
+	Record() (stop func() []Attachment)
+}
+
+// Fragment is the markup of a static component together with the font
+// families it uses, which are tracked again when the markup is reused
+type Fragment struct {
+	Markup string
+	Fonts  []string
+}
+
+// FragmentCacheStats is a snapshot of the fragment cache counters and size
+type FragmentCacheStats struct {
+	Hits      uint64 // Components written from cached markup
+	Misses    uint64 // Components rendered because their markup was not cached
+	Templates int    // Templates with cached fragments
+	Fragments int    // Fragments cached across all templates
+}
+
+// maxFragmentsPerTemplate bounds the fragments kept for one template, so static
+// components whose content changes on every render cannot grow it without limit
+const maxFragmentsPerTemplate = 1024
+
+// FragmentCache keeps the markup of static components across renders, grouped
+// by the hash of the template they belong to. When more templates than the
+// limit have fragments, the fragments of the least recently rendered template
+// are dropped together. A FragmentCache is safe for concurrent use.
+type FragmentCache struct {
+	mu           sync.Mutex
+	maxTemplates int
+	order        *list.List               // front is most recently used; values are *templateFragments
+	templates    map[uint64]*list.Element // template hash -> element in order
+	fragments    int
+	hits         uint64
+	misses       uint64
+}
+
+// templateFragments holds the fragments of one template by component key
+type templateFragments struct {
+	hash      uint64
+	fragments map[uint64]Fragment
+}
+
+// NewFragmentCache creates a fragment cache holding the fragments of up to
+// maxTemplates templates. A limit of zero or less keeps every template.
+func NewFragmentCache(maxTemplates int) *FragmentCache {
+	return &FragmentCache{
+		maxTemplates: maxTemplates,
+		order:        list.New(),
+		templates:    make(map[uint64]*list.Element),
+	}
+}
+
+// Get returns the fragment cached for the component key of a template
+func (c *FragmentCache) Get(template, key uint64) (Fragment, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.templates[template]; ok {
+		c.order.MoveToFront(el)
+		if fragment, ok := el.Value.(*templateFragments).fragments[key]; ok {
+			c.hits++
+			return fragment, true
+		}
+	}
+	c.misses++
+	return Fragment{}, false
+}
+
+// Add caches the fragment for the component key of a template
+func (c *FragmentCache) Add(template, key uint64, fragment Fragment) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.templates[template]
+	if !ok {
+		el = c.order.PushFront(&templateFragments{hash: template, fragments: make(map[uint64]Fragment)})
+		c.templates[template] = el
+		for c.maxTemplates > 0 && c.order.Len() > c.maxTemplates {
+			oldest := c.order.Remove(c.order.Back()).(*templateFragments)
+			delete(c.templates, oldest.hash)
+			c.fragments -= len(oldest.fragments)
+		}
+	}
+	entry := el.Value.(*templateFragments)
+	if _, exists := entry.fragments[key]; !exists {
+		if len(entry.fragments) >= maxFragmentsPerTemplate {
+			return
+		}
+		c.fragments++
+	}
+	entry.fragments[key] = fragment
+}
+
+// Stats returns the current fragment cache statistics
+func (c *FragmentCache) Stats() FragmentCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return FragmentCacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Templates: c.order.Len(),
+		Fragments: c.fragments,
+	}
+}
+
+// Reset drops every cached fragment and clears the counters
+func (c *FragmentCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	clear(c.templates)
+	c.fragments = 0
+	c.hits = 0
+	c.misses = 0
+}
+
+// IncludeAttributes holds the mj-attributes, mj-class and default attributes
+// of each isolated include, keyed by the AttributeScope of its content
+type IncludeAttributes map[*parser.MJMLNode]*globals.GlobalAttributes
+
+// BlockRenderer writes the top-level body block at index to w. It calls render
+// to produce the block's markup, or writes markup it kept from an earlier
+// render of the same block.
+type BlockRenderer func(index int, w io.StringWriter, render func(io.StringWriter) error) error
+
+// BlockHandler receives the markup of the top-level body block at index as
+// soon as it is rendered. An error aborts the render.
+type BlockHandler func(index int, tagName string, line int, markup string) error
+
 // RenderOpts contains options for MJML rendering
 type RenderOpts struct {
-	DebugTags                bool                     // Whether to include debug attributes in output
-	InsideGroup              bool                     // Whether the component is being rendered inside a group
-	InsideHero               bool                     // Whether the component is being rendered inside a hero
-	InsideWrapper            bool                     // Whether the component is being rendered inside a wrapper
-	GroupColumnCount         int                      // Number of columns in the current group context (0 when not inside a group)
-	FontTracker              *FontTracker             // Tracks fonts used during rendering
-	UseCache                 bool                     // Whether to enable AST caching
-	Lang                     string                   // Language attribute from root MJML element
-	Title                    string                   // Document title extracted from <mj-title>
-	InlineClassStyles        map[string][]InlineStyle // CSS declarations to inline for css-class selectors
-	SkipInlineStylesInHead   bool                     // Whether to omit inline mj-style rules from the head output
-	PendingMSOSectionClose   bool                     // Indicates an Outlook conditional comment is still open for section chaining
-	RemainingBodySections    int                      // Remaining Outlook-sensitive blocks (mj-section/mj-wrapper) after the current one
-	RequireEmptyStyleTag     bool                     // Whether the head output should include an empty style tag for Outlook parity
+	DebugTags                bool                      // Whether to include debug attributes in output
+	InsideGroup              bool                      // Whether the component is being rendered inside a group
+	InsideHero               bool                      // Whether the component is being rendered inside a hero
+	InsideWrapper            bool                      // Whether the component is being rendered inside a wrapper
+	GroupColumnCount         int                       // Number of columns in the current group context (0 when not inside a group)
+	FontTracker              *FontTracker              // Tracks fonts used during rendering
+	UseCache                 bool                      // Whether to enable AST caching
+	Lang                     string                    // Language attribute from root MJML element
+	Dir                      string                    // Text direction from root MJML element; "rtl" cascades to body blocks
+	Features                 Features                  // Output behaviors of the MJML release matched, resolved from the version
+	Title                    string                    // Document title extracted from <mj-title>
+	InlineRules              []cssmatch.Rule           // Rules from inline mj-style blocks, in stylesheet order
+	GlobalAttributes         *globals.GlobalAttributes // mj-attributes, mj-class and default attributes of the document being rendered
+	IncludeAttributes        IncludeAttributes         // Attributes of isolated includes, by the AttributeScope of their content
+	SkipInlineStylesInHead   bool                      // Whether to omit inline mj-style rules from the head output
+	PendingMSOSectionClose   bool                      // Indicates an Outlook conditional comment is still open for section chaining
+	RemainingBodySections    int                       // Remaining Outlook-sensitive blocks (mj-section/mj-wrapper) after the current one
+	RequireEmptyStyleTag     bool                      // Whether the head output should include an empty style tag for MRML parity
 	InvalidAttributeReporter func(tagName, attrName string, line int)
+	InvalidTagReporter       func(tagName string, line int)
+	UnsafeHrefReporter       func(tagName, href string, line int)
+	InvalidValueReporter     func(tagName, attrName, value string, problem error, line int)
+	ValidationLevel          ValidationLevel                       // How invalid attributes and unknown tags are handled
+	Sanitizer                *sanitize.Policy                      // Allowlist applied to embedded raw HTML (nil disables sanitization)
+	StripEventHandlers       bool                                  // Whether event handler attributes are removed from embedded raw HTML
+	StyleNonce               string                                // Nonce attribute added to every <style> tag (empty adds none)
+	OutputFormat             OutputFormat                          // Markup dialect to produce (classic HTML or AMP)
+	MarkupEscaping           html.EscapePolicy                     // How attribute values and text from the source are escaped
+	ComponentSizeReporter    func(tagName string, line, bytes int) // Receives output size of each top-level body block
+	BlockRenderer            BlockRenderer                         // Writes each top-level body block, e.g. from markup kept from a previous render
+	BlockHandler             BlockHandler                          // Receives the markup of each top-level body block as soon as it is rendered
+	HTMLTransforms           []func(doc *nethtml.Node) error       // Applied in order to the parsed final document
+	AfterRender              func(html string)                     // Invoked with the final rendered document
+	Logger                   debug.Logger                          // Receives debug events (the zero value logs only in debug builds)
+	Profiler                 func(component string, depth int, duration time.Duration, bytes int)
+	ProfileDepth             int    // Nesting depth of the component currently being profiled
+	SocialIconBaseURL        string // Replaces the MJML-hosted base URL of built-in mj-social-element icons
+	BaseURL                  string // Resolves relative URLs in attributes such as src, href and background-url
+	IDGenerator              func(component string, index int) string
+	IDCounters               map[string]int                // IDs handed out per component during the current render
+	ImageSizer               ImageSizer                    // Supplies intrinsic image sizes for mj-image heights left at auto
+	ImageResizer             ImageResizer                  // Builds the scaled image URLs of generated mj-image srcsets (nil disables them)
+	ResponsiveImageWidths    []int                         // Widths in pixels of the generated srcset candidates, unless set by srcset-widths
+	ImageInliner             ImageInliner                  // Rewrites image sources to attachments or data URIs (nil keeps them)
+	FragmentCache            *FragmentCache                // Keeps the markup of static components across renders (nil disables it)
+	TemplateHash             uint64                        // Identifies the mj-head and root attributes, scoping FragmentCache entries
+	OverrideTitle            string                        // Plain-text document title replacing mj-title (empty keeps the document's)
+	OverridePreview          string                        // Plain-text preheader replacing mj-preview (empty keeps the document's)
+	PreheaderPadding         int                           // Character count the preview text is padded to with &nbsp;&zwnj; (0 disables padding)
+	OverrideLang             string                        // Language replacing the mjml lang attribute (empty keeps the document's)
+	OverrideDir              string                        // Text direction replacing the mjml dir attribute (empty keeps the document's)
+	OverrideVersion          string                        // MJML release replacing the mjml version attribute (empty keeps the document's)
+	DefaultAttributes        map[string]map[string]string  // Attribute defaults applied beneath mj-attributes, keyed by tag name or "mj-all"
+	Variables                map[string]any                // Values that mj-cond test expressions are evaluated against
+	Includes                 fs.FS                         // Files mj-include paths are read from (nil ignores mj-include)
+	IsolateIncludes          bool                          // Whether included files and the including document keep their mj-attributes to themselves
+	Fonts                    map[string]string             // Web font URLs by font name, replacing the built-in Google Fonts (nil keeps them)
+	Translator               func(key, lang string) string // Resolves i18n-key attributes on mj-text and mj-button for the document language
+	AccessibilityChecks      bool                          // Whether accessibility problems are reported after rendering
+	AccessibilityAutoFix     bool                          // Whether missing table roles and image alt attributes are added instead of reported
+	HeroOverflowChecks       bool                          // Whether fixed-height heroes whose content may not fit are reported after rendering
+	SourceMap                bool                          // Whether component output ranges are recorded for a source map
+	OutlookVMLButtons        bool                          // Whether linked mj-buttons with a pixel width also render a VML roundrect for Outlook
+	TextLineHeightRule       bool                          // Whether mj-text line heights are enforced in Outlook desktop with mso-line-height-rule
+	ForceOWADesktop          bool                          // Whether Outlook on the web gets the desktop column layout through [owa] rules
+	DisableAppleReformatting bool                          // Whether the head asks Apple Mail not to rescale the message
+	OmitOutlookSupport       bool                          // Whether MSO conditional comments, VML and the Office namespaces are left out
+	MergeHeadStyles          bool                          // Whether head CSS is deduplicated and merged into one style tag
+	MinifyHeadStyles         bool                          // Whether merged head CSS is minified
+	RawPassthrough           bool                          // Whether mj-raw content is parsed and emitted byte for byte
+	Comments                 CommentMode                   // Which HTML comments from the source are kept
+	TextEscaping             TextEscaping                  // How non-ASCII characters are written to the output
+	InvisibleCharacters      InvisibleCharacters           // How no-break spaces and zero-width characters are written to the output
+	ThemeOverrides           map[string]string             // Replacement colors written to the output, keyed by the color they replace
+	Compatibility            Compatibility                 // Reference implementation matched where MRML and MJML 4 output differ
+	ExternalResources        ExternalResources             // Which references to font and image hosts are added to the head
+	Doctype                  string                        // Document type declaration written before the html element (empty writes <!doctype html>)
+	OfficeNamespaces         OfficeNamespaces              // Which of the VML and Office namespaces the html element declares
+	HeadHTML                 []HeadHTML                    // Markup added to the head, in the order it was added
+	BatchConcurrency         int                           // Documents RenderBatch renders at once (0 uses GOMAXPROCS)
 }
 
-// InlineStyle represents a CSS declaration parsed from an inline mj-style rule.
-// The order of declarations is preserved to match the MJML reference output.
-type InlineStyle struct {
-	Property string
-	Value    string
+// AddHeadHTML adds markup to the head of the rendered document at position,
+// after any markup added there before. The markup is written as it is,
+// without escaping or validation, so integrations can add meta tags, scripts
+// for the web view or blocks an ESP expects without mj-raw in the template.
+func (opts *RenderOpts) AddHeadHTML(position HeadPosition, markup string) {
+	opts.HeadHTML = append(opts.HeadHTML, HeadHTML{Position: position, HTML: markup})
 }
@@ -0,0 +1,41 @@
+package mjml
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	partsHeadSection = regexp.MustCompile(`(?s)<head>(.*?)</head>`)
+	partsBodySection = regexp.MustCompile(`(?s)<body[^>]*>(.*)</body>`)
+	partsTitleTag    = regexp.MustCompile(`(?s)<title>.*?</title>`)
+	partsMetaTag     = regexp.MustCompile(`<meta[^>]*>`)
+)
+
+// RenderParts renders the MJML document and returns the head and body markup
+// separately, for embedding the email inside an existing page (for example an
+// editor preview pane). head holds the font links and style blocks, including
+// the Outlook conditional ones; the document title and meta tags are dropped.
+// body holds the content of the <body> element. Like Render, attribute
+// validation problems are returned as an Error alongside the rendered parts.
+func RenderParts(mjmlContent string, opts ...RenderOption) (head, body string, err error) {
+	document, err := Render(mjmlContent, opts...)
+	if document == "" {
+		return "", "", err
+	}
+	head, body = splitDocument(document)
+	return head, body, err
+}
+
+// splitDocument extracts the embeddable head markup and the body content from
+// a rendered document.
+func splitDocument(document string) (head, body string) {
+	if match := partsHeadSection.FindStringSubmatch(document); match != nil {
+		head = partsTitleTag.ReplaceAllString(match[1], "")
+		head = strings.TrimSpace(partsMetaTag.ReplaceAllString(head, ""))
+	}
+	if match := partsBodySection.FindStringSubmatch(document); match != nil {
+		body = strings.TrimSpace(match[1])
+	}
+	return head, body
+}
@@ -0,0 +1,60 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderParts(t *testing.T) {
+	input := `<mjml>
+  <mj-head>
+    <mj-title>Preview</mj-title>
+    <mj-font name="Roboto" href="https://fonts.googleapis.com/css?family=Roboto" />
+    <mj-style>.highlight { color: #f45e43; }</mj-style>
+  </mj-head>
+  <mj-body background-color="#eeeeee">
+    <mj-section>
+      <mj-column>
+        <mj-text font-family="Roboto" css-class="highlight">Hello</mj-text>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	head, body, err := RenderParts(input)
+	if err != nil {
+		t.Fatalf("RenderParts() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`.highlight { color: #f45e43; }`,
+		`https://fonts.googleapis.com/css?family=Roboto`,
+		`.mj-column-per-100`,
+	} {
+		if !strings.Contains(head, want) {
+			t.Errorf("expected head to contain %q", want)
+		}
+	}
+	for _, unwanted := range []string{"<title>", "<meta", "<head>", "<body"} {
+		if strings.Contains(head, unwanted) || strings.Contains(body, unwanted) {
+			t.Errorf("expected %q to be stripped from the parts", unwanted)
+		}
+	}
+
+	if !strings.HasPrefix(body, `<div`) || !strings.HasSuffix(body, `</div>`) {
+		t.Errorf("expected body to be the content of the body element, got %q", body)
+	}
+	if !strings.Contains(body, "Hello") || !strings.Contains(body, "background-color:#eeeeee") {
+		t.Error("expected body content to include the rendered sections")
+	}
+}
+
+func TestRenderPartsParseError(t *testing.T) {
+	head, body, err := RenderParts(`<mjml><mj-body>`)
+	if err == nil {
+		t.Fatal("expected parse error")
+	}
+	if head != "" || body != "" {
+		t.Errorf("expected empty parts on error, got head=%q body=%q", head, body)
+	}
+}
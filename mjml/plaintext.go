@@ -0,0 +1,204 @@
+package mjml
+
+import (
+	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml/components"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// plainTextDivider is emitted for mj-divider in plain-text output
+const plainTextDivider = "---"
+
+// RenderText renders the MJML document as a text/plain alternative suitable for
+// multipart emails. It walks the component tree and emits text blocks, button
+// and link labels followed by their URLs, image alt text, and dividers as "---".
+// Blocks are separated by blank lines.
+func RenderText(mjmlContent string, opts ...RenderOption) (string, error) {
+	renderOpts := &RenderOpts{}
+	for _, opt := range opts {
+		opt(renderOpts)
+	}
+
+	ast, err := parseASTWithOptions(mjmlContent, renderOpts.UseCache, parseOptions(renderOpts), renderOpts.Logger)
+	if err != nil {
+		return "", err
+	}
+	if ast, err = expandExtensions(ast, renderOpts); err != nil {
+		return "", err
+	}
+
+	ast = applyGlobalAttributes(ast, renderOpts)
+
+	component, err := CreateComponent(ast, renderOpts)
+	if err != nil {
+		return "", err
+	}
+
+	var blocks []string
+	collectPlainTextBlocks(component, &blocks)
+	return strings.Join(blocks, "\n\n"), nil
+}
+
+// collectPlainTextBlocks appends the plain-text blocks for comp and its descendants.
+func collectPlainTextBlocks(comp Component, blocks *[]string) {
+	appendBlock := func(text string) {
+		if text = strings.TrimSpace(text); text != "" {
+			*blocks = append(*blocks, text)
+		}
+	}
+
+	switch v := comp.(type) {
+	case *components.MJTextComponent:
+		appendBlock(htmlToPlainText(v.Localize(v.Node.GetMixedContent())))
+	case *components.MJAccordionTitleComponent:
+		appendBlock(htmlToPlainText(v.Node.GetMixedContent()))
+	case *components.MJAccordionTextComponent:
+		appendBlock(htmlToPlainText(v.Node.GetMixedContent()))
+	case *components.MJTableComponent:
+		appendBlock(htmlToPlainText(v.Node.GetMixedContent()))
+	case *components.MJButtonComponent:
+		appendBlock(labelWithURL(htmlToPlainText(v.Localize(v.Node.GetMixedContent())), v.Attrs["href"]))
+	case *components.MJImageComponent:
+		appendBlock(labelWithURL(v.Attrs["alt"], v.Attrs["href"]))
+	case *components.MJCarouselImageComponent:
+		appendBlock(labelWithURL(v.Attrs["alt"], v.Attrs["href"]))
+	case *components.MJNavbarLinkComponent:
+		appendBlock(labelWithURL(htmlToPlainText(v.Node.GetMixedContent()), v.Attrs["href"]))
+	case *components.MJSocialElementComponent:
+		label := htmlToPlainText(v.Node.GetMixedContent())
+		if label == "" {
+			label = v.Attrs["name"]
+		}
+		appendBlock(labelWithURL(label, v.Attrs["href"]))
+	case *components.MJDividerComponent:
+		appendBlock(plainTextDivider)
+	}
+
+	for _, child := range componentChildren(comp) {
+		collectPlainTextBlocks(child, blocks)
+	}
+}
+
+// labelWithURL formats a link as "label (url)", collapsing to whichever part is
+// present when the other is empty or identical.
+func labelWithURL(label, url string) string {
+	label = strings.TrimSpace(label)
+	url = strings.TrimSpace(url)
+	switch {
+	case url == "" || url == "#" || url == label:
+		return label
+	case label == "":
+		return url
+	default:
+		return label + " (" + url + ")"
+	}
+}
+
+// htmlToPlainText converts an HTML fragment to readable text. Block elements and
+// <br> become line breaks, list items are prefixed with "- ", links keep their
+// target in parentheses, and script/style content is dropped.
+func htmlToPlainText(fragment string) string {
+	if fragment == "" {
+		return ""
+	}
+
+	var (
+		out       strings.Builder
+		hrefStack []string
+		skipDepth int
+	)
+
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		tok := z.Token()
+
+		switch tt {
+		case html.TextToken:
+			if skipDepth == 0 {
+				out.WriteString(tok.Data)
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch tok.DataAtom {
+			case atom.Script, atom.Style:
+				if tt == html.StartTagToken {
+					skipDepth++
+				}
+			case atom.Br:
+				out.WriteString("\n")
+			case atom.Li:
+				out.WriteString("\n- ")
+			case atom.A:
+				href := ""
+				for _, attr := range tok.Attr {
+					if attr.Key == "href" {
+						href = attr.Val
+					}
+				}
+				if tt == html.StartTagToken {
+					hrefStack = append(hrefStack, href)
+				}
+			default:
+				if isPlainTextBlock(tok.DataAtom) {
+					out.WriteString("\n")
+				}
+			}
+		case html.EndTagToken:
+			switch tok.DataAtom {
+			case atom.Script, atom.Style:
+				if skipDepth > 0 {
+					skipDepth--
+				}
+			case atom.A:
+				if n := len(hrefStack); n > 0 {
+					href := hrefStack[n-1]
+					hrefStack = hrefStack[:n-1]
+					if href != "" && href != "#" {
+						out.WriteString(" (" + href + ")")
+					}
+				}
+			default:
+				if isPlainTextBlock(tok.DataAtom) {
+					out.WriteString("\n")
+				}
+			}
+		}
+	}
+
+	return normalizePlainTextWhitespace(out.String())
+}
+
+func isPlainTextBlock(a atom.Atom) bool {
+	switch a {
+	case atom.P, atom.Div, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Ul, atom.Ol, atom.Tr, atom.Table, atom.Blockquote, atom.Pre, atom.Hr:
+		return true
+	}
+	return false
+}
+
+// normalizePlainTextWhitespace collapses runs of spaces within lines, trims each
+// line, and limits consecutive blank lines to one.
+func normalizePlainTextWhitespace(text string) string {
+	lines := strings.Split(text, "\n")
+	result := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			if !blank && len(result) > 0 {
+				result = append(result, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		result = append(result, line)
+	}
+	return strings.TrimSpace(strings.Join(result, "\n"))
+}
@@ -0,0 +1,73 @@
+package mjml
+
+import "testing"
+
+func TestRenderText(t *testing.T) {
+	input := `<mjml>
+  <mj-head>
+    <mj-title>Ignored</mj-title>
+  </mj-head>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-image src="https://example.com/logo.png" alt="Company logo" href="https://example.com" />
+        <mj-text>
+          <h1>Welcome</h1>
+          <p>Hello <b>there</b>, read the <a href="https://example.com/docs">docs</a>.</p>
+          <ul><li>One</li><li>Two</li></ul>
+        </mj-text>
+        <mj-divider />
+        <mj-button href="https://example.com/start">Get started</mj-button>
+        <mj-text>Line one<br/>Line two<style>.x{}</style></mj-text>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	got, err := RenderText(input)
+	if err != nil {
+		t.Fatalf("RenderText() error = %v", err)
+	}
+
+	want := `Company logo (https://example.com)
+
+Welcome
+
+Hello there, read the docs (https://example.com/docs).
+
+- One
+- Two
+
+---
+
+Get started (https://example.com/start)
+
+Line one
+Line two`
+	if got != want {
+		t.Errorf("RenderText() =\n%s\n\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderTextInvalidMJML(t *testing.T) {
+	if _, err := RenderText(`<mjml><mj-body>`); err == nil {
+		t.Error("RenderText() expected error for malformed input")
+	}
+}
+
+func TestLabelWithURL(t *testing.T) {
+	tests := []struct {
+		label, url, want string
+	}{
+		{"Click", "https://x.io", "Click (https://x.io)"},
+		{"Click", "", "Click"},
+		{"Click", "#", "Click"},
+		{"", "https://x.io", "https://x.io"},
+		{"https://x.io", "https://x.io", "https://x.io"},
+	}
+	for _, tt := range tests {
+		if got := labelWithURL(tt.label, tt.url); got != tt.want {
+			t.Errorf("labelWithURL(%q, %q) = %q, want %q", tt.label, tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package mjml
+
+import (
+	"io"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml/constants"
+	"github.com/preslavrachev/gomjml/mjml/options"
+)
+
+// OfficeNamespaces is an alias for convenience
+type OfficeNamespaces = options.OfficeNamespaces
+
+// Declaration of the VML and Office namespaces
+const (
+	OfficeNamespacesAlways = options.OfficeNamespacesAlways
+	OfficeNamespacesAuto   = options.OfficeNamespacesAuto
+	OfficeNamespacesOmit   = options.OfficeNamespacesOmit
+)
+
+// Document type declarations for WithDoctype
+const (
+	DoctypeHTML5             = `<!doctype html>`
+	DoctypeXHTMLTransitional = `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">`
+	DoctypeXHTMLStrict       = `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Strict//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-strict.dtd">`
+	DoctypeHTML4Transitional = `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 4.01 Transitional//EN" "http://www.w3.org/TR/html4/loose.dtd">`
+)
+
+const (
+	vmlNamespace    = ` xmlns:v="urn:schemas-microsoft-com:vml"`
+	officeNamespace = ` xmlns:o="urn:schemas-microsoft-com:office:office"`
+)
+
+// WithDoctype replaces the <!doctype html> declaration that starts the
+// document, for senders whose tooling expects another one, such as
+// DoctypeXHTMLTransitional. Any declaration can be given; it is written as
+// is.
+func WithDoctype(doctype string) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.Doctype = doctype
+	}
+}
+
+// WithOfficeNamespaces controls the xmlns:v (VML) and xmlns:o (Office)
+// declarations on the html element. OfficeNamespacesAlways (the default)
+// declares both, like MJML. OfficeNamespacesAuto drops xmlns:v when the
+// body has no VML, such as background images and VML buttons for Outlook;
+// xmlns:o stays for the Office settings in the head. OfficeNamespacesOmit
+// drops both, which makes Outlook ignore the VML and the Office settings.
+func WithOfficeNamespaces(mode OfficeNamespaces) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.OfficeNamespaces = mode
+	}
+}
+
+// writeDocumentStart writes the doctype and the html start tag of a document
+// with the given body.
+func writeDocumentStart(w io.StringWriter, opts *RenderOpts, body string) error {
+	doctype := DoctypeHTML5
+	if opts.Doctype != "" {
+		doctype = opts.Doctype
+	}
+
+	var sb strings.Builder
+	sb.WriteString(doctype)
+	sb.WriteString(`<html lang="` + opts.Lang + `"`)
+	if opts.Dir != constants.DirAuto || !opts.Features.OmitRootAccessibility {
+		sb.WriteString(` dir="` + opts.Dir + `"`)
+	}
+	sb.WriteString(` xmlns="http://www.w3.org/1999/xhtml"`)
+	switch {
+	case opts.OmitOutlookSupport:
+	case opts.OfficeNamespaces == OfficeNamespacesAlways:
+		sb.WriteString(vmlNamespace + officeNamespace)
+	case opts.OfficeNamespaces == OfficeNamespacesAuto:
+		if strings.Contains(body, "<v:") {
+			sb.WriteString(vmlNamespace)
+		}
+		sb.WriteString(officeNamespace)
+	}
+	sb.WriteString(">")
+	_, err := w.WriteString(sb.String())
+	return err
+}
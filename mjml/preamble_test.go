@@ -0,0 +1,40 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithDoctypeAndOfficeNamespaces(t *testing.T) {
+	plain := `<mjml><mj-body><mj-section><mj-column><mj-text>Hi</mj-text></mj-column></mj-section></mj-body></mjml>`
+	withVML := `<mjml><mj-body><mj-section background-url="https://example.com/bg.png"><mj-column><mj-text>Hi</mj-text></mj-column></mj-section></mj-body></mjml>`
+
+	tests := []struct {
+		name  string
+		input string
+		opts  []RenderOption
+		start string
+	}{
+		{"default", plain, nil,
+			`<!doctype html><html lang="und" dir="auto" xmlns="http://www.w3.org/1999/xhtml" xmlns:v="urn:schemas-microsoft-com:vml" xmlns:o="urn:schemas-microsoft-com:office:office">`},
+		{"xhtml doctype", plain, []RenderOption{WithDoctype(DoctypeXHTMLTransitional)},
+			DoctypeXHTMLTransitional + `<html lang="und" dir="auto" xmlns="http://www.w3.org/1999/xhtml" xmlns:v=`},
+		{"auto without vml", plain, []RenderOption{WithOfficeNamespaces(OfficeNamespacesAuto)},
+			`<!doctype html><html lang="und" dir="auto" xmlns="http://www.w3.org/1999/xhtml" xmlns:o="urn:schemas-microsoft-com:office:office">`},
+		{"auto with vml", withVML, []RenderOption{WithOfficeNamespaces(OfficeNamespacesAuto)},
+			`<!doctype html><html lang="und" dir="auto" xmlns="http://www.w3.org/1999/xhtml" xmlns:v="urn:schemas-microsoft-com:vml" xmlns:o="urn:schemas-microsoft-com:office:office">`},
+		{"omit", withVML, []RenderOption{WithOfficeNamespaces(OfficeNamespacesOmit)},
+			`<!doctype html><html lang="und" dir="auto" xmlns="http://www.w3.org/1999/xhtml"><head>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, err := Render(tt.input, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(html, tt.start) {
+				t.Errorf("output starts with %q, want %q", html[:min(len(html), len(tt.start))], tt.start)
+			}
+		})
+	}
+}
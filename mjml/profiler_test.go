@@ -0,0 +1,64 @@
+package mjml
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithProfiler(t *testing.T) {
+	type call struct {
+		component string
+		depth     int
+		bytes     int
+	}
+
+	var calls []call
+	html, err := Render(`<mjml>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-text>Hello</mj-text>
+        <mj-button href="https://example.com">Click</mj-button>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`, WithProfiler(func(component string, depth int, duration time.Duration, bytes int) {
+		if duration < 0 {
+			t.Errorf("negative duration for %s", component)
+		}
+		calls = append(calls, call{component, depth, bytes})
+	}))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := []struct {
+		component string
+		depth     int
+	}{
+		{"mj-text", 3},
+		{"mj-button", 3},
+		{"mj-column", 2},
+		{"mj-section", 1},
+		{"mj-body", 0},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("got %d profiler calls, want %d: %+v", len(calls), len(want), calls)
+	}
+	for i, w := range want {
+		if calls[i].component != w.component || calls[i].depth != w.depth {
+			t.Errorf("call %d = %+v, want %s at depth %d", i, calls[i], w.component, w.depth)
+		}
+		if calls[i].bytes <= 0 {
+			t.Errorf("call %d (%s) reported no output bytes", i, calls[i].component)
+		}
+	}
+
+	body := calls[len(calls)-1]
+	if body.bytes >= len(html) {
+		t.Errorf("body bytes %d should be smaller than document size %d", body.bytes, len(html))
+	}
+	if calls[3].bytes >= body.bytes || calls[0].bytes >= calls[2].bytes {
+		t.Errorf("parent output should include child output: %+v", calls)
+	}
+}
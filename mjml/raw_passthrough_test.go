@@ -0,0 +1,54 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderRawPassthrough(t *testing.T) {
+	const raw = `
+    {{#if user.premium}}<p>Hi &amp; welcome&nbsp;{{ user.name }}<br/></p>{{/if}}
+    <script>if (a < b && c) { go(); }</script>
+  `
+	input := `<mjml><mj-head><mj-raw>` + raw + `</mj-raw></mj-head><mj-body>` +
+		`<mj-raw>` + raw + `</mj-raw>` +
+		`<mj-section><mj-column><mj-text>&copy; Text</mj-text></mj-column></mj-section>` +
+		`</mj-body></mjml>`
+
+	html, err := Render(input, WithRawPassthrough())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if n := strings.Count(html, raw); n != 2 {
+		t.Errorf("verbatim mj-raw content found %d times, want 2:\n%s", n, html)
+	}
+	if !strings.Contains(html, "© Text") {
+		t.Error("content outside mj-raw should still be preprocessed")
+	}
+
+	// The same source parsed both ways must not share a cached AST.
+	entities := `<mjml><mj-body><mj-raw><p>&copy; Fish</p></mj-raw></mj-body></mjml>`
+	preprocessed, err := Render(entities, WithCache())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	verbatim, err := Render(entities, WithCache(), WithRawPassthrough())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(preprocessed, "© Fish") || !strings.Contains(verbatim, "&copy; Fish") {
+		t.Errorf("cached renders mixed up:\n%s\n%s", preprocessed, verbatim)
+	}
+
+	tmpl, err := Compile(input, WithRawPassthrough())
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	var sb strings.Builder
+	if err := tmpl.Render(&sb); err != nil {
+		t.Fatalf("template render failed: %v", err)
+	}
+	if sb.String() != html {
+		t.Error("compiled template output differs from Render")
+	}
+}
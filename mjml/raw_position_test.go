@@ -0,0 +1,39 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRawFileStartPosition verifies that mj-raw with position="file-start" is
+// written before the doctype, whether it sits in the mjml root, mj-head or
+// mj-body, while other head mj-raw content stays at the end of the head.
+func TestRawFileStartPosition(t *testing.T) {
+	input := `<mjml>
+  <mj-raw position="file-start">{% layout 'base' %}</mj-raw>
+  <mj-head>
+    <mj-raw position="file-start">{% assign a = 1 %}</mj-raw>
+    <mj-raw><meta name="x-tracking" content="on" /></mj-raw>
+  </mj-head>
+  <mj-body>
+    <mj-raw position="file-start">{% assign b = 2 %}</mj-raw>
+    <mj-section><mj-column><mj-text>Hello</mj-text></mj-column></mj-section>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(input, WithValidationLevel(ValidationStrict))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	prefix := "{% layout 'base' %}\n{% assign a = 1 %}\n{% assign b = 2 %}\n<!doctype html>"
+	if !strings.HasPrefix(html, prefix) {
+		t.Errorf("expected file-start content before the doctype, got %q", html[:min(len(html), 120)])
+	}
+	if strings.Count(html, "{% assign") != 2 {
+		t.Error("expected file-start content to be written only once")
+	}
+	if !strings.Contains(html, `<meta name="x-tracking" content="on"></head>`) {
+		t.Error("expected head mj-raw content at the end of the head")
+	}
+}
@@ -4,17 +4,20 @@ import (
 	"context"
 	"fmt"
 	"hash/maphash"
+	"html"
 	"io"
+	"log/slog"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/preslavrachev/gomjml/mjml/components"
-	"github.com/preslavrachev/gomjml/mjml/constants"
 	"github.com/preslavrachev/gomjml/mjml/debug"
 	"github.com/preslavrachev/gomjml/mjml/fonts"
 	"github.com/preslavrachev/gomjml/mjml/globals"
 	"github.com/preslavrachev/gomjml/mjml/options"
+	"github.com/preslavrachev/gomjml/mjml/sanitize"
 	"github.com/preslavrachev/gomjml/mjml/styles"
 	"github.com/preslavrachev/gomjml/parser"
 )
@@ -25,9 +28,18 @@ type MJMLNode = parser.MJMLNode
 // ParseMJML re-exports the parser function for convenience
 var ParseMJML = parser.ParseMJML
 
+// ParseMJMLReader re-exports the streaming parser function for convenience
+var ParseMJMLReader = parser.ParseMJMLReader
+
 // RenderOpts is an alias for convenience
 type RenderOpts = options.RenderOpts
 
+// ImageSizer is an alias for convenience
+type ImageSizer = options.ImageSizer
+
+// ImageResizer is an alias for convenience
+type ImageResizer = options.ImageResizer
+
 // RenderOption is a functional option for configuring MJML rendering
 type RenderOption func(*RenderOpts)
 
@@ -57,11 +69,21 @@ func calculateOptimalBufferSize(mjmlContent string) int {
 	}
 }
 
-// cachedAST wraps an MJML AST with a fixed expiration time.
-// Entries are immutable once stored in the cache to avoid concurrent mutation.
-type cachedAST struct {
-	node    *MJMLNode
-	expires time.Time
+// rollingOutputSize folds the output size of a render into the average of the
+// earlier renders of a template. The newest render weighs a quarter, so the
+// average follows templates whose output varies with their data.
+func rollingOutputSize(average, size int) int {
+	if average == 0 {
+		return size
+	}
+	return average + (size-average)/4
+}
+
+// outputSizeHint returns the buffer size for a template whose renders average
+// the given size, with headroom so that a slightly longer render does not
+// grow the builder, which copies everything written so far.
+func outputSizeHint(average int) int {
+	return average + average/16
 }
 
 // Global cache state and synchronization primitives.
@@ -72,13 +94,13 @@ type cachedAST struct {
 // careful synchronization for thread safety.
 //
 // MEMORY MANAGEMENT STRATEGY:
-// - Fixed TTL expiration (no LRU) keeps implementation simple and predictable
-// - Background cleanup prevents unbounded memory growth
-// - No size limits - monitor memory usage in production environments
-// - Cache grows between cleanup cycles, then shrinks during cleanup
+// - Fixed TTL expiration; hits do not extend an entry's lifetime
+// - Background cleanup removes expired entries between lookups
+// - Optional LRU bounds on entry count and estimated bytes (see SetASTCacheLimits)
+// - CacheStats exposes hits, misses, evictions and the current size
 //
 // CONCURRENCY ARCHITECTURE:
-// - sync.Map for the cache itself (optimized for high read/low write workloads)
+// - A mutex-protected LRU list for the cache itself (hits update recency)
 // - Singleflight pattern prevents duplicate parsing under high concurrency
 // - Multiple mutexes to minimize lock contention and prevent deadlocks
 //
@@ -99,11 +121,11 @@ type cachedAST struct {
 //   - Short-lived processes where cache warmup overhead > benefits
 var (
 	// Cache storage and configuration
-	astCache                sync.Map          // map[uint64]*cachedAST - main cache storage
-	astCacheTTL             = 5 * time.Minute // default expiration time
-	astCacheTTLOnce         sync.Once         // ensures TTL is set only once
-	astCacheCleanupInterval = astCacheTTL / 2 // how often to run cleanup
-	astCacheCleanupOnce     sync.Once         // ensures cleanup interval set only once
+	astCache                = newASTCacheStore() // LRU of *cachedAST keyed by template hash
+	astCacheTTL             = 5 * time.Minute    // default expiration time
+	astCacheTTLOnce         sync.Once            // ensures TTL is set only once
+	astCacheCleanupInterval = astCacheTTL / 2    // how often to run cleanup
+	astCacheCleanupOnce     sync.Once            // ensures cleanup interval set only once
 
 	// Cache lifecycle management
 	cacheCleanupMutex sync.Mutex         // protects cleanup goroutine lifecycle
@@ -209,49 +231,54 @@ func hashTemplate(s string) uint64 {
 
 // parseAST handles MJML parsing with optional caching.
 func parseAST(mjmlContent string, useCache bool) (*MJMLNode, error) {
+	return parseASTWithOptions(mjmlContent, useCache, parser.ParseOptions{}, debug.Logger{})
+}
+
+// parseASTWithOptions is parseAST with explicit parse options, logging to
+// logger. Cached ASTs are kept apart per option set.
+func parseASTWithOptions(mjmlContent string, useCache bool, parseOpts parser.ParseOptions, logger debug.Logger) (*MJMLNode, error) {
+	parse := func() (*MJMLNode, error) {
+		if parseOpts == (parser.ParseOptions{}) {
+			return ParseMJML(mjmlContent)
+		}
+		return parser.ParseMJMLWithOptions(mjmlContent, parseOpts)
+	}
+
 	if !useCache {
-		if debug.Enabled() {
-			debug.DebugLog("mjml", "parse-start", "Starting MJML parsing")
+		if logger.Enabled() {
+			logger.Log("mjml", "parse-start", "Starting MJML parsing")
 		}
-		node, err := ParseMJML(mjmlContent)
+		node, err := parse()
 		if err != nil {
-			if debug.Enabled() {
-				debug.DebugLogError("mjml", "parse-error", "Failed to parse MJML", err)
-			}
+			logger.LogError("mjml", "parse-error", "Failed to parse MJML", err)
 			return nil, err
 		}
-		if debug.Enabled() {
-			debug.DebugLog("mjml", "parse-complete", "MJML parsing completed successfully")
+		if logger.Enabled() {
+			logger.Log("mjml", "parse-complete", "MJML parsing completed successfully")
 		}
 		return node, nil
 	}
 
 	startASTCacheCleanup()
-	hash := hashTemplate(mjmlContent)
-	if cached, found := astCache.Load(hash); found {
-		entry := cached.(*cachedAST)
-		if time.Now().Before(entry.expires) {
-			if debug.Enabled() {
-				debug.DebugLog("mjml", "parse-cache-hit", "Using cached MJML AST")
-			}
-			return entry.node, nil
+	hash := astCacheKey(mjmlContent, parseOpts)
+	if node, found := astCache.get(hash, time.Now()); found {
+		if logger.Enabled() {
+			logger.Log("mjml", "parse-cache-hit", "Using cached MJML AST")
 		}
-		astCache.Delete(hash)
+		return node, nil
 	}
 
 	node, err := singleflightDo(hash, func() (*MJMLNode, error) {
-		if debug.Enabled() {
-			debug.DebugLog("mjml", "parse-start", "Starting MJML parsing")
+		if logger.Enabled() {
+			logger.Log("mjml", "parse-start", "Starting MJML parsing")
 		}
-		node, err := ParseMJML(mjmlContent)
+		node, err := parse()
 		if err != nil {
-			if debug.Enabled() {
-				debug.DebugLogError("mjml", "parse-error", "Failed to parse MJML", err)
-			}
+			logger.LogError("mjml", "parse-error", "Failed to parse MJML", err)
 			return nil, err
 		}
-		if debug.Enabled() {
-			debug.DebugLog("mjml", "parse-complete", "MJML parsing completed successfully")
+		if logger.Enabled() {
+			logger.Log("mjml", "parse-complete", "MJML parsing completed successfully")
 		}
 
 		// Read TTL with proper synchronization for cache storage
@@ -259,7 +286,7 @@ func parseAST(mjmlContent string, useCache bool) (*MJMLNode, error) {
 		ttl := astCacheTTL
 		cacheConfigMutex.RUnlock()
 
-		astCache.Store(hash, &cachedAST{node: node, expires: time.Now().Add(ttl)})
+		astCache.add(hash, node, estimateASTSize(node), time.Now().Add(ttl))
 		return node, nil
 	})
 	if err != nil {
@@ -268,6 +295,28 @@ func parseAST(mjmlContent string, useCache bool) (*MJMLNode, error) {
 	return node, nil
 }
 
+// astCacheKey returns the AST cache key of mjmlContent parsed with parseOpts.
+// The same source yields a different AST for each set of parse options.
+func astCacheKey(mjmlContent string, parseOpts parser.ParseOptions) uint64 {
+	return hashTemplate(mjmlContent) ^ parseOptionsHash(parseOpts)
+}
+
+// parseOptionsHash returns a value mixed into the cache key of ASTs parsed
+// with opts. It is zero for the default options.
+func parseOptionsHash(opts parser.ParseOptions) uint64 {
+	var bits uint64
+	if opts.RawPassthrough {
+		bits |= 1
+	}
+	if opts.StripComments {
+		bits |= 2
+	}
+	if opts.StripRawComments {
+		bits |= 4
+	}
+	return bits * 0x9E3779B97F4A7C15
+}
+
 // startASTCacheCleanup launches a background goroutine to periodically remove expired cache entries.
 //
 // HOW it works: Starts a single goroutine with a ticker that scans the entire
@@ -296,14 +345,7 @@ func startASTCacheCleanup() {
 		for {
 			select {
 			case <-ticker.C:
-				now := time.Now()
-				astCache.Range(func(key, value interface{}) bool {
-					entry := value.(*cachedAST)
-					if now.After(entry.expires) {
-						astCache.Delete(key)
-					}
-					return true
-				})
+				astCache.removeExpired(time.Now())
 			case <-ctx.Done():
 				return
 			}
@@ -335,22 +377,241 @@ func WithCache() RenderOption {
 	}
 }
 
+// WithSanitizeHTML runs raw HTML embedded in mj-text, mj-raw and mj-table
+// through the default allowlist sanitizer before it is written to the output.
+// Use it when rendering MJML authored by untrusted users.
+func WithSanitizeHTML() RenderOption {
+	return func(opts *RenderOpts) {
+		opts.Sanitizer = sanitize.DefaultPolicy()
+	}
+}
+
+// WithSanitizePolicy is like WithSanitizeHTML but uses a caller-provided policy,
+// allowing the permitted tags, attributes and URL schemes to be customized.
+func WithSanitizePolicy(policy *sanitize.Policy) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.Sanitizer = policy
+	}
+}
+
+// WithStripEventHandlers removes event handler attributes (onclick, onload,
+// ...) from raw HTML embedded in mj-text, mj-raw and mj-table, leaving the
+// rest of it as written. Together with WithStyleNonce it lets the output be
+// shown in a web preview under a strict Content-Security-Policy.
+func WithStripEventHandlers() RenderOption {
+	return func(opts *RenderOpts) {
+		opts.StripEventHandlers = true
+	}
+}
+
+// WithStyleNonce adds nonce="<nonce>" to every <style> tag of the output,
+// including those in raw content, so they are allowed by a
+// Content-Security-Policy with style-src 'nonce-<nonce>'. Email layouts also
+// rely on style attributes, which the policy must allow separately
+// (style-src-attr 'unsafe-inline').
+func WithStyleNonce(nonce string) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.StyleNonce = nonce
+	}
+}
+
+// WithProfiler reports the render duration and output size of every component
+// in the body. Depth is 0 for mj-body and increases by one per nesting level.
+// Durations include the time spent rendering nested children.
+func WithProfiler(cb func(component string, depth int, duration time.Duration, bytes int)) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.Profiler = cb
+	}
+}
+
+// WithLogger sends the render's debug events, such as component creation,
+// attribute resolution and font detection, to handler as debug-level slog
+// records carrying "component" and "phase" attributes; errors are logged at
+// warning level. The handler decides which levels are kept and can sample
+// or forward them to a tracing system. Unlike the "debug" build tag, this
+// works per render in production builds. A nil handler restores the default.
+func WithLogger(handler slog.Handler) RenderOption {
+	return func(opts *RenderOpts) {
+		if handler == nil {
+			opts.Logger = debug.Logger{}
+			return
+		}
+		opts.Logger = debug.NewLogger(slog.New(handler))
+	}
+}
+
+// WithFonts replaces the web fonts imported for the font families a document
+// uses, like the fonts option of mjml2html. Keys are font names matched
+// against font-family values, values are stylesheet URLs; fonts outside the
+// map are not imported. mj-font declarations are imported as before. The
+// default is a set of Google Fonts including Ubuntu, Roboto and Open Sans.
+func WithFonts(fonts map[string]string) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.Fonts = fonts
+	}
+}
+
+// WithSocialIconBaseURL serves the icons of built-in mj-social-element networks
+// from baseURL instead of MJML's default host. Icon file names are kept, so the
+// location must mirror the default set (facebook.png, twitter.png, ...).
+// Elements with an explicit src are not affected.
+func WithSocialIconBaseURL(baseURL string) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.SocialIconBaseURL = baseURL
+	}
+}
+
+// WithIDGenerator makes the IDs of interactive elements (the mj-carousel radio
+// group and the mj-navbar hamburger checkbox) come from gen instead of random
+// hex strings. gen receives the component tag name and a 0-based index counting
+// the IDs requested for that component within the render, so a deterministic
+// gen yields byte-identical output for identical input. Returning "" falls back
+// to the default random ID. IDs must be unique within the document.
+func WithIDGenerator(gen func(component string, index int) string) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.IDGenerator = gen
+		opts.IDCounters = make(map[string]int)
+	}
+}
+
+// WithImageSizer supplies the intrinsic size of mj-image sources. When an
+// image's height is left at auto, its height attribute is set to the height
+// the image has at its rendered width instead of "auto", so clients that size
+// images from their attributes reserve the space before the image loads and
+// the layout does not jump. The width and the CSS height are unchanged. The
+// sizer is called for every such image on every render and should return
+// cached results; ok is false when the size is unknown.
+func WithImageSizer(sizer ImageSizer) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.ImageSizer = sizer
+	}
+}
+
+// WithResponsiveImages gives mj-image elements a srcset of the source scaled
+// to each of widths, built with resizer, and a matching sizes attribute, so
+// clients that support srcset load an image no larger than they display. The
+// sizes follow the rendered width, and the full viewport width below the
+// mobile breakpoint for fluid-on-mobile images. The gomjml srcset-widths
+// attribute, a comma-separated list of pixel widths, replaces widths for one
+// image. Images with their own srcset, and images being inlined, are left as
+// they are; src is kept for clients without srcset support.
+func WithResponsiveImages(widths []int, resizer ImageResizer) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.ResponsiveImageWidths = widths
+		opts.ImageResizer = resizer
+	}
+}
+
+// WithTitle sets the document title, replacing any mj-title. The value is
+// plain text and is HTML-escaped. Use it to localize one template per campaign.
+func WithTitle(title string) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.OverrideTitle = title
+	}
+}
+
+// WithLang sets the document language, replacing the lang attribute of the
+// mjml root element, e.g. to render one template for several locales.
+func WithLang(lang string) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.OverrideLang = lang
+	}
+}
+
+// WithDir sets the document text direction ("ltr", "rtl" or "auto"),
+// replacing the dir attribute of the mjml root element. With "rtl", sections,
+// wrappers, groups and columns default to direction="rtl" and mj-text to
+// align="right"; explicit attributes still win.
+func WithDir(dir string) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.OverrideDir = dir
+	}
+}
+
+// WithPreviewText sets the hidden preheader shown by mail clients next to the
+// subject, replacing any mj-preview. The value is plain text and is HTML-escaped.
+func WithPreviewText(text string) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.OverridePreview = text
+	}
+}
+
+// WithPreheaderPadding pads the preview text with &nbsp;&zwnj; sequences up
+// to n characters, so inbox previews do not continue with text from the
+// email body. Emoji count as one character. Documents without a preview are
+// not affected.
+func WithPreheaderPadding(n int) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.PreheaderPadding = n
+	}
+}
+
+// WithDefaultAttributes applies organization-wide attribute defaults without
+// repeating an mj-attributes block in every template. defaults is keyed by
+// component tag name (e.g. "mj-text") or "mj-all", like the children of
+// mj-attributes. The document's own mj-attributes and inline attributes take
+// precedence; mj-class definitions are not supported here.
+func WithDefaultAttributes(defaults map[string]map[string]string) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.DefaultAttributes = defaults
+	}
+}
+
+// WithOutlookVMLButtons renders linked mj-buttons as bulletproof v:roundrect
+// VML shapes in Outlook desktop, making the whole button clickable there. The
+// arcsize is derived from border-radius. Buttons need a pixel width; others keep
+// the regular table markup. All other clients are unaffected.
+func WithOutlookVMLButtons() RenderOption {
+	return func(opts *RenderOpts) {
+		opts.OutlookVMLButtons = true
+	}
+}
+
+// WithRawPassthrough emits the content of mj-raw elements byte for byte as it
+// appears in the source. By default mj-raw content goes through entity
+// decoding and whitespace normalization, which can alter scripts and ESP merge
+// tags such as {{#if}} or %%[ ]%%. With this option the content does not need
+// to be well-formed XML either. WithSanitizeHTML still applies.
+func WithRawPassthrough() RenderOption {
+	return func(opts *RenderOpts) {
+		opts.RawPassthrough = true
+	}
+}
+
+// parseOptions returns the parser options selected by renderOpts.
+func parseOptions(renderOpts *RenderOpts) parser.ParseOptions {
+	return parser.ParseOptions{
+		RawPassthrough:   renderOpts.RawPassthrough,
+		StripComments:    renderOpts.Comments != CommentsPreserve,
+		StripRawComments: renderOpts.Comments == CommentsStrip,
+	}
+}
+
+// WithTranslator resolves localized content at render time. mj-text and
+// mj-button elements carrying an i18n-key attribute have their content replaced
+// by translate(key, lang), where lang is the lang attribute of the mjml root.
+// Returning "" keeps the content from the template. Translations are inserted as
+// HTML, so escape plain strings before returning them.
+func WithTranslator(translate func(key, lang string) string) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.Translator = translate
+	}
+}
+
 // RenderResult contains both the rendered HTML and the MJML AST
 type RenderResult struct {
-	HTML string
-	AST  *MJMLNode
+	HTML         string
+	AST          *MJMLNode
+	SourceMap    *SourceMap   // Set when rendering WithSourceMap
+	Attachments  []Attachment // Images referenced by cid: when rendering WithInlineImages(InlineImagesCID, ...)
+	TemplateHash uint64       // TemplateHash of the MJML source (0 when rendered from an AST)
+	OutputHash   uint64       // Hash of HTML, for ETags; see ETag
+	BodyWidth    int          // Layout width of the email in pixels; see MJMLComponent.BodyWidth
 }
 
 // RenderWithAST provides the internal MJML to HTML conversion function that returns both HTML and AST
 func RenderWithAST(mjmlContent string, opts ...RenderOption) (*RenderResult, error) {
 	startTime := time.Now()
-	debugEnabled := debug.Enabled()
-	if debugEnabled {
-		debug.DebugLogWithData("mjml", "render-start", "Starting MJML rendering", map[string]interface{}{
-			"content_length": len(mjmlContent),
-			"has_debug":      len(opts) > 0,
-		})
-	}
 
 	// Apply render options
 	renderOpts := &RenderOpts{
@@ -360,50 +621,42 @@ func RenderWithAST(mjmlContent string, opts ...RenderOption) (*RenderResult, err
 		opt(renderOpts)
 	}
 
-	var validationErr *Error
-	existingReporter := renderOpts.InvalidAttributeReporter
-	renderOpts.InvalidAttributeReporter = func(tagName, attrName string, line int) {
-		errDetail := ErrInvalidAttribute(tagName, attrName, line)
-		if validationErr == nil {
-			validationErr = errDetail
-		} else {
-			validationErr.Append(errDetail)
-		}
-		if existingReporter != nil {
-			existingReporter(tagName, attrName, line)
-		}
+	logger := renderOpts.Logger
+	debugEnabled := logger.Enabled()
+	if debugEnabled {
+		logger.LogWithData("mjml", "render-start", "Starting MJML rendering", map[string]interface{}{
+			"content_length": len(mjmlContent),
+			"has_debug":      len(opts) > 0,
+		})
 	}
 
+	validation := collectValidation(renderOpts)
+
 	// Parse MJML using the parser package (with optional cache)
-	ast, err := parseAST(mjmlContent, renderOpts.UseCache)
+	ast, err := parseASTWithOptions(mjmlContent, renderOpts.UseCache, parseOptions(renderOpts), logger)
 	if err != nil {
 		return nil, err
 	}
-
-	// Initialize global attributes
-	globalAttrs := globals.NewGlobalAttributes()
-
-	// Process global attributes from head if it exists
-	if headNode := ast.FindFirstChild("mj-head"); headNode != nil {
-		globalAttrs.ProcessAttributesFromHead(headNode)
+	if ast, err = expandExtensions(ast, renderOpts); err != nil {
+		return nil, err
 	}
 
-	// Set the global attributes instance
-	globals.SetGlobalAttributes(globalAttrs)
+	ast = applyGlobalAttributes(ast, renderOpts)
 
 	// Create component tree
 	if debugEnabled {
-		debug.DebugLog("mjml", "component-tree-start", "Creating component tree from AST")
+		logger.Log("mjml", "component-tree-start", "Creating component tree from AST")
 	}
 	component, err := CreateComponent(ast, renderOpts)
 	if err != nil {
-		if debugEnabled {
-			debug.DebugLogError("mjml", "component-tree-error", "Failed to create component tree", err)
-		}
+		logger.LogError("mjml", "component-tree-error", "Failed to create component tree", err)
 		return nil, err
 	}
 	if debugEnabled {
-		debug.DebugLog("mjml", "component-tree-complete", "Component tree created successfully")
+		logger.Log("mjml", "component-tree-complete", "Component tree created successfully")
+	}
+	if err := validation.strictErr(); err != nil {
+		return nil, err
 	}
 
 	if root, ok := component.(*MJMLComponent); ok && root.Body == nil {
@@ -411,15 +664,31 @@ func RenderWithAST(mjmlContent string, opts ...RenderOption) (*RenderResult, err
 		// MJML CLI reports "MJML badly formatted" in this scenario, so mirror that sentinel output
 		// to keep test fixtures consistent while avoiding rendering partially constructed markup.
 		return &RenderResult{
-			HTML: "MJML badly formatted",
-			AST:  ast,
+			HTML:         "MJML badly formatted",
+			AST:          ast,
+			TemplateHash: TemplateHash(mjmlContent),
+			OutputHash:   fingerprint("MJML badly formatted"),
 		}, nil
 	}
 
-	// Render to HTML with optimized pre-allocation based on template complexity
+	if renderOpts.OutputFormat == FormatAMP {
+		if ampErr := validateAMPComponents(component); ampErr != nil {
+			return nil, *ampErr
+		}
+	}
+
+	// Render to HTML with optimized pre-allocation: the size of earlier renders
+	// of a cached template, or an estimate based on template complexity
 	bufferSize := calculateOptimalBufferSize(mjmlContent)
+	var cacheKey uint64
+	if renderOpts.UseCache {
+		cacheKey = astCacheKey(mjmlContent, parseOptions(renderOpts))
+		if hint, ok := astCache.outputSizeHint(cacheKey); ok {
+			bufferSize = hint
+		}
+	}
 	if debugEnabled {
-		debug.DebugLogWithData("mjml", "render-html-start", "Starting HTML rendering", map[string]interface{}{
+		logger.LogWithData("mjml", "render-html-start", "Starting HTML rendering", map[string]interface{}{
 			"buffer_size": bufferSize,
 		})
 	}
@@ -429,18 +698,29 @@ func RenderWithAST(mjmlContent string, opts ...RenderOption) (*RenderResult, err
 	renderStart := time.Now()
 	err = component.Render(&html)
 	if err != nil {
-		if debugEnabled {
-			debug.DebugLogError("mjml", "render-html-error", "Failed to render HTML", err)
-		}
+		logger.LogError("mjml", "render-html-error", "Failed to render HTML", err)
 		return nil, err
 	}
 	renderDuration := time.Since(renderStart).Milliseconds()
+	if renderOpts.UseCache {
+		astCache.recordOutputSize(cacheKey, html.Len())
+	}
 
-	htmlOutput := html.String()
+	htmlOutput, a11yErr := checkAccessibility(component, html.String(), renderOpts)
+	if a11yErr != nil {
+		validation.add(a11yErr)
+	}
+	if heroErr := checkHeroOverflow(component, renderOpts); heroErr != nil {
+		validation.add(heroErr)
+	}
+	htmlOutput, sourceMap, err := finishRender(htmlOutput, renderOpts)
+	if err != nil {
+		return nil, err
+	}
 	totalDuration := time.Since(startTime).Milliseconds()
 
 	if debugEnabled {
-		debug.DebugLogWithData("mjml", "render-complete", "MJML rendering completed", map[string]interface{}{
+		logger.LogWithData("mjml", "render-complete", "MJML rendering completed", map[string]interface{}{
 			"output_length":    len(htmlOutput),
 			"render_time_ms":   renderDuration,
 			"total_time_ms":    totalDuration,
@@ -448,17 +728,66 @@ func RenderWithAST(mjmlContent string, opts ...RenderOption) (*RenderResult, err
 		})
 	}
 
-	if validationErr != nil {
-		return &RenderResult{
-			HTML: htmlOutput,
-			AST:  ast,
-		}, *validationErr
+	return &RenderResult{
+		HTML:         htmlOutput,
+		AST:          ast,
+		SourceMap:    sourceMap,
+		Attachments:  attachments(renderOpts),
+		TemplateHash: TemplateHash(mjmlContent),
+		OutputHash:   fingerprint(htmlOutput),
+		BodyWidth:    bodyWidth(component),
+	}, validation.result()
+}
+
+// finishRender applies output-format conversion and theme overrides to a
+// rendered document, strips source map markers, runs the HTML transforms and
+// notifies the AfterRender hook. The source map is nil unless one was requested.
+func finishRender(htmlOutput string, renderOpts *RenderOpts) (string, *SourceMap, error) {
+	if renderOpts.OutputFormat == FormatAMP {
+		htmlOutput = convertToAMP(htmlOutput)
+	} else if renderOpts.OmitOutlookSupport {
+		htmlOutput = stripOutlookSupport(htmlOutput)
+	}
+	htmlOutput = applyTheme(htmlOutput, renderOpts.ThemeOverrides)
+	htmlOutput = escapeOutput(htmlOutput, renderOpts)
+	if renderOpts.StyleNonce != "" {
+		htmlOutput = addStyleNonce(htmlOutput, renderOpts.StyleNonce)
+	}
+	var sourceMap *SourceMap
+	if renderOpts.SourceMap {
+		htmlOutput, sourceMap = extractSourceMap(htmlOutput)
 	}
+	if len(renderOpts.HTMLTransforms) > 0 {
+		var err error
+		if htmlOutput, err = transformHTML(htmlOutput, renderOpts); err != nil {
+			return "", nil, err
+		}
+	}
+	if renderOpts.AfterRender != nil {
+		renderOpts.AfterRender(htmlOutput)
+	}
+	return htmlOutput, sourceMap, nil
+}
 
-	return &RenderResult{
-		HTML: htmlOutput,
-		AST:  ast,
-	}, nil
+// addStyleNonce adds a nonce attribute to every <style> tag in htmlOutput.
+func addStyleNonce(htmlOutput, nonce string) string {
+	attr := ` nonce="` + html.EscapeString(nonce) + `"`
+	var out strings.Builder
+	out.Grow(len(htmlOutput) + strings.Count(htmlOutput, "<style")*len(attr))
+	for {
+		i := strings.Index(htmlOutput, "<style")
+		if i < 0 {
+			break
+		}
+		end := i + len("<style")
+		out.WriteString(htmlOutput[:end])
+		if end < len(htmlOutput) && strings.IndexByte(" \t\n\r/>", htmlOutput[end]) >= 0 {
+			out.WriteString(attr)
+		}
+		htmlOutput = htmlOutput[end:]
+	}
+	out.WriteString(htmlOutput)
+	return out.String()
 }
 
 // Render provides the main MJML to HTML conversion function
@@ -479,33 +808,36 @@ func RenderFromAST(ast *MJMLNode, opts ...RenderOption) (string, error) {
 		opt(renderOpts)
 	}
 
-	var validationErr *Error
-	existingReporter := renderOpts.InvalidAttributeReporter
-	renderOpts.InvalidAttributeReporter = func(tagName, attrName string, line int) {
-		errDetail := ErrInvalidAttribute(tagName, attrName, line)
-		if validationErr == nil {
-			validationErr = errDetail
-		} else {
-			validationErr.Append(errDetail)
-		}
-		if existingReporter != nil {
-			existingReporter(tagName, attrName, line)
-		}
-	}
+	validation := collectValidation(renderOpts)
 
+	ast, err := expandExtensions(ast, renderOpts)
+	if err != nil {
+		return "", err
+	}
+	ast = applyGlobalAttributes(ast, renderOpts)
 	component, err := CreateComponent(ast, renderOpts)
 	if err != nil {
 		return "", err
 	}
+	if err := validation.strictErr(); err != nil {
+		return "", err
+	}
+
+	if renderOpts.OutputFormat == FormatAMP {
+		if ampErr := validateAMPComponents(component); ampErr != nil {
+			return "", *ampErr
+		}
+	}
 
 	html, err := RenderComponentString(component)
 	if err != nil {
 		return "", err
 	}
-	if validationErr != nil {
-		return html, *validationErr
+	html, _, err = finishRender(html, renderOpts)
+	if err != nil {
+		return "", err
 	}
-	return html, nil
+	return html, validation.result()
 }
 
 // NewFromAST creates a component from a pre-parsed AST (alias for CreateComponent)
@@ -518,9 +850,26 @@ func NewFromAST(ast *MJMLNode, opts ...RenderOption) (Component, error) {
 		opt(renderOpts)
 	}
 
+	ast, err := expandExtensions(ast, renderOpts)
+	if err != nil {
+		return nil, err
+	}
+	ast = applyGlobalAttributes(ast, renderOpts)
 	return CreateComponent(ast, renderOpts)
 }
 
+// newGlobalAttributes collects the mj-attributes of the document's mj-head,
+// layered on top of any caller-provided defaults. The store belongs to one
+// render and reaches components through RenderOpts.
+func newGlobalAttributes(ast *MJMLNode, defaults map[string]map[string]string) *globals.GlobalAttributes {
+	globalAttrs := globals.NewGlobalAttributes()
+	globalAttrs.SetDefaults(defaults)
+	if headNode := ast.FindFirstChild("mj-head"); headNode != nil {
+		globalAttrs.ProcessAttributesFromHead(headNode)
+	}
+	return globalAttrs
+}
+
 // normalizeGroupColumnClassOrder rewrites the mj-group column class ordering to match
 // the canonical MJML output where the responsive width class precedes the Outlook fix
 // helper. The rendering pipeline historically emitted the inverse ordering for
@@ -595,10 +944,31 @@ type MJMLComponent struct {
 	*components.BaseComponent
 	Head             *components.MJHeadComponent
 	Body             *components.MJBodyComponent
-	mobileCSSAdded   bool                   // Track if mobile CSS has been added
-	columnClasses    map[string]styles.Size // Track column classes used in the document
-	columnClassOrder []string               // Preserve insertion order of column classes
-	carouselCSS      strings.Builder        // Collect carousel CSS from components
+	mobileCSSAdded   bool                         // Track if mobile CSS has been added
+	columnClasses    map[string]styles.Size       // Track column classes used in the document
+	columnClassOrder []string                     // Preserve insertion order of column classes
+	carouselCSS      strings.Builder              // Collect carousel CSS from components
+	headStyles       *headStyleRegistry           // Collects head CSS when head styles are merged
+	fileStartRaws    []*components.MJRawComponent // mj-raw elements written before the doctype
+	wrapperOpts      []*options.RenderOpts        // Options copies the children of mj-wrapper elements render with
+}
+
+// BodyWidth returns the layout width of the email in pixels: the mj-body width,
+// whether set on the element, through mj-class or in mj-attributes, or 600.
+// Sections and wrappers size their Outlook tables to it.
+func (c *MJMLComponent) BodyWidth() int {
+	if c.Body == nil {
+		return components.GetDefaultBodyWidthPixels()
+	}
+	return c.Body.GetEffectiveWidth()
+}
+
+// bodyWidth returns the BodyWidth of comp when it is a document root, or 0.
+func bodyWidth(comp Component) int {
+	if root, ok := comp.(*MJMLComponent); ok {
+		return root.BodyWidth()
+	}
+	return 0
 }
 
 // RequestMobileCSS allows components to request mobile CSS to be added
@@ -672,13 +1042,13 @@ func (c *MJMLComponent) collectCarouselCSSFromComponent(comp Component) {
 // hasCustomGlobalFonts checks if global attributes specify custom fonts
 func (c *MJMLComponent) hasCustomGlobalFonts() bool {
 	// Check if global attributes have specified font-family
-	globalFontFamily := globals.GetGlobalAttribute("mj-all", "font-family")
+	globalFontFamily := c.RenderOpts.GlobalAttributes.GetGlobalAttribute("mj-all", "font-family")
 	if globalFontFamily != "" && globalFontFamily != fonts.DefaultFontStack {
 		return true
 	}
 
 	// Check if any text components have global font-family defined
-	textFontFamily := globals.GetGlobalAttribute("mj-text", "font-family")
+	textFontFamily := c.RenderOpts.GlobalAttributes.GetGlobalAttribute("mj-text", "font-family")
 	if textFontFamily != "" && textFontFamily != fonts.DefaultFontStack {
 		return true
 	}
@@ -815,17 +1185,8 @@ func (c *MJMLComponent) collectColumnClassesFromComponent(comp Component) {
 		}
 	case *components.MJGroupComponent:
 		// Register group's CSS class based on its width attribute
-		groupWidth := v.GetAttribute("width")
-		if groupWidth != nil && strings.HasSuffix(*groupWidth, "px") {
-			// Parse pixel width and register pixel-based class
-			var widthPx int
-			fmt.Sscanf(*groupWidth, "%dpx", &widthPx)
-			className := fmt.Sprintf("mj-column-px-%d", widthPx)
-			c.registerColumnClass(className, styles.NewPixelSize(float64(widthPx)))
-		} else {
-			// Default to percentage-based class
-			c.registerColumnClass("mj-column-per-100", styles.NewPercentSize(100))
-		}
+		className, size := v.GetColumnClass()
+		c.registerColumnClass(className, size)
 
 		// Also recurse into children to collect column classes
 		for _, child := range v.Children {
@@ -878,24 +1239,53 @@ func (c *MJMLComponent) generateResponsiveCSS() string {
 	return css.String()
 }
 
-// extractHeadMetadata collects document-level metadata from mj-head children such as title
-// and custom font declarations. The extracted title is stored on the render options so that
-// body-level rendering can access it for accessibility attributes (aria-label).
-func (c *MJMLComponent) extractHeadMetadata() (string, []string) {
-	title := ""
-	customFonts := make([]string, 0)
+// forceOWADesktop reports whether Outlook on the web should get the desktop
+// column layout, requested with <mjml owa="desktop"> or WithTargetClients.
+func (c *MJMLComponent) forceOWADesktop() bool {
+	if c.RenderOpts != nil && c.RenderOpts.ForceOWADesktop {
+		return true
+	}
+	owa := c.GetAttribute("owa")
+	return owa != nil && *owa == "desktop"
+}
 
-	if c.Head == nil {
-		if c.RenderOpts != nil {
-			c.RenderOpts.Title = ""
-		}
-		return title, customFonts
+// generateOWACSS repeats the desktop column widths for Outlook on the web,
+// which marks its message container with an owa attribute but ignores media
+// queries.
+func (c *MJMLComponent) generateOWACSS() string {
+	var css strings.Builder
+	css.WriteString("<style type=\"text/css\">")
+	for _, className := range c.columnClassOrder {
+		size := c.columnClasses[className]
+		css.WriteString("[owa] .")
+		css.WriteString(className)
+		css.WriteString(" { width:")
+		css.WriteString(size.String())
+		css.WriteString(" !important; max-width: ")
+		css.WriteString(size.String())
+		css.WriteString("; }")
 	}
+	css.WriteString("</style>")
+	return css.String()
+}
 
-	for _, child := range c.Head.Children {
+// extractHeadMetadata collects document-level metadata from mj-head children such as title
+// and custom font declarations. mj-font hrefs pointing to stylesheets are returned as
+// customFonts, and those pointing to font files as fontFaces, one per font name. The
+// extracted title is stored on the render options so that body-level rendering can access
+// it for accessibility attributes (aria-label).
+func (c *MJMLComponent) extractHeadMetadata() (title string, customFonts []string, fontFaces []fonts.FontFace) {
+	customFonts = make([]string, 0)
+
+	var headChildren []Component
+	if c.Head != nil {
+		headChildren = c.Head.Children
+	}
+
+	for _, child := range headChildren {
 		switch comp := child.(type) {
 		case *components.MJTitleComponent:
-			title = strings.TrimSpace(comp.Node.Text)
+			title = comp.Title()
 		case *components.MJFontComponent:
 			getAttr := func(name string) string {
 				if attr := comp.GetAttribute(name); attr != nil {
@@ -906,17 +1296,30 @@ func (c *MJMLComponent) extractHeadMetadata() (string, []string) {
 
 			fontName := getAttr("name")
 			fontHref := getAttr("href")
-			if fontName != "" && fontHref != "" {
+			if fontName == "" || fontHref == "" {
+				continue
+			}
+			if fonts.FontFileFormat(fontHref) == "" {
 				customFonts = append(customFonts, fontHref)
+				continue
+			}
+			i := slices.IndexFunc(fontFaces, func(face fonts.FontFace) bool { return face.Family == fontName })
+			if i < 0 {
+				fontFaces = append(fontFaces, fonts.FontFace{Family: fontName})
+				i = len(fontFaces) - 1
 			}
+			fontFaces[i].URLs = append(fontFaces[i].URLs, fontHref)
 		}
 	}
 
 	if c.RenderOpts != nil {
+		if c.RenderOpts.OverrideTitle != "" {
+			title = html.EscapeString(c.RenderOpts.OverrideTitle)
+		}
 		c.RenderOpts.Title = title
 	}
 
-	return title, customFonts
+	return title, customFonts, fontFaces
 }
 
 // generateCustomStyles generates the final mj-style content tag (MRML lines 240-244)
@@ -993,6 +1396,19 @@ func (c *MJMLComponent) generateCarouselCSS() string {
 	return "<style type=\"text/css\">" + c.carouselCSS.String() + "</style>"
 }
 
+// writeHeadStyle writes head style markup, or collects its style tags when
+// head styles are merged.
+func (c *MJMLComponent) writeHeadStyle(w io.StringWriter, markup string) error {
+	if c.headStyles != nil {
+		markup = c.headStyles.add(markup)
+	}
+	if markup == "" {
+		return nil
+	}
+	_, err := w.WriteString(markup)
+	return err
+}
+
 // hasMobileCSSComponents recursively checks if any component needs mobile CSS
 func (c *MJMLComponent) hasMobileCSSComponents() bool {
 	if c.Body == nil {
@@ -1184,14 +1600,15 @@ func (c *MJMLComponent) GetTagName() string {
 
 // Render implements optimized Writer-based rendering for MJMLComponent
 func (c *MJMLComponent) Render(w io.StringWriter) error {
-	debugEnabled := debug.Enabled()
+	logger := c.RenderOpts.Logger
+	debugEnabled := logger.Enabled()
 	if debugEnabled {
-		debug.DebugLog("mjml-root", "render-start", "Starting root MJML component rendering")
+		logger.Log("mjml-root", "render-start", "Starting root MJML component rendering")
 	}
 
 	// First, prepare the body to establish sibling relationships without full rendering
 	if debugEnabled {
-		debug.DebugLog("mjml-root", "prepare-siblings", "Preparing body sibling relationships")
+		logger.Log("mjml-root", "prepare-siblings", "Preparing body sibling relationships")
 	}
 	if c.Body != nil {
 		c.prepareBodySiblings(c.Body)
@@ -1199,66 +1616,64 @@ func (c *MJMLComponent) Render(w io.StringWriter) error {
 
 	// Now collect column classes after sibling relationships are established
 	if debugEnabled {
-		debug.DebugLog("mjml-root", "collect-column-classes", "Collecting column classes for responsive CSS")
+		logger.Log("mjml-root", "collect-column-classes", "Collecting column classes for responsive CSS")
 	}
 	c.collectColumnClasses()
 	if debugEnabled {
-		debug.DebugLogWithData("mjml-root", "column-classes-collected", "Column classes collected", map[string]interface{}{
+		logger.LogWithData("mjml-root", "column-classes-collected", "Column classes collected", map[string]interface{}{
 			"class_count": len(c.columnClasses),
 		})
 	}
 
 	// Collect carousel CSS from all carousel components
 	if debugEnabled {
-		debug.DebugLog("mjml-root", "collect-carousel-css", "Collecting carousel CSS")
+		logger.Log("mjml-root", "collect-carousel-css", "Collecting carousel CSS")
 	}
 	c.collectCarouselCSS()
 
 	// Extract head metadata (title, custom fonts) before rendering body so accessibility
 	// attributes can access the document title during body rendering.
-	title, customFonts := c.extractHeadMetadata()
+	title, customFonts, fontFaces := c.extractHeadMetadata()
 
 	// Generate body content once for both font detection and final output
 	if debugEnabled {
-		debug.DebugLog("mjml-root", "render-body", "Rendering body content for font analysis and output")
+		logger.Log("mjml-root", "render-body", "Rendering body content for font analysis and output")
 	}
 	var bodyBuffer strings.Builder
 	if c.Body != nil {
-		if err := c.Body.Render(&bodyBuffer); err != nil {
-			if debugEnabled {
-				debug.DebugLogError("mjml-root", "render-body-error", "Failed to render body", err)
-			}
+		if err := c.RenderChild(c.Body, &bodyBuffer); err != nil {
+			logger.LogError("mjml-root", "render-body-error", "Failed to render body", err)
 			return err
 		}
 	}
 	bodyContent := bodyBuffer.String()
 	if debugEnabled {
-		debug.DebugLogWithData("mjml-root", "render-complete", "Body rendering completed", map[string]interface{}{
+		logger.LogWithData("mjml-root", "render-complete", "Body rendering completed", map[string]interface{}{
 			"body_length": len(bodyContent),
 		})
 	}
 
-	// DOCTYPE and HTML opening - include attributes from MJML root element
-	var langValue string
-	if langAttr := c.GetAttribute("lang"); langAttr != nil {
-		langValue = *langAttr
-	} else {
-		langValue = constants.LangUndetermined
-	}
-
-	var dirValue string
-	if dirAttr := c.GetAttribute("dir"); dirAttr != nil {
-		dirValue = *dirAttr
-	} else {
-		dirValue = constants.DirAuto
+	// mj-raw position="file-start" content precedes the doctype, one per line
+	for _, raw := range c.fileStartRaws {
+		if err := raw.Render(w); err != nil {
+			return err
+		}
+		if _, err := w.WriteString("\n"); err != nil {
+			return err
+		}
 	}
 
-	if _, err := w.WriteString(`<!doctype html><html lang="` + langValue + `" dir="` + dirValue + `" xmlns="http://www.w3.org/1999/xhtml" xmlns:v="urn:schemas-microsoft-com:vml" xmlns:o="urn:schemas-microsoft-com:office:office">`); err != nil {
+	// DOCTYPE and HTML opening - lang and dir were resolved from the MJML root
+	// element (or the render options) when the component was created
+	if err := writeDocumentStart(w, c.RenderOpts, bodyContent); err != nil {
 		return err
 	}
 	if _, err := w.WriteString(`<head>`); err != nil {
 		return err
 	}
+	if err := c.writeHeadHTML(w, options.HeadStart); err != nil {
+		return err
+	}
 
 	if _, err := w.WriteString(`<title>` + title + `</title>`); err != nil {
 		return err
@@ -1272,6 +1687,18 @@ func (c *MJMLComponent) Render(w io.StringWriter) error {
 	if _, err := w.WriteString(`<meta name="viewport" content="width=device-width,initial-scale=1">`); err != nil {
 		return err
 	}
+	if c.RenderOpts != nil && c.RenderOpts.DisableAppleReformatting {
+		if _, err := w.WriteString(`<meta name="x-apple-disable-message-reformatting">`); err != nil {
+			return err
+		}
+	}
+	if err := c.writeHeadHTML(w, options.HeadAfterMeta); err != nil {
+		return err
+	}
+
+	if c.RenderOpts != nil && c.RenderOpts.MergeHeadStyles {
+		c.headStyles = newHeadStyleRegistry(c.RenderOpts.MinifyHeadStyles)
+	}
 
 	// Base CSS
 	baseCSSText := `<style type="text/css">#outlook a { padding:0; }
@@ -1279,7 +1706,7 @@ func (c *MJMLComponent) Render(w io.StringWriter) error {
       table, td { border-collapse:collapse;mso-table-lspace:0pt;mso-table-rspace:0pt; }
       img { border:0;height:auto;line-height:100%; outline:none;text-decoration:none;-ms-interpolation-mode:bicubic; }
       p { display:block;margin:13px 0; }</style>`
-	if _, err := w.WriteString(baseCSSText); err != nil {
+	if err := c.writeHeadStyle(w, baseCSSText); err != nil {
 		return err
 	}
 
@@ -1310,9 +1737,13 @@ func (c *MJMLComponent) Render(w io.StringWriter) error {
 
 	// Get fonts tracked during component rendering
 	trackedFonts := c.RenderOpts.FontTracker.GetFonts()
-	detectedFonts := fonts.ConvertFontFamiliesToURLs(trackedFonts)
+	fontMapping := fonts.GoogleFontsMapping
+	if c.RenderOpts.Fonts != nil {
+		fontMapping = c.RenderOpts.Fonts
+	}
+	detectedFonts := fonts.FontURLs(trackedFonts, fontMapping)
 	if debugEnabled {
-		debug.DebugLogWithData(
+		logger.LogWithData(
 			"font-detection",
 			"component-tracking",
 			"Fonts tracked from components",
@@ -1347,11 +1778,11 @@ func (c *MJMLComponent) Render(w io.StringWriter) error {
 	// This matches MRML's behavior: explicit fonts override default font imports
 	// Also respect custom global fonts from mj-all attributes
 	// Special case: social components with only default fonts should trigger Ubuntu fallback
-	hasOnlyDefaultFonts := len(detectedFonts) == 1 && detectedFonts[0] == fonts.GetGoogleFontURL(fonts.DefaultFontStack)
+	hasOnlyDefaultFonts := len(detectedFonts) == 1 && detectedFonts[0] == fonts.FontURL(fonts.DefaultFontStack, fontMapping)
 	// Pass trackedFonts count to check if ANY fonts (including system fonts) were used
 	if c.shouldImportDefaultFonts(detectedFonts, len(trackedFonts), hasText, hasSocial, hasButtons, hasOnlyDefaultFonts) {
 		if debugEnabled {
-			debug.DebugLogWithData(
+			logger.LogWithData(
 				"font-detection",
 				"check-defaults",
 				"No content fonts detected, checking defaults",
@@ -1360,9 +1791,13 @@ func (c *MJMLComponent) Render(w io.StringWriter) error {
 				},
 			)
 		}
-		defaultFonts := fonts.DetectDefaultFonts(hasText, hasSocial, hasButtons)
+		// Mirrors fonts.DetectDefaultFonts with the render's font mapping
+		var defaultFonts []string
+		if hasText || hasSocial || hasButtons {
+			defaultFonts = fonts.FontURLs([]string{fonts.DefaultFontStack}, fontMapping)
+		}
 		if debugEnabled {
-			debug.DebugLogWithData("font-detection", "default-fonts", "Default fonts to import", map[string]interface{}{
+			logger.LogWithData("font-detection", "default-fonts", "Default fonts to import", map[string]interface{}{
 				"count": len(defaultFonts),
 				"fonts": strings.Join(defaultFonts, ","),
 			})
@@ -1382,7 +1817,7 @@ func (c *MJMLComponent) Render(w io.StringWriter) error {
 		}
 	} else {
 		if debugEnabled {
-			debug.DebugLogWithData("font-detection", "skip-defaults", "Skipping default fonts", map[string]interface{}{
+			logger.LogWithData("font-detection", "skip-defaults", "Skipping default fonts", map[string]interface{}{
 				"detected_count": len(detectedFonts),
 				"has_social":     hasSocial,
 			})
@@ -1391,24 +1826,43 @@ func (c *MJMLComponent) Render(w io.StringWriter) error {
 
 	// Generate font import HTML
 	if debugEnabled {
-		debug.DebugLogWithData("font-detection", "final-list", "Final fonts to import", map[string]interface{}{
+		logger.LogWithData("font-detection", "final-list", "Final fonts to import", map[string]interface{}{
 			"total_count": len(allFontsToImport),
 			"fonts":       strings.Join(allFontsToImport, ","),
 		})
 	}
+	switch c.RenderOpts.ExternalResources {
+	case options.ExternalResourcesOffline:
+		allFontsToImport = nil
+		fontFaces = nil
+	case options.ExternalResourcesHinted:
+		if _, err := w.WriteString(buildNetworkHints(allFontsToImport, fontFaces, bodyContent)); err != nil {
+			return err
+		}
+	}
 	if len(allFontsToImport) > 0 {
 		fontImportsHTML := fonts.BuildFontsTags(allFontsToImport)
 		if _, err := w.WriteString(fontImportsHTML); err != nil {
 			return err
 		}
 	}
+	if len(fontFaces) > 0 {
+		if _, err := w.WriteString(fonts.BuildFontFaces(fontFaces)); err != nil {
+			return err
+		}
+	}
 
 	// Dynamic responsive CSS based on collected column classes - only if we have columns
 	if len(c.columnClasses) > 0 {
 		responsiveCSS := c.generateResponsiveCSS()
-		if _, err := w.WriteString(responsiveCSS); err != nil {
+		if err := c.writeHeadStyle(w, responsiveCSS); err != nil {
 			return err
 		}
+		if c.forceOWADesktop() {
+			if err := c.writeHeadStyle(w, c.generateOWACSS()); err != nil {
+				return err
+			}
+		}
 	}
 
 	// Mobile CSS - add only if components need it (following MRML pattern)
@@ -1418,7 +1872,7 @@ func (c *MJMLComponent) Render(w io.StringWriter) error {
                 td.mj-full-width-mobile { width: auto !important; }
             }
             </style>`
-		if _, err := w.WriteString(mobileCSSText); err != nil {
+		if err := c.writeHeadStyle(w, mobileCSSText); err != nil {
 			return err
 		}
 	}
@@ -1426,7 +1880,7 @@ func (c *MJMLComponent) Render(w io.StringWriter) error {
 	// Accordion CSS - add only if components need it (following MRML pattern)
 	if c.hasAccordionComponents() {
 		accordionCSSText := c.generateAccordionCSS()
-		if _, err := w.WriteString(accordionCSSText); err != nil {
+		if err := c.writeHeadStyle(w, accordionCSSText); err != nil {
 			return err
 		}
 	}
@@ -1434,7 +1888,7 @@ func (c *MJMLComponent) Render(w io.StringWriter) error {
 	// Navbar CSS - add only if components need it (following MRML pattern)
 	if c.hasNavbarComponents() {
 		navbarCSSText := c.generateNavbarCSS()
-		if _, err := w.WriteString(navbarCSSText); err != nil {
+		if err := c.writeHeadStyle(w, navbarCSSText); err != nil {
 			return err
 		}
 	}
@@ -1442,16 +1896,25 @@ func (c *MJMLComponent) Render(w io.StringWriter) error {
 	// Carousel CSS - add only if components need it (following MRML pattern)
 	if c.hasCarouselComponents() {
 		carouselCSSText := c.generateCarouselCSS()
-		if _, err := w.WriteString(carouselCSSText); err != nil {
+		if err := c.writeHeadStyle(w, carouselCSSText); err != nil {
 			return err
 		}
 	}
 
 	// Custom styles from mj-style components (MRML lines 240-244)
 	customStyles := c.generateCustomStyles()
-	if _, err := w.WriteString(customStyles); err != nil {
+	if err := c.writeHeadStyle(w, customStyles); err != nil {
 		return err
 	}
+	if c.headStyles != nil {
+		// Merged head styles are written where the mj-style content would be.
+		if merged := c.headStyles.String(); merged != "" {
+			customStyles = headStyleOpenTag + merged + "</style>"
+			if _, err := w.WriteString(customStyles); err != nil {
+				return err
+			}
+		}
+	}
 	if c.RenderOpts != nil && c.RenderOpts.RequireEmptyStyleTag && customStyles == "" {
 		if _, err := w.WriteString(`<style type="text/css"></style>`); err != nil {
 			return err
@@ -1470,6 +1933,9 @@ func (c *MJMLComponent) Render(w io.StringWriter) error {
 			}
 		}
 	}
+	if err := c.writeHeadHTML(w, options.HeadEnd); err != nil {
+		return err
+	}
 
 	if _, err := w.WriteString(`</head>`); err != nil {
 		return err
@@ -1484,8 +1950,8 @@ func (c *MJMLComponent) Render(w io.StringWriter) error {
 	}
 
 	if c.Body != nil {
-		if bgColor := c.Body.GetAttribute("background-color"); bgColor != nil && *bgColor != "" {
-			bodyStyles = append(bodyStyles, "background-color:"+*bgColor)
+		if bgColor := c.Body.GetAttributeWithDefault(c.Body, "background-color"); bgColor != "" {
+			bodyStyles = append(bodyStyles, "background-color:"+bgColor)
 		}
 	}
 
@@ -1503,7 +1969,11 @@ func (c *MJMLComponent) Render(w io.StringWriter) error {
 	}
 
 	// Add preview text from head components right after body tag
-	if c.Head != nil {
+	if c.RenderOpts != nil && c.RenderOpts.OverridePreview != "" {
+		if err := components.WritePreviewText(w, html.EscapeString(c.RenderOpts.OverridePreview), c.RenderOpts.PreheaderPadding); err != nil {
+			return err
+		}
+	} else if c.Head != nil {
 		for _, child := range c.Head.Children {
 			if previewComp, ok := child.(*components.MJPreviewComponent); ok {
 				if err := previewComp.Render(w); err != nil {
@@ -1523,3 +1993,19 @@ func (c *MJMLComponent) Render(w io.StringWriter) error {
 
 	return nil
 }
+
+// writeHeadHTML writes the markup added to the head at position.
+func (c *MJMLComponent) writeHeadHTML(w io.StringWriter, position options.HeadPosition) error {
+	if c.RenderOpts == nil {
+		return nil
+	}
+	for _, head := range c.RenderOpts.HeadHTML {
+		if head.Position != position {
+			continue
+		}
+		if _, err := w.WriteString(head.HTML); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const renderFromASTDocument = `<mjml>
+  <mj-head><mj-style>.note { color: #333333 !important; }</mj-style></mj-head>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-text css-class="note">Hello <span onclick="track()">there</span></mj-text>
+        <mj-button href="https://example.com">Go</mj-button>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+// TestRenderFromASTOptions checks that RenderFromAST applies the options
+// handled after the component tree is rendered like Render does.
+func TestRenderFromASTOptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		opt   func(got *string) RenderOption
+		check func(t *testing.T, output, hooked string)
+	}{
+		{
+			name: "WithoutOutlookSupport",
+			opt:  func(*string) RenderOption { return WithoutOutlookSupport() },
+			check: func(t *testing.T, output, _ string) {
+				if strings.Contains(output, "<!--[if mso") || strings.Contains(output, "urn:schemas-microsoft-com") {
+					t.Error("Outlook conditional comments or namespaces were kept")
+				}
+			},
+		},
+		{
+			name: "WithHTMLTransform",
+			opt: func(*string) RenderOption {
+				return WithHTMLTransform(func(doc *html.Node) error {
+					var walk func(n *html.Node)
+					walk = func(n *html.Node) {
+						if n.DataAtom == atom.Body {
+							n.AppendChild(&html.Node{Type: html.ElementNode, Data: "img", DataAtom: atom.Img,
+								Attr: []html.Attribute{{Key: "src", Val: "https://example.com/pixel.gif"}}})
+						}
+						for c := n.FirstChild; c != nil; c = c.NextSibling {
+							walk(c)
+						}
+					}
+					walk(doc)
+					return nil
+				})
+			},
+			check: func(t *testing.T, output, _ string) {
+				if !strings.Contains(output, `<img src="https://example.com/pixel.gif"/>`) {
+					t.Error("transform was not applied")
+				}
+			},
+		},
+		{
+			name: "WithStyleNonce",
+			opt:  func(*string) RenderOption { return WithStyleNonce("abc123") },
+			check: func(t *testing.T, output, _ string) {
+				if strings.Count(output, "<style") != strings.Count(output, `<style nonce="abc123"`) {
+					t.Error("not every style tag has the nonce")
+				}
+			},
+		},
+		{
+			name: "WithStripEventHandlers",
+			opt:  func(*string) RenderOption { return WithStripEventHandlers() },
+			check: func(t *testing.T, output, _ string) {
+				if strings.Contains(output, "onclick") {
+					t.Error("event handler attribute was kept")
+				}
+			},
+		},
+		{
+			name: "FormatAMP",
+			opt:  func(*string) RenderOption { return WithOutputFormat(FormatAMP) },
+			check: func(t *testing.T, output, _ string) {
+				if !strings.Contains(output, "<html ⚡4email") || !strings.Contains(output, "<style amp-custom>") {
+					t.Error("output was not converted to AMP")
+				}
+			},
+		},
+		{
+			name: "AfterRender",
+			opt: func(got *string) RenderOption {
+				return func(opts *RenderOpts) {
+					opts.AfterRender = func(html string) { *got = html }
+				}
+			},
+			check: func(t *testing.T, output, hooked string) {
+				if hooked != output {
+					t.Error("AfterRender did not receive the final document")
+				}
+			},
+		},
+		{
+			name: "WithSizeReport",
+			opt: func(got *string) RenderOption {
+				return WithSizeReport(0, func(report SizeReport) {
+					if report.TotalBytes > 0 && len(report.Components) == 1 {
+						*got = "reported"
+					}
+				})
+			},
+			check: func(t *testing.T, _, hooked string) {
+				if hooked != "reported" {
+					t.Error("size report was not delivered")
+				}
+			},
+		},
+	}
+
+	ast, err := ParseMJML(renderFromASTDocument)
+	if err != nil {
+		t.Fatalf("ParseMJML() error = %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hooked, hookedRender string
+			output, err := RenderFromAST(ast, tt.opt(&hooked))
+			if err != nil {
+				t.Fatalf("RenderFromAST() error = %v", err)
+			}
+			want, err := Render(renderFromASTDocument, tt.opt(&hookedRender))
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if normalizeGroupColumnClassOrder(output) != want {
+				t.Error("RenderFromAST() output differs from Render()")
+			}
+			tt.check(t, output, hooked)
+		})
+	}
+}
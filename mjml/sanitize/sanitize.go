@@ -0,0 +1,339 @@
+// Package sanitize provides an allowlist-based HTML sanitizer for the raw HTML
+// fragments embedded in MJML documents (mj-text, mj-raw and mj-table content).
+// It is intended for platforms that render customer-authored MJML and must not
+// let script or style injection through to the generated email.
+package sanitize
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// globalAttrKey is the Policy key holding attributes allowed on every tag
+const globalAttrKey = "*"
+
+// urlAttributes lists attributes whose values are treated as URLs and checked
+// against the allowed URL schemes.
+var urlAttributes = map[string]struct{}{
+	"href":       {},
+	"src":        {},
+	"background": {},
+	"action":     {},
+	"poster":     {},
+}
+
+// Policy describes which tags, attributes and URL schemes survive sanitization.
+// A Policy is safe for concurrent use once it is no longer being modified.
+type Policy struct {
+	tags    map[string]struct{}
+	attrs   map[string]map[string]struct{}
+	schemes map[string]struct{}
+}
+
+// NewPolicy returns an empty policy that strips every tag.
+// Use AllowTags and AllowAttrs to build it up.
+func NewPolicy() *Policy {
+	return &Policy{
+		tags:    make(map[string]struct{}),
+		attrs:   make(map[string]map[string]struct{}),
+		schemes: make(map[string]struct{}),
+	}
+}
+
+// DefaultPolicy returns a policy suitable for typical email content: text
+// formatting, links, images, lists and tables, with inline styles allowed.
+// Scripts, styles, forms, frames and event handler attributes are removed.
+func DefaultPolicy() *Policy {
+	return NewPolicy().
+		AllowTags(
+			"a", "abbr", "b", "blockquote", "br", "center", "code", "del", "div",
+			"em", "font", "h1", "h2", "h3", "h4", "h5", "h6", "hr", "i", "img",
+			"ins", "li", "ol", "p", "pre", "s", "small", "span", "strike", "strong",
+			"sub", "sup", "table", "tbody", "td", "tfoot", "th", "thead", "tr", "u", "ul",
+		).
+		AllowAttrs(globalAttrKey, "align", "class", "dir", "id", "lang", "style", "title").
+		AllowAttrs("a", "href", "name", "rel", "target").
+		AllowAttrs("img", "alt", "border", "height", "src", "width").
+		AllowAttrs("font", "color", "face", "size").
+		AllowAttrs("table", "bgcolor", "border", "cellpadding", "cellspacing", "width").
+		AllowAttrs("td", "bgcolor", "colspan", "height", "rowspan", "valign", "width").
+		AllowAttrs("th", "bgcolor", "colspan", "height", "rowspan", "valign", "width").
+		AllowAttrs("tr", "bgcolor", "valign").
+		AllowURLSchemes("http", "https", "mailto", "tel")
+}
+
+// AllowTags adds the given tag names to the allowlist.
+func (p *Policy) AllowTags(tags ...string) *Policy {
+	for _, tag := range tags {
+		p.tags[strings.ToLower(tag)] = struct{}{}
+	}
+	return p
+}
+
+// DisallowTags removes the given tag names from the allowlist.
+func (p *Policy) DisallowTags(tags ...string) *Policy {
+	for _, tag := range tags {
+		delete(p.tags, strings.ToLower(tag))
+	}
+	return p
+}
+
+// AllowAttrs allows the given attributes on a tag. Use "*" as the tag name to
+// allow attributes on every allowed tag.
+func (p *Policy) AllowAttrs(tag string, attrs ...string) *Policy {
+	tag = strings.ToLower(tag)
+	set := p.attrs[tag]
+	if set == nil {
+		set = make(map[string]struct{}, len(attrs))
+		p.attrs[tag] = set
+	}
+	for _, attr := range attrs {
+		set[strings.ToLower(attr)] = struct{}{}
+	}
+	return p
+}
+
+// AllowURLSchemes adds URL schemes permitted in href/src-like attributes.
+// Relative URLs and fragments are always allowed.
+func (p *Policy) AllowURLSchemes(schemes ...string) *Policy {
+	for _, scheme := range schemes {
+		p.schemes[strings.ToLower(scheme)] = struct{}{}
+	}
+	return p
+}
+
+func (p *Policy) tagAllowed(tag string) bool {
+	_, ok := p.tags[tag]
+	return ok
+}
+
+func (p *Policy) attrAllowed(tag, attr string) bool {
+	if strings.HasPrefix(attr, "on") {
+		return false
+	}
+	if set, ok := p.attrs[tag]; ok {
+		if _, ok := set[attr]; ok {
+			return true
+		}
+	}
+	if set, ok := p.attrs[globalAttrKey]; ok {
+		if _, ok := set[attr]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// urlAllowed reports whether value has no scheme or an allowed one. The value
+// is checked as a browser would read it: entities are decoded until none are
+// left, so that an attribute written back with &amp; cannot smuggle in a
+// scheme, and whitespace, control and invisible formatting characters are
+// removed. A colon anywhere means the value must start with an allowed scheme.
+func (p *Policy) urlAllowed(value string) bool {
+	decoded := value
+	for {
+		unescaped := html.UnescapeString(decoded)
+		if unescaped == decoded {
+			break
+		}
+		decoded = unescaped
+	}
+	decoded = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsControl(r) || unicode.Is(unicode.Cf, r) {
+			return -1
+		}
+		return r
+	}, decoded)
+
+	colon := strings.IndexByte(decoded, ':')
+	if colon == -1 {
+		return true
+	}
+	_, ok := p.schemes[strings.ToLower(decoded[:colon])]
+	return ok
+}
+
+// styleAllowed rejects legacy CSS constructs that can execute script.
+func styleAllowed(value string) bool {
+	lower := strings.ToLower(value)
+	return !strings.Contains(lower, "expression(") &&
+		!strings.Contains(lower, "javascript:") &&
+		!strings.Contains(lower, "behavior:") &&
+		!strings.Contains(lower, "-moz-binding")
+}
+
+// dropContentTags lists tags whose entire content is discarded when the tag
+// itself is not allowed, rather than keeping the inner text. It includes
+// every raw text and RCDATA element, whose content the tokenizer returns as
+// a single text token that may hold markup.
+var dropContentTags = map[string]struct{}{
+	"script":    {},
+	"style":     {},
+	"iframe":    {},
+	"object":    {},
+	"embed":     {},
+	"noscript":  {},
+	"noembed":   {},
+	"noframes":  {},
+	"xmp":       {},
+	"plaintext": {},
+	"title":     {},
+	"template":  {},
+	"textarea":  {},
+	"select":    {},
+}
+
+// textEscaper escapes the markup characters of text content. Entities are
+// left as written, so they survive sanitization unchanged.
+var textEscaper = strings.NewReplacer("<", "&lt;", ">", "&gt;")
+
+// Sanitize returns fragment with every disallowed tag, attribute and comment
+// removed. Text content keeps its entities and whitespace, with < and >
+// escaped, so that text the tokenizer read as raw text cannot be written back
+// as markup.
+func (p *Policy) Sanitize(fragment string) string {
+	if fragment == "" || strings.IndexByte(fragment, '<') == -1 {
+		return fragment
+	}
+
+	var out strings.Builder
+	out.Grow(len(fragment))
+
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	skipDepth := 0
+	skipTag := ""
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return out.String()
+		}
+
+		if skipDepth > 0 {
+			name, _ := z.TagName()
+			switch tt {
+			case html.StartTagToken:
+				if string(name) == skipTag {
+					skipDepth++
+				}
+			case html.EndTagToken:
+				if string(name) == skipTag {
+					skipDepth--
+				}
+			}
+			continue
+		}
+
+		switch tt {
+		case html.TextToken:
+			textEscaper.WriteString(&out, string(z.Raw()))
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			if !p.tagAllowed(tok.Data) {
+				if _, drop := dropContentTags[tok.Data]; drop && tt == html.StartTagToken {
+					skipTag = tok.Data
+					skipDepth = 1
+				}
+				continue
+			}
+			p.writeStartTag(&out, tok, tt == html.SelfClosingTagToken)
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			if p.tagAllowed(string(name)) {
+				out.WriteString("</")
+				out.Write(name)
+				out.WriteByte('>')
+			}
+		case html.CommentToken, html.DoctypeToken:
+			// Comments can hide conditional markup for specific clients, so they are dropped.
+		}
+	}
+}
+
+func (p *Policy) writeStartTag(out *strings.Builder, tok html.Token, selfClosing bool) {
+	allowed := tok.Attr[:0]
+	for _, attr := range tok.Attr {
+		attr.Key = strings.ToLower(attr.Key)
+		if attr.Namespace != "" || !p.attrAllowed(tok.Data, attr.Key) {
+			continue
+		}
+		if _, isURL := urlAttributes[attr.Key]; isURL && !p.urlAllowed(attr.Val) {
+			continue
+		}
+		if attr.Key == "style" && !styleAllowed(attr.Val) {
+			continue
+		}
+		allowed = append(allowed, attr)
+	}
+	tok.Attr = allowed
+	writeTag(out, tok, selfClosing)
+}
+
+// writeTag writes a start tag with double-quoted attribute values.
+func writeTag(out *strings.Builder, tok html.Token, selfClosing bool) {
+	out.WriteByte('<')
+	out.WriteString(tok.Data)
+	for _, attr := range tok.Attr {
+		out.WriteByte(' ')
+		if attr.Namespace != "" {
+			out.WriteString(attr.Namespace)
+			out.WriteByte(':')
+		}
+		out.WriteString(attr.Key)
+		out.WriteString(`="`)
+		out.WriteString(escapeAttribute(attr.Val))
+		out.WriteByte('"')
+	}
+	if selfClosing {
+		out.WriteString(" />")
+		return
+	}
+	out.WriteByte('>')
+}
+
+// escapeAttribute escapes an attribute value for a double-quoted attribute.
+// The tokenizer has decoded its entities, so & is escaped too; otherwise a
+// value such as &amp;#106; would be written back as the live reference &#106;.
+func escapeAttribute(value string) string {
+	return html.EscapeString(value)
+}
+
+// StripEventHandlers returns fragment with every event handler attribute
+// (onclick, onload, ...) removed. Unlike Sanitize it keeps all tags and
+// comments; tags without event handlers are written byte-for-byte.
+func StripEventHandlers(fragment string) string {
+	if !strings.Contains(strings.ToLower(fragment), "on") {
+		return fragment
+	}
+
+	var out strings.Builder
+	out.Grow(len(fragment))
+
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return out.String()
+		}
+		raw := z.Raw()
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			out.Write(raw)
+			continue
+		}
+		tok := z.Token()
+		if !slices.ContainsFunc(tok.Attr, isEventHandler) {
+			out.Write(raw)
+			continue
+		}
+		tok.Attr = slices.DeleteFunc(tok.Attr, isEventHandler)
+		writeTag(&out, tok, tt == html.SelfClosingTagToken)
+	}
+}
+
+// isEventHandler reports whether attr is an event handler attribute.
+func isEventHandler(attr html.Attribute) bool {
+	return len(attr.Key) > 2 && strings.EqualFold(attr.Key[:2], "on")
+}
@@ -0,0 +1,151 @@
+package sanitize
+
+import "testing"
+
+func TestDefaultPolicySanitize(t *testing.T) {
+	policy := DefaultPolicy()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "plain text untouched",
+			input: "Hello &amp; welcome",
+			want:  "Hello &amp; welcome",
+		},
+		{
+			name:  "allowed formatting kept",
+			input: `<p style="color:red">Hi <strong>there</strong><br /></p>`,
+			want:  `<p style="color:red">Hi <strong>there</strong><br /></p>`,
+		},
+		{
+			name:  "script removed with content",
+			input: `before<script>alert(1)</script>after`,
+			want:  `beforeafter`,
+		},
+		{
+			name:  "style block removed",
+			input: `<style>body{display:none}</style><span>ok</span>`,
+			want:  `<span>ok</span>`,
+		},
+		{
+			name:  "event handlers stripped",
+			input: `<img src="https://example.com/a.png" onerror="alert(1)" alt="a">`,
+			want:  `<img src="https://example.com/a.png" alt="a">`,
+		},
+		{
+			name:  "javascript url stripped",
+			input: `<a href="javascript:alert(1)">x</a>`,
+			want:  `<a>x</a>`,
+		},
+		{
+			name:  "obfuscated javascript url stripped",
+			input: `<a href="java&#09;script:alert(1)">x</a>`,
+			want:  `<a>x</a>`,
+		},
+		{
+			name:  "entity-encoded javascript url stripped",
+			input: `<a href="&amp;#106;avascript:alert(1)">x</a><a href="&#x6A;avascript&#58;alert(1)">y</a>`,
+			want:  `<a>x</a><a>y</a>`,
+		},
+		{
+			name:  "mixed-case javascript url stripped",
+			input: `<a href=" JaVaScRiPt:alert(1)">x</a><img src="VBScript:msgbox(1)">`,
+			want:  `<a>x</a><img>`,
+		},
+		{
+			name:  "template placeholder before scheme stripped",
+			input: `<a href="{{ .Prefix }}javascript:alert(1)">x</a><a href="{{ .URL }}">y</a>`,
+			want:  `<a>x</a><a href="{{ .URL }}">y</a>`,
+		},
+		{
+			name:  "ampersands escaped",
+			input: `<a href="https://example.com/?a=1&amp;b=2" title="R&amp;D">x</a>`,
+			want:  `<a href="https://example.com/?a=1&amp;b=2" title="R&amp;D">x</a>`,
+		},
+		{
+			name:  "relative and mailto urls kept",
+			input: `<a href="/path?a=1">a</a><a href="mailto:me@example.com">b</a>`,
+			want:  `<a href="/path?a=1">a</a><a href="mailto:me@example.com">b</a>`,
+		},
+		{
+			name:  "unknown tag unwrapped",
+			input: `<marquee>move</marquee>`,
+			want:  `move`,
+		},
+		{
+			name:  "comments dropped",
+			input: `a<!--[if mso]><script>x</script><![endif]-->b`,
+			want:  `ab`,
+		},
+		{
+			name:  "css expression dropped",
+			input: `<div style="width:expression(alert(1))">x</div>`,
+			want:  `<div>x</div>`,
+		},
+		{
+			name:  "raw text elements removed with content",
+			input: `a<xmp><script>alert(1)</script></xmp>b<noembed><img src=x onerror=alert(1)></noembed>c<noframes><script>alert(2)</script></noframes>d<title></p><script>alert(3)</script></title>e`,
+			want:  `abcde`,
+		},
+		{
+			name:  "plaintext removed with the rest of the fragment",
+			input: `a<plaintext><script>alert(1)</script>`,
+			want:  `a`,
+		},
+		{
+			name:  "markup characters in text escaped",
+			input: `1 < 2 &amp;&amp; 3 > 2`,
+			want:  `1 &lt; 2 &amp;&amp; 3 &gt; 2`,
+		},
+		{
+			name:  "attribute quotes escaped",
+			input: `<span title='a"b'>x</span>`,
+			want:  `<span title="a&#34;b">x</span>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Sanitize(tt.input); got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCustomPolicy(t *testing.T) {
+	policy := NewPolicy().AllowTags("b").AllowAttrs("b", "data-id")
+
+	got := policy.Sanitize(`<b data-id="1" class="x">bold</b><i>italic</i>`)
+	want := `<b data-id="1">bold</b>italic`
+	if got != want {
+		t.Errorf("Sanitize() = %q, want %q", got, want)
+	}
+
+	policy = DefaultPolicy().DisallowTags("img")
+	if got := policy.Sanitize(`<img src="a.png">text`); got != "text" {
+		t.Errorf("Sanitize() with disallowed img = %q, want %q", got, "text")
+	}
+}
+
+func TestStripEventHandlers(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"no handlers", `<p class='a'  id=x>Hi &amp; bye</p><!-- note -->`, `<p class='a'  id=x>Hi &amp; bye</p><!-- note -->`},
+		{"handlers removed", `<a href="#" OnClick="go()" onmouseover='x'>Go</a>`, `<a href="#">Go</a>`},
+		{"self-closing", `<img src="a.png" onerror="x()" />`, `<img src="a.png" />`},
+		{"script kept", `<script>if (a.onload) {}</script>`, `<script>if (a.onload) {}</script>`},
+		{"not a handler", `<td on="1" data-onclick="2">x</td>`, `<td on="1" data-onclick="2">x</td>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripEventHandlers(tt.in); got != tt.want {
+				t.Errorf("StripEventHandlers() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/preslavrachev/gomjml/mjml/sanitize"
+)
+
+func TestRenderWithSanitizeHTML(t *testing.T) {
+	input := `<mjml>
+  <mj-body>
+    <mj-raw><script>alert('raw')</script><p>raw ok</p></mj-raw>
+    <mj-section>
+      <mj-column>
+        <mj-text><p onclick="steal()">Hello</p><script>alert('text')</script></mj-text>
+        <mj-table><tr><td onmouseover="x()">cell</td></tr><script>alert('table')</script></mj-table>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	unsafe, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(unsafe, "alert('text')") {
+		t.Fatalf("expected unsanitized output to keep script content")
+	}
+
+	html, err := Render(input, WithSanitizeHTML())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, forbidden := range []string{"<script", "alert(", "onclick", "onmouseover"} {
+		if strings.Contains(html, forbidden) {
+			t.Errorf("sanitized output should not contain %q", forbidden)
+		}
+	}
+	for _, want := range []string{"<p>Hello</p>", "<p>raw ok</p>", "<td>cell</td>"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("sanitized output should contain %q", want)
+		}
+	}
+}
+
+func TestRenderWithSanitizeHTMLRawTextElements(t *testing.T) {
+	for _, tag := range []string{"xmp", "noembed", "noframes", "title", "plaintext", "textarea", "noscript", "iframe", "style"} {
+		payload := "<" + tag + "><script>alert(1)</script><img src=\"x\" onerror=\"alert(2)\"></" + tag + ">"
+		for _, container := range []string{
+			"<mj-raw>" + payload + "</mj-raw>",
+			"<mj-section><mj-column><mj-text>" + payload + "</mj-text></mj-column></mj-section>",
+		} {
+			input := "<mjml><mj-body>" + container + "</mj-body></mjml>"
+			html, err := Render(input, WithSanitizeHTML())
+			if err != nil {
+				t.Fatalf("%s: Render() error = %v", tag, err)
+			}
+			// The head has title, style and noscript elements of its own
+			html = html[strings.Index(html, "<body"):]
+			for _, forbidden := range []string{"<script", "alert(", "onerror", "<" + tag} {
+				if strings.Contains(html, forbidden) {
+					t.Errorf("%s: sanitized output of %s should not contain %q", tag, container[:8], forbidden)
+				}
+			}
+		}
+	}
+}
+
+func TestRenderWithSanitizePolicy(t *testing.T) {
+	input := `<mjml><mj-body><mj-section><mj-column>
+    <mj-text><b>bold</b> <i>italic</i></mj-text>
+  </mj-column></mj-section></mj-body></mjml>`
+
+	html, err := Render(input, WithSanitizePolicy(sanitize.NewPolicy().AllowTags("b")))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(html, "<b>bold</b> italic") {
+		t.Errorf("expected custom policy to keep <b> and strip <i>")
+	}
+}
+
+func TestRenderForStrictCSP(t *testing.T) {
+	input := `<mjml><mj-head><mj-style>.x { color: red; }</mj-style></mj-head><mj-body><mj-section><mj-column>
+    <mj-text><p onclick="alert(1)" class="x">Hello</p></mj-text>
+    <mj-raw><div onmouseover="alert(2)">raw</div><style>.y { color: blue; }</style></mj-raw>
+  </mj-column></mj-section></mj-body></mjml>`
+
+	html, err := Render(input, WithStripEventHandlers(), WithStyleNonce("r4nd0m"))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, forbidden := range []string{"onclick", "onmouseover", "<style>", `<style type="text/css">`} {
+		if strings.Contains(html, forbidden) {
+			t.Errorf("output should not contain %q", forbidden)
+		}
+	}
+	for _, want := range []string{`<p class="x">Hello</p>`, `<div>raw</div>`, `<style nonce="r4nd0m">.y`, `<style nonce="r4nd0m" type="text/css">`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("output should contain %q", want)
+		}
+	}
+	if got, want := strings.Count(html, "<style"), strings.Count(html, ` nonce="r4nd0m"`); got != want {
+		t.Errorf("%d style tags but %d nonces", got, want)
+	}
+}
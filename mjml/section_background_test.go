@@ -0,0 +1,36 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSectionBackgroundURLEncoding verifies that special characters in a
+// section background URL are encoded the same way in the background
+// attribute, the inline style and the VML fill.
+func TestSectionBackgroundURLEncoding(t *testing.T) {
+	for _, fullWidth := range []string{"", ` full-width="full-width"`} {
+		input := `<mjml><mj-body><mj-section` + fullWidth + ` background-url="https://example.com/my image (1).png?a=1&amp;b='2'" background-position="bottom right" background-size="cover" background-repeat="no-repeat">
+  <mj-column><mj-text>Hello</mj-text></mj-column>
+</mj-section></mj-body></mjml>`
+
+		html, err := Render(input)
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+
+		encoded := "https://example.com/my%20image%20%281%29.png?a=1&amp;b=%272%27"
+		for _, want := range []string{
+			`background="` + encoded + `"`,
+			`url('` + encoded + `') right bottom / cover no-repeat`,
+			`src="` + encoded + `"`,
+		} {
+			if !strings.Contains(html, want) {
+				t.Errorf("full-width=%q: expected %s in output", fullWidth, want)
+			}
+		}
+		if !strings.Contains(html, `origin="0.5, 0.5" position="0.5, 0.5"`) {
+			t.Errorf("full-width=%q: expected bottom right to map to VML 0.5, 0.5", fullWidth)
+		}
+	}
+}
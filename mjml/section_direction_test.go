@@ -0,0 +1,41 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSectionDirectionRTLWithColumns locks in MJML's right-to-left section output:
+// the section cell carries direction and text-align, columns keep their own ltr
+// direction, and Outlook cells stay in source order (MJML does not reorder them).
+func TestSectionDirectionRTLWithColumns(t *testing.T) {
+	input := `<mjml>
+  <mj-body>
+    <mj-section direction="rtl" text-align="right">
+      <mj-column><mj-text>First</mj-text></mj-column>
+      <mj-column direction="rtl"><mj-text>Second</mj-text></mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if !strings.Contains(html, `<td style="direction:rtl;font-size:0px;padding:20px 0;text-align:right;">`) {
+		t.Error("expected direction and text-align on the section cell")
+	}
+	if !strings.Contains(html, `<div class="mj-column-per-50 mj-outlook-group-fix" style="font-size:0px;text-align:left;direction:ltr;`) {
+		t.Error("expected first column to keep the default ltr direction")
+	}
+	if !strings.Contains(html, `<div class="mj-column-per-50 mj-outlook-group-fix" style="font-size:0px;text-align:left;direction:rtl;`) {
+		t.Error("expected second column to use its own direction")
+	}
+	if first, second := strings.Index(html, ">First<"), strings.Index(html, ">Second<"); first == -1 || second == -1 || first > second {
+		t.Error("expected columns to be emitted in source order")
+	}
+	if strings.Contains(html, `dir="rtl"`) {
+		t.Error("MJML does not add dir attributes to the Outlook tables")
+	}
+}
@@ -31,7 +31,7 @@ func TestSingleflightDoPanicCleanup(t *testing.T) {
 
 	go func() {
 		_, _ = singleflightDo(hash, func() (*MJMLNode, error) {
-			t.Fatal("second call should not execute")
+			t.Error("second call should not execute")
 			return nil, nil
 		})
 		close(done)
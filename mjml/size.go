@@ -0,0 +1,110 @@
+package mjml
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// GmailClipThreshold is the document size in bytes above which Gmail clips the
+// message body and hides the remainder behind a "View entire message" link.
+const GmailClipThreshold = 102 * 1024
+
+// sizeWarningRatio is the fraction of the budget at which an early warning is emitted
+const sizeWarningRatio = 0.9
+
+var (
+	headSection = regexp.MustCompile(`(?s)<head>.*?</head>`)
+	bodySection = regexp.MustCompile(`(?s)<body[^>]*>.*</body>`)
+	styleBlock  = regexp.MustCompile(`(?s)<style[^>]*>.*?</style>`)
+	// msoRevealedStart opens a downlevel-revealed block, which msoHiddenBlock
+	// also matches but whose content every client shows.
+	msoRevealedStart = regexp.MustCompile(`^<!--\[if [^\]]*\]><!-->`)
+)
+
+// ComponentSize records the rendered output size of a top-level body block.
+type ComponentSize struct {
+	TagName string `json:"tagName"`
+	Line    int    `json:"line"`
+	Bytes   int    `json:"bytes"`
+}
+
+// SizeReport summarizes where the bytes of a rendered email go.
+type SizeReport struct {
+	TotalBytes          int             `json:"totalBytes"`
+	HeadBytes           int             `json:"headBytes"`
+	BodyBytes           int             `json:"bodyBytes"`
+	StyleBytes          int             `json:"styleBytes"`          // Bytes inside <style> blocks
+	MSOConditionalBytes int             `json:"msoConditionalBytes"` // Bytes inside Outlook-only conditional comments
+	Budget              int             `json:"budget"`
+	Components          []ComponentSize `json:"components,omitempty"` // Only populated by WithSizeReport
+	Warnings            []string        `json:"warnings,omitempty"`
+}
+
+// Analyze measures a rendered HTML document against Gmail's clipping threshold.
+func Analyze(html string) SizeReport {
+	return analyzeWithBudget(html, GmailClipThreshold)
+}
+
+func analyzeWithBudget(html string, budget int) SizeReport {
+	report := SizeReport{
+		TotalBytes: len(html),
+		HeadBytes:  len(headSection.FindString(html)),
+		BodyBytes:  len(bodySection.FindString(html)),
+		Budget:     budget,
+	}
+	for _, block := range styleBlock.FindAllString(html, -1) {
+		report.StyleBytes += len(block)
+	}
+	for _, block := range msoHiddenBlock.FindAllString(html, -1) {
+		if !msoRevealedStart.MatchString(block) {
+			report.MSOConditionalBytes += len(block)
+		}
+	}
+
+	switch {
+	case report.TotalBytes > budget:
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"output is %d bytes, exceeding the %d byte budget; Gmail will clip the message", report.TotalBytes, budget))
+	case float64(report.TotalBytes) > float64(budget)*sizeWarningRatio:
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"output is %d bytes, within %d%% of the %d byte budget", report.TotalBytes, int((1-sizeWarningRatio)*100), budget))
+	}
+	return report
+}
+
+// WithSizeReport calls fn with a SizeReport for the rendered document, including the
+// size of every top-level body block in document order. A budget of zero or less
+// uses GmailClipThreshold. Blocks that alone consume more than half of the budget
+// produce an additional warning.
+func WithSizeReport(budget int, fn func(SizeReport)) RenderOption {
+	if budget <= 0 {
+		budget = GmailClipThreshold
+	}
+	return func(opts *RenderOpts) {
+		var sizes []ComponentSize
+		existingReporter := opts.ComponentSizeReporter
+		opts.ComponentSizeReporter = func(tagName string, line, bytes int) {
+			sizes = append(sizes, ComponentSize{TagName: tagName, Line: line, Bytes: bytes})
+			if existingReporter != nil {
+				existingReporter(tagName, line, bytes)
+			}
+		}
+
+		existingAfterRender := opts.AfterRender
+		opts.AfterRender = func(html string) {
+			report := analyzeWithBudget(html, budget)
+			report.Components = sizes
+			for _, size := range sizes {
+				if size.Bytes > budget/2 {
+					report.Warnings = append(report.Warnings, fmt.Sprintf(
+						"<%s> on line %d renders to %d bytes, more than half of the %d byte budget",
+						size.TagName, size.Line, size.Bytes, budget))
+				}
+			}
+			fn(report)
+			if existingAfterRender != nil {
+				existingAfterRender(html)
+			}
+		}
+	}
+}
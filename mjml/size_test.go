@@ -0,0 +1,96 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	html, err := Render(`<mjml><mj-body><mj-section><mj-column><mj-text>Hello</mj-text></mj-column></mj-section></mj-body></mjml>`)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	report := Analyze(html)
+	if report.TotalBytes != len(html) {
+		t.Errorf("TotalBytes = %d, want %d", report.TotalBytes, len(html))
+	}
+	if report.HeadBytes == 0 || report.BodyBytes == 0 || report.StyleBytes == 0 || report.MSOConditionalBytes == 0 {
+		t.Errorf("expected non-zero section sizes, got %+v", report)
+	}
+	if report.HeadBytes+report.BodyBytes > report.TotalBytes {
+		t.Errorf("head and body sizes exceed total: %+v", report)
+	}
+	if len(report.Warnings) != 0 {
+		t.Errorf("small document should not produce warnings, got %v", report.Warnings)
+	}
+	if report.Budget != GmailClipThreshold {
+		t.Errorf("Budget = %d, want %d", report.Budget, GmailClipThreshold)
+	}
+}
+
+func TestAnalyzeMSOConditionalBytes(t *testing.T) {
+	hidden := `<!--[if mso | IE]><table><tr><td><![endif]-->`
+	revealed := `<!--[if !mso]><!--><div class="desktop">Shown everywhere but Outlook</div><!--<![endif]-->`
+	html := `<html><head></head><body>` + hidden + revealed + `<p>Hi</p>` + hidden + `</body></html>`
+
+	report := Analyze(html)
+	if want := 2 * len(hidden); report.MSOConditionalBytes != want {
+		t.Errorf("MSOConditionalBytes = %d, want %d", report.MSOConditionalBytes, want)
+	}
+}
+
+func TestAnalyzeWarnings(t *testing.T) {
+	over := analyzeWithBudget(strings.Repeat("x", 120), 100)
+	if len(over.Warnings) != 1 || !strings.Contains(over.Warnings[0], "exceeding") {
+		t.Errorf("expected clipping warning, got %v", over.Warnings)
+	}
+
+	near := analyzeWithBudget(strings.Repeat("x", 95), 100)
+	if len(near.Warnings) != 1 || !strings.Contains(near.Warnings[0], "within") {
+		t.Errorf("expected approaching-budget warning, got %v", near.Warnings)
+	}
+}
+
+func TestWithSizeReport(t *testing.T) {
+	input := `<mjml>
+  <mj-body>
+    <mj-section>
+      <mj-column><mj-text>First</mj-text></mj-column>
+    </mj-section>
+    <mj-wrapper padding="10px">
+      <mj-section>
+        <mj-column><mj-text>Second</mj-text></mj-column>
+      </mj-section>
+    </mj-wrapper>
+  </mj-body>
+</mjml>`
+
+	var report SizeReport
+	called := false
+	html, err := Render(input, WithSizeReport(1000, func(r SizeReport) {
+		called = true
+		report = r
+	}))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !called {
+		t.Fatal("size report callback was not invoked")
+	}
+	if report.TotalBytes != len(html) {
+		t.Errorf("TotalBytes = %d, want %d", report.TotalBytes, len(html))
+	}
+	if len(report.Components) != 2 {
+		t.Fatalf("expected 2 top-level components, got %+v", report.Components)
+	}
+	if report.Components[0].TagName != "mj-section" || report.Components[1].TagName != "mj-wrapper" {
+		t.Errorf("unexpected component order: %+v", report.Components)
+	}
+	if report.Components[1].Line != 6 {
+		t.Errorf("wrapper line = %d, want 6", report.Components[1].Line)
+	}
+	if len(report.Warnings) == 0 {
+		t.Error("expected warnings for a 1000 byte budget")
+	}
+}
@@ -0,0 +1,90 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSocialVerticalModeLinksAndPadding verifies that vertical mj-social links
+// both the icon and the text, applies the parent's icon-padding and puts
+// css-class on the element row, like horizontal mode.
+func TestSocialVerticalModeLinksAndPadding(t *testing.T) {
+	input := `<mjml><mj-body><mj-section><mj-column>
+  <mj-social mode="vertical" icon-padding="4px">
+    <mj-social-element name="github" href="https://github.com/example" css-class="gh" rel="noopener">GitHub</mj-social-element>
+  </mj-social>
+</mj-column></mj-section></mj-body></mjml>`
+
+	html, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if got := strings.Count(html, `<a href="https://github.com/example" rel="noopener" target="_blank"`); got != 2 {
+		t.Errorf("expected the icon and the text to be linked, found %d links", got)
+	}
+	if !strings.Contains(html, `<tr class="gh">`) {
+		t.Error("expected css-class on the vertical element row")
+	}
+	if !strings.Contains(html, `padding:4px;`) {
+		t.Error("expected icon-padding from mj-social on the icon cell")
+	}
+}
+
+// TestSocialNonWebHrefSkipsShareURL verifies that mailto: and tel: links on
+// share networks are used as they are instead of being wrapped in a share URL.
+func TestSocialNonWebHrefSkipsShareURL(t *testing.T) {
+	input := `<mjml><mj-body><mj-section><mj-column>
+  <mj-social>
+    <mj-social-element name="facebook" href="mailto:team@example.com?subject=Hello there">Mail</mj-social-element>
+    <mj-social-element name="twitter" href="tel:+1 555 123 4567">Call</mj-social-element>
+  </mj-social>
+</mj-column></mj-section></mj-body></mjml>`
+
+	html, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if strings.Contains(html, "sharer") || strings.Contains(html, "intent/tweet") {
+		t.Error("non-web hrefs should not be wrapped in share URLs")
+	}
+	for _, want := range []string{`href="mailto:team@example.com?subject=Hello%20there"`, `href="tel:+15551234567"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s", want)
+		}
+	}
+}
+
+// TestSocialTextOnlyElements verifies that elements without an icon render as
+// linked text instead of disappearing, and that elements with neither an icon
+// nor text still render nothing.
+func TestSocialTextOnlyElements(t *testing.T) {
+	input := `<mjml><mj-body><mj-section><mj-column>
+  <mj-social color="#888888">
+    <mj-social-element href="https://example.com/unsubscribe">Unsubscribe</mj-social-element>
+    <mj-social-element href="https://example.com/preferences">Preferences</mj-social-element>
+    <mj-social-element href="https://example.com/empty"></mj-social-element>
+  </mj-social>
+</mj-column></mj-section></mj-body></mjml>`
+
+	html, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	for _, text := range []string{"Unsubscribe", "Preferences"} {
+		if !strings.Contains(html, `target="_blank" style="color:#888888;`) || !strings.Contains(html, ">"+text+"</a>") {
+			t.Errorf("expected %q to render as a styled link", text)
+		}
+	}
+	if strings.Contains(html, "<img") {
+		t.Error("expected no icon for elements without src")
+	}
+	if strings.Contains(html, "https://example.com/empty") {
+		t.Error("expected an element without icon or text to render nothing")
+	}
+	if got := strings.Count(html, "display:inline-table"); got != 2 {
+		t.Errorf("expected 2 inline tables, got %d", got)
+	}
+}
@@ -0,0 +1,105 @@
+package mjml
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml/components"
+)
+
+// SourceMapping links a byte range of the rendered HTML to the MJML element
+// that produced it.
+type SourceMapping struct {
+	Component string // MJML tag, such as "mj-section" or "mj-text"
+	Line      int    // Source line of the MJML element
+	Start     int    // Offset of the first byte of the element's output
+	End       int    // Offset just past the last byte of the element's output
+	Depth     int    // Nesting depth below mj-body, which has depth 0
+}
+
+// SourceMap records which parts of a rendered document came from which MJML
+// elements. Mappings are in document order, so parents precede their children.
+type SourceMap struct {
+	Mappings []SourceMapping
+}
+
+// Lookup returns the innermost mapping whose range contains offset, or nil
+// when offset lies outside every component, for example in the head.
+func (m *SourceMap) Lookup(offset int) *SourceMapping {
+	var found *SourceMapping
+	for i := range m.Mappings {
+		mapping := &m.Mappings[i]
+		if mapping.Start > offset {
+			break
+		}
+		if offset < mapping.End && (found == nil || mapping.Depth > found.Depth) {
+			found = mapping
+		}
+	}
+	return found
+}
+
+// WithSourceMap records the output range of every component while rendering.
+// The map is returned in RenderResult.SourceMap by RenderWithAST; offsets refer
+// to RenderResult.HTML, after any output-format conversion. Render and Template
+// produce the same HTML but discard the map.
+func WithSourceMap() RenderOption {
+	return func(opts *RenderOpts) {
+		opts.SourceMap = true
+	}
+}
+
+// extractSourceMap removes the component markers written during rendering and
+// returns the clean document with the ranges they delimited. Ranges whose
+// markers were dropped by post-processing, such as AMP conversion removing
+// Outlook-only blocks, are omitted.
+func extractSourceMap(document string) (string, *SourceMap) {
+	const delimiter = components.SourceMapMarkerDelimiter
+
+	var (
+		out   strings.Builder
+		open  []int
+		spans []SourceMapping
+		keep  []bool
+	)
+	out.Grow(len(document))
+
+	for {
+		idx := strings.Index(document, delimiter)
+		if idx < 0 {
+			out.WriteString(document)
+			break
+		}
+		out.WriteString(document[:idx])
+		document = document[idx+len(delimiter):]
+
+		end := strings.Index(document, delimiter)
+		if end < 0 {
+			break
+		}
+		marker := document[:end]
+		document = document[end+len(delimiter):]
+
+		if tag, ok := strings.CutPrefix(marker, "+"); ok {
+			component, lineText, _ := strings.Cut(tag, ":")
+			line, _ := strconv.Atoi(lineText)
+			open = append(open, len(spans))
+			spans = append(spans, SourceMapping{Component: component, Line: line, Start: out.Len(), Depth: len(open) - 1})
+			keep = append(keep, false)
+			continue
+		}
+		if n := len(open); n > 0 {
+			spans[open[n-1]].End = out.Len()
+			keep[open[n-1]] = true
+			open = open[:n-1]
+		}
+	}
+
+	sourceMap := &SourceMap{Mappings: make([]SourceMapping, 0, len(spans))}
+	for i, span := range spans {
+		if keep[i] {
+			sourceMap.Mappings = append(sourceMap.Mappings, span)
+		}
+	}
+	return out.String(), sourceMap
+}
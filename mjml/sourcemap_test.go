@@ -0,0 +1,70 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+const sourceMapDoc = `<mjml>
+<mj-body>
+<mj-section>
+<mj-column>
+<mj-text>First paragraph</mj-text>
+<mj-button href="#">Click</mj-button>
+</mj-column>
+</mj-section>
+</mj-body>
+</mjml>`
+
+func TestWithSourceMap(t *testing.T) {
+	plain, err := Render(sourceMapDoc)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+
+	result, err := RenderWithAST(sourceMapDoc, WithSourceMap())
+	if err != nil {
+		t.Fatalf("render with source map: %v", err)
+	}
+	if normalizeGroupColumnClassOrder(result.HTML) != plain {
+		t.Fatal("expected the source map to leave the HTML unchanged")
+	}
+	if strings.Contains(result.HTML, "\x00") {
+		t.Fatal("source map markers leaked into the output")
+	}
+
+	var got []string
+	for _, m := range result.SourceMap.Mappings {
+		got = append(got, m.Component)
+		if m.Start < 0 || m.End > len(result.HTML) || m.Start >= m.End {
+			t.Errorf("invalid range for %s: [%d, %d)", m.Component, m.Start, m.End)
+		}
+	}
+	if want := "mj-body mj-section mj-column mj-text mj-button"; strings.Join(got, " ") != want {
+		t.Fatalf("mappings = %v, want %s", got, want)
+	}
+
+	textOffset := strings.Index(result.HTML, "First paragraph")
+	mapping := result.SourceMap.Lookup(textOffset)
+	if mapping == nil || mapping.Component != "mj-text" || mapping.Line != 5 || mapping.Depth != 3 {
+		t.Fatalf("Lookup(text) = %+v, want mj-text on line 5 at depth 3", mapping)
+	}
+	if span := result.HTML[mapping.Start:mapping.End]; !strings.HasPrefix(span, "<tr>") || !strings.HasSuffix(span, "</tr>") {
+		t.Errorf("expected the mj-text range to cover its table row, got %q", span)
+	}
+
+	if m := result.SourceMap.Lookup(strings.Index(result.HTML, "<title>")); m != nil {
+		t.Errorf("expected no mapping in the head, got %+v", m)
+	}
+}
+
+func TestWithSourceMapAMP(t *testing.T) {
+	result, err := RenderWithAST(sourceMapDoc, WithSourceMap(), WithOutputFormat(FormatAMP))
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	m := result.SourceMap.Lookup(strings.Index(result.HTML, "Click"))
+	if m == nil || m.Component != "mj-button" {
+		t.Fatalf("expected AMP offsets to map to mj-button, got %+v", m)
+	}
+}
@@ -0,0 +1,141 @@
+package mjml
+
+import (
+	"strings"
+)
+
+// TemplateStats summarizes the structure of an MJML document, so tooling can
+// enforce template policies (for example a maximum number of sections) before
+// rendering.
+type TemplateStats struct {
+	Components           map[string]int `json:"components"`           // Number of elements per MJML tag, such as "mj-section"
+	MaxDepth             int            `json:"maxDepth"`             // Deepest nesting of MJML tags; the mjml root is depth 1
+	Images               int            `json:"images"`               // Image URLs: src, background and icon attributes and <img> in ending tags
+	Links                int            `json:"links"`                // href attributes, including anchors in ending tags
+	EstimatedOutputBytes int            `json:"estimatedOutputBytes"` // Rough size of the rendered HTML
+}
+
+// Document and per-component output sizes used by the size estimate, measured
+// from the default rendering of each component. componentOutputBytes is the
+// cost of every occurrence; componentStyleBytes is the one-off CSS a component
+// adds to the head when first used.
+const (
+	documentOutputBytes         = 1300
+	defaultComponentOutputBytes = 300
+)
+
+var componentOutputBytes = map[string]int{
+	"mj-section":           650,
+	"mj-column":            380,
+	"mj-group":             900,
+	"mj-wrapper":           670,
+	"mj-hero":              1500,
+	"mj-text":              220,
+	"mj-image":             430,
+	"mj-button":            720,
+	"mj-divider":           460,
+	"mj-spacer":            120,
+	"mj-table":             330,
+	"mj-social":            200,
+	"mj-social-element":    1000,
+	"mj-navbar":            300,
+	"mj-navbar-link":       340,
+	"mj-accordion":         400,
+	"mj-accordion-element": 950,
+	"mj-carousel":          2800,
+	"mj-carousel-image":    1900,
+}
+
+var componentStyleBytes = map[string]int{
+	"mj-column":    300,
+	"mj-text":      250,
+	"mj-image":     240,
+	"mj-button":    250,
+	"mj-social":    250,
+	"mj-navbar":    1150,
+	"mj-accordion": 1500,
+}
+
+// tagsWithoutOutput contribute nothing to the rendered body on their own.
+var tagsWithoutOutput = map[string]struct{}{
+	"mjml":                  {},
+	"mj-head":               {},
+	"mj-body":               {},
+	"mj-attributes":         {},
+	"mj-all":                {},
+	"mj-class":              {},
+	"mj-breakpoint":         {},
+	"mj-font":               {},
+	"mj-title":              {},
+	"mj-preview":            {},
+	"mj-style":              {},
+	"mj-raw":                {},
+	"mj-accordion-title":    {},
+	"mj-accordion-text":     {},
+	"mj-html-attributes":    {},
+	"mj-selector":           {},
+	"mj-html-attribute":     {},
+	"mj-include":            {},
+	"mj-cond":               {},
+	"mj-anchor":             {},
+	"mj-carousel-thumbnail": {},
+}
+
+// Stats counts the components, nesting depth, images and links of ast and
+// estimates the size of its rendered output. Like ExtractLinks, it works on
+// the document as written: mj-cond blocks are counted unexpanded and
+// mj-attributes defaults are not applied. The size estimate is a heuristic
+// meant for budgeting; use Analyze on the rendered HTML for exact numbers.
+func Stats(ast *MJMLNode) TemplateStats {
+	stats := TemplateStats{Components: make(map[string]int)}
+	if ast == nil {
+		return stats
+	}
+
+	payload := 0
+	collectStats(ast, 0, &stats, &payload)
+
+	for _, link := range ExtractLinks(ast) {
+		switch {
+		case link.Attribute == "href":
+			stats.Links++
+		case link.Element == "mjml" || strings.HasPrefix(link.Element, "mj-") || link.Element == "img":
+			stats.Images++
+		}
+	}
+
+	size := documentOutputBytes + payload
+	for tag, count := range stats.Components {
+		if _, ok := tagsWithoutOutput[tag]; ok {
+			continue
+		}
+		perComponent, ok := componentOutputBytes[tag]
+		if !ok {
+			perComponent = defaultComponentOutputBytes
+		}
+		size += count*perComponent + componentStyleBytes[tag]
+	}
+	stats.EstimatedOutputBytes = size
+	return stats
+}
+
+// collectStats counts the MJML tags below node and accumulates the text and
+// attribute bytes that are copied into the output.
+func collectStats(node *MJMLNode, depth int, stats *TemplateStats, payload *int) {
+	tagName := node.GetTagName()
+	if tagName == "mjml" || strings.HasPrefix(tagName, "mj-") {
+		depth++
+		stats.Components[tagName]++
+		if depth > stats.MaxDepth {
+			stats.MaxDepth = depth
+		}
+	}
+
+	*payload += len(node.Text)
+	for _, attr := range node.Attrs {
+		*payload += len(attr.Value)
+	}
+	for _, child := range node.Children {
+		collectStats(child, depth, stats, payload)
+	}
+}
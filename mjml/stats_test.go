@@ -0,0 +1,82 @@
+package mjml
+
+import (
+	"testing"
+
+	"github.com/preslavrachev/gomjml/parser"
+)
+
+func TestStats(t *testing.T) {
+	input := `<mjml>
+  <mj-head>
+    <mj-title>Stats</mj-title>
+  </mj-head>
+  <mj-body>
+    <mj-wrapper>
+      <mj-section background-url="https://example.com/bg.png">
+        <mj-column>
+          <mj-image src="https://example.com/logo.png" href="https://example.com" />
+          <mj-text>Read <a href="https://example.com/post">the post</a> <img src="https://example.com/pixel.gif" /></mj-text>
+        </mj-column>
+        <mj-column>
+          <mj-button href="https://example.com/buy">Buy</mj-button>
+        </mj-column>
+      </mj-section>
+    </mj-wrapper>
+    <mj-section background-color="linear-gradient(#fff, #000)">
+      <mj-column>
+        <mj-social>
+          <mj-social-element name="facebook" href="https://facebook.com/example">Share</mj-social-element>
+        </mj-social>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+	ast, err := parser.ParseMJML(input)
+	if err != nil {
+		t.Fatalf("ParseMJML() error = %v", err)
+	}
+	stats := Stats(ast)
+
+	wantComponents := map[string]int{
+		"mjml": 1, "mj-head": 1, "mj-title": 1, "mj-body": 1, "mj-wrapper": 1,
+		"mj-section": 2, "mj-column": 3, "mj-image": 1, "mj-text": 1, "mj-button": 1,
+		"mj-social": 1, "mj-social-element": 1,
+	}
+	for tag, want := range wantComponents {
+		if got := stats.Components[tag]; got != want {
+			t.Errorf("Components[%q] = %d, want %d", tag, got, want)
+		}
+	}
+	if len(stats.Components) != len(wantComponents) {
+		t.Errorf("Components = %v, want %v", stats.Components, wantComponents)
+	}
+	// mjml > mj-body > mj-wrapper > mj-section > mj-column > mj-image
+	if stats.MaxDepth != 6 {
+		t.Errorf("MaxDepth = %d, want 6", stats.MaxDepth)
+	}
+	// background-url, mj-image src and the <img> in mj-text; the gradient is not an image
+	if stats.Images != 3 {
+		t.Errorf("Images = %d, want 3", stats.Images)
+	}
+	if stats.Links != 4 {
+		t.Errorf("Links = %d, want 4", stats.Links)
+	}
+
+	html, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	ratio := float64(stats.EstimatedOutputBytes) / float64(len(html))
+	if ratio < 0.7 || ratio > 1.3 {
+		t.Errorf("EstimatedOutputBytes = %d, rendered %d bytes", stats.EstimatedOutputBytes, len(html))
+	}
+}
+
+func TestStatsNil(t *testing.T) {
+	stats := Stats(nil)
+	if stats.Components == nil || stats.MaxDepth != 0 || stats.EstimatedOutputBytes != 0 {
+		t.Errorf("Stats(nil) = %+v", stats)
+	}
+}
@@ -30,9 +30,11 @@ type Spacing struct {
 }
 
 // String returns the CSS representation of the spacing value.
-// It automatically optimizes the output format:
+// It uses the shortest shorthand that ParseSpacing reads back to the same
+// values:
 // - If all sides are equal: "10px"
 // - If top/bottom and left/right are equal: "10px 20px"
+// - If left/right are equal: "10px 20px 30px"
 // - Otherwise: "10px 20px 30px 40px"
 //
 // Example:
@@ -40,13 +42,15 @@ type Spacing struct {
 //	s := Spacing{Top: 10, Right: 20, Bottom: 10, Left: 20}
 //	fmt.Println(s.String()) // "10px 20px"
 func (s Spacing) String() string {
-	if s.Top == s.Right && s.Right == s.Bottom && s.Bottom == s.Left {
-		return fmt.Sprintf("%.0fpx", s.Top)
+	switch {
+	case s.Top == s.Right && s.Right == s.Bottom && s.Bottom == s.Left:
+		return FormatPixels(s.Top)
+	case s.Top == s.Bottom && s.Right == s.Left:
+		return FormatPixels(s.Top) + " " + FormatPixels(s.Right)
+	case s.Right == s.Left:
+		return FormatPixels(s.Top) + " " + FormatPixels(s.Right) + " " + FormatPixels(s.Bottom)
 	}
-	if s.Top == s.Bottom && s.Right == s.Left {
-		return fmt.Sprintf("%.0fpx %.0fpx", s.Top, s.Right)
-	}
-	return fmt.Sprintf("%.0fpx %.0fpx %.0fpx %.0fpx", s.Top, s.Right, s.Bottom, s.Left)
+	return FormatPixels(s.Top) + " " + FormatPixels(s.Right) + " " + FormatPixels(s.Bottom) + " " + FormatPixels(s.Left)
 }
 
 // ParsePixel parses a string value into a Pixel struct.
@@ -85,10 +89,9 @@ func ParsePixel(value string) (*Pixel, error) {
 // Supported formats:
 //   - "10px" -> all sides = 10
 //   - "10px 20px" -> top/bottom = 10, left/right = 20
+//   - "10px 20px 30px" -> top=10, left/right = 20, bottom=30
 //   - "10px 20px 30px 40px" -> top=10, right=20, bottom=30, left=40
 //
-// The 3-value format ("10px 20px 30px") is not currently supported and will return an error.
-//
 // Returns an error for invalid formats or unparseable pixel values.
 //
 // Example:
@@ -102,49 +105,43 @@ func ParseSpacing(value string) (*Spacing, error) {
 		return nil, nil
 	}
 
-	parts := strings.Fields(value)
-	var spacing Spacing
-
-	switch len(parts) {
-	case 1:
-		val, err := ParsePixel(parts[0])
+	sides, err := ExpandShorthand(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spacing format: %s", value)
+	}
+	var values [4]float64
+	for i, side := range sides {
+		px, err := ParsePixel(side)
 		if err != nil {
 			return nil, err
 		}
-		spacing = Spacing{val.Value, val.Value, val.Value, val.Value}
+		values[i] = px.Value
+	}
+
+	return &Spacing{Top: values[0], Right: values[1], Bottom: values[2], Left: values[3]}, nil
+}
+
+// ExpandShorthand expands a CSS box shorthand of one to four values, such as
+// padding or border-width, into its top, right, bottom and left values. The
+// values are returned as written, so any unit is accepted.
+//
+// Example:
+//
+//	sides, _ := ExpandShorthand("10px 5%")
+//	fmt.Println(sides) // [10px 5% 10px 5%]
+func ExpandShorthand(value string) ([4]string, error) {
+	parts := strings.Fields(value)
+	switch len(parts) {
+	case 1:
+		return [4]string{parts[0], parts[0], parts[0], parts[0]}, nil
 	case 2:
-		topBottom, err := ParsePixel(parts[0])
-		if err != nil {
-			return nil, err
-		}
-		leftRight, err := ParsePixel(parts[1])
-		if err != nil {
-			return nil, err
-		}
-		spacing = Spacing{topBottom.Value, leftRight.Value, topBottom.Value, leftRight.Value}
+		return [4]string{parts[0], parts[1], parts[0], parts[1]}, nil
+	case 3:
+		return [4]string{parts[0], parts[1], parts[2], parts[1]}, nil
 	case 4:
-		top, err := ParsePixel(parts[0])
-		if err != nil {
-			return nil, err
-		}
-		right, err := ParsePixel(parts[1])
-		if err != nil {
-			return nil, err
-		}
-		bottom, err := ParsePixel(parts[2])
-		if err != nil {
-			return nil, err
-		}
-		left, err := ParsePixel(parts[3])
-		if err != nil {
-			return nil, err
-		}
-		spacing = Spacing{top.Value, right.Value, bottom.Value, left.Value}
-	default:
-		return nil, fmt.Errorf("invalid spacing format: %s", value)
+		return [4]string{parts[0], parts[1], parts[2], parts[3]}, nil
 	}
-
-	return &spacing, nil
+	return [4]string{}, fmt.Errorf("shorthand takes 1 to 4 values, got %d: %q", len(parts), value)
 }
 
 // Color represents a CSS color value with validation and normalization.
@@ -209,15 +206,7 @@ func NormalizeColor(value string) string {
 
 // isHexColor checks if a string contains only valid hex color characters
 func isHexColor(s string) bool {
-	if len(s) != 3 && len(s) != 6 {
-		return false
-	}
-	for _, r := range s {
-		if !((r >= '0' && r <= '9') || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')) {
-			return false
-		}
-	}
-	return true
+	return (len(s) == 3 || len(s) == 6) && isHexDigits(s)
 }
 
 // Size represents CSS size values (width, height) that can be in pixels or percentages.
@@ -134,10 +134,9 @@ func TestParseSpacing(t *testing.T) {
 			hasError: false,
 		},
 		{
-			name:     "three values (invalid)",
+			name:     "three values",
 			input:    "10px 20px 30px",
-			expected: nil,
-			hasError: true,
+			expected: &Spacing{Top: 10, Right: 20, Bottom: 30, Left: 20},
 		},
 		{
 			name:     "invalid value",
@@ -211,6 +210,16 @@ func TestSpacingString(t *testing.T) {
 			spacing:  Spacing{Top: 5, Right: 10, Bottom: 5, Left: 10},
 			expected: "5px 10px",
 		},
+		{
+			name:     "three values",
+			spacing:  Spacing{Top: 5, Right: 10, Bottom: 15, Left: 10},
+			expected: "5px 10px 15px",
+		},
+		{
+			name:     "fractional values",
+			spacing:  Spacing{Top: 12.5, Right: 12.5, Bottom: 12.5, Left: 12.5},
+			expected: "12.5px",
+		},
 	}
 
 	for _, tt := range tests {
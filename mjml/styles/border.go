@@ -3,13 +3,25 @@ package styles
 import "strings"
 
 // ParseBorderWidth extracts the pixel width from a CSS border shorthand value.
-// It returns 0 if the width cannot be determined.
+// Like MJML, the width may appear anywhere in the shorthand ("2px solid red"
+// or "solid 2px red"). It returns 0 if the width cannot be determined.
 func ParseBorderWidth(attr string) int {
-	parts := strings.Fields(attr)
-	if len(parts) > 0 {
-		if px, err := ParsePixel(parts[0]); err == nil && px != nil {
+	for _, part := range strings.Fields(attr) {
+		if px, err := ParsePixel(part); err == nil && px != nil {
 			return int(px.Value)
 		}
 	}
 	return 0
 }
+
+// ParseBorderColor extracts the color from a CSS border shorthand value, in
+// any position ("2px solid red" or "red solid 2px"). It returns "" if the
+// shorthand has no color.
+func ParseBorderColor(attr string) string {
+	for _, part := range splitTopLevel(attr, ' ') {
+		if IsColor(part) {
+			return part
+		}
+	}
+	return ""
+}
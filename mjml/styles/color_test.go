@@ -0,0 +1,51 @@
+package styles
+
+import "testing"
+
+func TestColorHex(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"#fff", "#ffffff", true},
+		{"#ABCDEF", "#abcdef", true},
+		{"Red", "#ff0000", true},
+		{"rebeccapurple", "#663399", true},
+		{"rgb(255, 128, 0)", "#ff8000", true},
+		{"rgb(100% 0% 0%)", "#ff0000", true},
+		{"rgba(0,0,0,1)", "#000000", true},
+		{"rgba(0,0,0,0.5)", "", false},
+		{"hsl(0, 100%, 50%)", "", false},
+		{"transparent", "", false},
+		{"#ggg", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Color{Value: tt.input}.Hex()
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Color{%q}.Hex() = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if r, g, b, ok := (Color{Value: "coral"}).RGB(); !ok || r != 0xff || g != 0x7f || b != 0x50 {
+		t.Errorf("Color{coral}.RGB() = %d, %d, %d, %v", r, g, b, ok)
+	}
+}
+
+func TestIsColor(t *testing.T) {
+	for value, want := range map[string]bool{
+		"#fff":             true,
+		"#ff000080":        true,
+		"navy":             true,
+		"currentColor":     true,
+		"hsl(0, 50%, 50%)": true,
+		"#ffff0":           false,
+		"10px":             false,
+		"notacolor":        false,
+		"url(a.png)":       false,
+	} {
+		if got := IsColor(value); got != want {
+			t.Errorf("IsColor(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package styles
+
+import (
+	"strings"
+)
+
+// gradientFunctions are the CSS image functions treated as gradients.
+var gradientFunctions = []string{
+	"linear-gradient(",
+	"radial-gradient(",
+	"conic-gradient(",
+	"repeating-linear-gradient(",
+	"repeating-radial-gradient(",
+	"repeating-conic-gradient(",
+}
+
+// gradientKeywords appear in gradient direction and shape arguments and are
+// never color stops.
+var gradientKeywords = map[string]struct{}{
+	"to": {}, "at": {}, "from": {}, "in": {},
+	"top": {}, "bottom": {}, "left": {}, "right": {}, "center": {},
+	"circle": {}, "ellipse": {},
+	"closest-side": {}, "closest-corner": {}, "farthest-side": {}, "farthest-corner": {},
+}
+
+// IsGradient reports whether value is a CSS gradient such as
+// "linear-gradient(#fff, #000)".
+func IsGradient(value string) bool {
+	v := strings.ToLower(strings.TrimSpace(value))
+	for _, fn := range gradientFunctions {
+		if strings.HasPrefix(v, fn) {
+			return true
+		}
+	}
+	return false
+}
+
+// GradientFallbackColor returns the first color stop of a gradient, for use
+// where only a plain color is accepted (bgcolor attributes, Outlook). It
+// returns "" when no color stop can be found.
+func GradientFallbackColor(gradient string) string {
+	v := strings.TrimSpace(gradient)
+	open := strings.IndexByte(v, '(')
+	end := strings.LastIndexByte(v, ')')
+	if open < 0 || end <= open {
+		return ""
+	}
+
+	for _, arg := range splitTopLevel(v[open+1:end], ',') {
+		fields := splitTopLevel(strings.TrimSpace(arg), ' ')
+		if len(fields) == 0 || !isColorToken(fields[0]) {
+			continue
+		}
+		return fields[0]
+	}
+	return ""
+}
+
+// SplitBackground separates plain colors, gradients and image URLs given in
+// background-color and background-url. A gradient may appear in either
+// attribute; when both hold one, the background-url gradient wins. fallback is
+// the plain color to use where gradients are unsupported: background-color if
+// it is a plain color, otherwise the gradient's first color stop.
+func SplitBackground(color, url string) (fallback, gradient, imageURL string) {
+	if IsGradient(url) {
+		gradient = strings.TrimSpace(url)
+	} else {
+		imageURL = url
+	}
+
+	if IsGradient(color) {
+		if gradient == "" {
+			gradient = strings.TrimSpace(color)
+		}
+		color = ""
+	}
+
+	fallback = color
+	if fallback == "" && gradient != "" {
+		fallback = GradientFallbackColor(gradient)
+	}
+	return fallback, gradient, imageURL
+}
+
+// isColorToken reports whether token starts a color stop rather than a
+// direction, angle, shape or position.
+func isColorToken(token string) bool {
+	t := strings.ToLower(token)
+	if t == "" {
+		return false
+	}
+	if t[0] == '#' {
+		return true
+	}
+	if open := strings.IndexByte(t, '('); open > 0 {
+		_, ok := colorFunctions[t[:open]]
+		return ok
+	}
+	if _, keyword := gradientKeywords[t]; keyword {
+		return false
+	}
+	// Angles, lengths and percentages start with a digit, sign or dot.
+	c := t[0]
+	return c >= 'a' && c <= 'z'
+}
+
+// splitTopLevel splits s at sep, ignoring separators inside parentheses.
+// Empty parts are dropped.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case sep:
+			if depth == 0 {
+				if part := strings.TrimSpace(s[start:i]); part != "" {
+					parts = append(parts, part)
+				}
+				start = i + 1
+			}
+		}
+	}
+	if part := strings.TrimSpace(s[start:]); part != "" {
+		parts = append(parts, part)
+	}
+	return parts
+}
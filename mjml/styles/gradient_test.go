@@ -0,0 +1,63 @@
+package styles
+
+import "testing"
+
+func TestIsGradient(t *testing.T) {
+	tests := map[string]bool{
+		"linear-gradient(#fff, #000)":                      true,
+		"  Radial-Gradient(circle, red, blue)":             true,
+		"repeating-linear-gradient(45deg, #000, #fff 10%)": true,
+		"conic-gradient(red, blue)":                        true,
+		"#ffffff":                                          false,
+		"https://example.com/linear-gradient(.png":         false,
+		"": false,
+	}
+	for input, want := range tests {
+		if got := IsGradient(input); got != want {
+			t.Errorf("IsGradient(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestGradientFallbackColor(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"linear-gradient(#ff0000, #0000ff)", "#ff0000"},
+		{"linear-gradient(to right, #ff0000 0%, #0000ff 100%)", "#ff0000"},
+		{"linear-gradient(135deg, rgba(0, 0, 0, 0.5), #fff)", "rgba(0, 0, 0, 0.5)"},
+		{"radial-gradient(circle at center, red, blue)", "red"},
+		{"linear-gradient(to bottom right, white 10%, black)", "white"},
+		{"linear-gradient()", ""},
+		{"not a gradient", ""},
+	}
+	for _, tt := range tests {
+		if got := GradientFallbackColor(tt.input); got != tt.expected {
+			t.Errorf("GradientFallbackColor(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestSplitBackground(t *testing.T) {
+	tests := []struct {
+		name                         string
+		color, url                   string
+		fallback, gradient, imageURL string
+	}{
+		{"plain color and image", "#fff", "https://example.com/bg.png", "#fff", "", "https://example.com/bg.png"},
+		{"gradient in color", "linear-gradient(#111, #222)", "", "#111", "linear-gradient(#111, #222)", ""},
+		{"gradient in url keeps color", "#333", "linear-gradient(#111, #222)", "#333", "linear-gradient(#111, #222)", ""},
+		{"url gradient wins", "linear-gradient(#aaa, #bbb)", "linear-gradient(#111, #222)", "#111", "linear-gradient(#111, #222)", ""},
+		{"gradient color with image", "linear-gradient(#111, #222)", "https://example.com/bg.png", "#111", "linear-gradient(#111, #222)", "https://example.com/bg.png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fallback, gradient, imageURL := SplitBackground(tt.color, tt.url)
+			if fallback != tt.fallback || gradient != tt.gradient || imageURL != tt.imageURL {
+				t.Errorf("SplitBackground(%q, %q) = (%q, %q, %q), want (%q, %q, %q)",
+					tt.color, tt.url, fallback, gradient, imageURL, tt.fallback, tt.gradient, tt.imageURL)
+			}
+		})
+	}
+}
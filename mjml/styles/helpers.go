@@ -1,6 +1,18 @@
 // Package styles provides utilities for parsing and applying CSS values to HTML elements.
 // It includes type-safe CSS value parsing and common style application patterns
 // used throughout the MJML to HTML conversion process.
+//
+// The parsing API normalizes MJML attribute values:
+//   - Lengths: ParseLength, ParsePixels and Length.Convert between px, % and em;
+//     FormatPixels and Length.String format values so they parse back unchanged.
+//   - Box shorthands: ExpandShorthand expands one to four values into sides, and
+//     ParseSpacing reads them as pixels.
+//   - Borders: ParseBorderWidth and ParseBorderColor read the border shorthand.
+//   - Colors: IsColor checks the syntax, NormalizeColor expands #rgb as MJML
+//     does, and Color.Hex and Color.RGB resolve hex, named and rgb() colors.
+//   - Attribute types: ParseValueType parses the types of the MJML attribute
+//     tables (units, enums, colors, booleans and integers) into a ValueType
+//     whose Validate checks values against them; URLType checks URLs.
 package styles
 
 import (
@@ -17,7 +29,7 @@ import (
 //
 //	tag: the HTMLTag to modify
 //	bgcolor: background color (e.g., "#f0f0f0")
-//	bgImage: background image URL
+//	bgImage: background image URL or CSS gradient
 //	bgRepeat: background repeat value (e.g., "no-repeat")
 //	bgSize: background size value (e.g., "cover")
 //	bgPosition: background position value (e.g., "center center")
@@ -33,7 +45,11 @@ func ApplyBackgroundStyles(tag *html.HTMLTag, bgcolor, bgImage, bgRepeat, bgSize
 	tag.MaybeAddStyleString("background-color", bgcolor)
 
 	if bgImage != "" {
-		tag.AddStyle("background-image", fmt.Sprintf("url('%s')", bgImage))
+		if IsGradient(bgImage) {
+			tag.AddStyle("background-image", bgImage)
+		} else {
+			tag.AddStyle("background-image", fmt.Sprintf("url('%s')", bgImage))
+		}
 		tag.MaybeAddStyleString("background-repeat", bgRepeat)
 		tag.MaybeAddStyleString("background-size", bgSize)
 		tag.MaybeAddStyleString("background-position", bgPosition)
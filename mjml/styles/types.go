@@ -0,0 +1,122 @@
+package styles
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ValueType validates the values of an MJML attribute against the type its
+// component declares for it.
+type ValueType interface {
+	// Validate returns an error describing what the type accepts when value
+	// does not match it.
+	Validate(value string) error
+}
+
+// EnumType accepts one of a fixed list of values, as in "enum(left,center,right)".
+// An empty entry, as in "enum(full-width,false,)", accepts the empty string.
+type EnumType struct {
+	Values []string
+}
+
+// ColorType accepts CSS colors and, for background colors, gradients.
+type ColorType struct{}
+
+// BooleanType accepts "true" and "false".
+type BooleanType struct{}
+
+// IntegerType accepts non-negative whole numbers.
+type IntegerType struct{}
+
+// URLType accepts URLs, absolute or relative. Merge tags such as "{{url}}"
+// parse as relative URLs, so only malformed values are rejected.
+type URLType struct{}
+
+var enumTypePattern = regexp.MustCompile(`^enum\(([^)]*)\)$`)
+
+// ParseValueType parses an MJML attribute type, as written in the component
+// attribute tables: unit types, "enum(...)", "color", "boolean" and
+// "integer". It returns false for types that accept any value, such as
+// "string".
+//
+// Example:
+//
+//	t, _ := ParseValueType("enum(left,center,right)")
+//	err := t.Validate("middle") // only accepts one of (left, center, right)
+func ParseValueType(typ string) (ValueType, bool) {
+	if unitType, ok := ParseUnitType(typ); ok {
+		return unitType, true
+	}
+	if m := enumTypePattern.FindStringSubmatch(typ); m != nil {
+		return EnumType{Values: strings.Split(m[1], ",")}, true
+	}
+	switch typ {
+	case "color":
+		return ColorType{}, true
+	case "boolean":
+		return BooleanType{}, true
+	case "integer":
+		return IntegerType{}, true
+	}
+	return nil, false
+}
+
+// Validate implements ValueType.
+func (t EnumType) Validate(value string) error {
+	for _, v := range t.Values {
+		if value == v {
+			return nil
+		}
+	}
+	values := make([]string, 0, len(t.Values))
+	for _, v := range t.Values {
+		if v == "" {
+			v = `""`
+		}
+		values = append(values, v)
+	}
+	return fmt.Errorf("only accepts one of (%s)", strings.Join(values, ", "))
+}
+
+// Validate implements ValueType. Besides colors it accepts the CSS-wide
+// keywords inherit, initial and unset.
+func (ColorType) Validate(value string) error {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "inherit", "initial", "unset":
+		return nil
+	}
+	if IsColor(value) || IsGradient(value) {
+		return nil
+	}
+	return fmt.Errorf("only accepts colors")
+}
+
+// Validate implements ValueType.
+func (BooleanType) Validate(value string) error {
+	if value == "true" || value == "false" {
+		return nil
+	}
+	return fmt.Errorf("only accepts true or false")
+}
+
+// Validate implements ValueType.
+func (IntegerType) Validate(value string) error {
+	if value != "" && strings.Trim(value, "0123456789") == "" {
+		return nil
+	}
+	return fmt.Errorf("only accepts integers")
+}
+
+// Validate implements ValueType.
+func (URLType) Validate(value string) error {
+	_, err := url.Parse(strings.TrimSpace(value))
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+	if err != nil {
+		return fmt.Errorf("only accepts URLs: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package styles
+
+import "testing"
+
+func TestValueTypeValidate(t *testing.T) {
+	tests := []struct {
+		typ, value string
+		wantErr    string
+	}{
+		{"enum(left,center,right)", "center", ""},
+		{"enum(left,center,right)", "Center", "only accepts one of (left, center, right)"},
+		{"enum(full-width,false,)", "", ""},
+		{"enum(full-width,false,)", "true", `only accepts one of (full-width, false, "")`},
+		{"color", "#ABCDEF", ""},
+		{"color", "rgba(0, 0, 0, 0.5)", ""},
+		{"color", "inherit", ""},
+		{"color", "linear-gradient(90deg, #fff, #000)", ""},
+		{"color", "#abcd1", "only accepts colors"},
+		{"color", "blu", "only accepts colors"},
+		{"boolean", "true", ""},
+		{"boolean", "yes", "only accepts true or false"},
+		{"integer", "42", ""},
+		{"integer", "4.2", "only accepts integers"},
+		{"unit(px)", "10px", ""},
+		{"unit(px)", "10em", "only accepts (px) units and 1 value(s)"},
+	}
+	for _, tt := range tests {
+		valueType, ok := ParseValueType(tt.typ)
+		if !ok {
+			t.Fatalf("ParseValueType(%q) failed", tt.typ)
+		}
+		err := valueType.Validate(tt.value)
+		if got := ""; err != nil {
+			got = err.Error()
+			if got != tt.wantErr {
+				t.Errorf("%s.Validate(%q) = %q, want %q", tt.typ, tt.value, got, tt.wantErr)
+			}
+		} else if tt.wantErr != "" {
+			t.Errorf("%s.Validate(%q) accepted the value, want %q", tt.typ, tt.value, tt.wantErr)
+		}
+	}
+
+	if _, ok := ParseValueType("string"); ok {
+		t.Error("string accepts any value")
+	}
+}
+
+func TestURLTypeValidate(t *testing.T) {
+	for _, value := range []string{"https://example.com/a?b=1&c=2", "/relative/path.png", "{{unsubscribe_url}}", "mailto:me@example.com"} {
+		if err := (URLType{}).Validate(value); err != nil {
+			t.Errorf("Validate(%q) = %v", value, err)
+		}
+	}
+	if err := (URLType{}).Validate("http://exa mple.com"); err == nil {
+		t.Error("expected a URL with a space in the host to be rejected")
+	}
+}
@@ -0,0 +1,237 @@
+package styles
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Unit is a CSS length unit. UnitNone marks a unitless number, such as a
+// line-height factor.
+type Unit string
+
+// Units accepted by MJML attributes
+const (
+	UnitNone    Unit = ""
+	UnitPx      Unit = "px"
+	UnitPercent Unit = "%"
+	UnitEm      Unit = "em"
+)
+
+// Length is a number with a CSS unit, such as "20px" or "50%".
+type Length struct {
+	Value float64
+	Unit  Unit
+}
+
+// Number returns the value without its unit, formatted like CSS writes it.
+//
+// Example:
+//
+//	l := Length{Value: 20, Unit: UnitPx}
+//	fmt.Println(l.Number()) // "20"
+func (l Length) Number() string {
+	return strconv.FormatFloat(l.Value, 'f', -1, 64)
+}
+
+// String returns the CSS representation of the length.
+func (l Length) String() string {
+	return l.Number() + string(l.Unit)
+}
+
+// ParseLength parses a single CSS length into its value and unit. Whatever
+// follows the number is taken as the unit, so "2vw" parses; callers check the
+// unit against what the attribute accepts.
+//
+// Supported formats:
+//   - "20px" -> Length{Value: 20, Unit: UnitPx}
+//   - "50%" -> Length{Value: 50, Unit: UnitPercent}
+//   - "1.5" -> Length{Value: 1.5, Unit: UnitNone}
+//
+// Returns an error for values that do not start with a number.
+func ParseLength(value string) (Length, error) {
+	end := 0
+	for end < len(value) && (value[end] >= '0' && value[end] <= '9' || value[end] == '.' ||
+		end == 0 && (value[end] == '-' || value[end] == '+')) {
+		end++
+	}
+	number, err := strconv.ParseFloat(value[:end], 64)
+	if err != nil {
+		return Length{}, fmt.Errorf("invalid length value: %s", value)
+	}
+	return Length{Value: number, Unit: Unit(strings.ToLower(value[end:]))}, nil
+}
+
+// ToPixels converts the length to pixels. reference is the length in pixels
+// relative units refer to: the container width for percentages and the font
+// size for em. Unitless numbers are taken as pixels; other units return false.
+//
+// Example:
+//
+//	l := Length{Value: 50, Unit: UnitPercent}
+//	px, _ := l.ToPixels(600) // 300
+func (l Length) ToPixels(reference float64) (float64, bool) {
+	switch l.Unit {
+	case UnitPx, UnitNone:
+		return l.Value, true
+	case UnitPercent:
+		return l.Value * reference / 100, true
+	case UnitEm:
+		return l.Value * reference, true
+	}
+	return 0, false
+}
+
+// Convert converts the length to unit, with reference as in ToPixels. It
+// returns false when either unit cannot be converted or a relative unit has
+// no reference.
+//
+// Example:
+//
+//	l := Length{Value: 300, Unit: UnitPx}
+//	pct, _ := l.Convert(UnitPercent, 600) // Length{Value: 50, Unit: UnitPercent}
+func (l Length) Convert(unit Unit, reference float64) (Length, bool) {
+	px, ok := l.ToPixels(reference)
+	if !ok {
+		return Length{}, false
+	}
+	switch unit {
+	case UnitPx, UnitNone:
+		return Length{Value: px, Unit: unit}, true
+	case UnitPercent, UnitEm:
+		if reference == 0 {
+			return Length{}, false
+		}
+		if unit == UnitPercent {
+			return Length{Value: px * 100 / reference, Unit: unit}, true
+		}
+		return Length{Value: px / reference, Unit: unit}, true
+	}
+	return Length{}, false
+}
+
+// FormatPixels formats a pixel value without rounding it, such as "12.5px",
+// so parsing the result gives back the same value.
+func FormatPixels(value float64) string {
+	return Length{Value: value, Unit: UnitPx}.String()
+}
+
+// ParsePixels returns the pixel value of a "px" length. Unitless numbers are
+// taken as pixels, as everywhere else in the renderer; other units and invalid
+// values return false.
+func ParsePixels(value string) (float64, bool) {
+	l, err := ParseLength(value)
+	if err != nil || (l.Unit != UnitPx && l.Unit != UnitNone) {
+		return 0, false
+	}
+	return l.Value, true
+}
+
+// HTMLDimension converts a CSS length to the value of an HTML width or height
+// attribute, which is in pixels without a unit: "20px" becomes "20". Other
+// values, such as percentages and "auto", are returned unchanged.
+func HTMLDimension(value string) string {
+	if l, err := ParseLength(value); err == nil && l.Unit == UnitPx {
+		return value[:len(value)-len(l.Unit)]
+	}
+	return value
+}
+
+// UnitType describes the values an MJML attribute of a unit type accepts, as
+// written in the component attribute tables: "unit(px,%){1,4}" takes one to
+// four lengths in px or %, "unit(px,%,)" also takes unitless numbers,
+// "unit(px,auto)" also takes "auto", and "unitWithNegative(px,em)" takes
+// negative lengths. Zero is accepted without a unit, and so are other numbers
+// where px is, since the renderer reads them as pixels.
+type UnitType struct {
+	Units     []Unit
+	Auto      bool
+	Negative  bool
+	MinValues int
+	MaxValues int
+}
+
+var unitTypePattern = regexp.MustCompile(`^(unit|unitWithNegative)\(([^)]*)\)(?:\{(\d+)(?:,(\d+))?\})?$`)
+
+// ParseUnitType parses an MJML attribute type. It returns false when the type
+// is not a unit type.
+func ParseUnitType(typ string) (UnitType, bool) {
+	m := unitTypePattern.FindStringSubmatch(typ)
+	if m == nil {
+		return UnitType{}, false
+	}
+
+	t := UnitType{Negative: m[1] == "unitWithNegative", MinValues: 1, MaxValues: 1}
+	for _, unit := range strings.Split(m[2], ",") {
+		unit = strings.TrimSpace(unit)
+		if unit == "auto" {
+			t.Auto = true
+			continue
+		}
+		t.Units = append(t.Units, Unit(unit))
+	}
+	if m[3] != "" {
+		t.MinValues, _ = strconv.Atoi(m[3])
+		t.MaxValues = t.MinValues
+		if m[4] != "" {
+			t.MaxValues, _ = strconv.Atoi(m[4])
+		}
+	}
+	return t, true
+}
+
+// Validate checks value against the type and returns an error describing what
+// the type accepts when it does not match.
+//
+// Example:
+//
+//	t, _ := ParseUnitType("unit(px,%){1,4}")
+//	err := t.Validate("10em") // only accepts (px, %) units and 1 to 4 value(s)
+func (t UnitType) Validate(value string) error {
+	parts := strings.Fields(value)
+	if len(parts) < t.MinValues || len(parts) > t.MaxValues {
+		return t.invalid()
+	}
+	for _, part := range parts {
+		if !t.accepts(part) {
+			return t.invalid()
+		}
+	}
+	return nil
+}
+
+func (t UnitType) accepts(part string) bool {
+	if part == "0" || t.Auto && part == "auto" {
+		return true
+	}
+	l, err := ParseLength(part)
+	if err != nil || strings.HasPrefix(part, "+") || l.Value < 0 && !t.Negative {
+		return false
+	}
+	for _, unit := range t.Units {
+		if l.Unit == unit || l.Unit == UnitNone && unit == UnitPx {
+			return true
+		}
+	}
+	return false
+}
+
+func (t UnitType) invalid() error {
+	units := make([]string, 0, len(t.Units)+1)
+	for _, unit := range t.Units {
+		if unit == UnitNone {
+			units = append(units, "unitless")
+			continue
+		}
+		units = append(units, string(unit))
+	}
+	if t.Auto {
+		units = append(units, "auto")
+	}
+	count := strconv.Itoa(t.MinValues)
+	if t.MaxValues != t.MinValues {
+		count += " to " + strconv.Itoa(t.MaxValues)
+	}
+	return fmt.Errorf("only accepts (%s) units and %s value(s)", strings.Join(units, ", "), count)
+}
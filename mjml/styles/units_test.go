@@ -0,0 +1,122 @@
+package styles
+
+import "testing"
+
+func TestParseLength(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Length
+		wantErr bool
+	}{
+		{"20px", Length{20, UnitPx}, false},
+		{"50%", Length{50, UnitPercent}, false},
+		{"1.5", Length{1.5, UnitNone}, false},
+		{"-2EM", Length{-2, UnitEm}, false},
+		{"auto", Length{}, true},
+		{"", Length{}, true},
+		{"px", Length{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLength(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLength(%q) = %v, %v; want %v, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestHTMLDimension(t *testing.T) {
+	tests := map[string]string{
+		"20px":   "20",
+		"12.5px": "12.5",
+		"50%":    "50%",
+		"auto":   "auto",
+		"20":     "20",
+	}
+	for input, want := range tests {
+		if got := HTMLDimension(input); got != want {
+			t.Errorf("HTMLDimension(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestUnitTypeValidate(t *testing.T) {
+	tests := []struct {
+		typ, value string
+		wantErr    string
+	}{
+		{"unit(px,%){1,4}", "10px 5% 0 20", ""},
+		{"unit(px,%){1,4}", "10em", "only accepts (px, %) units and 1 to 4 value(s)"},
+		{"unit(px,%){1,4}", "1px 2px 3px 4px 5px", "only accepts (px, %) units and 1 to 4 value(s)"},
+		{"unit(px,%)", "-5px", "only accepts (px, %) units and 1 value(s)"},
+		{"unit(px)", "50%", "only accepts (px) units and 1 value(s)"},
+		{"unit(px,auto)", "auto", ""},
+		{"unit(px,%,)", "1.5", ""},
+		{"unit(%)", "1.5", "only accepts (%) units and 1 value(s)"},
+		{"unitWithNegative(px,em)", "-0.5em", ""},
+	}
+	for _, tt := range tests {
+		unitType, ok := ParseUnitType(tt.typ)
+		if !ok {
+			t.Fatalf("ParseUnitType(%q) failed", tt.typ)
+		}
+		err := unitType.Validate(tt.value)
+		if got := ""; err != nil {
+			got = err.Error()
+			if got != tt.wantErr {
+				t.Errorf("%s.Validate(%q) = %q, want %q", tt.typ, tt.value, got, tt.wantErr)
+			}
+		} else if tt.wantErr != "" {
+			t.Errorf("%s.Validate(%q) accepted the value, want %q", tt.typ, tt.value, tt.wantErr)
+		}
+	}
+
+	if _, ok := ParseUnitType("color"); ok {
+		t.Error("color is not a unit type")
+	}
+}
+
+func TestLengthConversion(t *testing.T) {
+	tests := []struct {
+		length    Length
+		unit      Unit
+		reference float64
+		want      Length
+		ok        bool
+	}{
+		{Length{50, UnitPercent}, UnitPx, 600, Length{300, UnitPx}, true},
+		{Length{300, UnitPx}, UnitPercent, 600, Length{50, UnitPercent}, true},
+		{Length{1.5, UnitEm}, UnitPx, 16, Length{24, UnitPx}, true},
+		{Length{24, UnitPx}, UnitEm, 16, Length{1.5, UnitEm}, true},
+		{Length{20, UnitPx}, UnitPercent, 0, Length{}, false},
+		{Length{2, "vw"}, UnitPx, 600, Length{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := tt.length.Convert(tt.unit, tt.reference)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%v.Convert(%q, %v) = %v, %v; want %v, %v", tt.length, tt.unit, tt.reference, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if got := FormatPixels(12.5); got != "12.5px" {
+		t.Errorf("FormatPixels(12.5) = %q", got)
+	}
+}
+
+func TestExpandShorthand(t *testing.T) {
+	tests := map[string][4]string{
+		"10px":               {"10px", "10px", "10px", "10px"},
+		"10px 5%":            {"10px", "5%", "10px", "5%"},
+		"1px 2px 3px":        {"1px", "2px", "3px", "2px"},
+		" 1px 2px 3px  4px ": {"1px", "2px", "3px", "4px"},
+	}
+	for input, want := range tests {
+		if got, err := ExpandShorthand(input); err != nil || got != want {
+			t.Errorf("ExpandShorthand(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "1px 2px 3px 4px 5px"} {
+		if _, err := ExpandShorthand(input); err == nil {
+			t.Errorf("ExpandShorthand(%q) succeeded", input)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package mjml
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/preslavrachev/gomjml/mjml/globals"
+	"github.com/preslavrachev/gomjml/mjml/options"
+)
+
+// Template is a precompiled MJML document. Parsing, extension expansion,
+// validation, global attribute processing and component tree construction
+// happen once in Compile. Each Render applies the options afresh to the
+// component tree, so state kept by options such as WithSizeReport starts over
+// on every render, and the output goes into a buffer sized after the earlier
+// renders. A Template is safe for concurrent use and its renders run in
+// parallel; a render that finds every tree busy builds another one.
+type Template struct {
+	ast               *MJMLNode
+	opts              []RenderOption
+	globalAttributes  *globals.GlobalAttributes
+	includeAttributes options.IncludeAttributes
+	noBody            bool         // The document has no mj-body
+	sizeHint          atomic.Int64 // Buffer size for the next render
+	outputSize        atomic.Int64 // Rolling average of the rendered output size in bytes
+	mu                sync.Mutex
+	trees             []*templateTree // Component trees not being rendered
+}
+
+// templateTree is a component tree built from a Template's AST, kept between
+// renders.
+type templateTree struct {
+	root  Component
+	opts  *RenderOpts // Options the components of the tree render with
+	built RenderOpts  // Options as construction left them
+}
+
+// Compile parses mjmlContent and prepares it for repeated rendering. Like
+// Render, attribute validation problems are returned as an Error alongside a
+// usable Template; any other error leaves the Template nil.
+func Compile(mjmlContent string, opts ...RenderOption) (*Template, error) {
+	renderOpts := &RenderOpts{
+		FontTracker: options.NewFontTracker(),
+	}
+	for _, opt := range opts {
+		opt(renderOpts)
+	}
+
+	validation := collectValidation(renderOpts)
+
+	ast, err := parseASTWithOptions(mjmlContent, renderOpts.UseCache, parseOptions(renderOpts), renderOpts.Logger)
+	if err != nil {
+		return nil, err
+	}
+	if ast, err = expandExtensions(ast, renderOpts); err != nil {
+		return nil, err
+	}
+
+	ast = applyGlobalAttributes(ast, renderOpts)
+
+	tree, err := newTemplateTree(ast, renderOpts)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.strictErr(); err != nil {
+		return nil, err
+	}
+
+	if renderOpts.OutputFormat == FormatAMP {
+		if ampErr := validateAMPComponents(tree.root); ampErr != nil {
+			return nil, *ampErr
+		}
+	}
+
+	tmpl := &Template{
+		ast:               ast,
+		opts:              opts,
+		globalAttributes:  renderOpts.GlobalAttributes,
+		includeAttributes: renderOpts.IncludeAttributes,
+		trees:             []*templateTree{tree},
+	}
+	if root, ok := tree.root.(*MJMLComponent); ok && root.Body == nil {
+		tmpl.noBody = true
+	}
+	tmpl.sizeHint.Store(int64(calculateOptimalBufferSize(mjmlContent)))
+
+	return tmpl, validation.result()
+}
+
+// Render writes the HTML for the compiled document to w.
+func (t *Template) Render(w io.Writer) error {
+	output, err := t.RenderString()
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, output)
+	return err
+}
+
+// RenderString returns the HTML for the compiled document.
+func (t *Template) RenderString() (string, error) {
+	if t.noBody {
+		// Mirrors RenderWithAST for documents without an mj-body.
+		return "MJML badly formatted", nil
+	}
+
+	tree, err := t.acquireTree()
+	if err != nil {
+		return "", err
+	}
+	defer t.releaseTree(tree)
+	component, renderOpts := tree.root, tree.opts
+
+	var html strings.Builder
+	html.Grow(int(t.sizeHint.Load()))
+	if err := component.Render(&html); err != nil {
+		return "", err
+	}
+	outputSize := rollingOutputSize(int(t.outputSize.Load()), html.Len())
+	t.outputSize.Store(int64(outputSize))
+	t.sizeHint.Store(int64(outputSizeHint(outputSize)))
+
+	output, issues := checkAccessibility(component, html.String(), renderOpts)
+	if heroErr := checkHeroOverflow(component, renderOpts); heroErr != nil {
+		if issues == nil {
+			issues = heroErr
+		} else {
+			issues.Append(heroErr)
+		}
+	}
+	output, _, err = finishRender(output, renderOpts)
+	if err != nil {
+		return "", err
+	}
+	output = normalizeGroupColumnClassOrder(output)
+	if issues != nil {
+		return output, *issues
+	}
+	return output, nil
+}
+
+// newRenderOpts applies the Template's options to fresh RenderOpts for one
+// render. Validation already happened in Compile, so its reporters are not
+// called again.
+func (t *Template) newRenderOpts() *RenderOpts {
+	renderOpts := &RenderOpts{
+		FontTracker: options.NewFontTracker(),
+	}
+	for _, opt := range t.opts {
+		opt(renderOpts)
+	}
+	renderOpts.InvalidAttributeReporter = nil
+	renderOpts.InvalidTagReporter = nil
+	renderOpts.UnsafeHrefReporter = nil
+	renderOpts.InvalidValueReporter = nil
+	renderOpts.GlobalAttributes = t.globalAttributes
+	renderOpts.IncludeAttributes = t.includeAttributes
+	return renderOpts
+}
+
+// acquireTree returns a component tree for one render, set up with fresh
+// options. It reuses a tree from an earlier render when one is free.
+func (t *Template) acquireTree() (*templateTree, error) {
+	renderOpts := t.newRenderOpts()
+
+	t.mu.Lock()
+	n := len(t.trees)
+	if n == 0 {
+		t.mu.Unlock()
+		return newTemplateTree(t.ast, renderOpts)
+	}
+	tree := t.trees[n-1]
+	t.trees = t.trees[:n-1]
+	t.mu.Unlock()
+
+	tree.reset(renderOpts)
+	return tree, nil
+}
+
+// releaseTree makes tree available to later renders.
+func (t *Template) releaseTree(tree *templateTree) {
+	t.mu.Lock()
+	t.trees = append(t.trees, tree)
+	t.mu.Unlock()
+}
+
+// newTemplateTree builds the component tree for ast with renderOpts.
+func newTemplateTree(ast *MJMLNode, renderOpts *RenderOpts) (*templateTree, error) {
+	component, err := CreateComponent(ast, renderOpts)
+	if err != nil {
+		return nil, err
+	}
+	return &templateTree{root: component, opts: renderOpts, built: *renderOpts}, nil
+}
+
+// reset replaces the options of the tree with renderOpts and clears the
+// state the previous render left on the root. The values construction
+// derives from the document, such as the language and the inline mj-style
+// rules, are kept.
+func (tree *templateTree) reset(renderOpts *RenderOpts) {
+	renderOpts.Lang = tree.built.Lang
+	renderOpts.Dir = tree.built.Dir
+	renderOpts.Features = tree.built.Features
+	renderOpts.TemplateHash = tree.built.TemplateHash
+	renderOpts.InlineRules = tree.built.InlineRules
+	renderOpts.SkipInlineStylesInHead = tree.built.SkipInlineStylesInHead
+	*tree.opts = *renderOpts
+
+	root, ok := tree.root.(*MJMLComponent)
+	if !ok {
+		return
+	}
+	for _, wrapperOpts := range root.wrapperOpts {
+		*wrapperOpts = *renderOpts
+		wrapperOpts.InsideWrapper = true
+	}
+	root.mobileCSSAdded = false
+	root.carouselCSS.Reset()
+}
@@ -0,0 +1,160 @@
+package mjml
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCompileRenderMatchesRender(t *testing.T) {
+	input := `<mjml>
+  <mj-head>
+    <mj-attributes><mj-text color="#ff0000" /></mj-attributes>
+    <mj-title>Compiled</mj-title>
+  </mj-head>
+  <mj-body>
+    <mj-section>
+      <mj-column><mj-text font-family="Roboto, sans-serif">Hello</mj-text></mj-column>
+      <mj-column><mj-image src="https://example.com/a.png" /></mj-column>
+    </mj-section>
+    <mj-wrapper>
+      <mj-section><mj-column><mj-button href="#">Go</mj-button></mj-column></mj-section>
+    </mj-wrapper>
+  </mj-body>
+</mjml>`
+
+	want, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	tmpl, err := Compile(input)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		var out strings.Builder
+		if err := tmpl.Render(&out); err != nil {
+			t.Fatalf("Template.Render() #%d error = %v", i, err)
+		}
+		if out.String() != want {
+			t.Fatalf("Template.Render() #%d output differs from Render()", i)
+		}
+		if hint := tmpl.sizeHint.Load(); hint < int64(len(want)) {
+			t.Errorf("Template.Render() #%d: size hint %d is below the output size %d", i, hint, len(want))
+		}
+	}
+}
+
+func TestCompileConcurrentRender(t *testing.T) {
+	input := `<mjml><mj-body><mj-section><mj-column><mj-text>Hi</mj-text></mj-column></mj-section></mj-body></mjml>`
+	tmpl, err := Compile(input)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	want, err := tmpl.RenderString()
+	if err != nil {
+		t.Fatalf("RenderString() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := tmpl.RenderString()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if got != want {
+				t.Error("concurrent render produced different output")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCompileRenderResetsOptionState(t *testing.T) {
+	input := `<mjml><mj-body>
+    <mj-section><mj-column><mj-text>One</mj-text></mj-column></mj-section>
+    <mj-section><mj-column><mj-text>Two</mj-text></mj-column></mj-section>
+  </mj-body></mjml>`
+
+	var reports []SizeReport
+	tmpl, err := Compile(input, WithSizeReport(0, func(report SizeReport) {
+		reports = append(reports, report)
+	}))
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := tmpl.RenderString(); err != nil {
+			t.Fatalf("RenderString() #%d error = %v", i, err)
+		}
+	}
+
+	if len(reports) != 3 {
+		t.Fatalf("got %d size reports, want 3", len(reports))
+	}
+	for i, report := range reports {
+		if len(report.Components) != 2 {
+			t.Errorf("render #%d: got %d component sizes, want 2", i, len(report.Components))
+		}
+	}
+}
+
+func TestCompileBuildsComponentTreeOnce(t *testing.T) {
+	input := `<mjml><mj-body>
+    <mj-section><mj-column><mj-text>One</mj-text></mj-column></mj-section>
+    <mj-wrapper><mj-section><mj-column><mj-text>Two</mj-text></mj-column></mj-section></mj-wrapper>
+  </mj-body></mjml>`
+
+	var debugLog bytes.Buffer
+	handler := slog.NewTextHandler(&debugLog, &slog.HandlerOptions{Level: slog.LevelDebug})
+	tmpl, err := Compile(input, WithLogger(handler))
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	constructions := strings.Count(debugLog.String(), `msg="Creating component"`)
+	if constructions == 0 {
+		t.Fatal("Compile() did not build a component tree")
+	}
+
+	want, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		got, err := tmpl.RenderString()
+		if err != nil {
+			t.Fatalf("RenderString() #%d error = %v", i, err)
+		}
+		if got != want {
+			t.Errorf("RenderString() #%d output differs from Render()", i)
+		}
+	}
+	if got := strings.Count(debugLog.String(), `msg="Creating component"`); got != constructions {
+		t.Errorf("renders created %d components, want none", got-constructions)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	if _, err := Compile(`<mjml><mj-body>`); err == nil {
+		t.Fatal("expected parse error for malformed MJML")
+	}
+
+	tmpl, err := Compile(`<mjml><mj-body><mj-section><mj-column><mj-text foo="bar">Hi</mj-text></mj-column></mj-section></mj-body></mjml>`)
+	if err == nil {
+		t.Fatal("expected validation error for unknown attribute")
+	}
+	if tmpl == nil {
+		t.Fatal("validation errors should still return a usable template")
+	}
+	if _, err := tmpl.RenderString(); err != nil {
+		t.Errorf("RenderString() error = %v", err)
+	}
+}
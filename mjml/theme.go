@@ -0,0 +1,210 @@
+package mjml
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml/styles"
+)
+
+var (
+	themeStyleElement = regexp.MustCompile(`(?is)(<style\b[^>]*>)(.*?)(</style>)`)
+	themeTag          = regexp.MustCompile(`<[a-zA-Z][^>]*>`)
+	themeColorAttr    = regexp.MustCompile(`(?i)(\s(?:style|bgcolor|color|color2|fillcolor|strokecolor)=)("[^"]*"|'[^']*')`)
+)
+
+// WithThemeOverrides replaces colors in the rendered email, for brand theming
+// or a dark variant of a template:
+//
+//	mjml.Render(source, mjml.WithThemeOverrides(map[string]string{
+//		"#ffffff": "#1e1e1e",
+//		"#000000": "#f0f0f0",
+//	}))
+//
+// Colors are matched by value, so "#fff", "white" and "rgb(255,255,255)" in
+// the output are all replaced by an override of "#ffffff". The overrides
+// apply to inline styles, color attributes such as bgcolor and the VML fill
+// colors of Outlook, and the head CSS, including mj-style, so colors from
+// attributes, mj-attributes, mj-class and component defaults change alike.
+// Text content is left unchanged and replacement values are written as is.
+func WithThemeOverrides(overrides map[string]string) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.ThemeOverrides = overrides
+	}
+}
+
+// ExtractColors renders an MJML template and returns the colors its output
+// uses, in the order they first appear, as the keys WithThemeOverrides
+// matches: lowercase six-digit hex for hex, named and opaque rgb() colors,
+// and the lowercase value for other colors. Component defaults are included,
+// and transparent and currentcolor are left out. Like Render, it returns
+// validation errors alongside the colors.
+func ExtractColors(mjmlContent string, opts ...RenderOption) ([]string, error) {
+	output, err := Render(mjmlContent, opts...)
+	if output == "" {
+		return nil, err
+	}
+	var colors []string
+	seen := make(map[string]struct{})
+	rewriteDocumentColors(output, func(color string) string {
+		key := colorKey(color)
+		if _, ok := seen[key]; !ok && key != "transparent" && key != "currentcolor" {
+			seen[key] = struct{}{}
+			colors = append(colors, key)
+		}
+		return color
+	})
+	return colors, err
+}
+
+// applyTheme replaces the colors of document that overrides maps.
+func applyTheme(document string, overrides map[string]string) string {
+	if len(overrides) == 0 {
+		return document
+	}
+	replacements := make(map[string]string, len(overrides))
+	for color, replacement := range overrides {
+		replacements[colorKey(color)] = replacement
+	}
+	return rewriteDocumentColors(document, func(color string) string {
+		if replacement, ok := replacements[colorKey(color)]; ok {
+			return replacement
+		}
+		return color
+	})
+}
+
+// colorKey returns the form colors are compared in: lowercase six-digit hex
+// when the color resolves to one, or the lowercase value otherwise.
+func colorKey(color string) string {
+	if hex, ok := (styles.Color{Value: color}).Hex(); ok {
+		return hex
+	}
+	return strings.ToLower(strings.TrimSpace(color))
+}
+
+// rewriteDocumentColors passes every color of an HTML document's style
+// elements, style attributes and color attributes to replace and writes back
+// what it returns.
+func rewriteDocumentColors(document string, replace func(color string) string) string {
+	document = themeStyleElement.ReplaceAllStringFunc(document, func(element string) string {
+		m := themeStyleElement.FindStringSubmatch(element)
+		return m[1] + rewriteCSSColors(m[2], false, replace) + m[3]
+	})
+	return themeTag.ReplaceAllStringFunc(document, func(tag string) string {
+		return themeColorAttr.ReplaceAllStringFunc(tag, func(attr string) string {
+			m := themeColorAttr.FindStringSubmatch(attr)
+			quote, value := m[2][:1], m[2][1:len(m[2])-1]
+			if strings.EqualFold(strings.TrimSpace(m[1]), "style=") {
+				value = rewriteCSSColors(value, true, replace)
+			} else if styles.IsColor(value) {
+				value = replace(value)
+			}
+			return m[1] + quote + value + quote
+		})
+	})
+}
+
+// rewriteCSSColors passes the colors in the declaration values of css to
+// replace and writes back what it returns. inline is set for the content of a
+// style attribute, which holds declarations only; in a stylesheet only
+// declarations inside blocks are read, so selectors such as #add are not
+// taken for colors. Strings, comments, url() arguments and font names are
+// skipped.
+func rewriteCSSColors(css string, inline bool, replace func(color string) string) string {
+	var sb strings.Builder
+	written := 0
+	depth := 0
+	inValue := false
+	property := ""
+	start := 0 // Start of the current declaration
+
+	emit := func(from, to int) {
+		color := css[from:to]
+		if replacement := replace(color); replacement != color {
+			sb.WriteString(css[written:from])
+			sb.WriteString(replacement)
+			written = to
+		}
+	}
+
+	for i := 0; i < len(css); {
+		c := css[i]
+		switch {
+		case c == '/' && strings.HasPrefix(css[i:], "/*"):
+			end := strings.Index(css[i+2:], "*/")
+			if end < 0 {
+				i = len(css)
+			} else {
+				i += end + 4
+			}
+			continue
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(css[i+1:], c)
+			if end < 0 {
+				i = len(css)
+			} else {
+				i += end + 2
+			}
+			continue
+		case c == '{':
+			depth++
+			inValue, start = false, i+1
+		case c == '}':
+			depth--
+			inValue, start = false, i+1
+		case c == ';':
+			inValue, start = false, i+1
+		case c == ':' && !inValue && (inline || depth > 0):
+			inValue = true
+			property = strings.ToLower(strings.TrimSpace(css[start:i]))
+		case inValue && c == '#' && (i == 0 || !isCSSNameByte(css[i-1])):
+			end := i + 1
+			for end < len(css) && isCSSNameByte(css[end]) {
+				end++
+			}
+			if styles.IsColor(css[i:end]) {
+				emit(i, end)
+			}
+			i = end
+			continue
+		case inValue && isCSSNameByte(c) && (i == 0 || !isCSSNameByte(css[i-1]) && css[i-1] != '#'):
+			end := i + 1
+			for end < len(css) && isCSSNameByte(css[end]) {
+				end++
+			}
+			name := strings.ToLower(css[i:end])
+			if end < len(css) && css[end] == '(' {
+				closing := strings.IndexByte(css[end:], ')')
+				if closing < 0 {
+					i = len(css)
+					continue
+				}
+				switch name {
+				case "url":
+					i = end + closing + 1
+					continue
+				case "rgb", "rgba", "hsl", "hsla", "hwb", "lab", "lch", "oklab", "oklch":
+					emit(i, end+closing+1)
+					i = end + closing + 1
+					continue
+				}
+			} else if property != "font" && property != "font-family" && styles.IsColor(name) {
+				emit(i, end)
+			}
+			i = end
+			continue
+		}
+		i++
+	}
+	if written == 0 {
+		return css
+	}
+	sb.WriteString(css[written:])
+	return sb.String()
+}
+
+// isCSSNameByte reports whether c can be part of a CSS identifier or hex color.
+func isCSSNameByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
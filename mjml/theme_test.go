@@ -0,0 +1,84 @@
+package mjml
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRewriteCSSColors(t *testing.T) {
+	mark := func(color string) string { return "[" + color + "]" }
+	tests := []struct {
+		css    string
+		inline bool
+		want   string
+	}{
+		{"color:#FFF;background:red", true, "color:[#FFF];background:[red]"},
+		{"border:1px solid rgb(0, 0, 0) !important", true, "border:1px solid [rgb(0, 0, 0)] !important"},
+		{"background:url(https://x.test/#abc.png) #abc", true, "background:url(https://x.test/#abc.png) [#abc]"},
+		{"font-family:Tan, 'Red Hat';color:tan", true, "font-family:Tan, 'Red Hat';color:[tan]"},
+		{"background:linear-gradient(#fff, blue)", true, "background:linear-gradient([#fff], [blue])"},
+		{"#add .red { color: #add; } @media (max-width:480px) { a:hover { color:#000 } }", false,
+			"#add .red { color: [#add]; } @media (max-width:480px) { a:hover { color:[#000] } }"},
+		{"/* color:#fff */ p { color: #123456 }", false, "/* color:#fff */ p { color: [#123456] }"},
+	}
+	for _, tt := range tests {
+		if got := rewriteCSSColors(tt.css, tt.inline, mark); got != tt.want {
+			t.Errorf("rewriteCSSColors(%q) = %q, want %q", tt.css, got, tt.want)
+		}
+	}
+}
+
+func TestThemeOverrides(t *testing.T) {
+	input := `<mjml><mj-head>
+    <mj-attributes><mj-text color="#333" /></mj-attributes>
+    <mj-style>.brand { color: #FF6600; }</mj-style>
+  </mj-head><mj-body background-color="white"><mj-section background-color="#ff6600"><mj-column>
+    <mj-text css-class="brand">Call #ff6600 now</mj-text>
+    <mj-button background-color="rgb(255, 102, 0)" href="#">Go</mj-button>
+  </mj-column></mj-section></mj-body></mjml>`
+
+	colors, err := ExtractColors(input)
+	if err != nil {
+		t.Fatalf("ExtractColors failed: %v", err)
+	}
+	for _, want := range []string{"#ff6600", "#333333", "#ffffff"} {
+		if !slices.Contains(colors, want) {
+			t.Errorf("expected %s among the extracted colors %v", want, colors)
+		}
+	}
+	if len(slices.Compact(slices.Sorted(slices.Values(colors)))) != len(colors) {
+		t.Errorf("expected each color once, got %v", colors)
+	}
+
+	themed, err := Render(input, WithThemeOverrides(map[string]string{
+		"#FF6600": "#0055aa",
+		"#ffffff": "#111111",
+		"#333333": "#eeeeee",
+	}))
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, stale := range []string{"#ff6600", "#FF6600", "rgb(255, 102, 0)", "#333", "background-color:white"} {
+		if strings.Contains(strings.ReplaceAll(themed, "Call #ff6600 now", ""), stale) {
+			t.Errorf("expected %s to be replaced", stale)
+		}
+	}
+	for _, want := range []string{".brand { color: #0055aa; }", `bgcolor="#0055aa"`, "color:#eeeeee;", "background-color:#111111;", "Call #ff6600 now"} {
+		if !strings.Contains(themed, want) {
+			t.Errorf("themed output missing %q", want)
+		}
+	}
+
+	ast, err := ParseMJML(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromAST, err := RenderFromAST(ast, WithThemeOverrides(map[string]string{"#ff6600": "#0055aa"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(fromAST, ".brand { color: #0055aa; }") {
+		t.Error("expected RenderFromAST to apply the theme")
+	}
+}
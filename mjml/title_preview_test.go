@@ -0,0 +1,78 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithTitleAndPreviewText(t *testing.T) {
+	withHead := `<mjml>
+  <mj-head>
+    <mj-title>Original title</mj-title>
+    <mj-preview>Original preview</mj-preview>
+  </mj-head>
+  <mj-body>
+    <mj-section><mj-column><mj-text>Hello</mj-text></mj-column></mj-section>
+  </mj-body>
+</mjml>`
+	withoutHead := `<mjml>
+  <mj-body>
+    <mj-section><mj-column><mj-text>Hello</mj-text></mj-column></mj-section>
+  </mj-body>
+</mjml>`
+
+	for name, input := range map[string]string{"override": withHead, "inject": withoutHead} {
+		t.Run(name, func(t *testing.T) {
+			html, err := Render(input, WithTitle("Soldes d'été & plus"), WithPreviewText("Jusqu'à -50% <aujourd'hui>"))
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			if !strings.Contains(html, `<title>Soldes d&#39;été &amp; plus</title>`) {
+				t.Error("expected escaped title override")
+			}
+			if !strings.Contains(html, `aria-label="Soldes d&#39;été &amp; plus"`) {
+				t.Error("expected title override in the body aria-label")
+			}
+			if !strings.Contains(html, `overflow:hidden;">Jusqu&#39;à -50% &lt;aujourd&#39;hui&gt;</div>`) {
+				t.Error("expected escaped preview override")
+			}
+			if strings.Contains(html, "Original") {
+				t.Error("expected document title and preview to be replaced")
+			}
+		})
+	}
+}
+
+func TestWithPreheaderPadding(t *testing.T) {
+	input := `<mjml>
+  <mj-head><mj-preview>Sale 🎉 today</mj-preview></mj-head>
+  <mj-body><mj-section><mj-column><mj-text>Hello</mj-text></mj-column></mj-section></mj-body>
+</mjml>`
+
+	html, err := Render(input, WithPreheaderPadding(20))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	// "Sale 🎉 today" is 12 characters, leaving 8 to pad
+	want := `overflow:hidden;">Sale 🎉 today` + strings.Repeat("&nbsp;&zwnj;", 8) + `</div>`
+	if !strings.Contains(html, want) {
+		t.Errorf("expected padded preview %q", want)
+	}
+
+	html, err = Render(input, WithPreheaderPadding(5))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if strings.Contains(html, "&zwnj;") {
+		t.Error("preview longer than the padding should not be padded")
+	}
+
+	html, err = Render(input, WithPreviewText("Hi"), WithPreheaderPadding(4))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(html, `overflow:hidden;">Hi&nbsp;&zwnj;&nbsp;&zwnj;</div>`) {
+		t.Error("expected padded preview override")
+	}
+}
@@ -0,0 +1,59 @@
+package mjml
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// WithHTMLTransform calls fn with the final document parsed into an HTML tree,
+// so it can be changed in one pass, for example to add a tracking pixel,
+// rewrite every link or remove elements, instead of editing the output string.
+// The changed tree is written back as the output. Transforms given in several
+// options run in order on the same tree, and an error from one fails the
+// render.
+//
+// The tree is written with html.Render, which quotes and escapes attributes in
+// its own way, so the output no longer matches MJML byte for byte. Outlook
+// conditional comments are kept, as comment nodes. Source map offsets refer
+// to the document before the transforms.
+//
+// Example:
+//
+//	mjml.WithHTMLTransform(func(doc *html.Node) error {
+//		for n := range doc.Descendants() {
+//			if n.Type == html.ElementNode && n.Data == "body" {
+//				n.AppendChild(trackingPixel)
+//			}
+//		}
+//		return nil
+//	})
+func WithHTMLTransform(fn func(doc *html.Node) error) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.HTMLTransforms = append(opts.HTMLTransforms, fn)
+	}
+}
+
+// transformHTML parses document, runs the HTML transforms on it and renders
+// the result. The character policies are applied again, since parsing decodes
+// the references.
+func transformHTML(document string, opts *RenderOpts) (string, error) {
+	doc, err := html.Parse(strings.NewReader(document))
+	if err != nil {
+		return "", fmt.Errorf("parsing output for HTML transform: %w", err)
+	}
+	for _, transform := range opts.HTMLTransforms {
+		if err := transform(doc); err != nil {
+			return "", fmt.Errorf("HTML transform: %w", err)
+		}
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(document))
+	if err := html.Render(&sb, doc); err != nil {
+		return "", fmt.Errorf("rendering transformed output: %w", err)
+	}
+	output := sb.String()
+	return escapeOutput(output, opts), nil
+}
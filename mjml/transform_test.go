@@ -0,0 +1,76 @@
+package mjml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func TestWithHTMLTransform(t *testing.T) {
+	input := `<mjml><mj-body><mj-section><mj-column>
+  <mj-button href="https://example.com/a">Buy</mj-button>
+  <mj-text><a href="https://example.com/b">Read</a></mj-text>
+</mj-column></mj-section></mj-body></mjml>`
+
+	rewriteLinks := func(doc *html.Node) error {
+		for n := range doc.Descendants() {
+			if n.DataAtom != atom.A {
+				continue
+			}
+			for i, attr := range n.Attr {
+				if attr.Key == "href" {
+					n.Attr[i].Val = "https://track.example.com/?u=" + attr.Val
+				}
+			}
+		}
+		return nil
+	}
+	addPixel := func(doc *html.Node) error {
+		for n := range doc.Descendants() {
+			if n.DataAtom == atom.Body {
+				n.AppendChild(&html.Node{Type: html.ElementNode, Data: "img", DataAtom: atom.Img,
+					Attr: []html.Attribute{{Key: "src", Val: "https://track.example.com/open.gif"}}})
+				break
+			}
+		}
+		return nil
+	}
+
+	output, err := Render(input, WithHTMLTransform(rewriteLinks), WithHTMLTransform(addPixel))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{
+		`href="https://track.example.com/?u=https://example.com/a"`,
+		`href="https://track.example.com/?u=https://example.com/b"`,
+		`<img src="https://track.example.com/open.gif"/></body>`,
+		`<!--[if mso | IE]>`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if strings.Contains(output, `href="https://example.com/`) {
+		t.Error("expected every link to be rewritten")
+	}
+
+	errBroken := errors.New("broken")
+	if _, err := Render(input, WithHTMLTransform(func(*html.Node) error { return errBroken })); !errors.Is(err, errBroken) {
+		t.Errorf("Render() error = %v, want %v", err, errBroken)
+	}
+}
+
+func TestWithHTMLTransformKeepsNonASCIIEscaping(t *testing.T) {
+	input := `<mjml><mj-body><mj-section><mj-column><mj-text>Grüße</mj-text></mj-column></mj-section></mj-body></mjml>`
+
+	output, err := Render(input, WithTextEscaping(EscapeNonASCII), WithHTMLTransform(func(*html.Node) error { return nil }))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(output, "Gr&#xFC;&#xDF;e") {
+		t.Error("expected non-ASCII characters to stay escaped after the transform")
+	}
+}
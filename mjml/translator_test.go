@@ -0,0 +1,63 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithTranslator(t *testing.T) {
+	const doc = `<mjml lang="de"><mj-body><mj-section><mj-column>
+<mj-text i18n-key="greeting">Hello</mj-text>
+<mj-button i18n-key="cta" href="https://example.com">Buy now</mj-button>
+<mj-text i18n-key="missing">Untranslated</mj-text>
+<mj-text>No key</mj-text>
+</mj-column></mj-section></mj-body></mjml>`
+
+	catalog := map[string]map[string]string{
+		"de": {"greeting": "Hallo <strong>Welt</strong>", "cta": "Jetzt kaufen"},
+	}
+	var langs []string
+	translate := func(key, lang string) string {
+		langs = append(langs, lang)
+		return catalog[lang][key]
+	}
+
+	html, err := Render(doc, WithTranslator(translate))
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	for _, want := range []string{"Hallo <strong>Welt</strong>", "Jetzt kaufen", "Untranslated", "No key"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %q in output", want)
+		}
+	}
+	for _, notWant := range []string{">Hello<", "Buy now", "i18n-key"} {
+		if strings.Contains(html, notWant) {
+			t.Errorf("did not expect %q in output", notWant)
+		}
+	}
+	if len(langs) != 3 {
+		t.Fatalf("expected 3 translator calls, got %d", len(langs))
+	}
+	for _, lang := range langs {
+		if lang != "de" {
+			t.Errorf("expected document language de, got %q", lang)
+		}
+	}
+
+	text, err := RenderText(doc, WithTranslator(translate))
+	if err != nil {
+		t.Fatalf("render text: %v", err)
+	}
+	if !strings.Contains(text, "Hallo Welt") || !strings.Contains(text, "Jetzt kaufen (https://example.com)") {
+		t.Errorf("expected translated plain text, got %q", text)
+	}
+
+	untranslated, err := Render(doc)
+	if err != nil {
+		t.Fatalf("render without translator: %v", err)
+	}
+	if !strings.Contains(untranslated, "Hello") || !strings.Contains(untranslated, "Buy now") {
+		t.Error("expected template content without a translator")
+	}
+}
@@ -0,0 +1,134 @@
+package mjml
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml/options"
+)
+
+// ValidationLevel is an alias for convenience
+type ValidationLevel = options.ValidationLevel
+
+// Validation levels
+const (
+	ValidationSoft   = options.ValidationSoft
+	ValidationSkip   = options.ValidationSkip
+	ValidationStrict = options.ValidationStrict
+)
+
+// WithValidationLevel selects how invalid attributes and unknown tags are
+// handled, like MJML's validationLevel option. ValidationSoft (the default)
+// renders the document and returns the problems as an Error alongside it.
+// ValidationSkip renders without validating. ValidationStrict returns the first
+// problem as an Error and no output.
+func WithValidationLevel(level ValidationLevel) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.ValidationLevel = level
+	}
+}
+
+// ParseValidationLevel parses the MJML name of a validation level: "skip",
+// "soft" or "strict".
+func ParseValidationLevel(name string) (ValidationLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "soft":
+		return ValidationSoft, nil
+	case "skip":
+		return ValidationSkip, nil
+	case "strict":
+		return ValidationStrict, nil
+	}
+	return ValidationSoft, fmt.Errorf("unknown validation level %q (expected skip, soft or strict)", name)
+}
+
+// unsupportedTags are MJML tags this renderer ignores. They are valid MJML, so
+// they are not reported as unknown.
+var unsupportedTags = map[string]struct{}{
+	"mj-breakpoint":      {},
+	"mj-html-attributes": {},
+	"mj-include":         {},
+}
+
+func isUnsupportedTag(tagName string) bool {
+	_, ok := unsupportedTags[tagName]
+	return ok
+}
+
+// validationCollector gathers the validation problems reported while the
+// component tree is built.
+type validationCollector struct {
+	level ValidationLevel
+	err   *Error
+}
+
+// collectValidation installs reporters on renderOpts that record invalid
+// attributes and attribute values, unknown tags and script hrefs, chaining to
+// any reporters set by the caller.
+// With ValidationSkip no reporters are installed, so nothing is validated.
+func collectValidation(renderOpts *RenderOpts) *validationCollector {
+	c := &validationCollector{level: renderOpts.ValidationLevel}
+	if c.level == ValidationSkip {
+		renderOpts.InvalidAttributeReporter = nil
+		renderOpts.InvalidTagReporter = nil
+		renderOpts.UnsafeHrefReporter = nil
+		renderOpts.InvalidValueReporter = nil
+		return c
+	}
+
+	existingAttrReporter := renderOpts.InvalidAttributeReporter
+	renderOpts.InvalidAttributeReporter = func(tagName, attrName string, line int) {
+		c.add(ErrInvalidAttribute(tagName, attrName, line))
+		if existingAttrReporter != nil {
+			existingAttrReporter(tagName, attrName, line)
+		}
+	}
+	existingTagReporter := renderOpts.InvalidTagReporter
+	renderOpts.InvalidTagReporter = func(tagName string, line int) {
+		c.add(ErrUnknownTag(tagName, line))
+		if existingTagReporter != nil {
+			existingTagReporter(tagName, line)
+		}
+	}
+	existingValueReporter := renderOpts.InvalidValueReporter
+	renderOpts.InvalidValueReporter = func(tagName, attrName, value string, problem error, line int) {
+		c.add(ErrInvalidAttributeValue(tagName, attrName, value, problem, line))
+		if existingValueReporter != nil {
+			existingValueReporter(tagName, attrName, value, problem, line)
+		}
+	}
+	existingHrefReporter := renderOpts.UnsafeHrefReporter
+	renderOpts.UnsafeHrefReporter = func(tagName, href string, line int) {
+		c.add(ErrUnsafeHref(tagName, href, line))
+		if existingHrefReporter != nil {
+			existingHrefReporter(tagName, href, line)
+		}
+	}
+	return c
+}
+
+// add records a problem.
+func (c *validationCollector) add(err *Error) {
+	if c.err == nil {
+		c.err = err
+	} else {
+		c.err.Append(err)
+	}
+}
+
+// strictErr returns the first recorded problem when validating strictly, or
+// nil when rendering may continue.
+func (c *validationCollector) strictErr() error {
+	if c.level != ValidationStrict || c.err == nil || len(c.err.Details) == 0 {
+		return nil
+	}
+	return Error{Message: c.err.Message, Details: c.err.Details[:1]}
+}
+
+// result returns the recorded problems as an error, or nil when there are none.
+func (c *validationCollector) result() error {
+	if c.err == nil {
+		return nil
+	}
+	return *c.err
+}
@@ -0,0 +1,169 @@
+package mjml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const invalidDocument = `<mjml>
+  <mj-body>
+    <mj-section>
+      <mj-column>
+        <mj-text bogus="1">Hello</mj-text>
+        <mj-foo>ignored</mj-foo>
+      </mj-column>
+    </mj-section>
+  </mj-body>
+</mjml>`
+
+func TestValidationLevels(t *testing.T) {
+	t.Run("soft", func(t *testing.T) {
+		html, err := Render(invalidDocument)
+		if !strings.Contains(html, "Hello") {
+			t.Fatal("soft validation should still render")
+		}
+		var mjmlErr Error
+		if !errors.As(err, &mjmlErr) {
+			t.Fatalf("expected an Error, got %v", err)
+		}
+		if len(mjmlErr.Details) != 2 {
+			t.Fatalf("expected 2 problems, got %v", mjmlErr.Details)
+		}
+		if got := mjmlErr.Details[0].Message; got != "Invalid attribute 'bogus' for tag <mj-text>" {
+			t.Errorf("first problem = %q", got)
+		}
+		if got := mjmlErr.Details[1]; got.TagName != "mj-foo" || got.Line != 6 || got.Message != "Element <mj-foo> doesn't exist or is not registered" {
+			t.Errorf("second problem = %+v", got)
+		}
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		reported := false
+		html, err := Render(invalidDocument, WithValidationLevel(ValidationSkip), func(opts *RenderOpts) {
+			opts.InvalidAttributeReporter = func(string, string, int) { reported = true }
+		})
+		if err != nil {
+			t.Fatalf("skip validation returned %v", err)
+		}
+		if !strings.Contains(html, "Hello") {
+			t.Error("skip validation should render")
+		}
+		if reported {
+			t.Error("skip validation should not validate attributes")
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		html, err := Render(invalidDocument, WithValidationLevel(ValidationStrict))
+		if html != "" {
+			t.Error("strict validation should not render an invalid document")
+		}
+		var mjmlErr Error
+		if !errors.As(err, &mjmlErr) {
+			t.Fatalf("expected an Error, got %v", err)
+		}
+		if len(mjmlErr.Details) != 1 || mjmlErr.Details[0].TagName != "mj-text" {
+			t.Errorf("strict validation should report only the first problem, got %v", mjmlErr.Details)
+		}
+
+		if _, err := Compile(invalidDocument, WithValidationLevel(ValidationStrict)); err == nil {
+			t.Error("Compile should fail under strict validation")
+		}
+	})
+
+	t.Run("strict valid document", func(t *testing.T) {
+		_, err := Render(`<mjml><mj-head><mj-breakpoint width="320px" /></mj-head><mj-body><mj-text>ok</mj-text></mj-body></mjml>`,
+			WithValidationLevel(ValidationStrict))
+		if err != nil {
+			t.Errorf("unsupported but valid MJML tags should not fail validation: %v", err)
+		}
+	})
+}
+
+func TestParseValidationLevel(t *testing.T) {
+	for _, level := range []ValidationLevel{ValidationSoft, ValidationSkip, ValidationStrict} {
+		parsed, err := ParseValidationLevel(strings.ToUpper(level.String()))
+		if err != nil || parsed != level {
+			t.Errorf("ParseValidationLevel(%q) = %v, %v", level.String(), parsed, err)
+		}
+	}
+	if _, err := ParseValidationLevel("loose"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestScriptHrefDiagnostic(t *testing.T) {
+	input := `<mjml><mj-body><mj-section><mj-column>
+  <mj-button href="mailto:team@example.com?subject=Hi">Mail</mj-button>
+  <mj-button href=" JavaScript:alert(1)">Click</mj-button>
+</mj-column></mj-section></mj-body></mjml>`
+
+	html, err := Render(input)
+	if !strings.Contains(html, "Click") {
+		t.Fatal("a script href should not stop the render")
+	}
+	var mjmlErr Error
+	if !errors.As(err, &mjmlErr) || len(mjmlErr.Details) != 1 {
+		t.Fatalf("expected one problem, got %v", err)
+	}
+	if got := mjmlErr.Details[0]; got.TagName != "mj-button" || got.Line != 3 || !strings.Contains(got.Message, "runs script") {
+		t.Errorf("problem = %+v", got)
+	}
+
+	if _, err := Render(input, WithValidationLevel(ValidationSkip)); err != nil {
+		t.Errorf("skip validation returned %v", err)
+	}
+}
+
+func TestInvalidAttributeValueDiagnostic(t *testing.T) {
+	input := `<mjml><mj-body><mj-section><mj-column>
+  <mj-image src="a.png" width="300" padding="10px 5%" />
+  <mj-social-element name="facebook" icon-size="2em" />
+</mj-column></mj-section></mj-body></mjml>`
+
+	html, err := Render(input)
+	if !strings.Contains(html, `src="a.png"`) {
+		t.Fatal("an invalid attribute value should not stop the render")
+	}
+	var mjmlErr Error
+	if !errors.As(err, &mjmlErr) || len(mjmlErr.Details) != 1 {
+		t.Fatalf("expected one problem, got %v", err)
+	}
+	want := `Attribute 'icon-size' of <mj-social-element> has invalid value "2em": only accepts (px, %) units and 1 value(s)`
+	if got := mjmlErr.Details[0]; got.TagName != "mj-social-element" || got.Line != 3 || got.Message != want {
+		t.Errorf("problem = %+v", got)
+	}
+
+	if _, err := Render(input, WithValidationLevel(ValidationSkip)); err != nil {
+		t.Errorf("skip validation returned %v", err)
+	}
+}
+
+func TestAttributeValueTypeDiagnostics(t *testing.T) {
+	input := `<mjml><mj-body><mj-section background-color="linear-gradient(#fff, #000)"><mj-column>
+  <mj-button align="middle" color="blue" href="https://example.com">Go</mj-button>
+  <mj-text color="not-a-color" align="justify">Hi</mj-text>
+  <mj-image src="http://exa mple.com/a.png" />
+  <mj-divider border-color="currentColor" />
+</mj-column></mj-section></mj-body></mjml>`
+
+	_, err := Render(input)
+	var mjmlErr Error
+	if !errors.As(err, &mjmlErr) {
+		t.Fatalf("expected validation problems, got %v", err)
+	}
+	want := []ErrorDetail{
+		{Line: 2, TagName: "mj-button", Message: `Attribute 'align' of <mj-button> has invalid value "middle": only accepts one of (left, center, right)`},
+		{Line: 3, TagName: "mj-text", Message: `Attribute 'color' of <mj-text> has invalid value "not-a-color": only accepts colors`},
+		{Line: 4, TagName: "mj-image", Message: `Attribute 'src' of <mj-image> has invalid value "http://exa mple.com/a.png": only accepts URLs: invalid character " " in host name`},
+	}
+	if len(mjmlErr.Details) != len(want) {
+		t.Fatalf("expected %d problems, got %v", len(want), err)
+	}
+	for i, got := range mjmlErr.Details {
+		if got != want[i] {
+			t.Errorf("problem %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package mjml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/preslavrachev/gomjml/mjml/options"
+)
+
+// Features is an alias for convenience
+type Features = options.Features
+
+// mjmlRelease is an MJML release whose output differs from the one before it.
+type mjmlRelease struct {
+	minor    int // MJML 4 minor version
+	features Features
+}
+
+// mjmlReleases lists the behaviors of each MJML 4 release that changed the
+// output, oldest first. A version between two entries behaves like the older
+// one; the last entry is the latest behavior and has zero Features.
+var mjmlReleases = []mjmlRelease{
+	{minor: 13, features: Features{OmitRootAccessibility: true}},
+	{minor: 15},
+}
+
+// WithMJMLVersion selects the MJML release whose output is matched, such as
+// "4.13" or "4.15.3", replacing the version attribute of the mjml root:
+//
+//	<mjml version="4.14">
+//
+// Releases older than the oldest one gomjml knows behave like it, and newer
+// ones like the latest. Without a version the output matches the latest
+// release. An unsupported version fails the render.
+func WithMJMLVersion(version string) RenderOption {
+	return func(opts *RenderOpts) {
+		opts.OverrideVersion = version
+	}
+}
+
+// FeaturesForVersion returns the output behaviors of an MJML release, given as
+// "4.13", "4.13.1" or "v4.13". An empty version returns those of the latest
+// release.
+func FeaturesForVersion(version string) (Features, error) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if version == "" {
+		return Features{}, nil
+	}
+	parts := strings.SplitN(version, ".", 3)
+	major, err := strconv.Atoi(parts[0])
+	if err != nil || major != 4 {
+		return Features{}, fmt.Errorf("unsupported MJML version %q (expected 4.x)", version)
+	}
+	if len(parts) == 1 {
+		return Features{}, nil
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil || minor < 0 {
+		return Features{}, fmt.Errorf("invalid MJML version %q", version)
+	}
+
+	release := mjmlReleases[0]
+	for _, r := range mjmlReleases[1:] {
+		if r.minor > minor {
+			break
+		}
+		release = r
+	}
+	return release.features, nil
+}
@@ -0,0 +1,76 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFeaturesForVersion(t *testing.T) {
+	tests := map[string]bool{
+		"":        false,
+		"4":       false,
+		"4.9":     true,
+		"4.13":    true,
+		"v4.14.1": true,
+		"4.15":    false,
+		"4.15.3":  false,
+		"4.20":    false,
+	}
+	for version, omit := range tests {
+		features, err := FeaturesForVersion(version)
+		if err != nil {
+			t.Errorf("FeaturesForVersion(%q) failed: %v", version, err)
+			continue
+		}
+		if features.OmitRootAccessibility != omit {
+			t.Errorf("FeaturesForVersion(%q).OmitRootAccessibility = %v, want %v", version, features.OmitRootAccessibility, omit)
+		}
+	}
+	for _, version := range []string{"3.3", "5.0", "latest", "4.x"} {
+		if _, err := FeaturesForVersion(version); err == nil {
+			t.Errorf("expected FeaturesForVersion(%q) to fail", version)
+		}
+	}
+}
+
+func TestMJMLVersion(t *testing.T) {
+	const document = `<mjml%s><mj-head><mj-title>News</mj-title></mj-head><mj-body>` +
+		`<mj-section><mj-column><mj-text>Hi</mj-text></mj-column></mj-section></mj-body></mjml>`
+	const accessibleRoot = `aria-roledescription="email" role="article" lang="und" dir="auto" aria-label="News"`
+
+	latest, err := Render(strings.Replace(document, "%s", "", 1), WithValidationLevel(ValidationStrict))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(latest, `<html lang="und" dir="auto"`) || !strings.Contains(latest, accessibleRoot) {
+		t.Error("expected the latest release to add the accessibility attributes")
+	}
+
+	pragma, err := Render(strings.Replace(document, "%s", ` version="4.13.0"`, 1), WithValidationLevel(ValidationStrict))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(pragma, `<html lang="und" xmlns=`) || strings.Contains(pragma, "aria-roledescription") {
+		t.Error("expected the version attribute to select MJML 4.13 output")
+	}
+
+	override, err := Render(strings.Replace(document, "%s", ` version="4.13"`, 1), WithMJMLVersion("4.15"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if override != latest {
+		t.Error("expected WithMJMLVersion to replace the version attribute")
+	}
+
+	rtl, err := Render(strings.Replace(document, "%s", ` dir="rtl"`, 1), WithMJMLVersion("4.13"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(rtl, `<html lang="und" dir="rtl"`) {
+		t.Error("expected an explicit direction to be kept")
+	}
+
+	if _, err := Render(strings.Replace(document, "%s", "", 1), WithMJMLVersion("3.3")); err == nil {
+		t.Error("expected an unsupported version to fail the render")
+	}
+}
@@ -0,0 +1,42 @@
+package mjml
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestFullWidthWrapperBorderRadiusAndPadding verifies that a full-width wrapper applies
+// border-radius like the boxed wrapper and that its padding shorthand narrows every
+// nested section, including transitions between three or more sections.
+func TestFullWidthWrapperBorderRadiusAndPadding(t *testing.T) {
+	input := `<mjml>
+  <mj-body>
+    <mj-wrapper full-width="full-width" border-radius="10px" padding="20px" background-color="#eeeeee">
+      <mj-section background-color="#ff0000"><mj-column><mj-text>One</mj-text></mj-column></mj-section>
+      <mj-section><mj-column><mj-text>Two</mj-text></mj-column></mj-section>
+      <mj-section background-color="#0000ff"><mj-column><mj-text>Three</mj-text></mj-column></mj-section>
+    </mj-wrapper>
+  </mj-body>
+</mjml>`
+
+	html, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if !strings.Contains(html, `<div style="margin:0px auto;max-width:600px;border-radius:10px;overflow:hidden;">`) {
+		t.Error("expected border-radius and overflow on the wrapper container")
+	}
+	if !strings.Contains(html, `style="width:100%;border-collapse:separate;"`) {
+		t.Error("expected separate border collapsing on the rounded wrapper table")
+	}
+	if !strings.Contains(html, `<td style="border-radius:10px;direction:ltr;font-size:0px;padding:20px;text-align:center;">`) {
+		t.Error("expected border-radius on the wrapper cell")
+	}
+	if got := strings.Count(html, `style="width:560px;" width="560"`); got != 3 {
+		t.Errorf("expected 3 sections narrowed by the wrapper padding, got %d", got)
+	}
+	if got := strings.Count(html, `</td></tr></table></td></tr><tr><td class="" width="600px" >`); got != 2 {
+		t.Errorf("expected 2 Outlook transitions between sections, got %d", got)
+	}
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"strings"
+)
+
+// stripNodeComments removes non-conditional HTML comments from the text of
+// node and its descendants, as selected by opts.
+func stripNodeComments(node *MJMLNode, opts ParseOptions) {
+	strip := opts.StripComments
+	if node.XMLName.Local == "mj-raw" {
+		strip = opts.StripRawComments && !node.Verbatim
+	}
+
+	if strip {
+		node.Text = stripHTMLComments(node.Text)
+		for i := range node.MixedContent {
+			if node.MixedContent[i].Node == nil {
+				node.MixedContent[i].Text = stripHTMLComments(node.MixedContent[i].Text)
+			}
+		}
+	}
+	for _, child := range node.Children {
+		stripNodeComments(child, opts)
+	}
+}
+
+// stripHTMLComments removes the HTML comments in s, keeping conditional
+// comments (<!--[if ...]>, <![endif]--> and their downlevel-revealed forms).
+func stripHTMLComments(s string) string {
+	start := strings.Index(s, "<!--")
+	if start < 0 {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for start >= 0 {
+		end := strings.Index(s[start+4:], "-->")
+		if end < 0 {
+			break
+		}
+		end += start + 4
+		sb.WriteString(s[:start])
+		if isConditionalCommentBody(s[start+4 : end]) {
+			sb.WriteString(s[start : end+3])
+		}
+		s = s[end+3:]
+		start = strings.Index(s, "<!--")
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
+
+// isConditionalCommentBody reports whether the text between "<!--" and "-->"
+// belongs to a conditional comment.
+func isConditionalCommentBody(body string) bool {
+	body = strings.TrimSpace(body)
+	return strings.HasPrefix(body, "[if") || strings.HasPrefix(body, "<![endif]")
+}
@@ -0,0 +1,21 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestStripHTMLComments(t *testing.T) {
+	tests := map[string]string{
+		"no comments":                                 "no comments",
+		"a<!-- x -->b<!---->c":                        "abc",
+		"<!--[if mso]><b>x</b><![endif]-->":           "<!--[if mso]><b>x</b><![endif]-->",
+		"<!--[if !mso]><!--><p>y</p><!--<![endif]-->": "<!--[if !mso]><!--><p>y</p><!--<![endif]-->",
+		"<!-- x --><!--[if mso]>-->":                  "<!--[if mso]>-->",
+		"unterminated <!-- comment":                   "unterminated <!-- comment",
+	}
+	for input, want := range tests {
+		if got := stripHTMLComments(input); got != want {
+			t.Errorf("stripHTMLComments(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
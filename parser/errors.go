@@ -0,0 +1,338 @@
+package parser
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxSyntaxErrors bounds how many problems a single ParseError reports, so a
+// badly broken document does not produce an unreadable wall of errors.
+const maxSyntaxErrors = 50
+
+// SyntaxError describes one malformed construct in an MJML document.
+type SyntaxError struct {
+	Line      int    // 1-based line in the MJML source
+	Column    int    // 1-based byte column in the line, or 0 when unknown
+	Tag       string // Tag the problem belongs to, if known
+	Attribute string // Attribute the problem belongs to, if any
+	Message   string
+}
+
+func (e *SyntaxError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("line %d", e.Line))
+	if e.Column > 0 {
+		sb.WriteString(fmt.Sprintf(", column %d", e.Column))
+	}
+	sb.WriteString(": ")
+	if e.Tag != "" {
+		sb.WriteString("<" + e.Tag + "> ")
+	}
+	sb.WriteString(e.Message)
+	return sb.String()
+}
+
+// ParseError is returned by ParseMJML for malformed documents. Parsing continues
+// past the first problem, so Errors lists every syntax error found in one pass.
+type ParseError struct {
+	Errors []*SyntaxError
+}
+
+func (e *ParseError) Error() string {
+	if len(e.Errors) == 1 {
+		return "failed to parse MJML: " + e.Errors[0].Error()
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("failed to parse MJML: %d syntax errors", len(e.Errors)))
+	for _, err := range e.Errors {
+		sb.WriteString("\n- " + err.Error())
+	}
+	return sb.String()
+}
+
+// Unwrap exposes the individual syntax errors to errors.As.
+func (e *ParseError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// newParseError explains a decoder failure. The original source is rescanned
+// with a tolerant scanner that reports positions in the user's document rather
+// than in the preprocessed copy given to encoding/xml. When the scanner finds
+// nothing, the decoder's own error is used.
+func newParseError(original string, decodeErr error) error {
+	if errs := scanSyntaxErrors(original); len(errs) > 0 {
+		return &ParseError{Errors: errs}
+	}
+	return newDecodeError(decodeErr)
+}
+
+// newDecodeError explains a decoder failure with the decoder's own error, for
+// when the original source is not available to rescan.
+func newDecodeError(decodeErr error) error {
+	var xmlErr *xml.SyntaxError
+	if errors.As(decodeErr, &xmlErr) {
+		return &ParseError{Errors: []*SyntaxError{{Line: xmlErr.Line, Message: xmlErr.Msg}}}
+	}
+	return fmt.Errorf("failed to parse MJML: %w", decodeErr)
+}
+
+// rawContentTags hold HTML that is not parsed as XML; their content is skipped.
+var rawContentTags = map[string]struct{}{
+	"mj-text": {},
+	"mj-raw":  {},
+}
+
+type openTag struct {
+	name   string
+	offset int
+}
+
+// syntaxScanner walks MJML source and records problems instead of stopping.
+type syntaxScanner struct {
+	src     string
+	lines   *lineLookup
+	pos     int
+	stack   []openTag
+	errs    []*SyntaxError
+	sawRoot bool
+}
+
+// scanSyntaxErrors returns the syntax errors in src, continuing after each one.
+func scanSyntaxErrors(src string) []*SyntaxError {
+	s := &syntaxScanner{src: src, lines: newLineLookup([]byte(src))}
+	s.scan()
+	return s.errs
+}
+
+func (s *syntaxScanner) report(offset int, tag, attr, format string, args ...any) {
+	if len(s.errs) >= maxSyntaxErrors {
+		return
+	}
+	line := s.lines.Line(int64(offset))
+	s.errs = append(s.errs, &SyntaxError{
+		Line:      line,
+		Column:    offset - s.lines.lineOffsets[line-1] + 1,
+		Tag:       tag,
+		Attribute: attr,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+func (s *syntaxScanner) scan() {
+	for s.pos < len(s.src) && len(s.errs) < maxSyntaxErrors {
+		next := strings.IndexByte(s.src[s.pos:], '<')
+		if next < 0 {
+			break
+		}
+		s.pos += next
+		rest := s.src[s.pos:]
+
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			s.skipPast("-->", "", "comment is never closed with -->")
+		case strings.HasPrefix(rest, "<![CDATA["):
+			s.skipPast("]]>", "", "CDATA section is never closed with ]]>")
+		case strings.HasPrefix(rest, "<?"):
+			s.skipPast("?>", "", "processing instruction is never closed with ?>")
+		case strings.HasPrefix(rest, "<!"):
+			s.skipPast(">", "", "declaration is never closed with >")
+		case strings.HasPrefix(rest, "</"):
+			s.scanEndTag()
+		default:
+			s.scanStartTag()
+		}
+	}
+
+	for _, tag := range s.stack {
+		s.report(tag.offset, tag.name, "", "%s", unclosedMessage(tag.name, ""))
+	}
+	if !s.sawRoot && len(s.errs) == 0 {
+		s.report(0, "", "", "document has no <mjml> root element")
+	}
+}
+
+// skipPast moves past the next occurrence of terminator, reporting message and
+// stopping the scan when it is missing.
+func (s *syntaxScanner) skipPast(terminator, tag, message string) {
+	end := strings.Index(s.src[s.pos:], terminator)
+	if end < 0 {
+		s.report(s.pos, tag, "", "%s", message)
+		s.pos = len(s.src)
+		return
+	}
+	s.pos += end + len(terminator)
+}
+
+func (s *syntaxScanner) scanEndTag() {
+	start := s.pos
+	s.pos += 2
+	name := s.readName()
+	s.skipSpace()
+	if s.pos >= len(s.src) || s.src[s.pos] != '>' {
+		s.report(start, name, "", "closing tag is missing >")
+		s.skipTo('<')
+	} else {
+		s.pos++
+	}
+	if name == "" {
+		s.report(start, "", "", "closing tag has no name")
+		return
+	}
+
+	for i := len(s.stack) - 1; i >= 0; i-- {
+		if s.stack[i].name != name {
+			continue
+		}
+		for _, unclosed := range s.stack[i+1:] {
+			s.report(unclosed.offset, unclosed.name, "", "%s", unclosedMessage(unclosed.name, name))
+		}
+		s.stack = s.stack[:i]
+		return
+	}
+	s.report(start, name, "", "closing tag has no matching opening tag")
+}
+
+func (s *syntaxScanner) scanStartTag() {
+	start := s.pos
+	s.pos++
+	name := s.readName()
+	if name == "" {
+		s.report(start, "", "", "'<' does not start a tag; escape it as &lt;")
+		return
+	}
+	if len(s.stack) == 0 && s.sawRoot {
+		s.report(start, name, "", "appears after the root element is closed")
+	}
+	s.sawRoot = true
+
+	seen := make(map[string]struct{})
+	for {
+		s.skipSpace()
+		if s.pos >= len(s.src) {
+			s.report(start, name, "", "opening tag is never closed with >")
+			return
+		}
+		switch {
+		case strings.HasPrefix(s.src[s.pos:], "/>"):
+			s.pos += 2
+			return
+		case s.src[s.pos] == '>':
+			s.pos++
+			s.openElement(name, start)
+			return
+		case s.src[s.pos] == '<':
+			s.report(start, name, "", "opening tag is missing >")
+			s.openElement(name, start)
+			return
+		}
+
+		attrStart := s.pos
+		attr := s.readName()
+		if attr == "" {
+			s.report(s.pos, name, "", "unexpected character %q in tag", s.src[s.pos])
+			s.pos++
+			continue
+		}
+		if _, dup := seen[attr]; dup {
+			s.report(attrStart, name, attr, "attribute %q is repeated", attr)
+		}
+		seen[attr] = struct{}{}
+
+		s.skipSpace()
+		if s.pos >= len(s.src) || s.src[s.pos] != '=' {
+			s.report(attrStart, name, attr, "attribute %q has no value", attr)
+			continue
+		}
+		s.pos++
+		s.skipSpace()
+		if s.pos >= len(s.src) {
+			continue
+		}
+
+		quote := s.src[s.pos]
+		if quote != '"' && quote != '\'' {
+			s.report(s.pos, name, attr, "value of attribute %q must be quoted", attr)
+			for s.pos < len(s.src) && !isSpace(s.src[s.pos]) && s.src[s.pos] != '>' && !strings.HasPrefix(s.src[s.pos:], "/>") {
+				s.pos++
+			}
+			continue
+		}
+		end := strings.IndexByte(s.src[s.pos+1:], quote)
+		if end < 0 {
+			s.report(s.pos, name, attr, "value of attribute %q is never closed", attr)
+			s.pos = len(s.src)
+			return
+		}
+		value := s.src[s.pos+1 : s.pos+1+end]
+		if lt := strings.IndexByte(value, '<'); lt >= 0 {
+			s.report(s.pos+1+lt, name, attr, "value of attribute %q contains '<'; escape it as &lt;", attr)
+		}
+		s.pos += end + 2
+	}
+}
+
+// openElement records an opened element, skipping the raw HTML body of ending
+// tags. A raw element without a closing tag takes the rest of the document and
+// is reported unclosed with its ancestors.
+func (s *syntaxScanner) openElement(name string, offset int) {
+	if _, raw := rawContentTags[name]; raw {
+		closing := "</" + name
+		if end := indexCI([]byte(s.src), []byte(closing), s.pos); end >= 0 {
+			s.pos = end
+		} else {
+			s.pos = len(s.src)
+		}
+	}
+	s.stack = append(s.stack, openTag{name: name, offset: offset})
+}
+
+// unclosedMessage explains a missing closing tag, found when parent closed or
+// at the end of the document when parent is empty. HTML void elements get a
+// hint, since MJML is parsed as XML and they must be self-closed.
+func unclosedMessage(name, parent string) string {
+	message := "is not closed"
+	if parent != "" {
+		message += " before </" + parent + ">"
+	}
+	if isVoidHTMLElement(name) {
+		message += "; write void elements as <" + name + " />"
+	}
+	return message
+}
+
+func (s *syntaxScanner) readName() string {
+	start := s.pos
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+		if isSpace(c) || c == '>' || c == '/' || c == '=' || c == '<' || c == '"' || c == '\'' {
+			break
+		}
+		s.pos++
+	}
+	return s.src[start:s.pos]
+}
+
+func (s *syntaxScanner) skipSpace() {
+	for s.pos < len(s.src) && isSpace(s.src[s.pos]) {
+		s.pos++
+	}
+}
+
+func (s *syntaxScanner) skipTo(c byte) {
+	if idx := strings.IndexByte(s.src[s.pos:], c); idx >= 0 {
+		s.pos += idx
+	} else {
+		s.pos = len(s.src)
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseMJMLSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []SyntaxError
+	}{
+		{
+			name: "reports several problems in one pass",
+			input: "<mjml>\n" +
+				"  <mj-body>\n" +
+				"    <mj-section padding=\"1px\" padding=\"2px\">\n" +
+				"      <mj-column>\n" +
+				"        <mj-button href=\"#\">Hi<br>there</mj-button>\n" +
+				"        <mj-image src=logo.png />\n" +
+				"        <mj-text>1 < 2 <br></mj-text>\n" +
+				"      </mj-column>\n" +
+				"    </mj-section>\n" +
+				"  </mj-body>\n" +
+				"</mjml>",
+			want: []SyntaxError{
+				{Line: 3, Column: 31, Tag: "mj-section", Attribute: "padding", Message: `attribute "padding" is repeated`},
+				{Line: 5, Column: 31, Tag: "br", Message: "is not closed before </mj-button>; write void elements as <br />"},
+				{Line: 6, Column: 23, Tag: "mj-image", Attribute: "src", Message: `value of attribute "src" must be quoted`},
+			},
+		},
+		{
+			name:  "mismatched closing tag",
+			input: "<mjml><mj-body><mj-section></mj-sectoin></mj-body></mjml>",
+			want: []SyntaxError{
+				{Line: 1, Column: 28, Tag: "mj-sectoin", Message: "closing tag has no matching opening tag"},
+				{Line: 1, Column: 16, Tag: "mj-section", Message: "is not closed before </mj-body>"},
+			},
+		},
+		{
+			name:  "truncated document",
+			input: "<mjml>\n<mj-body>\n<mj-text>Hello",
+			want: []SyntaxError{
+				{Line: 1, Column: 1, Tag: "mjml", Message: "is not closed"},
+				{Line: 2, Column: 1, Tag: "mj-body", Message: "is not closed"},
+				{Line: 3, Column: 1, Tag: "mj-text", Message: "is not closed"},
+			},
+		},
+		{
+			name:  "several tags unclosed before a closing tag",
+			input: "<mjml><mj-body><mj-section><mj-column><mj-spacer></mj-body></mjml>",
+			want: []SyntaxError{
+				{Line: 1, Column: 16, Tag: "mj-section", Message: "is not closed before </mj-body>"},
+				{Line: 1, Column: 28, Tag: "mj-column", Message: "is not closed before </mj-body>"},
+				{Line: 1, Column: 39, Tag: "mj-spacer", Message: "is not closed before </mj-body>"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMJML(tt.input)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("expected *ParseError, got %v", err)
+			}
+			if len(parseErr.Errors) != len(tt.want) {
+				t.Fatalf("expected %d errors, got %d:\n%v", len(tt.want), len(parseErr.Errors), err)
+			}
+			for i, want := range tt.want {
+				if got := *parseErr.Errors[i]; got != want {
+					t.Errorf("error %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	_, err := ParseMJML(`<mjml><mj-body><mj-image src=x.png /></mj-body></mjml>`)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	want := `failed to parse MJML: line 1, column 30: <mj-image> value of attribute "src" must be quoted`
+	if err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.Attribute != "src" {
+		t.Errorf("expected errors.As to find the SyntaxError, got %v", syntaxErr)
+	}
+}
+
+func TestParseMJMLEmptyDocument(t *testing.T) {
+	_, err := ParseMJML("")
+	if err == nil || !strings.Contains(err.Error(), "no <mjml> root element") {
+		t.Fatalf("expected missing root error, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package parser_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/preslavrachev/gomjml/mjml"
+	"github.com/preslavrachev/gomjml/parser"
+)
+
+// FuzzParseMJML feeds malformed documents to the parser and renders the ones
+// it accepts. Neither may panic, and rejected documents must be explained with
+// positions inside the source.
+func FuzzParseMJML(f *testing.F) {
+	for _, source := range []string{
+		`<mjml><mj-body><mj-section><mj-column><mj-text>Hi</mj-text></mj-column></mj-section></mj-body></mjml>`,
+		"<mjml>\n<mj-body>\n<mj-text>Hello",
+		`<mjml><mj-body><mj-section><mj-column></mj-body></mjml>`,
+		`<mjml><mj-body></</mj-body></mjml>`,
+		`<mjml><mj-body></ mj-body></mjml>`,
+		`<mjml><mj-body><mj-text>a</mj-text></mj-body></mjml></`,
+		`<mjml><mj-body><mj-image src=x.png /></mj-body></mjml>`,
+		`<mjml><mj-body><mj-image src="x.png /></mj-body></mjml>`,
+		`<mjml><mj-body><mj-image src="a" src="b" alt /></mj-body></mjml>`,
+		`<mjml><mj-body><mj-button href="<x>" =">Go</mj-button></mj-body></mjml>`,
+		`<mjml><mj-head><mj-style><![CDATA[.a{color:red}]]></mj-style></mj-head><mj-body /></mjml>`,
+		`<mjml><mj-body><mj-raw><![CDATA[<p>unclosed</mj-raw></mj-body></mjml>`,
+		`<mjml><mj-body><mj-text><![CDATA[</mj-text>]]></mj-text></mj-body></mjml>`,
+		`<mjml><mj-body><!-- never closed </mj-body></mjml>`,
+		`<mjml><mj-body><mj-text>1 < 2 <br></mj-text></mj-body></mjml>`,
+		`<mjml><mj-body><mj-section><mj-column><mj-text></mj-column></mj-section></mj-body></mjml>`,
+		`<mjml><?xml version="1.0"<mj-body></mj-body></mjml>`,
+		`<mjml></mjml><mjml>`,
+		`<`,
+		``,
+	} {
+		f.Add(source)
+	}
+	f.Fuzz(func(t *testing.T, source string) {
+		_, err := parser.ParseMJML(source)
+		if err == nil {
+			_, _ = mjml.Render(source)
+			return
+		}
+
+		var parseErr *parser.ParseError
+		if !errors.As(err, &parseErr) {
+			return
+		}
+		if len(parseErr.Errors) == 0 {
+			t.Fatal("ParseError without syntax errors")
+		}
+		for _, syntaxErr := range parseErr.Errors {
+			if syntaxErr.Line < 1 || syntaxErr.Column < 0 || syntaxErr.Message == "" {
+				t.Errorf("syntax error %+v has no position in the source or no message", *syntaxErr)
+			}
+		}
+	})
+}
@@ -85,6 +85,15 @@ type MJMLNode struct {
 	// as they originally appeared in the MJML source. Each entry contains either
 	// a text segment or a pointer to a child node.
 	MixedContent []MixedContentPart
+	// Verbatim is set on mj-raw nodes parsed with ParseOptions.RawPassthrough.
+	// Text then holds the source bytes from RawStart to RawEnd unchanged.
+	Verbatim bool
+	RawStart int // Offset of the first byte of the verbatim content in the source
+	RawEnd   int // Offset just past the verbatim content in the source
+	// AttributeScope is set on the content of an isolated mj-include to the
+	// included file's mj-head, whose mj-attributes apply to the node instead
+	// of the document's.
+	AttributeScope *MJMLNode
 }
 
 // MixedContentPart represents either a piece of text or a child node in the
@@ -145,25 +154,57 @@ func (ll *lineLookup) Line(offset int64) int {
 // </mjml>
 // The <mj-head> section is OPTIONAL and can be omitted entirely.
 
+// ParseOptions controls how MJML source is parsed
+type ParseOptions struct {
+	// RawPassthrough captures the inner content of mj-raw elements byte for
+	// byte from the source. The content bypasses entity preprocessing and XML
+	// decoding, so template merge tags, scripts and malformed HTML survive
+	// unchanged and need not be well-formed XML.
+	RawPassthrough bool
+	// StripComments removes HTML comments outside mj-raw from the AST.
+	// Conditional comments such as <!--[if mso]>...<![endif]--> are kept.
+	StripComments bool
+	// StripRawComments removes HTML comments inside mj-raw the same way.
+	// Content captured with RawPassthrough is never altered.
+	StripRawComments bool
+}
+
 // ParseMJML parses an MJML string into an AST
 func ParseMJML(mjmlContent string) (*MJMLNode, error) {
+	return ParseMJMLWithOptions(mjmlContent, ParseOptions{})
+}
+
+// ParseMJMLWithOptions parses an MJML string into an AST using opts
+func ParseMJMLWithOptions(mjmlContent string, opts ParseOptions) (*MJMLNode, error) {
+	source := mjmlContent
+	var raws []rawSegment
+	if opts.RawPassthrough {
+		mjmlContent, raws = extractRawContent(mjmlContent)
+	}
+
 	// AIDEV-NOTE: comment-preservation; Preserve all XML comments for MRML compatibility
 	// MRML preserves regular XML comments and wraps them with MSO conditionals
 	processedContent := stripNonMSOComments(mjmlContent)
 
-	// Pre-process HTML entities that XML parser doesn't handle
-	processedContent = preprocessHTMLEntities(processedContent)
-
 	// Wrap mj-text inner content in CDATA to preserve raw HTML
 	processedContent = wrapMJTextContent(processedContent)
 
+	// Pre-process HTML entities that XML parser doesn't handle
+	processedContent = preprocessHTMLEntities(processedContent)
+
 	contentBytes := []byte(processedContent)
 	lookup := newLineLookup(contentBytes)
 
 	decoder := xml.NewDecoder(bytes.NewReader(contentBytes))
 	root, err := parseNode(decoder, xml.StartElement{}, lookup, 0, contentBytes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse MJML: %w", err)
+		return nil, newParseError(source, err)
+	}
+	if opts.RawPassthrough {
+		restoreRawContent(root, source, raws)
+	}
+	if opts.StripComments || opts.StripRawComments {
+		stripNodeComments(root, opts)
 	}
 	return root, nil
 }
@@ -173,30 +214,64 @@ func ParseMJML(mjmlContent string) (*MJMLNode, error) {
 // escaped to &amp; for XML safety, then most entities are replaced with Unicode.
 // The &amp; entities are left for the XML parser to handle, preventing re-introduction
 // of invalid raw ampersands that would break XML parsing.
+//
+// Inside CDATA sections, which hold mj-text content verbatim, &lt;, &gt;, &quot;
+// and &apos; are kept: decoding them would turn escaped text into markup.
 func preprocessHTMLEntities(content string) string {
-	// First, escape raw ampersands in attribute values that aren't part of valid entities
-	result := escapeAttributeAmpersands(content)
-
-	// Replace the most common HTML entities with Unicode characters
-	// NOTE: &amp; entities are intentionally preserved - the XML parser will convert
-	// them to raw ampersands safely after parsing, maintaining XML validity.
-	result = strings.ReplaceAll(result, "&copy;", "©")
-	result = strings.ReplaceAll(result, "&reg;", "®")
-	result = strings.ReplaceAll(result, "&trade;", "™")
-	result = strings.ReplaceAll(result, "&lt;", "<")
-	result = strings.ReplaceAll(result, "&gt;", ">")
-	result = strings.ReplaceAll(result, "&quot;", `"`)
-	result = strings.ReplaceAll(result, "&apos;", "'")
-	result = strings.ReplaceAll(result, "&nbsp;", "\u00A0") // Unicode non-breaking space
-	result = strings.ReplaceAll(result, "&#xA0;", "\u00A0") // Numeric character reference for non-breaking space
-	result = strings.ReplaceAll(result, "&#160;", "\u00A0") // Decimal numeric reference for non-breaking space
-	result = strings.ReplaceAll(result, "&ndash;", "–")
-	result = strings.ReplaceAll(result, "&mdash;", "—")
-	result = strings.ReplaceAll(result, "&hellip;", "…")
+	if !strings.Contains(content, cdataStart) {
+		return htmlEntityReplacer.Replace(escapeAttributeAmpersands(content))
+	}
 
-	return result
+	var out strings.Builder
+	out.Grow(len(content))
+	for content != "" {
+		start := strings.Index(content, cdataStart)
+		if start == -1 {
+			out.WriteString(htmlEntityReplacer.Replace(escapeAttributeAmpersands(content)))
+			break
+		}
+		out.WriteString(htmlEntityReplacer.Replace(escapeAttributeAmpersands(content[:start])))
+
+		end := strings.Index(content[start+len(cdataStart):], cdataEnd)
+		if end == -1 {
+			out.WriteString(content[start:])
+			break
+		}
+		end += start + len(cdataStart)
+		out.WriteString(cdataStart)
+		out.WriteString(cdataEntityReplacer.Replace(escapeAttributeAmpersands(content[start+len(cdataStart) : end])))
+		out.WriteString(cdataEnd)
+		content = content[end+len(cdataEnd):]
+	}
+	return out.String()
 }
 
+// Replacements of the most common HTML entities with Unicode characters.
+// NOTE: &amp; entities are intentionally preserved - the XML parser will convert
+// them to raw ampersands safely after parsing, maintaining XML validity.
+var (
+	textEntityReplacements = []string{
+		"&copy;", "©",
+		"&reg;", "®",
+		"&trade;", "™",
+		"&nbsp;", "\u00A0", // Unicode non-breaking space
+		"&#xA0;", "\u00A0", // Numeric character reference for non-breaking space
+		"&#160;", "\u00A0", // Decimal numeric reference for non-breaking space
+		"&ndash;", "–",
+		"&mdash;", "—",
+		"&hellip;", "…",
+	}
+	markupEntityReplacements = []string{
+		"&lt;", "<",
+		"&gt;", ">",
+		"&quot;", `"`,
+		"&apos;", "'",
+	}
+
+	htmlEntityReplacer  = strings.NewReplacer(append(append([]string(nil), textEntityReplacements...), markupEntityReplacements...)...)
+	cdataEntityReplacer = strings.NewReplacer(textEntityReplacements...)
+)
+
 // escapeAttributeAmpersands escapes raw ampersands in XML attribute values
 // that aren't part of valid HTML entities. This prevents XML parsing errors
 // when URLs contain query parameters like "?param1=value1&param2=value2".
@@ -497,7 +572,7 @@ func wrapMJTextContent(content string) string {
 			continue
 		}
 
-		closeIdx := indexCI(b, []byte(closeNeedle), endStart)
+		closeIdx := findMJTextClose(b, endStart)
 		if closeIdx < 0 {
 			out.Write(b[endStart:])
 			break
@@ -505,7 +580,7 @@ func wrapMJTextContent(content string) string {
 
 		inner := b[endStart:closeIdx]
 
-		alreadyCDATA := bytes.HasPrefix(bytes.TrimLeft(inner, " \t\r\n"), []byte(cdataStart))
+		alreadyCDATA := isSingleCDATASection(inner)
 
 		inner = normalizeSelfClosingVoidTags(inner)
 
@@ -528,6 +603,65 @@ func wrapMJTextContent(content string) string {
 	return out.String()
 }
 
+// rawTextElements are the HTML elements whose content is not markup, so a
+// "</mj-text>" inside them does not end the mj-text.
+var rawTextElements = []string{"script", "style"}
+
+// findMJTextClose returns the index of the </mj-text> that closes the content
+// starting at from, or -1. Comments, CDATA sections and script and style
+// elements are skipped, so they may contain "</mj-text>".
+func findMJTextClose(b []byte, from int) int {
+	for i := from; i < len(b); i++ {
+		if b[i] != '<' {
+			continue
+		}
+		rest := b[i:]
+		switch {
+		case bytes.HasPrefix(rest, []byte("<!--")):
+			i = skipPastBytes(b, i, "-->")
+		case bytes.HasPrefix(rest, []byte(cdataStart)):
+			i = skipPastBytes(b, i, cdataEnd)
+		case len(rest) >= len(closeNeedle) && equalFoldASCII(rest[:len(closeNeedle)], []byte(closeNeedle)):
+			return i
+		default:
+			for _, name := range rawTextElements {
+				if hasTagPrefix(b, i, "<"+name) {
+					closeTag := "</" + name + ">"
+					if end := indexCI(b, []byte(closeTag), i); end >= 0 {
+						i = end + len(closeTag) - 1
+					} else {
+						i = len(b)
+					}
+					break
+				}
+			}
+		}
+	}
+	return -1
+}
+
+// skipPastBytes is skipPast for a byte slice.
+func skipPastBytes(b []byte, i int, needle string) int {
+	idx := bytes.Index(b[i+1:], []byte(needle))
+	if idx < 0 {
+		return len(b)
+	}
+	return i + 1 + idx + len(needle) - 1
+}
+
+// isSingleCDATASection reports whether content, apart from surrounding
+// whitespace, is exactly one CDATA section. Content with a CDATA section and
+// other markup is wrapped like any other content, which keeps the section
+// markers in the output.
+func isSingleCDATASection(content []byte) bool {
+	trimmed := bytes.TrimSpace(content)
+	if !bytes.HasPrefix(trimmed, []byte(cdataStart)) {
+		return false
+	}
+	end := bytes.Index(trimmed, []byte(cdataEnd))
+	return end == len(trimmed)-len(cdataEnd)
+}
+
 // findTagEnd returns the index *after* the '>' of the start tag at 'start'
 // and whether it was self-closing (<.../>). Respects single/double quotes.
 func findTagEnd(b []byte, start int) (end int, selfClosing bool) {
@@ -648,7 +782,7 @@ func parseNode(decoder *xml.Decoder, start xml.StartElement, lookup *lineLookup,
 		}
 	}
 
-	if lookup != nil && len(node.Attrs) > 0 {
+	if lookup != nil {
 		node.LineNumber = lookup.Line(startOffset)
 	}
 
@@ -761,6 +895,70 @@ func (n *MJMLNode) GetAttribute(name string) string {
 	return ""
 }
 
+// SetAttribute sets an attribute value, adding the attribute after the
+// existing ones when it is not present. The attribute slice is replaced rather
+// than modified, so nodes copied from n keep their attributes.
+func (n *MJMLNode) SetAttribute(name, value string) {
+	attrs := make([]xml.Attr, 0, len(n.Attrs)+1)
+	found := false
+	for _, attr := range n.Attrs {
+		if attr.Name.Local == name {
+			attr.Value = value
+			found = true
+		}
+		attrs = append(attrs, attr)
+	}
+	if !found {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+	}
+	n.Attrs = attrs
+}
+
+// RemoveAttribute removes an attribute. Like SetAttribute, it replaces the
+// attribute slice instead of modifying it.
+func (n *MJMLNode) RemoveAttribute(name string) {
+	attrs := make([]xml.Attr, 0, len(n.Attrs))
+	for _, attr := range n.Attrs {
+		if attr.Name.Local != name {
+			attrs = append(attrs, attr)
+		}
+	}
+	n.Attrs = attrs
+}
+
+// Clone returns a deep copy of the node and its descendants. MixedContent
+// entries of the copy point to the copied children.
+func (n *MJMLNode) Clone() *MJMLNode {
+	if n == nil {
+		return nil
+	}
+	copied := *n
+	copied.Attrs = append([]xml.Attr(nil), n.Attrs...)
+
+	clones := make(map[*MJMLNode]*MJMLNode, len(n.Children))
+	if n.Children != nil {
+		copied.Children = make([]*MJMLNode, len(n.Children))
+		for i, child := range n.Children {
+			copied.Children[i] = child.Clone()
+			clones[child] = copied.Children[i]
+		}
+	}
+	if n.MixedContent != nil {
+		copied.MixedContent = make([]MixedContentPart, len(n.MixedContent))
+		for i, part := range n.MixedContent {
+			if part.Node != nil {
+				if clone, ok := clones[part.Node]; ok {
+					part.Node = clone
+				} else {
+					part.Node = part.Node.Clone()
+				}
+			}
+			copied.MixedContent[i] = part
+		}
+	}
+	return &copied
+}
+
 // GetTagName returns the local name of the XML tag
 func (n *MJMLNode) GetTagName() string {
 	return n.XMLName.Local
@@ -782,6 +980,14 @@ func (n *MJMLNode) GetTextContent() string {
 // GetMixedContent returns the full mixed content including HTML child elements
 // This reconstructs the original content like "Share <b>test</b> hi" from the AST
 func (n *MJMLNode) GetMixedContent() string {
+	return n.GetEscapedMixedContent(nil, nil)
+}
+
+// GetEscapedMixedContent is GetMixedContent with the text passed through
+// escapeText and the attribute values of child elements through escapeAttr,
+// so that entities decoded while parsing can be written back as HTML. A nil
+// function leaves its values as decoded.
+func (n *MJMLNode) GetEscapedMixedContent(escapeText, escapeAttr func(string) string) string {
 	if debug.Enabled() {
 		debug.DebugLogWithData("parser", "mixed-content", "Processing mixed content", map[string]any{
 			"tag_name":       n.XMLName.Local,
@@ -791,8 +997,15 @@ func (n *MJMLNode) GetMixedContent() string {
 		})
 	}
 
+	if escapeText == nil {
+		escapeText = func(s string) string { return s }
+	}
+	if escapeAttr == nil {
+		escapeAttr = func(s string) string { return s }
+	}
+
 	if len(n.MixedContent) == 0 {
-		result := strings.TrimSpace(n.Text)
+		result := escapeText(strings.TrimSpace(n.Text))
 		if debug.Enabled() {
 			debug.DebugLogWithData("parser", "text-only", "Returning plain text content", map[string]any{
 				"content": result,
@@ -811,7 +1024,7 @@ func (n *MJMLNode) GetMixedContent() string {
 				result.WriteString(" ")
 				result.WriteString(attr.Name.Local)
 				result.WriteString("=\"")
-				result.WriteString(attr.Value)
+				result.WriteString(escapeAttr(attr.Value))
 				result.WriteString("\"")
 			}
 			if isVoidHTMLElement(tag) {
@@ -819,7 +1032,7 @@ func (n *MJMLNode) GetMixedContent() string {
 				continue
 			}
 			result.WriteString(">")
-			result.WriteString(part.Node.GetMixedContent())
+			result.WriteString(part.Node.GetEscapedMixedContent(escapeText, escapeAttr))
 			result.WriteString("</")
 			result.WriteString(tag)
 			result.WriteString(">")
@@ -831,7 +1044,7 @@ func (n *MJMLNode) GetMixedContent() string {
 			if i == len(n.MixedContent)-1 {
 				text = strings.TrimRight(text, " \n\r\t")
 			}
-			result.WriteString(text)
+			result.WriteString(escapeText(text))
 		}
 	}
 
@@ -88,6 +88,38 @@ func TestMJMLNode_GetAttribute(t *testing.T) {
 	}
 }
 
+func TestMJMLNode_CloneAndSetAttribute(t *testing.T) {
+	node, err := ParseMJML(`<mjml lang="en"><mj-body><mj-text>Hi <b>there</b></mj-text></mj-body></mjml>`)
+	if err != nil {
+		t.Fatalf("ParseMJML() error = %v", err)
+	}
+
+	clone := node.Clone()
+	clone.SetAttribute("lang", "de")
+	clone.SetAttribute("dir", "ltr")
+	clone.RemoveAttribute("missing")
+
+	if got := node.GetAttribute("lang"); got != "en" {
+		t.Errorf("original lang = %q, want en", got)
+	}
+	if got := clone.GetAttribute("lang") + " " + clone.GetAttribute("dir"); got != "de ltr" {
+		t.Errorf("clone lang and dir = %q, want \"de ltr\"", got)
+	}
+
+	clone.RemoveAttribute("lang")
+	if got := clone.GetAttribute("lang"); got != "" {
+		t.Errorf("removed lang = %q, want empty", got)
+	}
+
+	body, clonedBody := node.Children[0], clone.Children[0]
+	if clonedBody.Children[0] == body.Children[0] {
+		t.Fatal("Clone() shared a child node")
+	}
+	if len(clonedBody.MixedContent) != 1 || clonedBody.MixedContent[0].Node != clonedBody.Children[0] {
+		t.Error("MixedContent does not point to the cloned child")
+	}
+}
+
 func TestMJMLNode_FindFirstChild(t *testing.T) {
 	input := `<mjml><mj-head><mj-title>Test</mj-title></mj-head><mj-body><mj-text>Hello</mj-text></mj-body></mjml>`
 	node, err := ParseMJML(input)
@@ -605,6 +637,11 @@ func TestPreprocessHTMLEntitiesIntegration(t *testing.T) {
   </mj-body>
 </mjml>`,
 		},
+		{
+			name:     "Escaped markup inside CDATA",
+			input:    `<mj-button>&copy; &gt;</mj-button><mj-text><![CDATA[&lt;b&gt; &nbsp;&copy;]]></mj-text>`,
+			expected: "<mj-button>© ></mj-button><mj-text><![CDATA[&lt;b&gt; \u00A0©]]></mj-text>",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,135 @@
+package parser
+
+import (
+	"strings"
+)
+
+const (
+	rawOpenNeedle  = "<mj-raw"
+	rawCloseNeedle = "</mj-raw>"
+)
+
+// rawSegment locates the inner content of an mj-raw element in the source.
+type rawSegment struct {
+	start, end int
+}
+
+// extractRawContent finds the inner content of every mj-raw element in
+// content and returns content with that inner content blanked out, together
+// with the location of each segment in document order. Only the newlines of
+// the blanked content are kept, so line numbers are unchanged. Comments, CDATA
+// sections and mj-text content are skipped, since mj-raw tags there are not
+// elements.
+func extractRawContent(content string) (string, []rawSegment) {
+	b := []byte(content)
+	var segments []rawSegment
+	var out strings.Builder
+
+	written := 0
+	for i := 0; i < len(b); i++ {
+		if b[i] != '<' {
+			continue
+		}
+		rest := content[i:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			i = skipPast(content, i, "-->")
+		case strings.HasPrefix(rest, cdataStart):
+			i = skipPast(content, i, cdataEnd)
+		case hasTagPrefix(b, i, openNeedle):
+			end, selfClosing := findTagEnd(b, i)
+			if end < 0 {
+				return content, nil
+			}
+			i = end - 1
+			if !selfClosing {
+				if closeIdx := findMJTextClose(b, end); closeIdx >= 0 {
+					i = closeIdx + len(closeNeedle) - 1
+				}
+			}
+		case hasTagPrefix(b, i, rawOpenNeedle):
+			end, selfClosing := findTagEnd(b, i)
+			if end < 0 {
+				return content, nil
+			}
+			i = end - 1
+			if selfClosing {
+				segments = append(segments, rawSegment{start: end, end: end})
+				continue
+			}
+			closeIdx := indexCI(b, []byte(rawCloseNeedle), end)
+			if closeIdx < 0 {
+				return content, nil
+			}
+
+			if out.Len() == 0 {
+				out.Grow(len(content))
+			}
+			out.WriteString(content[written:end])
+			out.WriteString(strings.Repeat("\n", strings.Count(content[end:closeIdx], "\n")))
+			written = closeIdx
+			segments = append(segments, rawSegment{start: end, end: closeIdx})
+			i = closeIdx + len(rawCloseNeedle) - 1
+		}
+	}
+
+	if written == 0 {
+		return content, segments
+	}
+	out.WriteString(content[written:])
+	return out.String(), segments
+}
+
+// restoreRawContent replaces the content of the mj-raw nodes below root with
+// the source bytes of the matching segments. The nodes are matched in document
+// order; if the counts differ the tree is left unchanged.
+func restoreRawContent(root *MJMLNode, source string, segments []rawSegment) {
+	var raws []*MJMLNode
+	var collect func(node *MJMLNode)
+	collect = func(node *MJMLNode) {
+		if node.XMLName.Local == "mj-raw" {
+			raws = append(raws, node)
+			return
+		}
+		for _, child := range node.Children {
+			collect(child)
+		}
+	}
+	collect(root)
+
+	if len(raws) != len(segments) {
+		return
+	}
+	for i, node := range raws {
+		seg := segments[i]
+		node.Text = source[seg.start:seg.end]
+		node.MixedContent = []MixedContentPart{{Text: node.Text}}
+		node.Verbatim = true
+		node.RawStart = seg.start
+		node.RawEnd = seg.end
+	}
+}
+
+// hasTagPrefix reports whether b holds the start tag name at i, followed by
+// whitespace, '>' or '/'.
+func hasTagPrefix(b []byte, i int, tag string) bool {
+	n := i + len(tag)
+	if n >= len(b) || !equalFoldASCII(b[i:n], []byte(tag)) {
+		return false
+	}
+	switch b[n] {
+	case ' ', '\t', '\n', '\r', '>', '/':
+		return true
+	}
+	return false
+}
+
+// skipPast returns the index of the last byte of the first needle after i, or
+// the end of content when there is none.
+func skipPast(content string, i int, needle string) int {
+	idx := strings.Index(content[i+1:], needle)
+	if idx < 0 {
+		return len(content)
+	}
+	return i + 1 + idx + len(needle) - 1
+}
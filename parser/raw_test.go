@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParseMJMLRawPassthrough(t *testing.T) {
+	raw := `
+  {{#if user.premium}}<p class="x">Hi &amp; welcome&nbsp;{{ user.name }}</p>{{/if}}
+  <script>if (a < b && c) { go(); }</script>
+  %%[ SET @x = "y" ]%%
+  `
+	mjml := "<mjml>\n<mj-head><mj-raw>" + raw + "</mj-raw></mj-head>\n<mj-body>\n" +
+		"<!-- <mj-raw>commented</mj-raw> -->\n" +
+		"<mj-text>&copy; <mj-raw>not an element</mj-raw></mj-text>\n" +
+		"<mj-raw />\n" +
+		"<mj-raw>" + raw + "</mj-raw>\n" +
+		"<mj-section line-marker=\"x\"></mj-section>\n" +
+		"</mj-body>\n</mjml>"
+
+	node, err := ParseMJMLWithOptions(mjml, ParseOptions{RawPassthrough: true})
+	if err != nil {
+		t.Fatalf("ParseMJMLWithOptions failed: %v", err)
+	}
+
+	headRaw := node.FindFirstChild("mj-head").FindFirstChild("mj-raw")
+	body := node.FindFirstChild("mj-body")
+	bodyRaws := body.FindAllChildren("mj-raw")
+	if len(bodyRaws) != 2 {
+		t.Fatalf("expected 2 mj-raw elements in the body, got %d", len(bodyRaws))
+	}
+
+	for _, n := range []*MJMLNode{headRaw, bodyRaws[1]} {
+		if !n.Verbatim || n.Text != raw {
+			t.Errorf("mj-raw text = %q (verbatim %v), want %q", n.Text, n.Verbatim, raw)
+		}
+		if got := mjml[n.RawStart:n.RawEnd]; got != raw {
+			t.Errorf("source offsets select %q, want %q", got, raw)
+		}
+	}
+	if bodyRaws[0].Text != "" {
+		t.Errorf("self-closing mj-raw text = %q, want empty", bodyRaws[0].Text)
+	}
+
+	if text := body.FindFirstChild("mj-text").Text; text != "© <mj-raw>not an element</mj-raw>" {
+		t.Errorf("mj-text content = %q", text)
+	}
+	if line := body.FindFirstChild("mj-section").LineNumber; line != 16 {
+		t.Errorf("mj-section line = %d, want 16", line)
+	}
+
+	normal, err := ParseMJML(mjml)
+	if err == nil {
+		if n := normal.FindFirstChild("mj-head").FindFirstChild("mj-raw"); n.Verbatim || n.Text == raw {
+			t.Error("mj-raw content should only be verbatim with RawPassthrough")
+		}
+	}
+}
@@ -0,0 +1,387 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/fs"
+	"math"
+	"slices"
+)
+
+// streamChunkSize is how much source a streaming stage reads at a time.
+const streamChunkSize = 32 * 1024
+
+// ParseMJMLReader parses MJML read from r into an AST
+func ParseMJMLReader(r io.Reader) (*MJMLNode, error) {
+	return ParseMJMLReaderWithOptions(r, ParseOptions{})
+}
+
+// ParseMJMLReaderWithOptions parses MJML read from r into an AST using opts.
+// The result is the same as ParseMJMLWithOptions on the whole source, but
+// comment stripping, mj-text wrapping and entity preprocessing happen in one
+// streaming pass, so only the preprocessed document is held in memory rather
+// than several full copies of it. This matters for templates of several
+// megabytes.
+//
+// With RawPassthrough the source is read completely, since mj-raw content is
+// restored from it. Syntax errors are located in the source when r is an
+// io.Seeker; otherwise they are reported as encoding/xml sees them.
+func ParseMJMLReaderWithOptions(r io.Reader, opts ParseOptions) (*MJMLNode, error) {
+	if opts.RawPassthrough {
+		source, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		return ParseMJMLWithOptions(string(source), opts)
+	}
+
+	var start int64 = -1
+	if seeker, ok := r.(io.Seeker); ok {
+		if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			start = offset
+		}
+	}
+
+	contentBytes, err := preprocessStream(r)
+	if err != nil {
+		return nil, err
+	}
+
+	lookup := newLineLookup(contentBytes)
+	decoder := xml.NewDecoder(bytes.NewReader(contentBytes))
+	root, err := parseNode(decoder, xml.StartElement{}, lookup, 0, contentBytes)
+	if err != nil {
+		if start >= 0 {
+			if _, seekErr := r.(io.Seeker).Seek(start, io.SeekStart); seekErr == nil {
+				if source, readErr := io.ReadAll(r); readErr == nil {
+					return nil, newParseError(string(source), err)
+				}
+			}
+		}
+		return nil, newDecodeError(err)
+	}
+	if opts.StripComments || opts.StripRawComments {
+		stripNodeComments(root, opts)
+	}
+	return root, nil
+}
+
+// byteStream is a pull-based byte stream with unbounded lookahead. fill
+// appends the next piece of output to buf and reports false at the end.
+// Slices returned by peek are only valid until the next peek.
+type byteStream struct {
+	buf  []byte
+	pos  int
+	done bool
+	fill func(s *byteStream) bool
+}
+
+// peek returns the next n bytes, or fewer at the end of the stream.
+func (s *byteStream) peek(n int) []byte {
+	for len(s.buf)-s.pos < n && !s.done {
+		if s.pos > 0 && s.pos >= len(s.buf)/2 {
+			s.buf = append(s.buf[:0], s.buf[s.pos:]...)
+			s.pos = 0
+		}
+		if !s.fill(s) {
+			s.done = true
+		}
+	}
+	return s.buf[s.pos:min(s.pos+n, len(s.buf))]
+}
+
+// peekUntil peeks progressively more bytes until found reports a match in
+// them or the stream ends, and returns the bytes peeked last.
+func (s *byteStream) peekUntil(found func(b []byte) bool) []byte {
+	n := streamChunkSize
+	for {
+		b := s.peek(n)
+		if found(b) || len(b) < n {
+			return b
+		}
+		n *= 2
+	}
+}
+
+func (s *byteStream) advance(n int) {
+	s.pos += n
+}
+
+// preprocessStream reads MJML from r and returns it preprocessed like
+// preprocessHTMLEntities(wrapMJTextContent(stripNonMSOComments(source))),
+// with each step run as a streaming stage over the previous one.
+func preprocessStream(r io.Reader) ([]byte, error) {
+	// The output is about as long as the source; reserving that up front
+	// avoids holding two large buffers while the output grows.
+	out := make([]byte, 0, sourceSizeHint(r))
+
+	var readErr error
+	source := &byteStream{fill: func(s *byteStream) bool {
+		if readErr != nil {
+			return false
+		}
+		s.buf = slices.Grow(s.buf, streamChunkSize)
+		n, err := r.Read(s.buf[len(s.buf):cap(s.buf)])
+		s.buf = s.buf[:len(s.buf)+n]
+		if err != nil {
+			if err != io.EOF {
+				readErr = err
+			}
+			return n > 0
+		}
+		return true
+	}}
+
+	stripRootPrefixComments(source)
+	wrapped := &byteStream{fill: (&mjTextWrapStage{in: source}).fill}
+	out = (&entityStage{in: wrapped, out: out}).run()
+	if readErr != nil {
+		return nil, readErr
+	}
+	return out, nil
+}
+
+// sourceSizeHint returns the number of bytes left in r when r can tell,
+// plus room for CDATA markers, or 0.
+func sourceSizeHint(r io.Reader) int {
+	var size int64
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		size = int64(v.Len())
+	case interface{ Stat() (fs.FileInfo, error) }:
+		info, err := v.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return 0
+		}
+		size = info.Size()
+		if seeker, ok := r.(io.Seeker); ok {
+			if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+				size -= offset
+			}
+		}
+	}
+	if size <= 0 || size > math.MaxInt32 {
+		return 0
+	}
+	return int(size + size/16)
+}
+
+// stripRootPrefixComments applies stripNonMSOComments to the source before
+// the first <mjml tag, which is all the step changes.
+func stripRootPrefixComments(s *byteStream) {
+	needle := []byte("<mjml")
+	prefix := s.peekUntil(func(b []byte) bool { return indexCI(b, needle, 0) >= 0 })
+	idx := indexCI(prefix, needle, 0)
+	if idx < 0 {
+		return
+	}
+	cleaned := stripNonMSOComments(string(prefix[:idx+len(needle)]))
+	rest := s.buf[s.pos+idx+len(needle):]
+	s.buf = append([]byte(cleaned), rest...)
+	s.pos = 0
+}
+
+// mjTextWrapStage is the streaming form of wrapMJTextContent. It buffers one
+// mj-text element at a time.
+type mjTextWrapStage struct {
+	in  *byteStream
+	raw bool // an unterminated mj-text was found; the rest is copied as is
+}
+
+func (w *mjTextWrapStage) fill(out *byteStream) bool {
+	data := w.in.peek(streamChunkSize)
+	if len(data) == 0 {
+		return false
+	}
+	if w.raw {
+		out.buf = append(out.buf, data...)
+		w.in.advance(len(data))
+		return true
+	}
+
+	if i := bytes.IndexByte(data, '<'); i != 0 {
+		if i < 0 {
+			i = len(data)
+		}
+		out.buf = append(out.buf, data[:i]...)
+		w.in.advance(i)
+		return true
+	}
+	if !equalFoldASCII(w.in.peek(len(openNeedle)), []byte(openNeedle)) {
+		out.buf = append(out.buf, '<')
+		w.in.advance(1)
+		return true
+	}
+
+	tag := w.in.peekUntil(func(b []byte) bool {
+		end, _ := findTagEnd(b, 0)
+		return end >= 0
+	})
+	endStart, selfClosing := findTagEnd(tag, 0)
+	if endStart < 0 {
+		w.raw = true
+		return true
+	}
+	if selfClosing {
+		out.buf = append(out.buf, tag[:endStart]...)
+		w.in.advance(endStart)
+		return true
+	}
+
+	element := w.in.peekUntil(func(b []byte) bool { return findMJTextClose(b, endStart) >= 0 })
+	closeIdx := findMJTextClose(element, endStart)
+	if closeIdx < 0 {
+		w.raw = true
+		return true
+	}
+	out.buf = append(out.buf, element[:endStart]...)
+
+	inner := element[endStart:closeIdx]
+	alreadyCDATA := isSingleCDATASection(inner)
+	inner = normalizeSelfClosingVoidTags(inner)
+	if alreadyCDATA {
+		out.buf = append(out.buf, inner...)
+	} else {
+		if bytes.Contains(inner, []byte(cdataEnd)) {
+			inner = bytes.ReplaceAll(inner, []byte(cdataEnd), []byte(cdataEndSafe))
+		}
+		out.buf = append(out.buf, cdataStart...)
+		out.buf = append(out.buf, inner...)
+		out.buf = append(out.buf, cdataEnd...)
+	}
+	out.buf = append(out.buf, closeNeedle...)
+	w.in.advance(closeIdx + len(closeNeedle))
+	return true
+}
+
+// maxEntityReplacementLen is the length of the longest entity replaced
+// outside CDATA sections.
+const maxEntityReplacementLen = len("&hellip;")
+
+// entityStage is the streaming form of preprocessHTMLEntities: the ampersand
+// escaping of escapeAttributeAmpersands and the entity replacements are done
+// together, restarting at every CDATA section boundary like the segments of
+// the string version. CDATA sections are buffered whole.
+type entityStage struct {
+	in    *byteStream
+	out   []byte
+	inTag bool
+	quote byte
+}
+
+func (e *entityStage) run() []byte {
+	for {
+		data := e.in.peek(streamChunkSize)
+		if len(data) == 0 {
+			return e.out
+		}
+		switch data[0] {
+		case '<':
+			if bytes.Equal(e.in.peek(len(cdataStart)), []byte(cdataStart)) {
+				e.cdata()
+				continue
+			}
+		case '&':
+			e.ampersand()
+			continue
+		}
+		e.advanceRun(e.in.peek(streamChunkSize))
+	}
+}
+
+// advanceRun copies bytes up to the next '&' or '<' after the first one,
+// tracking whether they are inside a tag or a quoted attribute value.
+func (e *entityStage) advanceRun(data []byte) {
+	i := 0
+	for ; i < len(data); i++ {
+		c := data[i]
+		if i > 0 && (c == '&' || c == '<') {
+			break
+		}
+		if e.quote != 0 {
+			if c == e.quote {
+				e.quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '<':
+			e.inTag = true
+		case '>':
+			e.inTag = false
+		case '\'', '"':
+			if e.inTag {
+				e.quote = c
+			}
+		}
+	}
+	e.out = append(e.out, data[:i]...)
+	e.in.advance(i)
+}
+
+// ampersand handles an '&': inside attribute values it is escaped unless it
+// starts a valid entity, and known entities are replaced.
+func (e *entityStage) ampersand() {
+	if e.quote == 0 {
+		if !e.replaceEntity(e.in.peek(maxEntityReplacementLen)) {
+			e.out = append(e.out, '&')
+			e.in.advance(1)
+		}
+		return
+	}
+
+	entityEnd := func(b []byte) int {
+		j := 1
+		for j < len(b) && b[j] != e.quote && !isEntityTerminator(b[j]) {
+			j++
+		}
+		return j
+	}
+	b := e.in.peekUntil(func(b []byte) bool { return entityEnd(b) < len(b) })
+	if j := entityEnd(b); j < len(b) && b[j] == ';' && isValidEntity(string(b[1:j])) {
+		if !e.replaceEntity(b[:j+1]) {
+			e.out = append(e.out, b[:j+1]...)
+			e.in.advance(j + 1)
+		}
+		return
+	}
+	e.out = append(e.out, "&amp;"...)
+	e.in.advance(1)
+}
+
+// replaceEntity writes the replacement of the entity b starts with and
+// reports whether there was one.
+func (e *entityStage) replaceEntity(b []byte) bool {
+	for _, replacements := range [][]string{textEntityReplacements, markupEntityReplacements} {
+		for k := 0; k < len(replacements); k += 2 {
+			if bytes.HasPrefix(b, []byte(replacements[k])) {
+				e.out = append(e.out, replacements[k+1]...)
+				e.in.advance(len(replacements[k]))
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cdata processes a CDATA section like preprocessHTMLEntities. An
+// unterminated section and everything after it is copied as is.
+func (e *entityStage) cdata() {
+	end := func(b []byte) int { return bytes.Index(b[len(cdataStart):], []byte(cdataEnd)) }
+	b := e.in.peekUntil(func(b []byte) bool { return end(b) >= 0 })
+	idx := end(b)
+	if idx < 0 {
+		for data := b; len(data) > 0; data = e.in.peek(streamChunkSize) {
+			e.out = append(e.out, data...)
+			e.in.advance(len(data))
+		}
+		return
+	}
+	inner := string(b[len(cdataStart) : len(cdataStart)+idx])
+	e.out = append(e.out, cdataStart...)
+	e.out = append(e.out, cdataEntityReplacer.Replace(escapeAttributeAmpersands(inner))...)
+	e.out = append(e.out, cdataEnd...)
+	e.in.advance(len(cdataStart) + idx + len(cdataEnd))
+	e.inTag, e.quote = false, 0
+}
@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// streamCases exercise the boundaries between the preprocessing steps.
+var streamCases = []string{
+	`<mjml><mj-body><mj-text>Hi &nbsp;&amp; <b>you</b><br/></mj-text></mj-body></mjml>`,
+	"<!-- leading -->\n  <?xml version=\"1.0\"?><MJML lang=\"en\"><mj-body></mj-body></MJML>",
+	`<!-- <mjml> in a comment --><mjml><mj-body/></mjml>`,
+	`<mjml><mj-body><mj-button href="https://x.test/?a=1&b=2&amp;c=&copy;&#xA0;&bogus;">Go &hellip;</mj-button></mj-body></mjml>`,
+	`<mjml><mj-body><mj-text><![CDATA[<p>&lt;kept&gt; &nbsp;</p>]]></mj-text></mj-body></mjml>`,
+	`<mjml><mj-body><mj-text>a ]]> b<script>"</mj-text>"</script><!-- </mj-text> --></mj-text></mj-body></mjml>`,
+	`<mjml><mj-body><mj-raw><![CDATA[x & "y" &quot;]]></mj-raw><mj-text a='1 > 0 &x'/></mj-body></mjml>`,
+	`<mjml><mj-body><mj-image alt="&quot;q&quot; &lt;tag&gt;" title='it&apos;s'/></mj-body></mjml>`,
+	`<mjml><mj-body><mj-text>unterminated`,
+	`<mjml><mj-body><mj-text title="unterminated`,
+	`<mjml><mj-body><mj-raw><![CDATA[unterminated &nbsp;</mj-raw>`,
+	`no root at all &nbsp; <mj-text>x</mj-text>`,
+}
+
+func preprocessString(source string) string {
+	return preprocessHTMLEntities(wrapMJTextContent(stripNonMSOComments(source)))
+}
+
+func TestPreprocessStreamMatchesStringPipeline(t *testing.T) {
+	sources := append([]string(nil), streamCases...)
+	files, _ := filepath.Glob("../mjml/testdata/*.mjml")
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sources = append(sources, string(b))
+	}
+
+	for i, source := range sources {
+		want := preprocessString(source)
+		// One-byte reads put a chunk boundary between every two bytes.
+		for name, r := range map[string]io.Reader{
+			"chunked":  strings.NewReader(source),
+			"one byte": iotest.OneByteReader(strings.NewReader(source)),
+		} {
+			got, err := preprocessStream(r)
+			if err != nil {
+				t.Fatalf("source %d: %v", i, err)
+			}
+			if string(got) != want {
+				t.Errorf("source %d (%s reads) differs:\n got: %q\nwant: %q", i, name, got, want)
+			}
+		}
+	}
+}
+
+func FuzzPreprocessStream(f *testing.F) {
+	for _, source := range streamCases {
+		f.Add(source)
+	}
+	f.Fuzz(func(t *testing.T, source string) {
+		got, err := preprocessStream(iotest.HalfReader(strings.NewReader(source)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := preprocessString(source); string(got) != want {
+			t.Errorf("differs:\n got: %q\nwant: %q", got, want)
+		}
+	})
+}
+
+func TestParseMJMLReader(t *testing.T) {
+	source := streamCases[3]
+	want, err := ParseMJML(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseMJMLReader(strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("ParseMJMLReader() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("ParseMJMLReader() and ParseMJML() returned different trees")
+	}
+}
+
+func TestParseMJMLReaderErrors(t *testing.T) {
+	source := "<mjml>\n<mj-body>\n<mj-section>\n</mj-body>\n</mjml>"
+	_, want := ParseMJML(source)
+
+	_, err := ParseMJMLReader(strings.NewReader(source))
+	if err == nil || err.Error() != want.Error() {
+		t.Errorf("seekable reader error = %v, want %v", err, want)
+	}
+
+	_, err = ParseMJMLReader(iotest.OneByteReader(strings.NewReader(source)))
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Errorf("stream error = %v, want a *ParseError", err)
+	}
+
+	readErr := errors.New("disk on fire")
+	if _, err := ParseMJMLReader(iotest.ErrReader(readErr)); !errors.Is(err, readErr) {
+		t.Errorf("read error = %v, want %v", err, readErr)
+	}
+}
@@ -0,0 +1,87 @@
+package preview
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EmailOnAcidBaseURL is the endpoint of the Email on Acid API.
+const EmailOnAcidBaseURL = "https://api.emailonacid.com/v5"
+
+// EmailOnAcid previews emails with the Email on Acid API. Preview waits for
+// the test to finish, checking its status every PollInterval.
+type EmailOnAcid struct {
+	APIKey       string        // API key
+	Password     string        // Account password
+	BaseURL      string        // API endpoint; empty uses EmailOnAcidBaseURL
+	HTTPClient   *http.Client  // Client for API requests; nil uses http.DefaultClient
+	PollInterval time.Duration // Delay between status checks; 0 uses DefaultPollInterval
+}
+
+// Name returns "emailonacid".
+func (e *EmailOnAcid) Name() string {
+	return "emailonacid"
+}
+
+// Preview creates an email test and returns its screenshots once every client
+// has finished. Without clients, the account's default clients are captured.
+// Clients the service could not capture are left out of the result.
+func (e *EmailOnAcid) Preview(ctx context.Context, req Request) (*Result, error) {
+	if e.APIKey == "" || e.Password == "" {
+		return nil, fmt.Errorf("missing Email on Acid API key or password")
+	}
+	api := apiClient{httpClient: e.HTTPClient, username: e.APIKey, password: e.Password}
+	base := strings.TrimSuffix(e.BaseURL, "/")
+	if base == "" {
+		base = EmailOnAcidBaseURL
+	}
+
+	body := map[string]any{"subject": req.Subject, "html": req.HTML}
+	if len(req.Clients) > 0 {
+		body["clients"] = req.Clients
+	}
+	var test struct {
+		ID string `json:"id"`
+	}
+	if err := api.do(ctx, http.MethodPost, base+"/email/tests", body, &test); err != nil {
+		return nil, err
+	}
+	testURL := base + "/email/tests/" + url.PathEscape(test.ID)
+
+	err := poll(ctx, e.PollInterval, func() (bool, error) {
+		var status struct {
+			Processing []string `json:"processing"`
+		}
+		if err := api.do(ctx, http.MethodGet, testURL, nil, &status); err != nil {
+			return false, err
+		}
+		return len(status.Processing) == 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results map[string]struct {
+		DisplayName string            `json:"display_name"`
+		Screenshots map[string]string `json:"screenshots"`
+	}
+	if err := api.do(ctx, http.MethodGet, testURL+"/results", nil, &results); err != nil {
+		return nil, err
+	}
+
+	result := &Result{Provider: e.Name(), TestID: test.ID}
+	for client, r := range results {
+		if shot := r.Screenshots["default"]; shot != "" {
+			result.Screenshots = append(result.Screenshots, Screenshot{Client: client, Name: r.DisplayName, URL: shot})
+		}
+	}
+	sort.Slice(result.Screenshots, func(i, j int) bool {
+		return result.Screenshots[i].Client < result.Screenshots[j].Client
+	})
+	return result, nil
+}
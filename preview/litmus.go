@@ -0,0 +1,116 @@
+package preview
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// LitmusBaseURL is the endpoint of the Litmus Instant API.
+const LitmusBaseURL = "https://instant-api.litmus.com/v1"
+
+// Litmus previews emails with the Litmus Instant API. Screenshots are
+// captured when their URL is first requested, so Preview requests each one
+// every PollInterval until the image is served.
+type Litmus struct {
+	APIKey       string        // Instant API key
+	BaseURL      string        // API endpoint; empty uses LitmusBaseURL
+	HTTPClient   *http.Client  // Client for API and image requests; nil uses http.DefaultClient
+	PollInterval time.Duration // Delay between image checks; 0 uses DefaultPollInterval
+}
+
+// Name returns "litmus".
+func (l *Litmus) Name() string {
+	return "litmus"
+}
+
+// Preview uploads the email and returns a screenshot for each client once
+// every image is ready. Without clients, every client available to the API
+// key is captured.
+func (l *Litmus) Preview(ctx context.Context, req Request) (*Result, error) {
+	if l.APIKey == "" {
+		return nil, fmt.Errorf("missing Litmus API key")
+	}
+	api := apiClient{httpClient: l.HTTPClient, username: l.APIKey}
+	base := strings.TrimSuffix(l.BaseURL, "/")
+	if base == "" {
+		base = LitmusBaseURL
+	}
+
+	var email struct {
+		GUID string `json:"email_guid"`
+	}
+	body := map[string]string{"subject": req.Subject, "html_text": req.HTML}
+	if err := api.do(ctx, http.MethodPost, base+"/emails", body, &email); err != nil {
+		return nil, err
+	}
+
+	clients := req.Clients
+	if len(clients) == 0 {
+		if err := api.do(ctx, http.MethodGet, base+"/clients", nil, &clients); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &Result{Provider: l.Name(), TestID: email.GUID}
+	for _, client := range clients {
+		var preview struct {
+			FullURL string `json:"full_url"`
+		}
+		previewURL := base + "/emails/" + url.PathEscape(email.GUID) + "/previews/" + url.PathEscape(client)
+		if err := api.do(ctx, http.MethodGet, previewURL, nil, &preview); err != nil {
+			return nil, err
+		}
+		result.Screenshots = append(result.Screenshots, Screenshot{Client: client, URL: preview.FullURL})
+	}
+
+	pending := result.Screenshots
+	err := poll(ctx, l.PollInterval, func() (bool, error) {
+		waiting := pending[:0:0]
+		for _, shot := range pending {
+			ready, err := l.imageReady(ctx, shot.URL)
+			if err != nil {
+				return false, err
+			}
+			if !ready {
+				waiting = append(waiting, shot)
+			}
+		}
+		pending = waiting
+		return len(pending) == 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// imageReady requests a screenshot URL, which starts its capture, and reports
+// whether the image is served yet. Litmus answers 202 Accepted or 404 Not
+// Found while the capture is in progress.
+func (l *Litmus) imageReady(ctx context.Context, imageURL string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return false, err
+	}
+	httpClient := l.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusAccepted, http.StatusNotFound:
+		return false, nil
+	}
+	return false, fmt.Errorf("GET %s: %s", imageURL, resp.Status)
+}
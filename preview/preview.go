@@ -0,0 +1,138 @@
+// Package preview sends rendered emails to client preview services, such as
+// Litmus and Email on Acid, and returns the screenshots they capture in each
+// email client. It is meant for QA workflows that check a template in real
+// clients before it is sent.
+//
+// Providers implement ClientPreviewProvider, so other services can be
+// plugged in next to the built-in Litmus and EmailOnAcid providers.
+package preview
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/preslavrachev/gomjml/mjml"
+)
+
+// DefaultPollInterval is how often providers check whether screenshots are
+// ready when none is configured.
+const DefaultPollInterval = 5 * time.Second
+
+// Request is an email to capture.
+type Request struct {
+	Subject string   // Subject line shown in the client
+	HTML    string   // Rendered email
+	Clients []string // Provider client codes; empty uses the provider's defaults
+}
+
+// Screenshot is the capture of an email in one client.
+type Screenshot struct {
+	Client string `json:"client"` // Provider client code
+	Name   string `json:"name"`   // Human-readable client name, when the provider reports one
+	URL    string `json:"url"`    // Full-size screenshot image
+}
+
+// Result is a finished preview test.
+type Result struct {
+	Provider    string       `json:"provider"`
+	TestID      string       `json:"testId"` // Provider identifier of the test
+	Screenshots []Screenshot `json:"screenshots"`
+}
+
+// ClientPreviewProvider captures an email in a set of email clients.
+type ClientPreviewProvider interface {
+	// Name returns the provider name, such as "litmus".
+	Name() string
+	// Preview submits the email and waits until its screenshots are ready or
+	// ctx is done.
+	Preview(ctx context.Context, req Request) (*Result, error)
+}
+
+// Render renders an MJML template and previews it with provider. Validation
+// problems reported alongside the HTML do not stop the preview; they are
+// returned with the result.
+func Render(ctx context.Context, provider ClientPreviewProvider, mjmlContent, subject string, clients []string, opts ...mjml.RenderOption) (*Result, error) {
+	html, renderErr := mjml.Render(mjmlContent, opts...)
+	if html == "" && renderErr != nil {
+		return nil, renderErr
+	}
+	result, err := provider.Preview(ctx, Request{Subject: subject, HTML: html, Clients: clients})
+	if err != nil {
+		return nil, fmt.Errorf("%s preview: %w", provider.Name(), err)
+	}
+	return result, renderErr
+}
+
+// apiClient performs the JSON requests shared by the providers.
+type apiClient struct {
+	httpClient *http.Client
+	username   string
+	password   string
+}
+
+// do sends a request with basic authentication and decodes the JSON response
+// into out, which may be nil.
+func (c apiClient) do(ctx context.Context, method, url string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := c.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: %s: %s", method, url, resp.Status, bytes.TrimSpace(message))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// poll calls check every interval until it reports done, fails or ctx is done.
+func poll(ctx context.Context, interval time.Duration, check func() (done bool, err error)) error {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for screenshots: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
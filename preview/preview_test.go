@@ -0,0 +1,120 @@
+package preview
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const document = `<mjml><mj-body><mj-section><mj-column><mj-text>Hello</mj-text></mj-column></mj-section></mj-body></mjml>`
+
+func TestLitmus(t *testing.T) {
+	var (
+		uploaded map[string]string
+		server   *httptest.Server
+	)
+	imageRequests := make(map[string]int)
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/img/") {
+			// Screenshots are captured on the first request
+			imageRequests[r.URL.Path]++
+			if imageRequests[r.URL.Path] < 2 {
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+			_, _ = w.Write([]byte("\x89PNG"))
+			return
+		}
+		if user, _, _ := r.BasicAuth(); user != "key" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/emails":
+			_ = json.NewDecoder(r.Body).Decode(&uploaded)
+			_, _ = w.Write([]byte(`{"email_guid":"abc"}`))
+		case r.URL.Path == "/clients":
+			_, _ = w.Write([]byte(`["OL2019","GMAILNEW"]`))
+		case strings.HasPrefix(r.URL.Path, "/emails/abc/previews/"):
+			client := strings.TrimPrefix(r.URL.Path, "/emails/abc/previews/")
+			_, _ = w.Write([]byte(`{"full_url":"` + server.URL + `/img/` + client + `.png"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := &Litmus{APIKey: "key", BaseURL: server.URL, PollInterval: time.Millisecond}
+	result, err := Render(context.Background(), provider, document, "Hi", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uploaded["subject"] != "Hi" || !strings.Contains(uploaded["html_text"], "Hello") {
+		t.Errorf("unexpected upload: %v", uploaded)
+	}
+	want := []Screenshot{
+		{Client: "OL2019", URL: server.URL + "/img/OL2019.png"},
+		{Client: "GMAILNEW", URL: server.URL + "/img/GMAILNEW.png"},
+	}
+	if result.TestID != "abc" || len(result.Screenshots) != len(want) {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	for i, shot := range want {
+		if result.Screenshots[i] != shot {
+			t.Errorf("screenshot %d = %+v, want %+v", i, result.Screenshots[i], shot)
+		}
+		if n := imageRequests["/img/"+shot.Client+".png"]; n != 2 {
+			t.Errorf("screenshot %s requested %d times, want 2", shot.Client, n)
+		}
+	}
+
+	if _, err := (&Litmus{APIKey: "wrong", BaseURL: server.URL}).Preview(context.Background(), Request{}); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Preview() error = %v, want the API status", err)
+	}
+}
+
+func TestEmailOnAcid(t *testing.T) {
+	var created struct {
+		Clients []string `json:"clients"`
+	}
+	checks := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/email/tests":
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			_, _ = w.Write([]byte(`{"id":"t1"}`))
+		case r.URL.Path == "/email/tests/t1":
+			checks++
+			if checks < 2 {
+				_, _ = w.Write([]byte(`{"completed":["outlook16"],"processing":["iphone13"]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"completed":["outlook16"],"processing":[],"bounced":["iphone13"]}`))
+		case r.URL.Path == "/email/tests/t1/results":
+			_, _ = w.Write([]byte(`{
+				"outlook16": {"display_name": "Outlook 2016", "screenshots": {"default": "https://img.example.com/ol.png"}},
+				"iphone13": {"display_name": "iPhone 13", "screenshots": {}}
+			}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := &EmailOnAcid{APIKey: "key", Password: "secret", BaseURL: server.URL, PollInterval: time.Millisecond}
+	result, err := provider.Preview(context.Background(), Request{Subject: "Hi", HTML: "<p>Hi</p>", Clients: []string{"outlook16", "iphone13"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created.Clients) != 2 || checks != 2 {
+		t.Errorf("clients = %v, status checks = %d", created.Clients, checks)
+	}
+	want := Screenshot{Client: "outlook16", Name: "Outlook 2016", URL: "https://img.example.com/ol.png"}
+	if len(result.Screenshots) != 1 || result.Screenshots[0] != want {
+		t.Errorf("screenshots = %+v, want only %+v", result.Screenshots, want)
+	}
+}